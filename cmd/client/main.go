@@ -4,10 +4,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
 	"github.com/MKhiriev/go-pass-keeper/internal/client"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
@@ -23,7 +27,9 @@ var (
 )
 
 func main() {
-	printBuildInfo()
+	if code, ok := versionCommand(os.Args[1:], os.Stdout); ok {
+		os.Exit(code)
+	}
 
 	log := logger.NewLogger("go-pass-client")
 	cfg, err := config.GetClientConfig()
@@ -31,9 +37,45 @@ func main() {
 		log.Fatal().Err(err).Msg("error getting configs")
 	}
 
-	serverAdapter, err := adapter.NewHTTPServerAdapter(cfg.Adapter, cfg.App, log)
+	// With -config-print stdout carries only the configuration.
+	if cfg.PrintConfig {
+		if err = config.Print(os.Stdout, cfg); err != nil {
+			log.Fatal().Err(err).Msg("error printing configs")
+		}
+		return
+	}
+
+	// The add command follows the global flags, e.g. "client -config c.json
+	// add -type login ...".
+	args := flag.Args()
+	adding := len(args) > 0 && args[0] == client.AddCommand
+
+	// In -json mode stdout carries only the JSON list, and in add mode only
+	// the confirmation of the new entry.
+	logOutput := os.Stdout
+	if cfg.App.ListJSON || adding {
+		logOutput = os.Stderr
+	} else {
+		printBuildInfo(os.Stdout)
+	}
+
+	log, err = logger.New("go-pass-client", logger.Options{
+		Level:  cfg.App.LogLevel,
+		Format: cfg.App.LogFormat,
+		Output: logOutput,
+	})
 	if err != nil {
-		log.Fatal().Err(err).Msg("create local adapter")
+		logger.NewLogger("go-pass-client").Fatal().Err(err).Msg("error configuring logger")
+	}
+
+	clk := clock.Real{}
+	serverAdapter := adapter.NewOfflineServerAdapter()
+	if !cfg.App.Offline {
+		serverAdapter, err = adapter.NewHTTPServerAdapter(cfg.Adapter, cfg.App, log)
+		if err != nil {
+			log.Fatal().Err(err).Msg("create local adapter")
+		}
+		serverAdapter = adapter.NewStatesCacheAdapter(serverAdapter, cfg.App.StatesCacheTTL, clk)
 	}
 
 	localStorage, err := store.NewClientStorages(cfg.Storage, log)
@@ -41,21 +83,58 @@ func main() {
 		log.Fatal().Err(err).Msg("create local storage")
 	}
 
-	services, err := service.NewClientServices(localStorage, serverAdapter, log)
+	// Fatal exits without running deferred calls, so the local store is
+	// closed explicitly until the App owns it.
+	fatal := func(err error, msg string) {
+		_ = localStorage.Close()
+		log.Fatal().Err(err).Msg(msg)
+	}
+
+	services, err := service.NewClientServices(localStorage, serverAdapter, cfg.App, clk, log)
 	if err != nil {
-		log.Fatal().Err(err).Msg("create client services")
+		fatal(err, "create client services")
+	}
+
+	if adding {
+		adder, err := client.NewAdder(services, cfg, args[1:], os.Stdin, os.Stdout)
+		if err != nil {
+			fatal(err, "init add command error")
+		}
+		err = adder.Run()
+		if closeErr := localStorage.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("add entry error")
+		}
+		return
+	}
+
+	if cfg.App.ListJSON {
+		lister, err := client.NewLister(services, cfg, os.Stdin, os.Stdout)
+		if err != nil {
+			fatal(err, "init json lister error")
+		}
+		err = lister.Run()
+		if closeErr := localStorage.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("json list error")
+		}
+		return
 	}
 
-	ui, err := tui.New(services, log)
+	ui, err := tui.New(services, cfg.App, log)
 	if err != nil {
-		log.Fatal().Err(err).Msg("error creating ui")
+		fatal(err, "error creating ui")
 	}
 
 	buildInfo := models.NewAppBuildInfo(buildVersion, buildDate, buildCommit)
 
-	app, err := client.NewApp(services, ui, cfg.Workers, buildInfo, log)
+	app, err := client.NewApp(services, localStorage, ui, cfg, buildInfo, log)
 	if err != nil {
-		log.Fatal().Err(err).Msg("init client app error")
+		fatal(err, "init client app error")
 	}
 
 	if err = app.Run(); err != nil {
@@ -63,7 +142,23 @@ func main() {
 	}
 }
 
-func printBuildInfo() {
+// versionCommand handles the "version" subcommand: it prints the build
+// information to out without reading any configuration. A lone -version or
+// --version is treated the same; with a value it stays the flag that sets
+// the app version (see config.App.Version). handled is false for any other
+// arguments.
+func versionCommand(args []string, out io.Writer) (exitCode int, handled bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+	if args[0] == "version" || len(args) == 1 && (args[0] == "-version" || args[0] == "--version") {
+		printBuildInfo(out)
+		return 0, true
+	}
+	return 0, false
+}
+
+func printBuildInfo(out io.Writer) {
 	if buildVersion == "" {
 		buildVersion = "N/A"
 	}
@@ -74,7 +169,7 @@ func printBuildInfo() {
 		buildCommit = "N/A"
 	}
 
-	fmt.Printf("Build version: %s\n", buildVersion)
-	fmt.Printf("Build date: %s\n", buildDate)
-	fmt.Printf("Build commit: %s\n", buildCommit)
+	fmt.Fprintf(out, "Build version: %s\n", buildVersion)
+	fmt.Fprintf(out, "Build date: %s\n", buildDate)
+	fmt.Fprintf(out, "Build commit: %s\n", buildCommit)
 }
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionCommand(t *testing.T) {
+	buildVersion, buildDate, buildCommit = "1.4.0", "2026-10-01", "abc1234"
+	t.Cleanup(func() { buildVersion, buildDate, buildCommit = "", "", "" })
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantHandled bool
+	}{
+		{name: "subcommand", args: []string{"version"}, wantHandled: true},
+		{name: "single dash flag", args: []string{"-version"}, wantHandled: true},
+		{name: "double dash flag", args: []string{"--version"}, wantHandled: true},
+		{name: "no arguments", args: nil},
+		{name: "other flags", args: []string{"-config", "config.json"}},
+		{name: "version flag with a value", args: []string{"-version", "1.4.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			code, handled := versionCommand(tt.args, &out)
+
+			assert.Equal(t, tt.wantHandled, handled)
+			assert.Equal(t, 0, code)
+			if !tt.wantHandled {
+				assert.Empty(t, out.String())
+				return
+			}
+			assert.Equal(t, "Build version: 1.4.0\nBuild date: 2026-10-01\nBuild commit: abc1234\n", out.String())
+		})
+	}
+}
@@ -4,14 +4,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/handler"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/server"
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/migrations"
 )
 
 var (
@@ -21,14 +26,42 @@ var (
 )
 
 func main() {
-	printBuildInfo()
+	if code, ok := versionCommand(os.Args[1:], os.Stdout); ok {
+		os.Exit(code)
+	}
 
 	log := logger.NewLogger("go-pass-server")
-	cfg, err := config.GetStructuredConfig()
+	cfg, err := config.GetServerConfig()
 	if err != nil {
 		log.Fatal().Err(err).Msg("error getting configs")
 	}
 
+	// With -config-print stdout carries only the configuration.
+	if cfg.PrintConfig {
+		if err = config.Print(os.Stdout, cfg); err != nil {
+			log.Fatal().Err(err).Msg("error printing configs")
+		}
+		return
+	}
+	printBuildInfo(os.Stdout)
+
+	log, err = logger.New("go-pass-server", logger.Options{
+		Level:  cfg.App.LogLevel,
+		Format: cfg.App.LogFormat,
+	})
+	if err != nil {
+		logger.NewLogger("go-pass-server").Fatal().Err(err).Msg("error configuring logger")
+	}
+
+	if cfg.MigrateDryRun {
+		pending, err := store.PendingMigrations(context.Background(), cfg.Storage, log)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error listing pending migrations")
+		}
+		printPendingMigrations(os.Stdout, pending)
+		return
+	}
+
 	log.Info().Msg("starting a server")
 	log.Debug().Any("config", cfg).Msg("received configs")
 
@@ -37,7 +70,8 @@ func main() {
 		log.Fatal().Err(err).Msg("error creating storages")
 	}
 
-	services, err := service.NewServices(storages, cfg.App, log)
+	clk := clock.Real{}
+	services, err := service.NewServices(storages, cfg.App, clk, log)
 	if err != nil {
 		log.Fatal().Err(err).Msg("error creating services")
 	}
@@ -52,10 +86,44 @@ func main() {
 		log.Fatal().Err(err).Msg("error creating server(s)")
 	}
 
+	evictors := append(services.IdleEvictors(), handlers.IdleEvictors()...)
+	janitor := service.NewJanitor(cfg.Server, clk, log, evictors...)
+	janitor.Start(context.Background())
 	servers.RunServer()
+	janitor.Stop()
+}
+
+// versionCommand handles the "version" subcommand: it prints the build
+// information to out without reading any configuration. A lone -version or
+// --version is treated the same; with a value it stays the flag that sets
+// the app version (see config.App.Version). handled is false for any other
+// arguments.
+func versionCommand(args []string, out io.Writer) (exitCode int, handled bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+	if args[0] == "version" || len(args) == 1 && (args[0] == "-version" || args[0] == "--version") {
+		printBuildInfo(out)
+		return 0, true
+	}
+	return 0, false
+}
+
+// printPendingMigrations prints the migrations a -migrate-dry-run found, one
+// per line in the order they would be applied.
+func printPendingMigrations(out io.Writer, pending []migrations.Migration) {
+	if len(pending) == 0 {
+		fmt.Fprintln(out, "No pending migrations")
+		return
+	}
+
+	fmt.Fprintf(out, "Pending migrations (%d), not applied:\n", len(pending))
+	for _, m := range pending {
+		fmt.Fprintf(out, "  %d %s\n", m.Version, m.Name)
+	}
 }
 
-func printBuildInfo() {
+func printBuildInfo(out io.Writer) {
 	if buildVersion == "" {
 		buildVersion = "N/A"
 	}
@@ -68,7 +136,7 @@ func printBuildInfo() {
 		buildCommit = "N/A"
 	}
 
-	fmt.Printf("Build version: %s\n", buildVersion)
-	fmt.Printf("Build date: %s\n", buildDate)
-	fmt.Printf("Build commit: %s\n", buildCommit)
+	fmt.Fprintf(out, "Build version: %s\n", buildVersion)
+	fmt.Fprintf(out, "Build date: %s\n", buildDate)
+	fmt.Fprintf(out, "Build commit: %s\n", buildCommit)
 }
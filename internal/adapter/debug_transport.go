@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package adapter
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+// debugTransport is an [http.RoundTripper] that logs one line per exchange
+// with the server: method, URL, status, duration and body sizes. It is
+// installed by [NewHTTPServerAdapter] when [config.ClientApp.DebugHTTP] is
+// set.
+//
+// Bodies are never read or logged, only their declared sizes, so encrypted
+// payloads, tokens and auth hashes never reach the log. The Authorization
+// header is reduced to [logger.RedactedValue].
+type debugTransport struct {
+	next   http.RoundTripper
+	logger *logger.Logger
+}
+
+// newDebugTransport wraps next with request logging. A nil next selects
+// [http.DefaultTransport]. Entries go to the logger attached to the request
+// context, or to fallback when the context carries none.
+func newDebugTransport(next http.RoundTripper, fallback *logger.Logger) *debugTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &debugTransport{next: next, logger: fallback}
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	log := logger.FromContext(req.Context())
+	if log.GetLevel() == zerolog.Disabled {
+		log = t.logger
+	}
+
+	event := log.Info().
+		Str("func", "debugTransport.RoundTrip").
+		Str("request_id", req.Header.Get(utils.RequestIDHeader)).
+		Str("method", req.Method).
+		Str("url", req.URL.Redacted()).
+		Int64("request_bytes", req.ContentLength).
+		Dur("duration", time.Since(start))
+	if req.Header.Get("Authorization") != "" {
+		event = event.Str("authorization", logger.RedactedValue)
+	}
+
+	if err != nil {
+		event.Err(err).Msg("http exchange failed")
+		return resp, err
+	}
+
+	event.
+		Int("status", resp.StatusCode).
+		Int64("response_bytes", resp.ContentLength).
+		Msg("http exchange")
+	return resp, nil
+}
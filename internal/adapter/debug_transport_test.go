@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugTransport_RoundTrip(t *testing.T) {
+	const (
+		token   = "Bearer eyJhbGciOiJIUzI1NiJ9.secret-claims.signature"
+		payload = `{"data":"c2VjcmV0LWNpcGhlcnRleHQ="}`
+	)
+
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		url        string
+		auth       string
+		wantStatus bool
+	}{
+		{name: "authorized request", url: srv.URL + "/api/data/", auth: token, wantStatus: true},
+		{name: "anonymous request", url: srv.URL + "/api/data/", wantStatus: true},
+		{name: "transport error", url: "http://127.0.0.1:0/api/data/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			transport := newDebugTransport(nil, &logger.Logger{Logger: zerolog.New(&buf)})
+
+			req, err := http.NewRequest(http.MethodPost, tt.url, strings.NewReader(payload))
+			require.NoError(t, err)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+
+			resp, err := transport.RoundTrip(req)
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+
+			out := buf.String()
+			assert.NotContains(t, out, "secret-claims")
+			assert.NotContains(t, out, "c2VjcmV0LWNpcGhlcnRleHQ=")
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry), out)
+			assert.Equal(t, http.MethodPost, entry["method"])
+			assert.Equal(t, tt.url, entry["url"])
+			assert.EqualValues(t, len(payload), entry["request_bytes"])
+
+			if tt.auth != "" {
+				assert.Equal(t, logger.RedactedValue, entry["authorization"])
+			} else {
+				assert.NotContains(t, entry, "authorization")
+			}
+
+			if tt.wantStatus {
+				require.NoError(t, err)
+				assert.Equal(t, tt.auth, gotAuth, "the header must still reach the server")
+				assert.EqualValues(t, http.StatusCreated, entry["status"])
+				assert.EqualValues(t, len(payload), entry["response_bytes"])
+			} else {
+				require.Error(t, err)
+				assert.NotContains(t, entry, "status")
+				assert.Contains(t, entry, "error")
+			}
+		})
+	}
+}
@@ -3,7 +3,11 @@
 
 package adapter
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
 
 // Sentinel errors produced by adapter implementations when the server returns a
 // non-2xx HTTP status code. Callers should use [errors.Is] to distinguish them,
@@ -31,6 +35,16 @@ var (
 	// client no longer matches the current server version.
 	ErrConflict = errors.New("conflict")
 
+	// ErrGone is returned when the server responds with HTTP 410, indicating
+	// that the client_side_id belongs to a deleted record. New content must be
+	// stored under a freshly generated client_side_id.
+	ErrGone = errors.New("gone")
+
+	// ErrLocked is returned when the server responds with HTTP 423: a
+	// concurrent sync of the same user holds the per-user sync lock. The
+	// request changed nothing and can be retried shortly.
+	ErrLocked = errors.New("locked")
+
 	// ErrBadGateway is returned when the server responds with HTTP 502,
 	// typically indicating an upstream service is unreachable.
 	ErrBadGateway = errors.New("bad gateway")
@@ -38,4 +52,58 @@ var (
 	// ErrInternalServerError is returned when the server responds with
 	// HTTP 500, indicating an unexpected server-side failure.
 	ErrInternalServerError = errors.New("internal server error")
+
+	// ErrOffline is returned by every call of the adapter created with
+	// [NewOfflineServerAdapter]: the client runs in offline mode and never
+	// contacts the server.
+	ErrOffline = errors.New("offline mode: server is not contacted")
+
+	// ErrInsecureTransport is returned by every call of the adapter created
+	// with [NewHTTPServerAdapter] for a plain http:// server address unless
+	// [config.ClientApp.Insecure] is set. Without TLS the auth hash and the
+	// bearer token would travel in cleartext.
+	ErrInsecureTransport = errors.New("insecure connection: server address uses plain http, use https or enable insecure mode")
 )
+
+// ConflictError is the [ErrConflict] returned for an HTTP 409 response. It
+// carries the conflict the server described in the response headers; fields
+// the server did not send stay zero, except Operation, which the adapter
+// fills in from the call that was rejected.
+type ConflictError struct {
+	models.VersionConflict
+
+	// body is the response body, kept for the error message.
+	body string
+}
+
+// Error implements error. The message has the "conflict: <body>" form of
+// the other status errors.
+func (e *ConflictError) Error() string {
+	return ErrConflict.Error() + ": " + e.body
+}
+
+// Unwrap makes [errors.Is] match [ErrConflict].
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// ValidationError is the [ErrBadRequest] returned for an HTTP 400 response
+// with a [models.ValidationFailure] body: the server named the item of the
+// request and the field that failed validation.
+type ValidationError struct {
+	models.ValidationFailure
+
+	// body describes the failure, kept for the error message.
+	body string
+}
+
+// Error implements error. The message has the "bad request: <body>" form of
+// the other status errors.
+func (e *ValidationError) Error() string {
+	return ErrBadRequest.Error() + ": " + e.body
+}
+
+// Unwrap makes [errors.Is] match [ErrBadRequest].
+func (e *ValidationError) Unwrap() error {
+	return ErrBadRequest
+}
@@ -4,27 +4,52 @@
 package adapter
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/MKhiriev/go-pass-keeper/models"
 	"github.com/go-resty/resty/v2"
 )
 
 // mapHTTPError converts a resty HTTP response into an error value. It returns
 // nil for any 2xx status code. For known error codes it wraps the corresponding
 // sentinel (e.g. [ErrConflict] for 409) with the trimmed response body as
-// additional context. For unrecognised non-2xx codes it returns a plain
-// "http <code>: <body>" error.
+// additional context (or the status text when the body is empty). A 409 is
+// returned as a [ConflictError] read from the conflict headers, and a 400 with
+// a [models.ValidationFailure] body as a [ValidationError]. For
+// unrecognised non-2xx codes it returns a plain "http <code>: <body>" error.
+//
+// When the request carried an X-Request-ID header, the ID is appended to the
+// context so that users can quote it when reporting a failure.
 func mapHTTPError(resp *resty.Response) error {
 	if resp.StatusCode() >= http.StatusOK && resp.StatusCode() < http.StatusMultipleChoices {
 		return nil
 	}
 
 	body := strings.TrimSpace(string(resp.Body()))
+	if body == "" {
+		body = http.StatusText(resp.StatusCode())
+	}
+	failure, isValidation := validationFailureFromResponse(resp)
+	if isValidation {
+		body = describeValidationFailure(failure)
+	}
+	if resp.Request != nil {
+		if requestID := resp.Request.Header.Get(utils.RequestIDHeader); requestID != "" {
+			body = fmt.Sprintf("%s (request_id=%s)", body, requestID)
+		}
+	}
 
 	switch resp.StatusCode() {
 	case http.StatusBadRequest:
+		if isValidation {
+			return &ValidationError{ValidationFailure: failure, body: body}
+		}
 		return fmt.Errorf("%w: %s", ErrBadRequest, body)
 	case http.StatusUnauthorized:
 		return fmt.Errorf("%w: %s", ErrUnauthorized, body)
@@ -33,15 +58,74 @@ func mapHTTPError(resp *resty.Response) error {
 	case http.StatusNotFound:
 		return fmt.Errorf("%w: %s", ErrNotFound, body)
 	case http.StatusConflict:
-		return fmt.Errorf("%w: %s", ErrConflict, body)
+		return conflictFromResponse(resp, body)
+	case http.StatusGone:
+		return fmt.Errorf("%w: %s", ErrGone, body)
+	case http.StatusLocked:
+		return fmt.Errorf("%w: %s", ErrLocked, body)
 	case http.StatusBadGateway:
 		return fmt.Errorf("%w: %s", ErrBadGateway, body)
 	case http.StatusInternalServerError:
 		return fmt.Errorf("%w: %s", ErrInternalServerError, body)
 	default:
-		if body == "" {
-			body = http.StatusText(resp.StatusCode())
-		}
 		return fmt.Errorf("http %d: %s", resp.StatusCode(), body)
 	}
 }
+
+// conflictFromResponse builds the [ConflictError] of a 409 response from its
+// conflict headers (see [utils.ConflictOperationHeader]). A missing or
+// malformed server version is left zero.
+func conflictFromResponse(resp *resty.Response, body string) *ConflictError {
+	conflict := &ConflictError{body: body}
+	conflict.Operation = models.ConflictOperation(resp.Header().Get(utils.ConflictOperationHeader))
+	conflict.ClientSideID = resp.Header().Get(utils.ConflictClientSideIDHeader)
+	if version, err := strconv.ParseInt(resp.Header().Get(utils.ServerVersionHeader), 10, 64); err == nil {
+		conflict.ServerVersion = version
+	}
+	return conflict
+}
+
+// validationFailureFromResponse reads the [models.ValidationFailure] body of a
+// 400 response. ok is false for other responses, including 400s with a
+// plain-text body.
+func validationFailureFromResponse(resp *resty.Response) (failure models.ValidationFailure, ok bool) {
+	if resp.StatusCode() != http.StatusBadRequest || !strings.HasPrefix(resp.Header().Get("Content-Type"), "application/json") {
+		return models.ValidationFailure{}, false
+	}
+	if err := json.Unmarshal(resp.Body(), &failure); err != nil || failure.Reason == "" {
+		return models.ValidationFailure{}, false
+	}
+	return failure, true
+}
+
+// describeValidationFailure renders failure as
+// "<message>: index <i>, field <field>: <reason>", leaving out the parts the
+// server did not send.
+func describeValidationFailure(failure models.ValidationFailure) string {
+	var where []string
+	if failure.Index != nil {
+		where = append(where, fmt.Sprintf("index %d", *failure.Index))
+	}
+	if failure.Field != "" {
+		where = append(where, "field "+failure.Field)
+	}
+
+	parts := make([]string, 0, 3)
+	if failure.Message != "" {
+		parts = append(parts, failure.Message)
+	}
+	if len(where) > 0 {
+		parts = append(parts, strings.Join(where, ", "))
+	}
+	return strings.Join(append(parts, failure.Reason), ": ")
+}
+
+// withConflictOperation sets the operation of a [ConflictError] in err to
+// operation when the server did not report it. err is returned unchanged.
+func withConflictOperation(err error, operation models.ConflictOperation) error {
+	var conflict *ConflictError
+	if errors.As(err, &conflict) && conflict.Operation == "" {
+		conflict.Operation = operation
+	}
+	return err
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
@@ -21,6 +22,11 @@ import (
 type httpServerAdapter struct {
 	client *utils.HTTPClient
 
+	// apiPrefix is prepended to every request path. It is the path of the
+	// configured base URL, or [config.DefaultServerBasePath] when the URL
+	// has none, and must match the server's [config.Server.BasePath].
+	apiPrefix string
+
 	hashKey string
 	token   string
 
@@ -31,7 +37,20 @@ type httpServerAdapter struct {
 // It normalises and validates the base URL from adapterCfg.HTTPAddress,
 // configures the underlying HTTP client with the resolved base URL and request
 // timeout, and initialises the shared HMAC hasher pool used for transport
-// integrity hashes.
+// integrity hashes. The path of the base URL, if any, becomes the API route
+// prefix (see [splitAPIPrefix]).
+//
+// Every outbound request carries an X-Request-ID header (see
+// [utils.RequestIDHeader]) so that a failing call can be matched with the
+// corresponding server-side log entries. Failed calls are logged together
+// with that ID. With appCfg.DebugHTTP set, every exchange is logged as well
+// (see [debugTransport]).
+//
+// A plain http:// address (including a bare host:port, which defaults to
+// http) is accepted only with appCfg.Insecure set, which is meant for local
+// development. Otherwise every request fails with [ErrInsecureTransport]
+// before anything is sent, so login and registration report the problem to
+// the user instead of exposing the auth hash and tokens.
 //
 // Returns an error if adapterCfg.HTTPAddress is empty or cannot be parsed as a
 // valid URL.
@@ -41,14 +60,86 @@ func NewHTTPServerAdapter(adapterCfg config.ClientAdapter, appCfg config.ClientA
 	if err != nil {
 		return nil, fmt.Errorf("invalid adapter http address: %w", err)
 	}
+	baseURL, apiPrefix := splitAPIPrefix(baseURL)
+
+	adapter := &httpServerAdapter{
+		client:    client,
+		apiPrefix: apiPrefix,
+		hashKey:   appCfg.HashKey,
+		logger:    logger,
+	}
 
 	client.
 		SetBaseURL(baseURL).
-		SetTimeout(adapterCfg.RequestTimeout)
+		SetTimeout(adapterCfg.RequestTimeout).
+		OnBeforeRequest(setRequestID).
+		OnAfterResponse(adapter.logFailedResponse).
+		OnError(adapter.logRequestError)
+
+	if appCfg.DebugHTTP {
+		client.SetTransport(newDebugTransport(client.GetClient().Transport, logger))
+	}
+
+	if strings.HasPrefix(baseURL, "http://") && !appCfg.Insecure {
+		client.OnBeforeRequest(rejectInsecureTransport)
+	}
 
 	utils.InitHasherPool(appCfg.HashKey)
 
-	return &httpServerAdapter{client: client, hashKey: appCfg.HashKey, logger: logger}, nil
+	return adapter, nil
+}
+
+// setRequestID is a resty request middleware that attaches the
+// X-Request-ID header to every outbound request. The ID is taken from the
+// request context when the caller pinned one via [utils.WithRequestID];
+// otherwise a fresh UUID is generated.
+func setRequestID(_ *resty.Client, req *resty.Request) error {
+	if req.Header.Get(utils.RequestIDHeader) != "" {
+		return nil
+	}
+
+	requestID, ok := utils.GetRequestIDFromContext(req.Context())
+	if !ok {
+		requestID = utils.NewUUIDGenerator().Generate()
+	}
+
+	req.SetHeader(utils.RequestIDHeader, requestID)
+	return nil
+}
+
+// rejectInsecureTransport is a resty request middleware installed for plain
+// http:// base URLs when insecure mode is off. It fails every request with
+// [ErrInsecureTransport].
+func rejectInsecureTransport(_ *resty.Client, _ *resty.Request) error {
+	return ErrInsecureTransport
+}
+
+// logFailedResponse is a resty response middleware that logs every non-2xx
+// response together with its request ID so that the user can report it.
+func (h *httpServerAdapter) logFailedResponse(_ *resty.Client, resp *resty.Response) error {
+	if !resp.IsError() {
+		return nil
+	}
+
+	h.logger.Warn().
+		Str("func", "httpServerAdapter.logFailedResponse").
+		Str("request_id", resp.Request.Header.Get(utils.RequestIDHeader)).
+		Str("method", resp.Request.Method).
+		Str("url", resp.Request.URL).
+		Int("status", resp.StatusCode()).
+		Msg("server returned an error")
+	return nil
+}
+
+// logRequestError is a resty error hook that logs transport-level failures
+// (connection refused, timeouts, ...) together with the request ID.
+func (h *httpServerAdapter) logRequestError(req *resty.Request, err error) {
+	h.logger.Err(err).
+		Str("func", "httpServerAdapter.logRequestError").
+		Str("request_id", req.Header.Get(utils.RequestIDHeader)).
+		Str("method", req.Method).
+		Str("url", req.URL).
+		Msg("request to server failed")
 }
 
 func normalizeBaseURL(raw string) (string, error) {
@@ -72,6 +163,26 @@ func normalizeBaseURL(raw string) (string, error) {
 	return strings.TrimRight(u.String(), "/"), nil
 }
 
+// splitAPIPrefix splits a normalised base URL into its origin and the API path
+// prefix. A URL without a path (e.g. "http://host:8080") yields
+// [config.DefaultServerBasePath], so "http://host/vault" talks to a server
+// started with base path "/vault".
+func splitAPIPrefix(baseURL string) (origin, prefix string) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Path == "" || u.Path == "/" {
+		return baseURL, config.DefaultServerBasePath
+	}
+
+	prefix = strings.TrimRight(u.Path, "/")
+	u.Path, u.RawPath = "", ""
+	return u.String(), prefix
+}
+
+// path returns the request path for the API route route (e.g. "/data/").
+func (h *httpServerAdapter) path(route string) string {
+	return h.apiPrefix + route
+}
+
 // SetToken implements [ServerAdapter]. It stores token (whitespace-trimmed) for
 // use in the Authorization header of all subsequent authenticated requests.
 func (h *httpServerAdapter) SetToken(token string) {
@@ -94,7 +205,7 @@ func (h *httpServerAdapter) Register(ctx context.Context, user models.User) (mod
 		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetBody(user).
-		Post("/api/auth/register")
+		Post(h.path("/auth/register"))
 	if err != nil {
 		return models.User{}, fmt.Errorf("register request: %w", err)
 	}
@@ -124,7 +235,7 @@ func (h *httpServerAdapter) RequestSalt(ctx context.Context, user models.User) (
 		SetHeader("Content-Type", "application/json").
 		SetBody(user).
 		SetResult(&foundUser).
-		Post("/api/auth/params")
+		Post(h.path("/auth/params"))
 
 	if err != nil {
 		return user, fmt.Errorf("request request: %w", err)
@@ -150,7 +261,7 @@ func (h *httpServerAdapter) Login(ctx context.Context, user models.User) (models
 		SetHeader("Content-Type", "application/json").
 		SetBody(user).
 		SetResult(&foundUser).
-		Post("/api/auth/login")
+		Post(h.path("/auth/login"))
 
 	if err != nil {
 		return user, fmt.Errorf("login request: %w", err)
@@ -170,25 +281,39 @@ func (h *httpServerAdapter) Login(ctx context.Context, user models.User) (models
 
 // Upload implements [ServerAdapter]. It computes a transport integrity hash
 // over req.PrivateDataList, sets req.Length, and POSTs the request to
-// POST /api/data/. Requires a valid bearer token to be set. Returns an error
-// if the request or response mapping fails.
-func (h *httpServerAdapter) Upload(ctx context.Context, req models.UploadRequest) error {
+// POST /api/data/. Requires a valid bearer token to be set. Returns the
+// decoded [models.UploadResponse]; an empty body yields an empty response.
+// Returns an error if the request, response mapping, or JSON decoding fails.
+func (h *httpServerAdapter) Upload(ctx context.Context, req models.UploadRequest) (models.UploadResponse, error) {
 	req.Hash = computeTransportHash(req.PrivateDataList)
 	req.Length = len(req.PrivateDataList)
 
+	var uploaded models.UploadResponse
+
 	resp, err := h.authedRequest(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetBody(req).
-		Post("/api/data/")
+		Post(h.path("/data/"))
 	if err != nil {
-		return fmt.Errorf("upload request: %w", err)
+		return uploaded, fmt.Errorf("upload request: %w", err)
+	}
+	if err = mapHTTPError(resp); err != nil {
+		return uploaded, err
 	}
 
-	return mapHTTPError(resp)
+	if len(resp.Body()) == 0 {
+		return uploaded, nil
+	}
+	if err = json.Unmarshal(resp.Body(), &uploaded); err != nil {
+		return uploaded, fmt.Errorf("decode upload response: %w", err)
+	}
+
+	return uploaded, nil
 }
 
 // Download implements [ServerAdapter]. It sets req.Length and POSTs the
-// download criteria to POST /api/data/download. Returns the decoded
+// download criteria to POST /api/data/download. A non-empty req.Types limits
+// the result to items of those data types. Returns the decoded
 // [models.PrivateData] slice. Requires a valid bearer token. Returns an error
 // if the request, response mapping, or JSON decoding fails.
 func (h *httpServerAdapter) Download(ctx context.Context, req models.DownloadRequest) ([]models.PrivateData, error) {
@@ -197,7 +322,7 @@ func (h *httpServerAdapter) Download(ctx context.Context, req models.DownloadReq
 	resp, err := h.authedRequest(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetBody(req).
-		Post("/api/data/download")
+		Post(h.path("/data/download"))
 	if err != nil {
 		return nil, fmt.Errorf("download request: %w", err)
 	}
@@ -215,7 +340,7 @@ func (h *httpServerAdapter) Download(ctx context.Context, req models.DownloadReq
 
 // Update implements [ServerAdapter]. It computes a transport integrity hash
 // over req.PrivateDataUpdates, sets req.Length, and PUTs the request to
-// PUT /api/data/update. Returns [ErrConflict] (wrapped) on HTTP 409.
+// PUT /api/data/update. Returns a [ConflictError] on HTTP 409.
 // Requires a valid bearer token.
 func (h *httpServerAdapter) Update(ctx context.Context, req models.UpdateRequest) error {
 	req.Hash = computeTransportHash(req.PrivateDataUpdates)
@@ -224,51 +349,157 @@ func (h *httpServerAdapter) Update(ctx context.Context, req models.UpdateRequest
 	resp, err := h.authedRequest(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetBody(req).
-		Put("/api/data/update")
+		Put(h.path("/data/update"))
 	if err != nil {
 		return fmt.Errorf("update request: %w", err)
 	}
 
-	return mapHTTPError(resp)
+	return withConflictOperation(mapHTTPError(resp), models.ConflictOnUpdate)
 }
 
 // Delete implements [ServerAdapter]. It sets req.Length and sends a DELETE
-// request to DELETE /api/data/delete. Returns [ErrConflict] (wrapped) on
-// HTTP 409. Requires a valid bearer token.
+// request to DELETE /api/data/delete. Returns a [ConflictError] on HTTP
+// 409. Requires a valid bearer token.
 func (h *httpServerAdapter) Delete(ctx context.Context, req models.DeleteRequest) error {
 	req.Length = len(req.DeleteEntries)
 
 	resp, err := h.authedRequest(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetBody(req).
-		Delete("/api/data/delete")
+		Delete(h.path("/data/delete"))
 	if err != nil {
 		return fmt.Errorf("delete request: %w", err)
 	}
 
-	return mapHTTPError(resp)
+	return withConflictOperation(mapHTTPError(resp), models.ConflictOnDelete)
 }
 
+// Restore implements [ServerAdapter]. It sets req.Length and sends a POST
+// request to POST /api/data/restore. Returns a [ConflictError] on HTTP 409.
+// Requires a valid bearer token.
+func (h *httpServerAdapter) Restore(ctx context.Context, req models.RestoreRequest) error {
+	req.Length = len(req.RestoreEntries)
+
+	resp, err := h.authedRequest(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(req).
+		Post(h.path("/data/restore"))
+	if err != nil {
+		return fmt.Errorf("restore request: %w", err)
+	}
+
+	return withConflictOperation(mapHTTPError(resp), models.ConflictOnRestore)
+}
+
+// statesPageLimit is the page size requested by
+// [httpServerAdapter.GetServerStates]. The server may cap it further.
+const statesPageLimit = 500
+
 // GetServerStates implements [ServerAdapter]. It GETs the sync state endpoint
-// GET /api/sync/ and decodes the response into a slice of
+// GET /api/sync/?after=<cursor>&limit=<n> page by page, following next_after
+// until the server reports the last page, and returns the assembled slice of
 // [models.PrivateDataState]. userID is unused in the HTTP implementation
 // (the server infers the user from the bearer token). Requires a valid bearer
-// token. Returns an error if the request, response mapping, or JSON decoding
-// fails.
+// token. Returns an error if any request, response mapping, or JSON decoding
+// fails, or if the server returns a cursor that does not advance.
 func (h *httpServerAdapter) GetServerStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
-	resp, err := h.authedRequest(ctx).Get("/api/sync/")
+	var states []models.PrivateDataState
+	var after int64
+
+	for {
+		resp, err := h.authedRequest(ctx).
+			SetQueryParam("after", strconv.FormatInt(after, 10)).
+			SetQueryParam("limit", strconv.Itoa(statesPageLimit)).
+			Get(h.path("/sync/"))
+		if err != nil {
+			return nil, fmt.Errorf("get server states request: %w", err)
+		}
+		if err = mapHTTPError(resp); err != nil {
+			return nil, err
+		}
+
+		var sr models.SyncResponse
+		if err = json.Unmarshal(resp.Body(), &sr); err != nil {
+			return nil, fmt.Errorf("decode server sync response: %w", err)
+		}
+		states = append(states, sr.PrivateDataStates...)
+
+		if sr.NextAfter == 0 {
+			return states, nil
+		}
+		if sr.NextAfter <= after {
+			return nil, fmt.Errorf("server states cursor did not advance past %d", after)
+		}
+		after = sr.NextAfter
+	}
+}
+
+// GetSchemaVersion implements [ServerAdapter]. It GETs the public endpoint
+// GET /api/version/schema and decodes the reported schema version and
+// delete mode. Returns an error if the request, response mapping, or JSON
+// decoding fails.
+func (h *httpServerAdapter) GetSchemaVersion(ctx context.Context) (models.SchemaVersionResponse, error) {
+	resp, err := h.client.R().SetContext(ctx).Get(h.path("/version/schema"))
+	if err != nil {
+		return models.SchemaVersionResponse{}, fmt.Errorf("get schema version request: %w", err)
+	}
+	if err = mapHTTPError(resp); err != nil {
+		return models.SchemaVersionResponse{}, err
+	}
+
+	var sv models.SchemaVersionResponse
+	if err = json.Unmarshal(resp.Body(), &sv); err != nil {
+		return models.SchemaVersionResponse{}, fmt.Errorf("decode schema version response: %w", err)
+	}
+	return sv, nil
+}
+
+// GetServerVersion implements [ServerAdapter]. It GETs the public endpoint
+// GET /api/version/ and returns the plain-text version with surrounding
+// whitespace trimmed. Returns an error if the request or response mapping
+// fails.
+func (h *httpServerAdapter) GetServerVersion(ctx context.Context) (string, error) {
+	resp, err := h.client.R().SetContext(ctx).Get(h.path("/version/"))
+	if err != nil {
+		return "", fmt.Errorf("get server version request: %w", err)
+	}
+	if err = mapHTTPError(resp); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(resp.String()), nil
+}
+
+// Ping implements [ServerAdapter]. It GETs the liveness endpoint GET /healthz,
+// which is served outside the API base path and touches no server
+// dependencies. Returns an error if the request or response mapping fails.
+func (h *httpServerAdapter) Ping(ctx context.Context) error {
+	resp, err := h.client.R().SetContext(ctx).Get("/healthz")
+	if err != nil {
+		return fmt.Errorf("ping request: %w", err)
+	}
+	return mapHTTPError(resp)
+}
+
+// GetVersionHistory implements [ServerAdapter]. It GETs
+// GET /api/data/history?client_side_id=<id> and decodes the archived
+// versions. Requires a valid bearer token. Returns an error if the request,
+// response mapping, or JSON decoding fails.
+func (h *httpServerAdapter) GetVersionHistory(ctx context.Context, clientSideID string) ([]models.PrivateDataVersion, error) {
+	resp, err := h.authedRequest(ctx).
+		SetQueryParam("client_side_id", clientSideID).
+		Get(h.path("/data/history"))
 	if err != nil {
-		return nil, fmt.Errorf("get server states request: %w", err)
+		return nil, fmt.Errorf("get version history request: %w", err)
 	}
 	if err = mapHTTPError(resp); err != nil {
 		return nil, err
 	}
 
-	var sr models.SyncResponse
-	if err = json.Unmarshal(resp.Body(), &sr); err != nil {
-		return nil, fmt.Errorf("decode server sync response: %w", err)
+	var versions []models.PrivateDataVersion
+	if err = json.Unmarshal(resp.Body(), &versions); err != nil {
+		return nil, fmt.Errorf("decode version history response: %w", err)
 	}
-	return sr.PrivateDataStates, nil
+	return versions, nil
 }
 
 func (h *httpServerAdapter) authedRequest(ctx context.Context) *resty.Request {
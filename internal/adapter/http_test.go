@@ -6,12 +6,17 @@ package adapter
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
 	"github.com/MKhiriev/go-pass-keeper/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,7 +27,7 @@ func newTestAdapter(t *testing.T, serverURL string) *httpServerAdapter {
 	t.Helper()
 	log := logger.NewClientLogger("test")
 	adapterCfg := config.ClientAdapter{HTTPAddress: serverURL}
-	appCfg := config.ClientApp{HashKey: "testhashkey"}
+	appCfg := config.ClientApp{HashKey: "testhashkey", Insecure: true}
 
 	a, err := NewHTTPServerAdapter(adapterCfg, appCfg, log)
 	require.NoError(t, err)
@@ -177,11 +182,42 @@ func TestUpload_Success(t *testing.T) {
 	a := newTestAdapter(t, srv.URL)
 	a.SetToken("sometoken")
 
-	err := a.Upload(context.Background(), models.UploadRequest{
+	resp, err := a.Upload(context.Background(), models.UploadRequest{
 		UserID:          1,
 		PrivateDataList: []*models.PrivateData{},
 	})
 	require.NoError(t, err)
+	assert.Empty(t, resp.Items)
+}
+
+func TestUpload_DecodesServerFields(t *testing.T) {
+	createdAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	want := models.UploadResponse{
+		Items:  []models.UploadedItem{{ID: 17, ClientSideID: "cid-1", CreatedAt: &createdAt}},
+		Length: 1,
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(want)
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	a.SetToken("sometoken")
+
+	got, err := a.Upload(context.Background(), models.UploadRequest{
+		UserID:          1,
+		PrivateDataList: []*models.PrivateData{{ClientSideID: "cid-1"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, int64(17), got.Items[0].ID)
+	assert.Equal(t, "cid-1", got.Items[0].ClientSideID)
+	require.NotNil(t, got.Items[0].CreatedAt)
+	assert.True(t, createdAt.Equal(*got.Items[0].CreatedAt))
+	assert.Nil(t, got.Items[0].UpdatedAt)
 }
 
 func TestUpload_Forbidden(t *testing.T) {
@@ -192,7 +228,7 @@ func TestUpload_Forbidden(t *testing.T) {
 	defer srv.Close()
 
 	a := newTestAdapter(t, srv.URL)
-	err := a.Upload(context.Background(), models.UploadRequest{UserID: 1})
+	_, err := a.Upload(context.Background(), models.UploadRequest{UserID: 1})
 
 	require.Error(t, err)
 	assert.ErrorIs(t, err, ErrForbidden)
@@ -206,12 +242,90 @@ func TestUpload_Conflict(t *testing.T) {
 	defer srv.Close()
 
 	a := newTestAdapter(t, srv.URL)
-	err := a.Upload(context.Background(), models.UploadRequest{UserID: 1})
+	_, err := a.Upload(context.Background(), models.UploadRequest{UserID: 1})
 
 	require.Error(t, err)
 	assert.ErrorIs(t, err, ErrConflict)
 }
 
+func TestUpload_Gone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+		_, _ = w.Write([]byte("client_side_id belongs to a deleted record"))
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	_, err := a.Upload(context.Background(), models.UploadRequest{UserID: 1})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrGone)
+}
+
+func TestUpload_ValidationError(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantFailure *models.ValidationFailure
+		wantMessage string
+	}{
+		{
+			name:        "structured body",
+			contentType: "application/json",
+			body:        `{"message":"invalid data provided","index":1,"field":"client_side_id","reason":"invalid client side id"}`,
+			wantFailure: &models.ValidationFailure{Message: "invalid data provided", Index: func() *int { i := 1; return &i }(), Field: "client_side_id", Reason: "invalid client side id"},
+			wantMessage: "bad request: invalid data provided: index 1, field client_side_id: invalid client side id",
+		},
+		{
+			name:        "plain-text body",
+			contentType: "text/plain; charset=utf-8",
+			body:        "invalid data provided\n",
+			wantMessage: "bad request: invalid data provided",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			a := newTestAdapter(t, srv.URL)
+			_, err := a.Upload(context.Background(), models.UploadRequest{UserID: 1})
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrBadRequest)
+			assert.Contains(t, err.Error(), tt.wantMessage)
+
+			var invalid *ValidationError
+			if tt.wantFailure == nil {
+				assert.NotErrorAs(t, err, &invalid)
+				return
+			}
+			require.ErrorAs(t, err, &invalid)
+			assert.Equal(t, *tt.wantFailure, invalid.ValidationFailure)
+		})
+	}
+}
+
+func TestDelete_Locked(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusLocked)
+		_, _ = w.Write([]byte("another sync of this user is in progress, retry later"))
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	err := a.Delete(context.Background(), models.DeleteRequest{UserID: 1})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrLocked)
+}
+
 // ── Download ─────────────────────────────────────────────────────────────────
 
 func TestDownload_Success(t *testing.T) {
@@ -238,6 +352,30 @@ func TestDownload_Success(t *testing.T) {
 	assert.Equal(t, want[0].ClientSideID, got[0].ClientSideID)
 }
 
+func TestDownload_SendsTypesFilter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body models.DownloadRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []models.DataType{models.BankCard, models.Text}, body.Types)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	a.SetToken("sometoken")
+
+	got, err := a.Download(context.Background(), models.DownloadRequest{
+		UserID: 1,
+		Types:  []models.DataType{models.BankCard, models.Text},
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
 func TestDownload_NotFound(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -283,6 +421,77 @@ func TestUpdate_Conflict(t *testing.T) {
 	assert.ErrorIs(t, err, ErrConflict)
 }
 
+func TestConflict_CarriesOperationAndServerVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		call    func(a *httpServerAdapter) error
+		want    models.VersionConflict
+	}{
+		{
+			name: "update with server headers",
+			headers: map[string]string{
+				utils.ConflictOperationHeader:    "update",
+				utils.ConflictClientSideIDHeader: "item-1",
+				utils.ServerVersionHeader:        "7",
+			},
+			call: func(a *httpServerAdapter) error {
+				return a.Update(context.Background(), models.UpdateRequest{UserID: 1})
+			},
+			want: models.VersionConflict{Operation: models.ConflictOnUpdate, ClientSideID: "item-1", ServerVersion: 7},
+		},
+		{
+			name: "delete with server headers",
+			headers: map[string]string{
+				utils.ConflictOperationHeader:    "delete",
+				utils.ConflictClientSideIDHeader: "item-2",
+				utils.ServerVersionHeader:        "3",
+			},
+			call: func(a *httpServerAdapter) error {
+				return a.Delete(context.Background(), models.DeleteRequest{UserID: 1})
+			},
+			want: models.VersionConflict{Operation: models.ConflictOnDelete, ClientSideID: "item-2", ServerVersion: 3},
+		},
+		{
+			name: "delete from older server without headers",
+			call: func(a *httpServerAdapter) error {
+				return a.Delete(context.Background(), models.DeleteRequest{UserID: 1})
+			},
+			want: models.VersionConflict{Operation: models.ConflictOnDelete},
+		},
+		{
+			name:    "malformed server version",
+			headers: map[string]string{utils.ServerVersionHeader: "abc"},
+			call: func(a *httpServerAdapter) error {
+				return a.Update(context.Background(), models.UpdateRequest{UserID: 1})
+			},
+			want: models.VersionConflict{Operation: models.ConflictOnUpdate},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte("version conflict"))
+			}))
+			defer srv.Close()
+
+			err := tt.call(newTestAdapter(t, srv.URL))
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrConflict)
+			assert.Contains(t, err.Error(), "conflict: version conflict")
+			var conflict *ConflictError
+			require.ErrorAs(t, err, &conflict)
+			assert.Equal(t, tt.want, conflict.VersionConflict)
+		})
+	}
+}
+
 func TestUpdate_BadRequest(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -328,6 +537,70 @@ func TestDelete_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, ErrNotFound)
 }
 
+// ── Restore ──────────────────────────────────────────────────────────────────
+
+func TestRestore_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/data/restore", r.URL.Path)
+
+		var req models.RestoreRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, 1, req.Length)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	a.SetToken("sometoken")
+
+	err := a.Restore(context.Background(), models.RestoreRequest{
+		UserID:         1,
+		RestoreEntries: []models.RestoreEntry{{ClientSideID: "abc", Version: 2}},
+	})
+	require.NoError(t, err)
+}
+
+func TestRestore_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{name: "not found", status: http.StatusNotFound, wantErr: ErrNotFound},
+		{name: "conflict", status: http.StatusConflict, wantErr: ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			err := newTestAdapter(t, srv.URL).Restore(context.Background(), models.RestoreRequest{UserID: 1})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRestore_ConflictNamesRestore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(utils.ConflictClientSideIDHeader, "abc")
+		w.Header().Set(utils.ServerVersionHeader, "5")
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer srv.Close()
+
+	err := newTestAdapter(t, srv.URL).Restore(context.Background(), models.RestoreRequest{UserID: 1})
+
+	var conflict *ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, models.ConflictOnRestore, conflict.Operation)
+	assert.Equal(t, int64(5), conflict.ServerVersion)
+}
+
 // ── GetServerStates ──────────────────────────────────────────────────────────
 
 func TestGetServerStates_Success(t *testing.T) {
@@ -357,6 +630,57 @@ func TestGetServerStates_Success(t *testing.T) {
 	assert.Equal(t, want.PrivateDataStates[0].Version, got[0].Version)
 }
 
+func TestGetServerStates_AssemblesPages(t *testing.T) {
+	pages := map[string]models.SyncResponse{
+		"0": {
+			PrivateDataStates: []models.PrivateDataState{{ClientSideID: "a"}, {ClientSideID: "b"}},
+			NextAfter:         7,
+		},
+		"7": {
+			PrivateDataStates: []models.PrivateDataState{{ClientSideID: "c"}, {ClientSideID: "d"}},
+			NextAfter:         12,
+		},
+		"12": {
+			PrivateDataStates: []models.PrivateDataState{{ClientSideID: "e"}},
+		},
+	}
+
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		after := r.URL.Query().Get("after")
+		cursors = append(cursors, after)
+		assert.Equal(t, strconv.Itoa(statesPageLimit), r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(pages[after])
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	got, err := a.GetServerStates(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0", "7", "12"}, cursors)
+	ids := make([]string, 0, len(got))
+	for _, s := range got {
+		ids = append(ids, s.ClientSideID)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, ids)
+}
+
+func TestGetServerStates_StalledCursor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.SyncResponse{NextAfter: 3})
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	_, err := a.GetServerStates(context.Background(), 1)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did not advance")
+}
+
 func TestGetServerStates_Unauthorized(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -371,6 +695,142 @@ func TestGetServerStates_Unauthorized(t *testing.T) {
 	assert.ErrorIs(t, err, ErrUnauthorized)
 }
 
+// ── GetSchemaVersion ─────────────────────────────────────────────────────────
+
+func TestGetSchemaVersion_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/version/schema", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.SchemaVersionResponse{SchemaVersion: 8, HardDelete: true})
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	got, err := a.GetSchemaVersion(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, models.SchemaVersionResponse{SchemaVersion: 8, HardDelete: true}, got)
+}
+
+func TestGetSchemaVersion_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	_, err := a.GetSchemaVersion(context.Background())
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// ── GetServerVersion ─────────────────────────────────────────────────────────
+
+func TestGetServerVersion_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/version/", r.URL.Path)
+		_, _ = w.Write([]byte("1.4.0\n"))
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	got, err := a.GetServerVersion(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", got)
+}
+
+func TestGetServerVersion_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	_, err := a.GetServerVersion(context.Background())
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// ── Ping ────────────────────────────────────────────────────────────────────
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{name: "server up", status: http.StatusOK},
+		{name: "proxy cannot reach server", status: http.StatusBadGateway, wantErr: ErrBadGateway},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: ErrInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/healthz", r.URL.Path)
+				assert.Empty(t, r.Header.Get("Authorization"))
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			a := newTestAdapter(t, srv.URL+"/vault")
+			a.SetToken("sometoken")
+			err := a.Ping(context.Background())
+
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPing_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	addr := srv.URL
+	srv.Close()
+
+	a := newTestAdapter(t, addr)
+	err := a.Ping(context.Background())
+
+	var netErr net.Error
+	assert.ErrorAs(t, err, &netErr)
+}
+
+// ── GetVersionHistory ───────────────────────────────────────────────────────
+
+func TestGetVersionHistory_Success(t *testing.T) {
+	want := []models.PrivateDataVersion{{ClientSideID: "abc-123", Version: 2}}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/data/history", r.URL.Path)
+		assert.Equal(t, "abc-123", r.URL.Query().Get("client_side_id"))
+		assert.Equal(t, "Bearer sometoken", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(want)
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	a.SetToken("sometoken")
+	got, err := a.GetVersionHistory(context.Background(), "abc-123")
+
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, int64(2), got[0].Version)
+}
+
+func TestGetVersionHistory_Unauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	_, err := a.GetVersionHistory(context.Background(), "abc-123")
+
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
 // ── normalizeBaseURL ─────────────────────────────────────────────────────────
 
 func TestNormalizeBaseURL(t *testing.T) {
@@ -399,3 +859,135 @@ func TestNormalizeBaseURL(t *testing.T) {
 		})
 	}
 }
+
+func TestNewHTTPServerAdapter_PlainHTTP(t *testing.T) {
+	var calls int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"user_id":1,"encryption_salt":"salt"}`))
+	})
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+	secure := httptest.NewTLSServer(handler)
+	defer secure.Close()
+
+	tests := []struct {
+		name      string
+		address   string
+		insecure  bool
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "http rejected by default", address: plain.URL, wantErr: ErrInsecureTransport},
+		{name: "bare host:port rejected by default", address: strings.TrimPrefix(plain.URL, "http://"), wantErr: ErrInsecureTransport},
+		{name: "http allowed in insecure mode", address: plain.URL, insecure: true, wantCalls: 1},
+		{name: "https allowed", address: secure.URL, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			appCfg := config.ClientApp{HashKey: "testhashkey", Insecure: tt.insecure}
+			a, err := NewHTTPServerAdapter(config.ClientAdapter{HTTPAddress: tt.address}, appCfg, logger.Nop())
+			require.NoError(t, err)
+			a.(*httpServerAdapter).client.SetTransport(secure.Client().Transport)
+
+			_, err = a.RequestSalt(context.Background(), models.User{Login: "alice"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestSplitAPIPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantOrigin string
+		wantPrefix string
+	}{
+		{"no path", "http://localhost:8080", "http://localhost:8080", "/api"},
+		{"custom prefix", "https://example.com/vault", "https://example.com", "/vault"},
+		{"nested prefix", "https://example.com/proxy/vault", "https://example.com", "/proxy/vault"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin, prefix := splitAPIPrefix(tt.input)
+			assert.Equal(t, tt.wantOrigin, origin)
+			assert.Equal(t, tt.wantPrefix, prefix)
+		})
+	}
+}
+
+func TestBasePath_PrefixFromBaseURL(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"schema_version":3}`))
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL+"/vault/")
+	version, err := a.GetSchemaVersion(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version.SchemaVersion)
+	assert.False(t, version.HardDelete, "an older server reports no delete mode")
+	assert.Equal(t, "/vault/version/schema", gotPath)
+}
+
+// ── Request ID ──────────────────────────────────────────────────────────────
+
+func TestRequestID_GeneratedAndSent(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(utils.RequestIDHeader)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"private_data_states":[]}`))
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	_, err := a.GetServerStates(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, got)
+}
+
+func TestRequestID_TakenFromContext(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(utils.RequestIDHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	ctx := utils.WithRequestID(context.Background(), "pinned-id")
+	err := a.Delete(ctx, models.DeleteRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "pinned-id", got)
+}
+
+func TestRequestID_IncludedInError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	a := newTestAdapter(t, srv.URL)
+	ctx := utils.WithRequestID(context.Background(), "failing-id")
+	err := a.Delete(ctx, models.DeleteRequest{})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInternalServerError)
+	assert.Contains(t, err.Error(), "request_id=failing-id")
+}
@@ -57,9 +57,10 @@ type ServerAdapter interface {
 
 	// Upload sends one or more new vault items to the server in a single
 	// request. A transport integrity hash covering the payload is computed and
-	// attached to the request automatically. Returns an error if the request
-	// or the server response indicates failure.
-	Upload(ctx context.Context, req models.UploadRequest) error
+	// attached to the request automatically. Returns the server-assigned IDs
+	// and timestamps of the stored items, or an error if the request or the
+	// server response indicates failure.
+	Upload(ctx context.Context, req models.UploadRequest) (models.UploadResponse, error)
 
 	// Download retrieves vault items identified by req.ClientSideIDs from the
 	// server. Returns the full [models.PrivateData] slice, including encrypted
@@ -78,9 +79,37 @@ type ServerAdapter interface {
 	// another error if the request fails.
 	Delete(ctx context.Context, req models.DeleteRequest) error
 
+	// Restore asks the server to undo the soft-delete of one or more vault
+	// items. Returns [ErrNotFound] (wrapped) if an item no longer exists on
+	// the server, [ErrConflict] (wrapped) on a version conflict, or another
+	// error if the request fails.
+	Restore(ctx context.Context, req models.RestoreRequest) error
+
 	// GetServerStates fetches lightweight state descriptors
 	// (ClientSideID, Hash, Version, Deleted, UpdatedAt) for all vault items
 	// owned by userID from the server. Used by the sync planner to compare
 	// server and client state without downloading full encrypted payloads.
+	// Paginated responses are fetched page by page and returned as one slice.
 	GetServerStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error)
+
+	// GetSchemaVersion fetches the version of the newest database migration
+	// applied on the server and whether it removes deleted items instead of
+	// keeping tombstones. Returns [ErrNotFound] (wrapped) when the server
+	// predates the schema version endpoint.
+	GetSchemaVersion(ctx context.Context) (models.SchemaVersionResponse, error)
+
+	// GetServerVersion fetches the application version the server reports,
+	// e.g. "1.4.0". Used for diagnostics only; it does not gate sync.
+	GetServerVersion(ctx context.Context) (string, error)
+
+	// Ping checks that the server is reachable with a request cheap enough
+	// to be sent periodically. It returns nil when the server answered and
+	// an error otherwise; it does not need a bearer token.
+	Ping(ctx context.Context) error
+
+	// GetVersionHistory fetches the previous versions of the vault item
+	// identified by clientSideID that the server keeps, newest first. The
+	// payloads are returned encrypted, exactly as they were uploaded. The
+	// result is empty when the server keeps no version history.
+	GetVersionHistory(ctx context.Context, clientSideID string) ([]models.PrivateDataVersion, error)
 }
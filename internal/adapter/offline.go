@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package adapter
+
+import (
+	"context"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// offlineServerAdapter is the [ServerAdapter] used in offline mode. It never
+// opens a connection: every server call fails with [ErrOffline], so nothing
+// can be uploaded, updated or deleted remotely by accident.
+type offlineServerAdapter struct{}
+
+// NewOfflineServerAdapter returns a [ServerAdapter] whose calls all fail with
+// [ErrOffline].
+func NewOfflineServerAdapter() ServerAdapter {
+	return offlineServerAdapter{}
+}
+
+// SetToken implements [ServerAdapter]. It is a no-op.
+func (offlineServerAdapter) SetToken(string) {}
+
+// Token implements [ServerAdapter]. It always returns an empty string.
+func (offlineServerAdapter) Token() string { return "" }
+
+// Register implements [ServerAdapter].
+func (offlineServerAdapter) Register(context.Context, models.User) (models.User, error) {
+	return models.User{}, ErrOffline
+}
+
+// RequestSalt implements [ServerAdapter].
+func (offlineServerAdapter) RequestSalt(context.Context, models.User) (models.User, error) {
+	return models.User{}, ErrOffline
+}
+
+// Login implements [ServerAdapter].
+func (offlineServerAdapter) Login(context.Context, models.User) (models.User, error) {
+	return models.User{}, ErrOffline
+}
+
+// Upload implements [ServerAdapter].
+func (offlineServerAdapter) Upload(context.Context, models.UploadRequest) (models.UploadResponse, error) {
+	return models.UploadResponse{}, ErrOffline
+}
+
+// Download implements [ServerAdapter].
+func (offlineServerAdapter) Download(context.Context, models.DownloadRequest) ([]models.PrivateData, error) {
+	return nil, ErrOffline
+}
+
+// Update implements [ServerAdapter].
+func (offlineServerAdapter) Update(context.Context, models.UpdateRequest) error {
+	return ErrOffline
+}
+
+// Delete implements [ServerAdapter].
+func (offlineServerAdapter) Delete(context.Context, models.DeleteRequest) error {
+	return ErrOffline
+}
+
+// Restore implements [ServerAdapter].
+func (offlineServerAdapter) Restore(context.Context, models.RestoreRequest) error {
+	return ErrOffline
+}
+
+// GetServerStates implements [ServerAdapter].
+func (offlineServerAdapter) GetServerStates(context.Context, int64) ([]models.PrivateDataState, error) {
+	return nil, ErrOffline
+}
+
+// GetSchemaVersion implements [ServerAdapter].
+func (offlineServerAdapter) GetSchemaVersion(context.Context) (models.SchemaVersionResponse, error) {
+	return models.SchemaVersionResponse{}, ErrOffline
+}
+
+// GetServerVersion implements [ServerAdapter].
+func (offlineServerAdapter) GetServerVersion(context.Context) (string, error) {
+	return "", ErrOffline
+}
+
+// Ping implements [ServerAdapter].
+func (offlineServerAdapter) Ping(context.Context) error {
+	return ErrOffline
+}
+
+// GetVersionHistory implements [ServerAdapter].
+func (offlineServerAdapter) GetVersionHistory(context.Context, string) ([]models.PrivateDataVersion, error) {
+	return nil, ErrOffline
+}
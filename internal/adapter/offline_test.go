@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfflineServerAdapter_RefusesEveryCall(t *testing.T) {
+	a := NewOfflineServerAdapter()
+	ctx := context.Background()
+
+	a.SetToken("token")
+	assert.Empty(t, a.Token())
+
+	calls := map[string]func() error{
+		"Register":    func() error { _, err := a.Register(ctx, models.User{}); return err },
+		"RequestSalt": func() error { _, err := a.RequestSalt(ctx, models.User{}); return err },
+		"Login":       func() error { _, err := a.Login(ctx, models.User{}); return err },
+		"Upload":      func() error { _, err := a.Upload(ctx, models.UploadRequest{}); return err },
+		"Download":    func() error { _, err := a.Download(ctx, models.DownloadRequest{}); return err },
+		"Update":      func() error { return a.Update(ctx, models.UpdateRequest{}) },
+		"Delete":      func() error { return a.Delete(ctx, models.DeleteRequest{}) },
+		"GetServerStates": func() error {
+			_, err := a.GetServerStates(ctx, 1)
+			return err
+		},
+		"GetSchemaVersion": func() error {
+			_, err := a.GetSchemaVersion(ctx)
+			return err
+		},
+		"GetServerVersion": func() error {
+			_, err := a.GetServerVersion(ctx)
+			return err
+		},
+		"GetVersionHistory": func() error {
+			_, err := a.GetVersionHistory(ctx, "cid")
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, call(), ErrOffline)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package adapter
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// statesCacheAdapter is a [ServerAdapter] that reuses the result of
+// GetServerStates for a short time, so that back-to-back syncs do not fetch
+// the same states again. Every write sent through it drops the cache.
+type statesCacheAdapter struct {
+	ServerAdapter
+
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu     sync.Mutex
+	states map[int64]cachedStates
+	// generation is bumped by every write; a fetch that overlapped a write
+	// is not cached, as it may predate the write.
+	generation uint64
+}
+
+type cachedStates struct {
+	states    []models.PrivateDataState
+	fetchedAt time.Time
+}
+
+// NewStatesCacheAdapter wraps next so that its GetServerStates results are
+// reused per user for ttl, as measured by clk. Upload, Update and Delete
+// invalidate the cache whether or not they succeed. A non-positive ttl
+// returns next unchanged.
+func NewStatesCacheAdapter(next ServerAdapter, ttl time.Duration, clk clock.Clock) ServerAdapter {
+	if ttl <= 0 {
+		return next
+	}
+	return &statesCacheAdapter{
+		ServerAdapter: next,
+		ttl:           ttl,
+		clock:         clk,
+		states:        make(map[int64]cachedStates),
+	}
+}
+
+// GetServerStates implements [ServerAdapter]. It returns a copy of the
+// cached states of userID while they are younger than the TTL and fetches
+// them from the wrapped adapter otherwise.
+func (a *statesCacheAdapter) GetServerStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
+	a.mu.Lock()
+	cached, ok := a.states[userID]
+	generation := a.generation
+	a.mu.Unlock()
+
+	if ok && a.clock.Now().Sub(cached.fetchedAt) < a.ttl {
+		return slices.Clone(cached.states), nil
+	}
+
+	fetchedAt := a.clock.Now()
+	states, err := a.ServerAdapter.GetServerStates(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	if a.generation == generation {
+		a.states[userID] = cachedStates{states: slices.Clone(states), fetchedAt: fetchedAt}
+	}
+	a.mu.Unlock()
+
+	return states, nil
+}
+
+// Upload implements [ServerAdapter].
+func (a *statesCacheAdapter) Upload(ctx context.Context, req models.UploadRequest) (models.UploadResponse, error) {
+	defer a.invalidate()
+	return a.ServerAdapter.Upload(ctx, req)
+}
+
+// Update implements [ServerAdapter].
+func (a *statesCacheAdapter) Update(ctx context.Context, req models.UpdateRequest) error {
+	defer a.invalidate()
+	return a.ServerAdapter.Update(ctx, req)
+}
+
+// Delete implements [ServerAdapter].
+func (a *statesCacheAdapter) Delete(ctx context.Context, req models.DeleteRequest) error {
+	defer a.invalidate()
+	return a.ServerAdapter.Delete(ctx, req)
+}
+
+// Restore implements [ServerAdapter].
+func (a *statesCacheAdapter) Restore(ctx context.Context, req models.RestoreRequest) error {
+	defer a.invalidate()
+	return a.ServerAdapter.Restore(ctx, req)
+}
+
+// invalidate drops the cached states of every user. It runs after the write
+// returns, so a read that starts later always reaches the server.
+func (a *statesCacheAdapter) invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.generation++
+	clear(a.states)
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statesCountingAdapter counts GetServerStates calls; the embedded nil
+// adapter panics on any call the test does not expect.
+type statesCountingAdapter struct {
+	ServerAdapter
+	calls    map[int64]int
+	statesFn func(userID int64) ([]models.PrivateDataState, error)
+	writeErr error
+}
+
+func (a *statesCountingAdapter) GetServerStates(_ context.Context, userID int64) ([]models.PrivateDataState, error) {
+	a.calls[userID]++
+	if a.statesFn != nil {
+		return a.statesFn(userID)
+	}
+	return []models.PrivateDataState{{ClientSideID: "c1", Version: int64(a.calls[userID])}}, nil
+}
+
+func (a *statesCountingAdapter) Upload(context.Context, models.UploadRequest) (models.UploadResponse, error) {
+	return models.UploadResponse{}, a.writeErr
+}
+
+func (a *statesCountingAdapter) Update(context.Context, models.UpdateRequest) error {
+	return a.writeErr
+}
+
+func (a *statesCountingAdapter) Delete(context.Context, models.DeleteRequest) error {
+	return a.writeErr
+}
+
+func (a *statesCountingAdapter) Restore(context.Context, models.RestoreRequest) error {
+	return a.writeErr
+}
+
+func newTestStatesCache(t *testing.T, next *statesCountingAdapter, clk clock.Clock) *statesCacheAdapter {
+	t.Helper()
+	next.calls = make(map[int64]int)
+	a, ok := NewStatesCacheAdapter(next, 5*time.Second, clk).(*statesCacheAdapter)
+	require.True(t, ok)
+	return a
+}
+
+func TestStatesCache_ReadsWithinWindowHitServerOnce(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC))
+	next := &statesCountingAdapter{}
+	a := newTestStatesCache(t, next, fake)
+
+	first, err := a.GetServerStates(ctx, 1)
+	require.NoError(t, err)
+	fake.Advance(4 * time.Second)
+	second, err := a.GetServerStates(ctx, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, next.calls[1])
+	assert.Equal(t, first, second)
+
+	// The cache is per user.
+	_, err = a.GetServerStates(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, next.calls[2])
+
+	// Callers may modify what they get without touching the cache.
+	second[0].Version = 99
+	third, err := a.GetServerStates(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, first, third)
+
+	// After the window the states are fetched again.
+	fake.Advance(time.Second)
+	fresh, err := a.GetServerStates(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.calls[1])
+	assert.Equal(t, int64(2), fresh[0].Version)
+}
+
+func TestStatesCache_WriteInvalidates(t *testing.T) {
+	tests := []struct {
+		name     string
+		writeErr error
+		write    func(a ServerAdapter) error
+	}{
+		{name: "upload", write: func(a ServerAdapter) error {
+			_, err := a.Upload(context.Background(), models.UploadRequest{})
+			return err
+		}},
+		{name: "update", write: func(a ServerAdapter) error {
+			return a.Update(context.Background(), models.UpdateRequest{})
+		}},
+		{name: "delete", write: func(a ServerAdapter) error {
+			return a.Delete(context.Background(), models.DeleteRequest{})
+		}},
+		{name: "restore", write: func(a ServerAdapter) error {
+			return a.Restore(context.Background(), models.RestoreRequest{})
+		}},
+		{name: "failed update", writeErr: ErrConflict, write: func(a ServerAdapter) error {
+			return a.Update(context.Background(), models.UpdateRequest{})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			next := &statesCountingAdapter{writeErr: tt.writeErr}
+			a := newTestStatesCache(t, next, clock.NewFake(time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)))
+
+			_, err := a.GetServerStates(ctx, 1)
+			require.NoError(t, err)
+
+			assert.ErrorIs(t, tt.write(a), tt.writeErr)
+
+			states, err := a.GetServerStates(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, 2, next.calls[1])
+			assert.Equal(t, int64(2), states[0].Version)
+		})
+	}
+}
+
+func TestStatesCache_FetchOverlappingWriteIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	next := &statesCountingAdapter{}
+	a := newTestStatesCache(t, next, clock.NewFake(time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)))
+
+	// A write completes while the first fetch is on its way back.
+	next.statesFn = func(int64) ([]models.PrivateDataState, error) {
+		next.statesFn = nil
+		require.NoError(t, a.Delete(ctx, models.DeleteRequest{}))
+		return []models.PrivateDataState{{ClientSideID: "c1", Version: 1}}, nil
+	}
+	_, err := a.GetServerStates(ctx, 1)
+	require.NoError(t, err)
+
+	_, err = a.GetServerStates(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.calls[1])
+}
+
+func TestStatesCache_ErrorsAreNotCached(t *testing.T) {
+	ctx := context.Background()
+	next := &statesCountingAdapter{}
+	a := newTestStatesCache(t, next, clock.NewFake(time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)))
+
+	failure := errors.New("server down")
+	next.statesFn = func(int64) ([]models.PrivateDataState, error) { return nil, failure }
+	_, err := a.GetServerStates(ctx, 1)
+	require.ErrorIs(t, err, failure)
+
+	next.statesFn = nil
+	_, err = a.GetServerStates(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.calls[1])
+}
+
+func TestNewStatesCacheAdapter_Disabled(t *testing.T) {
+	next := NewOfflineServerAdapter()
+	assert.Equal(t, next, NewStatesCacheAdapter(next, 0, clock.Real{}))
+}
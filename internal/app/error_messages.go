@@ -14,6 +14,11 @@ const (
 	// decoded or fails basic validation (e.g. missing required fields).
 	MsgInvalidDataProvided = "invalid data provided"
 
+	// MsgWeakKDFParameters is returned when a registration carries an
+	// encryption salt or auth hash shorter than the server accepts, i.e. the
+	// client derived its keys with weaker settings than required.
+	MsgWeakKDFParameters = "weak key derivation parameters"
+
 	// MsgInvalidLoginPassword is returned when the supplied login/password
 	// combination does not match any existing user record.
 	MsgInvalidLoginPassword = "invalid login/password"
@@ -59,6 +64,14 @@ const (
 	// request has a blank (empty string) client-side ID.
 	MsgEmptyClientIDForSync = "empty client ID provided for sync"
 
+	// MsgInvalidPage is returned when the "after" or "limit" parameter of a
+	// paginated request is negative or not a number.
+	MsgInvalidPage = "invalid after or limit"
+
+	// MsgInvalidIncludeDeleted is returned when the "include_deleted"
+	// parameter of the states request is not a boolean.
+	MsgInvalidIncludeDeleted = "invalid include_deleted"
+
 	// MsgAccessDenied is returned when the authenticated user attempts to
 	// access or modify a resource that belongs to a different user.
 	MsgAccessDenied = "access denied"
@@ -87,4 +100,36 @@ const (
 	// the version supplied by the client no longer matches the server's
 	// current version. The client should sync before retrying.
 	MsgVersionConflict = "version conflict, please sync"
+
+	// MsgDataTombstoned is returned when an upload or update targets a
+	// client_side_id whose record was deleted. The client should store new
+	// content under a freshly generated client_side_id.
+	MsgDataTombstoned = "client_side_id belongs to a deleted record, use a new id"
+
+	// MsgDataNotDeleted is returned when a restore targets a vault item that
+	// is not deleted.
+	MsgDataNotDeleted = "data is not deleted, nothing to restore"
+
+	// MsgDataAlreadyExists is returned when an upload reuses the
+	// client_side_id of a live record.
+	MsgDataAlreadyExists = "data already exists"
+
+	// MsgTooManyClientSideIDs is returned when a download or states request
+	// lists more client_side_ids than the server accepts at once. The client
+	// should split the request into smaller batches.
+	MsgTooManyClientSideIDs = "too many client_side_ids in one request, split it into smaller batches"
+
+	// MsgSyncLocked is returned when an update or delete waits too long for
+	// a concurrent sync of the same user. Nothing was changed; the client
+	// should retry shortly.
+	MsgSyncLocked = "another sync of this user is in progress, retry later"
+
+	// MsgServerBusy is returned with 503 when the server already processes
+	// as many requests as its in-flight limit allows.
+	MsgServerBusy = "server is busy, retry later"
+
+	// MsgTooManyLoginAttempts is returned with 429 while a login is locked
+	// after too many failed attempts. It is the same whether or not the
+	// account exists.
+	MsgTooManyLoginAttempts = "too many failed login attempts, retry later"
 )
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+// Package browser opens web addresses in the user's default browser from the
+// terminal client.
+//
+// The browser is started with the platform's launcher: "open" on macOS,
+// "rundll32 url.dll,FileProtocolHandler" on Windows and "xdg-open" elsewhere.
+// Only http and https addresses are opened so that a stored URI cannot make
+// the client launch a local file or an arbitrary application handler.
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnsupportedURI is returned for an empty URI or one whose scheme is not
+// http or https.
+var ErrUnsupportedURI = errors.New("unsupported uri")
+
+// Launcher opens a URI in a browser.
+type Launcher interface {
+	// Open starts the browser with uri, which must already be normalized
+	// with [Normalize]. It does not wait for the browser to exit.
+	Open(uri string) error
+}
+
+// New returns a [Launcher] that starts the default browser of the current
+// operating system.
+func New() Launcher {
+	return systemLauncher{goos: runtime.GOOS, start: startDetached}
+}
+
+// Normalize trims uri and adds the "https://" scheme when it has none, so
+// that "example.com/login" opens as "https://example.com/login". Returns
+// [ErrUnsupportedURI] when the result is not an http or https address with a
+// host.
+func Normalize(uri string) (string, error) {
+	uri = strings.TrimSpace(uri)
+	if uri == "" {
+		return "", ErrUnsupportedURI
+	}
+	if !strings.Contains(uri, "://") {
+		uri = "https://" + uri
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrUnsupportedURI, err)
+	}
+	if scheme := strings.ToLower(u.Scheme); scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("%w: scheme %q", ErrUnsupportedURI, u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%w: no host", ErrUnsupportedURI)
+	}
+	return u.String(), nil
+}
+
+// systemLauncher runs the platform launcher command through start.
+type systemLauncher struct {
+	goos  string
+	start func(name string, args ...string) error
+}
+
+// Open implements [Launcher].
+func (l systemLauncher) Open(uri string) error {
+	name, args := command(l.goos, uri)
+	if err := l.start(name, args...); err != nil {
+		return fmt.Errorf("open browser: %w", err)
+	}
+	return nil
+}
+
+// command returns the launcher command opening uri on goos.
+func command(goos, uri string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{uri}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", uri}
+	default:
+		return "xdg-open", []string{uri}
+	}
+}
+
+// startDetached starts the command without waiting for it; the launcher's
+// exit status is collected in the background.
+func startDetached(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
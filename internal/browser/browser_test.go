@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package browser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{name: "https kept", uri: "https://example.com/login", want: "https://example.com/login"},
+		{name: "http kept", uri: "http://localhost:8080", want: "http://localhost:8080"},
+		{name: "scheme added", uri: "  example.com/login ", want: "https://example.com/login"},
+		{name: "empty", uri: " ", wantErr: true},
+		{name: "file scheme", uri: "file:///etc/passwd", wantErr: true},
+		{name: "app scheme", uri: "androidapp://com.example", wantErr: true},
+		{name: "no host", uri: "https://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.uri)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnsupportedURI)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSystemLauncher_Open(t *testing.T) {
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArgs []string
+	}{
+		{goos: "linux", wantName: "xdg-open", wantArgs: []string{"https://example.com"}},
+		{goos: "freebsd", wantName: "xdg-open", wantArgs: []string{"https://example.com"}},
+		{goos: "darwin", wantName: "open", wantArgs: []string{"https://example.com"}},
+		{goos: "windows", wantName: "rundll32", wantArgs: []string{"url.dll,FileProtocolHandler", "https://example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			var gotName string
+			var gotArgs []string
+			l := systemLauncher{goos: tt.goos, start: func(name string, args ...string) error {
+				gotName, gotArgs = name, args
+				return nil
+			}}
+
+			require.NoError(t, l.Open("https://example.com"))
+			assert.Equal(t, tt.wantName, gotName)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+
+	t.Run("start fails", func(t *testing.T) {
+		startErr := errors.New("executable file not found")
+		l := systemLauncher{goos: "linux", start: func(string, ...string) error { return startErr }}
+		assert.ErrorIs(t, l.Open("https://example.com"), startErr)
+	})
+}
@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// AddCommand is the first argument that selects [Adder] instead of the TUI.
+const AddCommand = "add"
+
+var (
+	// ErrInvalidAddArgs is returned by [NewAdder] when the arguments of the
+	// add command are missing a required flag or name an unknown type.
+	ErrInvalidAddArgs = errors.New("invalid add arguments")
+
+	// ErrMissingSecret is returned by [Adder.Run] when the secret of the
+	// entry cannot be read from the input.
+	ErrMissingSecret = errors.New("secret is required on stdin")
+)
+
+// Adder is the non-interactive client runtime selected with the add command.
+// It creates a single entry from its flags and input, for scripts and
+// integrations that cannot drive the TUI.
+//
+// Secrets are never taken from arguments, where other users could read them
+// from the process list. The input holds, one per line, the login and the
+// master password (unless [EnvLogin] and [EnvMasterPassword] are set), then
+// the secret of the entry: the password of a login, the number and the
+// security code of a card, or, for a text entry, the rest of the input.
+type Adder struct {
+	services *service.ClientServices
+	offline  bool
+	plain    models.DecipheredPayload
+	in       *bufio.Reader
+	out      io.Writer
+}
+
+// NewAdder constructs an [Adder] from the arguments that follow the add
+// command, reading from in and reporting the created entry to out.
+//
+// Flags: -type (login, text or card; one of cfg.App.EnabledDataTypes),
+// -name, -folder (defaults to cfg.App.DefaultFolder), -username and -url for
+// a login, -holder, -exp-month and -exp-year for a card, and -password-stdin,
+// -text-stdin or -card-stdin, one of which must confirm that the secret of
+// the chosen type is piped in. Returns [ErrInvalidAddArgs] when a required
+// flag is missing.
+func NewAdder(services *service.ClientServices, cfg *config.ClientConfig, args []string, in io.Reader, out io.Writer) (*Adder, error) {
+	fs := flag.NewFlagSet(AddCommand, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	typeName := fs.String("type", "", "Entry type: login, text or card")
+	name := fs.String("name", "", "Entry name")
+	folder := fs.String("folder", cfg.App.DefaultFolder, "Entry folder")
+	username := fs.String("username", "", "Login username")
+	url := fs.String("url", "", "Login URL")
+	holder := fs.String("holder", "", "Cardholder name")
+	expMonth := fs.String("exp-month", "", "Card expiration month")
+	expYear := fs.String("exp-year", "", "Card expiration year")
+	passwordStdin := fs.Bool("password-stdin", false, "Read the login password from stdin")
+	textStdin := fs.Bool("text-stdin", false, "Read the text from stdin")
+	cardStdin := fs.Bool("card-stdin", false, "Read the card number and security code from stdin")
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddArgs, err)
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("%w: unexpected argument %q; secrets are read from stdin only", ErrInvalidAddArgs, fs.Arg(0))
+	}
+
+	dataTypes, err := config.ParseDataTypes(*typeName)
+	if err != nil || *typeName == "" || len(dataTypes) != 1 {
+		return nil, fmt.Errorf("%w: -type must be one of login, text or card", ErrInvalidAddArgs)
+	}
+	if len(cfg.App.EnabledDataTypes) > 0 && !slices.Contains(cfg.App.EnabledDataTypes, dataTypes[0]) {
+		return nil, fmt.Errorf("%w: type %q is disabled in the configuration", ErrInvalidAddArgs, *typeName)
+	}
+	if strings.TrimSpace(*name) == "" {
+		return nil, fmt.Errorf("%w: -name is required", ErrInvalidAddArgs)
+	}
+
+	plain := models.DecipheredPayload{
+		Type:     dataTypes[0],
+		Metadata: models.Metadata{Name: *name},
+	}
+	if f := strings.TrimSpace(*folder); f != "" {
+		plain.Metadata.Folder = &f
+	}
+
+	switch plain.Type {
+	case models.LoginPassword:
+		if strings.TrimSpace(*username) == "" {
+			return nil, fmt.Errorf("%w: -username is required for a login", ErrInvalidAddArgs)
+		}
+		if !*passwordStdin {
+			return nil, fmt.Errorf("%w: -password-stdin is required for a login", ErrInvalidAddArgs)
+		}
+		plain.LoginData = &models.LoginData{Username: strings.TrimSpace(*username)}
+		if u := strings.TrimSpace(*url); u != "" {
+			plain.LoginData.URIs = []models.LoginURI{{URI: u}}
+		}
+	case models.Text:
+		if !*textStdin {
+			return nil, fmt.Errorf("%w: -text-stdin is required for a text", ErrInvalidAddArgs)
+		}
+		plain.TextData = &models.TextData{}
+	case models.BankCard:
+		if !*cardStdin {
+			return nil, fmt.Errorf("%w: -card-stdin is required for a card", ErrInvalidAddArgs)
+		}
+		plain.BankCardData = &models.BankCardData{
+			CardholderName: strings.TrimSpace(*holder),
+			ExpMonth:       strings.TrimSpace(*expMonth),
+			ExpYear:        strings.TrimSpace(*expYear),
+		}
+	default:
+		return nil, fmt.Errorf("%w: type %q cannot be added from the command line", ErrInvalidAddArgs, *typeName)
+	}
+
+	return &Adder{
+		services: services,
+		offline:  cfg.App.Offline,
+		plain:    plain,
+		in:       bufio.NewReader(in),
+		out:      out,
+	}, nil
+}
+
+// Run implements [Client].
+//
+// The credentials and the secret are read before logging in, so a missing
+// secret fails without contacting the server. The entry is then created with
+// ClientPrivateDataService.Create, which stores it locally and uploads it.
+// Adding is refused in offline mode, where nothing can be uploaded.
+func (a *Adder) Run() error {
+	if a.offline {
+		return fmt.Errorf("add: %w", service.ErrOfflineMode)
+	}
+	ctx := context.Background()
+
+	user, fromEnv, err := credentials(a.in)
+	if err != nil {
+		return err
+	}
+	plain, err := a.readSecret()
+	if err != nil {
+		return err
+	}
+
+	userID, key, err := a.services.AuthService.Login(ctx, user)
+	user.MasterPassword = ""
+	if fromEnv {
+		clearEnvCredentials()
+	}
+	if err != nil {
+		return err
+	}
+	a.services.PrivateDataService.SetEncryptionKey(key)
+
+	plain.UserID = userID
+	if err = a.services.PrivateDataService.Create(ctx, userID, plain); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(a.out, "added %s %q\n", config.DataTypeName(plain.Type), strings.TrimSpace(plain.Metadata.Name))
+	return err
+}
+
+// readSecret returns a copy of the prepared payload with the secret of its
+// type read from the input.
+func (a *Adder) readSecret() (models.DecipheredPayload, error) {
+	plain := a.plain
+	switch plain.Type {
+	case models.LoginPassword:
+		password, err := readLine(a.in)
+		if err != nil || password == "" {
+			return plain, fmt.Errorf("%w: login password", ErrMissingSecret)
+		}
+		data := *plain.LoginData
+		data.Password = password
+		plain.LoginData = &data
+	case models.Text:
+		text, err := io.ReadAll(a.in)
+		if err != nil || strings.TrimSpace(string(text)) == "" {
+			return plain, fmt.Errorf("%w: text", ErrMissingSecret)
+		}
+		plain.TextData = &models.TextData{Text: strings.TrimRight(string(text), "\r\n")}
+	case models.BankCard:
+		number, err := readLine(a.in)
+		number = strings.ReplaceAll(strings.TrimSpace(number), " ", "")
+		if err != nil || number == "" {
+			return plain, fmt.Errorf("%w: card number", ErrMissingSecret)
+		}
+		code, _ := readLine(a.in)
+		data := *plain.BankCardData
+		data.Number = number
+		data.Code = strings.TrimSpace(code)
+		plain.BankCardData = &data
+	}
+	return plain, nil
+}
+
+// credentials returns the login and master password from the environment
+// when both variables are set, otherwise from the first two lines of in.
+// fromEnv reports which source was used. The password is never written
+// anywhere.
+func credentials(in *bufio.Reader) (user models.User, fromEnv bool, err error) {
+	login := strings.TrimSpace(os.Getenv(EnvLogin))
+	password := os.Getenv(EnvMasterPassword)
+	if login != "" && password != "" {
+		return models.User{Login: login, MasterPassword: password}, true, nil
+	}
+
+	user, err = readCredentials(in)
+	return user, false, err
+}
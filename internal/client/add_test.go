@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package client
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/mock"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestAdder(t *testing.T, app config.ClientApp, args []string, in string) (*Adder, listerMocks, *bytes.Buffer, error) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := listerMocks{
+		auth:    mock.NewMockClientAuthService(ctrl),
+		private: mock.NewMockClientPrivateDataService(ctrl),
+		sync:    mock.NewMockClientSyncService(ctrl),
+	}
+	services := &service.ClientServices{AuthService: m.auth, PrivateDataService: m.private, SyncService: m.sync}
+
+	var out bytes.Buffer
+	a, err := NewAdder(services, &config.ClientConfig{App: app}, args, strings.NewReader(in), &out)
+	return a, m, &out, err
+}
+
+func TestAdder_Run_BuildsPayloadFromStdin(t *testing.T) {
+	work := "work"
+	tests := []struct {
+		name    string
+		app     config.ClientApp
+		args    []string
+		in      string
+		want    models.DecipheredPayload
+		wantOut string
+	}{
+		{
+			name: "login",
+			args: []string{"--type", "login", "--name", " mail ", "--username", "alice", "--url", "https://mail.example", "--password-stdin"},
+			in:   "owner\nmaster pass\nhunter2\n",
+			want: models.DecipheredPayload{
+				UserID:    7,
+				Type:      models.LoginPassword,
+				Metadata:  models.Metadata{Name: " mail "},
+				LoginData: &models.LoginData{Username: "alice", Password: "hunter2", URIs: []models.LoginURI{{URI: "https://mail.example"}}},
+			},
+			wantOut: "added login \"mail\"\n",
+		},
+		{
+			name: "text keeps every line",
+			app:  config.ClientApp{DefaultFolder: work},
+			args: []string{"-type", "text", "-name", "ssh", "-text-stdin"},
+			in:   "owner\nmaster pass\nline one\r\nline two\n",
+			want: models.DecipheredPayload{
+				UserID:   7,
+				Type:     models.Text,
+				Metadata: models.Metadata{Name: "ssh", Folder: &work},
+				TextData: &models.TextData{Text: "line one\r\nline two"},
+			},
+			wantOut: "added text \"ssh\"\n",
+		},
+		{
+			name: "card without trailing newline",
+			args: []string{"-type", "card", "-name", "visa", "-holder", "ALICE", "-exp-month", "04", "-exp-year", "2030", "-card-stdin"},
+			in:   "owner\nmaster pass\n4111 1111 1111 1111\n123",
+			want: models.DecipheredPayload{
+				UserID:       7,
+				Type:         models.BankCard,
+				Metadata:     models.Metadata{Name: "visa"},
+				BankCardData: &models.BankCardData{CardholderName: "ALICE", Number: "4111111111111111", Code: "123", ExpMonth: "04", ExpYear: "2030"},
+			},
+			wantOut: "added card \"visa\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, m, out, err := newTestAdder(t, tt.app, tt.args, tt.in)
+			require.NoError(t, err)
+
+			m.auth.EXPECT().Login(gomock.Any(), models.User{Login: "owner", MasterPassword: "master pass"}).Return(int64(7), []byte("dek"), nil)
+			m.private.EXPECT().SetEncryptionKey([]byte("dek"))
+			m.private.EXPECT().Create(gomock.Any(), int64(7), tt.want).Return(nil)
+
+			require.NoError(t, a.Run())
+			assert.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
+
+func TestAdder_Run_CredentialsFromEnv(t *testing.T) {
+	t.Setenv(EnvLogin, "owner")
+	t.Setenv(EnvMasterPassword, "env-secret")
+
+	a, m, _, err := newTestAdder(t, config.ClientApp{}, []string{"-type", "login", "-name", "mail", "-username", "alice", "-password-stdin"}, "hunter2\n")
+	require.NoError(t, err)
+
+	m.auth.EXPECT().Login(gomock.Any(), models.User{Login: "owner", MasterPassword: "env-secret"}).Return(int64(7), []byte("dek"), nil)
+	m.private.EXPECT().SetEncryptionKey([]byte("dek"))
+	m.private.EXPECT().Create(gomock.Any(), int64(7), gomock.Any()).DoAndReturn(
+		func(_ any, _ int64, plain models.DecipheredPayload) error {
+			assert.Equal(t, "hunter2", plain.LoginData.Password, "the first input line is the secret")
+			return nil
+		},
+	)
+
+	require.NoError(t, a.Run())
+	_, ok := os.LookupEnv(EnvMasterPassword)
+	assert.False(t, ok, "the credential variables are cleared")
+}
+
+func TestAdder_Run_Errors(t *testing.T) {
+	loginArgs := []string{"-type", "login", "-name", "mail", "-username", "alice", "-password-stdin"}
+
+	t.Run("missing secret fails before login", func(t *testing.T) {
+		a, _, _, err := newTestAdder(t, config.ClientApp{}, loginArgs, "owner\nmaster pass\n")
+		require.NoError(t, err)
+		assert.ErrorIs(t, a.Run(), ErrMissingSecret)
+	})
+
+	t.Run("blank text", func(t *testing.T) {
+		a, _, _, err := newTestAdder(t, config.ClientApp{}, []string{"-type", "text", "-name", "t", "-text-stdin"}, "owner\nmaster pass\n \n")
+		require.NoError(t, err)
+		assert.ErrorIs(t, a.Run(), ErrMissingSecret)
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		a, _, _, err := newTestAdder(t, config.ClientApp{}, loginArgs, "owner\n")
+		require.NoError(t, err)
+		assert.ErrorIs(t, a.Run(), ErrMissingCredentials)
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		a, _, _, err := newTestAdder(t, config.ClientApp{Offline: true}, loginArgs, "owner\nmaster pass\nhunter2\n")
+		require.NoError(t, err)
+		assert.ErrorIs(t, a.Run(), service.ErrOfflineMode)
+	})
+}
+
+func TestNewAdder_ValidatesArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		app  config.ClientApp
+		args []string
+	}{
+		{name: "no type", args: []string{"-name", "x"}},
+		{name: "unknown type", args: []string{"-type", "note", "-name", "x"}},
+		{name: "several types", args: []string{"-type", "login,text", "-name", "x"}},
+		{name: "binary", args: []string{"-type", "binary", "-name", "x"}},
+		{name: "disabled type", app: config.ClientApp{EnabledDataTypes: []models.DataType{models.Text}}, args: []string{"-type", "login", "-name", "x", "-username", "a", "-password-stdin"}},
+		{name: "blank name", args: []string{"-type", "text", "-name", " ", "-text-stdin"}},
+		{name: "login without username", args: []string{"-type", "login", "-name", "x", "-password-stdin"}},
+		{name: "login without password-stdin", args: []string{"-type", "login", "-name", "x", "-username", "a"}},
+		{name: "text without text-stdin", args: []string{"-type", "text", "-name", "x"}},
+		{name: "card without card-stdin", args: []string{"-type", "card", "-name", "x"}},
+		{name: "secret as argument", args: []string{"-type", "login", "-name", "x", "-username", "a", "-password-stdin", "hunter2"}},
+		{name: "unknown flag", args: []string{"-type", "login", "-password", "hunter2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := newTestAdder(t, tt.app, tt.args, "")
+			assert.ErrorIs(t, err, ErrInvalidAddArgs)
+		})
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
@@ -21,25 +23,63 @@ import (
 //
 // It coordinates authentication, encryption-key setup, initial synchronization,
 // periodic background sync jobs, and the main terminal UI loop.
+//
+// App owns the root context of the client. Every login session runs under a
+// child of it that is canceled on quit and logout, so in-flight adapter calls
+// abort instead of blocking shutdown.
+//
+// [App.Close] releases everything App owns; Run calls it on return.
 type App struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
 	services    *service.ClientServices
+	storage     io.Closer
 	tui         *tui.TUI
 	syncJobTime time.Duration
+	offline     bool
+	reencrypt   bool
 	buildInfo   models.AppBuildInfo
+	logger      *logger.Logger
+
+	// key is the DEK of the current session, kept only to be wiped.
+	key []byte
+
+	shutdownTimeout time.Duration
+	closeOnce       sync.Once
+	closeErr        error
 }
 
-// NewApp constructs an [App] using the provided services, terminal UI, worker
-// configuration, and build metadata.
+// DefaultShutdownTimeout bounds how long [App.Close] waits for the
+// background sync job to stop.
+const DefaultShutdownTimeout = 5 * time.Second
+
+// ErrShutdownTimeout is returned by [App.Close] when the background sync job
+// does not stop within the shutdown timeout.
+var ErrShutdownTimeout = errors.New("background sync did not stop in time")
+
+// NewApp constructs an [App] using the provided services, terminal UI, client
+// configuration, and build metadata. cfg.Workers sets the background sync
+// interval; with cfg.App.Offline set, no sync is run at all. storage is the
+// local store behind services; the App takes ownership of it and closes it
+// in [App.Close].
 //
-// The logger parameter is accepted for API consistency with other constructors
-// in the project, but is not currently used directly by this type.
-func NewApp(services *service.ClientServices, ui *tui.TUI, cfg config.ClientWorkers, buildInfo models.AppBuildInfo, logger *logger.Logger) (*App, error) {
+// logger receives the client/server version note logged after each online
+// login; nil disables it.
+func NewApp(services *service.ClientServices, storage io.Closer, ui *tui.TUI, cfg *config.ClientConfig, buildInfo models.AppBuildInfo, logger *logger.Logger) (*App, error) {
+	ctx, cancel := context.WithCancel(context.Background())
 
 	return &App{
-		services:    services,
-		tui:         ui,
-		syncJobTime: cfg.SyncInterval,
-		buildInfo:   buildInfo,
+		ctx:             ctx,
+		cancel:          cancel,
+		services:        services,
+		storage:         storage,
+		tui:             ui,
+		syncJobTime:     cfg.Workers.SyncInterval,
+		offline:         cfg.App.Offline,
+		reencrypt:       cfg.App.Reencrypt,
+		buildInfo:       buildInfo,
+		logger:          logger,
+		shutdownTimeout: DefaultShutdownTimeout,
 	}, nil
 }
 
@@ -47,35 +87,152 @@ func NewApp(services *service.ClientServices, ui *tui.TUI, cfg config.ClientWork
 //
 // Flow:
 //  1. Run login flow and obtain authenticated user ID and encryption key.
-//  2. Configure encryption key in private-data service.
-//  3. Perform an initial full sync (non-fatal warning on failure).
+//  2. Configure encryption key in private-data service and log the server
+//     version with a compatibility note.
+//  3. Perform an initial full sync (non-fatal warning on failure) and, with
+//     cfg.App.Reencrypt set, re-encrypt entries stored in an older format.
 //  4. Start periodic background sync job.
 //  5. Run the main TUI loop.
-//  6. On logout request, restart the lifecycle from login.
-func (a *App) Run() error {
-	ctx := context.Background()
+//  6. On logout request, cancel the session and restart from login.
+//
+// In offline mode the version note and steps 3 and 4 are skipped.
+//
+// Run closes the App when it returns; a close error is joined to the
+// returned error.
+func (a *App) Run() (err error) {
+	defer func() {
+		err = errors.Join(err, a.Close())
+	}()
+
+	for {
+		logout, err := a.runSession()
+		if err != nil || !logout {
+			return err
+		}
+	}
+}
+
+// runSession runs a single login-to-quit session under a child of the root
+// context. The child is canceled before the background sync job is stopped so
+// that a sync in progress aborts promptly.
+func (a *App) runSession() (logout bool, err error) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	defer cancel()
 
 	userID, key, err := a.tui.LoginFlow(ctx, a.buildInfo)
 	if err != nil {
 		if errors.Is(err, tui.ErrUserQuit) {
-			return nil
+			return false, nil
 		}
-		return err
+		return false, err
 	}
 
+	a.key = key
 	a.services.PrivateDataService.SetEncryptionKey(key)
+	defer a.wipeKey()
+
+	if a.offline {
+		return a.tui.MainLoop(ctx, userID, a.buildInfo)
+	}
+
+	a.logServerVersion(ctx)
 
 	if err = a.services.SyncService.FullSync(ctx, userID); err != nil {
 		fmt.Fprintf(os.Stderr, "sync warning: %v\n", err)
 	}
+	if a.reencrypt {
+		a.reencryptOutdated(ctx, userID)
+	}
 
 	a.services.SyncJob.Start(ctx, userID, a.syncJobTime)
-	defer a.services.SyncJob.Stop()
+	defer func() {
+		cancel()
+		a.services.SyncJob.Stop()
+	}()
+
+	return a.tui.MainLoop(ctx, userID, a.buildInfo)
+}
 
-	logout, err := a.tui.MainLoop(ctx, userID, a.buildInfo)
-	if logout {
-		return a.Run()
+// reencryptOutdated migrates the entries of userID still stored in an older
+// encryption format. Like the initial sync, a failure is only a warning: the
+// remaining entries are migrated by the next run or when edited.
+func (a *App) reencryptOutdated(ctx context.Context, userID int64) {
+	migrated, err := a.services.PrivateDataService.ReencryptOutdated(ctx, userID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "re-encrypt warning: %v\n", err)
+	}
+	if migrated > 0 && a.logger != nil {
+		a.logger.Info().Int("entries", migrated).Msg("re-encrypted entries in the current format")
+	}
+}
+
+// logServerVersion logs the server's version next to the client's and
+// whether they are compatible, for support triage. A failed request is
+// logged and otherwise ignored.
+func (a *App) logServerVersion(ctx context.Context) {
+	if a.logger == nil {
+		return
 	}
+	serverVersion, err := a.services.SyncService.ServerVersion(ctx)
+	if err != nil {
+		a.logger.Warn().Err(err).Msg("could not check server version")
+		return
+	}
+
+	compatibility := service.CheckVersionCompatibility(a.buildInfo.BuildVersion(), serverVersion)
+	event := a.logger.Info()
+	if compatibility == service.VersionMajorMismatch {
+		event = a.logger.Warn()
+	}
+	event.Str("client_version", a.buildInfo.BuildVersion()).
+		Str("server_version", serverVersion).
+		Str("compatibility", compatibility.String()).
+		Msg("server version")
+}
 
-	return err
+// Close shuts the App down: it cancels the root context so that a sync in
+// flight aborts, waits up to the shutdown timeout for the background sync
+// job to stop, wipes the DEK and closes the local store.
+//
+// Close is idempotent and safe to defer next to [App.Run]; later calls
+// return the result of the first. It returns [ErrShutdownTimeout] joined
+// with any store error when the sync job does not stop in time; the store is
+// closed anyway so that the process can exit.
+func (a *App) Close() error {
+	a.closeOnce.Do(func() {
+		a.cancel()
+
+		var errs []error
+		stopped := make(chan struct{})
+		go func() {
+			a.services.SyncJob.Stop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(a.shutdownTimeout):
+			errs = append(errs, ErrShutdownTimeout)
+		}
+
+		a.wipeKey()
+
+		if a.storage != nil {
+			if err := a.storage.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close local store: %w", err))
+			}
+		}
+		a.closeErr = errors.Join(errs...)
+	})
+	return a.closeErr
+}
+
+// wipeKey overwrites the session DEK in place, which also clears the copy
+// shared with the crypto service, and unsets it.
+func (a *App) wipeKey() {
+	if a.key == nil {
+		return
+	}
+	clear(a.key)
+	a.key = nil
+	a.services.PrivateDataService.SetEncryptionKey(nil)
 }
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/mock"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type countingCloser struct {
+	calls int
+	err   error
+}
+
+func (c *countingCloser) Close() error {
+	c.calls++
+	return c.err
+}
+
+func newTestApp(t *testing.T, storage *countingCloser) (*App, *mock.MockClientSyncJob, *mock.MockClientPrivateDataService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	job := mock.NewMockClientSyncJob(ctrl)
+	private := mock.NewMockClientPrivateDataService(ctrl)
+	services := &service.ClientServices{SyncJob: job, PrivateDataService: private}
+
+	app, err := NewApp(services, storage, nil, &config.ClientConfig{}, models.AppBuildInfo{}, nil)
+	require.NoError(t, err)
+	return app, job, private
+}
+
+func TestApp_Close(t *testing.T) {
+	storage := &countingCloser{}
+	app, job, private := newTestApp(t, storage)
+	app.key = []byte("session-dek")
+	key := app.key
+
+	job.EXPECT().Stop().Times(1)
+	private.EXPECT().SetEncryptionKey(nil).Times(1)
+
+	require.NoError(t, app.Close())
+	require.NoError(t, app.Close(), "second close is a no-op")
+
+	assert.Equal(t, 1, storage.calls)
+	assert.Equal(t, make([]byte, len(key)), key, "DEK is zeroed in place")
+	assert.Error(t, app.ctx.Err(), "root context is canceled")
+}
+
+func TestApp_Close_Errors(t *testing.T) {
+	storeErr := errors.New("disk gone")
+
+	t.Run("store close fails", func(t *testing.T) {
+		app, job, _ := newTestApp(t, &countingCloser{err: storeErr})
+		job.EXPECT().Stop()
+
+		err := app.Close()
+		assert.ErrorIs(t, err, storeErr)
+		assert.ErrorIs(t, app.Close(), storeErr, "later calls return the first result")
+	})
+
+	t.Run("sync job does not stop", func(t *testing.T) {
+		storage := &countingCloser{}
+		app, job, _ := newTestApp(t, storage)
+		app.shutdownTimeout = 10 * time.Millisecond
+
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+		job.EXPECT().Stop().Do(func() { <-release })
+
+		assert.ErrorIs(t, app.Close(), ErrShutdownTimeout)
+		assert.Equal(t, 1, storage.calls, "store is closed even after a timeout")
+	})
+}
+
+func TestApp_LogServerVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverVersion string
+		serverErr     error
+		want          []string
+	}{
+		{
+			name:          "compatible",
+			serverVersion: "1.9.0",
+			want:          []string{`"level":"info"`, `"client_version":"1.4.0"`, `"server_version":"1.9.0"`, `"compatibility":"compatible"`},
+		},
+		{
+			name:          "major mismatch",
+			serverVersion: "2.0.0",
+			want:          []string{`"level":"warn"`, `"server_version":"2.0.0"`, `"compatibility":"major versions differ, update the older side"`},
+		},
+		{
+			name:      "server unreachable",
+			serverErr: errors.New("connection refused"),
+			want:      []string{`"level":"warn"`, "could not check server version", "connection refused"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			syncSvc := mock.NewMockClientSyncService(ctrl)
+			syncSvc.EXPECT().ServerVersion(gomock.Any()).Return(tt.serverVersion, tt.serverErr)
+
+			var buf bytes.Buffer
+			log, err := logger.New("test", logger.Options{Output: &buf})
+			require.NoError(t, err)
+
+			app, err := NewApp(&service.ClientServices{SyncService: syncSvc}, nil, nil, &config.ClientConfig{},
+				models.NewAppBuildInfo("1.4.0", "", ""), log)
+			require.NoError(t, err)
+
+			app.logServerVersion(context.Background())
+			for _, want := range tt.want {
+				assert.Contains(t, buf.String(), want)
+			}
+		})
+	}
+}
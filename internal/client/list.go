@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// ErrMissingCredentials is returned by [Lister.Run] when the login or the
+// master password cannot be read from its input.
+var ErrMissingCredentials = errors.New("login and master password are required")
+
+// Environment variables that supply the credentials of [Lister] instead of
+// its input, for runs where nothing can be piped in (cron, CI secrets).
+// Both must be set; they are removed from the process environment once the
+// vault is unlocked.
+const (
+	EnvLogin          = "APP_LOGIN"
+	EnvMasterPassword = "APP_MASTER_PASSWORD"
+)
+
+// ListEntry is a single vault item in the output of [Lister].
+//
+// Only metadata is filled in by default; the type-specific fields, notes and
+// custom fields are included only when secrets are requested.
+type ListEntry struct {
+	ClientSideID string `json:"client_side_id"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	Folder       string `json:"folder,omitempty"`
+
+	LoginData        *models.LoginData     `json:"login_data,omitempty"`
+	TextData         *models.TextData      `json:"text_data,omitempty"`
+	BinaryData       *models.BinaryData    `json:"binary_data,omitempty"`
+	BankCardData     *models.BankCardData  `json:"bank_card_data,omitempty"`
+	Notes            *models.Notes         `json:"notes,omitempty"`
+	AdditionalFields *[]models.CustomField `json:"fields,omitempty"`
+}
+
+// Lister is the non-interactive client runtime selected with the -json flag.
+//
+// It reads the login and the master password from [EnvLogin] and
+// [EnvMasterPassword] or, when those are not set, from its input, one per
+// line. It then unlocks the vault, syncs it unless the client is offline, prints every
+// decryptable entry as a JSON array and exits. The TUI is never started, so
+// it can be used from scripts and CI.
+type Lister struct {
+	services *service.ClientServices
+	offline  bool
+	secrets  bool
+	in       io.Reader
+	out      io.Writer
+	errOut   io.Writer
+}
+
+// NewLister constructs a [Lister] that reads credentials from in and writes
+// the JSON list to out. Warnings (a failed sync, skipped entries) go to
+// os.Stderr so that out contains only JSON.
+//
+// With cfg.App.Offline set the vault is unlocked from the locally cached
+// credentials and no sync is run; with cfg.App.ListSecrets set the secret
+// fields of every entry are printed as well.
+func NewLister(services *service.ClientServices, cfg *config.ClientConfig, in io.Reader, out io.Writer) (*Lister, error) {
+	return &Lister{
+		services: services,
+		offline:  cfg.App.Offline,
+		secrets:  cfg.App.ListSecrets,
+		in:       in,
+		out:      out,
+		errOut:   os.Stderr,
+	}, nil
+}
+
+// Run implements [Client].
+//
+// Returns [ErrMissingCredentials] when the input does not contain both a
+// login and a master password, or the error of login, listing or writing the
+// output. A failed sync is only reported as a warning; the local vault is
+// listed anyway.
+func (l *Lister) Run() error {
+	ctx := context.Background()
+
+	user, fromEnv, err := credentials(bufio.NewReader(l.in))
+	if err != nil {
+		return err
+	}
+
+	userID, key, err := l.services.AuthService.Login(ctx, user)
+	// The password is not needed once the key is derived; drop every copy
+	// this process holds, successful login or not.
+	user.MasterPassword = ""
+	if fromEnv {
+		clearEnvCredentials()
+	}
+	if err != nil {
+		return err
+	}
+	l.services.PrivateDataService.SetEncryptionKey(key)
+
+	if !l.offline {
+		if err = l.services.SyncService.FullSync(ctx, userID); err != nil {
+			fmt.Fprintf(l.errOut, "sync warning: %v\n", err)
+		}
+	}
+
+	items, failed, err := l.services.PrivateDataService.GetAll(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, id := range failed {
+		fmt.Fprintf(l.errOut, "skipped entry %s: cannot be decrypted\n", id)
+	}
+
+	entries := make([]ListEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, newListEntry(item, l.secrets))
+	}
+
+	enc := json.NewEncoder(l.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// newListEntry converts item to its output form, copying the secret fields
+// only when secrets is true.
+func newListEntry(item models.DecipheredPayload, secrets bool) ListEntry {
+	entry := ListEntry{
+		ClientSideID: item.ClientSideID,
+		Type:         config.DataTypeName(item.Type),
+		Name:         item.Metadata.Name,
+	}
+	if item.Metadata.Folder != nil {
+		entry.Folder = *item.Metadata.Folder
+	}
+	if !secrets {
+		return entry
+	}
+
+	entry.LoginData = item.LoginData
+	entry.TextData = item.TextData
+	entry.BinaryData = item.BinaryData
+	entry.BankCardData = item.BankCardData
+	entry.Notes = item.Notes
+	entry.AdditionalFields = item.AdditionalFields
+	return entry
+}
+
+// clearEnvCredentials removes the credential variables from the process
+// environment so that child processes and later code cannot read them.
+func clearEnvCredentials() {
+	_ = os.Unsetenv(EnvLogin)
+	_ = os.Unsetenv(EnvMasterPassword)
+}
+
+// readCredentials reads the login and the master password from the next two
+// lines of in, leaving the rest of the input unread.
+func readCredentials(in *bufio.Reader) (models.User, error) {
+	var lines []string
+	for len(lines) < 2 {
+		line, err := readLine(in)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return models.User{}, fmt.Errorf("%w: %w", ErrMissingCredentials, err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) < 2 || strings.TrimSpace(lines[0]) == "" || lines[1] == "" {
+		return models.User{}, ErrMissingCredentials
+	}
+
+	return models.User{Login: strings.TrimSpace(lines[0]), MasterPassword: lines[1]}, nil
+}
+
+// readLine reads one line of in without its line ending. A last line without
+// a newline is returned as well; io.EOF is returned only when nothing is
+// left.
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/mock"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func listFixture() []models.DecipheredPayload {
+	folder := "work"
+	totp := "JBSWY3DPEHPK3PXP"
+	return []models.DecipheredPayload{
+		{
+			ClientSideID: "id-login",
+			UserID:       7,
+			Type:         models.LoginPassword,
+			Metadata:     models.Metadata{Name: "mail", Folder: &folder},
+			LoginData:    &models.LoginData{Username: "alice", Password: "hunter2", TOTP: &totp},
+			Notes:        &models.Notes{Notes: "recovery codes"},
+		},
+		{
+			ClientSideID: "id-card",
+			UserID:       7,
+			Type:         models.BankCard,
+			Metadata:     models.Metadata{Name: "visa"},
+			BankCardData: &models.BankCardData{Number: "4111111111111111", Code: "123"},
+		},
+	}
+}
+
+type listerMocks struct {
+	auth    *mock.MockClientAuthService
+	private *mock.MockClientPrivateDataService
+	sync    *mock.MockClientSyncService
+}
+
+func newTestLister(t *testing.T, app config.ClientApp, in string) (*Lister, listerMocks, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := listerMocks{
+		auth:    mock.NewMockClientAuthService(ctrl),
+		private: mock.NewMockClientPrivateDataService(ctrl),
+		sync:    mock.NewMockClientSyncService(ctrl),
+	}
+	services := &service.ClientServices{AuthService: m.auth, PrivateDataService: m.private, SyncService: m.sync}
+
+	var out, errOut bytes.Buffer
+	l, err := NewLister(services, &config.ClientConfig{App: app}, strings.NewReader(in), &out)
+	require.NoError(t, err)
+	l.errOut = &errOut
+	return l, m, &out, &errOut
+}
+
+func TestLister_Run_PrintsMetadataOnly(t *testing.T) {
+	l, m, out, errOut := newTestLister(t, config.ClientApp{}, "alice\nmaster pass\n")
+
+	m.auth.EXPECT().Login(gomock.Any(), models.User{Login: "alice", MasterPassword: "master pass"}).Return(int64(7), []byte("dek"), nil)
+	m.private.EXPECT().SetEncryptionKey([]byte("dek"))
+	m.sync.EXPECT().FullSync(gomock.Any(), int64(7)).Return(nil)
+	m.private.EXPECT().GetAll(gomock.Any(), int64(7)).Return(listFixture(), []string{"id-broken"}, nil)
+
+	require.NoError(t, l.Run())
+
+	var entries []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, map[string]any{"client_side_id": "id-login", "type": "login", "name": "mail", "folder": "work"}, entries[0])
+	assert.Equal(t, map[string]any{"client_side_id": "id-card", "type": "card", "name": "visa"}, entries[1])
+
+	for _, secret := range []string{"alice", "hunter2", "JBSWY3DPEHPK3PXP", "recovery codes", "4111111111111111"} {
+		assert.NotContains(t, out.String(), secret)
+	}
+	assert.Contains(t, errOut.String(), "id-broken")
+}
+
+func TestLister_Run_SecretsOnRequest(t *testing.T) {
+	l, m, out, _ := newTestLister(t, config.ClientApp{ListSecrets: true}, "alice\npw\n")
+
+	m.auth.EXPECT().Login(gomock.Any(), gomock.Any()).Return(int64(7), []byte("dek"), nil)
+	m.private.EXPECT().SetEncryptionKey(gomock.Any())
+	m.sync.EXPECT().FullSync(gomock.Any(), int64(7)).Return(nil)
+	m.private.EXPECT().GetAll(gomock.Any(), int64(7)).Return(listFixture(), nil, nil)
+
+	require.NoError(t, l.Run())
+
+	var entries []ListEntry
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	require.NotNil(t, entries[0].LoginData)
+	assert.Equal(t, "hunter2", entries[0].LoginData.Password)
+	require.NotNil(t, entries[0].Notes)
+	assert.Equal(t, "recovery codes", entries[0].Notes.Notes)
+	require.NotNil(t, entries[1].BankCardData)
+	assert.Equal(t, "123", entries[1].BankCardData.Code)
+}
+
+func TestLister_Run_OfflineSkipsSync(t *testing.T) {
+	l, m, out, _ := newTestLister(t, config.ClientApp{Offline: true}, "alice\npw")
+
+	m.auth.EXPECT().Login(gomock.Any(), gomock.Any()).Return(int64(7), []byte("dek"), nil)
+	m.private.EXPECT().SetEncryptionKey(gomock.Any())
+	m.private.EXPECT().GetAll(gomock.Any(), int64(7)).Return(nil, nil, nil)
+
+	require.NoError(t, l.Run())
+	assert.JSONEq(t, `[]`, out.String())
+}
+
+func TestLister_Run_SyncFailureIsWarning(t *testing.T) {
+	l, m, out, errOut := newTestLister(t, config.ClientApp{}, "alice\npw\n")
+
+	m.auth.EXPECT().Login(gomock.Any(), gomock.Any()).Return(int64(7), []byte("dek"), nil)
+	m.private.EXPECT().SetEncryptionKey(gomock.Any())
+	m.sync.EXPECT().FullSync(gomock.Any(), int64(7)).Return(errors.New("server down"))
+	m.private.EXPECT().GetAll(gomock.Any(), int64(7)).Return(listFixture()[:1], nil, nil)
+
+	require.NoError(t, l.Run())
+	assert.Contains(t, errOut.String(), "server down")
+	assert.Contains(t, out.String(), "id-login")
+}
+
+func TestLister_Run_CredentialsFromEnv(t *testing.T) {
+	t.Setenv(EnvLogin, "alice")
+	t.Setenv(EnvMasterPassword, "env-secret")
+
+	// Stdin is ignored when both variables are set.
+	l, m, out, _ := newTestLister(t, config.ClientApp{Offline: true}, "bob\nstdin-secret\n")
+
+	m.auth.EXPECT().Login(gomock.Any(), models.User{Login: "alice", MasterPassword: "env-secret"}).
+		DoAndReturn(func(_ context.Context, _ models.User) (int64, []byte, error) {
+			// The variables are still readable while the key is derived.
+			assert.Equal(t, "env-secret", os.Getenv(EnvMasterPassword))
+			return 7, []byte("dek"), nil
+		})
+	m.private.EXPECT().SetEncryptionKey([]byte("dek"))
+	m.private.EXPECT().GetAll(gomock.Any(), int64(7)).Return(nil, nil, nil)
+
+	require.NoError(t, l.Run())
+	assert.JSONEq(t, `[]`, out.String())
+
+	_, loginSet := os.LookupEnv(EnvLogin)
+	_, passwordSet := os.LookupEnv(EnvMasterPassword)
+	assert.False(t, loginSet)
+	assert.False(t, passwordSet)
+}
+
+func TestLister_Run_PartialEnvFallsBackToInput(t *testing.T) {
+	t.Setenv(EnvLogin, "alice")
+	t.Setenv(EnvMasterPassword, "")
+
+	l, m, _, _ := newTestLister(t, config.ClientApp{Offline: true}, "bob\npw\n")
+
+	m.auth.EXPECT().Login(gomock.Any(), models.User{Login: "bob", MasterPassword: "pw"}).Return(int64(7), []byte("dek"), nil)
+	m.private.EXPECT().SetEncryptionKey(gomock.Any())
+	m.private.EXPECT().GetAll(gomock.Any(), int64(7)).Return(nil, nil, nil)
+
+	require.NoError(t, l.Run())
+	assert.Equal(t, "alice", os.Getenv(EnvLogin))
+}
+
+func TestLister_Run_Errors(t *testing.T) {
+	loginErr := errors.New("bad password")
+
+	tests := []struct {
+		name    string
+		in      string
+		setup   func(m listerMocks)
+		wantErr error
+	}{
+		{name: "empty input", in: "", wantErr: ErrMissingCredentials},
+		{name: "password missing", in: "alice\n", wantErr: ErrMissingCredentials},
+		{name: "blank login", in: " \npw\n", wantErr: ErrMissingCredentials},
+		{
+			name: "login fails",
+			in:   "alice\npw\n",
+			setup: func(m listerMocks) {
+				m.auth.EXPECT().Login(gomock.Any(), gomock.Any()).Return(int64(0), nil, loginErr)
+			},
+			wantErr: loginErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, m, out, _ := newTestLister(t, config.ClientApp{}, tt.in)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+
+			assert.ErrorIs(t, l.Run(), tt.wantErr)
+			assert.Empty(t, out.String())
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+// Package clipboard copies text to the user's clipboard from the terminal
+// client.
+//
+// Two backends are provided: the system clipboard (via atotto/clipboard,
+// which needs a desktop session such as X11, Wayland, macOS or Windows) and
+// OSC 52, a terminal escape sequence that asks the terminal emulator to set
+// the clipboard and therefore also works over SSH. [New] selects the backends
+// according to a configured mode and the environment.
+package clipboard
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/atotto/clipboard"
+)
+
+// Supported values for the clipboard mode passed to [New].
+const (
+	// ModeAuto picks the backends from the environment: OSC 52 first inside
+	// an SSH session, the system clipboard first otherwise.
+	ModeAuto = "auto"
+	// ModeSystem uses only the system clipboard.
+	ModeSystem = "system"
+	// ModeOSC52 uses only the OSC 52 terminal escape sequence.
+	ModeOSC52 = "osc52"
+	// ModeNone disables copying; every write returns [ErrUnavailable].
+	ModeNone = "none"
+)
+
+// ErrUnavailable is returned by [Clipboard.WriteAll] when no backend could
+// copy the text.
+var ErrUnavailable = errors.New("clipboard unavailable")
+
+// Clipboard writes text to a clipboard.
+type Clipboard interface {
+	// WriteAll copies text to the clipboard.
+	WriteAll(text string) error
+}
+
+// IsValidMode reports whether mode is a supported clipboard mode. The empty
+// string is accepted as [ModeAuto].
+func IsValidMode(mode string) bool {
+	switch mode {
+	case "", ModeAuto, ModeSystem, ModeOSC52, ModeNone:
+		return true
+	}
+	return false
+}
+
+// New returns a [Clipboard] for mode. OSC 52 sequences are written to out.
+// getenv is used to inspect the environment (pass [os.Getenv]).
+//
+// In [ModeAuto] the system clipboard is skipped when it is known not to work
+// (no supported tool installed, or a Linux session without a display), and
+// OSC 52 is skipped when the terminal is unknown ("dumb" or TERM unset). The
+// returned Clipboard tries the remaining backends in order and returns
+// [ErrUnavailable] if none is left or all of them fail.
+func New(mode string, out io.Writer, getenv func(string) string) Clipboard {
+	env := environment{
+		systemOK: !clipboard.Unsupported && hasDisplay(getenv),
+		osc52OK:  out != nil && getenv("TERM") != "" && getenv("TERM") != "dumb",
+		ssh:      isSSH(getenv),
+	}
+	return choose(mode, env, systemClipboard{}, osc52Clipboard{out: out, tmux: getenv("TMUX") != ""})
+}
+
+// environment captures what [New] learned about the runtime environment.
+type environment struct {
+	systemOK bool // the system clipboard is expected to work
+	osc52OK  bool // the terminal is expected to understand OSC 52
+	ssh      bool // running inside an SSH session
+}
+
+// choose builds the backend chain for mode. Explicit modes ignore env.
+func choose(mode string, env environment, system, osc52 Clipboard) Clipboard {
+	switch mode {
+	case ModeSystem:
+		return chain{system}
+	case ModeOSC52:
+		return chain{osc52}
+	case ModeNone:
+		return chain{}
+	}
+
+	var backends chain
+	if env.ssh {
+		backends = appendIf(backends, osc52, env.osc52OK)
+		backends = appendIf(backends, system, env.systemOK)
+	} else {
+		backends = appendIf(backends, system, env.systemOK)
+		backends = appendIf(backends, osc52, env.osc52OK)
+	}
+	return backends
+}
+
+func appendIf(c chain, b Clipboard, ok bool) chain {
+	if ok {
+		return append(c, b)
+	}
+	return c
+}
+
+// isSSH reports whether the process runs inside an SSH session.
+func isSSH(getenv func(string) string) bool {
+	return getenv("SSH_TTY") != "" || getenv("SSH_CONNECTION") != ""
+}
+
+// hasDisplay reports whether a desktop session is reachable. Only Unix-like
+// systems other than macOS need DISPLAY or WAYLAND_DISPLAY; elsewhere the
+// system clipboard does not depend on the environment.
+func hasDisplay(getenv func(string) string) bool {
+	if !needsDisplay {
+		return true
+	}
+	return getenv("DISPLAY") != "" || getenv("WAYLAND_DISPLAY") != ""
+}
+
+// chain tries each backend in order and stops at the first success.
+type chain []Clipboard
+
+// WriteAll implements [Clipboard].
+func (c chain) WriteAll(text string) error {
+	if len(c) == 0 {
+		return ErrUnavailable
+	}
+
+	var errs []error
+	for _, b := range c {
+		err := b.WriteAll(text)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("%w: %w", ErrUnavailable, errors.Join(errs...))
+}
+
+// systemClipboard uses the operating system clipboard.
+type systemClipboard struct{}
+
+// WriteAll implements [Clipboard].
+func (systemClipboard) WriteAll(text string) error {
+	if err := clipboard.WriteAll(text); err != nil {
+		return fmt.Errorf("system clipboard: %w", err)
+	}
+	return nil
+}
+
+// osc52Clipboard asks the terminal emulator to set the clipboard with an
+// OSC 52 escape sequence. The terminal does not acknowledge the request, so a
+// write that reaches out is considered successful.
+type osc52Clipboard struct {
+	out io.Writer
+	// tmux wraps the sequence in a DCS passthrough so that tmux forwards it
+	// to the outer terminal.
+	tmux bool
+}
+
+// WriteAll implements [Clipboard].
+func (o osc52Clipboard) WriteAll(text string) error {
+	if o.out == nil {
+		return errors.New("osc52: no terminal output")
+	}
+	if _, err := io.WriteString(o.out, osc52Sequence(text, o.tmux)); err != nil {
+		return fmt.Errorf("osc52: %w", err)
+	}
+	return nil
+}
+
+// osc52Sequence builds the escape sequence that sets the system clipboard
+// ("c" selection) to text.
+func osc52Sequence(text string, tmux bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if tmux {
+		return "\x1bPtmux;\x1b" + seq + "\x1b\\"
+	}
+	return seq
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClipboard records writes and optionally fails.
+type fakeClipboard struct {
+	name  string
+	err   error
+	calls *[]string
+}
+
+func (f fakeClipboard) WriteAll(string) error {
+	*f.calls = append(*f.calls, f.name)
+	return f.err
+}
+
+func TestChoose(t *testing.T) {
+	failing := errors.New("boom")
+
+	tests := []struct {
+		name      string
+		mode      string
+		env       environment
+		systemErr error
+		osc52Err  error
+		wantCalls []string
+		wantErr   bool
+	}{
+		{
+			name:      "auto prefers system on a desktop",
+			mode:      ModeAuto,
+			env:       environment{systemOK: true, osc52OK: true},
+			wantCalls: []string{"system"},
+		},
+		{
+			name:      "auto falls back to osc52 when system fails",
+			mode:      ModeAuto,
+			env:       environment{systemOK: true, osc52OK: true},
+			systemErr: failing,
+			wantCalls: []string{"system", "osc52"},
+		},
+		{
+			name:      "auto prefers osc52 over ssh",
+			mode:      "",
+			env:       environment{systemOK: true, osc52OK: true, ssh: true},
+			wantCalls: []string{"osc52"},
+		},
+		{
+			name:      "auto skips system without display",
+			mode:      ModeAuto,
+			env:       environment{osc52OK: true},
+			wantCalls: []string{"osc52"},
+		},
+		{
+			name:      "auto skips osc52 on unknown terminal",
+			mode:      ModeAuto,
+			env:       environment{systemOK: true},
+			systemErr: failing,
+			wantCalls: []string{"system"},
+			wantErr:   true,
+		},
+		{
+			name:    "auto with nothing available",
+			mode:    ModeAuto,
+			env:     environment{ssh: true},
+			wantErr: true,
+		},
+		{
+			name:      "explicit system ignores environment",
+			mode:      ModeSystem,
+			env:       environment{osc52OK: true, ssh: true},
+			wantCalls: []string{"system"},
+		},
+		{
+			name:      "explicit osc52 ignores environment",
+			mode:      ModeOSC52,
+			env:       environment{systemOK: true},
+			wantCalls: []string{"osc52"},
+		},
+		{
+			name:    "none never copies",
+			mode:    ModeNone,
+			env:     environment{systemOK: true, osc52OK: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			system := fakeClipboard{name: "system", err: tt.systemErr, calls: &calls}
+			osc52 := fakeClipboard{name: "osc52", err: tt.osc52Err, calls: &calls}
+
+			err := choose(tt.mode, tt.env, system, osc52).WriteAll("secret")
+
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrUnavailable)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNew_OSC52WritesEscapeSequence(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{
+			name: "plain terminal",
+			env:  map[string]string{"TERM": "xterm-256color"},
+			want: "\x1b]52;c;c2VjcmV0\a",
+		},
+		{
+			name: "inside tmux",
+			env:  map[string]string{"TERM": "screen", "TMUX": "/tmp/tmux-0/default,1,0"},
+			want: "\x1bPtmux;\x1b\x1b]52;c;c2VjcmV0\a\x1b\\",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cb := New(ModeOSC52, &out, func(k string) string { return tt.env[k] })
+
+			require.NoError(t, cb.WriteAll("secret"))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestIsValidMode(t *testing.T) {
+	for _, mode := range []string{"", ModeAuto, ModeSystem, ModeOSC52, ModeNone} {
+		assert.True(t, IsValidMode(mode), mode)
+	}
+	assert.False(t, IsValidMode("x11"))
+}
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+//go:build !unix || darwin
+
+package clipboard
+
+// needsDisplay is false where the system clipboard does not depend on a
+// display server.
+const needsDisplay = false
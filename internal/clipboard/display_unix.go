@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+//go:build unix && !darwin
+
+package clipboard
+
+// needsDisplay is true where the system clipboard tools (xclip, xsel,
+// wl-copy) only work inside an X11 or Wayland session.
+const needsDisplay = true
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+// Package clock provides the time source of the services and the terminal
+// client.
+//
+// Code that stamps records, issues tokens or compares ages reads the time
+// from a [Clock] instead of calling time.Now, so that tests can fix the
+// current time with [Fake] and check expiry and timeout boundaries exactly.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// Real is the [Clock] of the system: Now returns time.Now.
+type Real struct{}
+
+// Now implements [Clock].
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Fake is a [Clock] for tests whose time only changes when Set or Advance is
+// called. It is safe for concurrent use.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake returns a [Fake] showing now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now implements [Clock].
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set moves the clock to now.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance moves the clock forward by d and returns the new time.
+func (f *Fake) Advance(d time.Duration) time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	return f.now
+}
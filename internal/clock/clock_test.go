@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	assert.Equal(t, start, c.Now())
+	assert.Equal(t, start, c.Now(), "the fake clock must not move by itself")
+
+	assert.Equal(t, start.Add(90*time.Second), c.Advance(90*time.Second))
+	assert.Equal(t, start.Add(90*time.Second), c.Now())
+
+	later := start.Add(24 * time.Hour)
+	c.Set(later)
+	assert.Equal(t, later, c.Now())
+}
+
+func TestReal(t *testing.T) {
+	before := time.Now()
+	now := Real{}.Now()
+	assert.False(t, now.Before(before))
+	assert.False(t, now.After(time.Now()))
+}
@@ -4,6 +4,9 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"time"
 )
 
@@ -41,6 +44,18 @@ type StructuredConfig struct {
 	// already loaded from environment variables and flags.
 	// Populated via the CONFIG environment variable or the -c / -config flag.
 	JSONFilePath string `env:"CONFIG"`
+
+	// PrintConfig makes the binary print its effective configuration with
+	// [Print] and exit instead of starting.
+	// Populated via the CONFIG_PRINT environment variable or the
+	// -config-print flag.
+	PrintConfig bool `env:"CONFIG_PRINT"`
+
+	// MigrateDryRun makes the server connect to its database, print the
+	// migrations it would apply on startup and exit without applying them.
+	// Populated via the MIGRATE_DRY_RUN environment variable or the
+	// -migrate-dry-run flag.
+	MigrateDryRun bool `env:"MIGRATE_DRY_RUN"`
 }
 
 // Storage groups the configuration for all storage backends used by the
@@ -51,6 +66,110 @@ type Storage struct {
 
 	// Files holds the file-system storage settings for binary vault data.
 	Files Files `envPrefix:"FILES_"`
+
+	// HardDelete makes deletes remove vault items from the database
+	// immediately instead of keeping a soft-deleted tombstone row. Clients
+	// then detect the removal by the item's absence from the sync states.
+	// Env: STORAGE_HARD_DELETE
+	HardDelete bool `env:"HARD_DELETE"`
+
+	// VersionHistory is the number of previous versions of each vault item
+	// the server keeps in its version history. Zero (the default) disables
+	// the history.
+	// Env: STORAGE_VERSION_HISTORY
+	VersionHistory int `env:"VERSION_HISTORY"`
+
+	// MaxClientSideIDs caps the number of client-side IDs a single download
+	// or states request may filter by; each ID becomes one query parameter.
+	// Zero means [DefaultMaxClientSideIDs].
+	// Env: STORAGE_MAX_CLIENT_SIDE_IDS
+	MaxClientSideIDs int `env:"MAX_CLIENT_SIDE_IDS"`
+
+	// SyncLockTimeout is how long a batch update or delete waits for the
+	// per-user sync lock held by a concurrent one before it is rejected.
+	// Zero means [DefaultSyncLockTimeout]; a negative value disables the
+	// lock.
+	// Env: STORAGE_SYNC_LOCK_TIMEOUT
+	SyncLockTimeout time.Duration `env:"SYNC_LOCK_TIMEOUT"`
+
+	// SkipSchemaCheck disables the startup check that the "ciphers" table
+	// has exactly the columns the server queries.
+	// Env: STORAGE_SKIP_SCHEMA_CHECK
+	SkipSchemaCheck bool `env:"SKIP_SCHEMA_CHECK"`
+}
+
+// DefaultMaxNotesLength is the notes limit, in characters, used when
+// [App.MaxNotesLength] is not set.
+const DefaultMaxNotesLength = 10000
+
+// NotesLengthLimit returns the configured notes limit, or
+// [DefaultMaxNotesLength] when it is not set.
+func (a App) NotesLengthLimit() int {
+	if a.MaxNotesLength <= 0 {
+		return DefaultMaxNotesLength
+	}
+	return a.MaxNotesLength
+}
+
+// DefaultMaxBatchEntries is the per-request update and delete entry cap used
+// when [App.MaxBatchEntries] is not set. Clients split larger operations into
+// batches of this size.
+const DefaultMaxBatchEntries = 500
+
+// BatchEntriesLimit returns the configured per-request update and delete
+// entry cap, or [DefaultMaxBatchEntries] when it is not set.
+func (a App) BatchEntriesLimit() int {
+	if a.MaxBatchEntries <= 0 {
+		return DefaultMaxBatchEntries
+	}
+	return a.MaxBatchEntries
+}
+
+// MaxClientIDPrefixLength is the longest [App.ClientIDPrefix]: together with
+// the dash and the 36-character UUID it fills the 64-character client-side ID
+// column of the server.
+const MaxClientIDPrefixLength = 27
+
+// isValidClientIDPrefix reports whether prefix is empty or a short run of
+// letters, digits, underscores and dots.
+func isValidClientIDPrefix(prefix string) bool {
+	if len(prefix) > MaxClientIDPrefixLength {
+		return false
+	}
+	for _, r := range prefix {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultMaxClientSideIDs is the per-request client-side ID cap used when
+// [Storage.MaxClientSideIDs] is not set. It stays far below PostgreSQL's
+// limit of 65535 bind parameters per query.
+const DefaultMaxClientSideIDs = 1000
+
+// ClientSideIDsLimit returns the configured per-request client-side ID cap,
+// or [DefaultMaxClientSideIDs] when it is not set.
+func (s Storage) ClientSideIDsLimit() int {
+	if s.MaxClientSideIDs <= 0 {
+		return DefaultMaxClientSideIDs
+	}
+	return s.MaxClientSideIDs
+}
+
+// DefaultSyncLockTimeout is the per-user sync lock wait used when
+// [Storage.SyncLockTimeout] is not set.
+const DefaultSyncLockTimeout = 5 * time.Second
+
+// SyncLockWait returns the configured per-user sync lock wait, or
+// [DefaultSyncLockTimeout] when it is not set. A negative result means the
+// lock is disabled.
+func (s Storage) SyncLockWait() time.Duration {
+	if s.SyncLockTimeout == 0 {
+		return DefaultSyncLockTimeout
+	}
+	return s.SyncLockTimeout
 }
 
 // App holds application-level configuration values that control security,
@@ -66,6 +185,12 @@ type App struct {
 	// Env: APP_TOKEN_SIGN_KEY
 	TokenSignKey string `env:"TOKEN_SIGN_KEY"`
 
+	// TokenSignKeyFile is the path to a file holding the JWT signing key,
+	// e.g. a mounted container secret. A trailing line break is ignored. It
+	// is an alternative to TokenSignKey; setting both is an error.
+	// Env: APP_TOKEN_SIGN_KEY_FILE
+	TokenSignKeyFile string `env:"TOKEN_SIGN_KEY_FILE"`
+
 	// TokenIssuer is the "iss" claim embedded in every issued JWT token.
 	// It identifies the service that issued the token and is validated on
 	// every authenticated request.
@@ -86,6 +211,202 @@ type App struct {
 	// (e.g. "1.2.3"). Exposed via the /api/version/ endpoint.
 	// Env: APP_VERSION
 	Version string `env:"VERSION"`
+
+	// LogLevel is the minimum level emitted by the application logger:
+	// "debug", "info", "warn" or "error". Empty means "info".
+	// Env: APP_LOG_LEVEL
+	LogLevel string `env:"LOG_LEVEL"`
+
+	// LogFormat selects the logger output encoding: "json" or "console".
+	// The server defaults to "json" and the client to "console".
+	// Env: APP_LOG_FORMAT
+	LogFormat string `env:"LOG_FORMAT"`
+
+	// MaxBinarySize is the largest file, in bytes, the client accepts as a
+	// Binary attachment. Zero means [DefaultMaxBinarySize].
+	// Env: APP_MAX_BINARY_SIZE
+	MaxBinarySize int64 `env:"MAX_BINARY_SIZE"`
+
+	// MaxNotesLength is the longest note, in characters, the client accepts
+	// when adding an entry. The server rejects encrypted notes longer than a
+	// note of this length can produce. Zero means [DefaultMaxNotesLength].
+	// Env: APP_MAX_NOTES_LENGTH
+	MaxNotesLength int `env:"MAX_NOTES_LENGTH"`
+
+	// MaxBatchEntries caps the number of entries a single update or delete
+	// request may carry, which bounds how long its transaction holds locks.
+	// Larger requests get 400. Zero means [DefaultMaxBatchEntries].
+	// Env: APP_MAX_BATCH_ENTRIES
+	MaxBatchEntries int `env:"MAX_BATCH_ENTRIES"`
+
+	// ClientIDPrefix is an optional device name prepended to the client-side
+	// IDs of new entries, e.g. "laptop" gives "laptop-<uuid>". It is purely
+	// informational: the server matches IDs as opaque strings. At most
+	// [MaxClientIDPrefixLength] characters of [A-Za-z0-9_.]. Empty means no prefix.
+	// Env: APP_CLIENT_ID_PREFIX
+	ClientIDPrefix string `env:"CLIENT_ID_PREFIX"`
+
+	// SyncStaleAfter is the age after which the client highlights the last
+	// successful sync time as stale. Zero means [DefaultSyncStaleAfter].
+	// Env: APP_SYNC_STALE_AFTER
+	SyncStaleAfter time.Duration `env:"SYNC_STALE_AFTER"`
+
+	// StatesCacheTTL is how long the client reuses the item states fetched
+	// from the server for back-to-back syncs. Zero or negative disables the
+	// cache.
+	// Env: APP_STATES_CACHE_TTL
+	StatesCacheTTL time.Duration `env:"STATES_CACHE_TTL"`
+
+	// Clipboard selects the client clipboard backend: "auto", "system",
+	// "osc52" or "none". Empty means "auto".
+	// Env: APP_CLIPBOARD
+	Clipboard string `env:"CLIPBOARD"`
+
+	// CopyGenerated makes the client's password generator also copy the
+	// generated password to the clipboard, cleared again after a while.
+	// Env: APP_COPY_GENERATED
+	CopyGenerated bool `env:"COPY_GENERATED"`
+
+	// DetectDuplicates enables the client's duplicate search after a manual
+	// sync. Found duplicates are only merged after the user confirms.
+	// Env: APP_DETECT_DUPLICATES
+	DetectDuplicates bool `env:"DETECT_DUPLICATES"`
+
+	// SyncOnChange makes the client run a sync right after every successful
+	// create, update or delete in the TUI. A failed sync does not undo the
+	// local change; it is retried by the next manual or background sync.
+	// Env: APP_SYNC_ON_CHANGE
+	SyncOnChange bool `env:"SYNC_ON_CHANGE"`
+
+	// LoginTimeout limits the client's whole login handshake with the
+	// server, retries included. Zero means [DefaultLoginTimeout]; a negative
+	// value disables the limit.
+	// Env: APP_LOGIN_TIMEOUT
+	LoginTimeout time.Duration `env:"LOGIN_TIMEOUT"`
+
+	// LoginRetries is how many times the client repeats a login request
+	// that failed with a network error or a 5xx response. Zero means
+	// [DefaultLoginRetries]; a negative value disables retries.
+	// Env: APP_LOGIN_RETRIES
+	LoginRetries int `env:"LOGIN_RETRIES"`
+
+	// SyncBreakerThreshold is the number of syncs in a row that fail because
+	// the server cannot be reached after which the client stops trying for
+	// SyncBreakerCooldown. Zero means [DefaultSyncBreakerThreshold]; a
+	// negative value disables the breaker.
+	// Env: APP_SYNC_BREAKER_THRESHOLD
+	SyncBreakerThreshold int `env:"SYNC_BREAKER_THRESHOLD"`
+
+	// SyncBreakerCooldown is how long syncs are skipped once the breaker
+	// opened, before one is let through to probe the server. Zero means
+	// [DefaultSyncBreakerCooldown].
+	// Env: APP_SYNC_BREAKER_COOLDOWN
+	SyncBreakerCooldown time.Duration `env:"SYNC_BREAKER_COOLDOWN"`
+
+	// PingInterval is how often the TUI checks that the server is reachable
+	// to show the connection indicator. Zero means [DefaultPingInterval]; a
+	// negative value disables the check.
+	// Env: APP_PING_INTERVAL
+	PingInterval time.Duration `env:"PING_INTERVAL"`
+
+	// IdleLogout logs the TUI out after that long without a key press.
+	// Zero means [DefaultIdleLogout]; a negative value disables the logout.
+	// Env: APP_IDLE_LOGOUT
+	IdleLogout time.Duration `env:"IDLE_LOGOUT"`
+
+	// NonceAudit makes the client record every AES-GCM nonce it generates
+	// and refuse to encrypt if one repeats. Meant for diagnostic runs: the
+	// recorded nonces are kept in memory for the lifetime of the process.
+	// Env: APP_NONCE_AUDIT
+	NonceAudit bool `env:"NONCE_AUDIT"`
+
+	// DebugHTTP makes the client log every request to the server: method,
+	// URL, status, duration and body sizes. Bodies and the Authorization
+	// header value are never logged.
+	// Env: APP_DEBUG_HTTP
+	DebugHTTP bool `env:"DEBUG_HTTP"`
+
+	// Reencrypt makes the client re-encrypt, after the initial sync of every
+	// online login, the entries still stored in an older encryption format.
+	// Without it, entries move to the current format only when edited.
+	// Env: APP_REENCRYPT
+	Reencrypt bool `env:"REENCRYPT"`
+
+	// Offline runs the client against its local vault only: the server is
+	// never contacted, the local database is opened read-only and every
+	// action that would modify the vault is disabled.
+	// Env: APP_OFFLINE
+	Offline bool `env:"OFFLINE"`
+
+	// DefaultDataType preselects the type in the client's add flow: "login",
+	// "text", "binary" or "card". Empty keeps the first type selected.
+	// Env: APP_DEFAULT_DATA_TYPE
+	DefaultDataType string `env:"DEFAULT_DATA_TYPE"`
+
+	// EnabledDataTypes limits the types the client can add to a
+	// comma-separated subset of "login", "text", "binary" and "card".
+	// Entries of other types stay viewable but read-only. Empty enables all.
+	// Env: APP_ENABLED_DATA_TYPES
+	EnabledDataTypes string `env:"ENABLED_DATA_TYPES"`
+
+	// DefaultFolder pre-fills the folder in the client's add flow.
+	// Env: APP_DEFAULT_FOLDER
+	DefaultFolder string `env:"DEFAULT_FOLDER"`
+
+	// SyncMode restricts client sync to one direction: "bidirectional"
+	// (default), "push-only" or "pull-only".
+	// Env: APP_SYNC_MODE
+	SyncMode string `env:"SYNC_MODE"`
+
+	// SyncConflicts selects how the client handles an update the server
+	// rejects with a version conflict: "server-wins" (default) or "manual",
+	// which asks the user which version to keep.
+	// Env: APP_SYNC_CONFLICTS
+	SyncConflicts string `env:"SYNC_CONFLICTS"`
+
+	// SyncEvents enables the client's machine-readable sync event stream:
+	// "stderr" or a file path that JSON lines are appended to. Empty
+	// disables the stream.
+	// Env: APP_SYNC_EVENTS
+	SyncEvents string `env:"SYNC_EVENTS"`
+
+	// Theme selects the client color theme: "default", "high-contrast" or
+	// "monochrome". Empty means "default".
+	// Env: APP_THEME
+	Theme string `env:"THEME"`
+
+	// ListColumns selects the columns of the client's vault list and their
+	// order as a comma-separated list of "name", "type" and "folder". The
+	// row number is always shown first. Empty means "name,type,folder".
+	// Env: APP_LIST_COLUMNS
+	ListColumns string `env:"LIST_COLUMNS"`
+
+	// MaskNames starts the client's vault list in privacy mode, where entry
+	// names are partially masked except on the focused row.
+	// Env: APP_MASK_NAMES
+	MaskNames bool `env:"MASK_NAMES"`
+
+	// FolderTree starts the client's vault list grouped into a folder tree,
+	// where folders named like "Work/Email" are nested under their parents.
+	// Env: APP_FOLDER_TREE
+	FolderTree bool `env:"FOLDER_TREE"`
+
+	// ListJSON makes the client print the decrypted vault list as JSON and
+	// exit instead of starting the TUI. Only metadata is printed unless
+	// ListSecrets is also set.
+	// Env: APP_LIST_JSON
+	ListJSON bool `env:"LIST_JSON"`
+
+	// ListSecrets includes the secret fields of every entry in the ListJSON
+	// output.
+	// Env: APP_LIST_SECRETS
+	ListSecrets bool `env:"LIST_SECRETS"`
+
+	// Insecure allows the client to talk to a plain http:// server address.
+	// Meant for local development only: without TLS the auth hash and the
+	// bearer token are sent in cleartext.
+	// Env: APP_INSECURE
+	Insecure bool `env:"INSECURE"`
 }
 
 // Server holds network and timeout settings for the inbound transport layer.
@@ -100,10 +421,227 @@ type Server struct {
 	// Env: SERVER_GRPC_ADDRESS
 	GRPCAddress string `env:"GRPC_ADDRESS"`
 
+	// AdminAddress is an optional separate TCP address, in "host:port"
+	// format, for the operational endpoints: health checks and the admin
+	// routes. When set, the admin routes are served only there, so they can
+	// be bound to an internal interface. Empty serves everything on
+	// HTTPAddress.
+	// Env: SERVER_ADMIN_ADDRESS
+	AdminAddress string `env:"ADMIN_ADDRESS"`
+
 	// RequestTimeout is the maximum duration allowed for a single inbound
 	// request before the server cancels it (e.g. "30s", "1m").
 	// Env: SERVER_REQUEST_TIMEOUT
 	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT"`
+
+	// ReadTimeout is the maximum duration for reading an entire request,
+	// including the body. Zero falls back to RequestTimeout, then to
+	// [DefaultServerReadTimeout].
+	// Env: SERVER_READ_TIMEOUT
+	ReadTimeout time.Duration `env:"READ_TIMEOUT"`
+
+	// ReadHeaderTimeout is the maximum duration for reading request headers.
+	// It is the main defence against slowloris-style clients. Zero means
+	// [DefaultServerReadHeaderTimeout].
+	// Env: SERVER_READ_HEADER_TIMEOUT
+	ReadHeaderTimeout time.Duration `env:"READ_HEADER_TIMEOUT"`
+
+	// WriteTimeout is the maximum duration for writing a response. It bounds
+	// full-vault downloads too, so it must be at least [MinServerWriteTimeout].
+	// Zero means [DefaultServerWriteTimeout].
+	// Env: SERVER_WRITE_TIMEOUT
+	WriteTimeout time.Duration `env:"WRITE_TIMEOUT"`
+
+	// IdleTimeout is the maximum time a keep-alive connection may stay idle.
+	// Zero means [DefaultServerIdleTimeout].
+	// Env: SERVER_IDLE_TIMEOUT
+	IdleTimeout time.Duration `env:"IDLE_TIMEOUT"`
+
+	// AdminToken is the shared secret required in the "X-Admin-Token" header
+	// by admin endpoints. Admin endpoints are disabled when it is empty.
+	// Env: SERVER_ADMIN_TOKEN
+	AdminToken string `env:"ADMIN_TOKEN"`
+
+	// AccessLogLevel is the level at which one access-log line per request is
+	// emitted: "debug", "info", "warn" or "error". Empty means "info".
+	// Env: SERVER_ACCESS_LOG_LEVEL
+	AccessLogLevel string `env:"ACCESS_LOG_LEVEL"`
+
+	// BasePath is the URL path prefix under which all API routes are
+	// registered (e.g. "/vault" behind a reverse proxy). Empty means
+	// [DefaultServerBasePath].
+	// Env: SERVER_BASE_PATH
+	BasePath string `env:"BASE_PATH"`
+
+	// MinSaltLength is the smallest encryption salt, in decoded bytes, the
+	// register endpoint accepts. Zero means [DefaultMinSaltLength].
+	// Env: SERVER_MIN_SALT_LENGTH
+	MinSaltLength int `env:"MIN_SALT_LENGTH"`
+
+	// MaxInFlight is the number of requests processed at the same time;
+	// requests beyond it are rejected with 503 Service Unavailable. Health
+	// endpoints are not counted. Zero or negative disables the limit.
+	// Env: SERVER_MAX_IN_FLIGHT
+	MaxInFlight int `env:"MAX_IN_FLIGHT"`
+
+	// GzipLevel is the gzip level of compressed responses, from 1 (fastest)
+	// to 9 (smallest). Zero means the gzip default, 6.
+	// Env: SERVER_GZIP_LEVEL
+	GzipLevel int `env:"GZIP_LEVEL"`
+
+	// GzipMinSize is the smallest response body, in bytes, that is
+	// compressed. Zero means [DefaultGzipMinSize]; a negative value
+	// compresses every response.
+	// Env: SERVER_GZIP_MIN_SIZE
+	GzipMinSize int `env:"GZIP_MIN_SIZE"`
+
+	// StateTTL is how long per-user state kept in memory, such as the last
+	// recorded sync activity, survives without being accessed before the
+	// cleanup evicts it. Zero means [DefaultStateTTL].
+	// Env: SERVER_STATE_TTL
+	StateTTL time.Duration `env:"STATE_TTL"`
+
+	// StateCleanupInterval is how often stale in-memory state is evicted.
+	// Zero means [DefaultStateCleanupInterval]; a negative value disables
+	// the cleanup.
+	// Env: SERVER_STATE_CLEANUP_INTERVAL
+	StateCleanupInterval time.Duration `env:"STATE_CLEANUP_INTERVAL"`
+
+	// LoginMaxAttempts is the number of failed logins to one account within
+	// LoginAttemptWindow after which the account is locked for LoginLockout.
+	// Zero or negative disables the lockout.
+	// Env: SERVER_LOGIN_MAX_ATTEMPTS
+	LoginMaxAttempts int `env:"LOGIN_MAX_ATTEMPTS"`
+
+	// LoginAttemptWindow is the period failed logins are counted in. Zero
+	// means [DefaultLoginAttemptWindow].
+	// Env: SERVER_LOGIN_ATTEMPT_WINDOW
+	LoginAttemptWindow time.Duration `env:"LOGIN_ATTEMPT_WINDOW"`
+
+	// LoginLockout is how long an account stays locked once
+	// LoginMaxAttempts is reached. Zero means [DefaultLoginLockout].
+	// Env: SERVER_LOGIN_LOCKOUT
+	LoginLockout time.Duration `env:"LOGIN_LOCKOUT"`
+}
+
+// Defaults of the login lockout, see [Server.LoginAttemptWindow] and
+// [Server.LoginLockout].
+const (
+	DefaultLoginAttemptWindow = 15 * time.Minute
+	DefaultLoginLockout       = 15 * time.Minute
+)
+
+// LoginAttemptWindowOrDefault returns the configured failed-login window, or
+// [DefaultLoginAttemptWindow] when it is not set.
+func (s Server) LoginAttemptWindowOrDefault() time.Duration {
+	if s.LoginAttemptWindow <= 0 {
+		return DefaultLoginAttemptWindow
+	}
+	return s.LoginAttemptWindow
+}
+
+// LoginLockoutOrDefault returns the configured lockout duration, or
+// [DefaultLoginLockout] when it is not set.
+func (s Server) LoginLockoutOrDefault() time.Duration {
+	if s.LoginLockout <= 0 {
+		return DefaultLoginLockout
+	}
+	return s.LoginLockout
+}
+
+// Defaults of the in-memory state cleanup, see [Server.StateTTL] and
+// [Server.StateCleanupInterval].
+const (
+	DefaultStateTTL             = time.Hour
+	DefaultStateCleanupInterval = 10 * time.Minute
+)
+
+// StateTTLOrDefault returns the configured state TTL, or [DefaultStateTTL]
+// when it is not set.
+func (s Server) StateTTLOrDefault() time.Duration {
+	if s.StateTTL <= 0 {
+		return DefaultStateTTL
+	}
+	return s.StateTTL
+}
+
+// StateCleanupIntervalOrDefault returns the configured cleanup interval, or
+// [DefaultStateCleanupInterval] when it is not set. A negative result means
+// the cleanup is disabled.
+func (s Server) StateCleanupIntervalOrDefault() time.Duration {
+	if s.StateCleanupInterval == 0 {
+		return DefaultStateCleanupInterval
+	}
+	return s.StateCleanupInterval
+}
+
+// DefaultGzipMinSize is the smallest compressed response body used when
+// [Server.GzipMinSize] is not set. Smaller bodies fit in a packet anyway and
+// would gain less than the gzip header adds.
+const DefaultGzipMinSize = 512
+
+// GzipMinSizeLimit returns the configured minimum size of a compressed
+// response, or [DefaultGzipMinSize] when it is not set. A negative result
+// means every response is compressed.
+func (s Server) GzipMinSizeLimit() int {
+	if s.GzipMinSize == 0 {
+		return DefaultGzipMinSize
+	}
+	return s.GzipMinSize
+}
+
+// DefaultMinSaltLength is the encryption salt minimum used when
+// [Server.MinSaltLength] is not set. It matches the salt length generated by
+// the client.
+const DefaultMinSaltLength = 16
+
+// DefaultServerBasePath is the API path prefix used when [Server.BasePath]
+// is not set.
+const DefaultServerBasePath = "/api"
+
+// NormalizeBasePath returns p with surrounding whitespace and trailing
+// slashes removed and a leading slash added. An empty p yields
+// [DefaultServerBasePath]; "/" is kept as is and mounts the API at the root.
+func NormalizeBasePath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return DefaultServerBasePath
+	}
+	p = "/" + strings.Trim(p, "/")
+	return p
+}
+
+// Default HTTP server timeouts applied by [Server.WithTimeoutDefaults].
+const (
+	DefaultServerReadTimeout       = 30 * time.Second
+	DefaultServerReadHeaderTimeout = 10 * time.Second
+	DefaultServerWriteTimeout      = 5 * time.Minute
+	DefaultServerIdleTimeout       = 2 * time.Minute
+)
+
+// MinServerWriteTimeout is the smallest accepted explicit write timeout.
+// Shorter values would cut off downloads of vaults with large attachments.
+const MinServerWriteTimeout = 30 * time.Second
+
+// WithTimeoutDefaults returns a copy of s with every unset HTTP timeout
+// replaced by its default. ReadTimeout falls back to RequestTimeout first.
+func (s Server) WithTimeoutDefaults() Server {
+	if s.ReadTimeout == 0 {
+		s.ReadTimeout = s.RequestTimeout
+	}
+	if s.ReadTimeout == 0 {
+		s.ReadTimeout = DefaultServerReadTimeout
+	}
+	if s.ReadHeaderTimeout == 0 {
+		s.ReadHeaderTimeout = DefaultServerReadHeaderTimeout
+	}
+	if s.WriteTimeout == 0 {
+		s.WriteTimeout = DefaultServerWriteTimeout
+	}
+	if s.IdleTimeout == 0 {
+		s.IdleTimeout = DefaultServerIdleTimeout
+	}
+	return s
 }
 
 // DB holds connection settings for the relational database backend.
@@ -121,6 +659,23 @@ type Files struct {
 	// encrypted binary vault files are stored and served from.
 	// Env: STORAGE_FILES_BINARY_DATA_DIR
 	BinaryDataDir string `env:"BINARY_DATA_DIR"`
+
+	// DataDir is the directory of the client's local databases, one file
+	// per account login. Empty selects [DefaultClientDataDir]. Ignored by
+	// the client when a DSN is configured.
+	// Env: STORAGE_FILES_DATA_DIR
+	DataDir string `env:"DATA_DIR"`
+
+	// BackupDir is the directory where the client keeps snapshots of its
+	// local database, taken before each sync. Empty selects "backups" inside
+	// DataDir.
+	// Env: STORAGE_FILES_BACKUP_DIR
+	BackupDir string `env:"BACKUP_DIR"`
+
+	// BackupCount is how many snapshots of each local database the client
+	// keeps; the oldest are deleted first. Zero disables the snapshots.
+	// Env: STORAGE_FILES_BACKUP_COUNT
+	BackupCount int `env:"BACKUP_COUNT"`
 }
 
 // Adapter holds configuration for external adapter integrations.
@@ -166,3 +721,42 @@ func GetStructuredConfig() (*StructuredConfig, error) {
 		withJSON().
 		build()
 }
+
+// GetServerConfig loads the configuration via [GetStructuredConfig], reads
+// the token signing key from [App.TokenSignKeyFile] when one is configured,
+// and checks that the settings the server requires are present.
+// No JSON file is needed: the server can be configured with environment
+// variables and flags alone.
+//
+// Returns [ErrMissingRequiredConfig] naming every missing setting.
+func GetServerConfig() (*StructuredConfig, error) {
+	cfg, err := GetStructuredConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err = cfg.App.loadTokenSignKeyFile(); err != nil {
+		return nil, err
+	}
+	if err = cfg.validateServer(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadTokenSignKeyFile replaces an empty TokenSignKey with the contents of
+// TokenSignKeyFile, without a trailing line break.
+func (a *App) loadTokenSignKeyFile() error {
+	if a.TokenSignKeyFile == "" {
+		return nil
+	}
+	if a.TokenSignKey != "" {
+		return fmt.Errorf("%w: both a token signing key and a key file are set", ErrInvalidServerConfigs)
+	}
+
+	data, err := os.ReadFile(a.TokenSignKeyFile)
+	if err != nil {
+		return fmt.Errorf("%w: token signing key file: %w", ErrInvalidServerConfigs, err)
+	}
+	a.TokenSignKey = strings.TrimRight(string(data), "\r\n")
+	return nil
+}
@@ -6,6 +6,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 
 	"dario.cat/mergo"
 )
@@ -99,7 +100,9 @@ func (b *configBuilder) withFlags() *configBuilder {
 // appending the result to the builder.
 //
 // When multiple sources specify a JSONFilePath, the last non-empty value
-// wins. If no path is found, withJSON is a no-op.
+// wins. If no path is found, or the file does not exist, withJSON is a
+// no-op so that a deployment configured through the environment alone does
+// not need the file.
 //
 // If parsing fails, the error is joined into b.err and the builder is
 // returned unchanged. Returns the same *configBuilder to support method
@@ -117,6 +120,9 @@ func (b *configBuilder) withJSON() *configBuilder {
 
 	if isJSONSpecified {
 		jsonCfg, err := parseJSON(jsonPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			return b
+		}
 		if err != nil {
 			b.err = errors.Join(b.err, err)
 			return b
@@ -5,8 +5,11 @@ package config
 
 import (
 	"encoding/json"
+	"flag"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -168,15 +171,26 @@ func TestWithJSON_AppendsConfig_WhenValidFile(t *testing.T) {
 	assert.Equal(t, "json-issuer", b.configs[1].App.TokenIssuer)
 }
 
-// TestWithJSON_SetsError_WhenFileNotFound verifies that a missing file path
-// sets b.err.
-func TestWithJSON_SetsError_WhenFileNotFound(t *testing.T) {
+// TestWithJSON_SkipsMissingFile verifies that a path to a file that does
+// not exist is ignored: the config is then loaded from env and flags only.
+func TestWithJSON_SkipsMissingFile(t *testing.T) {
 	b := newConfigBuilder()
 	b.configs = append(b.configs, &StructuredConfig{
 		JSONFilePath: "/nonexistent/config.json",
 	})
 	b.withJSON()
 
+	assert.NoError(t, b.err)
+	assert.Len(t, b.configs, 1)
+}
+
+// TestWithJSON_SetsError_WhenPathIsDirectory verifies that a path that
+// exists but cannot be read as a file sets b.err.
+func TestWithJSON_SetsError_WhenPathIsDirectory(t *testing.T) {
+	b := newConfigBuilder()
+	b.configs = append(b.configs, &StructuredConfig{JSONFilePath: t.TempDir()})
+	b.withJSON()
+
 	assert.Error(t, b.err)
 }
 
@@ -232,3 +246,166 @@ func TestWithJSON_DoesNotAppend_WhenErrorAlreadySet(t *testing.T) {
 	// the pre-existing error is preserved alongside.
 	assert.ErrorIs(t, b.err, assert.AnError)
 }
+
+func TestStructuredConfigValidate_ServerTimeouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  Server
+		wantErr bool
+		wantMsg string
+	}{
+		{name: "unset timeouts", server: Server{}},
+		{name: "valid timeouts", server: Server{ReadTimeout: time.Second, WriteTimeout: MinServerWriteTimeout}},
+		{name: "negative read timeout", server: Server{ReadTimeout: -time.Second}, wantErr: true},
+		{name: "negative idle timeout", server: Server{IdleTimeout: -time.Second}, wantErr: true},
+		{name: "write timeout too short for downloads", server: Server{WriteTimeout: time.Second}, wantErr: true},
+		{name: "valid access log level", server: Server{AccessLogLevel: "debug"}},
+		{name: "unknown access log level", server: Server{AccessLogLevel: "verbose"}, wantErr: true},
+		{name: "custom base path", server: Server{BasePath: "/vault/"}},
+		{name: "base path with query", server: Server{BasePath: "/vault?x=1"}, wantErr: true},
+		{name: "separate admin address", server: Server{HTTPAddress: "0.0.0.0:8080", AdminAddress: "127.0.0.1:9100"}},
+		{name: "address without port", server: Server{HTTPAddress: "localhost"}, wantErr: true},
+		{name: "admin port out of range", server: Server{AdminAddress: "127.0.0.1:70000"}, wantErr: true},
+		{name: "admin address shared with API", server: Server{HTTPAddress: ":8080", AdminAddress: ":8080"}, wantErr: true},
+		{name: "default gzip level", server: Server{GzipLevel: 0}},
+		{name: "fastest gzip level", server: Server{GzipLevel: 1}},
+		{name: "best gzip level", server: Server{GzipLevel: 9}},
+		{name: "gzip level out of range", server: Server{GzipLevel: 10}, wantErr: true, wantMsg: "gzip level 10 must be 0 (default) or between 1 and 9"},
+		{name: "negative gzip level", server: Server{GzipLevel: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &StructuredConfig{Server: tt.server}
+			err := cfg.validate()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidServerConfigs)
+				if tt.wantMsg != "" {
+					assert.ErrorContains(t, err, tt.wantMsg)
+				}
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNormalizeBasePath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: DefaultServerBasePath},
+		{in: "  ", want: DefaultServerBasePath},
+		{in: "/vault", want: "/vault"},
+		{in: "vault/", want: "/vault"},
+		{in: "/vault/api//", want: "/vault/api"},
+		{in: "/", want: "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeBasePath(tt.in))
+		})
+	}
+}
+
+func TestGetServerConfig_EnvOnly(t *testing.T) {
+	fullEnv := map[string]string{
+		"CONFIG":                  filepath.Join(t.TempDir(), "absent.json"),
+		"APP_TOKEN_SIGN_KEY":      "jwt_secret",
+		"SERVER_ADDRESS":          "0.0.0.0:8080",
+		"STORAGE_DB_DATABASE_URI": "postgres://user:pass@db/vault",
+	}
+	without := func(key string) map[string]string {
+		env := make(map[string]string, len(fullEnv))
+		for k, v := range fullEnv {
+			if k != key {
+				env[k] = v
+			}
+		}
+		return env
+	}
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantMissing []string
+	}{
+		{name: "no file, full env", env: fullEnv},
+		{name: "no config path", env: without("CONFIG")},
+		{name: "grpc address only", env: func() map[string]string {
+			env := without("SERVER_ADDRESS")
+			env["SERVER_GRPC_ADDRESS"] = "0.0.0.0:9090"
+			return env
+		}()},
+		{name: "missing DSN", env: without("STORAGE_DB_DATABASE_URI"), wantMissing: []string{"STORAGE_DB_DATABASE_URI"}},
+		{name: "missing listen address", env: without("SERVER_ADDRESS"), wantMissing: []string{"SERVER_ADDRESS", "SERVER_GRPC_ADDRESS"}},
+		{name: "missing signing key", env: without("APP_TOKEN_SIGN_KEY"), wantMissing: []string{"APP_TOKEN_SIGN_KEY"}},
+		{name: "nothing set", env: map[string]string{}, wantMissing: []string{"STORAGE_DB_DATABASE_URI", "SERVER_ADDRESS", "APP_TOKEN_SIGN_KEY"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnvVars(t, tt.env)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			oldArgs := os.Args
+			os.Args = []string{"cmd"}
+			t.Cleanup(func() { os.Args = oldArgs })
+
+			cfg, err := GetServerConfig()
+			if len(tt.wantMissing) > 0 {
+				require.ErrorIs(t, err, ErrMissingRequiredConfig)
+				for _, name := range tt.wantMissing {
+					assert.Contains(t, err.Error(), name)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "jwt_secret", cfg.App.TokenSignKey)
+			assert.Equal(t, "postgres://user:pass@db/vault", cfg.Storage.DB.DSN)
+		})
+	}
+}
+
+func TestGetServerConfig_TokenSignKeyFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "jwt")
+	require.NoError(t, os.WriteFile(keyFile, []byte("file-secret\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantKey string
+		wantErr error
+	}{
+		{name: "key from file", env: map[string]string{"APP_TOKEN_SIGN_KEY_FILE": keyFile}, wantKey: "file-secret"},
+		{name: "key from env", env: map[string]string{"APP_TOKEN_SIGN_KEY": "env-secret"}, wantKey: "env-secret"},
+		{name: "both set", env: map[string]string{"APP_TOKEN_SIGN_KEY": "env-secret", "APP_TOKEN_SIGN_KEY_FILE": keyFile}, wantErr: ErrInvalidServerConfigs},
+		{name: "file missing", env: map[string]string{"APP_TOKEN_SIGN_KEY_FILE": keyFile + ".absent"}, wantErr: ErrInvalidServerConfigs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{
+				"SERVER_ADDRESS":          "0.0.0.0:8080",
+				"STORAGE_DB_DATABASE_URI": "postgres://user:pass@db/vault",
+			}
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			setEnvVars(t, env)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			oldArgs := os.Args
+			os.Args = []string{"cmd"}
+			t.Cleanup(func() { os.Args = oldArgs })
+
+			cfg, err := GetServerConfig()
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKey, cfg.App.TokenSignKey)
+		})
+	}
+}
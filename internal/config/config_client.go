@@ -5,7 +5,14 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clipboard"
+	"github.com/MKhiriev/go-pass-keeper/models"
 )
 
 // ClientApp holds client-side application settings derived from the shared
@@ -13,6 +20,283 @@ import (
 type ClientApp struct {
 	// HashKey is the HMAC key used by the client for payload integrity checks.
 	HashKey string
+	// LogLevel is the minimum level emitted by the client logger.
+	LogLevel string
+	// LogFormat is the client logger output encoding. Defaults to
+	// [DefaultClientLogFormat] when not configured.
+	LogFormat string
+	// MaxBinarySize is the largest Binary attachment, in bytes, the client
+	// accepts. Defaults to [DefaultMaxBinarySize] when not configured.
+	MaxBinarySize int64
+	// MaxNotesLength is the longest note, in characters, the add form
+	// accepts. Defaults to [DefaultMaxNotesLength] when not configured.
+	MaxNotesLength int
+	// MaxClientSideIDs is the largest number of items the sync downloads in
+	// one request, the server's [Storage.MaxClientSideIDs]. Defaults to
+	// [DefaultMaxClientSideIDs] when not configured.
+	MaxClientSideIDs int
+	// MaxBatchEntries is the largest number of updates a bulk move sends in
+	// one request, the server's [App.MaxBatchEntries]. Defaults to
+	// [DefaultMaxBatchEntries] when not configured.
+	MaxBatchEntries int
+	// ClientIDPrefix is the device name prepended to generated client-side
+	// IDs. Empty by default.
+	ClientIDPrefix string
+	// SyncStaleAfter is the age after which the last successful sync is
+	// shown as stale. Defaults to [DefaultSyncStaleAfter] when not configured.
+	SyncStaleAfter time.Duration
+	// StatesCacheTTL is how long the server item states are reused between
+	// syncs. Zero, the default, disables the cache.
+	StatesCacheTTL time.Duration
+	// Clipboard is the clipboard backend mode. Defaults to
+	// [clipboard.ModeAuto] when not configured.
+	Clipboard string
+	// CopyGenerated copies a generated password to the clipboard as well as
+	// into the form. Disabled by default.
+	CopyGenerated bool
+	// DetectDuplicates offers to merge entries with identical content after
+	// a manual sync. Disabled by default.
+	DetectDuplicates bool
+	// SyncOnChange syncs right after every successful create, update or
+	// delete. Disabled by default.
+	SyncOnChange bool
+	// LoginTimeout limits the login handshake with the server. Defaults to
+	// [DefaultLoginTimeout]; zero means no limit.
+	LoginTimeout time.Duration
+	// LoginRetries is how many times a transiently failed login request is
+	// repeated. Defaults to [DefaultLoginRetries].
+	LoginRetries int
+	// SyncBreakerThreshold is the number of syncs in a row failing on the
+	// network that open the sync breaker. Defaults to
+	// [DefaultSyncBreakerThreshold]; zero disables the breaker.
+	SyncBreakerThreshold int
+	// SyncBreakerCooldown is how long an open sync breaker skips syncs.
+	// Defaults to [DefaultSyncBreakerCooldown].
+	SyncBreakerCooldown time.Duration
+	// PingInterval is how often the TUI checks that the server is
+	// reachable. Defaults to [DefaultPingInterval]; zero disables the check.
+	PingInterval time.Duration
+	// IdleLogout is how long the TUI waits without a key press before it
+	// logs out. Defaults to [DefaultIdleLogout]; zero disables the logout.
+	IdleLogout time.Duration
+	// NonceAudit enables the in-memory nonce-reuse check of the crypto
+	// layer. Disabled by default.
+	NonceAudit bool
+	// DebugHTTP logs every request to the server without bodies or
+	// credentials. Disabled by default.
+	DebugHTTP bool
+	// Reencrypt migrates entries stored in an older encryption format after
+	// the initial sync of an online login. Disabled by default.
+	Reencrypt bool
+	// Offline disables all server communication and vault mutations; login
+	// uses the credentials cached by a previous online login.
+	Offline bool
+	// DefaultDataType is preselected when a new entry is added. Zero keeps
+	// the first type selected.
+	DefaultDataType models.DataType
+	// EnabledDataTypes are the types that can be added, in the order they
+	// are offered. Defaults to [AllDataTypes] when not configured.
+	EnabledDataTypes []models.DataType
+	// DefaultFolder pre-fills the folder of a new entry. Empty by default.
+	DefaultFolder string
+	// SyncMode selects which sync plan buckets are executed. Defaults to
+	// [models.SyncModeBidirectional] when not configured.
+	SyncMode models.SyncMode
+	// SyncConflicts selects how version conflicts found by a sync are
+	// handled. Defaults to [models.ConflictServerWins] when not configured.
+	SyncConflicts models.ConflictPolicy
+	// SyncEvents is the target of the JSON-lines sync event stream:
+	// "stderr" or a file path. Empty disables the stream.
+	SyncEvents string
+	// Theme is the name of the TUI color theme. Defaults to [ThemeDefault]
+	// when not configured.
+	Theme string
+	// ListColumns are the vault list columns in display order. Defaults to
+	// [DefaultListColumns] when not configured.
+	ListColumns []string
+	// MaskNames starts the vault list with partially masked entry names.
+	// Disabled by default.
+	MaskNames bool
+	// FolderTree starts the vault list grouped into a folder tree. Disabled
+	// by default.
+	FolderTree bool
+	// ListJSON prints the vault list as JSON and exits instead of starting
+	// the TUI.
+	ListJSON bool
+	// ListSecrets includes secret fields in the ListJSON output.
+	ListSecrets bool
+	// Insecure allows a plain http:// server address. Disabled by default.
+	Insecure bool
+}
+
+// dataTypeNames maps the configurable names of data types to their values.
+var dataTypeNames = map[string]models.DataType{
+	"login":  models.LoginPassword,
+	"text":   models.Text,
+	"binary": models.Binary,
+	"card":   models.BankCard,
+}
+
+// AllDataTypes returns every data type in the order the add flow offers
+// them.
+func AllDataTypes() []models.DataType {
+	return []models.DataType{models.LoginPassword, models.Text, models.Binary, models.BankCard}
+}
+
+// ParseDataTypes parses a comma-separated list of data type names ("login",
+// "text", "binary", "card"). Names are case-insensitive and blank entries are
+// ignored; an empty list yields [AllDataTypes]. The types are returned in the
+// order of [AllDataTypes]. Unknown names are an error.
+func ParseDataTypes(s string) ([]models.DataType, error) {
+	enabled := make(map[models.DataType]bool)
+	for _, part := range strings.Split(s, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		dataType, ok := dataTypeNames[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown data type %q", ErrInvalidAppConfigs, part)
+		}
+		enabled[dataType] = true
+	}
+
+	if len(enabled) == 0 {
+		return AllDataTypes(), nil
+	}
+	var types []models.DataType
+	for _, dataType := range AllDataTypes() {
+		if enabled[dataType] {
+			types = append(types, dataType)
+		}
+	}
+	return types, nil
+}
+
+// DataTypeName returns the configurable name of dataType ("login", "text",
+// "binary" or "card"), or an empty string for an unknown type.
+func DataTypeName(dataType models.DataType) string {
+	for name, t := range dataTypeNames {
+		if t == dataType {
+			return name
+		}
+	}
+	return ""
+}
+
+// Built-in client color themes selectable with [App.Theme].
+const (
+	ThemeDefault      = "default"
+	ThemeHighContrast = "high-contrast"
+	// ThemeMonochrome uses text attributes only, for terminals with limited
+	// color support.
+	ThemeMonochrome = "monochrome"
+)
+
+// isValidTheme reports whether name is one of the built-in themes.
+func isValidTheme(name string) bool {
+	switch name {
+	case ThemeDefault, ThemeHighContrast, ThemeMonochrome:
+		return true
+	}
+	return false
+}
+
+// Columns of the TUI vault list selectable with [App.ListColumns].
+const (
+	ListColumnName   = "name"
+	ListColumnType   = "type"
+	ListColumnFolder = "folder"
+)
+
+// DefaultListColumns returns the vault list columns used when none are
+// configured.
+func DefaultListColumns() []string {
+	return []string{ListColumnName, ListColumnType, ListColumnFolder}
+}
+
+// ParseListColumns parses a comma-separated list of vault list columns.
+// Names are case-insensitive and blank entries are ignored; an empty list
+// yields [DefaultListColumns]. Unknown and repeated names are an error.
+func ParseListColumns(s string) ([]string, error) {
+	var columns []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		switch name {
+		case ListColumnName, ListColumnType, ListColumnFolder:
+		default:
+			return nil, fmt.Errorf("%w: unknown list column %q", ErrInvalidAppConfigs, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: list column %q repeated", ErrInvalidAppConfigs, name)
+		}
+		seen[name] = true
+		columns = append(columns, name)
+	}
+
+	if len(columns) == 0 {
+		return DefaultListColumns(), nil
+	}
+	return columns, nil
+}
+
+// DefaultMaxBinarySize is the Binary attachment limit used by the client when
+// none is configured.
+const DefaultMaxBinarySize int64 = 10 * 1024 * 1024
+
+// DefaultSyncStaleAfter is the last-sync age after which the client highlights
+// the sync time as stale when no threshold is configured.
+const DefaultSyncStaleAfter = time.Hour
+
+// DefaultLoginTimeout is the limit of the client's login handshake used when
+// none is configured.
+const DefaultLoginTimeout = 30 * time.Second
+
+// DefaultLoginRetries is how many times the client repeats a transiently
+// failed login request when nothing is configured.
+const DefaultLoginRetries = 1
+
+// Defaults of the client's sync breaker, see [App.SyncBreakerThreshold] and
+// [App.SyncBreakerCooldown].
+const (
+	DefaultSyncBreakerThreshold = 3
+	DefaultSyncBreakerCooldown  = 30 * time.Second
+)
+
+// DefaultPingInterval is how often the TUI checks the connection to the
+// server when nothing is configured, see [App.PingInterval].
+const DefaultPingInterval = 30 * time.Second
+
+// DefaultIdleLogout is how long the TUI stays logged in without a key press
+// when nothing is configured, see [App.IdleLogout].
+const DefaultIdleLogout = 15 * time.Minute
+
+// DefaultClientLogFormat is the log format used by the client when none is
+// configured. Console output is easier to read when tailing a local log.
+const DefaultClientLogFormat = "console"
+
+// clientDataDirName is the directory of the client's data inside the user's
+// configuration directory.
+const clientDataDirName = "go-pass-keeper"
+
+// defaultBackupDirName is the directory of the local database snapshots
+// inside the data directory when none is configured.
+const defaultBackupDirName = "backups"
+
+// DefaultClientDataDir returns the directory of the client's local databases
+// used when none is configured: "go-pass-keeper" inside the per-user
+// configuration directory of the OS (see [os.UserConfigDir]), or in the
+// working directory when the OS reports none.
+func DefaultClientDataDir() string {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return clientDataDirName
+	}
+	return filepath.Join(base, clientDataDirName)
 }
 
 // ClientAdapter holds network settings used by the client transport layer.
@@ -28,6 +312,8 @@ type ClientAdapter struct {
 // ClientDB contains local database connection settings for the client.
 type ClientDB struct {
 	// DSN is the SQLite/PostgreSQL connection string used by the client.
+	// When set, every account shares this database; when empty, each
+	// account login gets its own database in [ClientStorage.DataDir].
 	DSN string
 }
 
@@ -35,6 +321,18 @@ type ClientDB struct {
 type ClientStorage struct {
 	// DB holds local database settings.
 	DB ClientDB
+	// DataDir is the directory holding one local database per account
+	// login. Used only when DB.DSN is empty.
+	DataDir string
+	// ReadOnly opens the local database in query-only mode. Set in offline
+	// mode so that browsing a vault snapshot cannot modify it.
+	ReadOnly bool
+	// BackupDir is the directory of the local database snapshots taken
+	// before each sync. Defaults to a "backups" directory in DataDir.
+	BackupDir string
+	// BackupCount is how many snapshots of each database are kept. Zero
+	// disables the snapshots.
+	BackupCount int
 }
 
 // ClientWorkers contains client background worker settings.
@@ -54,6 +352,9 @@ type ClientConfig struct {
 	Storage ClientStorage
 	// Workers contains background job settings.
 	Workers ClientWorkers
+	// PrintConfig makes the client print this configuration with [Print]
+	// and exit.
+	PrintConfig bool
 }
 
 // GetClientConfig builds and validates a client-specific config view from the
@@ -69,7 +370,40 @@ func GetClientConfig() (*ClientConfig, error) {
 
 	clientCfg := &ClientConfig{
 		App: ClientApp{
-			HashKey: cfg.App.HashKey,
+			HashKey:              cfg.App.HashKey,
+			LogLevel:             cfg.App.LogLevel,
+			LogFormat:            cfg.App.LogFormat,
+			MaxBinarySize:        cfg.App.MaxBinarySize,
+			MaxNotesLength:       cfg.App.NotesLengthLimit(),
+			MaxClientSideIDs:     cfg.Storage.ClientSideIDsLimit(),
+			MaxBatchEntries:      cfg.App.BatchEntriesLimit(),
+			ClientIDPrefix:       cfg.App.ClientIDPrefix,
+			SyncStaleAfter:       cfg.App.SyncStaleAfter,
+			StatesCacheTTL:       max(cfg.App.StatesCacheTTL, 0),
+			Clipboard:            cfg.App.Clipboard,
+			CopyGenerated:        cfg.App.CopyGenerated,
+			DetectDuplicates:     cfg.App.DetectDuplicates,
+			SyncOnChange:         cfg.App.SyncOnChange,
+			LoginTimeout:         cfg.App.LoginTimeout,
+			LoginRetries:         cfg.App.LoginRetries,
+			SyncBreakerThreshold: cfg.App.SyncBreakerThreshold,
+			SyncBreakerCooldown:  cfg.App.SyncBreakerCooldown,
+			PingInterval:         cfg.App.PingInterval,
+			IdleLogout:           cfg.App.IdleLogout,
+			NonceAudit:           cfg.App.NonceAudit,
+			DebugHTTP:            cfg.App.DebugHTTP,
+			Reencrypt:            cfg.App.Reencrypt,
+			Offline:              cfg.App.Offline,
+			DefaultFolder:        strings.TrimSpace(cfg.App.DefaultFolder),
+			SyncMode:             models.SyncMode(strings.ToLower(strings.TrimSpace(cfg.App.SyncMode))),
+			SyncConflicts:        models.ConflictPolicy(strings.ToLower(strings.TrimSpace(cfg.App.SyncConflicts))),
+			SyncEvents:           strings.TrimSpace(cfg.App.SyncEvents),
+			Theme:                strings.ToLower(strings.TrimSpace(cfg.App.Theme)),
+			MaskNames:            cfg.App.MaskNames,
+			FolderTree:           cfg.App.FolderTree,
+			ListJSON:             cfg.App.ListJSON,
+			ListSecrets:          cfg.App.ListSecrets,
+			Insecure:             cfg.App.Insecure,
 		},
 		Adapter: ClientAdapter{
 			HTTPAddress:    cfg.Adapter.HTTPAddress,
@@ -80,8 +414,93 @@ func GetClientConfig() (*ClientConfig, error) {
 			DB: ClientDB{
 				DSN: cfg.Storage.DB.DSN,
 			},
+			DataDir:     strings.TrimSpace(cfg.Storage.Files.DataDir),
+			ReadOnly:    cfg.App.Offline,
+			BackupDir:   strings.TrimSpace(cfg.Storage.Files.BackupDir),
+			BackupCount: cfg.Storage.Files.BackupCount,
 		},
-		Workers: ClientWorkers{SyncInterval: cfg.Workers.SyncInterval},
+		Workers:     ClientWorkers{SyncInterval: cfg.Workers.SyncInterval},
+		PrintConfig: cfg.PrintConfig,
+	}
+
+	if clientCfg.App.LogFormat == "" {
+		clientCfg.App.LogFormat = DefaultClientLogFormat
+	}
+	if clientCfg.Storage.DataDir == "" {
+		clientCfg.Storage.DataDir = DefaultClientDataDir()
+	}
+	if clientCfg.Storage.BackupDir == "" {
+		clientCfg.Storage.BackupDir = filepath.Join(clientCfg.Storage.DataDir, defaultBackupDirName)
+	}
+	if clientCfg.App.MaxBinarySize == 0 {
+		clientCfg.App.MaxBinarySize = DefaultMaxBinarySize
+	}
+	if clientCfg.App.SyncStaleAfter == 0 {
+		clientCfg.App.SyncStaleAfter = DefaultSyncStaleAfter
+	}
+	switch {
+	case clientCfg.App.LoginTimeout == 0:
+		clientCfg.App.LoginTimeout = DefaultLoginTimeout
+	case clientCfg.App.LoginTimeout < 0:
+		clientCfg.App.LoginTimeout = 0
+	}
+	switch {
+	case clientCfg.App.LoginRetries == 0:
+		clientCfg.App.LoginRetries = DefaultLoginRetries
+	case clientCfg.App.LoginRetries < 0:
+		clientCfg.App.LoginRetries = 0
+	}
+	switch {
+	case clientCfg.App.SyncBreakerThreshold == 0:
+		clientCfg.App.SyncBreakerThreshold = DefaultSyncBreakerThreshold
+	case clientCfg.App.SyncBreakerThreshold < 0:
+		clientCfg.App.SyncBreakerThreshold = 0
+	}
+	if clientCfg.App.SyncBreakerCooldown <= 0 {
+		clientCfg.App.SyncBreakerCooldown = DefaultSyncBreakerCooldown
+	}
+	switch {
+	case clientCfg.App.PingInterval == 0:
+		clientCfg.App.PingInterval = DefaultPingInterval
+	case clientCfg.App.PingInterval < 0:
+		clientCfg.App.PingInterval = 0
+	}
+	switch {
+	case clientCfg.App.IdleLogout == 0:
+		clientCfg.App.IdleLogout = DefaultIdleLogout
+	case clientCfg.App.IdleLogout < 0:
+		clientCfg.App.IdleLogout = 0
+	}
+	if clientCfg.App.Clipboard == "" {
+		clientCfg.App.Clipboard = clipboard.ModeAuto
+	}
+	if clientCfg.App.SyncMode == "" {
+		clientCfg.App.SyncMode = models.SyncModeBidirectional
+	}
+	if clientCfg.App.SyncConflicts == "" {
+		clientCfg.App.SyncConflicts = models.ConflictServerWins
+	}
+	if clientCfg.App.Theme == "" {
+		clientCfg.App.Theme = ThemeDefault
+	}
+
+	if name := strings.ToLower(strings.TrimSpace(cfg.App.DefaultDataType)); name != "" {
+		dataType, ok := dataTypeNames[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown default data type %q", ErrInvalidAppConfigs, cfg.App.DefaultDataType)
+		}
+		clientCfg.App.DefaultDataType = dataType
+	}
+
+	if clientCfg.App.EnabledDataTypes, err = ParseDataTypes(cfg.App.EnabledDataTypes); err != nil {
+		return nil, err
+	}
+	if clientCfg.App.DefaultDataType != 0 && !slices.Contains(clientCfg.App.EnabledDataTypes, clientCfg.App.DefaultDataType) {
+		return nil, fmt.Errorf("%w: default data type %q is not enabled", ErrInvalidAppConfigs, cfg.App.DefaultDataType)
+	}
+
+	if clientCfg.App.ListColumns, err = ParseListColumns(cfg.App.ListColumns); err != nil {
+		return nil, err
 	}
 
 	return clientCfg, clientCfg.validate()
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty means default", in: "", want: DefaultListColumns()},
+		{name: "blank entries", in: " , ", want: DefaultListColumns()},
+		{name: "order kept, case ignored", in: "Folder, name", want: []string{ListColumnFolder, ListColumnName}},
+		{name: "unknown column", in: "name,size", wantErr: true},
+		{name: "repeated column", in: "name,type,NAME", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseListColumns(tt.in)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAppConfigs)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseDataTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []models.DataType
+		wantErr bool
+	}{
+		{name: "empty enables all", in: "", want: AllDataTypes()},
+		{name: "blank entries", in: " , ", want: AllDataTypes()},
+		{name: "subset in add flow order", in: "Text, login", want: []models.DataType{models.LoginPassword, models.Text}},
+		{name: "repeats ignored", in: "card,card", want: []models.DataType{models.BankCard}},
+		{name: "unknown type", in: "login,note", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDataTypes(tt.in)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAppConfigs)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsValidClientIDPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		want   bool
+	}{
+		{name: "empty", prefix: "", want: true},
+		{name: "device name", prefix: "work_laptop.2", want: true},
+		{name: "longest", prefix: strings.Repeat("a", MaxClientIDPrefixLength), want: true},
+		{name: "too long", prefix: strings.Repeat("a", MaxClientIDPrefixLength+1), want: false},
+		{name: "dash", prefix: "my-laptop", want: false},
+		{name: "space", prefix: "my laptop", want: false},
+		{name: "non-ascii", prefix: "ноутбук", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isValidClientIDPrefix(tt.prefix))
+		})
+	}
+}
+
+func TestClientApp_validate(t *testing.T) {
+	valid := func() ClientApp {
+		return ClientApp{
+			HashKey:       "hash-key",
+			Clipboard:     "auto",
+			SyncMode:      models.SyncModeBidirectional,
+			SyncConflicts: models.ConflictServerWins,
+			Theme:         ThemeDefault,
+		}
+	}
+	tests := []struct {
+		name    string
+		modify  func(a *ClientApp)
+		wantMsg string
+	}{
+		{name: "valid", modify: func(*ClientApp) {}},
+		{name: "no hash key", modify: func(a *ClientApp) { a.HashKey = "" }, wantMsg: "hash key is not set (APP_HASH_KEY or -hash-key)"},
+		{name: "negative max binary size", modify: func(a *ClientApp) { a.MaxBinarySize = -1 }, wantMsg: "max binary size -1 is negative"},
+		{name: "negative max notes length", modify: func(a *ClientApp) { a.MaxNotesLength = -2 }, wantMsg: "max notes length -2 is negative"},
+		{name: "client ID prefix too long", modify: func(a *ClientApp) { a.ClientIDPrefix = strings.Repeat("a", MaxClientIDPrefixLength+1) }, wantMsg: `client ID prefix "` + strings.Repeat("a", MaxClientIDPrefixLength+1) + `" must be at most 27`},
+		{name: "malformed client ID prefix", modify: func(a *ClientApp) { a.ClientIDPrefix = "my-laptop" }, wantMsg: `client ID prefix "my-laptop"`},
+		{name: "negative sync stale after", modify: func(a *ClientApp) { a.SyncStaleAfter = -1 }, wantMsg: "sync stale after -1ns is negative"},
+		{name: "unknown clipboard", modify: func(a *ClientApp) { a.Clipboard = "x11" }, wantMsg: `clipboard "x11"`},
+		{name: "unknown sync mode", modify: func(a *ClientApp) { a.SyncMode = "push" }, wantMsg: `sync mode "push"`},
+		{name: "unknown sync conflicts", modify: func(a *ClientApp) { a.SyncConflicts = "client-wins" }, wantMsg: `sync conflicts "client-wins"`},
+		{name: "unknown theme", modify: func(a *ClientApp) { a.Theme = "dark" }, wantMsg: `theme "dark"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid()
+			tt.modify(&a)
+
+			err := a.validate()
+			if tt.wantMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidAppConfigs)
+			assert.ErrorContains(t, err, tt.wantMsg)
+		})
+	}
+}
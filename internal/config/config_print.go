@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Print writes cfg, a merged configuration such as [StructuredConfig] or
+// [ClientConfig], to w as indented JSON keyed by the Go field names.
+// Durations are written in their string form ("30s"). Secret values are
+// redacted with the loggers' rules (see [logger.RedactJSON]), so the output
+// can be shared when debugging which source a setting came from.
+func Print(w io.Writer, cfg any) error {
+	data, err := json.Marshal(printable(reflect.ValueOf(cfg)))
+	if err != nil {
+		return fmt.Errorf("error encoding config: %w", err)
+	}
+	if data, err = logger.RedactJSON(data); err != nil {
+		return fmt.Errorf("error redacting config: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err = json.Indent(&out, data, "", "  "); err != nil {
+		return fmt.Errorf("error encoding config: %w", err)
+	}
+	out.WriteByte('\n')
+	_, err = out.WriteTo(w)
+	return err
+}
+
+// printable converts v into values encoding/json renders readably: structs
+// become maps of their exported fields and durations become strings.
+func printable(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Struct:
+		fields := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() {
+				fields[field.Name] = printable(v.Field(i))
+			}
+		}
+		return fields
+	}
+	return v.Interface()
+}
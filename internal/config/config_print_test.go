@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint_EffectiveConfig(t *testing.T) {
+	path := writeTempJSONConfig(t, map[string]any{
+		"app": map[string]any{
+			"token_sign_key": "file-sign-key",
+			"token_issuer":   "file-issuer",
+			"token_duration": "2h",
+		},
+		"storage": map[string]any{
+			"db": map[string]any{"dsn": "postgres://user:file-pass@db/vault"},
+		},
+	})
+	setEnvVars(t, map[string]string{
+		"CONFIG":                  path,
+		"CONFIG_PRINT":            "true",
+		"APP_TOKEN_SIGN_KEY":      "env-sign-key",
+		"APP_TOKEN_ISSUER":        "env-issuer",
+		"SERVER_ADDRESS":          "0.0.0.0:8080",
+		"STORAGE_DB_DATABASE_URI": "postgres://user:env-pass@db/vault",
+	})
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	oldArgs := os.Args
+	os.Args = []string{"cmd"}
+	t.Cleanup(func() { os.Args = oldArgs })
+
+	cfg, err := GetServerConfig()
+	require.NoError(t, err)
+	require.True(t, cfg.PrintConfig)
+
+	var out bytes.Buffer
+	require.NoError(t, Print(&out, cfg))
+
+	var printed struct {
+		App struct {
+			TokenSignKey  string
+			TokenIssuer   string
+			TokenDuration string
+		}
+		Storage struct {
+			DB struct{ DSN string }
+		}
+		Server struct{ HTTPAddress string }
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &printed))
+
+	// Environment variables win over the file; the file fills the rest.
+	assert.Equal(t, "env-issuer", printed.App.TokenIssuer)
+	assert.Equal(t, "2h0m0s", printed.App.TokenDuration)
+	assert.Equal(t, "0.0.0.0:8080", printed.Server.HTTPAddress)
+
+	assert.Equal(t, logger.RedactedValue, printed.App.TokenSignKey)
+	assert.Equal(t, logger.RedactedValue, printed.Storage.DB.DSN)
+	for _, secret := range []string{"env-sign-key", "file-sign-key", "env-pass", "file-pass"} {
+		assert.NotContains(t, out.String(), secret)
+	}
+}
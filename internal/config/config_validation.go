@@ -3,16 +3,87 @@
 
 package config
 
-import "strings"
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clipboard"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+)
 
 // validate checks that the final merged [StructuredConfig] satisfies all
 // application invariants before it is used at startup.
 //
-// Currently a no-op placeholder; validation rules will be added as the
-// application matures (e.g. requiring non-empty DSN, token sign key, etc.).
+// The HTTP server timeouts are checked (none may be negative, and an explicit
+// write timeout must be at least [MinServerWriteTimeout]), as are the gzip
+// level, the access log level, the API base path and the listen addresses.
 //
 // Returns nil if the configuration is valid, or a descriptive error otherwise.
 func (cfg *StructuredConfig) validate() error {
+	s := cfg.Server
+	if s.RequestTimeout < 0 || s.ReadTimeout < 0 || s.ReadHeaderTimeout < 0 || s.WriteTimeout < 0 || s.IdleTimeout < 0 {
+		return fmt.Errorf("%w: negative timeout", ErrInvalidServerConfigs)
+	}
+	if s.WriteTimeout != 0 && s.WriteTimeout < MinServerWriteTimeout {
+		return fmt.Errorf("%w: write timeout %s is shorter than %s", ErrInvalidServerConfigs, s.WriteTimeout, MinServerWriteTimeout)
+	}
+	if s.GzipLevel < 0 || s.GzipLevel > 9 {
+		return fmt.Errorf("%w: gzip level %d must be 0 (default) or between 1 and 9", ErrInvalidServerConfigs, s.GzipLevel)
+	}
+	if _, err := logger.ParseLevel(s.AccessLogLevel); err != nil {
+		return fmt.Errorf("%w: access log level: %w", ErrInvalidServerConfigs, err)
+	}
+	if strings.ContainsAny(s.BasePath, "?#% \t") {
+		return fmt.Errorf("%w: base path %q must be a plain URL path", ErrInvalidServerConfigs, s.BasePath)
+	}
+	for _, address := range []string{s.HTTPAddress, s.GRPCAddress, s.AdminAddress} {
+		if err := validateListenAddress(address); err != nil {
+			return fmt.Errorf("%w: listen address %q: %w", ErrInvalidServerConfigs, address, err)
+		}
+	}
+	if s.AdminAddress != "" && (s.AdminAddress == s.HTTPAddress || s.AdminAddress == s.GRPCAddress) {
+		return fmt.Errorf("%w: admin address %q is already used by another listener", ErrInvalidServerConfigs, s.AdminAddress)
+	}
+	return nil
+}
+
+// validateListenAddress checks that a non-empty address is "host:port" with
+// a port in range. The host may be empty to listen on all interfaces.
+func validateListenAddress(address string) error {
+	if address == "" {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+// validateServer checks that the settings the server cannot start without
+// are set: the database DSN, at least one listen address and the token
+// signing key. All missing settings are reported at once, each named by its
+// environment variable and flag.
+func (cfg *StructuredConfig) validateServer() error {
+	var missing []string
+	if strings.TrimSpace(cfg.Storage.DB.DSN) == "" {
+		missing = append(missing, "database DSN (STORAGE_DB_DATABASE_URI or -d)")
+	}
+	if cfg.Server.HTTPAddress == "" && cfg.Server.GRPCAddress == "" {
+		missing = append(missing, "listen address (SERVER_ADDRESS or -a, SERVER_GRPC_ADDRESS or -grpc-address)")
+	}
+	if cfg.App.TokenSignKey == "" {
+		missing = append(missing, "token signing key (APP_TOKEN_SIGN_KEY, APP_TOKEN_SIGN_KEY_FILE, -token-sign-key or -token-sign-key-file)")
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingRequiredConfig, strings.Join(missing, "; "))
+	}
 	return nil
 }
 
@@ -29,9 +100,39 @@ func (cfg *ClientConfig) validate() error {
 		return ErrInvalidWorkerConfigs
 	}
 
-	if cfg.App.HashKey == "" {
-		return ErrInvalidAppConfigs
-	}
+	return cfg.App.validate()
+}
 
+// validate checks the application settings of the client one by one and
+// reports the first invalid one with its value, named by its environment
+// variable and flag.
+func (a *ClientApp) validate() error {
+	if a.HashKey == "" {
+		return fmt.Errorf("%w: hash key is not set (APP_HASH_KEY or -hash-key)", ErrInvalidAppConfigs)
+	}
+	if a.MaxBinarySize < 0 {
+		return fmt.Errorf("%w: max binary size %d is negative (APP_MAX_BINARY_SIZE or -max-binary-size)", ErrInvalidAppConfigs, a.MaxBinarySize)
+	}
+	if a.MaxNotesLength < 0 {
+		return fmt.Errorf("%w: max notes length %d is negative (APP_MAX_NOTES_LENGTH or -max-notes-length)", ErrInvalidAppConfigs, a.MaxNotesLength)
+	}
+	if !isValidClientIDPrefix(a.ClientIDPrefix) {
+		return fmt.Errorf("%w: client ID prefix %q must be at most %d letters, digits, '_' or '.' (APP_CLIENT_ID_PREFIX or -client-id-prefix)", ErrInvalidAppConfigs, a.ClientIDPrefix, MaxClientIDPrefixLength)
+	}
+	if a.SyncStaleAfter < 0 {
+		return fmt.Errorf("%w: sync stale after %s is negative (APP_SYNC_STALE_AFTER or -sync-stale-after)", ErrInvalidAppConfigs, a.SyncStaleAfter)
+	}
+	if !clipboard.IsValidMode(a.Clipboard) {
+		return fmt.Errorf("%w: clipboard %q is not auto, system, osc52 or none (APP_CLIPBOARD or -clipboard)", ErrInvalidAppConfigs, a.Clipboard)
+	}
+	if !a.SyncMode.IsValid() {
+		return fmt.Errorf("%w: sync mode %q is not bidirectional, push-only or pull-only (APP_SYNC_MODE or -sync-mode)", ErrInvalidAppConfigs, a.SyncMode)
+	}
+	if !a.SyncConflicts.IsValid() {
+		return fmt.Errorf("%w: sync conflicts %q is not server-wins or manual (APP_SYNC_CONFLICTS or -sync-conflicts)", ErrInvalidAppConfigs, a.SyncConflicts)
+	}
+	if !isValidTheme(a.Theme) {
+		return fmt.Errorf("%w: theme %q is not default, high-contrast or monochrome (APP_THEME or -theme)", ErrInvalidAppConfigs, a.Theme)
+	}
 	return nil
 }
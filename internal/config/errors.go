@@ -5,8 +5,9 @@ package config
 
 import "errors"
 
-// Validation errors returned by [ClientConfig.validate] when required
-// configuration groups are incomplete or invalid.
+// Validation errors returned by [ClientConfig.validate] and
+// [StructuredConfig.validate] when required configuration groups are
+// incomplete or invalid.
 var (
 	// ErrInvalidAdapterConfigs indicates invalid client adapter settings
 	// (for example, missing HTTP address or request timeout).
@@ -20,4 +21,11 @@ var (
 	// ErrInvalidWorkerConfigs indicates invalid background worker settings
 	// (for example, zero sync interval).
 	ErrInvalidWorkerConfigs = errors.New("invalid worker configuration")
+	// ErrInvalidServerConfigs indicates invalid server settings (for example,
+	// a negative timeout or a write timeout too short for large downloads).
+	ErrInvalidServerConfigs = errors.New("invalid server configuration")
+	// ErrMissingRequiredConfig indicates that a setting the server cannot
+	// start without is not set by any source. The error message names the
+	// environment variable and the flag of every missing setting.
+	ErrMissingRequiredConfig = errors.New("missing required configuration")
 )
@@ -25,57 +25,276 @@ type NetAddress struct {
 //
 //	-a server address in format [host]:[port]
 //	-grpc-address grpc server address in format [host]:[port]
+//	-admin-address health and admin endpoints address in format [host]:[port]
 //	-f file storage path
+//	-data-dir directory of the client's per-account local databases
+//	-backup-dir directory of the local database snapshots taken before each sync
+//	-backup-count local database snapshots kept per database (0 disables them)
 //	-d database DSN
 //	-crypto-key private key path
 //	-c/-config json file path with configs
+//	-config-print print the effective configuration (secrets redacted) as JSON and exit
+//	-migrate-dry-run print the pending server migrations and exit without applying them
 //	-password-hash-key password hash key
 //	-token-sign-key token signing key
+//	-token-sign-key-file file holding the token signing key
 //	-token-issuer token issuer name
 //	-token-duration token duration (e.g., "1h", "30m")
 //	-request-timeout request timeout (e.g., "30s", "1m")
+//	-read-timeout HTTP server read timeout
+//	-read-header-timeout HTTP server read header timeout
+//	-write-timeout HTTP server write timeout
+//	-idle-timeout HTTP server keep-alive idle timeout
+//	-admin-token shared secret for admin endpoints
+//	-hard-delete remove deleted items immediately instead of soft-deleting them
+//	-version-history number of previous versions kept per vault item (0 disables)
+//	-max-client-side-ids maximum number of client-side IDs per download or states request
+//	-max-batch-entries maximum number of entries per update or delete request
+//	-sync-lock-timeout wait for a concurrent sync of the same user (negative disables the lock)
+//	-skip-schema-check skip the startup check of the ciphers table columns
+//	-access-log-level level of per-request access-log lines (debug, info, warn, error)
+//	-base-path URL path prefix of the API routes (default /api)
+//	-min-salt-length minimum encryption salt length in bytes accepted at registration
+//	-max-in-flight maximum number of requests processed at once (0 disables the limit)
+//	-gzip-level gzip level of compressed responses, 1 (fastest) to 9 (smallest)
+//	-gzip-min-size smallest response body in bytes that is compressed (negative compresses all)
+//	-state-ttl time after which unused in-memory per-user state is evicted
+//	-state-cleanup-interval how often stale in-memory state is evicted (negative disables)
+//	-login-max-attempts failed logins to one account that lock it (0 disables)
+//	-login-attempt-window period failed logins are counted in
+//	-login-lockout how long an account stays locked
 //	-hash-key security hash key
+//	-log-level minimum log level (debug, info, warn, error)
+//	-log-format log output format (json, console)
+//	-max-binary-size maximum binary attachment size in bytes
+//	-max-notes-length maximum notes length in characters
+//	-client-id-prefix device name prepended to new client-side IDs
+//	-sync-stale-after age after which the last sync is shown as stale
+//	-states-cache-ttl how long server item states are reused between syncs (0 disables)
+//	-clipboard clipboard backend (auto, system, osc52, none)
+//	-copy-generated also copy a generated password to the clipboard
+//	-detect-duplicates offer to merge duplicate entries after a manual sync
+//	-sync-on-change sync right after every create, update or delete
+//	-login-timeout limit of the login handshake with the server (negative disables it)
+//	-login-retries retries of a transiently failed login request (negative disables them)
+//	-sync-breaker-threshold syncs in a row failing on the network that pause syncing (negative disables)
+//	-sync-breaker-cooldown how long syncing is paused before the server is probed again
+//	-ping-interval how often the TUI checks the connection to the server (negative disables)
+//	-idle-logout how long the TUI stays logged in without a key press (negative disables)
+//	-nonce-audit record encryption nonces and refuse to reuse one (diagnostics)
+//	-debug-http log every request to the server without bodies (diagnostics)
+//	-reencrypt re-encrypt entries stored in an older format after login
+//	-offline browse the local vault read-only without contacting the server
+//	-default-data-type type preselected when adding an entry (login, text, binary, card)
+//	-enabled-data-types types that can be added, comma-separated (login, text, binary, card)
+//	-default-folder folder pre-filled when adding an entry
+//	-sync-mode sync direction (bidirectional, push-only, pull-only)
+//	-sync-conflicts version conflict handling (server-wins, manual)
+//	-sync-events JSON-lines sync event stream target ("stderr" or a file path)
+//	-theme client color theme (default, high-contrast, monochrome)
+//	-list-columns vault list columns in display order (name, type, folder)
+//	-mask-names start the vault list with partially masked entry names
+//	-folder-tree start the vault list grouped into a folder tree
+//	-json print the vault list as JSON and exit (metadata only)
+//	-json-secrets include secret fields in the -json output
+//	-insecure allow a plain http:// server address (local development only)
 //	-v/version info about version number of client or server
 func ParseFlags() *StructuredConfig {
-	var serverAddress, grpcServerAddress NetAddress
+	var serverAddress, grpcServerAddress, adminAddress NetAddress
 	var fileStoragePath string
+	var dataDir string
+	var backupDir string
+	var backupCount int
 	var databaseDSN string
 	var cryptoKey string
 	var jsonConfigPath string
+	var printConfig bool
+	var migrateDryRun bool
 	var passwordHashKey string
 	var tokenSignKey string
+	var tokenSignKeyFile string
 	var tokenIssuer string
 	var tokenDuration time.Duration
 	var requestTimeout time.Duration
+	var readTimeout, readHeaderTimeout, writeTimeout, idleTimeout time.Duration
+	var adminToken string
+	var hardDelete bool
+	var versionHistory int
+	var maxClientSideIDs int
+	var syncLockTimeout time.Duration
+	var skipSchemaCheck bool
+	var accessLogLevel string
+	var basePath string
+	var minSaltLength int
+	var maxInFlight int
+	var gzipLevel, gzipMinSize int
+	var stateTTL, stateCleanupInterval time.Duration
+	var loginMaxAttempts int
+	var loginAttemptWindow, loginLockout time.Duration
 	var hashKey string
 	var version string
+	var logLevel string
+	var logFormat string
+	var maxBinarySize int64
+	var maxNotesLength int
+	var maxBatchEntries int
+	var clientIDPrefix string
+	var syncStaleAfter time.Duration
+	var statesCacheTTL time.Duration
+	var clipboardMode string
+	var copyGenerated bool
+	var detectDuplicates bool
+	var nonceAudit bool
+	var debugHTTP bool
+	var reencrypt bool
+	var offline bool
+	var defaultDataType string
+	var enabledDataTypes string
+	var defaultFolder string
+	var syncMode string
+	var syncConflicts string
+	var syncEvents string
+	var theme string
+	var listColumns string
+	var maskNames bool
+	var folderTree bool
+	var listJSON bool
+	var listSecrets bool
+	var insecure bool
+	var syncOnChange bool
+	var loginTimeout time.Duration
+	var loginRetries int
+	var syncBreakerThreshold int
+	var syncBreakerCooldown time.Duration
+	var pingInterval time.Duration
+	var idleLogout time.Duration
 
 	flag.Var(&serverAddress, "a", "Net address host:port")
 	flag.Var(&grpcServerAddress, "grpc-address", "Net grpc server address host:port")
+	flag.Var(&adminAddress, "admin-address", "Net address host:port of the health and admin endpoints")
 	flag.StringVar(&fileStoragePath, "f", "", "File storage path")
+	flag.StringVar(&dataDir, "data-dir", "", "Directory of the client's per-account local databases")
+	flag.StringVar(&backupDir, "backup-dir", "", "Directory of the local database snapshots taken before each sync (default <data-dir>/backups)")
+	flag.IntVar(&backupCount, "backup-count", 0, "Local database snapshots kept per database; 0 disables them")
 	flag.StringVar(&databaseDSN, "d", "", "Database DSN")
 	flag.StringVar(&cryptoKey, "crypto-key", "", "Private key path")
 	flag.StringVar(&jsonConfigPath, "c", "", "JSON config file path")
 	flag.StringVar(&jsonConfigPath, "config", "", "JSON config file path (alias)")
+	flag.BoolVar(&printConfig, "config-print", false, "Print the effective configuration (secrets redacted) as JSON and exit")
+	flag.BoolVar(&migrateDryRun, "migrate-dry-run", false, "Print the pending database migrations and exit without applying them")
 	flag.StringVar(&passwordHashKey, "password-hash-key", "", "Password hash key")
 	flag.StringVar(&tokenSignKey, "token-sign-key", "", "Token signing key")
+	flag.StringVar(&tokenSignKeyFile, "token-sign-key-file", "", "File holding the token signing key")
 	flag.StringVar(&tokenIssuer, "token-issuer", "", "Token issuer")
 	flag.DurationVar(&tokenDuration, "token-duration", 0, "Token duration (e.g., 1h, 30m)")
 	flag.DurationVar(&requestTimeout, "request-timeout", 0, "Request timeout (e.g., 30s, 1m)")
+	flag.DurationVar(&readTimeout, "read-timeout", 0, "HTTP server read timeout (e.g., 30s)")
+	flag.DurationVar(&readHeaderTimeout, "read-header-timeout", 0, "HTTP server read header timeout (e.g., 10s)")
+	flag.DurationVar(&writeTimeout, "write-timeout", 0, "HTTP server write timeout (e.g., 5m)")
+	flag.DurationVar(&idleTimeout, "idle-timeout", 0, "HTTP server keep-alive idle timeout (e.g., 2m)")
+	flag.StringVar(&adminToken, "admin-token", "", "Shared secret for admin endpoints")
+	flag.BoolVar(&hardDelete, "hard-delete", false, "Remove deleted items immediately instead of soft-deleting them")
+	flag.IntVar(&versionHistory, "version-history", 0, "Number of previous versions kept per vault item (0 disables)")
+	flag.IntVar(&maxClientSideIDs, "max-client-side-ids", 0, "Maximum number of client-side IDs per download or states request (default 1000)")
+	flag.DurationVar(&syncLockTimeout, "sync-lock-timeout", 0, "Wait for a concurrent sync of the same user before rejecting with 423 (default 5s, negative disables the lock)")
+	flag.BoolVar(&skipSchemaCheck, "skip-schema-check", false, "Skip the startup check of the ciphers table columns")
+	flag.StringVar(&accessLogLevel, "access-log-level", "", "Access log level (debug, info, warn, error)")
+	flag.StringVar(&basePath, "base-path", "", "URL path prefix of the API routes (default /api)")
+	flag.IntVar(&minSaltLength, "min-salt-length", 0, "Minimum encryption salt length in bytes accepted at registration (default 16)")
+	flag.IntVar(&maxInFlight, "max-in-flight", 0, "Maximum number of requests processed at once, 0 disables the limit")
+	flag.IntVar(&gzipLevel, "gzip-level", 0, "Gzip level of compressed responses, 1 (fastest) to 9 (smallest) (default 6)")
+	flag.IntVar(&gzipMinSize, "gzip-min-size", 0, "Smallest response body in bytes that is compressed, negative compresses all (default 512)")
+	flag.DurationVar(&stateTTL, "state-ttl", 0, "Time after which unused in-memory per-user state is evicted (default 1h)")
+	flag.DurationVar(&stateCleanupInterval, "state-cleanup-interval", 0, "How often stale in-memory state is evicted, negative disables (default 10m)")
+	flag.IntVar(&loginMaxAttempts, "login-max-attempts", 0, "Failed logins to one account within the attempt window that lock it, 0 disables the lockout")
+	flag.DurationVar(&loginAttemptWindow, "login-attempt-window", 0, "Period failed logins are counted in (default 15m)")
+	flag.DurationVar(&loginLockout, "login-lockout", 0, "How long an account stays locked after too many failed logins (default 15m)")
 	flag.StringVar(&hashKey, "hash-key", "", "Security hash key")
 	flag.StringVar(&version, "v", "", "App version number")
 	flag.StringVar(&version, "version", "", "App version number")
+	flag.StringVar(&logLevel, "log-level", "", "Log level (debug, info, warn, error)")
+	flag.StringVar(&logFormat, "log-format", "", "Log format (json, console)")
+	flag.Int64Var(&maxBinarySize, "max-binary-size", 0, "Maximum binary attachment size in bytes")
+	flag.IntVar(&maxNotesLength, "max-notes-length", 0, "Maximum notes length in characters")
+	flag.IntVar(&maxBatchEntries, "max-batch-entries", 0, "Maximum number of entries per update or delete request (default 500)")
+	flag.StringVar(&clientIDPrefix, "client-id-prefix", "", "Device name prepended to new client-side IDs (e.g., laptop)")
+	flag.DurationVar(&syncStaleAfter, "sync-stale-after", 0, "Age after which the last sync is shown as stale (e.g., 1h)")
+	flag.DurationVar(&statesCacheTTL, "states-cache-ttl", 0, "How long server item states are reused between syncs, 0 disables (e.g., 5s)")
+
+	flag.StringVar(&clipboardMode, "clipboard", "", "Clipboard backend (auto, system, osc52, none)")
+	flag.BoolVar(&copyGenerated, "copy-generated", false, "Also copy a generated password to the clipboard")
+	flag.BoolVar(&detectDuplicates, "detect-duplicates", false, "Offer to merge duplicate entries after a manual sync")
+	flag.BoolVar(&syncOnChange, "sync-on-change", false, "Sync right after every create, update or delete")
+	flag.DurationVar(&loginTimeout, "login-timeout", 0, "Limit of the login handshake with the server (default 30s, negative disables it)")
+	flag.IntVar(&loginRetries, "login-retries", 0, "Retries of a login request failed with a network error or 5xx (default 1, negative disables them)")
+	flag.IntVar(&syncBreakerThreshold, "sync-breaker-threshold", 0, "Syncs in a row failing on the network after which syncing is paused (default 3, negative disables)")
+	flag.DurationVar(&syncBreakerCooldown, "sync-breaker-cooldown", 0, "How long syncing is paused before the server is probed again (default 30s)")
+	flag.DurationVar(&pingInterval, "ping-interval", 0, "How often the TUI checks the connection to the server (default 30s, negative disables)")
+	flag.DurationVar(&idleLogout, "idle-logout", 0, "How long the TUI stays logged in without a key press (default 15m, negative disables)")
+	flag.BoolVar(&nonceAudit, "nonce-audit", false, "Record encryption nonces and refuse to reuse one (diagnostics)")
+	flag.BoolVar(&debugHTTP, "debug-http", false, "Log every request to the server without bodies (diagnostics)")
+	flag.BoolVar(&reencrypt, "reencrypt", false, "Re-encrypt entries stored in an older encryption format after login")
+	flag.BoolVar(&offline, "offline", false, "Browse the local vault read-only without contacting the server")
+	flag.StringVar(&defaultDataType, "default-data-type", "", "Type preselected when adding an entry (login, text, binary, card)")
+	flag.StringVar(&enabledDataTypes, "enabled-data-types", "", "Types that can be added, comma-separated (login, text, binary, card); all when empty")
+	flag.StringVar(&defaultFolder, "default-folder", "", "Folder pre-filled when adding an entry")
+	flag.StringVar(&syncMode, "sync-mode", "", "Sync direction (bidirectional, push-only, pull-only)")
+	flag.StringVar(&syncConflicts, "sync-conflicts", "", "Version conflict handling (server-wins, manual)")
+	flag.StringVar(&theme, "theme", "", "Client color theme (default, high-contrast, monochrome)")
+	flag.StringVar(&listColumns, "list-columns", "", "Vault list columns in display order, comma-separated (name, type, folder)")
+	flag.BoolVar(&maskNames, "mask-names", false, "Start the vault list with partially masked entry names")
+	flag.BoolVar(&folderTree, "folder-tree", false, "Start the vault list grouped into a folder tree")
+	flag.StringVar(&syncEvents, "sync-events", "", "Write sync events as JSON lines to \"stderr\" or a file")
+	flag.BoolVar(&listJSON, "json", false, "Print the vault list as JSON and exit (metadata only)")
+	flag.BoolVar(&listSecrets, "json-secrets", false, "Include secret fields in the -json output")
+	flag.BoolVar(&insecure, "insecure", false, "Allow a plain http:// server address (local development only)")
 
 	flag.Parse()
 
 	return &StructuredConfig{
 		App: App{
-			PasswordHashKey: passwordHashKey,
-			TokenSignKey:    tokenSignKey,
-			TokenIssuer:     tokenIssuer,
-			TokenDuration:   tokenDuration,
-			HashKey:         hashKey,
-			Version:         version,
+			PasswordHashKey:      passwordHashKey,
+			TokenSignKey:         tokenSignKey,
+			TokenSignKeyFile:     tokenSignKeyFile,
+			TokenIssuer:          tokenIssuer,
+			TokenDuration:        tokenDuration,
+			HashKey:              hashKey,
+			Version:              version,
+			LogLevel:             logLevel,
+			LogFormat:            logFormat,
+			MaxBinarySize:        maxBinarySize,
+			MaxNotesLength:       maxNotesLength,
+			MaxBatchEntries:      maxBatchEntries,
+			ClientIDPrefix:       clientIDPrefix,
+			SyncStaleAfter:       syncStaleAfter,
+			StatesCacheTTL:       statesCacheTTL,
+			Clipboard:            clipboardMode,
+			CopyGenerated:        copyGenerated,
+			DetectDuplicates:     detectDuplicates,
+			SyncOnChange:         syncOnChange,
+			LoginTimeout:         loginTimeout,
+			LoginRetries:         loginRetries,
+			SyncBreakerThreshold: syncBreakerThreshold,
+			SyncBreakerCooldown:  syncBreakerCooldown,
+			PingInterval:         pingInterval,
+			IdleLogout:           idleLogout,
+			NonceAudit:           nonceAudit,
+			DebugHTTP:            debugHTTP,
+			Reencrypt:            reencrypt,
+			Offline:              offline,
+			DefaultDataType:      defaultDataType,
+			EnabledDataTypes:     enabledDataTypes,
+			DefaultFolder:        defaultFolder,
+			SyncMode:             syncMode,
+			SyncConflicts:        syncConflicts,
+			SyncEvents:           syncEvents,
+			Theme:                theme,
+			ListColumns:          listColumns,
+			MaskNames:            maskNames,
+			FolderTree:           folderTree,
+			ListJSON:             listJSON,
+			ListSecrets:          listSecrets,
+			Insecure:             insecure,
 		},
 		Storage: Storage{
 			DB: DB{
@@ -83,16 +302,43 @@ func ParseFlags() *StructuredConfig {
 			},
 			Files: Files{
 				BinaryDataDir: fileStoragePath,
+				DataDir:       dataDir,
+				BackupDir:     backupDir,
+				BackupCount:   backupCount,
 			},
+			HardDelete:       hardDelete,
+			VersionHistory:   versionHistory,
+			MaxClientSideIDs: maxClientSideIDs,
+			SyncLockTimeout:  syncLockTimeout,
+			SkipSchemaCheck:  skipSchemaCheck,
 		},
 		Server: Server{
-			HTTPAddress:    serverAddress.String(),
-			GRPCAddress:    grpcServerAddress.String(),
-			RequestTimeout: requestTimeout,
+			HTTPAddress:          serverAddress.String(),
+			GRPCAddress:          grpcServerAddress.String(),
+			AdminAddress:         adminAddress.String(),
+			RequestTimeout:       requestTimeout,
+			ReadTimeout:          readTimeout,
+			ReadHeaderTimeout:    readHeaderTimeout,
+			WriteTimeout:         writeTimeout,
+			IdleTimeout:          idleTimeout,
+			AdminToken:           adminToken,
+			AccessLogLevel:       accessLogLevel,
+			BasePath:             basePath,
+			MinSaltLength:        minSaltLength,
+			MaxInFlight:          maxInFlight,
+			GzipLevel:            gzipLevel,
+			GzipMinSize:          gzipMinSize,
+			StateTTL:             stateTTL,
+			StateCleanupInterval: stateCleanupInterval,
+			LoginMaxAttempts:     loginMaxAttempts,
+			LoginAttemptWindow:   loginAttemptWindow,
+			LoginLockout:         loginLockout,
 		},
-		Adapter:      Adapter{},
-		Workers:      Workers{},
-		JSONFilePath: jsonConfigPath,
+		Adapter:       Adapter{},
+		Workers:       Workers{},
+		JSONFilePath:  jsonConfigPath,
+		PrintConfig:   printConfig,
+		MigrateDryRun: migrateDryRun,
 	}
 }
 
@@ -165,6 +165,8 @@ func TestParseFlags(t *testing.T) {
 				"-token-duration", "1h",
 				"-request-timeout", "30s",
 				"-hash-key", "security_hash",
+				"-log-level", "warn",
+				"-log-format", "console",
 			},
 			validate: func(t *testing.T, cfg *StructuredConfig) {
 				assert.Equal(t, "localhost:8080", cfg.Server.HTTPAddress)
@@ -178,6 +180,8 @@ func TestParseFlags(t *testing.T) {
 				assert.Equal(t, time.Hour, cfg.App.TokenDuration)
 				assert.Equal(t, 30*time.Second, cfg.Server.RequestTimeout)
 				assert.Equal(t, "security_hash", cfg.App.HashKey)
+				assert.Equal(t, "warn", cfg.App.LogLevel)
+				assert.Equal(t, "console", cfg.App.LogFormat)
 			},
 		},
 		{
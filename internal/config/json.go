@@ -20,12 +20,48 @@ import (
 type StructuredJSONConfig struct {
 	// App holds application-level settings loaded from the JSON file.
 	App struct {
-		PasswordHashKey string   `json:"password_hash_key"`
-		TokenSignKey    string   `json:"token_sign_key"`
-		TokenIssuer     string   `json:"token_issuer"`
-		TokenDuration   Duration `json:"token_duration"`
-		HashKey         string   `json:"hash_key"`
-		Version         string   `json:"version"`
+		PasswordHashKey      string   `json:"password_hash_key"`
+		TokenSignKey         string   `json:"token_sign_key"`
+		TokenSignKeyFile     string   `json:"token_sign_key_file"`
+		TokenIssuer          string   `json:"token_issuer"`
+		TokenDuration        Duration `json:"token_duration"`
+		HashKey              string   `json:"hash_key"`
+		Version              string   `json:"version"`
+		LogLevel             string   `json:"log_level"`
+		LogFormat            string   `json:"log_format"`
+		MaxBinarySize        int64    `json:"max_binary_size"`
+		MaxNotesLength       int      `json:"max_notes_length"`
+		MaxBatchEntries      int      `json:"max_batch_entries"`
+		ClientIDPrefix       string   `json:"client_id_prefix"`
+		SyncStaleAfter       Duration `json:"sync_stale_after"`
+		StatesCacheTTL       Duration `json:"states_cache_ttl"`
+		Clipboard            string   `json:"clipboard"`
+		CopyGenerated        bool     `json:"copy_generated"`
+		DetectDuplicates     bool     `json:"detect_duplicates"`
+		SyncOnChange         bool     `json:"sync_on_change"`
+		LoginTimeout         Duration `json:"login_timeout"`
+		LoginRetries         int      `json:"login_retries"`
+		SyncBreakerThreshold int      `json:"sync_breaker_threshold"`
+		SyncBreakerCooldown  Duration `json:"sync_breaker_cooldown"`
+		PingInterval         Duration `json:"ping_interval"`
+		IdleLogout           Duration `json:"idle_logout"`
+		NonceAudit           bool     `json:"nonce_audit"`
+		DebugHTTP            bool     `json:"debug_http"`
+		Reencrypt            bool     `json:"reencrypt"`
+		Offline              bool     `json:"offline"`
+		DefaultDataType      string   `json:"default_data_type"`
+		EnabledDataTypes     string   `json:"enabled_data_types"`
+		DefaultFolder        string   `json:"default_folder"`
+		SyncMode             string   `json:"sync_mode"`
+		SyncConflicts        string   `json:"sync_conflicts"`
+		SyncEvents           string   `json:"sync_events"`
+		Theme                string   `json:"theme"`
+		ListColumns          string   `json:"list_columns"`
+		MaskNames            bool     `json:"mask_names"`
+		FolderTree           bool     `json:"folder_tree"`
+		ListJSON             bool     `json:"list_json"`
+		ListSecrets          bool     `json:"list_secrets"`
+		Insecure             bool     `json:"insecure"`
 	} `json:"app,omitempty"`
 
 	// Storage holds database and file-storage settings loaded from the JSON file.
@@ -36,14 +72,40 @@ type StructuredJSONConfig struct {
 
 		Files struct {
 			BinaryDataDir string `json:"binary_data_dir"`
+			DataDir       string `json:"data_dir"`
+			BackupDir     string `json:"backup_dir"`
+			BackupCount   int    `json:"backup_count"`
 		} `json:"files,omitempty"`
+
+		HardDelete       bool     `json:"hard_delete"`
+		VersionHistory   int      `json:"version_history"`
+		MaxClientSideIDs int      `json:"max_client_side_ids"`
+		SyncLockTimeout  Duration `json:"sync_lock_timeout"`
+		SkipSchemaCheck  bool     `json:"skip_schema_check"`
 	} `json:"storage,omitempty"`
 
 	// Server holds HTTP and gRPC server settings loaded from the JSON file.
 	Server struct {
-		HTTPAddress    string   `json:"http_address"`
-		GRPCAddress    string   `json:"grpc_address"`
-		RequestTimeout Duration `json:"request_timeout"`
+		HTTPAddress          string   `json:"http_address"`
+		GRPCAddress          string   `json:"grpc_address"`
+		AdminAddress         string   `json:"admin_address"`
+		RequestTimeout       Duration `json:"request_timeout"`
+		ReadTimeout          Duration `json:"read_timeout"`
+		ReadHeaderTimeout    Duration `json:"read_header_timeout"`
+		WriteTimeout         Duration `json:"write_timeout"`
+		IdleTimeout          Duration `json:"idle_timeout"`
+		AdminToken           string   `json:"admin_token"`
+		AccessLogLevel       string   `json:"access_log_level"`
+		BasePath             string   `json:"base_path"`
+		MinSaltLength        int      `json:"min_salt_length"`
+		MaxInFlight          int      `json:"max_in_flight"`
+		GzipLevel            int      `json:"gzip_level"`
+		GzipMinSize          int      `json:"gzip_min_size"`
+		StateTTL             Duration `json:"state_ttl"`
+		StateCleanupInterval Duration `json:"state_cleanup_interval"`
+		LoginMaxAttempts     int      `json:"login_max_attempts"`
+		LoginAttemptWindow   Duration `json:"login_attempt_window"`
+		LoginLockout         Duration `json:"login_lockout"`
 	} `json:"server,omitempty"`
 
 	// Adapter is reserved for future external adapter configuration.
@@ -81,12 +143,48 @@ func parseJSON(jsonFilePath string) (*StructuredConfig, error) {
 
 	cfg := &StructuredConfig{
 		App: App{
-			PasswordHashKey: jsonCfg.App.PasswordHashKey,
-			TokenSignKey:    jsonCfg.App.TokenSignKey,
-			TokenIssuer:     jsonCfg.App.TokenIssuer,
-			TokenDuration:   time.Duration(jsonCfg.App.TokenDuration),
-			HashKey:         jsonCfg.App.HashKey,
-			Version:         jsonCfg.App.Version,
+			PasswordHashKey:      jsonCfg.App.PasswordHashKey,
+			TokenSignKey:         jsonCfg.App.TokenSignKey,
+			TokenSignKeyFile:     jsonCfg.App.TokenSignKeyFile,
+			TokenIssuer:          jsonCfg.App.TokenIssuer,
+			TokenDuration:        time.Duration(jsonCfg.App.TokenDuration),
+			HashKey:              jsonCfg.App.HashKey,
+			Version:              jsonCfg.App.Version,
+			LogLevel:             jsonCfg.App.LogLevel,
+			LogFormat:            jsonCfg.App.LogFormat,
+			MaxBinarySize:        jsonCfg.App.MaxBinarySize,
+			MaxNotesLength:       jsonCfg.App.MaxNotesLength,
+			MaxBatchEntries:      jsonCfg.App.MaxBatchEntries,
+			ClientIDPrefix:       jsonCfg.App.ClientIDPrefix,
+			SyncStaleAfter:       time.Duration(jsonCfg.App.SyncStaleAfter),
+			StatesCacheTTL:       time.Duration(jsonCfg.App.StatesCacheTTL),
+			Clipboard:            jsonCfg.App.Clipboard,
+			CopyGenerated:        jsonCfg.App.CopyGenerated,
+			DetectDuplicates:     jsonCfg.App.DetectDuplicates,
+			SyncOnChange:         jsonCfg.App.SyncOnChange,
+			LoginTimeout:         time.Duration(jsonCfg.App.LoginTimeout),
+			LoginRetries:         jsonCfg.App.LoginRetries,
+			SyncBreakerThreshold: jsonCfg.App.SyncBreakerThreshold,
+			SyncBreakerCooldown:  time.Duration(jsonCfg.App.SyncBreakerCooldown),
+			PingInterval:         time.Duration(jsonCfg.App.PingInterval),
+			IdleLogout:           time.Duration(jsonCfg.App.IdleLogout),
+			NonceAudit:           jsonCfg.App.NonceAudit,
+			DebugHTTP:            jsonCfg.App.DebugHTTP,
+			Reencrypt:            jsonCfg.App.Reencrypt,
+			Offline:              jsonCfg.App.Offline,
+			DefaultDataType:      jsonCfg.App.DefaultDataType,
+			EnabledDataTypes:     jsonCfg.App.EnabledDataTypes,
+			DefaultFolder:        jsonCfg.App.DefaultFolder,
+			SyncMode:             jsonCfg.App.SyncMode,
+			SyncConflicts:        jsonCfg.App.SyncConflicts,
+			SyncEvents:           jsonCfg.App.SyncEvents,
+			Theme:                jsonCfg.App.Theme,
+			ListColumns:          jsonCfg.App.ListColumns,
+			MaskNames:            jsonCfg.App.MaskNames,
+			FolderTree:           jsonCfg.App.FolderTree,
+			ListJSON:             jsonCfg.App.ListJSON,
+			ListSecrets:          jsonCfg.App.ListSecrets,
+			Insecure:             jsonCfg.App.Insecure,
 		},
 		Storage: Storage{
 			DB: DB{
@@ -94,12 +192,37 @@ func parseJSON(jsonFilePath string) (*StructuredConfig, error) {
 			},
 			Files: Files{
 				BinaryDataDir: jsonCfg.Storage.Files.BinaryDataDir,
+				DataDir:       jsonCfg.Storage.Files.DataDir,
+				BackupDir:     jsonCfg.Storage.Files.BackupDir,
+				BackupCount:   jsonCfg.Storage.Files.BackupCount,
 			},
+			HardDelete:       jsonCfg.Storage.HardDelete,
+			VersionHistory:   jsonCfg.Storage.VersionHistory,
+			MaxClientSideIDs: jsonCfg.Storage.MaxClientSideIDs,
+			SyncLockTimeout:  time.Duration(jsonCfg.Storage.SyncLockTimeout),
+			SkipSchemaCheck:  jsonCfg.Storage.SkipSchemaCheck,
 		},
 		Server: Server{
-			HTTPAddress:    jsonCfg.Server.HTTPAddress,
-			GRPCAddress:    jsonCfg.Server.GRPCAddress,
-			RequestTimeout: time.Duration(jsonCfg.Server.RequestTimeout),
+			HTTPAddress:          jsonCfg.Server.HTTPAddress,
+			GRPCAddress:          jsonCfg.Server.GRPCAddress,
+			AdminAddress:         jsonCfg.Server.AdminAddress,
+			RequestTimeout:       time.Duration(jsonCfg.Server.RequestTimeout),
+			ReadTimeout:          time.Duration(jsonCfg.Server.ReadTimeout),
+			ReadHeaderTimeout:    time.Duration(jsonCfg.Server.ReadHeaderTimeout),
+			WriteTimeout:         time.Duration(jsonCfg.Server.WriteTimeout),
+			IdleTimeout:          time.Duration(jsonCfg.Server.IdleTimeout),
+			AdminToken:           jsonCfg.Server.AdminToken,
+			AccessLogLevel:       jsonCfg.Server.AccessLogLevel,
+			BasePath:             jsonCfg.Server.BasePath,
+			MinSaltLength:        jsonCfg.Server.MinSaltLength,
+			MaxInFlight:          jsonCfg.Server.MaxInFlight,
+			GzipLevel:            jsonCfg.Server.GzipLevel,
+			GzipMinSize:          jsonCfg.Server.GzipMinSize,
+			StateTTL:             time.Duration(jsonCfg.Server.StateTTL),
+			StateCleanupInterval: time.Duration(jsonCfg.Server.StateCleanupInterval),
+			LoginMaxAttempts:     jsonCfg.Server.LoginMaxAttempts,
+			LoginAttemptWindow:   time.Duration(jsonCfg.Server.LoginAttemptWindow),
+			LoginLockout:         time.Duration(jsonCfg.Server.LoginLockout),
 		},
 		Adapter: Adapter{
 			HTTPAddress:    jsonCfg.Adapter.HTTPAddress,
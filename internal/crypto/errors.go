@@ -2,3 +2,30 @@
 // Copyright 2026 Rasul Khiriev
 
 package crypto
+
+import "errors"
+
+var (
+	// ErrStreamTruncated is returned by DecryptStream when the ciphertext ends
+	// before a chunk marked as final has been read.
+	ErrStreamTruncated = errors.New("encrypted stream is truncated")
+
+	// ErrStreamChunkAuth is returned by DecryptStream when a chunk fails GCM
+	// authentication. This covers corrupted data, a wrong key, and chunks that
+	// were reordered, duplicated, or dropped, because the chunk index and the
+	// final-chunk flag are bound into each nonce.
+	ErrStreamChunkAuth = errors.New("encrypted stream chunk failed authentication")
+
+	// ErrStreamTrailingData is returned by DecryptStream when bytes follow the
+	// final chunk or the chunk count does not match the expected value.
+	ErrStreamTrailingData = errors.New("encrypted stream has unexpected trailing data")
+
+	// ErrStreamTooLong is returned by EncryptStream when the input would need
+	// more chunks than the 32-bit chunk counter can address.
+	ErrStreamTooLong = errors.New("stream exceeds maximum number of chunks")
+
+	// ErrNonceReuse is returned when a [NonceRecorder] is installed and a
+	// freshly generated nonce has already been used. Encryption is refused
+	// rather than sealing two messages under the same key and nonce.
+	ErrNonceReuse = errors.New("nonce reuse detected")
+)
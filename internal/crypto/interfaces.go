@@ -35,6 +35,8 @@
 //  4. [KeyChainService.DecryptDEK](encryptedDEK, KEK)  → recover DEK
 package crypto
 
+import "io"
+
 //go:generate mockgen -source=interfaces.go -destination=../mock/keychain_service_mock.go -package=mock
 
 // KeyChainService is responsible for all client-side cryptography in the
@@ -83,8 +85,9 @@ type KeyChainService interface {
 	DecryptDEK(encryptedDEK, KEK []byte) ([]byte, error)
 
 	// EncryptData serialises data to JSON and encrypts it with DEK using
-	// AES-256-GCM. Returns a Base64-encoded blob (nonce ‖ ciphertext) that
-	// is safe to store on the server.
+	// AES-256-GCM under a fresh random nonce. Returns a Base64-encoded blob
+	// (version ‖ salt ‖ nonce ‖ ciphertext, see [FormatVersion2]) that is
+	// safe to store on the server.
 	EncryptData(data any, DEK []byte) (string, error)
 
 	// DecryptData decodes the Base64 blob produced by
@@ -93,4 +96,24 @@ type KeyChainService interface {
 	// required by [encoding/json.Unmarshal]). Returns an error if decoding,
 	// decryption, or unmarshalling fails.
 	DecryptData(encryptedB64 string, DEK []byte, target any) error
+
+	// DecryptDataVersion is [KeyChainService.DecryptData] that also returns
+	// the format version the blob was written in: [FormatVersion1],
+	// [FormatVersion2], or [FormatVersionLegacy]. A version below
+	// [CurrentFormatVersion] means the blob should be re-encrypted.
+	DecryptDataVersion(encryptedB64 string, DEK []byte, target any) (byte, error)
+
+	// EncryptStream encrypts src into dst in independently sealed
+	// AES-256-GCM chunks of chunkSize plaintext bytes, so that arbitrarily
+	// large inputs can be encrypted with bounded memory. The returned
+	// [StreamInfo] must be stored with the ciphertext; it is required by
+	// [KeyChainService.DecryptStream].
+	EncryptStream(dst io.Writer, src io.Reader, DEK []byte, chunkSize int) (StreamInfo, error)
+
+	// DecryptStream decrypts a stream produced by
+	// [KeyChainService.EncryptStream], verifying every chunk as well as
+	// chunk order and stream completeness. Returns an error wrapping
+	// [ErrStreamChunkAuth], [ErrStreamTruncated], or [ErrStreamTrailingData]
+	// when the stream was tampered with.
+	DecryptStream(dst io.Writer, src io.Reader, DEK []byte, info StreamInfo) error
 }
@@ -6,6 +6,7 @@ package crypto
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hkdf"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -93,8 +94,8 @@ func (k *keyChainService) GetEncryptedDEK(DEK, KEK []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+	nonce, err := newNonce(gcm.NonceSize())
+	if err != nil {
 		return nil, err
 	}
 
@@ -149,9 +150,12 @@ func (k *keyChainService) DecryptDEK(encryptedDEK, KEK []byte) ([]byte, error) {
 }
 
 // EncryptData implements [KeyChainService]. It marshals data to JSON, then
-// encrypts it with DEK using AES-256-GCM. The output is a Base64
-// (standard encoding) string of the blob: nonce (12 bytes) ‖ ciphertext.
-// Returns an error if marshalling, cipher creation, or nonce generation fails.
+// encrypts it with AES-256-GCM under a fresh random nonce and a key derived
+// from DEK and a fresh random salt. The output is a Base64 (standard
+// encoding) string of the blob: version (1 byte) ‖ salt (16 bytes) ‖
+// nonce (12 bytes) ‖ ciphertext, where version is [CurrentFormatVersion].
+// Returns an error if marshalling, key derivation, cipher creation, or
+// nonce generation fails.
 func (k *keyChainService) EncryptData(data any, DEK []byte) (string, error) {
 	// 1. Serialize to JSON
 	plaintext, err := json.Marshal(data)
@@ -159,69 +163,125 @@ func (k *keyChainService) EncryptData(data any, DEK []byte) (string, error) {
 		return "", fmt.Errorf("marshal data: %w", err)
 	}
 
-	// 2. Build AES-GCM cipher from DEK
-	block, err := aes.NewCipher(DEK)
-	if err != nil {
-		return "", fmt.Errorf("create cipher: %w", err)
+	// 2. Derive the per-blob AES-GCM cipher from DEK and a random salt
+	salt := make([]byte, blobSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
 	}
-	gcm, err := cipher.NewGCM(block)
+	gcm, err := blobCipher(DEK, salt)
 	if err != nil {
-		return "", fmt.Errorf("create gcm: %w", err)
+		return "", err
 	}
 
 	// 3. Generate a random nonce
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", fmt.Errorf("generate nonce: %w", err)
+	nonce, err := newNonce(gcm.NonceSize())
+	if err != nil {
+		return "", err
 	}
 
-	// 4. Encrypt: nonce || ciphertext
-	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
-	blob := append(nonce, ciphertext...)
+	// 4. Encrypt: version || salt || nonce || ciphertext, version and salt bound as AAD
+	header := append([]byte{CurrentFormatVersion}, salt...)
+	blob := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+gcm.Overhead())
+	blob = append(blob, header...)
+	blob = append(blob, nonce...)
+	blob = gcm.Seal(blob, nonce, plaintext, header)
 
 	return base64.StdEncoding.EncodeToString(blob), nil
 }
 
-// DecryptData implements [KeyChainService]. It Base64-decodes encryptedB64,
-// splits out the nonce, decrypts the ciphertext with DEK via AES-256-GCM,
-// and unmarshals the resulting JSON into target. target must be a non-nil
-// pointer, identical to the requirement of [encoding/json.Unmarshal]. Returns
-// an error if any step (decoding, cipher creation, decryption, or
-// unmarshalling) fails.
+// DecryptData implements [KeyChainService] by delegating to
+// [keyChainService.DecryptDataVersion] and dropping the version.
 func (k *keyChainService) DecryptData(encryptedB64 string, DEK []byte, target any) error {
+	_, err := k.DecryptDataVersion(encryptedB64, DEK, target)
+	return err
+}
+
+// DecryptDataVersion implements [KeyChainService]. It Base64-decodes
+// encryptedB64, reads the format version, decrypts the ciphertext with the
+// decoder of that version, and unmarshals the resulting JSON into target.
+// Blobs written before the version header existed (nonce ‖ ciphertext) are
+// still accepted and reported as [FormatVersionLegacy]. target must be a
+// non-nil pointer, identical to the requirement of [encoding/json.Unmarshal].
+// Returns an error if any step (decoding, cipher creation, decryption, or
+// unmarshalling) fails.
+func (k *keyChainService) DecryptDataVersion(encryptedB64 string, DEK []byte, target any) (byte, error) {
 	// 1. Decode base64 blob
 	blob, err := base64.StdEncoding.DecodeString(encryptedB64)
 	if err != nil {
-		return fmt.Errorf("decode base64: %w", err)
+		return 0, fmt.Errorf("decode base64: %w", err)
 	}
 
-	// 2. Build AES-GCM cipher from DEK
-	block, err := aes.NewCipher(DEK)
+	// 2-4. Dispatch on the header; decrypt and verify auth tag
+	version, plaintext, err := openBlob(DEK, blob)
 	if err != nil {
-		return fmt.Errorf("create cipher: %w", err)
+		return 0, err
 	}
-	gcm, err := cipher.NewGCM(block)
+
+	// 5. Unmarshal JSON into target
+	if err := json.Unmarshal(plaintext, target); err != nil {
+		return 0, fmt.Errorf("unmarshal data: %w", err)
+	}
+
+	return version, nil
+}
+
+// blobSaltSize is the length of the per-blob salt of [FormatVersion2].
+const blobSaltSize = 16
+
+// blobKeyInfo is the HKDF info string of [FormatVersion2] blob keys. It
+// domain-separates them from any other key derived from the DEK.
+const blobKeyInfo = "gopasskeeper data v2"
+
+// blobCipher returns the AES-256-GCM cipher of a [FormatVersion2] blob,
+// keyed with HKDF-SHA256(DEK, salt).
+func blobCipher(DEK, salt []byte) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, DEK, salt, blobKeyInfo, len(DEK))
 	if err != nil {
-		return fmt.Errorf("create gcm: %w", err)
+		return nil, fmt.Errorf("derive key: %w", err)
 	}
+	return newGCM(key)
+}
 
-	// 3. Split nonce and ciphertext
+// openBlob decrypts a blob produced by [keyChainService.EncryptData] and
+// returns its format version. A legacy blob has no version header, and its
+// first nonce byte may happen to equal a known version, so a versioned open
+// that fails authentication is retried as legacy before the error is
+// reported.
+func openBlob(DEK, blob []byte) (byte, []byte, error) {
+	gcm, err := newGCM(DEK)
+	if err != nil {
+		return 0, nil, err
+	}
 	nonceSize := gcm.NonceSize()
+
+	if len(blob) > 0 {
+		switch blob[0] {
+		case FormatVersion2:
+			if len(blob) > 1+blobSaltSize+nonceSize {
+				header, rest := blob[:1+blobSaltSize], blob[1+blobSaltSize:]
+				if v2, err := blobCipher(DEK, header[1:]); err == nil {
+					if plaintext, err := v2.Open(nil, rest[:nonceSize], rest[nonceSize:], header); err == nil {
+						return FormatVersion2, plaintext, nil
+					}
+				}
+			}
+		case FormatVersion1:
+			if len(blob) > nonceSize {
+				header, rest := blob[:1], blob[1:]
+				if plaintext, err := gcm.Open(nil, rest[:nonceSize], rest[nonceSize:], header); err == nil {
+					return FormatVersion1, plaintext, nil
+				}
+			}
+		}
+	}
+
 	if len(blob) < nonceSize {
-		return fmt.Errorf("ciphertext too short")
+		return 0, nil, fmt.Errorf("ciphertext too short")
 	}
 	nonce, ciphertext := blob[:nonceSize], blob[nonceSize:]
-
-	// 4. Decrypt and verify auth tag
 	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
-		return fmt.Errorf("decrypt data: %w", err)
+		return 0, nil, fmt.Errorf("decrypt data: %w", err)
 	}
-
-	// 5. Unmarshal JSON into target
-	if err := json.Unmarshal(plaintext, target); err != nil {
-		return fmt.Errorf("unmarshal data: %w", err)
-	}
-
-	return nil
+	return FormatVersionLegacy, plaintext, nil
 }
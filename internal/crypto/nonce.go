@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package crypto
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// FormatVersion1 is the first versioned layout of blobs produced by
+// [KeyChainService.EncryptData]: version (1 byte) ‖ nonce (12 bytes) ‖
+// ciphertext. The version byte is bound to the ciphertext as GCM additional
+// data, so it cannot be altered without failing authentication.
+const FormatVersion1 byte = 1
+
+// FormatVersion2 is the layout version (1 byte) ‖ salt (16 bytes) ‖
+// nonce (12 bytes) ‖ ciphertext. Every blob is sealed under its own
+// AES-256-GCM key, derived from the DEK and the random salt with HKDF-SHA256,
+// so the number of blobs a DEK can safely encrypt is no longer bounded by
+// random-nonce collisions. The version byte and the salt are bound to the
+// ciphertext as GCM additional data.
+const FormatVersion2 byte = 2
+
+// FormatVersionLegacy is reported by [KeyChainService.DecryptDataVersion] for
+// blobs written before the header was introduced (nonce ‖ ciphertext).
+const FormatVersionLegacy byte = 0
+
+// CurrentFormatVersion is the format version written by
+// [KeyChainService.EncryptData]. Blobs of older versions, including legacy
+// ones, are still accepted by DecryptData.
+const CurrentFormatVersion = FormatVersion2
+
+// nonceSource supplies the random bytes for every nonce. Tests replace it to
+// simulate a broken random source.
+var nonceSource io.Reader = rand.Reader
+
+// nonceRecorder holds the recorder installed by [SetNonceRecorder], or nil.
+var nonceRecorder atomic.Pointer[NonceRecorder]
+
+// NonceRecorder is a debug hook that remembers every nonce generated by this
+// package and refuses to hand out one that was already used. It keeps all
+// nonces in memory, so it is meant for tests and diagnostic runs only.
+type NonceRecorder struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewNonceRecorder returns an empty [NonceRecorder].
+func NewNonceRecorder() *NonceRecorder {
+	return &NonceRecorder{seen: make(map[string]struct{})}
+}
+
+// Record stores nonce and returns [ErrNonceReuse] if it was recorded before.
+func (r *NonceRecorder) Record(nonce []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := string(nonce)
+	if _, ok := r.seen[key]; ok {
+		return ErrNonceReuse
+	}
+	r.seen[key] = struct{}{}
+	return nil
+}
+
+// Count returns the number of distinct nonces recorded so far.
+func (r *NonceRecorder) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.seen)
+}
+
+// SetNonceRecorder installs r as the package-wide nonce recorder and returns
+// a function that restores the previous one. Passing nil disables recording.
+// While a recorder is installed, every seal fails with [ErrNonceReuse]
+// instead of encrypting under a repeated nonce.
+func SetNonceRecorder(r *NonceRecorder) (restore func()) {
+	prev := nonceRecorder.Swap(r)
+	return func() { nonceRecorder.Store(prev) }
+}
+
+// newNonce returns size fresh random bytes for use as a nonce (or nonce
+// prefix). Every seal in this package draws its nonce here, so a nonce is
+// never derived from or shared with another encryption.
+func newNonce(size int) ([]byte, error) {
+	nonce := make([]byte, size)
+	if _, err := io.ReadFull(nonceSource, nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	if r := nonceRecorder.Load(); r != nil {
+		if err := r.Record(nonce); err != nil {
+			return nil, err
+		}
+	}
+	return nonce, nil
+}
@@ -0,0 +1,224 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type noncePayload struct {
+	Name string `json:"name"`
+}
+
+func TestEncryptData_UniqueNoncesAcrossManyEncryptions(t *testing.T) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(t)
+
+	recorder := NewNonceRecorder()
+	t.Cleanup(SetNonceRecorder(recorder))
+
+	const n = 2000
+	seen := make(map[string]struct{}, n)
+	for i := 0; i < n; i++ {
+		blob, err := svc.EncryptData(noncePayload{Name: "same"}, dek)
+		if err != nil {
+			t.Fatalf("EncryptData error at %d: %v", i, err)
+		}
+		if _, dup := seen[blob]; dup {
+			t.Fatalf("ciphertext repeated at %d", i)
+		}
+		seen[blob] = struct{}{}
+	}
+
+	if recorder.Count() != n {
+		t.Fatalf("recorded %d nonces, want %d", recorder.Count(), n)
+	}
+}
+
+func TestEncryptData_ReplayedNonceDetected(t *testing.T) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(t)
+
+	t.Cleanup(SetNonceRecorder(NewNonceRecorder()))
+	prevSource := nonceSource
+	t.Cleanup(func() { nonceSource = prevSource })
+	// A broken random source that returns the same bytes twice.
+	nonceSource = bytes.NewReader(bytes.Repeat([]byte{0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42}, 2))
+
+	if _, err := svc.EncryptData(noncePayload{Name: "first"}, dek); err != nil {
+		t.Fatalf("first EncryptData error: %v", err)
+	}
+	if _, err := svc.EncryptData(noncePayload{Name: "second"}, dek); !errors.Is(err, ErrNonceReuse) {
+		t.Fatalf("second EncryptData error = %v, want %v", err, ErrNonceReuse)
+	}
+}
+
+func TestNonceRecorder_Record(t *testing.T) {
+	r := NewNonceRecorder()
+
+	if err := r.Record([]byte("nonce-a")); err != nil {
+		t.Fatalf("Record error: %v", err)
+	}
+	if err := r.Record([]byte("nonce-b")); err != nil {
+		t.Fatalf("Record error: %v", err)
+	}
+	if err := r.Record([]byte("nonce-a")); !errors.Is(err, ErrNonceReuse) {
+		t.Fatalf("Record replay error = %v, want %v", err, ErrNonceReuse)
+	}
+	if r.Count() != 2 {
+		t.Fatalf("Count = %d, want 2", r.Count())
+	}
+}
+
+func TestEncryptData_FormatVersionHeader(t *testing.T) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(t)
+
+	enc, err := svc.EncryptData(noncePayload{Name: "versioned"}, dek)
+	if err != nil {
+		t.Fatalf("EncryptData error: %v", err)
+	}
+	blob, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if blob[0] != CurrentFormatVersion {
+		t.Fatalf("version byte = %d, want %d", blob[0], CurrentFormatVersion)
+	}
+
+	var got noncePayload
+	if err := svc.DecryptData(enc, dek, &got); err != nil {
+		t.Fatalf("DecryptData error: %v", err)
+	}
+	if got.Name != "versioned" {
+		t.Fatalf("got %q, want %q", got.Name, "versioned")
+	}
+
+	// The version byte is authenticated: changing it must break decryption.
+	blob[0] = FormatVersion1
+	if err := svc.DecryptData(base64.StdEncoding.EncodeToString(blob), dek, &got); err == nil {
+		t.Fatalf("expected error for altered version byte")
+	}
+}
+
+func TestDecryptData_LegacyBlob(t *testing.T) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(t)
+
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		t.Fatalf("NewCipher error: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("NewGCM error: %v", err)
+	}
+	plaintext, err := json.Marshal(noncePayload{Name: "legacy"})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		firstNonce byte
+	}{
+		{name: "nonce does not look like a version", firstNonce: 0xAB},
+		{name: "nonce starts with a version byte", firstNonce: FormatVersion1},
+		{name: "nonce starts with the current version byte", firstNonce: FormatVersion2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonce := randomBytes(t, gcm.NonceSize())
+			nonce[0] = tt.firstNonce
+			blob := append(bytes.Clone(nonce), gcm.Seal(nil, nonce, plaintext, nil)...)
+
+			var got noncePayload
+			version, err := svc.DecryptDataVersion(base64.StdEncoding.EncodeToString(blob), dek, &got)
+			if err != nil {
+				t.Fatalf("DecryptDataVersion error: %v", err)
+			}
+			if version != FormatVersionLegacy {
+				t.Fatalf("version = %d, want %d", version, FormatVersionLegacy)
+			}
+			if got.Name != "legacy" {
+				t.Fatalf("got %q, want %q", got.Name, "legacy")
+			}
+		})
+	}
+}
+
+func TestDecryptData_FormatVersion1Blob(t *testing.T) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(t)
+
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		t.Fatalf("NewCipher error: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("NewGCM error: %v", err)
+	}
+	plaintext, err := json.Marshal(noncePayload{Name: "v1"})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	header := []byte{FormatVersion1}
+	nonce := randomBytes(t, gcm.NonceSize())
+	blob := append(append(bytes.Clone(header), nonce...), gcm.Seal(nil, nonce, plaintext, header)...)
+	enc := base64.StdEncoding.EncodeToString(blob)
+
+	var got noncePayload
+	version, err := svc.DecryptDataVersion(enc, dek, &got)
+	if err != nil {
+		t.Fatalf("DecryptDataVersion error: %v", err)
+	}
+	if version != FormatVersion1 {
+		t.Fatalf("version = %d, want %d", version, FormatVersion1)
+	}
+	if got.Name != "v1" {
+		t.Fatalf("got %q, want %q", got.Name, "v1")
+	}
+
+	// Re-encrypting what was read writes the current format.
+	reenc, err := svc.EncryptData(got, dek)
+	if err != nil {
+		t.Fatalf("EncryptData error: %v", err)
+	}
+	version, err = svc.DecryptDataVersion(reenc, dek, &got)
+	if err != nil {
+		t.Fatalf("DecryptDataVersion error: %v", err)
+	}
+	if version != CurrentFormatVersion || CurrentFormatVersion != FormatVersion2 {
+		t.Fatalf("version = %d, want %d", version, FormatVersion2)
+	}
+}
+
+func TestEncryptData_FormatVersion2SaltIsAuthenticated(t *testing.T) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(t)
+
+	enc, err := svc.EncryptData(noncePayload{Name: "salted"}, dek)
+	if err != nil {
+		t.Fatalf("EncryptData error: %v", err)
+	}
+	blob, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	blob[1] ^= 0xFF
+	var got noncePayload
+	if err := svc.DecryptData(base64.StdEncoding.EncodeToString(blob), dek, &got); err == nil {
+		t.Fatalf("expected error for altered salt")
+	}
+}
@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package crypto
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// DefaultChunkSize is the plaintext size of a single chunk produced by
+// [keyChainService.EncryptStream] when no explicit size is requested.
+// 64 KiB keeps per-chunk overhead (16-byte GCM tag) negligible while bounding
+// memory use to two chunk buffers regardless of file size.
+const DefaultChunkSize = 64 * 1024
+
+// streamNoncePrefixSize is the number of random bytes at the start of every
+// chunk nonce. The remaining 5 bytes of the 12-byte GCM nonce hold a 4-byte
+// big-endian chunk counter and a 1-byte "last chunk" flag.
+const streamNoncePrefixSize = 7
+
+// StreamInfo describes how a stream was chunked by [KeyChainService.EncryptStream].
+// It is not secret and must be stored alongside the ciphertext so that the
+// stream can be decrypted.
+type StreamInfo struct {
+	// ChunkSize is the plaintext size of every chunk except the last one.
+	ChunkSize int
+
+	// ChunkCount is the number of chunks written, including the final one.
+	ChunkCount int
+
+	// NoncePrefix is the random per-stream nonce prefix.
+	NoncePrefix []byte
+
+	// Size is the total plaintext size in bytes.
+	Size int64
+}
+
+// EncryptStream implements [KeyChainService]. It reads src in chunkSize pieces
+// and writes each piece to dst sealed independently with AES-256-GCM under DEK.
+//
+// Every chunk nonce is noncePrefix ‖ counter (uint32, big-endian) ‖ last flag,
+// following the STREAM construction: the counter prevents reordering, and the
+// final-chunk flag prevents truncation at a chunk boundary. Only two chunk
+// buffers are held in memory at a time. An empty src produces a single empty
+// final chunk. A non-positive chunkSize selects [DefaultChunkSize].
+func (k *keyChainService) EncryptStream(dst io.Writer, src io.Reader, DEK []byte, chunkSize int) (StreamInfo, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	gcm, err := newGCM(DEK)
+	if err != nil {
+		return StreamInfo{}, err
+	}
+
+	prefix, err := newNonce(streamNoncePrefixSize)
+	if err != nil {
+		return StreamInfo{}, fmt.Errorf("nonce prefix: %w", err)
+	}
+
+	info := StreamInfo{ChunkSize: chunkSize, NoncePrefix: prefix}
+
+	// Look one chunk ahead so the final chunk can be flagged before sealing.
+	current := make([]byte, chunkSize)
+	next := make([]byte, chunkSize)
+	sealed := make([]byte, 0, chunkSize+gcm.Overhead())
+	nonce := make([]byte, gcm.NonceSize())
+
+	n, err := readChunk(src, current)
+	if err != nil {
+		return StreamInfo{}, err
+	}
+
+	for counter := uint64(0); ; counter++ {
+		if counter > math.MaxUint32 {
+			return StreamInfo{}, ErrStreamTooLong
+		}
+
+		last := n < chunkSize
+		var nextN int
+		if !last {
+			nextN, err = readChunk(src, next)
+			if err != nil {
+				return StreamInfo{}, err
+			}
+			last = nextN == 0
+		}
+
+		streamNonce(nonce, prefix, uint32(counter), last)
+		sealed = gcm.Seal(sealed[:0], nonce, current[:n], nil)
+		if _, err := dst.Write(sealed); err != nil {
+			return StreamInfo{}, fmt.Errorf("write chunk %d: %w", counter, err)
+		}
+
+		info.Size += int64(n)
+		info.ChunkCount++
+
+		if last {
+			return info, nil
+		}
+		current, next = next, current
+		n = nextN
+	}
+}
+
+// DecryptStream implements [KeyChainService]. It reverses
+// [keyChainService.EncryptStream], writing the plaintext of each verified chunk
+// to dst as soon as it is authenticated.
+//
+// Returns [ErrStreamChunkAuth] if any chunk fails authentication (including
+// reordered or duplicated chunks), [ErrStreamTruncated] if the input ends
+// before the final chunk, and [ErrStreamTrailingData] if data follows the final
+// chunk or the number of chunks differs from info.ChunkCount (when non-zero).
+// Plaintext already written to dst before an error must be discarded by the
+// caller.
+func (k *keyChainService) DecryptStream(dst io.Writer, src io.Reader, DEK []byte, info StreamInfo) error {
+	if info.ChunkSize <= 0 || len(info.NoncePrefix) != streamNoncePrefixSize {
+		return fmt.Errorf("invalid stream info")
+	}
+
+	gcm, err := newGCM(DEK)
+	if err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(src)
+	sealed := make([]byte, info.ChunkSize+gcm.Overhead())
+	plain := make([]byte, 0, info.ChunkSize)
+	nonce := make([]byte, gcm.NonceSize())
+
+	for counter := uint64(0); ; counter++ {
+		if counter > math.MaxUint32 {
+			return ErrStreamTooLong
+		}
+
+		n, err := readChunk(r, sealed)
+		if err != nil {
+			return err
+		}
+		if n < gcm.Overhead() {
+			return fmt.Errorf("%w: chunk %d", ErrStreamTruncated, counter)
+		}
+
+		// A chunk is final when nothing follows it.
+		_, peekErr := r.Peek(1)
+		last := errors.Is(peekErr, io.EOF)
+
+		streamNonce(nonce, info.NoncePrefix, uint32(counter), last)
+		plain, err = gcm.Open(plain[:0], nonce, sealed[:n], nil)
+		if err != nil {
+			if last {
+				// The sealing side did not mark this chunk as final, so
+				// chunks are missing from the end of the stream.
+				if _, retryErr := gcm.Open(plain[:0], streamNonce(nonce, info.NoncePrefix, uint32(counter), false), sealed[:n], nil); retryErr == nil {
+					return fmt.Errorf("%w: after chunk %d", ErrStreamTruncated, counter)
+				}
+			}
+			return fmt.Errorf("%w: chunk %d", ErrStreamChunkAuth, counter)
+		}
+
+		if _, err := dst.Write(plain); err != nil {
+			return fmt.Errorf("write chunk %d: %w", counter, err)
+		}
+
+		if last {
+			if info.ChunkCount > 0 && int(counter)+1 != info.ChunkCount {
+				return fmt.Errorf("%w: got %d chunks, want %d", ErrStreamTrailingData, counter+1, info.ChunkCount)
+			}
+			return nil
+		}
+	}
+}
+
+// readChunk fills buf from r and returns the number of bytes read. A short
+// read at the end of the input is not an error.
+func readChunk(r io.Reader, buf []byte) (int, error) {
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, fmt.Errorf("read chunk: %w", err)
+	}
+	return n, nil
+}
+
+// streamNonce writes prefix ‖ counter ‖ last into nonce and returns it.
+func streamNonce(nonce, prefix []byte, counter uint32, last bool) []byte {
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[streamNoncePrefixSize:], counter)
+	nonce[len(nonce)-1] = 0
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+	return gcm, nil
+}
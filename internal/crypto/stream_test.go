@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+const testChunkSize = 1024
+
+func newStreamTestDEK(t testing.TB) []byte {
+	t.Helper()
+	dek, err := NewKeyChainService().GenerateDEK()
+	if err != nil {
+		t.Fatalf("GenerateDEK error: %v", err)
+	}
+	return dek
+}
+
+func randomBytes(t testing.TB, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("rand.Read error: %v", err)
+	}
+	return b
+}
+
+// splitChunks splits a stream produced by EncryptStream into sealed chunks.
+func splitChunks(sealed []byte, chunkSize int) [][]byte {
+	step := chunkSize + 16 // GCM tag
+	var chunks [][]byte
+	for len(sealed) > step {
+		chunks = append(chunks, sealed[:step])
+		sealed = sealed[step:]
+	}
+	return append(chunks, sealed)
+}
+
+func TestEncryptStream_RoundTrip(t *testing.T) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(t)
+
+	tests := []struct {
+		name       string
+		size       int
+		wantChunks int
+	}{
+		{name: "empty", size: 0, wantChunks: 1},
+		{name: "smaller than chunk", size: 100, wantChunks: 1},
+		{name: "exact chunk", size: testChunkSize, wantChunks: 1},
+		{name: "exact multiple of chunk", size: 3 * testChunkSize, wantChunks: 3},
+		{name: "multi chunk with tail", size: 5*testChunkSize + 17, wantChunks: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain := randomBytes(t, tt.size)
+
+			var sealed bytes.Buffer
+			info, err := svc.EncryptStream(&sealed, bytes.NewReader(plain), dek, testChunkSize)
+			if err != nil {
+				t.Fatalf("EncryptStream error: %v", err)
+			}
+			if info.ChunkCount != tt.wantChunks {
+				t.Fatalf("ChunkCount = %d, want %d", info.ChunkCount, tt.wantChunks)
+			}
+			if info.Size != int64(tt.size) {
+				t.Fatalf("Size = %d, want %d", info.Size, tt.size)
+			}
+			if info.ChunkSize != testChunkSize {
+				t.Fatalf("ChunkSize = %d, want %d", info.ChunkSize, testChunkSize)
+			}
+			if want := tt.size + 16*tt.wantChunks; sealed.Len() != want {
+				t.Fatalf("ciphertext length = %d, want %d", sealed.Len(), want)
+			}
+
+			var out bytes.Buffer
+			if err := svc.DecryptStream(&out, &sealed, dek, info); err != nil {
+				t.Fatalf("DecryptStream error: %v", err)
+			}
+			if !bytes.Equal(out.Bytes(), plain) {
+				t.Fatalf("round-trip mismatch")
+			}
+		})
+	}
+}
+
+func TestEncryptStream_DefaultChunkSize(t *testing.T) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(t)
+
+	var sealed bytes.Buffer
+	info, err := svc.EncryptStream(&sealed, bytes.NewReader(randomBytes(t, DefaultChunkSize+1)), dek, 0)
+	if err != nil {
+		t.Fatalf("EncryptStream error: %v", err)
+	}
+	if info.ChunkSize != DefaultChunkSize || info.ChunkCount != 2 {
+		t.Fatalf("got chunkSize=%d chunkCount=%d, want %d and 2", info.ChunkSize, info.ChunkCount, DefaultChunkSize)
+	}
+}
+
+func TestDecryptStream_RejectsTampering(t *testing.T) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(t)
+	plain := randomBytes(t, 4*testChunkSize+10)
+
+	var sealed bytes.Buffer
+	info, err := svc.EncryptStream(&sealed, bytes.NewReader(plain), dek, testChunkSize)
+	if err != nil {
+		t.Fatalf("EncryptStream error: %v", err)
+	}
+	chunks := splitChunks(sealed.Bytes(), testChunkSize)
+	if len(chunks) != 5 {
+		t.Fatalf("got %d chunks, want 5", len(chunks))
+	}
+
+	join := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+	flipped := bytes.Clone(sealed.Bytes())
+	flipped[testChunkSize+20] ^= 0x01
+
+	tests := []struct {
+		name    string
+		data    []byte
+		info    StreamInfo
+		wantErr error
+	}{
+		{
+			name:    "reordered chunks",
+			data:    join(chunks[1], chunks[0], chunks[2], chunks[3], chunks[4]),
+			info:    info,
+			wantErr: ErrStreamChunkAuth,
+		},
+		{
+			name:    "duplicated chunk",
+			data:    join(chunks[0], chunks[0], chunks[2], chunks[3], chunks[4]),
+			info:    info,
+			wantErr: ErrStreamChunkAuth,
+		},
+		{
+			name:    "truncated at chunk boundary",
+			data:    join(chunks[0], chunks[1], chunks[2]),
+			info:    info,
+			wantErr: ErrStreamTruncated,
+		},
+		{
+			name:    "truncated mid chunk",
+			data:    sealed.Bytes()[:2*testChunkSize+5],
+			info:    info,
+			wantErr: ErrStreamChunkAuth,
+		},
+		{
+			name:    "truncated to nothing",
+			data:    nil,
+			info:    info,
+			wantErr: ErrStreamTruncated,
+		},
+		{
+			name:    "trailing data after final chunk",
+			data:    join(sealed.Bytes(), chunks[4]),
+			info:    info,
+			wantErr: ErrStreamChunkAuth,
+		},
+		{
+			name:    "bit flip",
+			data:    flipped,
+			info:    info,
+			wantErr: ErrStreamChunkAuth,
+		},
+		{
+			name:    "chunk count mismatch",
+			data:    sealed.Bytes(),
+			info:    StreamInfo{ChunkSize: info.ChunkSize, ChunkCount: 4, NoncePrefix: info.NoncePrefix},
+			wantErr: ErrStreamTrailingData,
+		},
+		{
+			name:    "wrong nonce prefix",
+			data:    sealed.Bytes(),
+			info:    StreamInfo{ChunkSize: info.ChunkSize, ChunkCount: info.ChunkCount, NoncePrefix: make([]byte, streamNoncePrefixSize)},
+			wantErr: ErrStreamChunkAuth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.DecryptStream(io.Discard, bytes.NewReader(tt.data), dek, tt.info)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecryptStream error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecryptStream_WrongKey(t *testing.T) {
+	svc := NewKeyChainService()
+
+	var sealed bytes.Buffer
+	info, err := svc.EncryptStream(&sealed, bytes.NewReader(randomBytes(t, 3*testChunkSize)), newStreamTestDEK(t), testChunkSize)
+	if err != nil {
+		t.Fatalf("EncryptStream error: %v", err)
+	}
+
+	err = svc.DecryptStream(io.Discard, &sealed, newStreamTestDEK(t), info)
+	if !errors.Is(err, ErrStreamChunkAuth) {
+		t.Fatalf("DecryptStream error = %v, want %v", err, ErrStreamChunkAuth)
+	}
+}
+
+func TestDecryptStream_InvalidInfo(t *testing.T) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(t)
+
+	if err := svc.DecryptStream(io.Discard, bytes.NewReader(nil), dek, StreamInfo{}); err == nil {
+		t.Fatalf("expected error for empty stream info")
+	}
+}
+
+// benchmarkPayloadSize is large enough that whole-buffer encryption dominates
+// allocations, making the difference with streaming visible in -benchmem.
+const benchmarkPayloadSize = 16 << 20
+
+// BenchmarkEncryptData_WholeBuffer measures the pre-streaming approach: the
+// entire file is read into memory and sealed by EncryptData in one piece.
+func BenchmarkEncryptData_WholeBuffer(b *testing.B) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(b)
+	plain := randomBytes(b, benchmarkPayloadSize)
+
+	b.SetBytes(benchmarkPayloadSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.EncryptData(plain, dek); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEncryptStream measures streaming encryption of the same payload;
+// memory use is bounded by two chunk buffers regardless of payload size.
+func BenchmarkEncryptStream(b *testing.B) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(b)
+	plain := randomBytes(b, benchmarkPayloadSize)
+
+	b.SetBytes(benchmarkPayloadSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.EncryptStream(io.Discard, bytes.NewReader(plain), dek, DefaultChunkSize); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDecryptStream measures streaming decryption of the same payload.
+func BenchmarkDecryptStream(b *testing.B) {
+	svc := NewKeyChainService()
+	dek := newStreamTestDEK(b)
+
+	var sealed bytes.Buffer
+	info, err := svc.EncryptStream(&sealed, bytes.NewReader(randomBytes(b, benchmarkPayloadSize)), dek, DefaultChunkSize)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(benchmarkPayloadSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := svc.DecryptStream(io.Discard, bytes.NewReader(sealed.Bytes()), dek, info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -48,7 +48,7 @@ func NewHandlers(services *service.Services, cfg config.Server, logger *logger.L
 	handlers := &Handlers{}
 
 	if cfg.HTTPAddress != "" {
-		handlers.HTTP = http.NewHandler(services, logger)
+		handlers.HTTP = http.NewHandler(services, cfg, logger)
 	}
 	//if cfg.GRPCAddress != "" {
 	//	handlers.GRPC = grpc.NewHandler(services, logger)
@@ -60,3 +60,13 @@ func NewHandlers(services *service.Services, cfg config.Server, logger *logger.L
 
 	return handlers, nil
 }
+
+// IdleEvictors returns the in-memory state of the enabled handlers that a
+// [service.Janitor] has to evict.
+func (h *Handlers) IdleEvictors() []service.IdleEvictor {
+	var evictors []service.IdleEvictor
+	if h.HTTP != nil {
+		evictors = append(evictors, h.HTTP.IdleEvictors()...)
+	}
+	return evictors
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// getAuditLog returns a page of audit entries for the user given by the
+// "user_id" query parameter. "limit" and "offset" are optional.
+func (h *Handler) getAuditLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromRequest(r)
+
+	query := r.URL.Query()
+	userID, err := strconv.ParseInt(query.Get("user_id"), 10, 64)
+	if err != nil {
+		log.Err(err).Str("func", "*Handler.getAuditLog").Msg("invalid user_id")
+		http.Error(w, "invalid user_id", http.StatusBadRequest)
+		return
+	}
+
+	request := models.AuditLogRequest{UserID: userID}
+	for name, dst := range map[string]*int{"limit": &request.Limit, "offset": &request.Offset} {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		if *dst, err = strconv.Atoi(raw); err != nil {
+			log.Err(err).Str("func", "*Handler.getAuditLog").Msg("invalid " + name)
+			http.Error(w, "invalid "+name, http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := h.services.AuditService.GetAuditLog(ctx, request)
+	if err != nil {
+		log.Err(err).Str("func", "*Handler.getAuditLog").Msg("error getting audit log")
+		resp := responseFromError(err)
+		http.Error(w, resp.message, resp.status)
+		return
+	}
+
+	utils.WriteJSON(w, entries, http.StatusOK)
+}
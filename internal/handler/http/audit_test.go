@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuditSvc struct {
+	got     models.AuditLogRequest
+	entries []models.AuditEntry
+	err     error
+}
+
+func (m *mockAuditSvc) GetAuditLog(_ context.Context, request models.AuditLogRequest) ([]models.AuditEntry, error) {
+	m.got = request
+	return m.entries, m.err
+}
+
+func newAuditTestRouter(adminToken string, audit service.AuditService) http.Handler {
+	svcs := &service.Services{AuditService: audit}
+	return NewHandler(svcs, config.Server{AdminToken: adminToken}, logger.Nop()).Init()
+}
+
+func TestGetAuditLog_AdminAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		adminToken string
+		header     string
+		wantStatus int
+	}{
+		{name: "disabled without configured token", adminToken: "", header: "secret", wantStatus: http.StatusNotFound},
+		{name: "missing header", adminToken: "secret", header: "", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", adminToken: "secret", header: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "valid token", adminToken: "secret", header: "secret", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAuditTestRouter(tt.adminToken, &mockAuditSvc{})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/audit?user_id=7", nil)
+			if tt.header != "" {
+				req.Header.Set(adminTokenHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestGetAuditLog_Query(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		svcErr     error
+		wantStatus int
+		wantReq    models.AuditLogRequest
+	}{
+		{name: "user only", query: "user_id=7", wantStatus: http.StatusOK, wantReq: models.AuditLogRequest{UserID: 7}},
+		{name: "with pagination", query: "user_id=7&limit=10&offset=30", wantStatus: http.StatusOK, wantReq: models.AuditLogRequest{UserID: 7, Limit: 10, Offset: 30}},
+		{name: "missing user_id", query: "", wantStatus: http.StatusBadRequest},
+		{name: "invalid limit", query: "user_id=7&limit=abc", wantStatus: http.StatusBadRequest},
+		{name: "invalid offset", query: "user_id=7&offset=abc", wantStatus: http.StatusBadRequest},
+		{name: "service validation error", query: "user_id=7&offset=-1", svcErr: service.ErrInvalidDataProvided, wantStatus: http.StatusBadRequest, wantReq: models.AuditLogRequest{UserID: 7, Offset: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := []models.AuditEntry{{ID: 3, UserID: 7, Operation: models.AuditOperationUpdate, ClientSideID: "cid", Version: 2}}
+			svc := &mockAuditSvc{entries: entries, err: tt.svcErr}
+			router := newAuditTestRouter("secret", svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/audit?"+tt.query, nil)
+			req.Header.Set(adminTokenHeader, "secret")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.wantReq, svc.got)
+
+			var got []models.AuditEntry
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, entries, got)
+		})
+	}
+}
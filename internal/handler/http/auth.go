@@ -4,15 +4,25 @@
 package http
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/app"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
 	"github.com/MKhiriev/go-pass-keeper/internal/utils"
 	"github.com/MKhiriev/go-pass-keeper/models"
 )
 
+// minAuthHashLength is the length, in bytes, of the auth hash computed by the
+// client (SHA-256). Shorter hashes are refused at registration.
+const minAuthHashLength = 32
+
 func (h *Handler) register(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.FromRequest(r)
@@ -24,6 +34,12 @@ func (h *Handler) register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.checkKDFParameters(user); err != nil {
+		log.Err(err).Str("login", user.Login).Msg("registration with weak key derivation parameters rejected")
+		http.Error(w, app.MsgWeakKDFParameters, http.StatusBadRequest)
+		return
+	}
+
 	registeredUser, err := h.services.AuthService.RegisterUser(ctx, user)
 	if err != nil {
 		log.Err(err).Msg("error occurred during user registration")
@@ -44,6 +60,34 @@ func (h *Handler) register(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// checkKDFParameters verifies that the base64-encoded encryption salt and
+// auth hash of a registering user are at least [Handler.minSaltLength] and
+// [minAuthHashLength] bytes long. The server cannot see how the client
+// derived its keys; the lengths are what it can check to refuse clients
+// modified to use weak settings.
+func (h *Handler) checkKDFParameters(user models.User) error {
+	salt, err := base64.StdEncoding.DecodeString(user.EncryptionSalt)
+	if err != nil {
+		return fmt.Errorf("%w: encryption salt is not valid base64", ErrWeakKDFParameters)
+	}
+	if len(salt) < h.minSaltLength {
+		return fmt.Errorf("%w: encryption salt has %d bytes, at least %d required", ErrWeakKDFParameters, len(salt), h.minSaltLength)
+	}
+
+	authHash, err := base64.StdEncoding.DecodeString(user.AuthHash)
+	if err != nil {
+		return fmt.Errorf("%w: auth hash is not valid base64", ErrWeakKDFParameters)
+	}
+	if len(authHash) < minAuthHashLength {
+		return fmt.Errorf("%w: auth hash has %d bytes, at least %d required", ErrWeakKDFParameters, len(authHash), minAuthHashLength)
+	}
+	return nil
+}
+
+// login authenticates a user and returns a JWT in the Authorization header.
+// When the login lockout is enabled, a wrong password or an unknown login
+// counts as a failed attempt, and a locked login is rejected with 429 Too
+// Many Requests and a Retry-After header before its password is checked.
 func (h *Handler) login(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.FromRequest(r)
@@ -57,14 +101,25 @@ func (h *Handler) login(w http.ResponseWriter, r *http.Request) {
 
 	log.Debug().Any("received user info", user).Send()
 
+	if retryAfter, locked := h.loginLockout.retryAfter(user.Login); locked {
+		log.Warn().Str("login", user.Login).Int("retry_after", retryAfter).Msg("login rejected: too many failed attempts")
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		http.Error(w, app.MsgTooManyLoginAttempts, http.StatusTooManyRequests)
+		return
+	}
+
 	foundUser, err := h.services.AuthService.Login(ctx, user)
 	if err != nil {
 		log.Err(err).Msg("error occurred during user login")
+		if errors.Is(err, service.ErrWrongPassword) || errors.Is(err, store.ErrNoUserWasFound) {
+			h.loginLockout.fail(user.Login)
+		}
 		resp := responseFromError(err)
 		http.Error(w, resp.message, resp.status)
 		return
 
 	}
+	h.loginLockout.succeed(user.Login)
 
 	log.Debug().Int64("id", foundUser.UserID).Any("found user", foundUser).Msg("user successfully logged in")
 
@@ -5,6 +5,7 @@ package http
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -12,6 +13,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/app"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
@@ -65,7 +68,7 @@ func newHandlerWithAuth(t *testing.T, auth service.AuthService) *Handler {
 		AppInfoService: &mockAppInfoService{version: "test"},
 		AuthService:    auth,
 	}
-	return NewHandler(svcs, logger.Nop())
+	return NewHandler(svcs, config.Server{}, logger.Nop())
 }
 
 // userBody serialises a models.User to a JSON request body string.
@@ -81,10 +84,13 @@ func stubToken(signed string) models.Token {
 	return models.Token{SignedString: signed}
 }
 
-// validUser is a convenience fixture used across multiple tests.
+// validUser is a convenience fixture used across multiple tests. Its salt
+// and auth hash have the lengths generated by the client.
 var validUser = models.User{
 	Login:          "alice",
 	MasterPassword: "hashed-password",
+	EncryptionSalt: base64.StdEncoding.EncodeToString(make([]byte, 16)),
+	AuthHash:       base64.StdEncoding.EncodeToString(make([]byte, 32)),
 }
 
 // ─────────────────────────────────────────────
@@ -146,6 +152,64 @@ func TestRegister_EmptyBody(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
+// ─────────────────────────────────────────────
+// register — key derivation parameters
+// ─────────────────────────────────────────────
+
+// TestRegister_KDFParameters verifies that registrations whose encryption
+// salt or auth hash is shorter than required are rejected with 400 before
+// the service is called, and that acceptable ones are registered.
+func TestRegister_KDFParameters(t *testing.T) {
+	b64 := func(n int) string { return base64.StdEncoding.EncodeToString(make([]byte, n)) }
+
+	tests := []struct {
+		name          string
+		minSaltLength int
+		salt          string
+		authHash      string
+		wantStatus    int
+	}{
+		{name: "client defaults", salt: b64(16), authHash: b64(32), wantStatus: http.StatusOK},
+		{name: "longer salt", salt: b64(32), authHash: b64(32), wantStatus: http.StatusOK},
+		{name: "configured minimum met", minSaltLength: 24, salt: b64(24), authHash: b64(32), wantStatus: http.StatusOK},
+		{name: "short salt", salt: b64(8), authHash: b64(32), wantStatus: http.StatusBadRequest},
+		{name: "below configured minimum", minSaltLength: 24, salt: b64(16), authHash: b64(32), wantStatus: http.StatusBadRequest},
+		{name: "missing salt", authHash: b64(32), wantStatus: http.StatusBadRequest},
+		{name: "salt not base64", salt: "not base64!", authHash: b64(32), wantStatus: http.StatusBadRequest},
+		{name: "short auth hash", salt: b64(16), authHash: b64(16), wantStatus: http.StatusBadRequest},
+		{name: "missing auth hash", salt: b64(16), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registered := false
+			auth := &mockAuthService{
+				registerUserFn: func(_ context.Context, u models.User) (models.User, error) {
+					registered = true
+					return u, nil
+				},
+				createTokenFn: func(_ context.Context, _ models.User) (models.Token, error) {
+					return stubToken("signed.jwt.token"), nil
+				},
+			}
+			svcs := &service.Services{AppInfoService: &mockAppInfoService{version: "test"}, AuthService: auth}
+			h := NewHandler(svcs, config.Server{MinSaltLength: tt.minSaltLength}, logger.Nop())
+
+			user := models.User{Login: "alice", EncryptionSalt: tt.salt, AuthHash: tt.authHash}
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(userBody(t, user)))
+			rec := httptest.NewRecorder()
+
+			h.register(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantStatus == http.StatusOK, registered)
+			if tt.wantStatus == http.StatusBadRequest {
+				assert.Contains(t, rec.Body.String(), app.MsgWeakKDFParameters)
+			}
+		})
+	}
+}
+
 // ─────────────────────────────────────────────
 // register — RegisterUser errors
 // ─────────────────────────────────────────────
@@ -12,6 +12,17 @@ import (
 	"github.com/MKhiriev/go-pass-keeper/models"
 )
 
+// upload stores new vault items and responds 201 with their server-assigned
+// fields.
+//
+// A soft-deleted item keeps its client_side_id reserved until it is purged.
+// Uploading a new item under such an id responds 410 Gone; the client should
+// retry with a freshly generated client_side_id. Reusing the id of a live item
+// responds 409 Conflict. In both cases nothing from the batch is stored.
+// An item that fails validation responds 400 with a
+// [models.ValidationFailure] body naming its index and field.
+//
+// A request with best_effort set is handled by [Handler.uploadEach] instead.
 func (h *Handler) upload(w http.ResponseWriter, r *http.Request) {
 	log := logger.FromRequest(r)
 
@@ -22,15 +33,87 @@ func (h *Handler) upload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if uploadRequest.BestEffort {
+		h.uploadEach(w, r, uploadRequest)
+		return
+	}
+
 	err := h.services.PrivateDataService.UploadPrivateData(r.Context(), uploadRequest)
 	if err != nil {
 		log.Err(err).Str("func", "*Handler.upload").Msg("error uploading private data")
+		writeError(w, err)
+		return
+	}
+
+	utils.WriteJSON(w, newUploadResponse(uploadRequest.PrivateDataList), http.StatusCreated)
+}
+
+// uploadEach stores the items of a best-effort upload one by one. Items that
+// cannot be stored are listed in the response's "failed" entries with the
+// status and message a regular upload would have failed with; the stored ones
+// are listed in "items" as usual; an item that failed validation also names
+// the invalid field. It responds 201 when every item was stored
+// and 207 Multi-Status otherwise. Request-level errors, such as a length
+// mismatch, fail the whole request like a regular upload.
+func (h *Handler) uploadEach(w http.ResponseWriter, r *http.Request, uploadRequest models.UploadRequest) {
+	log := logger.FromRequest(r)
+
+	errs, err := h.services.PrivateDataService.UploadPrivateDataEach(r.Context(), uploadRequest)
+	if err != nil {
+		log.Err(err).Str("func", "*Handler.uploadEach").Msg("error uploading private data")
 		resp := responseFromError(err)
 		http.Error(w, resp.message, resp.status)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	stored := make([]*models.PrivateData, 0, len(uploadRequest.PrivateDataList))
+	var failed []models.UploadFailure
+	for i, data := range uploadRequest.PrivateDataList {
+		if i >= len(errs) || errs[i] == nil {
+			stored = append(stored, data)
+			continue
+		}
+
+		clientSideID := ""
+		if data != nil {
+			clientSideID = data.ClientSideID
+		}
+		log.Warn().Err(errs[i]).Str("func", "*Handler.uploadEach").Str("client_side_id", clientSideID).Msg("item of best-effort upload was not stored")
+		resp := responseFromError(errs[i])
+		failure := models.UploadFailure{ClientSideID: clientSideID, Status: resp.status, Error: resp.message}
+		if invalid, ok := validationFailure(errs[i], resp.message); ok {
+			failure.Error = resp.message + ": " + invalid.Reason
+			failure.Field = invalid.Field
+		}
+		failed = append(failed, failure)
+	}
+
+	response := newUploadResponse(stored)
+	response.Failed = failed
+
+	status := http.StatusCreated
+	if len(failed) > 0 {
+		status = http.StatusMultiStatus
+	}
+	utils.WriteJSON(w, response, status)
+}
+
+// newUploadResponse collects the server-assigned ID and timestamps that the
+// storage layer wrote back into each uploaded item.
+func newUploadResponse(list []*models.PrivateData) models.UploadResponse {
+	items := make([]models.UploadedItem, 0, len(list))
+	for _, data := range list {
+		if data == nil {
+			continue
+		}
+		items = append(items, models.UploadedItem{
+			ID:           data.ID,
+			ClientSideID: data.ClientSideID,
+			CreatedAt:    data.CreatedAt,
+			UpdatedAt:    data.UpdatedAt,
+		})
+	}
+	return models.UploadResponse{Items: items, Length: len(items)}
 }
 
 func (h *Handler) downloadMultiple(w http.ResponseWriter, r *http.Request) {
@@ -76,6 +159,12 @@ func (h *Handler) downloadAllUserData(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, requestedData, http.StatusOK)
 }
 
+// update applies partial updates with optimistic locking. A stale version
+// responds 409 Conflict with the conflict headers set by
+// [setConflictHeaders]; an update of a soft-deleted item responds 410 Gone,
+// because tombstones are never resurrected by an update. An update that fails
+// validation responds 400 with a [models.ValidationFailure] body naming its
+// index and field.
 func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	log := logger.FromRequest(r)
 
@@ -89,14 +178,16 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	err := h.services.PrivateDataService.UpdatePrivateData(r.Context(), dataArrayFromBody)
 	if err != nil {
 		log.Err(err).Str("func", "*Handler.update").Msg("error updating private data")
-		resp := responseFromError(err)
-		http.Error(w, resp.message, resp.status)
+		setConflictHeaders(w, err)
+		writeError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
+// delete soft-deletes items with optimistic locking. A stale version responds
+// 409 Conflict with the conflict headers set by [setConflictHeaders].
 func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 	log := logger.FromRequest(r)
 
@@ -111,9 +202,67 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Err(err).Str("func", "*Handler.update").Msg("error deleting private data")
 		resp := responseFromError(err)
+		setConflictHeaders(w, err)
 		http.Error(w, resp.message, resp.status)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// restore clears the deleted flag of soft-deleted items with optimistic
+// locking. An item that no longer exists responds 404 Not Found; a stale
+// version responds 409 Conflict with the conflict headers set by
+// [setConflictHeaders].
+func (h *Handler) restore(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromRequest(r)
+
+	var dataArrayFromBody models.RestoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&dataArrayFromBody); err != nil {
+		log.Err(err).Str("func", "*Handler.restore").Msg("Invalid JSON was passed")
+		http.Error(w, "Invalid JSON was passed", http.StatusBadRequest)
+		return
+	}
+
+	err := h.services.PrivateDataService.RestorePrivateData(r.Context(), dataArrayFromBody)
+	if err != nil {
+		log.Err(err).Str("func", "*Handler.restore").Msg("error restoring private data")
+		setConflictHeaders(w, err)
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// getVersionHistory returns the archived previous versions of the vault item
+// given by the "client_side_id" query parameter, newest first. The list is
+// empty when the server keeps no version history.
+func (h *Handler) getVersionHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromRequest(r)
+
+	userID, found := utils.GetUserIDFromContext(ctx)
+	if !found {
+		log.Error().Str("func", "*Handler.getVersionHistory").Msg("no user ID was given")
+		http.Error(w, "no user ID was given", http.StatusBadRequest)
+		return
+	}
+
+	clientSideID := r.URL.Query().Get("client_side_id")
+	if clientSideID == "" {
+		log.Error().Str("func", "*Handler.getVersionHistory").Msg("no client_side_id was given")
+		http.Error(w, "no client_side_id was given", http.StatusBadRequest)
+		return
+	}
+
+	versions, err := h.services.PrivateDataService.GetVersionHistory(ctx, userID, clientSideID)
+	if err != nil {
+		log.Err(err).Str("func", "*Handler.getVersionHistory").Msg("error getting version history")
+		resp := responseFromError(err)
+		http.Error(w, resp.message, resp.status)
+		return
+	}
+
+	utils.WriteJSON(w, versions, http.StatusOK)
+}
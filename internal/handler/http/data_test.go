@@ -8,14 +8,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/app"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
 	"github.com/MKhiriev/go-pass-keeper/internal/utils"
 	"github.com/MKhiriev/go-pass-keeper/models"
 	"github.com/stretchr/testify/assert"
@@ -93,6 +97,45 @@ func TestUpload_Success(t *testing.T) {
 	assert.Equal(t, http.StatusCreated, rec.Code)
 }
 
+func TestUpload_ReturnsServerFields(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	svc := &mockPrivateDataSvc{
+		uploadFn: func(_ context.Context, req models.UploadRequest) error {
+			// The storage layer writes the generated fields back into the list.
+			for i, data := range req.PrivateDataList {
+				data.ID = int64(100 + i)
+				data.CreatedAt = &createdAt
+			}
+			return nil
+		},
+	}
+
+	h := newHandlerForData(t, svc)
+	body := models.UploadRequest{
+		UserID:          1,
+		PrivateDataList: []*models.PrivateData{{ClientSideID: "a"}, {ClientSideID: "b"}},
+		Length:          2,
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/data/", encodeBody(t, body))
+	rec := httptest.NewRecorder()
+
+	h.upload(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var got models.UploadResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	require.Len(t, got.Items, 2)
+	assert.Equal(t, 2, got.Length)
+	assert.Equal(t, int64(100), got.Items[0].ID)
+	assert.Equal(t, "a", got.Items[0].ClientSideID)
+	assert.Equal(t, int64(101), got.Items[1].ID)
+	assert.Equal(t, "b", got.Items[1].ClientSideID)
+	require.NotNil(t, got.Items[0].CreatedAt)
+	assert.True(t, createdAt.Equal(*got.Items[0].CreatedAt))
+	assert.Nil(t, got.Items[0].UpdatedAt)
+}
+
 func TestUpload_InvalidJSON(t *testing.T) {
 	h := newHandlerForData(t, &mockPrivateDataSvc{})
 	req := httptest.NewRequest(http.MethodPost, "/api/data/", strings.NewReader(`{bad json}`))
@@ -131,6 +174,284 @@ func TestUpload_ServiceError(t *testing.T) {
 	assert.Contains(t, rec.Body.String(), "internal server error")
 }
 
+// TestUpload_InitialVersion runs uploads through the real validation layer:
+// a new item must start at version 0.
+func TestUpload_InitialVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    int64
+		wantStatus int
+		wantStored bool
+	}{
+		{name: "version 0 accepted", version: 0, wantStatus: http.StatusCreated, wantStored: true},
+		{name: "non-zero version rejected", version: 5, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := false
+			inner := &mockPrivateDataSvc{
+				uploadFn: func(_ context.Context, _ models.UploadRequest) error {
+					stored = true
+					return nil
+				},
+			}
+			h := newHandlerForData(t, service.NewPrivateDataValidationService(0, 0).Wrap(inner))
+
+			body := models.UploadRequest{
+				UserID: 1,
+				PrivateDataList: []*models.PrivateData{{
+					ClientSideID: "abc",
+					UserID:       1,
+					Payload: models.PrivateDataPayload{
+						Metadata: "meta",
+						Type:     models.LoginPassword,
+						Data:     "data",
+					},
+					Hash:    "hash",
+					Version: tt.version,
+				}},
+				Length: 1,
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/data/", encodeBody(t, body)).WithContext(ctxWithUser(1))
+			rec := httptest.NewRecorder()
+
+			h.upload(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantStored, stored)
+		})
+	}
+}
+
+func TestUpload_StructuredValidationError(t *testing.T) {
+	validItem := func(clientSideID string) *models.PrivateData {
+		return &models.PrivateData{
+			ClientSideID: clientSideID,
+			UserID:       1,
+			Payload:      models.PrivateDataPayload{Metadata: "meta", Type: models.LoginPassword, Data: "data"},
+			Hash:         "hash",
+		}
+	}
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name        string
+		mutate      func(items []*models.PrivateData) int
+		wantFailure *models.ValidationFailure
+	}{
+		{
+			name: "bad client_side_id of the second item",
+			mutate: func(items []*models.PrivateData) int {
+				items[1].ClientSideID = ""
+				return len(items)
+			},
+			wantFailure: &models.ValidationFailure{Message: app.MsgInvalidDataProvided, Index: intPtr(1), Field: "client_side_id", Reason: "invalid client side id"},
+		},
+		{
+			name: "non-zero version of the third item",
+			mutate: func(items []*models.PrivateData) int {
+				items[2].Version = 4
+				return len(items)
+			},
+			wantFailure: &models.ValidationFailure{Message: app.MsgInvalidDataProvided, Index: intPtr(2), Field: "version", Reason: "invalid Version"},
+		},
+		{
+			name: "request-level error stays plain text",
+			mutate: func(items []*models.PrivateData) int {
+				return len(items) + 1
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &mockPrivateDataSvc{
+				uploadFn: func(_ context.Context, _ models.UploadRequest) error {
+					t.Fatal("an invalid batch must not be stored")
+					return nil
+				},
+			}
+			h := newHandlerForData(t, service.NewPrivateDataValidationService(0, 0).Wrap(inner))
+
+			items := []*models.PrivateData{validItem("a"), validItem("b"), validItem("c")}
+			body := models.UploadRequest{UserID: 1, PrivateDataList: items, Length: tt.mutate(items)}
+			req := httptest.NewRequest(http.MethodPost, "/api/data/", encodeBody(t, body)).WithContext(ctxWithUser(1))
+			rec := httptest.NewRecorder()
+
+			h.upload(rec, req)
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			if tt.wantFailure == nil {
+				assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+				assert.Equal(t, app.MsgInvalidDataProvided, strings.TrimSpace(rec.Body.String()))
+				return
+			}
+
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			var failure models.ValidationFailure
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&failure))
+			assert.Equal(t, *tt.wantFailure, failure)
+		})
+	}
+}
+
+func TestUpload_BestEffortNamesInvalidField(t *testing.T) {
+	inner := &mockPrivateDataSvc{
+		uploadEachFn: func(_ context.Context, req models.UploadRequest) ([]error, error) {
+			return make([]error, len(req.PrivateDataList)), nil
+		},
+	}
+	h := newHandlerForData(t, service.NewPrivateDataValidationService(0, 0).Wrap(inner))
+
+	body := models.UploadRequest{
+		UserID: 1,
+		PrivateDataList: []*models.PrivateData{
+			{ClientSideID: "a", UserID: 1, Payload: models.PrivateDataPayload{Metadata: "meta", Type: models.Text, Data: "data"}, Hash: "hash"},
+			{ClientSideID: "b", UserID: 1, Payload: models.PrivateDataPayload{Type: models.Text, Data: "data"}, Hash: "hash"},
+		},
+		Length:     2,
+		BestEffort: true,
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/data/", encodeBody(t, body)).WithContext(ctxWithUser(1))
+	rec := httptest.NewRecorder()
+
+	h.upload(rec, req)
+
+	require.Equal(t, http.StatusMultiStatus, rec.Code)
+	var resp models.UploadResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, []models.UploadFailure{{
+		ClientSideID: "b",
+		Status:       http.StatusBadRequest,
+		Error:        app.MsgInvalidDataProvided + ": metadata is required",
+		Field:        "metadata",
+	}}, resp.Failed)
+}
+
+func TestUpload_BestEffort(t *testing.T) {
+	tests := []struct {
+		name       string
+		errs       []error
+		svcErr     error
+		wantStatus int
+		wantItems  []string
+		wantFailed []models.UploadFailure
+	}{
+		{
+			name:       "all stored",
+			errs:       []error{nil, nil, nil},
+			wantStatus: http.StatusCreated,
+			wantItems:  []string{"a", "b", "c"},
+		},
+		{
+			name:       "mixed success and failure",
+			errs:       []error{nil, store.ErrPrivateDataAlreadyExists, store.ErrPrivateDataTombstoned},
+			wantStatus: http.StatusMultiStatus,
+			wantItems:  []string{"a"},
+			wantFailed: []models.UploadFailure{
+				{ClientSideID: "b", Status: http.StatusConflict, Error: app.MsgDataAlreadyExists},
+				{ClientSideID: "c", Status: http.StatusGone, Error: app.MsgDataTombstoned},
+			},
+		},
+		{
+			name:       "request error",
+			svcErr:     service.ErrInvalidDataProvided,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strictCalled := false
+			svc := &mockPrivateDataSvc{
+				uploadFn: func(_ context.Context, _ models.UploadRequest) error {
+					strictCalled = true
+					return nil
+				},
+				uploadEachFn: func(_ context.Context, req models.UploadRequest) ([]error, error) {
+					assert.True(t, req.BestEffort)
+					for i, data := range req.PrivateDataList {
+						data.ID = int64(i + 1)
+					}
+					return tt.errs, tt.svcErr
+				},
+			}
+
+			h := newHandlerForData(t, svc)
+			body := models.UploadRequest{
+				UserID: 1,
+				PrivateDataList: []*models.PrivateData{
+					{ClientSideID: "a"}, {ClientSideID: "b"}, {ClientSideID: "c"},
+				},
+				Length:     3,
+				BestEffort: true,
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/data/", encodeBody(t, body))
+			rec := httptest.NewRecorder()
+
+			h.upload(rec, req)
+
+			assert.False(t, strictCalled)
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.svcErr != nil {
+				return
+			}
+
+			var resp models.UploadResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			ids := make([]string, 0, len(resp.Items))
+			for _, item := range resp.Items {
+				ids = append(ids, item.ClientSideID)
+			}
+			assert.Equal(t, tt.wantItems, ids)
+			assert.Equal(t, len(tt.wantItems), resp.Length)
+			assert.Equal(t, tt.wantFailed, resp.Failed)
+		})
+	}
+}
+
+func TestUpload_StoreConflicts(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "tombstoned client_side_id",
+			err:        store.ErrPrivateDataTombstoned,
+			wantStatus: http.StatusGone,
+			wantBody:   app.MsgDataTombstoned,
+		},
+		{
+			name:       "live client_side_id",
+			err:        store.ErrPrivateDataAlreadyExists,
+			wantStatus: http.StatusConflict,
+			wantBody:   app.MsgDataAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockPrivateDataSvc{
+				uploadFn: func(_ context.Context, _ models.UploadRequest) error {
+					return fmt.Errorf("failed to save private data at index 0: %w", tt.err)
+				},
+			}
+
+			h := newHandlerForData(t, svc)
+			req := httptest.NewRequest(http.MethodPost, "/api/data/", encodeBody(t, models.UploadRequest{UserID: 1}))
+			rec := httptest.NewRecorder()
+
+			h.upload(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
+
 // ─────────────────────────────────────────────
 // downloadMultiple
 // ─────────────────────────────────────────────
@@ -162,6 +483,24 @@ func TestDownloadMultiple_Success(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestDownloadMultiple_PassesTypesFilter(t *testing.T) {
+	svc := &mockPrivateDataSvc{
+		downloadFn: func(_ context.Context, req models.DownloadRequest) ([]models.PrivateData, error) {
+			assert.Equal(t, []models.DataType{models.BankCard}, req.Types)
+			return []models.PrivateData{}, nil
+		},
+	}
+
+	h := newHandlerForData(t, svc)
+	req := httptest.NewRequest(http.MethodPost, "/api/data/download",
+		strings.NewReader(`{"user_id":1,"types":[4]}`))
+	rec := httptest.NewRecorder()
+
+	h.downloadMultiple(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
 func TestDownloadMultiple_EmptyResult(t *testing.T) {
 	svc := &mockPrivateDataSvc{
 		downloadFn: func(_ context.Context, _ models.DownloadRequest) ([]models.PrivateData, error) {
@@ -318,6 +657,39 @@ func TestUpdate_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestUpdate_StructuredValidationError(t *testing.T) {
+	inner := &mockPrivateDataSvc{
+		updateFn: func(_ context.Context, _ models.UpdateRequest) error {
+			t.Fatal("an invalid batch must not be applied")
+			return nil
+		},
+	}
+	h := newHandlerForData(t, service.NewPrivateDataValidationService(0, 0).Wrap(inner))
+
+	metadata := models.CipheredMetadata("meta")
+	body := models.UpdateRequest{
+		UserID: 1,
+		PrivateDataUpdates: []models.PrivateDataUpdate{
+			{ClientSideID: "a", Version: 1, UpdatedRecordHash: "hash", FieldsUpdate: models.FieldsUpdate{Metadata: &metadata}},
+			{ClientSideID: "b", Version: 1, FieldsUpdate: models.FieldsUpdate{Metadata: &metadata}},
+		},
+		Length: 2,
+	}
+	req := httptest.NewRequest(http.MethodPut, "/api/data/update", encodeBody(t, body)).WithContext(ctxWithUser(1))
+	rec := httptest.NewRecorder()
+
+	h.update(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	var failure models.ValidationFailure
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&failure))
+	require.NotNil(t, failure.Index)
+	assert.Equal(t, 1, *failure.Index)
+	assert.Equal(t, "updated_record_hash", failure.Field)
+	assert.Equal(t, "invalid updated record hash", failure.Reason)
+	assert.Equal(t, app.MsgInvalidDataProvided, failure.Message)
+}
+
 func TestUpdate_InvalidJSON(t *testing.T) {
 	h := newHandlerForData(t, &mockPrivateDataSvc{})
 	req := httptest.NewRequest(http.MethodPut, "/api/data/update", strings.NewReader(`{bad json}`))
@@ -357,6 +729,24 @@ func TestUpdate_ServiceError(t *testing.T) {
 	assert.Contains(t, rec.Body.String(), "internal server error")
 }
 
+func TestUpdate_SyncLocked(t *testing.T) {
+	svc := &mockPrivateDataSvc{
+		updateFn: func(_ context.Context, _ models.UpdateRequest) error {
+			return fmt.Errorf("update: %w", store.ErrSyncLocked)
+		},
+	}
+
+	h := newHandlerForData(t, svc)
+	req := httptest.NewRequest(http.MethodPut, "/api/data/update",
+		encodeBody(t, models.UpdateRequest{UserID: 1}))
+	rec := httptest.NewRecorder()
+
+	h.update(rec, req)
+
+	assert.Equal(t, http.StatusLocked, rec.Code)
+	assert.Contains(t, rec.Body.String(), app.MsgSyncLocked)
+}
+
 // ─────────────────────────────────────────────
 // delete
 // ─────────────────────────────────────────────
@@ -427,3 +817,251 @@ func TestDelete_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	assert.Contains(t, rec.Body.String(), "internal server error")
 }
+
+// ─────────────────────────────────────────────
+// restore
+// ─────────────────────────────────────────────
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       io.Reader
+		restoreErr error
+		wantStatus int
+		wantBody   string
+		wantCalled bool
+	}{
+		{
+			name: "success",
+			body: encodeBody(t, models.RestoreRequest{
+				UserID:         3,
+				RestoreEntries: []models.RestoreEntry{{ClientSideID: "del-1", Version: 2}},
+				Length:         1,
+			}),
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "invalid JSON",
+			body:       strings.NewReader(`{bad json}`),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid JSON was passed",
+		},
+		{
+			name:       "item not found",
+			body:       encodeBody(t, models.RestoreRequest{UserID: 1}),
+			restoreErr: fmt.Errorf("restore: %w", store.ErrPrivateDataNotFound),
+			wantStatus: http.StatusNotFound,
+			wantBody:   app.MsgDataNotFound,
+			wantCalled: true,
+		},
+		{
+			name:       "item not deleted",
+			body:       encodeBody(t, models.RestoreRequest{UserID: 1}),
+			restoreErr: fmt.Errorf("restore: %w", store.ErrPrivateDataNotDeleted),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   app.MsgDataNotDeleted,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			svc := &mockPrivateDataSvc{
+				restoreFn: func(_ context.Context, _ models.RestoreRequest) error {
+					called = true
+					return tt.restoreErr
+				},
+			}
+			rec := httptest.NewRecorder()
+
+			newHandlerForData(t, svc).restore(rec, httptest.NewRequest(http.MethodPost, "/api/data/restore", tt.body))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantCalled, called)
+		})
+	}
+}
+
+func TestVersionConflict_SetsConflictHeaders(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation models.ConflictOperation
+		call      func(h *Handler, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "update",
+			operation: models.ConflictOnUpdate,
+			call: func(h *Handler, rec *httptest.ResponseRecorder) {
+				h.update(rec, httptest.NewRequest(http.MethodPut, "/api/data/update",
+					encodeBody(t, models.UpdateRequest{UserID: 1})))
+			},
+		},
+		{
+			name:      "delete",
+			operation: models.ConflictOnDelete,
+			call: func(h *Handler, rec *httptest.ResponseRecorder) {
+				h.delete(rec, httptest.NewRequest(http.MethodDelete, "/api/data/delete",
+					encodeBody(t, models.DeleteRequest{UserID: 1})))
+			},
+		},
+		{
+			name:      "restore",
+			operation: models.ConflictOnRestore,
+			call: func(h *Handler, rec *httptest.ResponseRecorder) {
+				h.restore(rec, httptest.NewRequest(http.MethodPost, "/api/data/restore",
+					encodeBody(t, models.RestoreRequest{UserID: 1})))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflict := &store.VersionConflictError{VersionConflict: models.VersionConflict{
+				Operation:     tt.operation,
+				ClientSideID:  "item-1",
+				ServerVersion: 4,
+			}}
+			svc := &mockPrivateDataSvc{
+				updateFn: func(_ context.Context, _ models.UpdateRequest) error {
+					return fmt.Errorf("update: %w", conflict)
+				},
+				deleteFn: func(_ context.Context, _ models.DeleteRequest) error {
+					return fmt.Errorf("delete: %w", conflict)
+				},
+				restoreFn: func(_ context.Context, _ models.RestoreRequest) error {
+					return fmt.Errorf("restore: %w", conflict)
+				},
+			}
+			rec := httptest.NewRecorder()
+
+			tt.call(newHandlerForData(t, svc), rec)
+
+			assert.Equal(t, http.StatusConflict, rec.Code)
+			assert.Contains(t, rec.Body.String(), app.MsgVersionConflict)
+			assert.Equal(t, string(tt.operation), rec.Header().Get(utils.ConflictOperationHeader))
+			assert.Equal(t, "item-1", rec.Header().Get(utils.ConflictClientSideIDHeader))
+			assert.Equal(t, "4", rec.Header().Get(utils.ServerVersionHeader))
+		})
+	}
+}
+
+// ─────────────────────────────────────────────
+// getVersionHistory
+// ─────────────────────────────────────────────
+
+func TestGetVersionHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		query      string
+		serviceErr error
+		wantStatus int
+		wantCalled bool
+	}{
+		{name: "success", ctx: ctxWithUser(7), query: "?client_side_id=cid-1", wantStatus: http.StatusOK, wantCalled: true},
+		{name: "no user in context", ctx: context.Background(), query: "?client_side_id=cid-1", wantStatus: http.StatusBadRequest},
+		{name: "no client_side_id", ctx: ctxWithUser(7), wantStatus: http.StatusBadRequest},
+		{
+			name:       "service error",
+			ctx:        ctxWithUser(7),
+			query:      "?client_side_id=cid-1",
+			serviceErr: store.ErrExecutingQuery,
+			wantStatus: http.StatusInternalServerError,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			svc := &mockPrivateDataSvc{
+				historyFn: func(_ context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error) {
+					called = true
+					assert.Equal(t, int64(7), userID)
+					assert.Equal(t, "cid-1", clientSideID)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return []models.PrivateDataVersion{{ClientSideID: "cid-1", Version: 2}}, nil
+				},
+			}
+
+			h := newHandlerForData(t, svc)
+			req := httptest.NewRequest(http.MethodGet, "/api/data/history"+tt.query, nil).WithContext(tt.ctx)
+			rec := httptest.NewRecorder()
+
+			h.getVersionHistory(rec, req)
+
+			assert.Equal(t, tt.wantCalled, called)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				var got []models.PrivateDataVersion
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+				require.Len(t, got, 1)
+				assert.Equal(t, int64(2), got[0].Version)
+			}
+		})
+	}
+}
+
+// ─────────────────────────────────────────────
+// getAllClientSideIDs
+// ─────────────────────────────────────────────
+
+func TestGetAllClientSideIDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		serviceErr error
+		wantStatus int
+		wantCalled bool
+	}{
+		{name: "success", ctx: ctxWithUser(7), wantStatus: http.StatusOK, wantCalled: true},
+		{name: "no user in context", ctx: context.Background(), wantStatus: http.StatusBadRequest},
+		{
+			name:       "service error",
+			ctx:        ctxWithUser(7),
+			serviceErr: store.ErrExecutingQuery,
+			wantStatus: http.StatusInternalServerError,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			svc := &mockPrivateDataSvc{
+				idsFn: func(_ context.Context, userID int64) ([]models.ClientSideIDState, error) {
+					called = true
+					assert.Equal(t, int64(7), userID)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return []models.ClientSideIDState{
+						{ClientSideID: "cid-1"},
+						{ClientSideID: "cid-2", Deleted: true},
+					}, nil
+				},
+			}
+
+			h := newHandlerForData(t, svc)
+			req := httptest.NewRequest(http.MethodGet, "/api/sync/ids", nil).WithContext(tt.ctx)
+			rec := httptest.NewRecorder()
+
+			h.getAllClientSideIDs(rec, req)
+
+			assert.Equal(t, tt.wantCalled, called)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				var got []models.ClientSideIDState
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+				require.Len(t, got, 2)
+				assert.False(t, got[0].Deleted)
+				assert.True(t, got[1].Deleted)
+			}
+		})
+	}
+}
@@ -7,4 +7,8 @@
 // API. Cross-cutting concerns such as authentication, request tracing, access
 // logging, response compression, and integrity checks are handled in this
 // package before requests are delegated to the service layer.
+//
+// The integrity checks here verify the transport HMAC of upload and update
+// requests. The per-item Hash is stored as sent and verified by the client
+// when it downloads the item.
 package http
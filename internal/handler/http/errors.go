@@ -6,7 +6,8 @@ package http
 import "errors"
 
 // Sentinel errors used by the authentication middleware when parsing the
-// "Authorization" HTTP header. Callers can match against them with [errors.Is].
+// "Authorization" HTTP header and by the register handler. Callers can match
+// against them with [errors.Is].
 var (
 	// ErrEmptyAuthorizationHeader is returned by the auth middleware when the
 	// incoming request does not include an "Authorization" header at all.
@@ -20,4 +21,9 @@ var (
 	// ErrEmptyToken is returned when the "Authorization" header contains the
 	// expected scheme prefix but the token value itself is an empty string.
 	ErrEmptyToken = errors.New("empty token in `Authorization` header")
+
+	// ErrWeakKDFParameters is returned by [Handler.checkKDFParameters] when a
+	// registration carries an encryption salt or auth hash that is not valid
+	// base64 or is shorter than the configured minimum.
+	ErrWeakKDFParameters = errors.New("weak key derivation parameters")
 )
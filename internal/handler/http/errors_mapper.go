@@ -6,10 +6,14 @@ package http
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/app"
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/MKhiriev/go-pass-keeper/internal/validators"
+	"github.com/MKhiriev/go-pass-keeper/models"
 )
 
 type errorResponse struct {
@@ -30,6 +34,7 @@ var errorStatusMap = map[error]errorResponse{
 	service.ErrValidationNoUserID:                             {message: app.MsgNoUserIDProvided, status: http.StatusBadRequest},
 	service.ErrValidationNoClientIDsProvidedForSyncRequests:   {message: app.MsgNoClientIDsForSync, status: http.StatusBadRequest},
 	service.ErrValidationEmptyClientIDProvidedForSyncRequests: {message: app.MsgEmptyClientIDForSync, status: http.StatusBadRequest},
+	service.ErrValidationInvalidPage:                          {message: app.MsgInvalidPage, status: http.StatusBadRequest},
 	service.ErrUnauthorizedAccessToDifferentUserData:          {message: app.MsgAccessDenied, status: http.StatusForbidden},
 	service.ErrVersionIsNotSpecified:                          {message: app.MsgVersionIsNotSpecified, status: http.StatusBadRequest},
 	service.ErrRegisterOnServer:                               {message: app.MsgRegistrationFailed, status: http.StatusBadGateway},
@@ -41,6 +46,10 @@ var errorStatusMap = map[error]errorResponse{
 	store.ErrPrivateDataNotFound: {message: app.MsgDataNotFound, status: http.StatusNotFound},
 	store.ErrVersionConflict:     {message: app.MsgVersionConflict, status: http.StatusConflict},
 
+	store.ErrPrivateDataTombstoned:    {message: app.MsgDataTombstoned, status: http.StatusGone},
+	store.ErrPrivateDataAlreadyExists: {message: app.MsgDataAlreadyExists, status: http.StatusConflict},
+	store.ErrPrivateDataNotDeleted:    {message: app.MsgDataNotDeleted, status: http.StatusUnprocessableEntity},
+
 	store.ErrBuildingSQLQuery:     {message: app.MsgInternalServerError, status: http.StatusInternalServerError},
 	store.ErrExecutingQuery:       {message: app.MsgInternalServerError, status: http.StatusInternalServerError},
 	store.ErrBeginningTransaction: {message: app.MsgInternalServerError, status: http.StatusInternalServerError},
@@ -49,6 +58,24 @@ var errorStatusMap = map[error]errorResponse{
 	store.ErrExecutingStatement:   {message: app.MsgInternalServerError, status: http.StatusInternalServerError},
 	store.ErrScanningRow:          {message: app.MsgInternalServerError, status: http.StatusInternalServerError},
 	store.ErrScanningRows:         {message: app.MsgInternalServerError, status: http.StatusInternalServerError},
+	store.ErrWritingAuditEntry:    {message: app.MsgInternalServerError, status: http.StatusInternalServerError},
+
+	store.ErrWritingVersionHistory: {message: app.MsgInternalServerError, status: http.StatusInternalServerError},
+	store.ErrTooManyClientSideIDs:  {message: app.MsgTooManyClientSideIDs, status: http.StatusBadRequest},
+	store.ErrSyncLocked:            {message: app.MsgSyncLocked, status: http.StatusLocked},
+}
+
+// setConflictHeaders describes a [store.VersionConflictError] in err with the
+// conflict headers of [utils.ConflictOperationHeader] and its siblings. It
+// does nothing for other errors.
+func setConflictHeaders(w http.ResponseWriter, err error) {
+	var conflict *store.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return
+	}
+	w.Header().Set(utils.ConflictOperationHeader, string(conflict.Operation))
+	w.Header().Set(utils.ConflictClientSideIDHeader, conflict.ClientSideID)
+	w.Header().Set(utils.ServerVersionHeader, strconv.FormatInt(conflict.ServerVersion, 10))
 }
 
 func responseFromError(err error) errorResponse {
@@ -59,3 +86,34 @@ func responseFromError(err error) errorResponse {
 	}
 	return errorResponse{message: app.MsgInternalServerError, status: http.StatusInternalServerError}
 }
+
+// validationFailure describes the [validators.FieldError] in err as the body
+// of a 400 response carrying message. ok is false when err has none.
+func validationFailure(err error, message string) (failure models.ValidationFailure, ok bool) {
+	var fieldErr *validators.FieldError
+	if !errors.As(err, &fieldErr) {
+		return models.ValidationFailure{}, false
+	}
+
+	failure = models.ValidationFailure{Message: message, Field: fieldErr.Field, Reason: fieldErr.Err.Error()}
+	if fieldErr.Index >= 0 {
+		index := fieldErr.Index
+		failure.Index = &index
+	}
+	return failure, true
+}
+
+// writeError responds with the status and message [responseFromError] maps
+// err to. A 400 caused by a [validators.FieldError] gets a
+// [models.ValidationFailure] JSON body naming the item and field instead of
+// the plain-text message.
+func writeError(w http.ResponseWriter, err error) {
+	resp := responseFromError(err)
+	if resp.status == http.StatusBadRequest {
+		if failure, ok := validationFailure(err, resp.message); ok {
+			utils.WriteJSON(w, failure, resp.status)
+			return
+		}
+	}
+	http.Error(w, resp.message, resp.status)
+}
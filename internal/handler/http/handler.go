@@ -4,8 +4,10 @@
 package http
 
 import (
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/rs/zerolog"
 )
 
 // Handler is the root HTTP handler that wires together all route groups
@@ -27,22 +29,92 @@ type Handler struct {
 	// logger is the structured logger used by the handler and all middleware
 	// for request-scoped and diagnostic log output.
 	logger *logger.Logger
+
+	// adminToken is the shared secret checked by [Handler.adminAuth]. Admin
+	// routes respond with 404 when it is empty.
+	adminToken string
+
+	// separateAdmin is set when cfg.AdminAddress is configured: the admin
+	// routes are then left out of [Handler.Init] and served by
+	// [Handler.InitAdmin] only.
+	separateAdmin bool
+
+	// accessLogLevel is the level of the per-request line written by
+	// withLogging, taken from cfg.AccessLogLevel.
+	accessLogLevel zerolog.Level
+
+	// basePath is the prefix all API routes are registered under, taken
+	// from cfg.BasePath. Empty means [config.DefaultServerBasePath].
+	basePath string
+
+	// minSaltLength is the smallest encryption salt, in bytes, accepted by
+	// the register handler, taken from cfg.MinSaltLength.
+	minSaltLength int
+
+	// maxInFlight is the number of requests processed at once, taken from
+	// cfg.MaxInFlight. Zero or less disables the limit.
+	maxInFlight int
+
+	// gzip tunes the response compression, taken from cfg.GzipLevel and
+	// cfg.GzipMinSize.
+	gzip gzipOptions
+
+	// loginLockout locks a login after too many failed attempts, taken from
+	// cfg.LoginMaxAttempts and its siblings. Nil when disabled.
+	loginLockout *loginLockout
 }
 
-// NewHandler constructs a [Handler] with the provided service container and
-// logger, and returns a pointer to the initialised instance.
+// NewHandler constructs a [Handler] with the provided service container,
+// server configuration and logger, and returns a pointer to the initialised
+// instance.
 //
 // The logger is used immediately to emit a debug-level startup message and
 // is stored for use by all route handlers and middleware registered on this
 // Handler.
 //
 // Parameters:
-//   - services: the application service layer; must not be nil.
+//   - services: the application service layer; must not be nil. Its Clock
+//     times the login lockout.
+//   - cfg: server settings; cfg.AdminToken enables the admin routes and
+//     cfg.AccessLogLevel sets the access-log level (info when empty or invalid);
+//     cfg.BasePath sets the API route prefix; cfg.MinSaltLength sets the
+//     encryption salt minimum enforced at registration; cfg.MaxInFlight
+//     limits the requests processed at once; cfg.GzipLevel and
+//     cfg.GzipMinSize tune the response compression; cfg.AdminAddress moves
+//     the admin routes to [Handler.InitAdmin]; cfg.LoginMaxAttempts,
+//     cfg.LoginAttemptWindow and cfg.LoginLockout configure the login lockout.
 //   - logger: structured logger for request tracing and diagnostics; must not be nil.
-func NewHandler(services *service.Services, logger *logger.Logger) *Handler {
+func NewHandler(services *service.Services, cfg config.Server, logger *logger.Logger) *Handler {
 	logger.Debug().Msg("http handler created")
 	return &Handler{
-		services: services,
-		logger:   logger,
+		services:       services,
+		logger:         logger,
+		adminToken:     cfg.AdminToken,
+		separateAdmin:  cfg.AdminAddress != "",
+		accessLogLevel: parseAccessLogLevel(cfg.AccessLogLevel),
+		basePath:       config.NormalizeBasePath(cfg.BasePath),
+		minSaltLength:  minSaltLength(cfg.MinSaltLength),
+		maxInFlight:    cfg.MaxInFlight,
+		gzip:           newGZipOptions(cfg),
+		loginLockout:   newLoginLockout(cfg, services.Clock),
+	}
+}
+
+// IdleEvictors returns the in-memory state of the handler that a
+// [service.Janitor] has to evict: the failed-login counters when the login
+// lockout is enabled.
+func (h *Handler) IdleEvictors() []service.IdleEvictor {
+	if h.loginLockout == nil {
+		return nil
+	}
+	return []service.IdleEvictor{h.loginLockout}
+}
+
+// minSaltLength returns configured, or [config.DefaultMinSaltLength] when it
+// is not positive.
+func minSaltLength(configured int) int {
+	if configured <= 0 {
+		return config.DefaultMinSaltLength
 	}
+	return configured
 }
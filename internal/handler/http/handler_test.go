@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
 	"github.com/stretchr/testify/assert"
@@ -19,28 +20,28 @@ import (
 // ─────────────────────────────────────────────
 
 func TestNewHandler_ReturnsNonNil(t *testing.T) {
-	h := NewHandler(&service.Services{}, logger.Nop())
+	h := NewHandler(&service.Services{}, config.Server{}, logger.Nop())
 
 	require.NotNil(t, h)
 }
 
 func TestNewHandler_StoresServices(t *testing.T) {
 	svc := &service.Services{}
-	h := NewHandler(svc, logger.Nop())
+	h := NewHandler(svc, config.Server{}, logger.Nop())
 
 	assert.Equal(t, svc, h.services)
 }
 
 func TestNewHandler_StoresLogger(t *testing.T) {
 	log := logger.Nop()
-	h := NewHandler(&service.Services{}, log)
+	h := NewHandler(&service.Services{}, config.Server{}, log)
 
 	assert.Equal(t, log, h.logger)
 }
 
 func TestNewHandler_IndependentInstances(t *testing.T) {
-	h1 := NewHandler(&service.Services{}, logger.Nop())
-	h2 := NewHandler(&service.Services{}, logger.Nop())
+	h1 := NewHandler(&service.Services{}, config.Server{}, logger.Nop())
+	h2 := NewHandler(&service.Services{}, config.Server{}, logger.Nop())
 
 	assert.NotSame(t, h1, h2)
 }
@@ -58,7 +59,7 @@ func newTestHandlerWithAppInfoService(t *testing.T) *Handler {
 		AppInfoService: &mockAppInfoService{version: "test-version"},
 	}
 
-	return NewHandler(svcs, logger.Nop())
+	return NewHandler(svcs, config.Server{}, logger.Nop())
 }
 
 func TestInit_ReturnsRouter(t *testing.T) {
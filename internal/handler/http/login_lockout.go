@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+)
+
+// loginLockout counts failed logins per login name and locks a login for a
+// while once too many of them happen within a window. Logins are counted
+// whether or not such an account exists, so a lockout reveals nothing about
+// the registered accounts. A nil *loginLockout is disabled: it never locks.
+type loginLockout struct {
+	maxAttempts int
+	window      time.Duration
+	lockout     time.Duration
+	clock       clock.Clock
+
+	mu       sync.Mutex
+	attempts map[string]*loginAttempts
+}
+
+// loginAttempts is the failed-login state of one login name.
+type loginAttempts struct {
+	// failures is the number of failed logins since windowStart.
+	failures int
+	// windowStart is the time of the first counted failure.
+	windowStart time.Time
+	// lockedUntil is the end of the current lockout; zero when not locked.
+	lockedUntil time.Time
+	// lastSeen is the time of the last failure, used for eviction.
+	lastSeen time.Time
+}
+
+// newLoginLockout returns the lockout configured by cfg.LoginMaxAttempts,
+// cfg.LoginAttemptWindow and cfg.LoginLockout, or nil when
+// cfg.LoginMaxAttempts disables it. Windows and lockouts are timed with clk.
+func newLoginLockout(cfg config.Server, clk clock.Clock) *loginLockout {
+	if cfg.LoginMaxAttempts <= 0 {
+		return nil
+	}
+	return &loginLockout{
+		maxAttempts: cfg.LoginMaxAttempts,
+		window:      cfg.LoginAttemptWindowOrDefault(),
+		lockout:     cfg.LoginLockoutOrDefault(),
+		clock:       clk,
+		attempts:    make(map[string]*loginAttempts),
+	}
+}
+
+// retryAfter reports whether login is locked and, if so, how many whole
+// seconds remain until the lockout ends.
+func (l *loginLockout) retryAfter(login string) (int, bool) {
+	if l == nil {
+		return 0, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.attempts[login]
+	if !ok {
+		return 0, false
+	}
+	remaining := a.lockedUntil.Sub(l.clock.Now())
+	if remaining <= 0 {
+		return 0, false
+	}
+	return int(math.Ceil(remaining.Seconds())), true
+}
+
+// fail records a failed login. The failure that reaches the limit within the
+// window locks login and starts counting anew.
+func (l *loginLockout) fail(login string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	a, ok := l.attempts[login]
+	if !ok {
+		a = &loginAttempts{}
+		l.attempts[login] = a
+	}
+	if a.failures == 0 || now.Sub(a.windowStart) > l.window {
+		a.failures = 0
+		a.windowStart = now
+	}
+	a.failures++
+	a.lastSeen = now
+
+	if a.failures >= l.maxAttempts {
+		a.failures = 0
+		a.lockedUntil = now.Add(l.lockout)
+	}
+}
+
+// succeed forgets the failed logins of login.
+func (l *loginLockout) succeed(login string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.attempts, login)
+}
+
+// EvictIdle implements [service.IdleEvictor]. It drops the state of logins
+// that neither failed nor were locked after cutoff; a running lockout is
+// always kept.
+func (l *loginLockout) EvictIdle(cutoff time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	evicted := 0
+	for login, a := range l.attempts {
+		if a.lastSeen.Before(cutoff) && a.lockedUntil.Before(cutoff) {
+			delete(l.attempts, login)
+			evicted++
+		}
+	}
+	return evicted
+}
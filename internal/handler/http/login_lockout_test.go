@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/app"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockoutTestHandler returns a handler with a login lockout of 3 attempts in
+// a minute, locking for 10 minutes, on a clock controlled through the
+// returned pointer. Logins with auth hash "right" succeed; others fail with
+// failErr. calls counts the logins that reached the auth service.
+func lockoutTestHandler(t *testing.T, failErr error) (*Handler, *clock.Fake, *int) {
+	t.Helper()
+	calls := 0
+	auth := &mockAuthService{
+		loginFn: func(_ context.Context, u models.User) (models.User, error) {
+			calls++
+			if u.AuthHash == "right" {
+				return u, nil
+			}
+			return models.User{}, failErr
+		},
+		createTokenFn: func(_ context.Context, _ models.User) (models.Token, error) {
+			return stubToken("jwt"), nil
+		},
+	}
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	svcs := &service.Services{AppInfoService: &mockAppInfoService{version: "test"}, AuthService: auth, Clock: fake}
+	h := NewHandler(svcs, config.Server{
+		LoginMaxAttempts:   3,
+		LoginAttemptWindow: time.Minute,
+		LoginLockout:       10 * time.Minute,
+	}, logger.Nop())
+
+	return h, fake, &calls
+}
+
+func doLogin(t *testing.T, h *Handler, login, authHash string) *httptest.ResponseRecorder {
+	t.Helper()
+	body := userBody(t, models.User{Login: login, AuthHash: authHash})
+	rec := httptest.NewRecorder()
+	h.login(rec, httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(body)))
+	return rec
+}
+
+func TestLogin_LockoutAfterMaxAttempts(t *testing.T) {
+	tests := []struct {
+		name    string
+		failErr error
+	}{
+		{name: "wrong password", failErr: service.ErrWrongPassword},
+		{name: "unknown login", failErr: store.ErrNoUserWasFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake, calls := lockoutTestHandler(t, tt.failErr)
+
+			for range 3 {
+				assert.Equal(t, http.StatusUnauthorized, doLogin(t, h, "alice", "wrong").Code)
+				fake.Advance(time.Second)
+			}
+
+			rec := doLogin(t, h, "alice", "right")
+			assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+			assert.Contains(t, rec.Body.String(), app.MsgTooManyLoginAttempts)
+			assert.Equal(t, "599", rec.Header().Get("Retry-After"))
+			assert.Equal(t, 3, *calls, "a locked login must not reach the auth service")
+
+			assert.Equal(t, http.StatusOK, doLogin(t, h, "bob", "right").Code, "other logins are not locked")
+		})
+	}
+}
+
+func TestLogin_LockoutRecoversAfterCooldown(t *testing.T) {
+	h, fake, _ := lockoutTestHandler(t, service.ErrWrongPassword)
+
+	for range 3 {
+		doLogin(t, h, "alice", "wrong")
+	}
+	require.Equal(t, http.StatusTooManyRequests, doLogin(t, h, "alice", "right").Code)
+
+	fake.Advance(10 * time.Minute)
+	assert.Equal(t, http.StatusOK, doLogin(t, h, "alice", "right").Code)
+
+	// The successful login reset the counter: two failures do not lock.
+	for range 2 {
+		doLogin(t, h, "alice", "wrong")
+	}
+	assert.Equal(t, http.StatusOK, doLogin(t, h, "alice", "right").Code)
+}
+
+func TestLogin_LockoutCountsWithinWindow(t *testing.T) {
+	h, fake, _ := lockoutTestHandler(t, service.ErrWrongPassword)
+
+	doLogin(t, h, "alice", "wrong")
+	doLogin(t, h, "alice", "wrong")
+	fake.Advance(2 * time.Minute)
+	doLogin(t, h, "alice", "wrong")
+
+	assert.Equal(t, http.StatusOK, doLogin(t, h, "alice", "right").Code,
+		"failures older than the window must not count")
+}
+
+func TestLogin_LockoutIgnoresOtherErrors(t *testing.T) {
+	h, _, _ := lockoutTestHandler(t, errors.New("db down"))
+
+	for range 5 {
+		assert.Equal(t, http.StatusInternalServerError, doLogin(t, h, "alice", "wrong").Code)
+	}
+	assert.Equal(t, http.StatusOK, doLogin(t, h, "alice", "right").Code)
+}
+
+func TestLogin_LockoutDisabled(t *testing.T) {
+	auth := &mockAuthService{
+		loginFn: func(_ context.Context, _ models.User) (models.User, error) {
+			return models.User{}, service.ErrWrongPassword
+		},
+	}
+	h := newHandlerWithAuth(t, auth)
+
+	for range 20 {
+		assert.Equal(t, http.StatusUnauthorized, doLogin(t, h, "alice", "wrong").Code)
+	}
+	assert.Empty(t, h.IdleEvictors())
+}
+
+func TestLoginLockout_EvictIdle(t *testing.T) {
+	h, fake, _ := lockoutTestHandler(t, service.ErrWrongPassword)
+	start := fake.Now()
+
+	doLogin(t, h, "alice", "wrong")
+	for range 3 {
+		doLogin(t, h, "bob", "wrong")
+	}
+	fake.Advance(5 * time.Minute)
+
+	evictors := h.IdleEvictors()
+	require.Len(t, evictors, 1)
+	assert.Equal(t, 1, evictors[0].EvictIdle(start.Add(time.Minute)), "only alice is idle; bob is still locked")
+	assert.Equal(t, http.StatusTooManyRequests, doLogin(t, h, "bob", "right").Code)
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+)
+
+// adminTokenHeader is the request header that carries the admin shared secret.
+const adminTokenHeader = "X-Admin-Token"
+
+// adminAuth is an HTTP middleware that guards admin-only routes with the
+// shared secret configured in [config.Server.AdminToken].
+//
+// When no admin token is configured the routes are disabled and the
+// middleware responds with HTTP 404, so their existence is not revealed.
+// Otherwise a request whose "X-Admin-Token" header does not match the
+// configured token (compared in constant time) is rejected with HTTP 401.
+func (h *Handler) adminAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.adminToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		token := r.Header.Get(adminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			logger.FromRequest(r).Warn().Str("func", "*Handler.adminAuth").Msg("invalid admin token")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
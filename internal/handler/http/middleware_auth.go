@@ -19,7 +19,8 @@ import (
 // It inspects the incoming "Authorization" header, extracts the bearer token,
 // validates it via [service.AuthService.ParseToken], and — on success — stores
 // the authenticated user's ID in the request context under [utils.UserIDCtxKey]
-// before delegating to the next handler.
+// before delegating to the next handler. The user ID is also recorded on the
+// request's access-log entry (see withLogging).
 //
 // The middleware rejects requests with HTTP 401 Unauthorized in the following cases:
 //   - The "Authorization" header is absent ([ErrEmptyAuthorizationHeader]).
@@ -67,6 +68,7 @@ func (h *Handler) auth(next http.Handler) http.Handler {
 		// Store the authenticated user's ID in the context so that downstream
 		// handlers can retrieve it without re-parsing the token.
 		ctx = context.WithValue(ctx, utils.UserIDCtxKey, token.UserID)
+		setAccessLogUserID(ctx, token.UserID)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
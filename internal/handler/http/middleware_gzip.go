@@ -9,20 +9,9 @@ import (
 	"net/http"
 	"strings"
 	"sync"
-)
 
-// gzipWriterPool is a pool of reusable [gzip.Writer] instances.
-//
-// Pooling writers avoids repeated heap allocations for each compressed
-// response. Each writer is reset to a new underlying [io.Writer] via
-// [gzip.Writer.Reset] before use and returned to the pool after the
-// response body has been flushed and closed.
-var gzipWriterPool = sync.Pool{
-	New: func() any {
-		w := gzip.NewWriter(nil)
-		return w
-	},
-}
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+)
 
 // gzipReaderPool is a pool of reusable [gzip.Reader] instances.
 //
@@ -36,7 +25,36 @@ var gzipReaderPool = sync.Pool{
 	},
 }
 
-// withGZip is an HTTP middleware that provides transparent gzip
+// gzipOptions tune the response compression of [withGZipOptions].
+type gzipOptions struct {
+	// level is the [gzip] compression level, from [gzip.BestSpeed] to
+	// [gzip.BestCompression], or [gzip.DefaultCompression].
+	level int
+
+	// minSize is the smallest response body, in bytes, that is compressed;
+	// smaller bodies are sent as they are. Zero or less compresses every
+	// response.
+	minSize int
+}
+
+// newGZipOptions returns the options of a server configured with the gzip
+// level and minimum size of cfg. A level of zero means
+// [gzip.DefaultCompression].
+func newGZipOptions(cfg config.Server) gzipOptions {
+	level := cfg.GzipLevel
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzipOptions{level: level, minSize: cfg.GzipMinSizeLimit()}
+}
+
+// withGZip is [withGZipOptions] with the default compression level and no
+// minimum size.
+func withGZip(next http.Handler) http.Handler {
+	return withGZipOptions(gzipOptions{level: gzip.DefaultCompression})(next)
+}
+
+// withGZipOptions returns an HTTP middleware that provides transparent gzip
 // compression and decompression for both request bodies and response bodies.
 //
 // Request decompression: if the incoming request carries a
@@ -49,66 +67,113 @@ var gzipReaderPool = sync.Pool{
 //
 // Response compression: if the client advertises gzip support via the
 // "Accept-Encoding: gzip" header, the response body is compressed on
-// the fly using a pooled [gzip.Writer] wrapped in a [gzipResponseWriter].
-// The "Content-Encoding: gzip" header is set automatically when the
-// first WriteHeader call is made. If the client does not advertise gzip
-// support, the response is passed through to next unchanged.
+// the fly at opts.level using a pooled [gzip.Writer] wrapped in a
+// [gzipResponseWriter]. Bodies shorter than opts.minSize, responses that
+// already carry a Content-Encoding and content types that are compressed
+// already (see [isPrecompressedType]) are passed through unchanged. If the
+// client does not advertise gzip support, the response is passed through to
+// next unchanged.
 //
 // Both the writer and reader pools are used to minimise allocations under
-// concurrent load.
-func withGZip(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		acceptEncoding := req.Header.Get("Accept-Encoding")
-		supportsGzip := strings.Contains(acceptEncoding, "gzip")
-
-		contentEncoding := req.Header.Get("Content-Encoding")
-		isGzipRequest := strings.Contains(contentEncoding, "gzip")
-
-		// Decompress the request body if the client sent it gzip-encoded.
-		if isGzipRequest && req.Body != nil {
-			gzipReader := gzipReaderPool.Get().(*gzip.Reader)
-			if err := gzipReader.Reset(req.Body); err != nil {
-				gzipReaderPool.Put(gzipReader)
-				http.Error(w, "Invalid gzip data", http.StatusBadRequest)
-				return
+// concurrent load. The writer pool belongs to the returned middleware, so
+// every writer in it uses opts.level.
+func withGZipOptions(opts gzipOptions) func(http.Handler) http.Handler {
+	writerPool := &sync.Pool{
+		New: func() any {
+			// The level is checked by the configuration, so the error of an
+			// invalid level cannot occur here.
+			w, err := gzip.NewWriterLevel(nil, opts.level)
+			if err != nil {
+				w = gzip.NewWriter(nil)
 			}
+			return w
+		},
+	}
 
-			// Wrap the gzip reader so that closing the body also closes the
-			// underlying gzip stream and returns the reader to the pool.
-			req.Body = &wrappedReadCloser{
-				Reader: gzipReader,
-				OnClose: func() {
-					gzipReader.Close()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			acceptEncoding := req.Header.Get("Accept-Encoding")
+			supportsGzip := strings.Contains(acceptEncoding, "gzip")
+
+			contentEncoding := req.Header.Get("Content-Encoding")
+			isGzipRequest := strings.Contains(contentEncoding, "gzip")
+
+			// Decompress the request body if the client sent it gzip-encoded.
+			if isGzipRequest && req.Body != nil {
+				gzipReader := gzipReaderPool.Get().(*gzip.Reader)
+				if err := gzipReader.Reset(req.Body); err != nil {
 					gzipReaderPool.Put(gzipReader)
-				},
+					http.Error(w, "Invalid gzip data", http.StatusBadRequest)
+					return
+				}
+
+				// Wrap the gzip reader so that closing the body also closes the
+				// underlying gzip stream and returns the reader to the pool.
+				req.Body = &wrappedReadCloser{
+					Reader: gzipReader,
+					OnClose: func() {
+						gzipReader.Close()
+						gzipReaderPool.Put(gzipReader)
+					},
+				}
+				// Remove the header so downstream handlers treat the body as plain data.
+				req.Header.Del("Content-Encoding")
 			}
-			// Remove the header so downstream handlers treat the body as plain data.
-			req.Header.Del("Content-Encoding")
-		}
 
-		// If the client does not support gzip, skip response compression.
-		if !supportsGzip {
-			next.ServeHTTP(w, req)
-			return
-		}
+			// If the client does not support gzip, skip response compression.
+			if !supportsGzip {
+				next.ServeHTTP(w, req)
+				return
+			}
 
-		// Acquire a pooled gzip writer, reset it to the real ResponseWriter,
-		// and wrap the ResponseWriter so that all writes go through compression.
-		gzipWriter := gzipWriterPool.Get().(*gzip.Writer)
+			// Wrap the ResponseWriter so that writes go through compression
+			// once the response turns out to be worth compressing.
+			gzipRW := &gzipResponseWriter{
+				ResponseWriter: w,
+				pool:           writerPool,
+				minSize:        opts.minSize,
+			}
 
-		gzipRW := &gzipResponseWriter{
-			ResponseWriter: w,
-			gzipWriter:     gzipWriter,
-		}
+			next.ServeHTTP(gzipRW, req)
 
-		gzipWriter.Reset(w)
+			// Flush and close the gzip stream, or send a response that
+			// stayed below the minimum size as it is.
+			gzipRW.Close()
+		})
+	}
+}
 
-		next.ServeHTTP(gzipRW, req)
+// precompressedTypes are the Content-Type prefixes of bodies that are
+// compressed already and gain nothing from gzip.
+var precompressedTypes = []string{
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/zstd",
+	"application/x-7z-compressed",
+	"application/x-bzip2",
+	"application/x-xz",
+	"application/x-rar-compressed",
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"image/avif",
+	"audio/",
+	"video/",
+	"font/woff",
+}
 
-		// Flush and close the gzip stream, then return the writer to the pool.
-		gzipWriter.Close()
-		gzipWriterPool.Put(gzipWriter)
-	})
+// isPrecompressedType reports whether contentType names a format that is
+// already compressed.
+func isPrecompressedType(contentType string) bool {
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	for _, prefix := range precompressedTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
 // wrappedReadCloser combines an [io.Reader] with a custom close callback.
@@ -134,42 +199,130 @@ func (w *wrappedReadCloser) Close() error {
 }
 
 // gzipResponseWriter is an [http.ResponseWriter] decorator that compresses
-// the response body using the provided [gzip.Writer].
+// the response body with a [gzip.Writer] taken from pool.
 //
-// It intercepts WriteHeader to inject the "Content-Encoding: gzip" response
-// header, and redirects Write calls to the gzip writer so that all response
-// data is compressed transparently. The underlying [http.ResponseWriter]
-// is still used for header management and status code propagation.
+// It holds back the status code and up to minSize bytes of the body until it
+// can tell whether the response is worth compressing: the first bytes
+// beyond minSize start compression and inject the "Content-Encoding: gzip"
+// header, while a response that ends below it, already carries a
+// Content-Encoding or has a precompressed Content-Type is written through
+// unchanged. The underlying [http.ResponseWriter] is still used for header
+// management and status code propagation.
 type gzipResponseWriter struct {
 	http.ResponseWriter
 
-	// gzipWriter is the pooled compressor to which response bytes are written.
+	// pool supplies the compressor; it is taken only once compression
+	// starts and returned by Close.
+	pool *sync.Pool
+
+	// minSize is the smallest body that is compressed.
+	minSize int
+
+	// gzipWriter is the pooled compressor to which response bytes are
+	// written, nil until compression starts.
 	gzipWriter *gzip.Writer
+
+	// status is the code passed to WriteHeader, held back with buf until
+	// the encoding is decided.
+	status int
+
+	// buf holds the body written before the encoding is decided.
+	buf []byte
+
+	// decided is set once the headers have been sent, compressed or not.
+	decided bool
 }
 
-// WriteHeader sets the "Content-Encoding: gzip" header on the response
-// and then delegates to the underlying [http.ResponseWriter.WriteHeader].
-//
-// It must be called before any call to Write, or the header will be
-// sent implicitly with HTTP 200 OK on the first Write.
+// WriteHeader records the status code. The header is sent with the first
+// body bytes that decide the encoding, or by Close. With no minimum size it
+// is sent at once, with "Content-Encoding: gzip" unless the response is
+// passed through.
 func (w *gzipResponseWriter) WriteHeader(statusCode int) {
-	w.Header().Set("Content-Encoding", "gzip")
-	w.ResponseWriter.WriteHeader(statusCode)
+	if w.decided || w.status != 0 {
+		return
+	}
+	w.status = statusCode
+	if w.minSize <= 0 || w.passThrough() {
+		w.decide(w.minSize <= 0 && !w.passThrough())
+	}
 }
 
-// Write compresses data and writes it to the underlying gzip stream.
-// It satisfies the [io.Writer] interface and is called by the HTTP server
-// when the handler writes its response body.
+// Write compresses data and writes it to the underlying gzip stream once
+// the response is known to reach the minimum size; until then it is
+// buffered. It satisfies the [io.Writer] interface and is called by the HTTP
+// server when the handler writes its response body.
 //
 // If WriteHeader has not been called before Write, the status code defaults
-// to HTTP 200 OK and the "Content-Encoding: gzip" header is set at that point.
+// to HTTP 200 OK.
 func (w *gzipResponseWriter) Write(data []byte) (int, error) {
-	return w.gzipWriter.Write(data)
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		w.buf = append(w.buf, data...)
+		if len(w.buf) < w.minSize {
+			return len(data), nil
+		}
+		w.decide(true)
+		buffered := w.buf
+		w.buf = nil
+		if _, err := w.gzipWriter.Write(buffered); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.gzipWriter != nil {
+		return w.gzipWriter.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// passThrough reports whether the headers set by the handler rule out
+// compression.
+func (w *gzipResponseWriter) passThrough() bool {
+	h := w.Header()
+	return h.Get("Content-Encoding") != "" || isPrecompressedType(h.Get("Content-Type"))
+}
+
+// decide sends the held-back status code, compressed or not, and flushes a
+// body buffered for an uncompressed response.
+func (w *gzipResponseWriter) decide(compress bool) {
+	w.decided = true
+	if compress {
+		w.gzipWriter = w.pool.Get().(*gzip.Writer)
+		w.gzipWriter.Reset(w.ResponseWriter)
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+	}
+	w.ResponseWriter.WriteHeader(w.status)
 }
 
-// Close flushes any buffered compressed data and closes the gzip stream.
-// It must be called after the handler returns to ensure all data is flushed
-// to the underlying [http.ResponseWriter] before the connection is finalised.
+// Close finishes the response: it flushes any buffered compressed data,
+// closes the gzip stream and returns the writer to the pool, or writes a
+// response that stayed below the minimum size as it is. It must be called
+// after the handler returns to ensure all data is flushed to the underlying
+// [http.ResponseWriter] before the connection is finalised.
 func (w *gzipResponseWriter) Close() error {
-	return w.gzipWriter.Close()
+	if !w.decided {
+		if w.status == 0 {
+			if len(w.buf) == 0 {
+				return nil
+			}
+			w.status = http.StatusOK
+		}
+		w.decide(false)
+		if len(w.buf) > 0 {
+			if _, err := w.ResponseWriter.Write(w.buf); err != nil {
+				return err
+			}
+			w.buf = nil
+		}
+	}
+	if w.gzipWriter == nil {
+		return nil
+	}
+	err := w.gzipWriter.Close()
+	w.pool.Put(w.gzipWriter)
+	w.gzipWriter = nil
+	return err
 }
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -381,3 +382,117 @@ func TestWrappedReadCloser_CloseWithoutCallback(t *testing.T) {
 	err := wrapped.Close()
 	assert.NoError(t, err, "Close should not fail when OnClose is nil")
 }
+
+func TestGZipOptions_UsesConfiguredLevel(t *testing.T) {
+	body := strings.Repeat("compressible vault data ", 200)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+
+	// The XFL byte of the gzip header records the extreme levels:
+	// 4 for the fastest and 2 for the best compression.
+	tests := []struct {
+		name    string
+		cfg     config.Server
+		wantXFL byte
+	}{
+		{name: "fastest", cfg: config.Server{GzipLevel: gzip.BestSpeed}, wantXFL: 4},
+		{name: "best", cfg: config.Server{GzipLevel: gzip.BestCompression}, wantXFL: 2},
+		{name: "default", cfg: config.Server{}, wantXFL: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			rr := httptest.NewRecorder()
+			withGZipOptions(newGZipOptions(tt.cfg))(next).ServeHTTP(rr, req)
+
+			require.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+			compressed := rr.Body.Bytes()
+			require.Greater(t, len(compressed), 9)
+			assert.Equal(t, tt.wantXFL, compressed[8])
+
+			var want bytes.Buffer
+			gw, err := gzip.NewWriterLevel(&want, newGZipOptions(tt.cfg).level)
+			require.NoError(t, err)
+			_, _ = gw.Write([]byte(body))
+			require.NoError(t, gw.Close())
+			assert.Equal(t, want.Bytes(), compressed, "the body is compressed at the configured level")
+
+			gr, err := gzip.NewReader(bytes.NewReader(compressed))
+			require.NoError(t, err)
+			decompressed, err := io.ReadAll(gr)
+			require.NoError(t, err)
+			assert.Equal(t, body, string(decompressed))
+		})
+	}
+}
+
+func TestGZipOptions_PassesThroughSmallAndPrecompressed(t *testing.T) {
+	large := strings.Repeat("a", 2*config.DefaultGzipMinSize)
+	opts := newGZipOptions(config.Server{})
+
+	tests := []struct {
+		name        string
+		contentType string
+		encoding    string
+		status      int
+		writes      []string
+		wantGzip    bool
+		wantBody    string
+		wantStatus  int
+	}{
+		{name: "tiny response", writes: []string{`{"ok":true}`}, wantBody: `{"ok":true}`},
+		{name: "tiny response with status", status: http.StatusCreated, writes: []string{"created"}, wantBody: "created", wantStatus: http.StatusCreated},
+		{name: "empty response", status: http.StatusNoContent, wantStatus: http.StatusNoContent},
+		{name: "large response", writes: []string{large}, wantGzip: true, wantBody: large},
+		{name: "large response in small writes", writes: []string{large[:100], large[100:]}, wantGzip: true, wantBody: large},
+		{name: "zip archive", contentType: "application/zip", writes: []string{large}, wantBody: large},
+		{name: "png image", contentType: "image/png", writes: []string{large}, wantBody: large},
+		{name: "already encoded", encoding: "br", writes: []string{large}, wantBody: large},
+		{name: "svg is compressed", contentType: "image/svg+xml", writes: []string{large}, wantGzip: true, wantBody: large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				for _, chunk := range tt.writes {
+					w.Write([]byte(chunk))
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			rr := httptest.NewRecorder()
+			withGZipOptions(opts)(next).ServeHTTP(rr, req)
+
+			wantStatus := tt.wantStatus
+			if wantStatus == 0 {
+				wantStatus = http.StatusOK
+			}
+			assert.Equal(t, wantStatus, rr.Code)
+
+			if !tt.wantGzip {
+				assert.NotEqual(t, "gzip", rr.Header().Get("Content-Encoding"))
+				assert.Equal(t, tt.wantBody, rr.Body.String())
+				return
+			}
+			require.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+			gr, err := gzip.NewReader(rr.Body)
+			require.NoError(t, err)
+			decompressed, err := io.ReadAll(gr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBody, string(decompressed))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/app"
+)
+
+// inFlightRetryAfter is the Retry-After value, in seconds, sent with a
+// request shed by [withInFlightLimit].
+const inFlightRetryAfter = 1
+
+// withInFlightLimit returns an HTTP middleware that lets at most limit
+// requests be processed at the same time. A request arriving while all slots
+// are taken is not queued: it is shed at once with 503 Service Unavailable,
+// a Retry-After header and [app.MsgServerBusy], so that a burst of syncing
+// clients cannot exhaust the database pool. Requests to [healthCheckPaths]
+// bypass the limit. A limit of zero or less disables the middleware.
+func withInFlightLimit(limit int) func(http.Handler) http.Handler {
+	if limit <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	slots := make(chan struct{}, limit)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, health := healthCheckPaths[r.URL.Path]; health {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+				next.ServeHTTP(w, r)
+			default:
+				w.Header().Set("Retry-After", strconv.Itoa(inFlightRetryAfter))
+				http.Error(w, app.MsgServerBusy, http.StatusServiceUnavailable)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithInFlightLimit(t *testing.T) {
+	const limit = 2
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	blocking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/data/all" {
+			entered <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := withInFlightLimit(limit)(blocking)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	// Fill every slot with a request that blocks until released.
+	var wg sync.WaitGroup
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, serve("/api/data/all").Code)
+		}()
+		<-entered
+	}
+
+	t.Run("sheds requests beyond the limit", func(t *testing.T) {
+		rr := serve("/api/version/")
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+		assert.Contains(t, rr.Body.String(), app.MsgServerBusy)
+	})
+
+	t.Run("health endpoints bypass the limit", func(t *testing.T) {
+		for path := range healthCheckPaths {
+			assert.Equal(t, http.StatusOK, serve(path).Code, path)
+		}
+	})
+
+	close(release)
+	wg.Wait()
+
+	t.Run("proceeds once capacity is available", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("/api/version/").Code)
+	})
+}
+
+func TestWithInFlightLimit_Disabled(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, limit := range []int{0, -1} {
+		rr := httptest.NewRecorder()
+		withInFlightLimit(limit)(next).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/version/", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+}
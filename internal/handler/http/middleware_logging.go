@@ -5,74 +5,156 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"io"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/rs/zerolog"
 )
 
-// withLogging is an HTTP middleware that records structured access-log entries
-// for every request processed by the handler chain.
+// healthCheckPaths holds the health-check endpoints that are excluded from
+// access logging and from the in-flight limit ([withInFlightLimit]). They are
+// polled every few seconds by load balancers and monitoring; logging them
+// would drown out real traffic, and shedding them would mark a busy server
+// as dead.
+var healthCheckPaths = map[string]struct{}{
+	"/healthz": {},
+	"/readyz":  {},
+}
+
+// accessLogCtxKey is the context key under which withLogging stores the
+// [accessLogEntry] of the current request.
+type accessLogCtxKey struct{}
+
+// accessLogEntry collects request attributes that only become known further
+// down the handler chain. withLogging places a pointer to it in the request
+// context and reads it back after the downstream handler has returned, so
+// values set on a derived request context are still visible to it.
+type accessLogEntry struct {
+	// userID is the authenticated user, or zero for anonymous requests.
+	userID int64
+}
+
+// setAccessLogUserID records userID on the access-log entry of the request
+// that ctx belongs to. It is a no-op when the request is not being logged.
+func setAccessLogUserID(ctx context.Context, userID int64) {
+	if entry, ok := ctx.Value(accessLogCtxKey{}).(*accessLogEntry); ok {
+		entry.userID = userID
+	}
+}
+
+// withLogging returns an HTTP middleware that writes exactly one structured
+// access-log line, at the given level, for every request processed by the
+// handler chain. Requests to [healthCheckPaths] are passed through without
+// logging.
 //
 // For each incoming request the middleware captures:
 //   - uri — the raw request URI as sent by the client ([http.Request.RequestURI]),
 //     including the path, query string, and any encoded characters.
+//   - path — the URL path without the query string.
 //   - method — the HTTP method (GET, POST, PUT, …).
 //   - status — the HTTP status code written by the downstream handler.
-//     If the handler never calls [http.ResponseWriter.WriteHeader], the status
-//     defaults to 0 and is recorded as such; the actual wire response will be
-//     200 OK because the standard library sends 200 on the first [Write].
-//   - duration — wall-clock time elapsed from the moment withLogging wraps the
-//     request until the downstream handler returns.
+//     If the handler writes nothing at all, 200 is recorded, matching what
+//     the standard library sends on the wire.
 //   - size — the total number of bytes written to the response body by the
 //     downstream handler, as tracked by the intercepting [responseWriter].
+//   - duration — wall-clock time elapsed from the moment withLogging wraps the
+//     request until the downstream handler returns.
+//   - remote_ip — the client address without the port.
+//   - user_id — the authenticated user, recorded by [Handler.auth] via
+//     [setAccessLogUserID]; omitted for anonymous requests.
 //
-// The log entry is emitted at INFO level via the context-scoped logger obtained
-// from [logger.FromRequest]. The logger must have been placed in the request
-// context by an earlier middleware (e.g. withTraceID) before withLogging is
-// invoked; otherwise the global zerolog no-op logger is used and the entry is
-// silently discarded.
+// The entry is emitted via the context-scoped logger obtained from
+// [logger.FromRequest], so it also carries the trace_id and request_id fields
+// added by withTraceID and withRequestID. Those middleware must therefore run
+// before withLogging; otherwise the global zerolog no-op logger is used and
+// the entry is silently discarded.
 //
 // withLogging does not recover from panics. If the downstream handler panics,
 // the log entry is never written and the panic propagates to the caller.
-func withLogging(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log := logger.FromRequest(r)
-
-		start := time.Now()
-
-		// Capture URI and method before delegating, in case a downstream
-		// handler mutates the request (e.g. via r.WithContext).
-		uri := r.RequestURI
-		method := r.Method
-
-		// Read and restore the request body so downstream handlers can still read it.
-		if r.Body != nil {
-			bodyBytes, err := io.ReadAll(r.Body)
-			if err == nil {
-				log.Debug().RawJSON("incoming data", bodyBytes).Msg("incoming request")
-				// Restore the body for downstream handlers.
-				r.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+func withLogging(level zerolog.Level) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, skip := healthCheckPaths[r.URL.Path]; skip {
+				next.ServeHTTP(w, r)
+				return
 			}
-		}
-
-		// Wrap the ResponseWriter so that the status code and response body
-		// size written by downstream handlers can be observed after the call.
-		lw := &responseWriter{
-			ResponseWriter: w,
-		}
-
-		next.ServeHTTP(lw, r)
-
-		duration := time.Since(start)
-
-		log.Info().
-			Str("uri", uri).
-			Str("method", method).
-			Int("status", lw.status).
-			Dur("duration", duration).
-			Int("size", lw.size).
-			Send()
-	})
+
+			log := logger.FromRequest(r)
+
+			start := time.Now()
+
+			// Capture request attributes before delegating, in case a
+			// downstream handler mutates the request (e.g. via r.WithContext).
+			uri := r.RequestURI
+			path := r.URL.Path
+			method := r.Method
+			remoteIP := remoteIP(r)
+
+			// Read and restore the request body so downstream handlers can still read it.
+			if r.Body != nil {
+				bodyBytes, err := io.ReadAll(r.Body)
+				if err == nil {
+					log.Debug().RawJSON("incoming data", bodyBytes).Msg("incoming request")
+					// Restore the body for downstream handlers.
+					r.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+				}
+			}
+
+			entry := &accessLogEntry{}
+			r = r.WithContext(context.WithValue(r.Context(), accessLogCtxKey{}, entry))
+
+			// Wrap the ResponseWriter so that the status code and response body
+			// size written by downstream handlers can be observed after the call.
+			lw := &responseWriter{
+				ResponseWriter: w,
+			}
+
+			next.ServeHTTP(lw, r)
+
+			duration := time.Since(start)
+
+			status := lw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			event := log.WithLevel(level).
+				Str("uri", uri).
+				Str("path", path).
+				Str("method", method).
+				Int("status", status).
+				Int("size", lw.size).
+				Dur("duration", duration).
+				Str("remote_ip", remoteIP)
+			if entry.userID != 0 {
+				event = event.Int64("user_id", entry.userID)
+			}
+			event.Msg("request completed")
+		})
+	}
+}
+
+// parseAccessLogLevel converts the configured access-log level name into a
+// [zerolog.Level]. The config is validated at startup, so an unknown name only
+// reaches here in tests and falls back to info.
+func parseAccessLogLevel(name string) zerolog.Level {
+	level, err := logger.ParseLevel(name)
+	if err != nil {
+		return zerolog.InfoLevel
+	}
+	return level
+}
+
+// remoteIP returns the host part of r.RemoteAddr, or the raw value when it
+// has no port.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
 }
@@ -5,6 +5,8 @@ package http
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -12,8 +14,10 @@ import (
 	"time"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // injectLogger puts zerolog.Logger into request context the same way
@@ -188,7 +192,7 @@ func TestWithLogging_TableTest(t *testing.T) {
 				}
 			})
 
-			middleware := withLogging(next)
+			middleware := withLogging(zerolog.InfoLevel)(next)
 
 			req := makeRequest(tt.method, tt.path, &logBuf)
 			rr := httptest.NewRecorder()
@@ -216,7 +220,7 @@ func TestWithLogging_ResponseSize(t *testing.T) {
 		_, _ = w.Write([]byte(strings.Repeat("a", 1024)))
 	})
 
-	middleware := withLogging(next)
+	middleware := withLogging(zerolog.InfoLevel)(next)
 
 	req := makeRequest(http.MethodGet, "/test", &logBuf)
 	rr := httptest.NewRecorder()
@@ -236,7 +240,7 @@ func TestWithLogging_NoStatusWritten(t *testing.T) {
 		_, _ = w.Write([]byte("implicit 200"))
 	})
 
-	middleware := withLogging(next)
+	middleware := withLogging(zerolog.InfoLevel)(next)
 
 	req := makeRequest(http.MethodGet, "/test", &logBuf)
 	rr := httptest.NewRecorder()
@@ -252,7 +256,7 @@ func TestWithLogging_ConcurrentRequests(t *testing.T) {
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
-	middleware := withLogging(next)
+	middleware := withLogging(zerolog.InfoLevel)(next)
 
 	const n = 50
 	done := make(chan struct{}, n)
@@ -285,7 +289,7 @@ func TestWithLogging_DurationAccuracy(t *testing.T) {
 		time.Sleep(delay)
 		w.WriteHeader(http.StatusOK)
 	})
-	middleware := withLogging(next)
+	middleware := withLogging(zerolog.InfoLevel)(next)
 
 	req := makeRequest(http.MethodGet, "/slow", &logBuf)
 	rr := httptest.NewRecorder()
@@ -306,7 +310,7 @@ func TestWithLogging_PanicNotSuppressed(t *testing.T) {
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		panic("test panic")
 	})
-	middleware := withLogging(next)
+	middleware := withLogging(zerolog.InfoLevel)(next)
 
 	req := makeRequest(http.MethodGet, "/panic", &logBuf)
 	rr := httptest.NewRecorder()
@@ -322,7 +326,7 @@ func TestWithLogging_NopLogger(t *testing.T) {
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
-	middleware := withLogging(next)
+	middleware := withLogging(zerolog.InfoLevel)(next)
 
 	// Put nop logger into request context.
 	nop := logger.Nop()
@@ -336,3 +340,110 @@ func TestWithLogging_NopLogger(t *testing.T) {
 	})
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
+
+// ---- Access-log fields ----
+
+// decodeAccessLog parses the access-log line written to buf and checks that
+// there is exactly one. Other lines (e.g. the debug body dump) are ignored.
+func decodeAccessLog(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.Contains(line, `"message":"request completed"`) {
+			lines = append(lines, line)
+		}
+	}
+	require.Len(t, lines, 1, "exactly one access-log line expected")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	return entry
+}
+
+func TestWithLogging_CapturesStatusAndSize(t *testing.T) {
+	body := `{"message":"created"}`
+
+	var logBuf bytes.Buffer
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(body[:10]))
+		_, _ = w.Write([]byte(body[10:]))
+	})
+
+	req := makeRequest(http.MethodPost, "/api/data/?x=1", &logBuf)
+	req.RemoteAddr = "203.0.113.7:51234"
+	rr := httptest.NewRecorder()
+	withLogging(zerolog.InfoLevel)(next).ServeHTTP(rr, req)
+
+	entry := decodeAccessLog(t, &logBuf)
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "POST", entry["method"])
+	assert.Equal(t, "/api/data/", entry["path"])
+	assert.Equal(t, float64(http.StatusCreated), entry["status"])
+	assert.Equal(t, float64(len(body)), entry["size"])
+	assert.Equal(t, "203.0.113.7", entry["remote_ip"])
+	assert.Contains(t, entry, "duration")
+	assert.NotContains(t, entry, "user_id", "anonymous requests carry no user")
+}
+
+func TestWithLogging_RecordsUserAndRequestID(t *testing.T) {
+	var logBuf bytes.Buffer
+	h := &Handler{logger: &logger.Logger{Logger: newTestLogger(&logBuf)}}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Handler.auth records the user on a derived context.
+		ctx := context.WithValue(r.Context(), utils.UserIDCtxKey, int64(42))
+		setAccessLogUserID(ctx, 42)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	chain := h.withTraceID(h.withRequestID(withLogging(zerolog.InfoLevel)(next)))
+	req := httptest.NewRequest(http.MethodGet, "/api/sync/", nil)
+	req.Header.Set(utils.RequestIDHeader, "req-123")
+	chain.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := decodeAccessLog(t, &logBuf)
+	assert.Equal(t, float64(42), entry["user_id"])
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Contains(t, entry, "trace_id")
+	assert.Equal(t, float64(http.StatusNoContent), entry["status"])
+}
+
+func TestWithLogging_ConfiguredLevel(t *testing.T) {
+	var logBuf bytes.Buffer
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	req := makeRequest(http.MethodGet, "/api/version/", &logBuf)
+	withLogging(zerolog.DebugLevel)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := decodeAccessLog(t, &logBuf)
+	assert.Equal(t, "debug", entry["level"])
+	assert.Equal(t, float64(http.StatusOK), entry["status"], "no write means an implicit 200")
+}
+
+func TestWithLogging_SkipsHealthEndpoints(t *testing.T) {
+	for path := range healthCheckPaths {
+		t.Run(path, func(t *testing.T) {
+			var logBuf bytes.Buffer
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := makeRequest(http.MethodGet, path, &logBuf)
+			rr := httptest.NewRecorder()
+			withLogging(zerolog.InfoLevel)(next).ServeHTTP(rr, req)
+
+			assert.True(t, called)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Empty(t, logBuf.String())
+		})
+	}
+}
+
+func TestParseAccessLogLevel(t *testing.T) {
+	assert.Equal(t, zerolog.InfoLevel, parseAccessLogLevel(""))
+	assert.Equal(t, zerolog.WarnLevel, parseAccessLogLevel("warn"))
+	assert.Equal(t, zerolog.InfoLevel, parseAccessLogLevel("verbose"))
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// maxRequestIDLength caps a request ID supplied by the client. A UUID takes
+// 36 characters, so this leaves room for other ID formats.
+const maxRequestIDLength = 64
+
+// withRequestID is an HTTP middleware that correlates a single client call
+// with the server-side log entries it produces.
+//
+// The request ID is taken from the [utils.RequestIDHeader] ("X-Request-ID")
+// header set by the client adapter. When the header is absent, longer than
+// [maxRequestIDLength] or contains anything but ASCII letters, digits, '-',
+// '_' and '.', a new random UUID is generated instead, so that every request
+// carries an ID that is safe to log and echo back.
+//
+// Once resolved, the middleware:
+//   - adds a "request_id" field to the logger already stored in the request
+//     context (see [Handler.withTraceID]), so every subsequent entry obtained
+//     via [logger.FromRequest] or [logger.FromContext] carries it;
+//   - stores the ID under [utils.RequestIDCtxKey] for handlers and services
+//     that need the raw value;
+//   - echoes the ID back in the "X-Request-ID" response header so that the
+//     client can report it when a call fails.
+//
+// withRequestID must be placed after withTraceID and before withLogging.
+func (h *Handler) withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(utils.RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		l := logger.FromRequest(r).GetChildLogger()
+		l.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("request_id", requestID)
+		})
+
+		ctx := utils.WithRequestID(l.WithContext(r.Context()), requestID)
+		r = r.WithContext(ctx)
+
+		w.Header().Set(utils.RequestIDHeader, requestID)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validRequestID reports whether id can be used as a request ID as is.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range []byte(id) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRequestID_TableTest(t *testing.T) {
+	tests := []struct {
+		name             string
+		requestID        string
+		wantSameID       bool
+		wantGeneratedID  bool
+		wantNextStatus   int
+		wantResponseCode int
+	}{
+		{
+			name:             "request ID from client is echoed back",
+			requestID:        "client-request-id",
+			wantSameID:       true,
+			wantNextStatus:   http.StatusOK,
+			wantResponseCode: http.StatusOK,
+		},
+		{
+			name:             "missing request ID is generated",
+			requestID:        "",
+			wantGeneratedID:  true,
+			wantNextStatus:   http.StatusCreated,
+			wantResponseCode: http.StatusCreated,
+		},
+		{
+			name:             "request ID with forbidden characters is replaced",
+			requestID:        "id\" forged=\"1",
+			wantGeneratedID:  true,
+			wantNextStatus:   http.StatusOK,
+			wantResponseCode: http.StatusOK,
+		},
+		{
+			name:             "request ID over the length limit is replaced",
+			requestID:        strings.Repeat("a", maxRequestIDLength+1),
+			wantGeneratedID:  true,
+			wantNextStatus:   http.StatusOK,
+			wantResponseCode: http.StatusOK,
+		},
+		{
+			name:             "request ID at the length limit is kept",
+			requestID:        strings.Repeat("a", maxRequestIDLength),
+			wantSameID:       true,
+			wantNextStatus:   http.StatusOK,
+			wantResponseCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := &Handler{logger: &logger.Logger{Logger: zerolog.New(&buf)}}
+
+			var ctxRequestID string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxRequestID, _ = utils.GetRequestIDFromContext(r.Context())
+				logger.FromRequest(r).Info().Msg("inside handler")
+				w.WriteHeader(tt.wantNextStatus)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.requestID != "" {
+				req.Header.Set(utils.RequestIDHeader, tt.requestID)
+			}
+			rr := httptest.NewRecorder()
+
+			h.withTraceID(h.withRequestID(next)).ServeHTTP(rr, req)
+
+			responseID := rr.Header().Get(utils.RequestIDHeader)
+			require.NotEmpty(t, responseID)
+			assert.Equal(t, responseID, ctxRequestID)
+			assert.Equal(t, tt.wantResponseCode, rr.Code)
+
+			if tt.wantSameID {
+				assert.Equal(t, tt.requestID, responseID)
+			}
+			if tt.wantGeneratedID {
+				_, err := uuid.Parse(responseID)
+				assert.NoError(t, err)
+			}
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			assert.Equal(t, responseID, entry["request_id"])
+			assert.Equal(t, rr.Header().Get(traceIDHeader), entry["trace_id"])
+		})
+	}
+}
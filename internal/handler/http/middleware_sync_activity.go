@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+)
+
+// withSyncActivity is an HTTP middleware for the vault and sync routes that
+// records the authenticated user as seen via
+// [service.SyncActivityService.RecordActivity] once the request has
+// succeeded. Every client sync goes through these routes, so no separate
+// heartbeat is needed, and the server's clock is used rather than one sent
+// by the client.
+//
+// It must run after [Handler.auth]. A failure to record is logged and does
+// not affect the response, which has already been written.
+func (h *Handler) withSyncActivity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		activity := h.services.SyncActivityService
+		if activity == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rw := &responseWriter{ResponseWriter: w}
+		next.ServeHTTP(rw, r)
+
+		if rw.status >= http.StatusBadRequest {
+			return
+		}
+		userID, ok := utils.GetUserIDFromContext(r.Context())
+		if !ok {
+			return
+		}
+		if err := activity.RecordActivity(r.Context(), userID); err != nil {
+			logger.FromRequest(r).Warn().Err(err).
+				Str("func", "*Handler.withSyncActivity").
+				Int64("user_id", userID).
+				Msg("could not record sync activity")
+		}
+	})
+}
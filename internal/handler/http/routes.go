@@ -4,6 +4,9 @@
 package http
 
 import (
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
@@ -18,24 +21,38 @@ import (
 //     trace, and returns HTTP 500 to the client so the server stays alive.
 //   - [Handler.withTraceID] — resolves or generates a trace ID and stores
 //     an enriched logger in the request context for structured tracing.
-//   - withLogging — emits a structured access-log entry (URI, method,
-//     status, duration, response size) after each request completes.
-//   - withGZip — transparently decompresses gzip-encoded request bodies and
-//     compresses response bodies for clients that advertise gzip support.
+//   - [Handler.withRequestID] — resolves or generates the client-supplied
+//     X-Request-ID, adds it to the context logger, and echoes it back.
+//   - withLogging — emits one structured access-log line per request (method,
+//     path, status, size, duration, remote IP, user and request IDs) at the
+//     configured level; health endpoints are skipped.
+//   - withInFlightLimit — sheds requests beyond [config.Server.MaxInFlight]
+//     processed at once with 503 and Retry-After; health endpoints bypass it.
+//   - withGZipOptions — transparently decompresses gzip-encoded request
+//     bodies and compresses response bodies for clients that advertise gzip
+//     support, at [config.Server.GzipLevel] and from
+//     [config.Server.GzipMinSize] bytes on.
 //
 // # Route groups
 //
-// All routes are nested under the "/api" prefix:
+//	GET /healthz           — liveness check, always 200 (public, outside the
+//	                         base path).
+//
+// All other routes are nested under the configured base path ([config.Server.BasePath],
+// "/api" by default), shown here as "/api":
 //
 //	/api/auth
 //	  POST /register       — create a new user account (public).
-//	  POST /login          — authenticate and receive a JWT (public).
+//	  POST /login          — authenticate and receive a JWT (public; 429 with
+//	                         Retry-After while the login is locked out).
 //	  /settings            — account settings (requires JWT via [Handler.auth]):
 //	    POST /password/change — update the master password.
 //	    POST /otp             — enable or update the OTP secret.
 //	    DELETE /otp           — disable OTP for the account.
 //
-//	/api/data              — vault item operations (requires JWT):
+//	/api/data              — vault item operations (requires JWT; a successful
+//	                         request marks the user as seen via
+//	                         [Handler.withSyncActivity]):
 //	  POST /               — upload new vault items
 //	                         (additionally guarded by [uploadHashing]).
 //	  GET  /all            — download all vault items for the authenticated user.
@@ -43,13 +60,29 @@ import (
 //	  PUT  /update         — update existing vault items
 //	                         (additionally guarded by [updateHashing]).
 //	  DELETE /delete       — soft-delete vault items.
+//	  POST /restore        — undo the soft-delete of vault items.
+//	  GET  /history        — previous versions of one vault item
+//	                         (?client_side_id=), newest first.
 //
-//	/api/sync              — client-server synchronisation (requires JWT):
+//	/api/sync              — client-server synchronisation (requires JWT;
+//	                         marks the user as seen like /api/data):
 //	  GET /                — retrieve the diff between client and server state.
 //	  GET /specific        — retrieve states for a specific subset of items.
+//	  GET /ids             — every client_side_id of the user, tombstones
+//	                         included.
 //
 //	/api/version           — server metadata (public):
 //	  GET /                — return the current server version string.
+//	  GET /schema          — return the applied database schema version.
+//
+//	/api/admin             — operator endpoints (requires X-Admin-Token via
+//	                         [Handler.adminAuth]; 404 when no token is configured).
+//	                         Served by [Handler.InitAdmin] instead when a
+//	                         separate admin address is configured:
+//	  GET /audit           — page through a user's audit log
+//	                         (?user_id=&limit=&offset=).
+//	  GET /sync-lag        — users' last-seen time and sync lag, stalest
+//	                         first (?user_id=&limit=&offset=, all optional).
 //
 // # Method-not-allowed behaviour
 //
@@ -59,9 +92,12 @@ import (
 // a given route through error-code enumeration.
 func (h *Handler) Init() *chi.Mux {
 	router := chi.NewRouter()
-	router.Use(middleware.Recoverer, h.withTraceID, withLogging, withGZip)
+	router.Use(middleware.Recoverer, h.withTraceID, h.withRequestID, withLogging(h.accessLogLevel),
+		withInFlightLimit(h.maxInFlight), withGZipOptions(h.gzip))
 
-	router.Route("/api", func(api chi.Router) {
+	router.Get("/healthz", h.healthz)
+
+	router.Route(h.apiBasePath(), func(api chi.Router) {
 
 		// Authentication and account-management routes.
 		api.Route("/auth", func(auth chi.Router) {
@@ -82,7 +118,7 @@ func (h *Handler) Init() *chi.Mux {
 
 		// Vault item (private data) routes — JWT required for all endpoints.
 		api.Route("/data", func(data chi.Router) {
-			data.Use(h.auth)
+			data.Use(h.auth, h.withSyncActivity)
 
 			// uploadHashing verifies the transport integrity checksum of the
 			// uploaded payload before the request reaches the upload handler.
@@ -95,20 +131,29 @@ func (h *Handler) Init() *chi.Mux {
 			// update payload before the request reaches the update handler.
 			data.With(updateHashing).Put("/update", h.update)
 			data.Delete("/delete", h.delete)
+			data.Post("/restore", h.restore)
+			data.Get("/history", h.getVersionHistory)
 		})
 
 		// Client-server synchronisation routes — JWT required for all endpoints.
 		api.Route("/sync", func(sync chi.Router) {
-			sync.Use(h.auth)
+			sync.Use(h.auth, h.withSyncActivity)
 
 			sync.Get("/", h.getClientServerDiff)
 			sync.Get("/specific", h.syncSpecificUserData)
+			sync.Get("/ids", h.getAllClientSideIDs)
 		})
 
 		// Server metadata routes — public, no authentication required.
 		api.Route("/version", func(version chi.Router) {
 			version.Get("/", h.getServerVersion)
+			version.Get("/schema", h.getSchemaVersion)
 		})
+
+		// Admin routes — guarded by the shared admin token.
+		if !h.separateAdmin {
+			api.Route("/admin", h.adminRoutes)
+		}
 	})
 
 	// Replace chi's default 405 Method Not Allowed with 404 Not Found so that
@@ -117,3 +162,35 @@ func (h *Handler) Init() *chi.Mux {
 
 	return router
 }
+
+// InitAdmin constructs the router of the separate admin listener
+// ([config.Server.AdminAddress]): the health check and the admin routes,
+// under the same paths as on the API router. The API routes are not served
+// there, and the in-flight limit and gzip are not applied.
+func (h *Handler) InitAdmin() *chi.Mux {
+	router := chi.NewRouter()
+	router.Use(middleware.Recoverer, h.withTraceID, h.withRequestID, withLogging(h.accessLogLevel))
+
+	router.Get("/healthz", h.healthz)
+	router.Route(strings.TrimSuffix(h.apiBasePath(), "/")+"/admin", h.adminRoutes)
+
+	router.MethodNotAllowed(CheckHTTPMethod(router))
+
+	return router
+}
+
+func (h *Handler) adminRoutes(admin chi.Router) {
+	admin.Use(h.adminAuth)
+
+	admin.Get("/audit", h.getAuditLog)
+	admin.Get("/sync-lag", h.getSyncLag)
+}
+
+// apiBasePath returns the configured base path, or
+// [config.DefaultServerBasePath] when none is set.
+func (h *Handler) apiBasePath() string {
+	if h.basePath == "" {
+		return config.DefaultServerBasePath
+	}
+	return h.basePath
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
@@ -43,14 +44,26 @@ func (m *mockAppInfoSvc) GetAppVersion(_ context.Context) string {
 	return "test-version"
 }
 
+func (m *mockAppInfoSvc) GetSchemaVersion(_ context.Context) (int64, error) {
+	return 1, nil
+}
+
+func (m *mockAppInfoSvc) HardDelete(_ context.Context) bool {
+	return false
+}
+
 // ---- Mock: PrivateDataService ----
 
 type mockPrivateDataSvc struct {
 	uploadFn      func(ctx context.Context, req models.UploadRequest) error
+	uploadEachFn  func(ctx context.Context, req models.UploadRequest) ([]error, error)
 	downloadFn    func(ctx context.Context, req models.DownloadRequest) ([]models.PrivateData, error)
 	downloadAllFn func(ctx context.Context, userID int64) ([]models.PrivateData, error)
 	updateFn      func(ctx context.Context, req models.UpdateRequest) error
 	deleteFn      func(ctx context.Context, req models.DeleteRequest) error
+	restoreFn     func(ctx context.Context, req models.RestoreRequest) error
+	historyFn     func(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error)
+	idsFn         func(ctx context.Context, userID int64) ([]models.ClientSideIDState, error)
 }
 
 func (m *mockPrivateDataSvc) UploadPrivateData(ctx context.Context, req models.UploadRequest) error {
@@ -59,6 +72,12 @@ func (m *mockPrivateDataSvc) UploadPrivateData(ctx context.Context, req models.U
 	}
 	return nil
 }
+func (m *mockPrivateDataSvc) UploadPrivateDataEach(ctx context.Context, req models.UploadRequest) ([]error, error) {
+	if m.uploadEachFn != nil {
+		return m.uploadEachFn(ctx, req)
+	}
+	return make([]error, len(req.PrivateDataList)), nil
+}
 func (m *mockPrivateDataSvc) DownloadPrivateData(ctx context.Context, req models.DownloadRequest) ([]models.PrivateData, error) {
 	if m.downloadFn != nil {
 		return m.downloadFn(ctx, req)
@@ -83,7 +102,25 @@ func (m *mockPrivateDataSvc) DeletePrivateData(ctx context.Context, req models.D
 	}
 	return nil
 }
-func (m *mockPrivateDataSvc) DownloadUserPrivateDataStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
+func (m *mockPrivateDataSvc) RestorePrivateData(ctx context.Context, req models.RestoreRequest) error {
+	if m.restoreFn != nil {
+		return m.restoreFn(ctx, req)
+	}
+	return nil
+}
+func (m *mockPrivateDataSvc) GetVersionHistory(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error) {
+	if m.historyFn != nil {
+		return m.historyFn(ctx, userID, clientSideID)
+	}
+	return nil, nil
+}
+func (m *mockPrivateDataSvc) GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error) {
+	if m.idsFn != nil {
+		return m.idsFn(ctx, userID)
+	}
+	return nil, nil
+}
+func (m *mockPrivateDataSvc) DownloadUserPrivateDataStates(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
 	return nil, nil
 }
 func (m *mockPrivateDataSvc) DownloadSpecificUserPrivateDataStates(ctx context.Context, req models.SyncRequest) ([]models.PrivateDataState, error) {
@@ -119,6 +156,7 @@ func TestInit_PublicRoutes(t *testing.T) {
 		{http.MethodPost, "/api/auth/register"},
 		{http.MethodPost, "/api/auth/login"},
 		{http.MethodGet, "/api/version/"},
+		{http.MethodGet, "/healthz"},
 	}
 
 	for _, tt := range tests {
@@ -190,6 +228,90 @@ func TestInit_ProtectedRoutes_PassWithValidToken(t *testing.T) {
 	}
 }
 
+// ---- Custom base path ----
+
+func TestInit_CustomBasePath(t *testing.T) {
+	for _, basePath := range []string{"/vault", "/"} {
+		t.Run("base path "+basePath, func(t *testing.T) {
+			h := &Handler{
+				logger: logger.Nop(),
+				services: &service.Services{
+					AuthService:        &mockAuthSvc{},
+					AppInfoService:     &mockAppInfoSvc{},
+					PrivateDataService: &mockPrivateDataSvc{},
+				},
+				basePath: basePath,
+			}
+			router := h.Init()
+			prefix := strings.TrimSuffix(basePath, "/")
+
+			tests := []struct {
+				name     string
+				method   string
+				path     string
+				auth     bool
+				wantCode int
+			}{
+				{name: "public route", method: http.MethodGet, path: prefix + "/version/", wantCode: http.StatusOK},
+				{name: "protected route without token", method: http.MethodGet, path: prefix + "/data/all", wantCode: http.StatusUnauthorized},
+				{name: "protected route with token", method: http.MethodGet, path: prefix + "/data/all", auth: true, wantCode: http.StatusOK},
+				{name: "default prefix is not served", method: http.MethodGet, path: "/api/version/", wantCode: http.StatusNotFound},
+				{name: "health check outside the prefix", method: http.MethodGet, path: "/healthz", wantCode: http.StatusOK},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					req := httptest.NewRequest(tt.method, tt.path, nil)
+					if tt.auth {
+						req.Header.Set("Authorization", validAuthHeader())
+					}
+					rr := httptest.NewRecorder()
+					router.ServeHTTP(rr, req)
+					assert.Equal(t, tt.wantCode, rr.Code)
+				})
+			}
+		})
+	}
+}
+
+// ---- Separate admin listener ----
+
+func TestInitAdmin_SeparateListener(t *testing.T) {
+	h := &Handler{
+		logger: logger.Nop(),
+		services: &service.Services{
+			AuthService:        &mockAuthSvc{},
+			AppInfoService:     &mockAppInfoSvc{},
+			PrivateDataService: &mockPrivateDataSvc{},
+		},
+		adminToken:    "admin-secret",
+		separateAdmin: true,
+	}
+
+	tests := []struct {
+		name     string
+		router   http.Handler
+		path     string
+		wantCode int
+	}{
+		{name: "API health check", router: h.Init(), path: "/healthz", wantCode: http.StatusOK},
+		{name: "admin routes left out of the API", router: h.Init(), path: "/api/admin/audit", wantCode: http.StatusNotFound},
+		{name: "admin health check", router: h.InitAdmin(), path: "/healthz", wantCode: http.StatusOK},
+		{name: "admin routes require the token", router: h.InitAdmin(), path: "/api/admin/audit", wantCode: http.StatusUnauthorized},
+		{name: "API routes are not served", router: h.InitAdmin(), path: "/api/version/", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(adminTokenHeader, "wrong")
+			rr := httptest.NewRecorder()
+			tt.router.ServeHTTP(rr, req)
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
+
 // ---- Unknown routes return 404 ----
 
 func TestInit_UnknownRoutes_Return404(t *testing.T) {
@@ -6,12 +6,49 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/app"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/utils"
 	"github.com/MKhiriev/go-pass-keeper/models"
 )
 
+// maxStatesPageLimit caps the number of states returned by one
+// getClientServerDiff response. It is also the page size used when the client
+// does not pass "limit".
+const maxStatesPageLimit = 1000
+
+// getAllClientSideIDs returns every client_side_id the user has on the
+// server, tombstones included, so a client can spot items it created that
+// never reached the server.
+func (h *Handler) getAllClientSideIDs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromRequest(r)
+
+	userID, found := utils.GetUserIDFromContext(ctx)
+	if !found {
+		log.Error().Str("func", "*Handler.getAllClientSideIDs").Msg("no user ID was given")
+		http.Error(w, "no user ID was given", http.StatusBadRequest)
+		return
+	}
+
+	ids, err := h.services.PrivateDataService.GetAllClientSideIDs(ctx, userID)
+	if err != nil {
+		log.Err(err).Str("func", "*Handler.getAllClientSideIDs").Msg("error getting client-side IDs")
+		resp := responseFromError(err)
+		http.Error(w, resp.message, resp.status)
+		return
+	}
+
+	utils.WriteJSON(w, ids, http.StatusOK)
+}
+
+// getClientServerDiff returns one page of the user's state descriptors.
+// The optional "after" query parameter is the cursor from the previous
+// page's next_after; "limit" is capped at [maxStatesPageLimit]. next_after is
+// omitted on the last page. Tombstones of deleted items are included unless
+// "include_deleted" is false.
 func (h *Handler) getClientServerDiff(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.FromRequest(r)
@@ -23,7 +60,40 @@ func (h *Handler) getClientServerDiff(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	privateDataStates, err := h.services.PrivateDataService.DownloadUserPrivateDataStates(ctx, userID)
+	request := models.StatesPageRequest{UserID: userID, Limit: maxStatesPageLimit}
+	query := r.URL.Query()
+	if raw := query.Get("after"); raw != "" {
+		after, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			log.Err(err).Str("func", "*Handler.getClientServerDiff").Msg("invalid after")
+			http.Error(w, app.MsgInvalidPage, http.StatusBadRequest)
+			return
+		}
+		request.After = after
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Err(err).Str("func", "*Handler.getClientServerDiff").Msg("invalid limit")
+			http.Error(w, app.MsgInvalidPage, http.StatusBadRequest)
+			return
+		}
+		// Zero keeps the default; negative values are rejected by validation.
+		if limit != 0 && limit < maxStatesPageLimit {
+			request.Limit = limit
+		}
+	}
+	if raw := query.Get("include_deleted"); raw != "" {
+		includeDeleted, err := strconv.ParseBool(raw)
+		if err != nil {
+			log.Err(err).Str("func", "*Handler.getClientServerDiff").Msg("invalid include_deleted")
+			http.Error(w, app.MsgInvalidIncludeDeleted, http.StatusBadRequest)
+			return
+		}
+		request.ExcludeDeleted = !includeDeleted
+	}
+
+	privateDataStates, err := h.services.PrivateDataService.DownloadUserPrivateDataStates(ctx, request)
 	if err != nil {
 		log.Error().Str("func", "*Handler.getClientServerDiff").Msg("error getting user private data states")
 		resp := responseFromError(err)
@@ -35,6 +105,9 @@ func (h *Handler) getClientServerDiff(w http.ResponseWriter, r *http.Request) {
 		PrivateDataStates: privateDataStates,
 		Length:            len(privateDataStates),
 	}
+	if len(privateDataStates) == request.Limit {
+		response.NextAfter = privateDataStates[len(privateDataStates)-1].ID
+	}
 
 	utils.WriteJSON(w, response, http.StatusOK)
 }
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// getSyncLag returns a page of users with their last-seen time and sync lag,
+// stalest first. "user_id", "limit" and "offset" are optional; without
+// "user_id" every user seen so far is listed.
+func (h *Handler) getSyncLag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromRequest(r)
+
+	query := r.URL.Query()
+	var request models.SyncLagRequest
+	if raw := query.Get("user_id"); raw != "" {
+		userID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			log.Err(err).Str("func", "*Handler.getSyncLag").Msg("invalid user_id")
+			http.Error(w, "invalid user_id", http.StatusBadRequest)
+			return
+		}
+		request.UserID = userID
+	}
+	for name, dst := range map[string]*int{"limit": &request.Limit, "offset": &request.Offset} {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		var err error
+		if *dst, err = strconv.Atoi(raw); err != nil {
+			log.Err(err).Str("func", "*Handler.getSyncLag").Msg("invalid " + name)
+			http.Error(w, "invalid "+name, http.StatusBadRequest)
+			return
+		}
+	}
+
+	lags, err := h.services.SyncActivityService.GetSyncLag(ctx, request)
+	if err != nil {
+		log.Err(err).Str("func", "*Handler.getSyncLag").Msg("error getting sync lag")
+		resp := responseFromError(err)
+		http.Error(w, resp.message, resp.status)
+		return
+	}
+
+	utils.WriteJSON(w, lags, http.StatusOK)
+}
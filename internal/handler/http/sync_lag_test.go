@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSyncActivitySvc struct {
+	recorded  []int64
+	recordErr error
+	got       models.SyncLagRequest
+	lags      []models.SyncLag
+	err       error
+}
+
+func (m *mockSyncActivitySvc) RecordActivity(_ context.Context, userID int64) error {
+	m.recorded = append(m.recorded, userID)
+	return m.recordErr
+}
+
+func (m *mockSyncActivitySvc) GetSyncLag(_ context.Context, request models.SyncLagRequest) ([]models.SyncLag, error) {
+	m.got = request
+	return m.lags, m.err
+}
+
+func TestWithSyncActivity(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		auth         bool
+		data         *mockPrivateDataSvc
+		recordErr    error
+		wantStatus   int
+		wantRecorded []int64
+	}{
+		{name: "successful sync request", method: http.MethodGet, path: "/api/sync/ids", auth: true, wantStatus: http.StatusOK, wantRecorded: []int64{1}},
+		{name: "successful vault request", method: http.MethodGet, path: "/api/data/all", auth: true, wantStatus: http.StatusOK, wantRecorded: []int64{1}},
+		{
+			name: "failed request", method: http.MethodGet, path: "/api/data/all", auth: true,
+			data: &mockPrivateDataSvc{downloadAllFn: func(context.Context, int64) ([]models.PrivateData, error) {
+				return nil, errors.New("db down")
+			}},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{name: "unauthenticated request", method: http.MethodGet, path: "/api/sync/ids", wantStatus: http.StatusUnauthorized},
+		{name: "recording error does not change the response", method: http.MethodGet, path: "/api/sync/ids", auth: true, recordErr: errors.New("db down"), wantStatus: http.StatusOK, wantRecorded: []int64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			if data == nil {
+				data = &mockPrivateDataSvc{}
+			}
+			activity := &mockSyncActivitySvc{recordErr: tt.recordErr}
+			h := &Handler{
+				logger: logger.Nop(),
+				services: &service.Services{
+					AuthService:         &mockAuthSvc{},
+					AppInfoService:      &mockAppInfoSvc{},
+					PrivateDataService:  data,
+					SyncActivityService: activity,
+				},
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.auth {
+				req.Header.Set("Authorization", validAuthHeader())
+			}
+			rec := httptest.NewRecorder()
+			h.Init().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantRecorded, activity.recorded)
+		})
+	}
+}
+
+func TestGetSyncLag_Query(t *testing.T) {
+	seen := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      string
+		svcErr     error
+		wantStatus int
+		wantReq    models.SyncLagRequest
+	}{
+		{name: "every user", query: "", wantStatus: http.StatusOK, wantReq: models.SyncLagRequest{}},
+		{name: "one user with pagination", query: "user_id=7&limit=10&offset=30", wantStatus: http.StatusOK, wantReq: models.SyncLagRequest{UserID: 7, Limit: 10, Offset: 30}},
+		{name: "invalid user_id", query: "user_id=abc", wantStatus: http.StatusBadRequest},
+		{name: "invalid limit", query: "limit=abc", wantStatus: http.StatusBadRequest},
+		{name: "service validation error", query: "offset=-1", svcErr: service.ErrInvalidDataProvided, wantStatus: http.StatusBadRequest, wantReq: models.SyncLagRequest{Offset: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lags := []models.SyncLag{{UserID: 7, LastSeenAt: seen, LagSeconds: 5400}}
+			svc := &mockSyncActivitySvc{lags: lags, err: tt.svcErr}
+			router := NewHandler(&service.Services{SyncActivityService: svc}, config.Server{AdminToken: "secret"}, logger.Nop()).Init()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/sync-lag?"+tt.query, nil)
+			req.Header.Set(adminTokenHeader, "secret")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.svcErr == nil && tt.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.wantReq, svc.got)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got []models.SyncLag
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, lags, got)
+		})
+	}
+}
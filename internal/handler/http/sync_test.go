@@ -21,7 +21,7 @@ import (
 )
 
 type mockPrivateDataService struct {
-	downloadUserStatesFn     func(ctx context.Context, userID int64) ([]models.PrivateDataState, error)
+	downloadUserStatesFn     func(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error)
 	downloadSpecificStatesFn func(ctx context.Context, req models.SyncRequest) ([]models.PrivateDataState, error)
 }
 
@@ -34,8 +34,8 @@ func (m *mockPrivateDataService) DownloadPrivateData(ctx context.Context, downlo
 func (m *mockPrivateDataService) DownloadAllPrivateData(ctx context.Context, userID int64) ([]models.PrivateData, error) {
 	return nil, nil
 }
-func (m *mockPrivateDataService) DownloadUserPrivateDataStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
-	return m.downloadUserStatesFn(ctx, userID)
+func (m *mockPrivateDataService) DownloadUserPrivateDataStates(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
+	return m.downloadUserStatesFn(ctx, request)
 }
 func (m *mockPrivateDataService) DownloadSpecificUserPrivateDataStates(ctx context.Context, req models.SyncRequest) ([]models.PrivateDataState, error) {
 	return m.downloadSpecificStatesFn(ctx, req)
@@ -46,6 +46,21 @@ func (m *mockPrivateDataService) UpdatePrivateData(ctx context.Context, updateRe
 func (m *mockPrivateDataService) DeletePrivateData(ctx context.Context, deleteRequests models.DeleteRequest) error {
 	return nil
 }
+func (m *mockPrivateDataService) RestorePrivateData(ctx context.Context, restoreRequests models.RestoreRequest) error {
+	return nil
+}
+
+func (m *mockPrivateDataService) GetVersionHistory(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error) {
+	return nil, nil
+}
+
+func (m *mockPrivateDataService) UploadPrivateDataEach(ctx context.Context, req models.UploadRequest) ([]error, error) {
+	return nil, nil
+}
+
+func (m *mockPrivateDataService) GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error) {
+	return nil, nil
+}
 
 func newHandlerWithPrivateDataService(pds service.PrivateDataService) *Handler {
 	return &Handler{
@@ -83,7 +98,7 @@ func TestGetClientServerDiff_Success(t *testing.T) {
 	}
 
 	mockSvc := &mockPrivateDataService{
-		downloadUserStatesFn: func(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
+		downloadUserStatesFn: func(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
 			return expected, nil
 		},
 	}
@@ -117,6 +132,101 @@ func TestGetClientServerDiff_Success(t *testing.T) {
 	}
 }
 
+func TestGetClientServerDiff_Pagination(t *testing.T) {
+	page := []models.PrivateDataState{
+		{ID: 8, ClientSideID: "id8"},
+		{ID: 13, ClientSideID: "id13"},
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		wantRequest   models.StatesPageRequest
+		wantNextAfter int64
+	}{
+		{
+			name:        "defaults to the maximum page",
+			query:       "",
+			wantRequest: models.StatesPageRequest{UserID: 1, Limit: maxStatesPageLimit},
+		},
+		{
+			name:          "full page returns cursor",
+			query:         "?after=5&limit=2",
+			wantRequest:   models.StatesPageRequest{UserID: 1, After: 5, Limit: 2},
+			wantNextAfter: 13,
+		},
+		{
+			name:        "short page is the last one",
+			query:       "?after=5&limit=3",
+			wantRequest: models.StatesPageRequest{UserID: 1, After: 5, Limit: 3},
+		},
+		{
+			name:        "limit above maximum is capped",
+			query:       "?limit=50000",
+			wantRequest: models.StatesPageRequest{UserID: 1, Limit: maxStatesPageLimit},
+		},
+		{
+			name:        "tombstones can be left out",
+			query:       "?include_deleted=false",
+			wantRequest: models.StatesPageRequest{UserID: 1, Limit: maxStatesPageLimit, ExcludeDeleted: true},
+		},
+		{
+			name:        "tombstones requested explicitly",
+			query:       "?include_deleted=true&limit=3",
+			wantRequest: models.StatesPageRequest{UserID: 1, Limit: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got models.StatesPageRequest
+			mockSvc := &mockPrivateDataService{
+				downloadUserStatesFn: func(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
+					got = request
+					return page, nil
+				},
+			}
+
+			h := newHandlerWithPrivateDataService(mockSvc)
+			req := httptest.NewRequest(http.MethodGet, "/sync"+tt.query, nil)
+			req = req.WithContext(withUserID(req.Context(), 1))
+			rr := httptest.NewRecorder()
+			h.getClientServerDiff(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rr.Code)
+			}
+			if got != tt.wantRequest {
+				t.Fatalf("service request = %+v, want %+v", got, tt.wantRequest)
+			}
+
+			var resp models.SyncResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if resp.NextAfter != tt.wantNextAfter {
+				t.Fatalf("next_after = %d, want %d", resp.NextAfter, tt.wantNextAfter)
+			}
+		})
+	}
+}
+
+func TestGetClientServerDiff_InvalidPageParams(t *testing.T) {
+	for _, query := range []string{"?after=abc", "?limit=ten", "?include_deleted=maybe"} {
+		t.Run(query, func(t *testing.T) {
+			h := newHandlerWithPrivateDataService(&mockPrivateDataService{})
+			req := httptest.NewRequest(http.MethodGet, "/sync"+query, nil)
+			req = req.WithContext(withUserID(req.Context(), 1))
+			rr := httptest.NewRecorder()
+			h.getClientServerDiff(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", rr.Code)
+			}
+		})
+	}
+}
+
 func TestSyncSpecificUserData_Success(t *testing.T) {
 	now := time.Unix(1700000000, 0).UTC()
 
@@ -172,7 +282,7 @@ func TestSyncSpecificUserData_Success(t *testing.T) {
 
 func TestGetClientServerDiff_ServiceError(t *testing.T) {
 	mockSvc := &mockPrivateDataService{
-		downloadUserStatesFn: func(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
+		downloadUserStatesFn: func(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
 			return nil, errors.New("service error")
 		},
 	}
@@ -5,6 +5,10 @@ package http
 
 import (
 	"net/http"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/MKhiriev/go-pass-keeper/models"
 )
 
 func (h *Handler) getServerVersion(w http.ResponseWriter, r *http.Request) {
@@ -12,3 +16,28 @@ func (h *Handler) getServerVersion(w http.ResponseWriter, r *http.Request) {
 
 	w.Write([]byte(serverVersion))
 }
+
+// getSchemaVersion reports the version of the newest applied database
+// migration. Clients refuse to sync when it is ahead of the schema they were
+// built for. The response also tells clients whether deleted items are
+// removed (hard_delete) or kept as tombstones.
+func (h *Handler) getSchemaVersion(w http.ResponseWriter, r *http.Request) {
+	version, err := h.services.AppInfoService.GetSchemaVersion(r.Context())
+	if err != nil {
+		logger.FromRequest(r).Err(err).Str("func", "*Handler.getSchemaVersion").Msg("error getting schema version")
+		resp := responseFromError(err)
+		http.Error(w, resp.message, resp.status)
+		return
+	}
+
+	utils.WriteJSON(w, models.SchemaVersionResponse{
+		SchemaVersion: version,
+		HardDelete:    h.services.AppInfoService.HardDelete(r.Context()),
+	}, http.StatusOK)
+}
+
+// healthz reports that the server process is up. It touches no dependencies,
+// so it stays cheap enough for frequent probes.
+func (h *Handler) healthz(w http.ResponseWriter, _ *http.Request) {
+	w.Write([]byte("ok"))
+}
@@ -5,10 +5,12 @@ package http
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
 	"github.com/stretchr/testify/assert"
@@ -21,13 +23,24 @@ import (
 
 // mockAppInfoService implements service.AppInfoService for testing.
 type mockAppInfoService struct {
-	version string
+	version       string
+	schemaVersion int64
+	schemaErr     error
+	hardDelete    bool
 }
 
 func (m *mockAppInfoService) GetAppVersion(_ context.Context) string {
 	return m.version
 }
 
+func (m *mockAppInfoService) GetSchemaVersion(_ context.Context) (int64, error) {
+	return m.schemaVersion, m.schemaErr
+}
+
+func (m *mockAppInfoService) HardDelete(_ context.Context) bool {
+	return m.hardDelete
+}
+
 // newHandlerWithAppInfo builds a Handler whose AppInfoService is replaced
 // with the provided mock. All other service fields are left nil because
 // getServerVersion does not use them.
@@ -35,6 +48,7 @@ func newHandlerWithAppInfo(t *testing.T, svc service.AppInfoService) *Handler {
 	t.Helper()
 	return NewHandler(
 		&service.Services{AppInfoService: svc},
+		config.Server{},
 		logger.Nop(),
 	)
 }
@@ -106,3 +120,38 @@ func TestGetServerVersion_ContentTypeNotJSON(t *testing.T) {
 	// Handler writes plain text — Content-Type must NOT be application/json.
 	assert.NotEqual(t, "application/json", rec.Header().Get("Content-Type"))
 }
+
+func TestGetSchemaVersion_WritesJSON(t *testing.T) {
+	h := newHandlerWithAppInfo(t, &mockAppInfoService{schemaVersion: 8})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/version/schema", nil)
+	rec := httptest.NewRecorder()
+
+	h.getSchemaVersion(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"schema_version":8,"hard_delete":false}`, rec.Body.String())
+}
+
+func TestGetSchemaVersion_ReportsHardDelete(t *testing.T) {
+	h := newHandlerWithAppInfo(t, &mockAppInfoService{schemaVersion: 8, hardDelete: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/version/schema", nil)
+	rec := httptest.NewRecorder()
+
+	h.getSchemaVersion(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"schema_version":8,"hard_delete":true}`, rec.Body.String())
+}
+
+func TestGetSchemaVersion_Error(t *testing.T) {
+	h := newHandlerWithAppInfo(t, &mockAppInfoService{schemaErr: errors.New("db down")})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/version/schema", nil)
+	rec := httptest.NewRecorder()
+
+	h.getSchemaVersion(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -13,10 +13,15 @@ package logger
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -40,7 +45,8 @@ type Logger struct {
 //   - a "func" caller field that records the fully-qualified function name
 //     (instead of the default file:line format) for easier log navigation.
 //
-// Output is written to os.Stdout in JSON format.
+// Output is written to os.Stdout in JSON format, with [DefaultRedactKeys]
+// redacted as described for [New].
 func NewLogger(role string) *Logger {
 	zerolog.SetGlobalLevel(zerolog.DebugLevel)
 	zerolog.CallerMarshalFunc = func(pc uintptr, file string, line int) string {
@@ -48,7 +54,7 @@ func NewLogger(role string) *Logger {
 	}
 
 	zerolog.CallerFieldName = "func"
-	logger := zerolog.New(os.Stdout).With().
+	logger := zerolog.New(newRedactWriter(os.Stdout, nil, 0)).With().
 		Str("role", role).
 		Timestamp().
 		Caller().
@@ -56,6 +62,126 @@ func NewLogger(role string) *Logger {
 
 	return &Logger{logger}
 }
+
+// Supported values for [Options.Format].
+const (
+	// FormatJSON emits one JSON object per log entry. It is the default for
+	// the server, where logs are usually shipped to a collector.
+	FormatJSON = "json"
+
+	// FormatConsole emits human-friendly, colourless key=value lines. It is
+	// the default for the client.
+	FormatConsole = "console"
+)
+
+// DebugEnv is the name of the environment variable that forces the Debug
+// level regardless of [Options.Level]. Any value accepted by
+// [strconv.ParseBool] as true enables it (e.g. GO_PASS_KEEPER_DEBUG=1),
+// which makes it possible to raise verbosity in the field without touching
+// the config file.
+const DebugEnv = "GO_PASS_KEEPER_DEBUG"
+
+// ErrInvalidLogLevel is returned by [New] when [Options.Level] is not one of
+// "debug", "info", "warn" or "error".
+var ErrInvalidLogLevel = errors.New("invalid log level")
+
+// ErrInvalidLogFormat is returned by [New] when [Options.Format] is neither
+// [FormatJSON] nor [FormatConsole].
+var ErrInvalidLogFormat = errors.New("invalid log format")
+
+// Options controls how [New] builds a *Logger.
+type Options struct {
+	// Level is the minimum level that is emitted: "debug", "info", "warn" or
+	// "error". Empty means "info".
+	Level string
+
+	// Format is the output encoding: [FormatJSON] or [FormatConsole].
+	// Empty means [FormatJSON].
+	Format string
+
+	// Output is the destination for log entries. Nil means os.Stdout.
+	Output io.Writer
+
+	// RedactKeys lists the field keys whose values are replaced with
+	// [RedactedValue]. Nil means [DefaultRedactKeys]; an empty non-nil slice
+	// disables redaction by key.
+	RedactKeys []string
+
+	// MaxValueLength is the length above which string values are truncated.
+	// Zero means [DefaultMaxValueLength].
+	MaxValueLength int
+}
+
+// New constructs a *Logger for the given role label using opts.
+//
+// The logger carries the same "role", timestamp and "func" caller fields as
+// [NewLogger], but its minimum level and output format are taken from opts.
+// If the [DebugEnv] environment variable is set to a true value, the level is
+// forced to Debug. Every entry passes through a redacting writer that masks
+// the values of [Options.RedactKeys] and truncates overly long strings, so a
+// token or password logged by mistake never reaches the output.
+//
+// Returns [ErrInvalidLogLevel] or [ErrInvalidLogFormat] (wrapped) when opts
+// contain an unsupported value.
+func New(role string, opts Options) (*Logger, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	if debug, _ := strconv.ParseBool(os.Getenv(DebugEnv)); debug {
+		level = zerolog.DebugLevel
+	}
+
+	output := opts.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	switch strings.ToLower(opts.Format) {
+	case "", FormatJSON:
+	case FormatConsole:
+		output = zerolog.ConsoleWriter{Out: output, NoColor: true, TimeFormat: "2006-01-02 15:04:05"}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidLogFormat, opts.Format)
+	}
+	output = newRedactWriter(output, opts.RedactKeys, opts.MaxValueLength)
+
+	zerolog.CallerMarshalFunc = func(pc uintptr, file string, line int) string {
+		return runtime.FuncForPC(pc).Name()
+	}
+	zerolog.CallerFieldName = "func"
+
+	logger := zerolog.New(output).Level(level).With().
+		Str("role", role).
+		Timestamp().
+		Caller().
+		Logger()
+
+	return &Logger{logger}, nil
+}
+
+// ParseLevel converts a textual level ("debug", "info", "warn" or "error",
+// case-insensitive) into a zerolog level. An empty string yields Info.
+//
+// Returns a wrapped [ErrInvalidLogLevel] for any other value.
+func ParseLevel(level string) (zerolog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return zerolog.DebugLevel, nil
+	case "", "info":
+		return zerolog.InfoLevel, nil
+	case "warn", "warning":
+		return zerolog.WarnLevel, nil
+	case "error":
+		return zerolog.ErrorLevel, nil
+	default:
+		return zerolog.NoLevel, fmt.Errorf("%w: %q", ErrInvalidLogLevel, level)
+	}
+}
+
+// NewClientLogger constructs a *Logger that appends JSON entries to a "logs"
+// file located next to the client executable, falling back to os.Stdout when
+// the file cannot be opened.
 func NewClientLogger(role string) *Logger {
 	zerolog.SetGlobalLevel(zerolog.DebugLevel)
 	zerolog.CallerMarshalFunc = func(pc uintptr, file string, line int) string {
@@ -71,7 +197,7 @@ func NewClientLogger(role string) *Logger {
 		logFile = os.Stdout // fallback to stdout if file can't be opened
 	}
 
-	logger := zerolog.New(logFile).With().
+	logger := zerolog.New(newRedactWriter(logFile, nil, 0)).With().
 		Str("role", role).
 		Timestamp().
 		Caller().
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/rs/zerolog"
@@ -167,3 +168,201 @@ func TestFromRequest_ReturnsAttachedLogger(t *testing.T) {
 	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
 	assert.Equal(t, "req-value", entry["req-key"])
 }
+
+// TestNew_RespectsLevel verifies that a logger built with New drops entries
+// below the configured level.
+func TestNew_RespectsLevel(t *testing.T) {
+	t.Setenv(DebugEnv, "")
+
+	tests := []struct {
+		name      string
+		level     string
+		wantDebug bool
+		wantInfo  bool
+		wantWarn  bool
+	}{
+		{name: "empty defaults to info", level: "", wantDebug: false, wantInfo: true, wantWarn: true},
+		{name: "debug", level: "debug", wantDebug: true, wantInfo: true, wantWarn: true},
+		{name: "info", level: "info", wantDebug: false, wantInfo: true, wantWarn: true},
+		{name: "warn", level: "WARN", wantDebug: false, wantInfo: false, wantWarn: true},
+		{name: "error", level: "error", wantDebug: false, wantInfo: false, wantWarn: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l, err := New("level-role", Options{Level: tt.level, Output: &buf})
+			require.NoError(t, err)
+
+			l.Debug().Msg("debug-msg")
+			l.Info().Msg("info-msg")
+			l.Warn().Msg("warn-msg")
+
+			assert.Equal(t, tt.wantDebug, strings.Contains(buf.String(), "debug-msg"))
+			assert.Equal(t, tt.wantInfo, strings.Contains(buf.String(), "info-msg"))
+			assert.Equal(t, tt.wantWarn, strings.Contains(buf.String(), "warn-msg"))
+		})
+	}
+}
+
+// TestNew_DebugEnvOverridesLevel verifies that DebugEnv forces Debug output
+// even when a higher level is configured.
+func TestNew_DebugEnvOverridesLevel(t *testing.T) {
+	t.Setenv(DebugEnv, "true")
+
+	var buf bytes.Buffer
+	l, err := New("env-role", Options{Level: "error", Output: &buf})
+	require.NoError(t, err)
+
+	l.Debug().Msg("debug-msg")
+	assert.Contains(t, buf.String(), "debug-msg")
+}
+
+// TestNew_Format verifies JSON and console encodings and rejection of
+// unknown formats and levels.
+func TestNew_Format(t *testing.T) {
+	t.Setenv(DebugEnv, "")
+
+	var jsonBuf bytes.Buffer
+	l, err := New("json-role", Options{Format: FormatJSON, Output: &jsonBuf})
+	require.NoError(t, err)
+	l.Info().Msg("json")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(jsonBuf.Bytes(), &entry))
+	assert.Equal(t, "json-role", entry["role"])
+
+	var consoleBuf bytes.Buffer
+	l, err = New("console-role", Options{Format: FormatConsole, Output: &consoleBuf})
+	require.NoError(t, err)
+	l.Info().Msg("console")
+
+	assert.Error(t, json.Unmarshal(consoleBuf.Bytes(), &entry))
+	assert.Contains(t, consoleBuf.String(), "console")
+	assert.Contains(t, consoleBuf.String(), "role=console-role")
+
+	_, err = New("bad", Options{Format: "xml"})
+	assert.ErrorIs(t, err, ErrInvalidLogFormat)
+
+	_, err = New("bad", Options{Level: "verbose"})
+	assert.ErrorIs(t, err, ErrInvalidLogLevel)
+}
+
+// TestNew_RedactsSensitiveFields verifies that values of redacted keys are
+// masked at any depth, other fields are untouched and long strings are
+// truncated, in both JSON and console formats.
+func TestNew_RedactsSensitiveFields(t *testing.T) {
+	t.Setenv(DebugEnv, "")
+
+	tests := []struct {
+		name  string
+		log   func(l *Logger)
+		check func(t *testing.T, out string)
+	}{
+		{
+			name: "top-level keys",
+			log: func(l *Logger) {
+				l.Info().
+					Str("password", "hunter2").
+					Str("Authorization", "Bearer abc.def").
+					Str("master_password", "m4ster").
+					Str("refresh_token", "r-tok").
+					Str("user_id", "42").
+					Msg("login")
+			},
+			check: func(t *testing.T, out string) {
+				var entry map[string]any
+				require.NoError(t, json.Unmarshal([]byte(out), &entry))
+				assert.Equal(t, RedactedValue, entry["password"])
+				assert.Equal(t, RedactedValue, entry["Authorization"])
+				assert.Equal(t, RedactedValue, entry["master_password"])
+				assert.Equal(t, RedactedValue, entry["refresh_token"])
+				assert.Equal(t, "42", entry["user_id"])
+				assert.Equal(t, "login", entry["message"])
+			},
+		},
+		{
+			name: "nested struct fields",
+			log: func(l *Logger) {
+				l.Info().Any("config", struct {
+					TokenSignKey  string
+					TokenIssuer   string
+					Storage       map[string]string
+					AdminTokens   []string
+					TokenDuration int
+				}{
+					TokenSignKey:  "sign-secret",
+					TokenIssuer:   "gpk",
+					Storage:       map[string]string{"DSN": "postgres://u:p@h/db"},
+					TokenDuration: 60,
+				}).Msg("configs")
+			},
+			check: func(t *testing.T, out string) {
+				assert.NotContains(t, out, "sign-secret")
+				assert.NotContains(t, out, "postgres://")
+				assert.Contains(t, out, `"TokenIssuer":"gpk"`)
+				assert.Contains(t, out, `"TokenDuration":60`)
+			},
+		},
+		{
+			name: "credential material",
+			log: func(l *Logger) {
+				l.Info().Any("user", struct {
+					Login              string `json:"login"`
+					AuthHash           string `json:"auth_hash"`
+					EncryptedMasterKey string `json:"encrypted_master_key"`
+				}{
+					Login:              "alice",
+					AuthHash:           "auth-hash-value",
+					EncryptedMasterKey: "wrapped-key-value",
+				}).Str("AuthHash", "auth-hash-value").Msg("register")
+			},
+			check: func(t *testing.T, out string) {
+				assert.NotContains(t, out, "auth-hash-value")
+				assert.NotContains(t, out, "wrapped-key-value")
+				assert.Contains(t, out, `"login":"alice"`)
+			},
+		},
+		{
+			name: "long values are truncated",
+			log: func(l *Logger) {
+				l.Info().Str("payload", strings.Repeat("x", 100)).Msg("big")
+			},
+			check: func(t *testing.T, out string) {
+				var entry map[string]any
+				require.NoError(t, json.Unmarshal([]byte(out), &entry))
+				assert.Equal(t, strings.Repeat("x", 16)+truncatedSuffix, entry["payload"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l, err := New("redact-role", Options{Output: &buf, MaxValueLength: 16})
+			require.NoError(t, err)
+
+			tt.log(l)
+			tt.check(t, buf.String())
+		})
+	}
+
+	t.Run("console format", func(t *testing.T) {
+		var buf bytes.Buffer
+		l, err := New("redact-role", Options{Format: FormatConsole, Output: &buf})
+		require.NoError(t, err)
+
+		l.Info().Str("token", "jwt-value").Msg("console")
+		assert.NotContains(t, buf.String(), "jwt-value")
+		assert.Contains(t, buf.String(), "token=***")
+	})
+
+	t.Run("unchanged entries keep field order", func(t *testing.T) {
+		var buf bytes.Buffer
+		l, err := New("redact-role", Options{Output: &buf})
+		require.NoError(t, err)
+
+		l.Info().Str("z", "1").Str("a", "2").Msg("order")
+		assert.Less(t, strings.Index(buf.String(), `"z"`), strings.Index(buf.String(), `"a"`))
+	})
+}
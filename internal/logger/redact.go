@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactedValue replaces the value of every field whose key is redacted.
+const RedactedValue = "***"
+
+// DefaultMaxValueLength is the length, in bytes, above which string values
+// are truncated when [Options.MaxValueLength] is zero.
+const DefaultMaxValueLength = 2048
+
+// truncatedSuffix is appended to string values cut to the maximum length.
+const truncatedSuffix = "...(truncated)"
+
+// DefaultRedactKeys is the set of field keys redacted when
+// [Options.RedactKeys] is nil. Keys are matched case-insensitively, ignoring
+// "_" and "-", against the end of a field key, so "token" also covers
+// "access_token" and "AdminToken", and "hash_key" covers "PasswordHashKey".
+var DefaultRedactKeys = []string{
+	"password",
+	"master_password",
+	"authorization",
+	"token",
+	"secret",
+	"sign_key",
+	"hash_key",
+	"dsn",
+	"auth_hash",
+	"encrypted_master_key",
+}
+
+// redactWriter rewrites every JSON log entry before passing it on: values of
+// redacted keys are replaced with [RedactedValue] at any nesting depth and
+// overly long strings are truncated. Entries that need no changes are passed
+// through byte for byte so field order is preserved.
+type redactWriter struct {
+	out    io.Writer
+	keys   []string
+	maxLen int
+}
+
+// newRedactWriter wraps out with redaction of keys and truncation of string
+// values longer than maxLen. A nil keys slice selects [DefaultRedactKeys]; a
+// non-positive maxLen selects [DefaultMaxValueLength].
+func newRedactWriter(out io.Writer, keys []string, maxLen int) *redactWriter {
+	if keys == nil {
+		keys = DefaultRedactKeys
+	}
+	if maxLen <= 0 {
+		maxLen = DefaultMaxValueLength
+	}
+
+	normalized := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k = normalizeKey(k); k != "" {
+			normalized = append(normalized, k)
+		}
+	}
+
+	return &redactWriter{out: out, keys: normalized, maxLen: maxLen}
+}
+
+// Write implements io.Writer. zerolog calls it once per log entry. Entries
+// that are not valid JSON objects are written unchanged.
+func (w *redactWriter) Write(p []byte) (int, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+
+	var entry map[string]any
+	if err := dec.Decode(&entry); err != nil {
+		return w.out.Write(p)
+	}
+	if !w.redactMap(entry) {
+		return w.out.Write(p)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return w.out.Write(p)
+	}
+	if _, err = w.out.Write(append(data, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// RedactJSON returns the JSON object data with the values of
+// [DefaultRedactKeys] replaced and overly long strings truncated, exactly as
+// they would be in a log entry. It lets other output that may carry secrets,
+// such as a configuration dump, share the loggers' redaction rules.
+func RedactJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	newRedactWriter(io.Discard, nil, 0).redactMap(obj)
+	return json.Marshal(obj)
+}
+
+// redactMap redacts m in place and reports whether anything changed.
+func (w *redactWriter) redactMap(m map[string]any) bool {
+	changed := false
+	for k, v := range m {
+		if w.isRedacted(k) {
+			if v != RedactedValue {
+				m[k] = RedactedValue
+				changed = true
+			}
+			continue
+		}
+		if nv, ok := w.redactValue(v); ok {
+			m[k] = nv
+			changed = true
+		}
+	}
+	return changed
+}
+
+// redactValue returns the redacted form of v and whether it differs from v.
+func (w *redactWriter) redactValue(v any) (any, bool) {
+	switch val := v.(type) {
+	case string:
+		if len(val) > w.maxLen {
+			cut := w.maxLen
+			for cut > 0 && !utf8.RuneStart(val[cut]) {
+				cut--
+			}
+			return val[:cut] + truncatedSuffix, true
+		}
+	case map[string]any:
+		return val, w.redactMap(val)
+	case []any:
+		changed := false
+		for i, item := range val {
+			if nv, ok := w.redactValue(item); ok {
+				val[i] = nv
+				changed = true
+			}
+		}
+		return val, changed
+	}
+	return v, false
+}
+
+// isRedacted reports whether key ends with one of the configured keys.
+func (w *redactWriter) isRedacted(key string) bool {
+	key = normalizeKey(key)
+	for _, k := range w.keys {
+		if strings.HasSuffix(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeKey lowercases key and strips "_" and "-" so that snake_case,
+// kebab-case and CamelCase spellings compare equal.
+func normalizeKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
@@ -14,6 +14,7 @@ package mock
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 	time "time"
 
@@ -60,6 +61,35 @@ func (mr *MockClientCryptoServiceMockRecorder) ComputeHash(payload any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ComputeHash", reflect.TypeOf((*MockClientCryptoService)(nil).ComputeHash), payload)
 }
 
+// DecryptBinary mocks base method.
+func (m *MockClientCryptoService) DecryptBinary(dst io.Writer, src io.Reader, meta models.BinaryData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecryptBinary", dst, src, meta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DecryptBinary indicates an expected call of DecryptBinary.
+func (mr *MockClientCryptoServiceMockRecorder) DecryptBinary(dst, src, meta any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptBinary", reflect.TypeOf((*MockClientCryptoService)(nil).DecryptBinary), dst, src, meta)
+}
+
+// DecryptMetadata mocks base method.
+func (m *MockClientCryptoService) DecryptMetadata(cipher models.PrivateDataPayload) (models.DecipheredPayload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecryptMetadata", cipher)
+	ret0, _ := ret[0].(models.DecipheredPayload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecryptMetadata indicates an expected call of DecryptMetadata.
+func (mr *MockClientCryptoServiceMockRecorder) DecryptMetadata(cipher any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptMetadata", reflect.TypeOf((*MockClientCryptoService)(nil).DecryptMetadata), cipher)
+}
+
 // DecryptPayload mocks base method.
 func (m *MockClientCryptoService) DecryptPayload(cipher models.PrivateDataPayload) (models.DecipheredPayload, error) {
 	m.ctrl.T.Helper()
@@ -75,6 +105,36 @@ func (mr *MockClientCryptoServiceMockRecorder) DecryptPayload(cipher any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptPayload", reflect.TypeOf((*MockClientCryptoService)(nil).DecryptPayload), cipher)
 }
 
+// DecryptPayloadOutdated mocks base method.
+func (m *MockClientCryptoService) DecryptPayloadOutdated(cipher models.PrivateDataPayload) (models.DecipheredPayload, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecryptPayloadOutdated", cipher)
+	ret0, _ := ret[0].(models.DecipheredPayload)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DecryptPayloadOutdated indicates an expected call of DecryptPayloadOutdated.
+func (mr *MockClientCryptoServiceMockRecorder) DecryptPayloadOutdated(cipher any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptPayloadOutdated", reflect.TypeOf((*MockClientCryptoService)(nil).DecryptPayloadOutdated), cipher)
+}
+
+// EncryptBinary mocks base method.
+func (m *MockClientCryptoService) EncryptBinary(dst io.Writer, src io.Reader, meta *models.BinaryData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptBinary", dst, src, meta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EncryptBinary indicates an expected call of EncryptBinary.
+func (mr *MockClientCryptoServiceMockRecorder) EncryptBinary(dst, src, meta any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptBinary", reflect.TypeOf((*MockClientCryptoService)(nil).EncryptBinary), dst, src, meta)
+}
+
 // EncryptPayload mocks base method.
 func (m *MockClientCryptoService) EncryptPayload(plain models.DecipheredPayload) (models.PrivateDataPayload, error) {
 	m.ctrl.T.Helper()
@@ -90,6 +150,20 @@ func (mr *MockClientCryptoServiceMockRecorder) EncryptPayload(plain any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptPayload", reflect.TypeOf((*MockClientCryptoService)(nil).EncryptPayload), plain)
 }
 
+// HasEncryptionKey mocks base method.
+func (m *MockClientCryptoService) HasEncryptionKey() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasEncryptionKey")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasEncryptionKey indicates an expected call of HasEncryptionKey.
+func (mr *MockClientCryptoServiceMockRecorder) HasEncryptionKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasEncryptionKey", reflect.TypeOf((*MockClientCryptoService)(nil).HasEncryptionKey))
+}
+
 // SetEncryptionKey mocks base method.
 func (m *MockClientCryptoService) SetEncryptionKey(key []byte) {
 	m.ctrl.T.Helper()
@@ -142,6 +216,21 @@ func (mr *MockClientAuthServiceMockRecorder) Login(ctx, user any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockClientAuthService)(nil).Login), ctx, user)
 }
 
+// RecoveryKit mocks base method.
+func (m *MockClientAuthService) RecoveryKit(ctx context.Context, userID int64) (models.RecoveryKit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecoveryKit", ctx, userID)
+	ret0, _ := ret[0].(models.RecoveryKit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecoveryKit indicates an expected call of RecoveryKit.
+func (mr *MockClientAuthServiceMockRecorder) RecoveryKit(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoveryKit", reflect.TypeOf((*MockClientAuthService)(nil).RecoveryKit), ctx, userID)
+}
+
 // Register mocks base method.
 func (m *MockClientAuthService) Register(ctx context.Context, user models.User) error {
 	m.ctrl.T.Helper()
@@ -156,6 +245,20 @@ func (mr *MockClientAuthServiceMockRecorder) Register(ctx, user any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockClientAuthService)(nil).Register), ctx, user)
 }
 
+// VerifyMasterPassword mocks base method.
+func (m *MockClientAuthService) VerifyMasterPassword(ctx context.Context, userID int64, masterPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMasterPassword", ctx, userID, masterPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyMasterPassword indicates an expected call of VerifyMasterPassword.
+func (mr *MockClientAuthServiceMockRecorder) VerifyMasterPassword(ctx, userID, masterPassword any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMasterPassword", reflect.TypeOf((*MockClientAuthService)(nil).VerifyMasterPassword), ctx, userID, masterPassword)
+}
+
 // MockClientPrivateDataService is a mock of ClientPrivateDataService interface.
 type MockClientPrivateDataService struct {
 	ctrl     *gomock.Controller
@@ -224,12 +327,13 @@ func (mr *MockClientPrivateDataServiceMockRecorder) Get(ctx, clientSideID, userI
 }
 
 // GetAll mocks base method.
-func (m *MockClientPrivateDataService) GetAll(ctx context.Context, userID int64) ([]models.DecipheredPayload, error) {
+func (m *MockClientPrivateDataService) GetAll(ctx context.Context, userID int64) ([]models.DecipheredPayload, []string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAll", ctx, userID)
 	ret0, _ := ret[0].([]models.DecipheredPayload)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetAll indicates an expected call of GetAll.
@@ -238,6 +342,139 @@ func (mr *MockClientPrivateDataServiceMockRecorder) GetAll(ctx, userID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockClientPrivateDataService)(nil).GetAll), ctx, userID)
 }
 
+// GetAllMeta mocks base method.
+func (m *MockClientPrivateDataService) GetAllMeta(ctx context.Context, userID int64) ([]models.DecipheredPayload, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMeta", ctx, userID)
+	ret0, _ := ret[0].([]models.DecipheredPayload)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllMeta indicates an expected call of GetAllMeta.
+func (mr *MockClientPrivateDataServiceMockRecorder) GetAllMeta(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMeta", reflect.TypeOf((*MockClientPrivateDataService)(nil).GetAllMeta), ctx, userID)
+}
+
+// GetHistory mocks base method.
+func (m *MockClientPrivateDataService) GetHistory(ctx context.Context, userID int64, clientSideID string) ([]models.DecipheredVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, userID, clientSideID)
+	ret0, _ := ret[0].([]models.DecipheredVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockClientPrivateDataServiceMockRecorder) GetHistory(ctx, userID, clientSideID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockClientPrivateDataService)(nil).GetHistory), ctx, userID, clientSideID)
+}
+
+// GetTrash mocks base method.
+func (m *MockClientPrivateDataService) GetTrash(ctx context.Context, userID int64) ([]models.DecipheredPayload, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, userID)
+	ret0, _ := ret[0].([]models.DecipheredPayload)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockClientPrivateDataServiceMockRecorder) GetTrash(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockClientPrivateDataService)(nil).GetTrash), ctx, userID)
+}
+
+// HasEncryptionKey mocks base method.
+func (m *MockClientPrivateDataService) HasEncryptionKey() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasEncryptionKey")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasEncryptionKey indicates an expected call of HasEncryptionKey.
+func (mr *MockClientPrivateDataServiceMockRecorder) HasEncryptionKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasEncryptionKey", reflect.TypeOf((*MockClientPrivateDataService)(nil).HasEncryptionKey))
+}
+
+// MoveToFolder mocks base method.
+func (m *MockClientPrivateDataService) MoveToFolder(ctx context.Context, userID int64, clientSideIDs []string, folder string) (models.MoveResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToFolder", ctx, userID, clientSideIDs, folder)
+	ret0, _ := ret[0].(models.MoveResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveToFolder indicates an expected call of MoveToFolder.
+func (mr *MockClientPrivateDataServiceMockRecorder) MoveToFolder(ctx, userID, clientSideIDs, folder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToFolder", reflect.TypeOf((*MockClientPrivateDataService)(nil).MoveToFolder), ctx, userID, clientSideIDs, folder)
+}
+
+// ReencryptOutdated mocks base method.
+func (m *MockClientPrivateDataService) ReencryptOutdated(ctx context.Context, userID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReencryptOutdated", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReencryptOutdated indicates an expected call of ReencryptOutdated.
+func (mr *MockClientPrivateDataServiceMockRecorder) ReencryptOutdated(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReencryptOutdated", reflect.TypeOf((*MockClientPrivateDataService)(nil).ReencryptOutdated), ctx, userID)
+}
+
+// RemoveFromTrash mocks base method.
+func (m *MockClientPrivateDataService) RemoveFromTrash(ctx context.Context, userID int64, clientSideID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromTrash", ctx, userID, clientSideID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromTrash indicates an expected call of RemoveFromTrash.
+func (mr *MockClientPrivateDataServiceMockRecorder) RemoveFromTrash(ctx, userID, clientSideID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromTrash", reflect.TypeOf((*MockClientPrivateDataService)(nil).RemoveFromTrash), ctx, userID, clientSideID)
+}
+
+// RestoreDeleted mocks base method.
+func (m *MockClientPrivateDataService) RestoreDeleted(ctx context.Context, userID int64, clientSideID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreDeleted", ctx, userID, clientSideID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreDeleted indicates an expected call of RestoreDeleted.
+func (mr *MockClientPrivateDataServiceMockRecorder) RestoreDeleted(ctx, userID, clientSideID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDeleted", reflect.TypeOf((*MockClientPrivateDataService)(nil).RestoreDeleted), ctx, userID, clientSideID)
+}
+
+// RestoreVersion mocks base method.
+func (m *MockClientPrivateDataService) RestoreVersion(ctx context.Context, userID int64, clientSideID string, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreVersion", ctx, userID, clientSideID, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreVersion indicates an expected call of RestoreVersion.
+func (mr *MockClientPrivateDataServiceMockRecorder) RestoreVersion(ctx, userID, clientSideID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVersion", reflect.TypeOf((*MockClientPrivateDataService)(nil).RestoreVersion), ctx, userID, clientSideID, version)
+}
+
 // SetEncryptionKey mocks base method.
 func (m *MockClientPrivateDataService) SetEncryptionKey(key []byte) {
 	m.ctrl.T.Helper()
@@ -250,6 +487,35 @@ func (mr *MockClientPrivateDataServiceMockRecorder) SetEncryptionKey(key any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEncryptionKey", reflect.TypeOf((*MockClientPrivateDataService)(nil).SetEncryptionKey), key)
 }
 
+// SetFavorite mocks base method.
+func (m *MockClientPrivateDataService) SetFavorite(ctx context.Context, userID int64, clientSideID string, favorite bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFavorite", ctx, userID, clientSideID, favorite)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFavorite indicates an expected call of SetFavorite.
+func (mr *MockClientPrivateDataServiceMockRecorder) SetFavorite(ctx, userID, clientSideID, favorite any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFavorite", reflect.TypeOf((*MockClientPrivateDataService)(nil).SetFavorite), ctx, userID, clientSideID, favorite)
+}
+
+// Summary mocks base method.
+func (m *MockClientPrivateDataService) Summary(ctx context.Context, userID int64) (models.VaultSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Summary", ctx, userID)
+	ret0, _ := ret[0].(models.VaultSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Summary indicates an expected call of Summary.
+func (mr *MockClientPrivateDataServiceMockRecorder) Summary(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summary", reflect.TypeOf((*MockClientPrivateDataService)(nil).Summary), ctx, userID)
+}
+
 // Update mocks base method.
 func (m *MockClientPrivateDataService) Update(ctx context.Context, data models.DecipheredPayload) error {
 	m.ctrl.T.Helper()
@@ -288,6 +554,20 @@ func (m *MockClientSyncService) EXPECT() *MockClientSyncServiceMockRecorder {
 	return m.recorder
 }
 
+// BreakerStatus mocks base method.
+func (m *MockClientSyncService) BreakerStatus() models.SyncBreakerStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BreakerStatus")
+	ret0, _ := ret[0].(models.SyncBreakerStatus)
+	return ret0
+}
+
+// BreakerStatus indicates an expected call of BreakerStatus.
+func (mr *MockClientSyncServiceMockRecorder) BreakerStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BreakerStatus", reflect.TypeOf((*MockClientSyncService)(nil).BreakerStatus))
+}
+
 // ExecutePlan mocks base method.
 func (m *MockClientSyncService) ExecutePlan(ctx context.Context, plan models.SyncPlan, userID int64) error {
 	m.ctrl.T.Helper()
@@ -302,6 +582,21 @@ func (mr *MockClientSyncServiceMockRecorder) ExecutePlan(ctx, plan, userID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecutePlan", reflect.TypeOf((*MockClientSyncService)(nil).ExecutePlan), ctx, plan, userID)
 }
 
+// FindDuplicates mocks base method.
+func (m *MockClientSyncService) FindDuplicates(ctx context.Context, userID int64) ([]models.DuplicateGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDuplicates", ctx, userID)
+	ret0, _ := ret[0].([]models.DuplicateGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDuplicates indicates an expected call of FindDuplicates.
+func (mr *MockClientSyncServiceMockRecorder) FindDuplicates(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicates", reflect.TypeOf((*MockClientSyncService)(nil).FindDuplicates), ctx, userID)
+}
+
 // FullSync mocks base method.
 func (m *MockClientSyncService) FullSync(ctx context.Context, userID int64) error {
 	m.ctrl.T.Helper()
@@ -316,6 +611,93 @@ func (mr *MockClientSyncServiceMockRecorder) FullSync(ctx, userID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FullSync", reflect.TypeOf((*MockClientSyncService)(nil).FullSync), ctx, userID)
 }
 
+// LastSyncedAt mocks base method.
+func (m *MockClientSyncService) LastSyncedAt(ctx context.Context, userID int64) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastSyncedAt", ctx, userID)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastSyncedAt indicates an expected call of LastSyncedAt.
+func (mr *MockClientSyncServiceMockRecorder) LastSyncedAt(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastSyncedAt", reflect.TypeOf((*MockClientSyncService)(nil).LastSyncedAt), ctx, userID)
+}
+
+// LoadConflict mocks base method.
+func (m *MockClientSyncService) LoadConflict(ctx context.Context, userID int64, conflict models.VersionConflict) (models.ConflictVersions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadConflict", ctx, userID, conflict)
+	ret0, _ := ret[0].(models.ConflictVersions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadConflict indicates an expected call of LoadConflict.
+func (mr *MockClientSyncServiceMockRecorder) LoadConflict(ctx, userID, conflict any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadConflict", reflect.TypeOf((*MockClientSyncService)(nil).LoadConflict), ctx, userID, conflict)
+}
+
+// MergeDuplicates mocks base method.
+func (m *MockClientSyncService) MergeDuplicates(ctx context.Context, userID int64, group models.DuplicateGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeDuplicates", ctx, userID, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeDuplicates indicates an expected call of MergeDuplicates.
+func (mr *MockClientSyncServiceMockRecorder) MergeDuplicates(ctx, userID, group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeDuplicates", reflect.TypeOf((*MockClientSyncService)(nil).MergeDuplicates), ctx, userID, group)
+}
+
+// Ping mocks base method.
+func (m *MockClientSyncService) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockClientSyncServiceMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockClientSyncService)(nil).Ping), ctx)
+}
+
+// ResolveConflict mocks base method.
+func (m *MockClientSyncService) ResolveConflict(ctx context.Context, userID int64, clientSideID string, choice models.ConflictChoice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveConflict", ctx, userID, clientSideID, choice)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveConflict indicates an expected call of ResolveConflict.
+func (mr *MockClientSyncServiceMockRecorder) ResolveConflict(ctx, userID, clientSideID, choice any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveConflict", reflect.TypeOf((*MockClientSyncService)(nil).ResolveConflict), ctx, userID, clientSideID, choice)
+}
+
+// ServerVersion mocks base method.
+func (m *MockClientSyncService) ServerVersion(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServerVersion", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServerVersion indicates an expected call of ServerVersion.
+func (mr *MockClientSyncServiceMockRecorder) ServerVersion(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerVersion", reflect.TypeOf((*MockClientSyncService)(nil).ServerVersion), ctx)
+}
+
 // MockClientSyncJob is a mock of ClientSyncJob interface.
 type MockClientSyncJob struct {
 	ctrl     *gomock.Controller
@@ -15,6 +15,7 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/MKhiriev/go-pass-keeper/models"
 	gomock "go.uber.org/mock/gomock"
@@ -44,6 +45,21 @@ func (m *MockLocalPrivateDataRepository) EXPECT() *MockLocalPrivateDataRepositor
 	return m.recorder
 }
 
+// CountByType mocks base method.
+func (m *MockLocalPrivateDataRepository) CountByType(ctx context.Context, userID int64) (models.VaultSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByType", ctx, userID)
+	ret0, _ := ret[0].(models.VaultSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByType indicates an expected call of CountByType.
+func (mr *MockLocalPrivateDataRepositoryMockRecorder) CountByType(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByType", reflect.TypeOf((*MockLocalPrivateDataRepository)(nil).CountByType), ctx, userID)
+}
+
 // DeletePrivateData mocks base method.
 func (m *MockLocalPrivateDataRepository) DeletePrivateData(ctx context.Context, clientSideID string, userID int64) error {
 	m.ctrl.T.Helper()
@@ -88,6 +104,21 @@ func (mr *MockLocalPrivateDataRepositoryMockRecorder) GetAllStates(ctx, userID a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllStates", reflect.TypeOf((*MockLocalPrivateDataRepository)(nil).GetAllStates), ctx, userID)
 }
 
+// GetDeletedPrivateData mocks base method.
+func (m *MockLocalPrivateDataRepository) GetDeletedPrivateData(ctx context.Context, userID int64) ([]models.PrivateData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedPrivateData", ctx, userID)
+	ret0, _ := ret[0].([]models.PrivateData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedPrivateData indicates an expected call of GetDeletedPrivateData.
+func (mr *MockLocalPrivateDataRepositoryMockRecorder) GetDeletedPrivateData(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedPrivateData", reflect.TypeOf((*MockLocalPrivateDataRepository)(nil).GetDeletedPrivateData), ctx, userID)
+}
+
 // GetPrivateData mocks base method.
 func (m *MockLocalPrivateDataRepository) GetPrivateData(ctx context.Context, clientSideID string, userID int64) (models.PrivateData, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +148,34 @@ func (mr *MockLocalPrivateDataRepositoryMockRecorder) IncrementVersion(ctx, clie
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementVersion", reflect.TypeOf((*MockLocalPrivateDataRepository)(nil).IncrementVersion), ctx, clientSideID, userID)
 }
 
+// PurgePrivateData mocks base method.
+func (m *MockLocalPrivateDataRepository) PurgePrivateData(ctx context.Context, clientSideID string, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgePrivateData", ctx, clientSideID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgePrivateData indicates an expected call of PurgePrivateData.
+func (mr *MockLocalPrivateDataRepositoryMockRecorder) PurgePrivateData(ctx, clientSideID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgePrivateData", reflect.TypeOf((*MockLocalPrivateDataRepository)(nil).PurgePrivateData), ctx, clientSideID, userID)
+}
+
+// RestorePrivateData mocks base method.
+func (m *MockLocalPrivateDataRepository) RestorePrivateData(ctx context.Context, clientSideID string, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestorePrivateData", ctx, clientSideID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestorePrivateData indicates an expected call of RestorePrivateData.
+func (mr *MockLocalPrivateDataRepositoryMockRecorder) RestorePrivateData(ctx, clientSideID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePrivateData", reflect.TypeOf((*MockLocalPrivateDataRepository)(nil).RestorePrivateData), ctx, clientSideID, userID)
+}
+
 // SavePrivateData mocks base method.
 func (m *MockLocalPrivateDataRepository) SavePrivateData(ctx context.Context, userID int64, data ...models.PrivateData) error {
 	m.ctrl.T.Helper()
@@ -136,6 +195,25 @@ func (mr *MockLocalPrivateDataRepositoryMockRecorder) SavePrivateData(ctx, userI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePrivateData", reflect.TypeOf((*MockLocalPrivateDataRepository)(nil).SavePrivateData), varargs...)
 }
 
+// SetServerFields mocks base method.
+func (m *MockLocalPrivateDataRepository) SetServerFields(ctx context.Context, userID int64, items ...models.UploadedItem) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, userID}
+	for _, a := range items {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetServerFields", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetServerFields indicates an expected call of SetServerFields.
+func (mr *MockLocalPrivateDataRepositoryMockRecorder) SetServerFields(ctx, userID any, items ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, userID}, items...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServerFields", reflect.TypeOf((*MockLocalPrivateDataRepository)(nil).SetServerFields), varargs...)
+}
+
 // UpdatePrivateData mocks base method.
 func (m *MockLocalPrivateDataRepository) UpdatePrivateData(ctx context.Context, data models.PrivateData) error {
 	m.ctrl.T.Helper()
@@ -149,3 +227,172 @@ func (mr *MockLocalPrivateDataRepositoryMockRecorder) UpdatePrivateData(ctx, dat
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePrivateData", reflect.TypeOf((*MockLocalPrivateDataRepository)(nil).UpdatePrivateData), ctx, data)
 }
+
+// MockLocalUserRepository is a mock of LocalUserRepository interface.
+type MockLocalUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLocalUserRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLocalUserRepositoryMockRecorder is the mock recorder for MockLocalUserRepository.
+type MockLocalUserRepositoryMockRecorder struct {
+	mock *MockLocalUserRepository
+}
+
+// NewMockLocalUserRepository creates a new mock instance.
+func NewMockLocalUserRepository(ctrl *gomock.Controller) *MockLocalUserRepository {
+	mock := &MockLocalUserRepository{ctrl: ctrl}
+	mock.recorder = &MockLocalUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocalUserRepository) EXPECT() *MockLocalUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetUserByID mocks base method.
+func (m *MockLocalUserRepository) GetUserByID(ctx context.Context, userID int64) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, userID)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockLocalUserRepositoryMockRecorder) GetUserByID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockLocalUserRepository)(nil).GetUserByID), ctx, userID)
+}
+
+// GetUserByLogin mocks base method.
+func (m *MockLocalUserRepository) GetUserByLogin(ctx context.Context, login string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByLogin", ctx, login)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByLogin indicates an expected call of GetUserByLogin.
+func (mr *MockLocalUserRepositoryMockRecorder) GetUserByLogin(ctx, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByLogin", reflect.TypeOf((*MockLocalUserRepository)(nil).GetUserByLogin), ctx, login)
+}
+
+// SaveUser mocks base method.
+func (m *MockLocalUserRepository) SaveUser(ctx context.Context, user models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUser indicates an expected call of SaveUser.
+func (mr *MockLocalUserRepositoryMockRecorder) SaveUser(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUser", reflect.TypeOf((*MockLocalUserRepository)(nil).SaveUser), ctx, user)
+}
+
+// MockLocalSyncStateRepository is a mock of LocalSyncStateRepository interface.
+type MockLocalSyncStateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLocalSyncStateRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLocalSyncStateRepositoryMockRecorder is the mock recorder for MockLocalSyncStateRepository.
+type MockLocalSyncStateRepositoryMockRecorder struct {
+	mock *MockLocalSyncStateRepository
+}
+
+// NewMockLocalSyncStateRepository creates a new mock instance.
+func NewMockLocalSyncStateRepository(ctrl *gomock.Controller) *MockLocalSyncStateRepository {
+	mock := &MockLocalSyncStateRepository{ctrl: ctrl}
+	mock.recorder = &MockLocalSyncStateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocalSyncStateRepository) EXPECT() *MockLocalSyncStateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeletePendingConflict mocks base method.
+func (m *MockLocalSyncStateRepository) DeletePendingConflict(ctx context.Context, userID int64, clientSideID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePendingConflict", ctx, userID, clientSideID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePendingConflict indicates an expected call of DeletePendingConflict.
+func (mr *MockLocalSyncStateRepositoryMockRecorder) DeletePendingConflict(ctx, userID, clientSideID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingConflict", reflect.TypeOf((*MockLocalSyncStateRepository)(nil).DeletePendingConflict), ctx, userID, clientSideID)
+}
+
+// GetLastSyncedAt mocks base method.
+func (m *MockLocalSyncStateRepository) GetLastSyncedAt(ctx context.Context, userID int64) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastSyncedAt", ctx, userID)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastSyncedAt indicates an expected call of GetLastSyncedAt.
+func (mr *MockLocalSyncStateRepositoryMockRecorder) GetLastSyncedAt(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastSyncedAt", reflect.TypeOf((*MockLocalSyncStateRepository)(nil).GetLastSyncedAt), ctx, userID)
+}
+
+// GetPendingConflicts mocks base method.
+func (m *MockLocalSyncStateRepository) GetPendingConflicts(ctx context.Context, userID int64) ([]models.VersionConflict, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingConflicts", ctx, userID)
+	ret0, _ := ret[0].([]models.VersionConflict)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingConflicts indicates an expected call of GetPendingConflicts.
+func (mr *MockLocalSyncStateRepositoryMockRecorder) GetPendingConflicts(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingConflicts", reflect.TypeOf((*MockLocalSyncStateRepository)(nil).GetPendingConflicts), ctx, userID)
+}
+
+// SavePendingConflicts mocks base method.
+func (m *MockLocalSyncStateRepository) SavePendingConflicts(ctx context.Context, userID int64, conflicts ...models.VersionConflict) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, userID}
+	for _, a := range conflicts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SavePendingConflicts", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePendingConflicts indicates an expected call of SavePendingConflicts.
+func (mr *MockLocalSyncStateRepositoryMockRecorder) SavePendingConflicts(ctx, userID any, conflicts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, userID}, conflicts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePendingConflicts", reflect.TypeOf((*MockLocalSyncStateRepository)(nil).SavePendingConflicts), varargs...)
+}
+
+// SetLastSyncedAt mocks base method.
+func (m *MockLocalSyncStateRepository) SetLastSyncedAt(ctx context.Context, userID int64, syncedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLastSyncedAt", ctx, userID, syncedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLastSyncedAt indicates an expected call of SetLastSyncedAt.
+func (mr *MockLocalSyncStateRepositoryMockRecorder) SetLastSyncedAt(ctx, userID, syncedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLastSyncedAt", reflect.TypeOf((*MockLocalSyncStateRepository)(nil).SetLastSyncedAt), ctx, userID, syncedAt)
+}
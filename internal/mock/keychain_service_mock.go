@@ -13,8 +13,10 @@
 package mock
 
 import (
+	io "io"
 	reflect "reflect"
 
+	crypto "github.com/MKhiriev/go-pass-keeper/internal/crypto"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -71,6 +73,35 @@ func (mr *MockKeyChainServiceMockRecorder) DecryptData(encryptedB64, DEK, target
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptData", reflect.TypeOf((*MockKeyChainService)(nil).DecryptData), encryptedB64, DEK, target)
 }
 
+// DecryptDataVersion mocks base method.
+func (m *MockKeyChainService) DecryptDataVersion(encryptedB64 string, DEK []byte, target any) (byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecryptDataVersion", encryptedB64, DEK, target)
+	ret0, _ := ret[0].(byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecryptDataVersion indicates an expected call of DecryptDataVersion.
+func (mr *MockKeyChainServiceMockRecorder) DecryptDataVersion(encryptedB64, DEK, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptDataVersion", reflect.TypeOf((*MockKeyChainService)(nil).DecryptDataVersion), encryptedB64, DEK, target)
+}
+
+// DecryptStream mocks base method.
+func (m *MockKeyChainService) DecryptStream(dst io.Writer, src io.Reader, DEK []byte, info crypto.StreamInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecryptStream", dst, src, DEK, info)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DecryptStream indicates an expected call of DecryptStream.
+func (mr *MockKeyChainServiceMockRecorder) DecryptStream(dst, src, DEK, info any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptStream", reflect.TypeOf((*MockKeyChainService)(nil).DecryptStream), dst, src, DEK, info)
+}
+
 // EncryptData mocks base method.
 func (m *MockKeyChainService) EncryptData(data any, DEK []byte) (string, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +117,21 @@ func (mr *MockKeyChainServiceMockRecorder) EncryptData(data, DEK any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptData", reflect.TypeOf((*MockKeyChainService)(nil).EncryptData), data, DEK)
 }
 
+// EncryptStream mocks base method.
+func (m *MockKeyChainService) EncryptStream(dst io.Writer, src io.Reader, DEK []byte, chunkSize int) (crypto.StreamInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptStream", dst, src, DEK, chunkSize)
+	ret0, _ := ret[0].(crypto.StreamInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptStream indicates an expected call of EncryptStream.
+func (mr *MockKeyChainServiceMockRecorder) EncryptStream(dst, src, DEK, chunkSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptStream", reflect.TypeOf((*MockKeyChainService)(nil).EncryptStream), dst, src, DEK, chunkSize)
+}
+
 // GenerateAuthHash mocks base method.
 func (m *MockKeyChainService) GenerateAuthHash(KEK []byte, authSalt string) []byte {
 	m.ctrl.T.Helper()
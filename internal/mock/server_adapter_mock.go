@@ -73,6 +73,21 @@ func (mr *MockServerAdapterMockRecorder) Download(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockServerAdapter)(nil).Download), ctx, req)
 }
 
+// GetSchemaVersion mocks base method.
+func (m *MockServerAdapter) GetSchemaVersion(ctx context.Context) (models.SchemaVersionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchemaVersion", ctx)
+	ret0, _ := ret[0].(models.SchemaVersionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchemaVersion indicates an expected call of GetSchemaVersion.
+func (mr *MockServerAdapterMockRecorder) GetSchemaVersion(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchemaVersion", reflect.TypeOf((*MockServerAdapter)(nil).GetSchemaVersion), ctx)
+}
+
 // GetServerStates mocks base method.
 func (m *MockServerAdapter) GetServerStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
 	m.ctrl.T.Helper()
@@ -88,6 +103,36 @@ func (mr *MockServerAdapterMockRecorder) GetServerStates(ctx, userID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerStates", reflect.TypeOf((*MockServerAdapter)(nil).GetServerStates), ctx, userID)
 }
 
+// GetServerVersion mocks base method.
+func (m *MockServerAdapter) GetServerVersion(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServerVersion", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServerVersion indicates an expected call of GetServerVersion.
+func (mr *MockServerAdapterMockRecorder) GetServerVersion(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerVersion", reflect.TypeOf((*MockServerAdapter)(nil).GetServerVersion), ctx)
+}
+
+// GetVersionHistory mocks base method.
+func (m *MockServerAdapter) GetVersionHistory(ctx context.Context, clientSideID string) ([]models.PrivateDataVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersionHistory", ctx, clientSideID)
+	ret0, _ := ret[0].([]models.PrivateDataVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersionHistory indicates an expected call of GetVersionHistory.
+func (mr *MockServerAdapterMockRecorder) GetVersionHistory(ctx, clientSideID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersionHistory", reflect.TypeOf((*MockServerAdapter)(nil).GetVersionHistory), ctx, clientSideID)
+}
+
 // Login mocks base method.
 func (m *MockServerAdapter) Login(ctx context.Context, user models.User) (models.User, error) {
 	m.ctrl.T.Helper()
@@ -103,6 +148,20 @@ func (mr *MockServerAdapterMockRecorder) Login(ctx, user any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockServerAdapter)(nil).Login), ctx, user)
 }
 
+// Ping mocks base method.
+func (m *MockServerAdapter) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockServerAdapterMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockServerAdapter)(nil).Ping), ctx)
+}
+
 // Register mocks base method.
 func (m *MockServerAdapter) Register(ctx context.Context, user models.User) (models.User, error) {
 	m.ctrl.T.Helper()
@@ -133,6 +192,20 @@ func (mr *MockServerAdapterMockRecorder) RequestSalt(ctx, user any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestSalt", reflect.TypeOf((*MockServerAdapter)(nil).RequestSalt), ctx, user)
 }
 
+// Restore mocks base method.
+func (m *MockServerAdapter) Restore(ctx context.Context, req models.RestoreRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockServerAdapterMockRecorder) Restore(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockServerAdapter)(nil).Restore), ctx, req)
+}
+
 // SetToken mocks base method.
 func (m *MockServerAdapter) SetToken(token string) {
 	m.ctrl.T.Helper()
@@ -174,11 +247,12 @@ func (mr *MockServerAdapterMockRecorder) Update(ctx, req any) *gomock.Call {
 }
 
 // Upload mocks base method.
-func (m *MockServerAdapter) Upload(ctx context.Context, req models.UploadRequest) error {
+func (m *MockServerAdapter) Upload(ctx context.Context, req models.UploadRequest) (models.UploadResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Upload", ctx, req)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(models.UploadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Upload indicates an expected call of Upload.
@@ -5,6 +5,8 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
@@ -17,13 +19,28 @@ type httpServer struct {
 	logger *logger.Logger
 }
 
-func newHTTPServer(handler http.Handler, cfg config.Server, logger *logger.Logger) *httpServer {
+// newHTTPServer builds an HTTP transport listening on address. Unset
+// timeouts in cfg are filled by [config.Server.WithTimeoutDefaults] so that
+// the server is never created without read, header, write and idle limits.
+func newHTTPServer(handler http.Handler, address string, cfg config.Server, logger *logger.Logger) *httpServer {
+	cfg = cfg.WithTimeoutDefaults()
+
+	logger.Info().
+		Str("address", address).
+		Dur("read_timeout", cfg.ReadTimeout).
+		Dur("read_header_timeout", cfg.ReadHeaderTimeout).
+		Dur("write_timeout", cfg.WriteTimeout).
+		Dur("idle_timeout", cfg.IdleTimeout).
+		Msg("HTTP server timeouts")
+
 	return &httpServer{
 		server: &http.Server{
-			Addr:         cfg.HTTPAddress,
-			Handler:      handler,
-			ReadTimeout:  cfg.RequestTimeout,
-			WriteTimeout: cfg.RequestTimeout,
+			Addr:              address,
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
 		},
 		logger: logger,
 	}
@@ -31,11 +48,20 @@ func newHTTPServer(handler http.Handler, cfg config.Server, logger *logger.Logge
 
 // RunServer starts the HTTP listener and serves incoming requests.
 func (h *httpServer) RunServer() {
-	if err := h.server.ListenAndServe(); err != nil {
+	if err := h.serve(); err != nil {
 		h.logger.Debug().Msgf("HTTP server ListenAndServe: %v\n", err)
 	}
 }
 
+// serve listens and serves until the server is shut down, which returns
+// nil, or the listener fails.
+func (h *httpServer) serve() error {
+	if err := h.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("listen on %s: %w", h.server.Addr, err)
+	}
+	return nil
+}
+
 // Shutdown gracefully stops the HTTP server.
 func (h *httpServer) Shutdown() {
 	if err := h.server.Shutdown(context.Background()); h.server != nil && err != nil {
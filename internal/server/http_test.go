@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPServer_Timeouts(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            config.Server
+		wantRead       time.Duration
+		wantReadHeader time.Duration
+		wantWrite      time.Duration
+		wantIdle       time.Duration
+	}{
+		{
+			name: "configured values",
+			cfg: config.Server{
+				ReadTimeout:       11 * time.Second,
+				ReadHeaderTimeout: 3 * time.Second,
+				WriteTimeout:      time.Minute,
+				IdleTimeout:       45 * time.Second,
+			},
+			wantRead:       11 * time.Second,
+			wantReadHeader: 3 * time.Second,
+			wantWrite:      time.Minute,
+			wantIdle:       45 * time.Second,
+		},
+		{
+			name:           "defaults when unset",
+			cfg:            config.Server{},
+			wantRead:       config.DefaultServerReadTimeout,
+			wantReadHeader: config.DefaultServerReadHeaderTimeout,
+			wantWrite:      config.DefaultServerWriteTimeout,
+			wantIdle:       config.DefaultServerIdleTimeout,
+		},
+		{
+			name:           "read timeout falls back to request timeout",
+			cfg:            config.Server{RequestTimeout: 20 * time.Second},
+			wantRead:       20 * time.Second,
+			wantReadHeader: config.DefaultServerReadHeaderTimeout,
+			wantWrite:      config.DefaultServerWriteTimeout,
+			wantIdle:       config.DefaultServerIdleTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newHTTPServer(http.NotFoundHandler(), "localhost:0", tt.cfg, logger.Nop())
+
+			assert.Equal(t, "localhost:0", s.server.Addr)
+			assert.Equal(t, tt.wantRead, s.server.ReadTimeout)
+			assert.Equal(t, tt.wantReadHeader, s.server.ReadHeaderTimeout)
+			assert.Equal(t, tt.wantWrite, s.server.WriteTimeout)
+			assert.Equal(t, tt.wantIdle, s.server.IdleTimeout)
+		})
+	}
+}
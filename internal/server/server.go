@@ -16,6 +16,8 @@ import (
 
 type server struct {
 	httpServer *httpServer
+	// adminServer serves the health and admin endpoints on their own address.
+	adminServer *httpServer
 	//gRPCServer *grpcServer
 	logger *logger.Logger
 }
@@ -26,6 +28,8 @@ type server struct {
 // Transport creation rules:
 //   - HTTP server is created when cfg.HTTPAddress is non-empty.
 //   - gRPC server is created when cfg.GRPCAddress is non-empty.
+//   - An admin HTTP server with the health and admin endpoints is created
+//     when cfg.AdminAddress is non-empty.
 //
 // At least one transport address must be configured. If both are empty,
 // [errNoServersAreCreated] is returned.
@@ -37,7 +41,7 @@ func NewServer(handlers *handler.Handlers, cfg config.Server, logger *logger.Log
 	servers := new(server)
 
 	if cfg.HTTPAddress != "" {
-		servers.httpServer = newHTTPServer(handlers.HTTP.Init(), cfg, logger)
+		servers.httpServer = newHTTPServer(handlers.HTTP.Init(), cfg.HTTPAddress, cfg, logger)
 	}
 	//if cfg.GRPCAddress != "" {
 	//	servers.gRPCServer = newGRPCServer(handlers.GRPC, cfg, logger)
@@ -47,6 +51,10 @@ func NewServer(handlers *handler.Handlers, cfg config.Server, logger *logger.Log
 		return nil, errNoServersAreCreated
 	}
 
+	if cfg.AdminAddress != "" {
+		servers.adminServer = newHTTPServer(handlers.HTTP.InitAdmin(), cfg.AdminAddress, cfg, logger)
+	}
+
 	servers.logger = logger
 
 	return servers, nil
@@ -61,9 +69,9 @@ func (s *server) RunServer() {
 
 // Shutdown gracefully stops all configured transports.
 func (s *server) Shutdown() {
-	// finish HTTP server
-	if s.httpServer != nil {
-		s.httpServer.Shutdown()
+	// finish HTTP servers
+	for _, srv := range s.httpServers() {
+		srv.Shutdown()
 	}
 
 	// finish gRPC server
@@ -72,13 +80,30 @@ func (s *server) Shutdown() {
 	//}
 }
 
+// httpServers returns the created HTTP transports.
+func (s *server) httpServers() []*httpServer {
+	var servers []*httpServer
+	if s.httpServer != nil {
+		servers = append(servers, s.httpServer)
+	}
+	if s.adminServer != nil {
+		servers = append(servers, s.adminServer)
+	}
+	return servers
+}
+
+// run launches every transport and blocks until a stop signal arrives, or
+// until any of them stops on its own, e.g. because its address is taken.
+// Either way all transports are shut down together, so the API is never
+// left running without its admin listener or the other way round.
 func (s *server) run() error {
+	servers := s.httpServers()
+
 	// check if any server was created
-	if s.httpServer == nil /*&& s.gRPCServer == nil*/ {
+	if len(servers) == 0 /*&& s.gRPCServer == nil*/ {
 		return errors.New("no servers to run")
 	}
 
-	idleConnectionsClosed := make(chan struct{})
 	ctx, stop := signal.NotifyContext(
 		context.Background(),
 		syscall.SIGTERM,
@@ -87,28 +112,34 @@ func (s *server) run() error {
 	)
 	defer stop()
 
-	// listen for stop signals
-	go func() {
-		<-ctx.Done()
-
-		// finish started servers
-		s.Shutdown()
-
-		close(idleConnectionsClosed)
-	}()
-
 	// launch all created servers
-	if s.httpServer != nil {
-		s.logger.Info().Msg("Launching HTTP server")
-		go s.httpServer.RunServer()
+	stopped := make(chan error, len(servers))
+	for _, srv := range servers {
+		s.logger.Info().Str("address", srv.server.Addr).Msg("Launching HTTP server")
+		go func() {
+			stopped <- srv.serve()
+		}()
 	}
 	//if s.gRPCServer != nil {
 	//	s.logger.Info().Msg("Launching GRPC server")
 	//	go s.gRPCServer.RunServer()
 	//}
 
-	<-idleConnectionsClosed
+	// wait for a stop signal or the first server to stop
+	running := len(servers)
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-stopped:
+		running--
+	}
+
+	// finish started servers
+	s.Shutdown()
+	for ; running > 0; running-- {
+		err = errors.Join(err, <-stopped)
+	}
 	s.logger.Info().Msg("server Shutdown gracefully")
 
-	return nil
+	return err
 }
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package server
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/handler"
+	handlerhttp "github.com/MKhiriev/go-pass-keeper/internal/handler/http"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// freeAddress returns a loopback address with a port that was free a moment ago.
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := l.Addr().String()
+	require.NoError(t, l.Close())
+	return address
+}
+
+func newTestServer(t *testing.T, cfg config.Server) *server {
+	t.Helper()
+	handlers := &handler.Handlers{HTTP: handlerhttp.NewHandler(&service.Services{}, cfg, logger.Nop())}
+	s, err := NewServer(handlers, cfg, logger.Nop())
+	require.NoError(t, err)
+	return s.(*server)
+}
+
+func healthy(address string) bool {
+	resp, err := http.Get("http://" + address + "/healthz")
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+func TestServer_RunsAPIAndAdminListenersTogether(t *testing.T) {
+	cfg := config.Server{HTTPAddress: freeAddress(t), AdminAddress: freeAddress(t)}
+	s := newTestServer(t, cfg)
+
+	done := make(chan error, 1)
+	go func() { done <- s.run() }()
+
+	for _, address := range []string{cfg.HTTPAddress, cfg.AdminAddress} {
+		assert.Eventually(t, func() bool { return healthy(address) }, 5*time.Second, 10*time.Millisecond,
+			"listener on %s", address)
+	}
+
+	s.Shutdown()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+	assert.False(t, healthy(cfg.HTTPAddress))
+	assert.False(t, healthy(cfg.AdminAddress))
+}
+
+func TestServer_FailingListenerStopsTheOthers(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer taken.Close()
+
+	cfg := config.Server{HTTPAddress: freeAddress(t), AdminAddress: taken.Addr().String()}
+	s := newTestServer(t, cfg)
+
+	done := make(chan error, 1)
+	go func() { done <- s.run() }()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), cfg.AdminAddress)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server kept running without its admin listener")
+	}
+	assert.False(t, healthy(cfg.HTTPAddress))
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// MaxBinaryPreviewSize is the largest embedded content, in bytes, of a Binary
+// entry ([models.BinaryData.Content]) that can be previewed.
+const MaxBinaryPreviewSize = 64 * 1024
+
+// BinaryPreview returns the embedded content of data as text for an inline
+// preview. ok is false when there is nothing to show as text: the content
+// was not embedded, is over [MaxBinaryPreviewSize], is not valid
+// UTF-8, or holds control characters other than tabs and line breaks.
+func BinaryPreview(data *models.BinaryData) (text string, ok bool) {
+	if data == nil || len(data.Content) == 0 || len(data.Content) > MaxBinaryPreviewSize {
+		return "", false
+	}
+	if !utf8.Valid(data.Content) {
+		return "", false
+	}
+	isBinary := bytes.ContainsFunc(data.Content, func(r rune) bool {
+		return unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t'
+	})
+	if isBinary {
+		return "", false
+	}
+	return string(data.Content), true
+}
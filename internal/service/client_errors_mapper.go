@@ -5,13 +5,63 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
 	"github.com/MKhiriev/go-pass-keeper/internal/app"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
 )
 
+// ConflictOf reports whether err is a version conflict returned by the server
+// for an update or delete and, if so, returns the operation and the server's
+// current version of the item.
+func ConflictOf(err error) (models.VersionConflict, bool) {
+	var conflict *adapter.ConflictError
+	if !errors.As(err, &conflict) {
+		return models.VersionConflict{}, false
+	}
+	return conflict.VersionConflict, true
+}
+
+// ValidationFailureOf reports whether err is a validation error returned by
+// the server with a structured body, and returns the rejected field.
+func ValidationFailureOf(err error) (models.ValidationFailure, bool) {
+	var invalid *adapter.ValidationError
+	if !errors.As(err, &invalid) {
+		return models.ValidationFailure{}, false
+	}
+	return invalid.ValidationFailure, true
+}
+
+// PendingConflictsError is the [ErrSyncConflicts] returned by a sync under
+// [models.ConflictManual]. It lists the conflicts left for the user to
+// resolve with [ClientSyncService.ResolveConflict].
+type PendingConflictsError struct {
+	Conflicts []models.VersionConflict
+}
+
+// Error implements error.
+func (e *PendingConflictsError) Error() string {
+	return fmt.Sprintf("%s: %d", ErrSyncConflicts.Error(), len(e.Conflicts))
+}
+
+// Unwrap makes [errors.Is] match [ErrSyncConflicts].
+func (e *PendingConflictsError) Unwrap() error {
+	return ErrSyncConflicts
+}
+
+// PendingConflictsOf reports whether err carries conflicts a sync left for
+// the user to resolve and, if so, returns them.
+func PendingConflictsOf(err error) ([]models.VersionConflict, bool) {
+	var pending *PendingConflictsError
+	if !errors.As(err, &pending) {
+		return nil, false
+	}
+	return pending.Conflicts, true
+}
+
 // mapAdapterError translates the adapter's transport error into a service business error
 func mapAdapterError(err error) error {
 	if err == nil {
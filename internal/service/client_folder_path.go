@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import "strings"
+
+// FolderSeparator separates the levels of a folder path such as
+// "Work/Email". The folder is still stored as a single string; only the
+// client interprets the separator, so no folder has to exist before an
+// entry is put into it.
+const FolderSeparator = "/"
+
+// FolderPath splits folder into its levels, from the outermost one. Each
+// level is trimmed and empty levels are dropped, so " Work // Email/ "
+// yields ["Work", "Email"]. An empty folder yields nil.
+func FolderPath(folder string) []string {
+	var path []string
+	for _, level := range strings.Split(folder, FolderSeparator) {
+		if level = strings.TrimSpace(level); level != "" {
+			path = append(path, level)
+		}
+	}
+	return path
+}
+
+// NormalizeFolder returns folder in the canonical form that is stored: the
+// levels of [FolderPath] joined with [FolderSeparator]. A folder without
+// any level normalizes to "".
+func NormalizeFolder(folder string) string {
+	return strings.Join(FolderPath(folder), FolderSeparator)
+}
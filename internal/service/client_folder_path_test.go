@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFolderPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantPath   []string
+		wantFolder string
+	}{
+		{name: "empty", input: ""},
+		{name: "separators only", input: " / // ", wantPath: nil},
+		{name: "single level", input: "Work", wantPath: []string{"Work"}, wantFolder: "Work"},
+		{name: "two levels", input: "Work/Email", wantPath: []string{"Work", "Email"}, wantFolder: "Work/Email"},
+		{name: "levels are trimmed", input: " Work / Email ", wantPath: []string{"Work", "Email"}, wantFolder: "Work/Email"},
+		{name: "empty levels are dropped", input: "/Work//Email/", wantPath: []string{"Work", "Email"}, wantFolder: "Work/Email"},
+		{name: "three levels", input: "Дом/Банки/Карты", wantPath: []string{"Дом", "Банки", "Карты"}, wantFolder: "Дом/Банки/Карты"},
+		{name: "inner spaces are kept", input: "My Work/Old Mail", wantPath: []string{"My Work", "Old Mail"}, wantFolder: "My Work/Old Mail"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantPath, FolderPath(tt.input))
+			assert.Equal(t, tt.wantFolder, NormalizeFolder(tt.input))
+		})
+	}
+}
@@ -5,6 +5,7 @@ package service
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/MKhiriev/go-pass-keeper/models"
@@ -18,6 +19,10 @@ type ClientCryptoService interface {
 	// Encrypt/Decrypt operations. It is called once after a successful login.
 	SetEncryptionKey(key []byte)
 
+	// HasEncryptionKey reports whether a DEK has been set. Encrypt and decrypt
+	// operations return [ErrKeyNotAvailable] while it is false.
+	HasEncryptionKey() bool
+
 	// EncryptPayload encrypts a plaintext vault payload and returns the
 	// ciphered representation ready for local storage or server upload.
 	// Returns an error if encryption of any field fails.
@@ -28,6 +33,29 @@ type ClientCryptoService interface {
 	// Returns an error if decryption of any field fails.
 	DecryptPayload(cipher models.PrivateDataPayload) (models.DecipheredPayload, error)
 
+	// DecryptPayloadOutdated is DecryptPayload that also reports whether any
+	// field of cipher was encrypted in a format older than
+	// crypto.CurrentFormatVersion, i.e. whether the payload should be
+	// re-encrypted with EncryptPayload on its next write.
+	DecryptPayloadOutdated(cipher models.PrivateDataPayload) (models.DecipheredPayload, bool, error)
+
+	// DecryptMetadata decrypts only the metadata of a ciphered vault payload
+	// and returns a [models.DecipheredPayload] with Metadata and Type set and
+	// every other field left empty. It is much cheaper than DecryptPayload
+	// for list views that show names and folders only.
+	DecryptMetadata(cipher models.PrivateDataPayload) (models.DecipheredPayload, error)
+
+	// EncryptBinary stream-encrypts binary content read from src into dst in
+	// independently sealed chunks, so large files are never held in memory as a
+	// whole. The chunk metadata required for decryption and the plaintext size
+	// are recorded in meta. Returns an error if reading, encryption or writing fails.
+	EncryptBinary(dst io.Writer, src io.Reader, meta *models.BinaryData) error
+
+	// DecryptBinary reverses EncryptBinary using the chunk metadata in meta.
+	// It rejects reordered, truncated or tampered chunks; plaintext already
+	// written to dst before an error must be discarded by the caller.
+	DecryptBinary(dst io.Writer, src io.Reader, meta models.BinaryData) error
+
 	// ComputeHash computes a deterministic hash of the given payload value
 	// (typically a models.PrivateDataPayload) for use in sync conflict detection.
 	// Returns the hash as a hex/base64 string or an error if serialisation fails.
@@ -52,6 +80,21 @@ type ClientAuthService interface {
 	// Returns the server-assigned user ID and the plaintext DEK, or an error if
 	// any step fails.
 	Login(ctx context.Context, user models.User) (userID int64, encryptionKey []byte, err error)
+
+	// RecoveryKit builds the recovery kit of userID from the credentials
+	// cached at login; see [models.RecoveryKit]. It contacts no server and
+	// includes neither the DEK nor the password, wrapped or not.
+	// Returns [ErrOfflineNoLocalUser] if userID never logged in on this
+	// device.
+	RecoveryKit(ctx context.Context, userID int64) (models.RecoveryKit, error)
+
+	// VerifyMasterPassword checks masterPassword against the credentials of
+	// userID cached at login by re-deriving the KEK, without contacting the
+	// server. Used to confirm the user before revealing an entry marked
+	// with [models.Metadata.Reprompt]. Returns [ErrWrongMasterPassword] if
+	// the password does not match and [ErrOfflineNoLocalUser] if userID
+	// never logged in on this device.
+	VerifyMasterPassword(ctx context.Context, userID int64, masterPassword string) error
 }
 
 // ClientPrivateDataService defines the client-side contract for managing vault items.
@@ -62,15 +105,34 @@ type ClientPrivateDataService interface {
 	// Must be called before any Create/Get/Update/Delete operation.
 	SetEncryptionKey(key []byte)
 
+	// HasEncryptionKey reports whether the underlying ClientCryptoService
+	// holds a DEK. Callers check it before operations that encrypt or decrypt
+	// so that a missing key can be handled by re-authenticating the user.
+	HasEncryptionKey() bool
+
 	// Create encrypts plain, assigns a new client-side UUID, saves the item to
 	// the local store, and uploads it to the server.
 	// Returns an error if encryption, local save, or server upload fails.
 	Create(ctx context.Context, userID int64, plain models.DecipheredPayload) error
 
 	// GetAll loads every non-deleted vault item for userID from the local store,
-	// decrypts each one, and returns the plaintext collection.
-	// Returns an error if the local query or any decryption fails.
-	GetAll(ctx context.Context, userID int64) ([]models.DecipheredPayload, error)
+	// decrypts each one, and returns the plaintext collection. Items that cannot
+	// be decrypted (corrupt ciphertext, a different key) are skipped and their
+	// client-side IDs returned in failed, so one bad item does not hide the rest.
+	// Returns an error if the local query fails or no DEK is set.
+	GetAll(ctx context.Context, userID int64) (items []models.DecipheredPayload, failed []string, err error)
+
+	// GetAllMeta is the metadata-only variant of GetAll for list views: only
+	// the metadata of each item is decrypted, so the returned payloads carry
+	// ClientSideID, UserID, Type and Metadata only. Use Get to decrypt the
+	// full payload of an item on demand. Items whose metadata cannot be
+	// decrypted are reported in failed.
+	GetAllMeta(ctx context.Context, userID int64) (items []models.DecipheredPayload, failed []string, err error)
+
+	// Summary counts the vault items of userID per type, plus the total and
+	// the soft-deleted ones, from the local store. Nothing is decrypted, so
+	// it works without a DEK.
+	Summary(ctx context.Context, userID int64) (models.VaultSummary, error)
 
 	// Get loads the single vault item identified by clientSideID from the local
 	// store, decrypts it, and returns the plaintext payload.
@@ -88,6 +150,53 @@ type ClientPrivateDataService interface {
 	// incremented.
 	// Returns an error if the local delete or server delete fails.
 	Delete(ctx context.Context, clientSideID string, userID int64) error
+
+	// GetHistory fetches the previous versions of the vault item identified
+	// by clientSideID from the server and decrypts them, newest first.
+	// Versions that cannot be decrypted are skipped.
+	GetHistory(ctx context.Context, userID int64, clientSideID string) ([]models.DecipheredVersion, error)
+
+	// GetTrash decrypts the metadata of the soft-deleted items of userID, the
+	// ones GetAll leaves out. Like GetAll, items that fail to decrypt are
+	// reported in failed.
+	GetTrash(ctx context.Context, userID int64) (items []models.DecipheredPayload, failed []string, err error)
+
+	// RestoreDeleted brings the soft-deleted item clientSideID back to the
+	// vault; see [clientPrivateDataService.RestoreDeleted].
+	RestoreDeleted(ctx context.Context, userID int64, clientSideID string) error
+
+	// RemoveFromTrash removes the soft-deleted item clientSideID from the
+	// local store. It is not a permanent delete: the server keeps its
+	// tombstone, so other devices still learn about the deletion. Returns
+	// [ErrDeletePending] while the server still has the item live.
+	RemoveFromTrash(ctx context.Context, userID int64, clientSideID string) error
+
+	// RestoreVersion makes the archived version of the vault item the
+	// current one. The archived ciphertext is stored locally and pushed to
+	// the server as a regular update based on the local version, so the
+	// server creates a new version rather than rewinding the counter.
+	// Returns [ErrVersionNotInHistory] if the server no longer keeps it.
+	RestoreVersion(ctx context.Context, userID int64, clientSideID string, version int64) error
+
+	// MoveToFolder moves the given vault items into folder (an empty folder
+	// removes them from their folder) with a single multi-record update. A
+	// folder path such as "Work/Email" is stored as given by
+	// [NormalizeFolder].
+	// Items whose local version is behind or ahead of the server are not
+	// moved and are reported in [models.MoveResult.Conflicted].
+	MoveToFolder(ctx context.Context, userID int64, clientSideIDs []string, folder string) (models.MoveResult, error)
+
+	// SetFavorite marks the vault item clientSideID as a favorite or clears
+	// the mark. The flag lives in the encrypted metadata, so the change is
+	// saved locally and pushed to the server like any other update. Nothing
+	// is written when the item already has the requested state.
+	SetFavorite(ctx context.Context, userID int64, clientSideID string, favorite bool) error
+
+	// ReencryptOutdated re-encrypts every local vault item of userID that is
+	// still stored in an older encryption format and pushes it to the server
+	// like an edit. Items rejected with a version conflict are left to the
+	// next sync. It returns the number of items migrated.
+	ReencryptOutdated(ctx context.Context, userID int64) (int, error)
 }
 
 // ClientSyncService defines the client-side contract for synchronising the local
@@ -96,13 +205,55 @@ type ClientSyncService interface {
 	// FullSync performs a complete bidirectional synchronisation for the given
 	// user: it fetches server and client state descriptors, builds a sync plan,
 	// and executes all required download, upload, update, and delete operations.
+	// On success the sync time is persisted locally; a failed sync leaves the
+	// previously recorded time unchanged.
 	// Returns an error if any step of the sync fails.
 	FullSync(ctx context.Context, userID int64) error
 
+	// BreakerStatus returns the state of the sync circuit breaker. While it
+	// is open, FullSync fails at once with [ErrServerUnavailable] instead of
+	// contacting a server that kept failing on the network level.
+	BreakerStatus() models.SyncBreakerStatus
+
+	// Ping checks that the server is reachable; see [adapter.ServerAdapter.Ping].
+	// The outcome is also fed to the sync circuit breaker: failed pings
+	// count towards opening it like failed syncs do, and a successful one
+	// closes it.
+	Ping(ctx context.Context) error
+
+	// LastSyncedAt returns the time of the last successful FullSync for the
+	// given user, or the zero time if the user has never synced on this device.
+	LastSyncedAt(ctx context.Context, userID int64) (time.Time, error)
+
+	// ServerVersion returns the application version reported by the server.
+	// It is informational; see [CheckVersionCompatibility].
+	ServerVersion(ctx context.Context) (string, error)
+
 	// ExecutePlan carries out the actions described in plan for the given user.
 	// Each action category (Download, Upload, Update, DeleteClient, DeleteServer)
-	// is executed in order. Returns the first error encountered, if any.
+	// is executed in order, except the categories excluded by the configured
+	// [models.SyncMode]. Returns the first error encountered, if any.
 	ExecutePlan(ctx context.Context, plan models.SyncPlan, userID int64) error
+
+	// FindDuplicates decrypts the user's local items and returns groups of
+	// entries with identical content but different ClientSideIDs. It never
+	// modifies data.
+	FindDuplicates(ctx context.Context, userID int64) ([]models.DuplicateGroup, error)
+
+	// MergeDuplicates soft-deletes group.Duplicates locally and on the server,
+	// keeping group.Keep. Callers must obtain the user's consent first.
+	MergeDuplicates(ctx context.Context, userID int64, group models.DuplicateGroup) error
+
+	// LoadConflict decrypts both versions of an item reported by a sync in a
+	// [*PendingConflictsError]: the local one and the one currently stored
+	// on the server.
+	LoadConflict(ctx context.Context, userID int64, conflict models.VersionConflict) (models.ConflictVersions, error)
+
+	// ResolveConflict applies the user's choice to the conflicting item
+	// clientSideID. The server's version is re-read, so a choice made on
+	// stale data cannot overwrite a newer change unnoticed: the update is
+	// rejected with a conflict again.
+	ResolveConflict(ctx context.Context, userID int64, clientSideID string, choice models.ConflictChoice) error
 }
 
 // ClientSyncJob defines the contract for a background sync worker that
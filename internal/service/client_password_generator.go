@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// GeneratedPasswordLength is the length of the passwords made by
+// [GeneratePassword] for new login entries.
+const GeneratedPasswordLength = 20
+
+// passwordClasses are the character classes a generated password draws
+// from. Characters that are easily confused, such as l, 1, O and 0, are
+// left out so that the password can be typed from the screen.
+var passwordClasses = []string{
+	"abcdefghijkmnopqrstuvwxyz",
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"23456789",
+	"!#$%&*+-=?@^_",
+}
+
+// GeneratePassword returns a random password of length characters taken
+// from a cryptographically secure source. It contains at least one
+// character of every class in [passwordClasses], so it passes the usual
+// site rules. length must be at least the number of classes.
+func GeneratePassword(length int) (string, error) {
+	if length < len(passwordClasses) {
+		return "", fmt.Errorf("generate password: length %d is shorter than %d", length, len(passwordClasses))
+	}
+
+	var all string
+	for _, class := range passwordClasses {
+		all += class
+	}
+
+	password := make([]byte, length)
+	for i := range password {
+		// The first characters cover every class; they are shuffled below.
+		alphabet := all
+		if i < len(passwordClasses) {
+			alphabet = passwordClasses[i]
+		}
+		c, err := randomIndex(len(alphabet))
+		if err != nil {
+			return "", err
+		}
+		password[i] = alphabet[c]
+	}
+
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// randomIndex returns a uniformly random integer in [0, n).
+func randomIndex(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("generate password: %w", err)
+	}
+	return int(v.Int64()), nil
+}
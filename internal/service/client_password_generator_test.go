@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  int
+		wantErr bool
+	}{
+		{name: "default length", length: GeneratedPasswordLength},
+		{name: "one character per class", length: len(passwordClasses)},
+		{name: "long", length: 64},
+		{name: "too short for every class", length: len(passwordClasses) - 1, wantErr: true},
+		{name: "zero", length: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GeneratePassword(tt.length)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Len(t, got, tt.length)
+			for _, class := range passwordClasses {
+				assert.True(t, strings.ContainsAny(got, class), "%q has no character of %q", got, class)
+			}
+			if tt.length >= GeneratedPasswordLength {
+				assert.False(t, IsWeakPassword(got), "%q is weak", got)
+			}
+		})
+	}
+}
+
+func TestGeneratePassword_Differs(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 50 {
+		got, err := GeneratePassword(GeneratedPasswordLength)
+		require.NoError(t, err)
+		assert.False(t, seen[got], "password %q generated twice", got)
+		seen[got] = true
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// WeakPasswordEntropyBits is the estimated entropy below which
+// [IsWeakPassword] reports a master password as weak.
+const WeakPasswordEntropyBits = 60
+
+// commonPasswords are rejected as weak whatever their estimated entropy:
+// they are the first guesses of any dictionary attack.
+var commonPasswords = map[string]struct{}{
+	"password":   {},
+	"password1":  {},
+	"passw0rd":   {},
+	"qwerty":     {},
+	"qwertyuiop": {},
+	"123456":     {},
+	"12345678":   {},
+	"123456789":  {},
+	"1234567890": {},
+	"111111":     {},
+	"abc123":     {},
+	"letmein":    {},
+	"iloveyou":   {},
+	"admin":      {},
+	"welcome":    {},
+	"monkey":     {},
+	"dragon":     {},
+	"йцукен":     {},
+}
+
+// PasswordEntropyBits estimates the entropy of password as the number of
+// distinct characters times log2 of the alphabet its character classes span
+// (lower- and upper-case letters, digits, ASCII symbols, other characters).
+// Counting distinct characters rather than the length discounts repeats
+// such as "aaaaaaaa". It is a rough score for a hint, not a guarantee.
+func PasswordEntropyBits(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+	distinct := make(map[rune]struct{})
+	for _, r := range password {
+		distinct[r] = struct{}{}
+		switch {
+		case r > unicode.MaxASCII:
+			other = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	alphabet := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 66}} {
+		if class.present {
+			alphabet += class.size
+		}
+	}
+	if alphabet == 0 {
+		return 0
+	}
+	return float64(len(distinct)) * math.Log2(float64(alphabet))
+}
+
+// IsWeakPassword reports whether password is a common password or scores
+// below [WeakPasswordEntropyBits]. The result must not be stored: it says
+// something about the master password.
+func IsWeakPassword(password string) bool {
+	if _, common := commonPasswords[strings.ToLower(password)]; common {
+		return true
+	}
+	return PasswordEntropyBits(password) < WeakPasswordEntropyBits
+}
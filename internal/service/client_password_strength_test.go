@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsWeakPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     bool
+	}{
+		{name: "empty", password: "", want: true},
+		{name: "short digits", password: "123456", want: true},
+		{name: "common password despite classes", password: "Passw0rd", want: true},
+		{name: "repeated character", password: "aaaaaaaaaaaaaaaaaaaaaaaa", want: true},
+		{name: "lower-case word", password: "sunshine", want: true},
+		{name: "mixed classes", password: "k7#Rm2!vQz9p", want: false},
+		{name: "long passphrase", password: "correct horse battery staple", want: false},
+		{name: "cyrillic passphrase", password: "жёлтый трамвай едет", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsWeakPassword(tt.password), "entropy %.1f bits", PasswordEntropyBits(tt.password))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// RecoveryKit implements ClientAuthService. Only the login, the user ID and
+// the public encryption salt are copied from the cached bundle; the auth
+// hash and the wrapped DEK are left out.
+func (a *clientAuthService) RecoveryKit(ctx context.Context, userID int64) (models.RecoveryKit, error) {
+	cached, err := a.localStore.UserRepository.GetUserByID(ctx, userID)
+	if errors.Is(err, store.ErrNoUserWasFound) {
+		return models.RecoveryKit{}, ErrOfflineNoLocalUser
+	}
+	if err != nil {
+		return models.RecoveryKit{}, fmt.Errorf("load cached credentials: %w", err)
+	}
+
+	return models.RecoveryKit{
+		Login:          cached.Login,
+		UserID:         cached.UserID,
+		EncryptionSalt: cached.EncryptionSalt,
+		CreatedAt:      a.clock.Now().UTC(),
+	}, nil
+}
+
+// RenderRecoveryKit formats kit as a printable text page.
+func RenderRecoveryKit(kit models.RecoveryKit) string {
+	var b strings.Builder
+	b.WriteString("GO-PASS-KEEPER: НАБОР ВОССТАНОВЛЕНИЯ\n\n")
+	writeShareLine(&b, "Логин", kit.Login)
+	writeShareLine(&b, "ID пользователя", fmt.Sprintf("%d", kit.UserID))
+	writeShareLine(&b, "Соль шифрования", kit.EncryptionSalt)
+	writeShareLine(&b, "Создан", kit.CreatedAt.Format(time.RFC3339))
+	b.WriteString("\n")
+	b.WriteString("Набор не содержит ни мастер-пароля, ни ключа шифрования.\n")
+	b.WriteString("Для входа с любого устройства по-прежнему нужен мастер-пароль:\n")
+	b.WriteString("без него данные восстановить невозможно. Храните набор отдельно\n")
+	b.WriteString("от пароля, например в распечатанном виде.\n")
+	return b.String()
+}
+
+// RecoveryKitFileName returns the file name of the recovery kit of login.
+// Every character of the login other than a letter, a digit, '-', '_' or
+// '.' is replaced with '_' so that the name is safe on every OS.
+func RecoveryKitFileName(login string) string {
+	safe := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(login))
+	return "go-pass-keeper-recovery-" + strings.Trim(safe, ".") + ".txt"
+}
+
+// WriteRecoveryKit writes the rendered kit into dir (the working directory
+// when empty) with mode 0600 and returns the path of the file. An existing
+// kit of the same login is replaced.
+func WriteRecoveryKit(dir string, kit models.RecoveryKit) (string, error) {
+	path := filepath.Join(dir, RecoveryKitFileName(kit.Login))
+	if err := os.WriteFile(path, []byte(RenderRecoveryKit(kit)), 0o600); err != nil {
+		return "", fmt.Errorf("write recovery kit: %w", err)
+	}
+	return path, nil
+}
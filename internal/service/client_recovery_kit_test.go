@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/mock"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClientAuthService_RecoveryKit(t *testing.T) {
+	cached := models.User{
+		UserID:             42,
+		Login:              "alice",
+		AuthHash:           "auth-hash-secret",
+		EncryptionSalt:     "c2FsdA==",
+		EncryptedMasterKey: "wrapped-dek-secret",
+	}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	dbErr := errors.New("disk I/O error")
+
+	tests := []struct {
+		name    string
+		user    models.User
+		err     error
+		want    models.RecoveryKit
+		wantErr error
+	}{
+		{name: "cached user", user: cached, want: models.RecoveryKit{Login: "alice", UserID: 42, EncryptionSalt: "c2FsdA==", CreatedAt: createdAt}},
+		{name: "never logged in here", err: store.ErrNoUserWasFound, wantErr: ErrOfflineNoLocalUser},
+		{name: "store error", err: dbErr, wantErr: dbErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			users := mock.NewMockLocalUserRepository(ctrl)
+			users.EXPECT().GetUserByID(gomock.Any(), int64(42)).Return(tt.user, tt.err)
+			svc := NewClientAuthService(&store.ClientStorages{UserRepository: users}, nil, nil, nil, false, LoginPolicy{}, clock.NewFake(createdAt.In(time.FixedZone("MSK", 3*60*60))))
+
+			kit, err := svc.RecoveryKit(context.Background(), 42)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			text := RenderRecoveryKit(kit)
+			assert.NotContains(t, text, cached.AuthHash)
+			assert.NotContains(t, text, cached.EncryptedMasterKey)
+
+			assert.Equal(t, tt.want, kit)
+		})
+	}
+}
+
+func TestWriteRecoveryKit_ExcludesSecrets(t *testing.T) {
+	kit := models.RecoveryKit{
+		Login:          "alice@example.com",
+		UserID:         42,
+		EncryptionSalt: "c2FsdA==",
+		CreatedAt:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	dir := t.TempDir()
+
+	path, err := WriteRecoveryKit(dir, kit)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "go-pass-keeper-recovery-alice_example.com.txt"), path)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	text := string(content)
+	for _, want := range []string{"Логин: alice@example.com", "ID пользователя: 42", "Соль шифрования: c2FsdA==", "2026-03-01T12:00:00Z", "нужен мастер-пароль"} {
+		assert.Contains(t, text, want)
+	}
+}
+
+func TestRecoveryKitFileName(t *testing.T) {
+	assert.Equal(t, "go-pass-keeper-recovery-alice.txt", RecoveryKitFileName(" alice "))
+	assert.Equal(t, "go-pass-keeper-recovery-_.._etc_passwd.txt", RecoveryKitFileName("/../etc/passwd"))
+	assert.Equal(t, "go-pass-keeper-recovery-алиса.txt", RecoveryKitFileName("алиса"))
+}
@@ -6,9 +6,13 @@ package service
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"net"
+	"time"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
 	"github.com/MKhiriev/go-pass-keeper/internal/crypto"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
 	"github.com/MKhiriev/go-pass-keeper/models"
@@ -21,13 +25,44 @@ type clientAuthService struct {
 	adapter             adapter.ServerAdapter
 	clientCryptoService ClientCryptoService
 	crypto              crypto.KeyChainService
+	offline             bool
+	loginPolicy         LoginPolicy
+	clock               clock.Clock
+}
+
+// LoginPolicy bounds the server calls of the online login handshake.
+type LoginPolicy struct {
+	// Timeout limits the whole handshake, retries included. Zero means no
+	// limit beyond the caller's context.
+	Timeout time.Duration
+	// Retries is how many times a server call failing with a transient error
+	// (a network failure or a 5xx response) is repeated. Zero disables
+	// retries.
+	Retries int
+}
+
+// loginRetryDelay is the pause before every retry of a login server call.
+var loginRetryDelay = 500 * time.Millisecond
+
+type loginAttemptKey struct{}
+
+// WithLoginAttemptObserver returns a context that makes
+// [ClientAuthService.Login] call observe with the attempt number (starting
+// at 2) before every retry of a server call, e.g. to show the progress in the
+// UI. observe is called from the goroutine running Login.
+func WithLoginAttemptObserver(ctx context.Context, observe func(attempt int)) context.Context {
+	return context.WithValue(ctx, loginAttemptKey{}, observe)
 }
 
 // NewClientAuthService constructs a clientAuthService wired to the provided local
 // store, server adapter, key-chain service, and crypto service.
+// When offline is true, Login unlocks the vault from the credentials cached
+// in the local store and Register is refused with [ErrOfflineMode].
+// The server calls of an online Login are bounded by loginPolicy. Recovery
+// kits are dated with clk.
 // The returned service is safe for concurrent use.
-func NewClientAuthService(localStore *store.ClientStorages, serverAdapter adapter.ServerAdapter, crypto crypto.KeyChainService, cryptoSvc ClientCryptoService) ClientAuthService {
-	return &clientAuthService{localStore: localStore, adapter: serverAdapter, crypto: crypto, clientCryptoService: cryptoSvc}
+func NewClientAuthService(localStore *store.ClientStorages, serverAdapter adapter.ServerAdapter, crypto crypto.KeyChainService, cryptoSvc ClientCryptoService, offline bool, loginPolicy LoginPolicy, clk clock.Clock) ClientAuthService {
+	return &clientAuthService{localStore: localStore, adapter: serverAdapter, crypto: crypto, clientCryptoService: cryptoSvc, offline: offline, loginPolicy: loginPolicy, clock: clk}
 }
 
 // Register implements ClientAuthService.
@@ -43,6 +78,10 @@ func NewClientAuthService(localStore *store.ClientStorages, serverAdapter adapte
 //
 // Returns an error if any key-generation, encryption, or server call fails.
 func (a *clientAuthService) Register(ctx context.Context, user models.User) error {
+	if a.offline {
+		return ErrOfflineMode
+	}
+
 	salt, err := a.crypto.GenerateEncryptionSalt()
 	if err != nil {
 		return fmt.Errorf("error generating Salt: %v", err)
@@ -71,7 +110,7 @@ func (a *clientAuthService) Register(ctx context.Context, user models.User) erro
 
 	_, err = a.adapter.Register(ctx, user)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrRegisterOnServer, err)
+		return serverAuthError(ErrRegisterOnServer, err)
 	}
 
 	return nil
@@ -86,14 +125,44 @@ func (a *clientAuthService) Register(ctx context.Context, user models.User) erro
 //  4. Send the login + auth hash to the server and receive the encrypted master key.
 //  5. Decode the base64 encrypted master key and decrypt it with the KEK to get the DEK.
 //  6. Store the DEK in the crypto service via SetEncryptionKey.
+//  7. Cache the credential bundle (never the password or the plaintext DEK)
+//     in the local store so that a later offline login can unlock the vault.
+//
+// Before anything else the local store is switched to the database of the
+// login with [store.ClientStorages.OpenAccount], so that accounts sharing a
+// device never see each other's items.
+//
+// In offline mode the server is not contacted; see [clientAuthService.loginOffline].
+//
+// Steps 1 and 4 are retried and time-limited according to the service's
+// [LoginPolicy]; running out of time yields [ErrServerNotResponding].
 //
 // Returns the server-assigned user ID and the plaintext DEK, or an error if
 // any step fails.
 func (a *clientAuthService) Login(ctx context.Context, user models.User) (int64, []byte, error) {
+	if err := a.localStore.OpenAccount(user.Login); err != nil {
+		return 0, nil, fmt.Errorf("open local store of %s: %w", user.Login, err)
+	}
+
+	if a.offline {
+		return a.loginOffline(ctx, user)
+	}
+
+	callCtx := ctx
+	if a.loginPolicy.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, a.loginPolicy.Timeout)
+		defer cancel()
+	}
+
 	// Fetch encryption_salt from the server by login.
-	userWithSalt, err := a.adapter.RequestSalt(ctx, user)
+	var userWithSalt models.User
+	err := a.retryLogin(callCtx, func() (err error) {
+		userWithSalt, err = a.adapter.RequestSalt(callCtx, user)
+		return err
+	})
 	if err != nil {
-		return 0, nil, fmt.Errorf("%w: %v", ErrLoginOnServer, err)
+		return 0, nil, a.loginServerError(ctx, err)
 	}
 
 	// Decode the salt and derive the KEK from the master password + salt.
@@ -108,9 +177,13 @@ func (a *clientAuthService) Login(ctx context.Context, user models.User) (int64,
 	user.AuthHash = base64.StdEncoding.EncodeToString(authHashBytes)
 
 	// Send login + auth_hash to the server; receive the encrypted master key.
-	foundUser, err := a.adapter.Login(ctx, user)
+	var foundUser models.User
+	err = a.retryLogin(callCtx, func() (err error) {
+		foundUser, err = a.adapter.Login(callCtx, user)
+		return err
+	})
 	if err != nil {
-		return 0, nil, fmt.Errorf("%w: %v", ErrLoginOnServer, err)
+		return 0, nil, a.loginServerError(ctx, err)
 	}
 
 	// Decode the encrypted master key and decrypt the DEK using the KEK.
@@ -126,5 +199,144 @@ func (a *clientAuthService) Login(ctx context.Context, user models.User) (int64,
 
 	a.clientCryptoService.SetEncryptionKey(dek)
 
+	// The auth hash is not cached: it is the credential the server accepts.
+	cached := models.User{
+		UserID:             foundUser.UserID,
+		Login:              user.Login,
+		EncryptionSalt:     userWithSalt.EncryptionSalt,
+		EncryptedMasterKey: foundUser.EncryptedMasterKey,
+	}
+	if err = a.localStore.UserRepository.SaveUser(ctx, cached); err != nil {
+		return 0, nil, fmt.Errorf("cache credentials locally: %w", err)
+	}
+
 	return foundUser.UserID, dek, nil
 }
+
+// retryLogin runs call and repeats it up to [LoginPolicy.Retries] times while
+// it fails with a transient error. Both login server calls only read, so
+// repeating them is safe.
+func (a *clientAuthService) retryLogin(ctx context.Context, call func() error) error {
+	observe, _ := ctx.Value(loginAttemptKey{}).(func(int))
+
+	err := call()
+	for attempt := 2; attempt <= a.loginPolicy.Retries+1 && isTransientLoginError(err); attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(loginRetryDelay):
+		}
+		if observe != nil {
+			observe(attempt)
+		}
+		err = call()
+	}
+	return err
+}
+
+// isTransientLoginError reports whether err is worth another attempt: a
+// network failure, a 5xx response or a timeout of the single request.
+func isTransientLoginError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, adapter.ErrBadGateway) || errors.Is(err, adapter.ErrInternalServerError) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// loginServerError wraps a failed login server call like [serverAuthError],
+// except that running out of the login timeout while ctx, the caller's
+// context, is still alive yields [ErrServerNotResponding]. So does a request
+// that kept timing out on the network level.
+func (a *clientAuthService) loginServerError(ctx context.Context, err error) error {
+	var netErr net.Error
+	timedOut := errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+	if timedOut && ctx.Err() == nil {
+		return fmt.Errorf("%w: %w", ErrServerNotResponding, err)
+	}
+	return serverAuthError(ErrLoginOnServer, err)
+}
+
+// serverAuthError wraps an adapter error of the register or login flow in
+// base, or in [ErrInsecureConnection] when the server address was refused as
+// plain http.
+func serverAuthError(base, err error) error {
+	if errors.Is(err, adapter.ErrInsecureTransport) {
+		return fmt.Errorf("%w: %w", ErrInsecureConnection, err)
+	}
+	return fmt.Errorf("%w: %v", base, err)
+}
+
+// loginOffline unlocks the vault from the credential bundle cached by a
+// previous online login. The KEK is derived from the master password and the
+// cached salt and unwraps the cached DEK; a wrong password fails the GCM
+// authentication of the wrapped key. Returns [ErrOfflineNoLocalUser] if the login was never used
+// on this device and [ErrOfflineWrongPassword] if the password is wrong.
+func (a *clientAuthService) loginOffline(ctx context.Context, user models.User) (int64, []byte, error) {
+	cached, err := a.localStore.UserRepository.GetUserByLogin(ctx, user.Login)
+	if errors.Is(err, store.ErrNoUserWasFound) {
+		return 0, nil, ErrOfflineNoLocalUser
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("load cached credentials: %w", err)
+	}
+
+	dek, ok, err := a.cachedDEK(cached, user.MasterPassword)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !ok {
+		return 0, nil, ErrOfflineWrongPassword
+	}
+
+	a.clientCryptoService.SetEncryptionKey(dek)
+
+	return cached.UserID, dek, nil
+}
+
+// VerifyMasterPassword implements ClientAuthService. The KEK is derived from
+// masterPassword and the cached salt and must unwrap the cached DEK, so no
+// server is contacted.
+func (a *clientAuthService) VerifyMasterPassword(ctx context.Context, userID int64, masterPassword string) error {
+	cached, err := a.localStore.UserRepository.GetUserByID(ctx, userID)
+	if errors.Is(err, store.ErrNoUserWasFound) {
+		return ErrOfflineNoLocalUser
+	}
+	if err != nil {
+		return fmt.Errorf("load cached credentials: %w", err)
+	}
+
+	_, ok, err := a.cachedDEK(cached, masterPassword)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrWrongMasterPassword
+	}
+	return nil
+}
+
+// cachedDEK derives the KEK of masterPassword with the salt of the cached
+// credentials and unwraps the cached DEK with it. ok is false when the
+// unwrapping fails, i.e. the password is wrong: AES-GCM authenticates the
+// wrapped key, so a wrong KEK never yields a DEK.
+func (a *clientAuthService) cachedDEK(cached models.User, masterPassword string) (dek []byte, ok bool, err error) {
+	saltBytes, err := base64.StdEncoding.DecodeString(cached.EncryptionSalt)
+	if err != nil {
+		return nil, false, fmt.Errorf("decode encryption salt: %w", err)
+	}
+	encryptedBlob, err := base64.StdEncoding.DecodeString(cached.EncryptedMasterKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("decode encrypted master key: %w", err)
+	}
+
+	kek := a.crypto.GenerateKEK(masterPassword, saltBytes)
+	dek, err = a.crypto.DecryptDEK(encryptedBlob, kek)
+	if err != nil {
+		return nil, false, nil
+	}
+	return dek, true, nil
+}
@@ -7,8 +7,13 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"fmt"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
 	"github.com/MKhiriev/go-pass-keeper/internal/crypto"
 	"github.com/MKhiriev/go-pass-keeper/internal/mock"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
@@ -33,9 +38,11 @@ func newTestAuthSvc(
 	mockKeyChain := mock.NewMockKeyChainService(ctrl)
 	mockCryptoSvc := mock.NewMockClientCryptoService(ctrl)
 
-	storages := &store.ClientStorages{}
+	mockUsers := mock.NewMockLocalUserRepository(ctrl)
+	mockUsers.EXPECT().SaveUser(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	storages := &store.ClientStorages{UserRepository: mockUsers}
 
-	svc := NewClientAuthService(storages, mockAdapter, mockKeyChain, mockCryptoSvc).(*clientAuthService)
+	svc := NewClientAuthService(storages, mockAdapter, mockKeyChain, mockCryptoSvc, false, LoginPolicy{}, clock.Real{}).(*clientAuthService)
 	svc.clientCryptoService = mockCryptoSvc
 
 	return svc, mockAdapter, mockKeyChain, mockCryptoSvc
@@ -226,6 +233,23 @@ func TestClientAuthService_Login_RequestSaltError(t *testing.T) {
 	assert.ErrorIs(t, err, ErrLoginOnServer)
 }
 
+func TestClientAuthService_Login_InsecureConnection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockAdapter, _, _ := newTestAuthSvc(t, ctrl)
+	ctx := context.Background()
+
+	user := models.User{Login: "testuser", MasterPassword: "pass"}
+
+	mockAdapter.EXPECT().RequestSalt(ctx, user).Return(models.User{}, fmt.Errorf("salt request: %w", adapter.ErrInsecureTransport))
+
+	_, _, err := svc.Login(ctx, user)
+	assert.ErrorIs(t, err, ErrInsecureConnection)
+	assert.ErrorIs(t, err, adapter.ErrInsecureTransport)
+	assert.NotErrorIs(t, err, ErrLoginOnServer)
+}
+
 func TestClientAuthService_Login_InvalidSaltBase64(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -269,6 +293,114 @@ func TestClientAuthService_Login_AdapterLoginError(t *testing.T) {
 	assert.ErrorIs(t, err, ErrLoginOnServer)
 }
 
+func TestClientAuthService_Login_RequestSaltTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockAdapter, _, _ := newTestAuthSvc(t, ctrl)
+	svc.loginPolicy = LoginPolicy{Timeout: 20 * time.Millisecond}
+
+	user := models.User{Login: "testuser", MasterPassword: "pass"}
+
+	// The server never answers: the call returns only when the login timeout
+	// cancels its context.
+	mockAdapter.EXPECT().RequestSalt(gomock.Any(), user).DoAndReturn(
+		func(ctx context.Context, _ models.User) (models.User, error) {
+			<-ctx.Done()
+			return models.User{}, fmt.Errorf("salt request: %w", ctx.Err())
+		},
+	)
+
+	_, _, err := svc.Login(context.Background(), user)
+	assert.ErrorIs(t, err, ErrServerNotResponding)
+	assert.NotErrorIs(t, err, ErrLoginOnServer)
+}
+
+func TestClientAuthService_Login_RetriesTransientFailure(t *testing.T) {
+	loginRetryDelay = 0
+	t.Cleanup(func() { loginRetryDelay = 500 * time.Millisecond })
+
+	tests := []struct {
+		name      string
+		retries   int
+		firstErr  error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "transient failure is retried once",
+			retries:   1,
+			firstErr:  fmt.Errorf("salt request: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}),
+			wantCalls: 2,
+		},
+		{
+			name:      "bad gateway is retried",
+			retries:   1,
+			firstErr:  adapter.ErrBadGateway,
+			wantCalls: 2,
+		},
+		{
+			name:      "retries disabled",
+			firstErr:  adapter.ErrBadGateway,
+			wantCalls: 1,
+			wantErr:   ErrLoginOnServer,
+		},
+		{
+			name:      "wrong credentials are not retried",
+			retries:   1,
+			firstErr:  adapter.ErrUnauthorized,
+			wantCalls: 1,
+			wantErr:   ErrLoginOnServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			svc, mockAdapter, mockKeyChain, mockCryptoSvc := newTestAuthSvc(t, ctrl)
+			svc.loginPolicy = LoginPolicy{Retries: tt.retries}
+
+			user := models.User{Login: "testuser", MasterPassword: "pass"}
+			salt := []byte("salt")
+			calls := 0
+			mockAdapter.EXPECT().RequestSalt(gomock.Any(), user).DoAndReturn(
+				func(context.Context, models.User) (models.User, error) {
+					calls++
+					if calls == 1 {
+						return models.User{}, tt.firstErr
+					}
+					return models.User{EncryptionSalt: base64.StdEncoding.EncodeToString(salt)}, nil
+				},
+			).Times(tt.wantCalls)
+
+			var attempts []int
+			ctx := WithLoginAttemptObserver(context.Background(), func(attempt int) {
+				attempts = append(attempts, attempt)
+			})
+
+			if tt.wantErr == nil {
+				mockKeyChain.EXPECT().GenerateKEK(user.MasterPassword, salt).Return([]byte("kek"))
+				mockKeyChain.EXPECT().GenerateAuthHash([]byte("kek"), authSalt).Return([]byte("hash"))
+				mockAdapter.EXPECT().Login(gomock.Any(), gomock.Any()).Return(models.User{
+					UserID:             1,
+					EncryptedMasterKey: base64.StdEncoding.EncodeToString([]byte("blob")),
+				}, nil)
+				mockKeyChain.EXPECT().DecryptDEK([]byte("blob"), []byte("kek")).Return([]byte("dek"), nil)
+				mockCryptoSvc.EXPECT().SetEncryptionKey([]byte("dek"))
+			}
+
+			_, _, err := svc.Login(ctx, user)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, attempts)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []int{2}, attempts)
+		})
+	}
+}
+
 func TestClientAuthService_Login_InvalidEncryptedMasterKeyBase64(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -345,7 +477,10 @@ func newIntegrationAuthSvc(
 	mockAdapter := mock.NewMockServerAdapter(ctrl)
 	cryptoSvc := NewClientCryptoService(keyChain)
 
-	svc := NewClientAuthService(&store.ClientStorages{}, mockAdapter, keyChain, cryptoSvc).(*clientAuthService)
+	mockUsers := mock.NewMockLocalUserRepository(ctrl)
+	mockUsers.EXPECT().SaveUser(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	svc := NewClientAuthService(&store.ClientStorages{UserRepository: mockUsers}, mockAdapter, keyChain, cryptoSvc, false, LoginPolicy{}, clock.Real{}).(*clientAuthService)
 	svc.clientCryptoService = cryptoSvc
 
 	return svc, mockAdapter, cryptoSvc
@@ -471,3 +606,145 @@ func TestIntegration_LoginWithWrongPassword(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "decrypt DEK")
 }
+
+// TestIntegration_OfflineLogin — онлайн-логин кэширует учётные данные
+// локально, после чего офлайн-сервис открывает хранилище без сервера.
+func TestIntegration_OfflineLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	password := "my-strong-master-password"
+	keyChain := crypto.NewKeyChainService()
+
+	// Локальный кэш пользователей в памяти.
+	cache := map[string]models.User{}
+	users := mock.NewMockLocalUserRepository(ctrl)
+	users.EXPECT().SaveUser(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, u models.User) error {
+			cache[u.Login] = u
+			return nil
+		},
+	).AnyTimes()
+	users.EXPECT().GetUserByLogin(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, login string) (models.User, error) {
+			u, ok := cache[login]
+			if !ok {
+				return models.User{}, store.ErrNoUserWasFound
+			}
+			return u, nil
+		},
+	).AnyTimes()
+	storages := &store.ClientStorages{UserRepository: users}
+
+	// ── Онлайн: регистрация и логин ──
+	mockAdapter := mock.NewMockServerAdapter(ctrl)
+	online := NewClientAuthService(storages, mockAdapter, keyChain, NewClientCryptoService(keyChain), false, LoginPolicy{}, clock.Real{})
+
+	var serverUser models.User
+	mockAdapter.EXPECT().Register(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, u models.User) (models.User, error) {
+			serverUser = u
+			serverUser.UserID = 77
+			return serverUser, nil
+		},
+	)
+	require.NoError(t, online.Register(ctx, models.User{Login: "alice", MasterPassword: password}))
+
+	mockAdapter.EXPECT().RequestSalt(ctx, gomock.Any()).Return(models.User{EncryptionSalt: serverUser.EncryptionSalt}, nil)
+	mockAdapter.EXPECT().Login(ctx, gomock.Any()).Return(models.User{UserID: 77, EncryptedMasterKey: serverUser.EncryptedMasterKey}, nil)
+	_, onlineDEK, err := online.Login(ctx, models.User{Login: "alice", MasterPassword: password})
+	require.NoError(t, err)
+
+	require.Contains(t, cache, "alice")
+	assert.Empty(t, cache["alice"].MasterPassword)
+	assert.Empty(t, cache["alice"].AuthHash, "the server credential must not be cached")
+
+	// ── Офлайн: сервер не вызывается (у мока нет ожиданий) ──
+	offlineCrypto := NewClientCryptoService(keyChain)
+	offline := NewClientAuthService(storages, mock.NewMockServerAdapter(ctrl), keyChain, offlineCrypto, true, LoginPolicy{}, clock.Real{})
+
+	tests := []struct {
+		name     string
+		user     models.User
+		wantErr  error
+		wantUser int64
+	}{
+		{name: "correct password", user: models.User{Login: "alice", MasterPassword: password}, wantUser: 77},
+		{name: "wrong password", user: models.User{Login: "alice", MasterPassword: "wrong"}, wantErr: ErrOfflineWrongPassword},
+		{name: "unknown login", user: models.User{Login: "bob", MasterPassword: password}, wantErr: ErrOfflineNoLocalUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, dek, err := offline.Login(ctx, tt.user)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUser, userID)
+			assert.Equal(t, onlineDEK, dek)
+			assert.True(t, offlineCrypto.HasEncryptionKey())
+		})
+	}
+
+	require.ErrorIs(t, offline.Register(ctx, models.User{Login: "carol", MasterPassword: password}), ErrOfflineMode)
+}
+
+func TestClientAuthService_VerifyMasterPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	password := "my-strong-master-password"
+	keyChain := crypto.NewKeyChainService()
+
+	salt := []byte("0123456789abcdef")
+	kek := keyChain.GenerateKEK(password, salt)
+	dek, err := keyChain.GenerateDEK()
+	require.NoError(t, err)
+	wrapped, err := keyChain.GetEncryptedDEK(dek, kek)
+	require.NoError(t, err)
+	cached := models.User{
+		UserID:             77,
+		Login:              "alice",
+		EncryptionSalt:     base64.StdEncoding.EncodeToString(salt),
+		EncryptedMasterKey: base64.StdEncoding.EncodeToString(wrapped),
+	}
+
+	users := mock.NewMockLocalUserRepository(ctrl)
+	users.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, userID int64) (models.User, error) {
+			if userID != cached.UserID {
+				return models.User{}, store.ErrNoUserWasFound
+			}
+			return cached, nil
+		},
+	).AnyTimes()
+
+	// The server is never called: the mock has no expectations.
+	svc := NewClientAuthService(&store.ClientStorages{UserRepository: users}, mock.NewMockServerAdapter(ctrl), keyChain, NewClientCryptoService(keyChain), false, LoginPolicy{}, clock.Real{})
+
+	tests := []struct {
+		name     string
+		userID   int64
+		password string
+		wantErr  error
+	}{
+		{name: "correct password", userID: 77, password: password},
+		{name: "wrong password", userID: 77, password: "wrong", wantErr: ErrWrongMasterPassword},
+		{name: "unknown user", userID: 78, password: password, wantErr: ErrOfflineNoLocalUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.VerifyMasterPassword(ctx, tt.userID, tt.password)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// conflictCopySuffix is appended to the name of the local copy made by
+// [models.ConflictKeepBoth], so that both entries can be told apart.
+const conflictCopySuffix = " (локальная копия)"
+
+// LoadConflict implements ClientSyncService.
+func (s *clientSyncService) LoadConflict(ctx context.Context, userID int64, conflict models.VersionConflict) (models.ConflictVersions, error) {
+	local, server, err := s.conflictSides(ctx, userID, conflict.ClientSideID)
+	if err != nil {
+		return models.ConflictVersions{}, err
+	}
+
+	versions := models.ConflictVersions{Conflict: conflict}
+	versions.Conflict.ServerVersion = server.Version
+	if versions.Local, err = s.crypto.DecryptPayload(local.Payload); err != nil {
+		return models.ConflictVersions{}, fmt.Errorf("decrypt local version of %s: %w", conflict.ClientSideID, err)
+	}
+	if versions.Server, err = s.crypto.DecryptPayload(server.Payload); err != nil {
+		return models.ConflictVersions{}, fmt.Errorf("decrypt server version of %s: %w", conflict.ClientSideID, err)
+	}
+	for _, side := range []*models.DecipheredPayload{&versions.Local, &versions.Server} {
+		side.ClientSideID = conflict.ClientSideID
+		side.UserID = userID
+	}
+
+	return versions, nil
+}
+
+// ResolveConflict implements ClientSyncService.
+//
+// Once the choice is applied the conflict is no longer pending, and later
+// syncs handle the item as usual again.
+//
+//   - [models.ConflictKeepServer] stores the server's version locally.
+//   - [models.ConflictKeepLocal] sends the local version as an update based
+//     on the server's current version, then stores it locally under the
+//     version the server assigned.
+//   - [models.ConflictKeepBoth] saves the local version as a new item, named
+//     with [conflictCopySuffix] and uploaded by the next sync, and then keeps
+//     the server's version under the original ID.
+func (s *clientSyncService) ResolveConflict(ctx context.Context, userID int64, clientSideID string, choice models.ConflictChoice) error {
+	if err := s.resolveConflict(ctx, userID, clientSideID, choice); err != nil {
+		return err
+	}
+	if err := s.localStore.SyncStateRepository.DeletePendingConflict(ctx, userID, clientSideID); err != nil {
+		return fmt.Errorf("forget resolved conflict %s: %w", clientSideID, err)
+	}
+	return nil
+}
+
+func (s *clientSyncService) resolveConflict(ctx context.Context, userID int64, clientSideID string, choice models.ConflictChoice) error {
+	conflict := models.VersionConflict{Operation: models.ConflictOnUpdate, ClientSideID: clientSideID}
+
+	switch choice {
+	case models.ConflictKeepServer:
+		return s.refreshConflict(ctx, userID, conflict)
+	case models.ConflictKeepLocal:
+		return s.overwriteServer(ctx, userID, clientSideID)
+	case models.ConflictKeepBoth:
+		if err := s.saveLocalCopy(ctx, userID, clientSideID); err != nil {
+			return err
+		}
+		return s.refreshConflict(ctx, userID, conflict)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownConflictChoice, choice)
+	}
+}
+
+// withoutConflicts drops the items of the pending conflicts from the
+// downloads and updates of plan, so that neither side overwrites the other
+// before the user has chosen.
+func withoutConflicts(plan models.SyncPlan, pending []models.VersionConflict) models.SyncPlan {
+	if len(pending) == 0 {
+		return plan
+	}
+
+	held := make(map[string]struct{}, len(pending))
+	for _, c := range pending {
+		held[c.ClientSideID] = struct{}{}
+	}
+	isHeld := func(st models.PrivateDataState) bool {
+		_, ok := held[st.ClientSideID]
+		return ok
+	}
+	plan.Download = slices.DeleteFunc(plan.Download, isHeld)
+	plan.Update = slices.DeleteFunc(plan.Update, isHeld)
+	return plan
+}
+
+// conflictSides loads the local copy of clientSideID and downloads the
+// server's current one.
+func (s *clientSyncService) conflictSides(ctx context.Context, userID int64, clientSideID string) (local, server models.PrivateData, err error) {
+	local, err = s.localStore.PrivateDataRepository.GetPrivateData(ctx, clientSideID, userID)
+	if err != nil {
+		return local, server, fmt.Errorf("load local version of %s: %w", clientSideID, err)
+	}
+
+	items, err := s.adapter.Download(ctx, models.DownloadRequest{UserID: userID, ClientSideIDs: []string{clientSideID}, Length: 1})
+	if err != nil {
+		return local, server, fmt.Errorf("download server version of %s: %w", clientSideID, err)
+	}
+	if len(items) == 0 || items[0].Deleted {
+		return local, server, fmt.Errorf("%w: %s", ErrConflictItemGone, clientSideID)
+	}
+	if err = s.verifyIntegrity(items[:1]); err != nil {
+		return local, server, err
+	}
+
+	return local, items[0], nil
+}
+
+// overwriteServer replaces the server's version of clientSideID with the
+// local one.
+func (s *clientSyncService) overwriteServer(ctx context.Context, userID int64, clientSideID string) error {
+	local, server, err := s.conflictSides(ctx, userID, clientSideID)
+	if err != nil {
+		return err
+	}
+
+	req := updateRequestOf(userID, local, server.Version)
+	if err = retryLocked(ctx, func() error { return s.adapter.Update(ctx, req) }); err != nil {
+		return fmt.Errorf("overwrite server item %s: %w", clientSideID, err)
+	}
+
+	local.Version = server.Version + 1
+	if err = s.localStore.PrivateDataRepository.UpdatePrivateData(ctx, local); err != nil {
+		return fmt.Errorf("store version of overwritten item %s: %w", clientSideID, err)
+	}
+	return nil
+}
+
+// saveLocalCopy saves the local version of clientSideID as a new item that
+// the server has not seen yet.
+func (s *clientSyncService) saveLocalCopy(ctx context.Context, userID int64, clientSideID string) error {
+	local, err := s.localStore.PrivateDataRepository.GetPrivateData(ctx, clientSideID, userID)
+	if err != nil {
+		return fmt.Errorf("load local version of %s: %w", clientSideID, err)
+	}
+	plain, err := s.crypto.DecryptPayload(local.Payload)
+	if err != nil {
+		return fmt.Errorf("decrypt local version of %s: %w", clientSideID, err)
+	}
+
+	plain.Metadata.Name += conflictCopySuffix
+	payload, err := s.crypto.EncryptPayload(plain)
+	if err != nil {
+		return fmt.Errorf("encrypt copy of %s: %w", clientSideID, err)
+	}
+	hash, err := s.crypto.ComputeHash(payload)
+	if err != nil {
+		return fmt.Errorf("compute hash of copy of %s: %w", clientSideID, err)
+	}
+
+	now := s.clock.Now().UTC()
+	copied := models.PrivateData{
+		ClientSideID: s.clientIDGenerator.Generate(),
+		UserID:       userID,
+		Payload:      payload,
+		Hash:         hash,
+		CreatedAt:    &now,
+		UpdatedAt:    &now,
+	}
+	if err = s.localStore.PrivateDataRepository.SavePrivateData(ctx, userID, copied); err != nil {
+		return fmt.Errorf("save copy of %s: %w", clientSideID, err)
+	}
+	return nil
+}
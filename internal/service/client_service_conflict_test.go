@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/crypto"
+	"github.com/MKhiriev/go-pass-keeper/internal/mock"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newConflictTestSyncSvc returns a sync service under the manual conflict
+// policy with a real crypto service, and the local and server versions of a
+// conflicting login "up1".
+func newConflictTestSyncSvc(t *testing.T, ctrl *gomock.Controller) (*clientSyncService, *conflictFixture) {
+	t.Helper()
+	svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	svc.conflicts = models.ConflictManual
+	mockSyncState := mock.NewMockLocalSyncStateRepository(ctrl)
+	svc.localStore.SyncStateRepository = mockSyncState
+
+	keyChain := crypto.NewKeyChainService()
+	dek, err := keyChain.GenerateDEK()
+	require.NoError(t, err)
+	cryptoSvc := NewClientCryptoService(keyChain)
+	cryptoSvc.SetEncryptionKey(dek)
+	svc.crypto = cryptoSvc
+
+	item := func(password string, version int64) models.PrivateData {
+		payload, encErr := cryptoSvc.EncryptPayload(models.DecipheredPayload{
+			Type:      models.LoginPassword,
+			Metadata:  models.Metadata{Name: "Банк"},
+			LoginData: &models.LoginData{Username: "user", Password: password},
+		})
+		require.NoError(t, encErr)
+		hash, hashErr := cryptoSvc.ComputeHash(payload)
+		require.NoError(t, hashErr)
+		return models.PrivateData{ClientSideID: "up1", UserID: 1, Payload: payload, Hash: hash, Version: version}
+	}
+
+	return svc, &conflictFixture{
+		repo:      mockRepo,
+		syncState: mockSyncState,
+		adapter:   mockAdapter,
+		crypto:    cryptoSvc,
+		local:     item("local-secret", 2),
+		server:    item("server-secret", 5),
+	}
+}
+
+type conflictFixture struct {
+	repo      *mock.MockLocalPrivateDataRepository
+	syncState *mock.MockLocalSyncStateRepository
+	adapter   *mock.MockServerAdapter
+	crypto    ClientCryptoService
+	local     models.PrivateData
+	server    models.PrivateData
+}
+
+func (f *conflictFixture) password(t *testing.T, item models.PrivateData) string {
+	t.Helper()
+	plain, err := f.crypto.DecryptPayload(item.Payload)
+	require.NoError(t, err)
+	return plain.LoginData.Password
+}
+
+func TestClientSyncService_ExecutePlan_ManualConflictIsReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc, f := newConflictTestSyncSvc(t, ctrl)
+	ctx := context.Background()
+
+	plan := models.SyncPlan{Update: []models.PrivateDataState{{ClientSideID: "up1"}, {ClientSideID: "up2"}}}
+	f.repo.EXPECT().GetPrivateData(ctx, "up1", int64(1)).Return(f.local, nil)
+	f.repo.EXPECT().GetPrivateData(ctx, "up2", int64(1)).Return(models.PrivateData{ClientSideID: "up2", UserID: 1, Version: 3}, nil)
+	gomock.InOrder(
+		f.adapter.EXPECT().Update(ctx, gomock.Any()).Return(&adapter.ConflictError{VersionConflict: models.VersionConflict{ServerVersion: 5}}),
+		// The conflict does not stop the plan.
+		f.adapter.EXPECT().Update(ctx, gomock.Any()).Return(nil),
+	)
+	// Nothing is downloaded: the local change is kept for the user to decide.
+	conflict := models.VersionConflict{Operation: models.ConflictOnUpdate, ClientSideID: "up1", ServerVersion: 5}
+	earlier := models.VersionConflict{Operation: models.ConflictOnUpdate, ClientSideID: "old", ServerVersion: 3}
+	f.syncState.EXPECT().SavePendingConflicts(ctx, int64(1), conflict).Return(nil)
+	f.syncState.EXPECT().GetPendingConflicts(ctx, int64(1)).Return([]models.VersionConflict{earlier, conflict}, nil)
+
+	err := svc.ExecutePlan(ctx, plan, 1)
+	require.ErrorIs(t, err, ErrSyncConflicts)
+	conflicts, ok := PendingConflictsOf(err)
+	require.True(t, ok)
+	assert.Equal(t, []models.VersionConflict{earlier, conflict}, conflicts, "conflicts left by earlier syncs are reported too")
+}
+
+// TestClientSyncService_FullSync_KeepsPendingConflict runs FullSync twice
+// over a real local store: the second sync must neither download the
+// server's version of an unresolved conflict nor push the local one.
+func TestClientSyncService_FullSync_KeepsPendingConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc, mockAdapter := newTestSyncSvcWithBackups(t, ctrl, t.TempDir(), 1)
+	svc.planner = NewSyncService()
+	svc.conflicts = models.ConflictManual
+	ctx := context.Background()
+	userID := int64(1)
+
+	local := models.PrivateData{ClientSideID: "up1", ID: 7, UserID: userID, Version: 2, Hash: "local-edit"}
+	repo := svc.localStore.PrivateDataRepository
+	require.NoError(t, repo.SavePrivateData(ctx, userID, local))
+
+	// First sync: the local edit is rejected because another device got
+	// there first.
+	mockAdapter.EXPECT().GetServerStates(ctx, userID).Return([]models.PrivateDataState{
+		{ClientSideID: "up1", ID: 7, Version: 2, Hash: "before"},
+	}, nil)
+	mockAdapter.EXPECT().Update(ctx, gomock.Any()).Return(&adapter.ConflictError{VersionConflict: models.VersionConflict{ServerVersion: 5}})
+
+	_, ok := PendingConflictsOf(svc.FullSync(ctx, userID))
+	require.True(t, ok)
+
+	// Second sync: the server is ahead, which would normally download its
+	// version over the local edit. No Download or Update is expected.
+	mockAdapter.EXPECT().GetServerStates(ctx, userID).Return([]models.PrivateDataState{
+		{ClientSideID: "up1", ID: 7, Version: 5, Hash: "other-device"},
+	}, nil)
+
+	conflicts, ok := PendingConflictsOf(svc.FullSync(ctx, userID))
+	require.True(t, ok, "the conflict is still reported")
+	assert.Equal(t, []models.VersionConflict{{Operation: models.ConflictOnUpdate, ClientSideID: "up1", ServerVersion: 5}}, conflicts)
+
+	kept, err := repo.GetPrivateData(ctx, "up1", userID)
+	require.NoError(t, err)
+	assert.Equal(t, "local-edit", kept.Hash, "the local edit is not clobbered")
+	assert.Equal(t, int64(2), kept.Version)
+}
+
+func TestClientSyncService_LoadConflict(t *testing.T) {
+	ctx := context.Background()
+	conflict := models.VersionConflict{Operation: models.ConflictOnUpdate, ClientSideID: "up1"}
+
+	t.Run("both versions decrypted", func(t *testing.T) {
+		svc, f := newConflictTestSyncSvc(t, gomock.NewController(t))
+		f.repo.EXPECT().GetPrivateData(ctx, "up1", int64(1)).Return(f.local, nil)
+		f.adapter.EXPECT().Download(ctx, gomock.Any()).Return([]models.PrivateData{f.server}, nil)
+
+		versions, err := svc.LoadConflict(ctx, 1, conflict)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), versions.Conflict.ServerVersion)
+		assert.Equal(t, "local-secret", versions.Local.LoginData.Password)
+		assert.Equal(t, "server-secret", versions.Server.LoginData.Password)
+		assert.Equal(t, "up1", versions.Server.ClientSideID)
+	})
+
+	t.Run("deleted on the server", func(t *testing.T) {
+		svc, f := newConflictTestSyncSvc(t, gomock.NewController(t))
+		f.repo.EXPECT().GetPrivateData(ctx, "up1", int64(1)).Return(f.local, nil)
+		f.adapter.EXPECT().Download(ctx, gomock.Any()).Return(nil, nil)
+
+		_, err := svc.LoadConflict(ctx, 1, conflict)
+		assert.ErrorIs(t, err, ErrConflictItemGone)
+	})
+}
+
+func TestClientSyncService_ResolveConflict(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keep server", func(t *testing.T) {
+		svc, f := newConflictTestSyncSvc(t, gomock.NewController(t))
+		f.adapter.EXPECT().Download(ctx, gomock.Any()).Return([]models.PrivateData{f.server}, nil)
+		f.repo.EXPECT().SavePrivateData(ctx, int64(1), f.server).Return(nil)
+		f.syncState.EXPECT().DeletePendingConflict(ctx, int64(1), "up1").Return(nil)
+
+		require.NoError(t, svc.ResolveConflict(ctx, 1, "up1", models.ConflictKeepServer))
+	})
+
+	t.Run("keep local", func(t *testing.T) {
+		svc, f := newConflictTestSyncSvc(t, gomock.NewController(t))
+		f.repo.EXPECT().GetPrivateData(ctx, "up1", int64(1)).Return(f.local, nil)
+		f.adapter.EXPECT().Download(ctx, gomock.Any()).Return([]models.PrivateData{f.server}, nil)
+		f.adapter.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req models.UpdateRequest) error {
+			require.Len(t, req.PrivateDataUpdates, 1)
+			update := req.PrivateDataUpdates[0]
+			assert.Equal(t, int64(5), update.Version, "based on the server's current version")
+			assert.Equal(t, f.local.Hash, update.UpdatedRecordHash)
+			assert.Equal(t, f.local.Payload.Data, *update.FieldsUpdate.Data)
+			return nil
+		})
+		f.repo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, data models.PrivateData) error {
+			assert.Equal(t, int64(6), data.Version)
+			assert.Equal(t, "local-secret", f.password(t, data))
+			return nil
+		})
+		f.syncState.EXPECT().DeletePendingConflict(ctx, int64(1), "up1").Return(nil)
+
+		require.NoError(t, svc.ResolveConflict(ctx, 1, "up1", models.ConflictKeepLocal))
+	})
+
+	t.Run("keep local rejected again", func(t *testing.T) {
+		svc, f := newConflictTestSyncSvc(t, gomock.NewController(t))
+		f.repo.EXPECT().GetPrivateData(ctx, "up1", int64(1)).Return(f.local, nil)
+		f.adapter.EXPECT().Download(ctx, gomock.Any()).Return([]models.PrivateData{f.server}, nil)
+		f.adapter.EXPECT().Update(ctx, gomock.Any()).Return(adapter.ErrConflict)
+
+		err := svc.ResolveConflict(ctx, 1, "up1", models.ConflictKeepLocal)
+		assert.ErrorIs(t, err, adapter.ErrConflict)
+	})
+
+	t.Run("keep both", func(t *testing.T) {
+		svc, f := newConflictTestSyncSvc(t, gomock.NewController(t))
+		f.repo.EXPECT().GetPrivateData(ctx, "up1", int64(1)).Return(f.local, nil)
+		gomock.InOrder(
+			f.repo.EXPECT().SavePrivateData(ctx, int64(1), gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, data ...models.PrivateData) error {
+				require.Len(t, data, 1)
+				copied := data[0]
+				assert.NotEqual(t, "up1", copied.ClientSideID)
+				assert.Zero(t, copied.Version, "the copy is new to the server")
+				plain, err := f.crypto.DecryptPayload(copied.Payload)
+				require.NoError(t, err)
+				assert.Equal(t, "Банк"+conflictCopySuffix, plain.Metadata.Name)
+				assert.Equal(t, "local-secret", plain.LoginData.Password)
+				return nil
+			}),
+			f.adapter.EXPECT().Download(ctx, gomock.Any()).Return([]models.PrivateData{f.server}, nil),
+			f.repo.EXPECT().SavePrivateData(ctx, int64(1), f.server).Return(nil),
+			f.syncState.EXPECT().DeletePendingConflict(ctx, int64(1), "up1").Return(nil),
+		)
+
+		require.NoError(t, svc.ResolveConflict(ctx, 1, "up1", models.ConflictKeepBoth))
+	})
+
+	t.Run("unknown choice", func(t *testing.T) {
+		svc, _ := newConflictTestSyncSvc(t, gomock.NewController(t))
+
+		err := svc.ResolveConflict(ctx, 1, "up1", "merge")
+		assert.ErrorIs(t, err, ErrUnknownConflictChoice)
+	})
+}
@@ -4,7 +4,10 @@
 package service
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"io"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/crypto"
 	"github.com/MKhiriev/go-pass-keeper/internal/utils"
@@ -29,6 +32,12 @@ func (c *clientCryptoService) SetEncryptionKey(key []byte) {
 	c.key = key
 }
 
+// HasEncryptionKey implements ClientCryptoService. It reports whether a
+// non-empty DEK has been set.
+func (c *clientCryptoService) HasEncryptionKey() bool {
+	return len(c.key) > 0
+}
+
 // dataPayload bundles all typed data fields into a single value before encryption.
 // Only one field will be non-nil depending on the DataType.
 type dataPayload struct {
@@ -41,9 +50,15 @@ type dataPayload struct {
 
 // EncryptPayload implements ClientCryptoService. It encrypts metadata, the typed
 // data bundle, and the optional notes and additional fields independently using the
-// stored DEK. The DataType field is left unencrypted. Returns an error if any field
-// encryption fails.
+// stored DEK. The embedded content of a Binary entry is first sealed in chunks
+// with EncryptBinary, so the bundle carries it only as chunk ciphertext. The
+// DataType field is left unencrypted. Returns [ErrKeyNotAvailable] if no DEK is
+// set, or an error if any field encryption fails.
 func (c *clientCryptoService) EncryptPayload(plain models.DecipheredPayload) (models.PrivateDataPayload, error) {
+	if !c.HasEncryptionKey() {
+		return models.PrivateDataPayload{}, ErrKeyNotAvailable
+	}
+
 	// --- Metadata ---
 	encMeta, err := c.crypto.EncryptData(plain.Metadata, c.key)
 	if err != nil {
@@ -51,11 +66,15 @@ func (c *clientCryptoService) EncryptPayload(plain models.DecipheredPayload) (mo
 	}
 
 	// --- Data: bundle all typed fields into one struct, then encrypt ---
+	binaryData, err := c.sealBinary(plain.BinaryData)
+	if err != nil {
+		return models.PrivateDataPayload{}, fmt.Errorf("encrypt binary content: %w", err)
+	}
 	dp := dataPayload{
 		LoginData:    plain.LoginData,
 		LoginURI:     plain.LoginURI,
 		TextData:     plain.TextData,
-		BinaryData:   plain.BinaryData,
+		BinaryData:   binaryData,
 		BankCardData: plain.BankCardData,
 	}
 	encData, err := c.crypto.EncryptData(dp, c.key)
@@ -90,21 +109,55 @@ func (c *clientCryptoService) EncryptPayload(plain models.DecipheredPayload) (mo
 	return out, nil
 }
 
+// DecryptMetadata implements ClientCryptoService. Only the metadata is
+// decrypted; the Type field is copied as-is. Returns [ErrKeyNotAvailable] if
+// no DEK is set.
+func (c *clientCryptoService) DecryptMetadata(enc models.PrivateDataPayload) (models.DecipheredPayload, error) {
+	if !c.HasEncryptionKey() {
+		return models.DecipheredPayload{}, ErrKeyNotAvailable
+	}
+
+	var meta models.Metadata
+	if err := c.crypto.DecryptData(string(enc.Metadata), c.key, &meta); err != nil {
+		return models.DecipheredPayload{}, fmt.Errorf("decrypt metadata: %w", err)
+	}
+
+	return models.DecipheredPayload{Metadata: meta, Type: enc.Type}, nil
+}
+
 // DecryptPayload implements ClientCryptoService. It decrypts metadata, the typed
 // data bundle, and the optional notes and additional fields using the stored DEK.
-// The DataType field is copied as-is (it is never encrypted). Returns an error if
-// any field decryption fails.
+// The DataType field is copied as-is (it is never encrypted). Returns
+// [ErrKeyNotAvailable] if no DEK is set, or an error if any field decryption fails.
 func (c *clientCryptoService) DecryptPayload(enc models.PrivateDataPayload) (models.DecipheredPayload, error) {
+	plain, _, err := c.DecryptPayloadOutdated(enc)
+	return plain, err
+}
+
+// DecryptPayloadOutdated implements ClientCryptoService. It is DecryptPayload
+// that also compares the format version of every field with
+// [crypto.CurrentFormatVersion]. Binary content embedded before it was sealed
+// in chunks counts as outdated too.
+func (c *clientCryptoService) DecryptPayloadOutdated(enc models.PrivateDataPayload) (models.DecipheredPayload, bool, error) {
+	if !c.HasEncryptionKey() {
+		return models.DecipheredPayload{}, false, ErrKeyNotAvailable
+	}
+	outdated := false
+
 	// --- Metadata ---
 	var meta models.Metadata
-	if err := c.crypto.DecryptData(string(enc.Metadata), c.key, &meta); err != nil {
-		return models.DecipheredPayload{}, fmt.Errorf("decrypt metadata: %w", err)
+	if err := c.decryptField(string(enc.Metadata), &meta, &outdated); err != nil {
+		return models.DecipheredPayload{}, false, fmt.Errorf("decrypt metadata: %w", err)
 	}
 
 	// --- Data ---
 	var dp dataPayload
-	if err := c.crypto.DecryptData(string(enc.Data), c.key, &dp); err != nil {
-		return models.DecipheredPayload{}, fmt.Errorf("decrypt data: %w", err)
+	if err := c.decryptField(string(enc.Data), &dp, &outdated); err != nil {
+		return models.DecipheredPayload{}, false, fmt.Errorf("decrypt data: %w", err)
+	}
+	binaryData, err := c.openBinary(dp.BinaryData, &outdated)
+	if err != nil {
+		return models.DecipheredPayload{}, false, fmt.Errorf("decrypt binary content: %w", err)
 	}
 
 	out := models.DecipheredPayload{
@@ -113,15 +166,15 @@ func (c *clientCryptoService) DecryptPayload(enc models.PrivateDataPayload) (mod
 		LoginData:    dp.LoginData,
 		LoginURI:     dp.LoginURI,
 		TextData:     dp.TextData,
-		BinaryData:   dp.BinaryData,
+		BinaryData:   binaryData,
 		BankCardData: dp.BankCardData,
 	}
 
 	// --- Notes (optional) ---
 	if enc.Notes != nil {
 		var notes models.Notes
-		if err := c.crypto.DecryptData(string(*enc.Notes), c.key, &notes); err != nil {
-			return models.DecipheredPayload{}, fmt.Errorf("decrypt notes: %w", err)
+		if err := c.decryptField(string(*enc.Notes), &notes, &outdated); err != nil {
+			return models.DecipheredPayload{}, false, fmt.Errorf("decrypt notes: %w", err)
 		}
 		out.Notes = &notes
 	}
@@ -129,13 +182,113 @@ func (c *clientCryptoService) DecryptPayload(enc models.PrivateDataPayload) (mod
 	// --- AdditionalFields (optional) ---
 	if enc.AdditionalFields != nil {
 		var fields []models.CustomField
-		if err := c.crypto.DecryptData(string(*enc.AdditionalFields), c.key, &fields); err != nil {
-			return models.DecipheredPayload{}, fmt.Errorf("decrypt additional fields: %w", err)
+		if err := c.decryptField(string(*enc.AdditionalFields), &fields, &outdated); err != nil {
+			return models.DecipheredPayload{}, false, fmt.Errorf("decrypt additional fields: %w", err)
 		}
 		out.AdditionalFields = &fields
 	}
 
-	return out, nil
+	return out, outdated, nil
+}
+
+// decryptField decrypts one ciphered field into target and sets *outdated
+// when the field was written in a format older than the current one.
+func (c *clientCryptoService) decryptField(enc string, target any, outdated *bool) error {
+	version, err := c.crypto.DecryptDataVersion(enc, c.key, target)
+	if err != nil {
+		return err
+	}
+	if version < crypto.CurrentFormatVersion {
+		*outdated = true
+	}
+	return nil
+}
+
+// EncryptBinary implements ClientCryptoService. It encrypts src chunk by chunk
+// with the stored DEK using crypto.DefaultChunkSize and fills meta.Size,
+// meta.ChunkSize, meta.ChunkCount and meta.NoncePrefix from the result.
+// Returns [ErrKeyNotAvailable] if no DEK is set.
+func (c *clientCryptoService) EncryptBinary(dst io.Writer, src io.Reader, meta *models.BinaryData) error {
+	if !c.HasEncryptionKey() {
+		return ErrKeyNotAvailable
+	}
+
+	info, err := c.crypto.EncryptStream(dst, src, c.key, crypto.DefaultChunkSize)
+	if err != nil {
+		return fmt.Errorf("encrypt binary: %w", err)
+	}
+
+	meta.Size = info.Size
+	meta.ChunkSize = info.ChunkSize
+	meta.ChunkCount = info.ChunkCount
+	meta.NoncePrefix = base64.StdEncoding.EncodeToString(info.NoncePrefix)
+	return nil
+}
+
+// DecryptBinary implements ClientCryptoService. It decrypts content produced by
+// EncryptBinary with the stored DEK, verifying chunk order and the chunk count
+// recorded in meta. Returns [ErrKeyNotAvailable] if no DEK is set.
+func (c *clientCryptoService) DecryptBinary(dst io.Writer, src io.Reader, meta models.BinaryData) error {
+	if !c.HasEncryptionKey() {
+		return ErrKeyNotAvailable
+	}
+
+	prefix, err := base64.StdEncoding.DecodeString(meta.NoncePrefix)
+	if err != nil {
+		return fmt.Errorf("decrypt binary: decode nonce prefix: %w", err)
+	}
+
+	info := crypto.StreamInfo{
+		ChunkSize:   meta.ChunkSize,
+		ChunkCount:  meta.ChunkCount,
+		NoncePrefix: prefix,
+		Size:        meta.Size,
+	}
+	if err := c.crypto.DecryptStream(dst, src, c.key, info); err != nil {
+		return fmt.Errorf("decrypt binary: %w", err)
+	}
+	return nil
+}
+
+// sealBinary returns a copy of data whose embedded content is replaced by its
+// chunk ciphertext from EncryptBinary, with the chunk metadata filled in.
+// data without content is returned as is.
+func (c *clientCryptoService) sealBinary(data *models.BinaryData) (*models.BinaryData, error) {
+	if data == nil || len(data.Content) == 0 {
+		return data, nil
+	}
+
+	sealed := *data
+	var buf bytes.Buffer
+	if err := c.EncryptBinary(&buf, bytes.NewReader(data.Content), &sealed); err != nil {
+		return nil, err
+	}
+	sealed.Content = buf.Bytes()
+	return &sealed, nil
+}
+
+// openBinary reverses sealBinary: it returns a copy of data with the
+// plaintext content and the chunk metadata cleared, so that the entry can be
+// encrypted again as is. Content stored before it was sealed in chunks is
+// returned unchanged and sets *outdated.
+func (c *clientCryptoService) openBinary(data *models.BinaryData, outdated *bool) (*models.BinaryData, error) {
+	if data == nil || len(data.Content) == 0 {
+		return data, nil
+	}
+	if data.ChunkSize == 0 {
+		*outdated = true
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(data.Content))
+	if err := c.DecryptBinary(&buf, bytes.NewReader(data.Content), *data); err != nil {
+		return nil, err
+	}
+	opened := *data
+	opened.Content = buf.Bytes()
+	opened.ChunkSize, opened.ChunkCount, opened.NoncePrefix = 0, 0, ""
+	return &opened, nil
 }
 
 // ComputeHash implements ClientCryptoService. It serialises payload to JSON and
@@ -4,6 +4,8 @@
 package service_test
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"testing"
 
@@ -96,7 +98,7 @@ func TestClientCryptoService_EncryptDecrypt_AllTypes(t *testing.T) {
 				Type:     models.Text,
 				Metadata: models.Metadata{Name: "My Note"},
 				TextData: &models.TextData{Text: "секретная заметка"},
-				Notes:    &models.Notes{Notes: "доп. заметка", IsEncrypted: false},
+				Notes:    &models.Notes{Notes: "доп. заметка"},
 			},
 		},
 		{
@@ -152,7 +154,10 @@ func TestClientCryptoService_EncryptDecrypt_WithOptionalFields(t *testing.T) {
 	svc, _ := newRealCryptoSvc(t)
 
 	notes := models.Notes{Notes: "важная заметка"}
-	fields := []models.CustomField{{Type: models.Text, Data: "1234"}}
+	fields := []models.CustomField{
+		{Name: "PIN", Type: models.Text, Data: "1234", IsSensitive: true},
+		{Name: "Сайт", Type: models.Text, Data: "example.com"},
+	}
 
 	plain := models.DecipheredPayload{
 		UserID:           1,
@@ -177,6 +182,122 @@ func TestClientCryptoService_EncryptDecrypt_WithOptionalFields(t *testing.T) {
 	assert.Equal(t, fields, *got.AdditionalFields)
 }
 
+func TestClientCryptoService_Notes_AlwaysEncrypted(t *testing.T) {
+	svc, dek := newRealCryptoSvc(t)
+	keyChain := crypto.NewKeyChainService()
+
+	tests := []struct {
+		name  string
+		notes func(t *testing.T) models.CipheredNotes
+		want  string
+	}{
+		{
+			name: "current client",
+			notes: func(t *testing.T) models.CipheredNotes {
+				enc, err := svc.EncryptPayload(models.DecipheredPayload{
+					Type:     models.Text,
+					Metadata: models.Metadata{Name: "Note"},
+					Notes:    &models.Notes{Notes: "код домофона 1234"},
+				})
+				require.NoError(t, err)
+				require.NotNil(t, enc.Notes)
+				return *enc.Notes
+			},
+			want: "код домофона 1234",
+		},
+		{
+			name: "legacy blob flagged encrypted",
+			notes: func(t *testing.T) models.CipheredNotes {
+				blob, err := keyChain.EncryptData(map[string]any{"IsEncrypted": true, "Notes": "старая заметка"}, dek)
+				require.NoError(t, err)
+				return models.CipheredNotes(blob)
+			},
+			want: "старая заметка",
+		},
+		{
+			name: "legacy blob flagged plaintext",
+			notes: func(t *testing.T) models.CipheredNotes {
+				blob, err := keyChain.EncryptData(map[string]any{"IsEncrypted": false, "Notes": "открытая заметка"}, dek)
+				require.NoError(t, err)
+				return models.CipheredNotes(blob)
+			},
+			want: "открытая заметка",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes := tt.notes(t)
+			assert.NotContains(t, string(notes), tt.want)
+
+			enc, err := svc.EncryptPayload(models.DecipheredPayload{Type: models.Text, Metadata: models.Metadata{Name: "Note"}})
+			require.NoError(t, err)
+			enc.Notes = &notes
+
+			got, err := svc.DecryptPayload(enc)
+			require.NoError(t, err)
+			require.NotNil(t, got.Notes)
+			assert.Equal(t, models.Notes{Notes: tt.want}, *got.Notes)
+		})
+	}
+}
+
+func TestClientCryptoService_DecryptMetadata(t *testing.T) {
+	svc, _ := newRealCryptoSvc(t)
+
+	folder := "Работа"
+	enc, err := svc.EncryptPayload(models.DecipheredPayload{
+		Type:      models.LoginPassword,
+		Metadata:  models.Metadata{Name: "Почта", Folder: &folder},
+		LoginData: &models.LoginData{Username: "user", Password: "secret"},
+		Notes:     &models.Notes{Notes: "заметка"},
+	})
+	require.NoError(t, err)
+
+	got, err := svc.DecryptMetadata(enc)
+	require.NoError(t, err)
+	assert.Equal(t, models.DecipheredPayload{
+		Type:     models.LoginPassword,
+		Metadata: models.Metadata{Name: "Почта", Folder: &folder},
+	}, got)
+
+	// Corrupt data does not matter as long as the metadata decrypts.
+	enc.Data = "corrupt"
+	_, err = svc.DecryptMetadata(enc)
+	require.NoError(t, err)
+
+	enc.Metadata = "corrupt"
+	_, err = svc.DecryptMetadata(enc)
+	require.Error(t, err)
+
+	_, err = service.NewClientCryptoService(crypto.NewKeyChainService()).DecryptMetadata(enc)
+	require.ErrorIs(t, err, service.ErrKeyNotAvailable)
+}
+
+func TestClientCryptoService_EncryptDecrypt_MultipleURIs(t *testing.T) {
+	svc, _ := newRealCryptoSvc(t)
+
+	uris := []models.LoginURI{
+		{URI: "https://mail.example", Match: models.URIMatchDomain},
+		{URI: "https://login.example/auth", Match: models.URIMatchStartsWith},
+		{URI: "androidapp://com.example", Match: models.URIMatchExact},
+	}
+	plain := models.DecipheredPayload{
+		UserID:    1,
+		Type:      models.LoginPassword,
+		Metadata:  models.Metadata{Name: "Mail"},
+		LoginData: &models.LoginData{Username: "user", Password: "secret", URIs: uris},
+	}
+
+	enc, err := svc.EncryptPayload(plain)
+	require.NoError(t, err)
+
+	got, err := svc.DecryptPayload(enc)
+	require.NoError(t, err)
+	require.NotNil(t, got.LoginData)
+	assert.Equal(t, uris, got.LoginData.URIs)
+}
+
 func TestClientCryptoService_EncryptDecrypt_NilOptionalFields(t *testing.T) {
 	svc, _ := newRealCryptoSvc(t)
 
@@ -260,6 +381,115 @@ func TestClientCryptoService_ComputeHash_DifferentForDifferentData(t *testing.T)
 	assert.NotEqual(t, h1, h2)
 }
 
+// --- EncryptBinary / DecryptBinary ---
+
+func TestClientCryptoService_EncryptDecryptBinary_MultiChunk(t *testing.T) {
+	svc, _ := newRealCryptoSvc(t)
+
+	plain := bytes.Repeat([]byte("0123456789abcdef"), 3*crypto.DefaultChunkSize/16+7)
+	meta := models.BinaryData{FileName: "big.bin"}
+
+	var sealed bytes.Buffer
+	require.NoError(t, svc.EncryptBinary(&sealed, bytes.NewReader(plain), &meta))
+
+	assert.Equal(t, int64(len(plain)), meta.Size)
+	assert.Equal(t, crypto.DefaultChunkSize, meta.ChunkSize)
+	assert.Equal(t, 4, meta.ChunkCount)
+	assert.NotEmpty(t, meta.NoncePrefix)
+	assert.False(t, bytes.Contains(sealed.Bytes(), plain[:64]))
+
+	var out bytes.Buffer
+	require.NoError(t, svc.DecryptBinary(&out, bytes.NewReader(sealed.Bytes()), meta))
+	assert.Equal(t, plain, out.Bytes())
+}
+
+func TestClientCryptoService_DecryptBinary_RejectsTruncation(t *testing.T) {
+	svc, _ := newRealCryptoSvc(t)
+
+	plain := bytes.Repeat([]byte{0x42}, 2*crypto.DefaultChunkSize+1)
+	var meta models.BinaryData
+
+	var sealed bytes.Buffer
+	require.NoError(t, svc.EncryptBinary(&sealed, bytes.NewReader(plain), &meta))
+
+	truncated := sealed.Bytes()[:sealed.Len()-17]
+	err := svc.DecryptBinary(io.Discard, bytes.NewReader(truncated), meta)
+	require.ErrorIs(t, err, crypto.ErrStreamTruncated)
+}
+
+func TestClientCryptoService_DecryptBinary_InvalidNoncePrefix(t *testing.T) {
+	svc, _ := newRealCryptoSvc(t)
+
+	err := svc.DecryptBinary(io.Discard, bytes.NewReader(nil), models.BinaryData{ChunkSize: 1, NoncePrefix: "%%%"})
+	require.Error(t, err)
+}
+
+// binaryBundle is the part of the encrypted data bundle that carries the
+// Binary fields.
+type binaryBundle struct {
+	BinaryData *models.BinaryData `json:"binary_data,omitempty"`
+}
+
+func TestClientCryptoService_EncryptPayload_SealsBinaryInChunks(t *testing.T) {
+	svc, dek := newRealCryptoSvc(t)
+	keyChain := crypto.NewKeyChainService()
+
+	content := bytes.Repeat([]byte("0123456789abcdef"), 2*crypto.DefaultChunkSize/16+1)
+	plain := models.DecipheredPayload{
+		Type:       models.Binary,
+		Metadata:   models.Metadata{Name: "backup"},
+		BinaryData: &models.BinaryData{FileName: "backup.tar", Size: int64(len(content)), Content: content},
+	}
+
+	enc, err := svc.EncryptPayload(plain)
+	require.NoError(t, err)
+
+	var bundle binaryBundle
+	require.NoError(t, keyChain.DecryptData(string(enc.Data), dek, &bundle))
+	require.NotNil(t, bundle.BinaryData)
+	assert.Equal(t, crypto.DefaultChunkSize, bundle.BinaryData.ChunkSize)
+	assert.Equal(t, 3, bundle.BinaryData.ChunkCount)
+	assert.NotEmpty(t, bundle.BinaryData.NoncePrefix)
+	assert.False(t, bytes.Contains(bundle.BinaryData.Content, content[:64]), "the content is sealed in chunks")
+
+	got, outdated, err := svc.DecryptPayloadOutdated(enc)
+	require.NoError(t, err)
+	assert.False(t, outdated)
+	assert.Equal(t, plain.BinaryData, got.BinaryData)
+
+	// Swapping two sealed chunks inside the bundle is detected.
+	sealed := bundle.BinaryData.Content
+	chunk := crypto.DefaultChunkSize + 16
+	reordered := append(append(append([]byte(nil), sealed[chunk:2*chunk]...), sealed[:chunk]...), sealed[2*chunk:]...)
+	bundle.BinaryData.Content = reordered
+	data, err := keyChain.EncryptData(bundle, dek)
+	require.NoError(t, err)
+	enc.Data = models.CipheredData(data)
+
+	_, err = svc.DecryptPayload(enc)
+	require.ErrorIs(t, err, crypto.ErrStreamChunkAuth)
+}
+
+func TestClientCryptoService_DecryptPayload_LegacyEmbeddedBinary(t *testing.T) {
+	svc, dek := newRealCryptoSvc(t)
+	keyChain := crypto.NewKeyChainService()
+
+	binary := &models.BinaryData{FileName: "id_ed25519", Size: 8, Content: []byte("key-body")}
+	meta, err := keyChain.EncryptData(models.Metadata{Name: "SSH"}, dek)
+	require.NoError(t, err)
+	data, err := keyChain.EncryptData(binaryBundle{BinaryData: binary}, dek)
+	require.NoError(t, err)
+
+	got, outdated, err := svc.DecryptPayloadOutdated(models.PrivateDataPayload{
+		Metadata: models.CipheredMetadata(meta),
+		Type:     models.Binary,
+		Data:     models.CipheredData(data),
+	})
+	require.NoError(t, err)
+	assert.True(t, outdated, "content sealed with the bundle only is re-encrypted into chunks")
+	assert.Equal(t, binary, got.BinaryData)
+}
+
 // --- SetEncryptionKey ---
 
 func TestClientCryptoService_SetEncryptionKey_ChangesKey(t *testing.T) {
@@ -278,3 +508,72 @@ func TestClientCryptoService_SetEncryptionKey_ChangesKey(t *testing.T) {
 	_, err = svc.DecryptPayload(enc)
 	require.Error(t, err) // Новый ключ — расшифровка должна упасть
 }
+
+// --- HasEncryptionKey ---
+
+func TestClientCryptoService_HasEncryptionKey(t *testing.T) {
+	svc := service.NewClientCryptoService(crypto.NewKeyChainService())
+	assert.False(t, svc.HasEncryptionKey())
+
+	dek, err := crypto.NewKeyChainService().GenerateDEK()
+	require.NoError(t, err)
+	svc.SetEncryptionKey(dek)
+	assert.True(t, svc.HasEncryptionKey())
+
+	svc.SetEncryptionKey(nil)
+	assert.False(t, svc.HasEncryptionKey())
+}
+
+func TestClientCryptoService_NoKey(t *testing.T) {
+	withKey, _ := newRealCryptoSvc(t)
+	enc, err := withKey.EncryptPayload(models.DecipheredPayload{
+		UserID:   1,
+		Metadata: models.Metadata{Name: "Test"},
+	})
+	require.NoError(t, err)
+
+	var sealed bytes.Buffer
+	var binMeta models.BinaryData
+	require.NoError(t, withKey.EncryptBinary(&sealed, bytes.NewReader([]byte("file")), &binMeta))
+
+	svc := service.NewClientCryptoService(crypto.NewKeyChainService())
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "EncryptPayload",
+			call: func() error {
+				_, err := svc.EncryptPayload(models.DecipheredPayload{Metadata: models.Metadata{Name: "Test"}})
+				return err
+			},
+		},
+		{
+			name: "DecryptPayload",
+			call: func() error {
+				_, err := svc.DecryptPayload(enc)
+				return err
+			},
+		},
+		{
+			name: "EncryptBinary",
+			call: func() error {
+				var meta models.BinaryData
+				return svc.EncryptBinary(io.Discard, bytes.NewReader([]byte("file")), &meta)
+			},
+		},
+		{
+			name: "DecryptBinary",
+			call: func() error {
+				return svc.DecryptBinary(io.Discard, bytes.NewReader(sealed.Bytes()), binMeta)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.call(), service.ErrKeyNotAvailable)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// duplicateCandidate is a decrypted vault item considered by [findDuplicates].
+type duplicateCandidate struct {
+	payload models.DecipheredPayload
+	version int64
+}
+
+// FindDuplicates implements ClientSyncService. It decrypts every non-deleted
+// local item of userID and groups the ones with identical content. Nothing is
+// modified; merging is left to [clientSyncService.MergeDuplicates] once the
+// user has confirmed it.
+func (s *clientSyncService) FindDuplicates(ctx context.Context, userID int64) ([]models.DuplicateGroup, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("find duplicates: invalid user id")
+	}
+
+	items, err := s.localStore.PrivateDataRepository.GetAllPrivateData(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get local items for duplicate search: %w", err)
+	}
+
+	candidates := make([]duplicateCandidate, 0, len(items))
+	for _, item := range items {
+		if item.Deleted {
+			continue
+		}
+		payload, err := s.crypto.DecryptPayload(item.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt item %s for duplicate search: %w", item.ClientSideID, err)
+		}
+		payload.ClientSideID = item.ClientSideID
+		candidates = append(candidates, duplicateCandidate{payload: payload, version: item.Version})
+	}
+
+	return findDuplicates(candidates)
+}
+
+// MergeDuplicates implements ClientSyncService. It soft-deletes every entry in
+// group.Duplicates locally and on the server, keeping group.Keep untouched.
+func (s *clientSyncService) MergeDuplicates(ctx context.Context, userID int64, group models.DuplicateGroup) error {
+	if userID <= 0 {
+		return fmt.Errorf("merge duplicates: invalid user id")
+	}
+	if group.Keep == "" || len(group.Duplicates) == 0 || slices.Contains(group.Duplicates, group.Keep) {
+		return fmt.Errorf("merge duplicates: invalid duplicate group")
+	}
+
+	for _, clientSideID := range group.Duplicates {
+		if err := s.localStore.PrivateDataRepository.DeletePrivateData(ctx, clientSideID, userID); err != nil {
+			return fmt.Errorf("soft delete duplicate %s: %w", clientSideID, err)
+		}
+		if err := s.deleteFromServer(ctx, clientSideID, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findDuplicates groups candidates whose decrypted content is identical. Within
+// a group the entry with the highest version is kept; ties are broken by the
+// smaller ClientSideID so the choice is stable across devices. Groups are
+// ordered by name.
+func findDuplicates(candidates []duplicateCandidate) ([]models.DuplicateGroup, error) {
+	byFingerprint := make(map[string][]duplicateCandidate)
+	for _, c := range candidates {
+		fp, err := duplicateFingerprint(c.payload)
+		if err != nil {
+			return nil, fmt.Errorf("fingerprint item %s: %w", c.payload.ClientSideID, err)
+		}
+		byFingerprint[fp] = append(byFingerprint[fp], c)
+	}
+
+	groups := make([]models.DuplicateGroup, 0)
+	for _, members := range byFingerprint {
+		if len(members) < 2 {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].version != members[j].version {
+				return members[i].version > members[j].version
+			}
+			return members[i].payload.ClientSideID < members[j].payload.ClientSideID
+		})
+
+		group := models.DuplicateGroup{
+			Name: members[0].payload.Metadata.Name,
+			Keep: members[0].payload.ClientSideID,
+		}
+		for _, m := range members[1:] {
+			group.Duplicates = append(group.Duplicates, m.payload.ClientSideID)
+		}
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Name != groups[j].Name {
+			return groups[i].Name < groups[j].Name
+		}
+		return groups[i].Keep < groups[j].Keep
+	})
+
+	return groups, nil
+}
+
+// duplicateFingerprint returns a digest of everything in p except the fields
+// that identify a particular copy (ClientSideID and UserID). Surrounding
+// whitespace in the name and folder is ignored, and an empty folder equals no
+// folder.
+func duplicateFingerprint(p models.DecipheredPayload) (string, error) {
+	p.ClientSideID = ""
+	p.UserID = 0
+	p.Metadata.Name = strings.TrimSpace(p.Metadata.Name)
+	if p.Metadata.Folder != nil {
+		folder := strings.TrimSpace(*p.Metadata.Folder)
+		p.Metadata.Folder = nil
+		if folder != "" {
+			p.Metadata.Folder = &folder
+		}
+	}
+
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
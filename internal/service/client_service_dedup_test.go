@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/mock"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func loginCandidate(id string, version int64, name, login, password string) duplicateCandidate {
+	return duplicateCandidate{
+		version: version,
+		payload: models.DecipheredPayload{
+			ClientSideID: id,
+			UserID:       1,
+			Metadata:     models.Metadata{Name: name},
+			Type:         models.LoginPassword,
+			LoginData:    &models.LoginData{Username: login, Password: password},
+		},
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	folder := "work"
+	blank := "  "
+	withFolder := func(c duplicateCandidate, f *string) duplicateCandidate {
+		c.payload.Metadata.Folder = f
+		return c
+	}
+	withUser := func(c duplicateCandidate, userID int64) duplicateCandidate {
+		c.payload.UserID = userID
+		return c
+	}
+
+	tests := []struct {
+		name       string
+		candidates []duplicateCandidate
+		want       []models.DuplicateGroup
+	}{
+		{
+			name:       "no items",
+			candidates: nil,
+			want:       []models.DuplicateGroup{},
+		},
+		{
+			name: "distinct content is not a duplicate",
+			candidates: []duplicateCandidate{
+				loginCandidate("a", 1, "mail", "bob", "p1"),
+				loginCandidate("b", 1, "mail", "bob", "p2"),
+			},
+			want: []models.DuplicateGroup{},
+		},
+		{
+			name: "highest version is kept",
+			candidates: []duplicateCandidate{
+				loginCandidate("a", 1, "mail", "bob", "p1"),
+				loginCandidate("b", 3, "mail", "bob", "p1"),
+				loginCandidate("c", 2, "mail", "bob", "p1"),
+			},
+			want: []models.DuplicateGroup{
+				{Name: "mail", Keep: "b", Duplicates: []string{"c", "a"}},
+			},
+		},
+		{
+			name: "equal versions keep the smaller client side id",
+			candidates: []duplicateCandidate{
+				loginCandidate("z", 1, "mail", "bob", "p1"),
+				loginCandidate("m", 1, "mail", "bob", "p1"),
+			},
+			want: []models.DuplicateGroup{
+				{Name: "mail", Keep: "m", Duplicates: []string{"z"}},
+			},
+		},
+		{
+			name: "identity fields and whitespace are ignored",
+			candidates: []duplicateCandidate{
+				withUser(loginCandidate("a", 1, "mail", "bob", "p1"), 1),
+				withUser(withFolder(loginCandidate("b", 1, "  mail ", "bob", "p1"), &blank), 2),
+			},
+			want: []models.DuplicateGroup{
+				{Name: "mail", Keep: "a", Duplicates: []string{"b"}},
+			},
+		},
+		{
+			name: "different folder is not a duplicate",
+			candidates: []duplicateCandidate{
+				loginCandidate("a", 1, "mail", "bob", "p1"),
+				withFolder(loginCandidate("b", 1, "mail", "bob", "p1"), &folder),
+			},
+			want: []models.DuplicateGroup{},
+		},
+		{
+			name: "groups are ordered by name",
+			candidates: []duplicateCandidate{
+				loginCandidate("a", 1, "zeta", "bob", "p1"),
+				loginCandidate("b", 1, "zeta", "bob", "p1"),
+				loginCandidate("c", 1, "alpha", "bob", "p1"),
+				loginCandidate("d", 1, "alpha", "bob", "p1"),
+			},
+			want: []models.DuplicateGroup{
+				{Name: "alpha", Keep: "c", Duplicates: []string{"d"}},
+				{Name: "zeta", Keep: "a", Duplicates: []string{"b"}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := findDuplicates(tc.candidates)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestClientSyncService_FindDuplicates_SkipsDeletedItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, _, _ := newTestSyncSvc(t, ctrl)
+	mockCrypto := mock.NewMockClientCryptoService(ctrl)
+	svc.crypto = mockCrypto
+	ctx := context.Background()
+
+	enc := models.PrivateDataPayload{Metadata: models.CipheredMetadata("enc")}
+	plain := models.DecipheredPayload{Metadata: models.Metadata{Name: "mail"}, Type: models.Text}
+
+	mockRepo.EXPECT().GetAllPrivateData(ctx, int64(1)).Return([]models.PrivateData{
+		{ClientSideID: "a", Version: 2, Payload: enc},
+		{ClientSideID: "b", Version: 1, Payload: enc},
+		{ClientSideID: "c", Version: 5, Payload: enc, Deleted: true},
+	}, nil)
+	mockCrypto.EXPECT().DecryptPayload(enc).Return(plain, nil).Times(2)
+
+	groups, err := svc.FindDuplicates(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []models.DuplicateGroup{{Name: "mail", Keep: "a", Duplicates: []string{"b"}}}, groups)
+}
+
+func TestClientSyncService_MergeDuplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+
+	gomock.InOrder(
+		mockRepo.EXPECT().DeletePrivateData(ctx, "b", userID).Return(nil),
+		mockRepo.EXPECT().GetPrivateData(ctx, "b", userID).Return(models.PrivateData{ClientSideID: "b", Version: 4}, nil),
+		mockAdapter.EXPECT().Delete(ctx, models.DeleteRequest{UserID: userID, DeleteEntries: []models.DeleteEntry{{ClientSideID: "b", Version: 4}}, Length: 1}).Return(nil),
+	)
+
+	err := svc.MergeDuplicates(ctx, userID, models.DuplicateGroup{Keep: "a", Duplicates: []string{"b"}})
+	require.NoError(t, err)
+}
+
+func TestClientSyncService_MergeDuplicates_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, _, _ := newTestSyncSvc(t, ctrl)
+	ctx := context.Background()
+
+	require.Error(t, svc.MergeDuplicates(ctx, 0, models.DuplicateGroup{Keep: "a", Duplicates: []string{"b"}}))
+	require.Error(t, svc.MergeDuplicates(ctx, 1, models.DuplicateGroup{Keep: "a"}))
+	require.Error(t, svc.MergeDuplicates(ctx, 1, models.DuplicateGroup{Keep: "a", Duplicates: []string{"a"}}))
+
+	mockRepo.EXPECT().DeletePrivateData(ctx, "b", int64(1)).Return(errors.New("db error"))
+	err := svc.MergeDuplicates(ctx, 1, models.DuplicateGroup{Keep: "a", Duplicates: []string{"b"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "soft delete duplicate b")
+}
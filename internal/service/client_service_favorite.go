@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// SetFavorite implements ClientPrivateDataService. Only the metadata is
+// re-encrypted; the change then goes through the same local-first update
+// as an edit (see pushUpdate), so a server conflict is reported the same way
+// and an unreachable server leaves the change for the next sync.
+func (p *clientPrivateDataService) SetFavorite(ctx context.Context, userID int64, clientSideID string, favorite bool) error {
+	prev, err := p.localStore.PrivateDataRepository.GetPrivateData(ctx, clientSideID, userID)
+	if err != nil {
+		return fmt.Errorf("load local item %s for favorite: %w", clientSideID, err)
+	}
+
+	payload, changed, err := p.editMetadata(prev.Payload, func(meta *models.Metadata) bool {
+		if meta.Favorite == favorite {
+			return false
+		}
+		meta.Favorite = favorite
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("set favorite of item %s: %w", clientSideID, err)
+	}
+	if !changed {
+		return nil
+	}
+
+	return p.pushUpdate(ctx, prev, payload)
+}
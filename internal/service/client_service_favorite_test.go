@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/crypto"
+	"github.com/MKhiriev/go-pass-keeper/internal/mock"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClientPrivateDataService_SetFavorite(t *testing.T) {
+	ctx := context.Background()
+
+	keyChain := crypto.NewKeyChainService()
+	dek, err := keyChain.GenerateDEK()
+	require.NoError(t, err)
+	cryptoSvc := NewClientCryptoService(keyChain)
+	cryptoSvc.SetEncryptionKey(dek)
+
+	payload, err := cryptoSvc.EncryptPayload(models.DecipheredPayload{
+		Type:      models.LoginPassword,
+		Metadata:  models.Metadata{Name: "Банк"},
+		LoginData: &models.LoginData{Username: "user", Password: "secret"},
+	})
+	require.NoError(t, err)
+	stored := models.PrivateData{ClientSideID: "id1", UserID: 1, Version: 3, Payload: payload}
+
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockLocalPrivateDataRepository(ctrl)
+	serverAdapter := mock.NewMockServerAdapter(ctrl)
+	svc := NewClientPrivateDataService(&store.ClientStorages{PrivateDataRepository: repo}, serverAdapter, cryptoSvc, testMaxBinarySize, 0, "", clock.Real{})
+
+	repo.EXPECT().GetPrivateData(ctx, "id1", int64(1)).DoAndReturn(
+		func(context.Context, string, int64) (models.PrivateData, error) { return stored, nil },
+	).AnyTimes()
+	repo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, data models.PrivateData) error {
+		stored = data
+		return nil
+	}).Times(2)
+	var pushed []models.UpdateRequest
+	serverAdapter.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req models.UpdateRequest) error {
+		pushed = append(pushed, req)
+		return nil
+	}).Times(2)
+	repo.EXPECT().IncrementVersion(ctx, "id1", int64(1)).DoAndReturn(func(context.Context, string, int64) error {
+		stored.Version++
+		return nil
+	}).Times(2)
+
+	favorite := func() bool {
+		t.Helper()
+		plain, decErr := cryptoSvc.DecryptPayload(stored.Payload)
+		require.NoError(t, decErr)
+		assert.Equal(t, "secret", plain.LoginData.Password, "the secret survives the metadata update")
+		return plain.Metadata.Favorite
+	}
+
+	require.NoError(t, svc.SetFavorite(ctx, 1, "id1", true))
+	assert.True(t, favorite())
+	assert.Equal(t, payload.Data, stored.Payload.Data, "only the metadata is re-encrypted")
+
+	// Already a favorite: nothing is written or sent.
+	require.NoError(t, svc.SetFavorite(ctx, 1, "id1", true))
+
+	require.NoError(t, svc.SetFavorite(ctx, 1, "id1", false))
+	assert.False(t, favorite())
+
+	require.Len(t, pushed, 2)
+	assert.Equal(t, int64(3), pushed[0].PrivateDataUpdates[0].Version)
+	assert.Equal(t, int64(4), pushed[1].PrivateDataUpdates[0].Version)
+	assert.Equal(t, stored.Payload.Metadata, *pushed[1].PrivateDataUpdates[0].FieldsUpdate.Metadata)
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// MoveToFolder implements ClientPrivateDataService. Items whose local version
+// does not match the server are counted as conflicted and skipped up front,
+// because the server applies a multi-record update in one transaction and a
+// single stale version would reject the whole batch. Only the metadata of
+// the remaining items is re-encrypted and sent, in batches of at most the
+// configured max_batch_entries updates, the server's per-request cap. The local store is updated after the server accepts a batch, so a
+// rejected batch leaves both sides as they were.
+func (p *clientPrivateDataService) MoveToFolder(ctx context.Context, userID int64, clientSideIDs []string, folder string) (models.MoveResult, error) {
+	var result models.MoveResult
+	if len(clientSideIDs) == 0 {
+		return result, nil
+	}
+	folder = NormalizeFolder(folder)
+
+	states, err := p.adapter.GetServerStates(ctx, userID)
+	if err != nil {
+		return result, fmt.Errorf("get server states for move: %w", err)
+	}
+	serverVersions := make(map[string]int64, len(states))
+	for _, st := range states {
+		if !st.Deleted {
+			serverVersions[st.ClientSideID] = st.Version
+		}
+	}
+
+	moved := make([]models.PrivateData, 0, len(clientSideIDs))
+	updates := make([]models.PrivateDataUpdate, 0, len(clientSideIDs))
+	for _, id := range clientSideIDs {
+		prev, getErr := p.localStore.PrivateDataRepository.GetPrivateData(ctx, id, userID)
+		if getErr != nil {
+			return result, fmt.Errorf("load local item %s for move: %w", id, getErr)
+		}
+		if version, ok := serverVersions[id]; !ok || version != prev.Version {
+			result.Conflicted = append(result.Conflicted, id)
+			continue
+		}
+
+		updated, changed, moveErr := p.withFolder(prev, folder)
+		if moveErr != nil {
+			return result, fmt.Errorf("move item %s: %w", id, moveErr)
+		}
+		if !changed {
+			result.Moved = append(result.Moved, id)
+			continue
+		}
+
+		meta := updated.Payload.Metadata
+		fields := models.FieldsUpdate{Metadata: &meta}
+		if updated.Payload.Data != prev.Payload.Data {
+			// editMetadata migrated the whole payload to the current format.
+			body := updated.Payload.Data
+			fields.Data = &body
+			fields.Notes = updated.Payload.Notes
+			fields.AdditionalFields = updated.Payload.AdditionalFields
+		}
+		updates = append(updates, models.PrivateDataUpdate{
+			ClientSideID:      id,
+			Version:           prev.Version,
+			UpdatedRecordHash: updated.Hash,
+			FieldsUpdate:      fields,
+		})
+		moved = append(moved, updated)
+	}
+
+	for start := 0; start < len(moved); start += p.maxBatchEntries {
+		end := min(start+p.maxBatchEntries, len(moved))
+		if err = p.pushMoveBatch(ctx, userID, updates[start:end], moved[start:end], &result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// pushMoveBatch sends one batch of folder updates to the server and, once it
+// is accepted, applies items to the local store. A batch rejected with a
+// version conflict is counted as conflicted without failing the move.
+func (p *clientPrivateDataService) pushMoveBatch(ctx context.Context, userID int64, updates []models.PrivateDataUpdate, items []models.PrivateData, result *models.MoveResult) error {
+	req := models.UpdateRequest{UserID: userID, PrivateDataUpdates: updates, Length: len(updates)}
+	if err := p.adapter.Update(ctx, req); err != nil {
+		if errors.Is(err, adapter.ErrConflict) {
+			for _, item := range items {
+				result.Conflicted = append(result.Conflicted, item.ClientSideID)
+			}
+			return nil
+		}
+		return fmt.Errorf("move items on server: %w", err)
+	}
+
+	for _, item := range items {
+		if err := p.localStore.PrivateDataRepository.UpdatePrivateData(ctx, item); err != nil {
+			return fmt.Errorf("update local item %s: %w", item.ClientSideID, err)
+		}
+		if err := p.localStore.PrivateDataRepository.IncrementVersion(ctx, item.ClientSideID, userID); err != nil {
+			return fmt.Errorf("error incrementing version locally: %w", err)
+		}
+		result.Moved = append(result.Moved, item.ClientSideID)
+	}
+
+	return nil
+}
+
+// withFolder returns a copy of item with its metadata re-encrypted under the
+// new folder and its hash recomputed. The other ciphertext fields are kept
+// as they are unless editMetadata migrates them. An empty folder removes the item from its folder. changed is
+// false when the item is already in folder.
+func (p *clientPrivateDataService) withFolder(item models.PrivateData, folder string) (models.PrivateData, bool, error) {
+	payload, changed, err := p.editMetadata(item.Payload, func(meta *models.Metadata) bool {
+		current := ""
+		if meta.Folder != nil {
+			current = *meta.Folder
+		}
+		if current == folder {
+			return false
+		}
+		meta.Folder = nil
+		if folder != "" {
+			meta.Folder = &folder
+		}
+		return true
+	})
+	if err != nil || !changed {
+		return item, false, err
+	}
+
+	updated := item
+	updated.Payload = payload
+	if updated.Hash, err = p.crypto.ComputeHash(updated.Payload); err != nil {
+		return item, false, fmt.Errorf("compute hash: %w", err)
+	}
+	now := p.clock.Now().UTC()
+	updated.UpdatedAt = &now
+	return updated, true, nil
+}
+
+// editMetadata decrypts the metadata of payload, applies edit and returns
+// payload with the metadata re-encrypted. The other ciphertext fields are
+// kept as they are, unless a field is stored in an older encryption format:
+// then the whole payload is re-encrypted, so that edited items migrate to the
+// current format. When edit reports no change, payload is returned as is
+// with changed set to false.
+func (p *clientPrivateDataService) editMetadata(payload models.PrivateDataPayload, edit func(meta *models.Metadata) bool) (models.PrivateDataPayload, bool, error) {
+	plain, outdated, err := p.crypto.DecryptPayloadOutdated(payload)
+	if err != nil {
+		return payload, false, fmt.Errorf("decrypt payload: %w", err)
+	}
+	if !edit(&plain.Metadata) {
+		return payload, false, nil
+	}
+
+	if outdated {
+		enc, err := p.crypto.EncryptPayload(plain)
+		if err != nil {
+			return payload, false, fmt.Errorf("encrypt payload: %w", err)
+		}
+		return enc, true, nil
+	}
+
+	enc, err := p.crypto.EncryptPayload(models.DecipheredPayload{Metadata: plain.Metadata, Type: plain.Type})
+	if err != nil {
+		return payload, false, fmt.Errorf("encrypt metadata: %w", err)
+	}
+	payload.Metadata = enc.Metadata
+	return payload, true, nil
+}
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/mock"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClientPrivateDataService_MoveToFolder(t *testing.T) {
+	work := "Работа"
+	home := "Дом"
+	local := map[string]models.PrivateData{
+		"id1": {ClientSideID: "id1", UserID: 1, Version: 3, Payload: models.PrivateDataPayload{Type: models.Text, Metadata: "meta-1", Data: "data-1"}},
+		"id2": {ClientSideID: "id2", UserID: 1, Version: 5, Payload: models.PrivateDataPayload{Type: models.LoginPassword, Metadata: "meta-2", Data: "data-2"}},
+		"id3": {ClientSideID: "id3", UserID: 1, Version: 2, Payload: models.PrivateDataPayload{Type: models.Text, Metadata: "meta-3", Data: "data-3"}},
+	}
+	plain := map[models.CipheredMetadata]models.DecipheredPayload{
+		"meta-1": {Type: models.Text, Metadata: models.Metadata{Name: "a", Folder: &home}},
+		"meta-2": {Type: models.LoginPassword, Metadata: models.Metadata{Name: "b"}},
+		"meta-3": {Type: models.Text, Metadata: models.Metadata{Name: "c", Folder: &home}},
+	}
+	states := []models.PrivateDataState{
+		{ClientSideID: "id1", Version: 3},
+		{ClientSideID: "id2", Version: 5},
+		// id3 changed on another device since the last sync.
+		{ClientSideID: "id3", Version: 4},
+	}
+
+	setup := func(t *testing.T) (ClientPrivateDataService, *mock.MockLocalPrivateDataRepository, *mock.MockServerAdapter) {
+		ctrl := gomock.NewController(t)
+		svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+		mockAdapter.EXPECT().GetServerStates(gomock.Any(), int64(1)).Return(states, nil)
+		mockRepo.EXPECT().GetPrivateData(gomock.Any(), gomock.Any(), int64(1)).DoAndReturn(
+			func(_ context.Context, id string, _ int64) (models.PrivateData, error) { return local[id], nil },
+		).AnyTimes()
+		mockCrypto.EXPECT().DecryptPayloadOutdated(gomock.Any()).DoAndReturn(
+			func(enc models.PrivateDataPayload) (models.DecipheredPayload, bool, error) {
+				return plain[enc.Metadata], false, nil
+			},
+		).AnyTimes()
+		mockCrypto.EXPECT().EncryptPayload(gomock.Any()).DoAndReturn(
+			func(p models.DecipheredPayload) (models.PrivateDataPayload, error) {
+				return models.PrivateDataPayload{Type: p.Type, Metadata: models.CipheredMetadata("meta-" + p.Metadata.Name + "@" + *p.Metadata.Folder)}, nil
+			},
+		).AnyTimes()
+		mockCrypto.EXPECT().ComputeHash(gomock.Any()).DoAndReturn(
+			func(p any) (string, error) {
+				return fmt.Sprintf("hash(%s)", p.(models.PrivateDataPayload).Metadata), nil
+			},
+		).AnyTimes()
+		return svc, mockRepo, mockAdapter
+	}
+
+	t.Run("single multi-record update for items in sync", func(t *testing.T) {
+		svc, mockRepo, mockAdapter := setup(t)
+		ctx := context.Background()
+
+		var pushed models.UpdateRequest
+		mockAdapter.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req models.UpdateRequest) error {
+			pushed = req
+			return nil
+		})
+		var stored []models.PrivateData
+		mockRepo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, data models.PrivateData) error {
+			stored = append(stored, data)
+			return nil
+		}).Times(2)
+		mockRepo.EXPECT().IncrementVersion(ctx, "id1", int64(1)).Return(nil)
+		mockRepo.EXPECT().IncrementVersion(ctx, "id2", int64(1)).Return(nil)
+
+		result, err := svc.MoveToFolder(ctx, 1, []string{"id1", "id2", "id3"}, " "+work+" ")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"id1", "id2"}, result.Moved)
+		assert.Equal(t, []string{"id3"}, result.Conflicted)
+
+		metaA := models.CipheredMetadata("meta-a@" + work)
+		metaB := models.CipheredMetadata("meta-b@" + work)
+		assert.Equal(t, models.UpdateRequest{
+			UserID: 1,
+			PrivateDataUpdates: []models.PrivateDataUpdate{
+				{ClientSideID: "id1", Version: 3, UpdatedRecordHash: "hash(" + string(metaA) + ")", FieldsUpdate: models.FieldsUpdate{Metadata: &metaA}},
+				{ClientSideID: "id2", Version: 5, UpdatedRecordHash: "hash(" + string(metaB) + ")", FieldsUpdate: models.FieldsUpdate{Metadata: &metaB}},
+			},
+			Length: 2,
+		}, pushed)
+
+		require.Len(t, stored, 2)
+		assert.Equal(t, metaA, stored[0].Payload.Metadata)
+		assert.Equal(t, models.CipheredData("data-1"), stored[0].Payload.Data, "only the metadata is re-encrypted")
+	})
+
+	t.Run("item already in the folder is not sent", func(t *testing.T) {
+		svc, _, _ := setup(t)
+
+		result, err := svc.MoveToFolder(context.Background(), 1, []string{"id1"}, home)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id1"}, result.Moved)
+		assert.Empty(t, result.Conflicted)
+	})
+
+	t.Run("folder path is normalized", func(t *testing.T) {
+		svc, mockRepo, mockAdapter := setup(t)
+		ctx := context.Background()
+
+		var pushed models.UpdateRequest
+		mockAdapter.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req models.UpdateRequest) error {
+			pushed = req
+			return nil
+		})
+		mockRepo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().IncrementVersion(ctx, "id2", int64(1)).Return(nil)
+
+		result, err := svc.MoveToFolder(ctx, 1, []string{"id2"}, " "+work+" // Почта/ ")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id2"}, result.Moved)
+
+		require.Len(t, pushed.PrivateDataUpdates, 1)
+		assert.Equal(t, models.CipheredMetadata("meta-b@"+work+"/Почта"), *pushed.PrivateDataUpdates[0].FieldsUpdate.Metadata)
+	})
+
+	t.Run("rejected batch leaves the local store untouched", func(t *testing.T) {
+		svc, _, mockAdapter := setup(t)
+
+		mockAdapter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(fmt.Errorf("%w: version conflict", adapter.ErrConflict))
+
+		result, err := svc.MoveToFolder(context.Background(), 1, []string{"id1", "id2"}, work)
+		require.NoError(t, err)
+		assert.Empty(t, result.Moved)
+		assert.Equal(t, []string{"id1", "id2"}, result.Conflicted)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		svc, _, mockAdapter := setup(t)
+
+		mockAdapter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("boom"))
+
+		_, err := svc.MoveToFolder(context.Background(), 1, []string{"id1"}, work)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "move items on server")
+	})
+}
+
+func TestClientPrivateDataService_MoveToFolder_SplitsLargeMoves(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+	ctx := context.Background()
+
+	total := config.DefaultMaxBatchEntries + 1
+	ids := make([]string, total)
+	states := make([]models.PrivateDataState, total)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("id%d", i)
+		states[i] = models.PrivateDataState{ClientSideID: ids[i], Version: 1}
+	}
+
+	mockAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return(states, nil)
+	mockRepo.EXPECT().GetPrivateData(ctx, gomock.Any(), int64(1)).DoAndReturn(
+		func(_ context.Context, id string, _ int64) (models.PrivateData, error) {
+			return models.PrivateData{ClientSideID: id, UserID: 1, Version: 1, Payload: models.PrivateDataPayload{Type: models.Text, Metadata: "meta"}}, nil
+		},
+	).Times(total)
+	mockCrypto.EXPECT().DecryptPayloadOutdated(gomock.Any()).Return(models.DecipheredPayload{Type: models.Text}, false, nil).Times(total)
+	mockCrypto.EXPECT().EncryptPayload(gomock.Any()).Return(models.PrivateDataPayload{Type: models.Text, Metadata: "moved"}, nil).Times(total)
+	mockCrypto.EXPECT().ComputeHash(gomock.Any()).Return("hash", nil).Times(total)
+	mockRepo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).Return(nil).Times(total)
+	mockRepo.EXPECT().IncrementVersion(ctx, gomock.Any(), int64(1)).Return(nil).Times(total)
+
+	var sizes []int
+	mockAdapter.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req models.UpdateRequest) error {
+		assert.Equal(t, len(req.PrivateDataUpdates), req.Length)
+		sizes = append(sizes, req.Length)
+		return nil
+	}).Times(2)
+
+	result, err := svc.MoveToFolder(ctx, 1, ids, "Архив")
+	require.NoError(t, err)
+	assert.Equal(t, []int{config.DefaultMaxBatchEntries, 1}, sizes)
+	assert.Len(t, result.Moved, total)
+	assert.Empty(t, result.Conflicted)
+}
+
+func TestClientPrivateDataService_MoveToFolder_UsesConfiguredBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockLocalPrivateDataRepository(ctrl)
+	mockAdapter := mock.NewMockServerAdapter(ctrl)
+	mockCrypto := mock.NewMockClientCryptoService(ctrl)
+	svc := NewClientPrivateDataService(&store.ClientStorages{PrivateDataRepository: mockRepo}, mockAdapter, mockCrypto, testMaxBinarySize, 2, "", clock.Real{})
+	ctx := context.Background()
+
+	ids := []string{"id0", "id1", "id2", "id3", "id4"}
+	states := make([]models.PrivateDataState, len(ids))
+	for i, id := range ids {
+		states[i] = models.PrivateDataState{ClientSideID: id, Version: 1}
+	}
+
+	mockAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return(states, nil)
+	mockRepo.EXPECT().GetPrivateData(ctx, gomock.Any(), int64(1)).DoAndReturn(
+		func(_ context.Context, id string, _ int64) (models.PrivateData, error) {
+			return models.PrivateData{ClientSideID: id, UserID: 1, Version: 1, Payload: models.PrivateDataPayload{Type: models.Text, Metadata: "meta"}}, nil
+		},
+	).Times(len(ids))
+	mockCrypto.EXPECT().DecryptPayloadOutdated(gomock.Any()).Return(models.DecipheredPayload{Type: models.Text}, false, nil).Times(len(ids))
+	mockCrypto.EXPECT().EncryptPayload(gomock.Any()).Return(models.PrivateDataPayload{Type: models.Text, Metadata: "moved"}, nil).Times(len(ids))
+	mockCrypto.EXPECT().ComputeHash(gomock.Any()).Return("hash", nil).Times(len(ids))
+	mockRepo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).Return(nil).Times(len(ids))
+	mockRepo.EXPECT().IncrementVersion(ctx, gomock.Any(), int64(1)).Return(nil).Times(len(ids))
+
+	var sizes []int
+	mockAdapter.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req models.UpdateRequest) error {
+		sizes = append(sizes, req.Length)
+		return nil
+	}).Times(3)
+
+	result, err := svc.MoveToFolder(ctx, 1, ids, "Архив")
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+	assert.Len(t, result.Moved, len(ids))
+}
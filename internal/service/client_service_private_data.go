@@ -5,10 +5,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
 	"github.com/MKhiriev/go-pass-keeper/internal/utils"
 	"github.com/MKhiriev/go-pass-keeper/models"
@@ -19,13 +26,104 @@ type clientPrivateDataService struct {
 	adapter           adapter.ServerAdapter
 	crypto            ClientCryptoService
 	clientIDGenerator *utils.UUIDGenerator
+	maxBinarySize     int64
+	// maxBatchEntries is the largest number of updates a bulk move sends in
+	// one request.
+	maxBatchEntries int
+	// clock stamps the creation and update times of items.
+	clock clock.Clock
 }
 
 // NewClientPrivateDataService constructs a clientPrivateDataService wired to the
 // provided local store, server adapter, and crypto service. A UUID generator is
-// allocated internally for assigning client-side IDs to new vault items.
-func NewClientPrivateDataService(localStore *store.ClientStorages, serverAdapter adapter.ServerAdapter, crypto ClientCryptoService) ClientPrivateDataService {
-	return &clientPrivateDataService{localStore: localStore, adapter: serverAdapter, crypto: crypto, clientIDGenerator: utils.NewUUIDGenerator()}
+// allocated internally for assigning client-side IDs to new vault items; a
+// non-empty clientIDPrefix is prepended to each of them as "prefix-".
+// maxBinarySize limits the size of Binary attachments; zero or a negative value
+// disables the check. maxBatchEntries should match the server's
+// max_batch_entries; zero or a negative value means
+// [config.DefaultMaxBatchEntries]. Items are stamped with the time of clk.
+func NewClientPrivateDataService(localStore *store.ClientStorages, serverAdapter adapter.ServerAdapter, crypto ClientCryptoService, maxBinarySize int64, maxBatchEntries int, clientIDPrefix string, clk clock.Clock) ClientPrivateDataService {
+	if maxBatchEntries <= 0 {
+		maxBatchEntries = config.DefaultMaxBatchEntries
+	}
+	return &clientPrivateDataService{
+		localStore:        localStore,
+		adapter:           serverAdapter,
+		crypto:            crypto,
+		clientIDGenerator: utils.NewPrefixedUUIDGenerator(clientIDPrefix),
+		maxBinarySize:     maxBinarySize,
+		maxBatchEntries:   maxBatchEntries,
+		clock:             clk,
+	}
+}
+
+// ValidateBinarySize returns [ErrBinaryTooLarge] (wrapped with the limit in
+// megabytes) when size exceeds maxSize. A file of exactly maxSize bytes is
+// accepted. A non-positive maxSize disables the check.
+func ValidateBinarySize(size, maxSize int64) error {
+	if maxSize <= 0 || size <= maxSize {
+		return nil
+	}
+
+	const mb = 1024 * 1024
+	limit := strconv.FormatFloat(float64(maxSize)/mb, 'f', -1, 64)
+	return fmt.Errorf("%w (max %s MB)", ErrBinaryTooLarge, limit)
+}
+
+// MaxItemNameLength is the longest vault item name, in characters, accepted
+// by [NormalizeItemName].
+const MaxItemNameLength = 256
+
+// NormalizeItemName trims surrounding whitespace from name and validates the
+// result. It returns [ErrItemNameEmpty] for an empty or whitespace-only name
+// and [ErrItemNameTooLong] when the trimmed name is longer than
+// [MaxItemNameLength] characters. A name of exactly MaxItemNameLength
+// characters is accepted.
+func NormalizeItemName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrItemNameEmpty
+	}
+	if utf8.RuneCountInString(name) > MaxItemNameLength {
+		return "", fmt.Errorf("%w (max %d)", ErrItemNameTooLong, MaxItemNameLength)
+	}
+	return name, nil
+}
+
+// validatePlain normalizes the item name and the folder path (see
+// [NormalizeFolder]) in place and checks the Binary attachment size. It runs
+// before anything is encrypted.
+func (p *clientPrivateDataService) validatePlain(plain *models.DecipheredPayload) error {
+	if err := p.validateBinary(*plain); err != nil {
+		return err
+	}
+	return normalizePlain(plain)
+}
+
+// normalizePlain normalizes the item name and the folder path of plain in
+// place.
+func normalizePlain(plain *models.DecipheredPayload) error {
+	name, err := NormalizeItemName(plain.Metadata.Name)
+	if err != nil {
+		return err
+	}
+	plain.Metadata.Name = name
+
+	if plain.Metadata.Folder != nil {
+		folder := NormalizeFolder(*plain.Metadata.Folder)
+		plain.Metadata.Folder = nil
+		if folder != "" {
+			plain.Metadata.Folder = &folder
+		}
+	}
+	return nil
+}
+
+func (p *clientPrivateDataService) validateBinary(plain models.DecipheredPayload) error {
+	if plain.Type != models.Binary || plain.BinaryData == nil {
+		return nil
+	}
+	return ValidateBinarySize(plain.BinaryData.Size, p.maxBinarySize)
 }
 
 // SetEncryptionKey implements ClientPrivateDataService. It forwards the DEK to the
@@ -34,17 +132,30 @@ func (p *clientPrivateDataService) SetEncryptionKey(key []byte) {
 	p.crypto.SetEncryptionKey(key)
 }
 
+// HasEncryptionKey implements ClientPrivateDataService by delegating to the
+// underlying ClientCryptoService.
+func (p *clientPrivateDataService) HasEncryptionKey() bool {
+	return p.crypto.HasEncryptionKey()
+}
+
 // Create implements ClientPrivateDataService. It encrypts plain, assigns a new
-// UUID as the client-side ID, saves the item to the local store, and uploads it
-// to the server. Returns an error if any step fails.
+// UUID as the client-side ID, saves the item to the local store, uploads it
+// to the server, and records the server-assigned ID and timestamps locally.
+// The item name is normalized with [NormalizeItemName], and Binary attachments
+// larger than the configured limit are rejected with [ErrBinaryTooLarge],
+// before anything is encrypted. Returns an error if any step fails.
 func (p *clientPrivateDataService) Create(ctx context.Context, userID int64, plain models.DecipheredPayload) error {
+	if err := p.validatePlain(&plain); err != nil {
+		return err
+	}
+
 	encPayload, err := p.crypto.EncryptPayload(plain)
 	if err != nil {
 		return fmt.Errorf("encrypt payload for create: %w", err)
 	}
 
 	clientSideID := p.clientIDGenerator.Generate()
-	now := time.Now().UTC()
+	now := p.clock.Now().UTC()
 
 	hash, err := p.crypto.ComputeHash(encPayload)
 	if err != nil {
@@ -64,27 +175,74 @@ func (p *clientPrivateDataService) Create(ctx context.Context, userID int64, pla
 		return fmt.Errorf("save created item to local store: %w", err)
 	}
 
-	if err = p.adapter.Upload(ctx, models.UploadRequest{UserID: userID, PrivateDataList: []*models.PrivateData{&item}}); err != nil {
+	uploaded, err := p.adapter.Upload(ctx, models.UploadRequest{UserID: userID, PrivateDataList: []*models.PrivateData{&item}, Length: 1})
+	if err != nil {
 		return fmt.Errorf("upload created item to server: %w", err)
 	}
 
+	if len(uploaded.Items) > 0 {
+		if err = p.localStore.PrivateDataRepository.SetServerFields(ctx, userID, uploaded.Items...); err != nil {
+			return fmt.Errorf("store server fields of created item: %w", err)
+		}
+	}
+
 	return nil
 }
 
 // GetAll implements ClientPrivateDataService. It loads all non-deleted vault items
 // for userID from the local store, decrypts each payload, and returns the plaintext
-// slice. Returns an error if the local query or any decryption fails.
-func (p *clientPrivateDataService) GetAll(ctx context.Context, userID int64) ([]models.DecipheredPayload, error) {
-	items, err := p.localStore.PrivateDataRepository.GetAllPrivateData(ctx, userID)
+// slice. An item that fails to decrypt does not abort the load: it is left out
+// of items and its client-side ID is reported in failed instead. Returns an
+// error if the local query fails or if no DEK is set ([ErrKeyNotAvailable]).
+func (p *clientPrivateDataService) GetAll(ctx context.Context, userID int64) (items []models.DecipheredPayload, failed []string, err error) {
+	return p.getAll(ctx, userID, p.crypto.DecryptPayload)
+}
+
+// Summary implements ClientPrivateDataService. The counts come from a grouped
+// query on the unencrypted type column of the local store.
+func (p *clientPrivateDataService) Summary(ctx context.Context, userID int64) (models.VaultSummary, error) {
+	summary, err := p.localStore.PrivateDataRepository.CountByType(ctx, userID)
+	if err != nil {
+		return models.VaultSummary{}, fmt.Errorf("count local items by type: %w", err)
+	}
+	return summary, nil
+}
+
+// GetAllMeta implements ClientPrivateDataService. It is GetAll with
+// [ClientCryptoService.DecryptMetadata] in place of the full decryption.
+func (p *clientPrivateDataService) GetAllMeta(ctx context.Context, userID int64) (items []models.DecipheredPayload, failed []string, err error) {
+	return p.getAll(ctx, userID, p.crypto.DecryptMetadata)
+}
+
+// getAll loads every local item of userID and decrypts it with decrypt.
+func (p *clientPrivateDataService) getAll(
+	ctx context.Context,
+	userID int64,
+	decrypt func(models.PrivateDataPayload) (models.DecipheredPayload, error),
+) (items []models.DecipheredPayload, failed []string, err error) {
+	stored, err := p.localStore.PrivateDataRepository.GetAllPrivateData(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("get all local items: %w", err)
+		return nil, nil, fmt.Errorf("get all local items: %w", err)
 	}
+	return p.decryptAll(stored, userID, decrypt)
+}
 
-	decrypted := make([]models.DecipheredPayload, 0, len(items))
-	for _, item := range items {
-		payload, err := p.crypto.DecryptPayload(item.Payload)
-		if err != nil {
-			return nil, fmt.Errorf("decrypt item %s: %w", item.ClientSideID, err)
+// decryptAll decrypts the stored items of userID with decrypt. Items that
+// fail to decrypt are reported in failed; a missing DEK fails the call.
+func (p *clientPrivateDataService) decryptAll(
+	stored []models.PrivateData,
+	userID int64,
+	decrypt func(models.PrivateDataPayload) (models.DecipheredPayload, error),
+) (items []models.DecipheredPayload, failed []string, err error) {
+	items = make([]models.DecipheredPayload, 0, len(stored))
+	for _, item := range stored {
+		payload, decryptErr := decrypt(item.Payload)
+		if errors.Is(decryptErr, ErrKeyNotAvailable) {
+			return nil, nil, fmt.Errorf("decrypt item %s: %w", item.ClientSideID, decryptErr)
+		}
+		if decryptErr != nil {
+			failed = append(failed, item.ClientSideID)
+			continue
 		}
 		payload.ClientSideID = item.ClientSideID
 		if item.UserID > 0 {
@@ -93,10 +251,10 @@ func (p *clientPrivateDataService) GetAll(ctx context.Context, userID int64) ([]
 			payload.UserID = userID
 		}
 
-		decrypted = append(decrypted, payload)
+		items = append(items, payload)
 	}
 
-	return decrypted, nil
+	return items, failed, nil
 }
 
 // Get implements ClientPrivateDataService. It loads the vault item identified by
@@ -124,24 +282,85 @@ func (p *clientPrivateDataService) Get(ctx context.Context, clientSideID string,
 
 // Update implements ClientPrivateDataService. It encrypts the modified payload,
 // updates the local store, and pushes the change to the server. On server success
-// the local version counter is incremented. Returns an error if any step fails.
+// the local version counter is incremented. The item name is normalized with
+// [NormalizeItemName] and oversized Binary attachments are rejected with
+// [ErrBinaryTooLarge], except for metadata-only edits of an item that
+// already holds one (see [clientPrivateDataService.updateOversized]).
+// Returns an error if any step fails.
 func (p *clientPrivateDataService) Update(ctx context.Context, data models.DecipheredPayload) error {
+	sizeErr := p.validateBinary(data)
+	if err := normalizePlain(&data); err != nil {
+		return err
+	}
+
 	prev, err := p.localStore.PrivateDataRepository.GetPrivateData(ctx, data.ClientSideID, data.UserID)
 	if err != nil {
 		return fmt.Errorf("load existing local item: %w", err)
 	}
+	if sizeErr != nil {
+		return p.updateOversized(ctx, prev, data, sizeErr)
+	}
 
 	encPayload, err := p.crypto.EncryptPayload(data)
 	if err != nil {
 		return fmt.Errorf("encrypt payload for update: %w", err)
 	}
 
+	return p.pushUpdate(ctx, prev, encPayload)
+}
+
+// updateOversized applies data to prev, a local item whose attachment is
+// over the size limit and therefore never synced. Only an edit that keeps the
+// attachment, notes and custom fields of prev is accepted; it re-encrypts
+// just the metadata and is stored locally without contacting the server.
+// Any other edit is rejected with sizeErr.
+func (p *clientPrivateDataService) updateOversized(ctx context.Context, prev models.PrivateData, data models.DecipheredPayload, sizeErr error) error {
+	current, err := p.crypto.DecryptPayload(prev.Payload)
+	if err != nil {
+		return fmt.Errorf("decrypt existing local item: %w", err)
+	}
+	if current.Type != data.Type ||
+		!reflect.DeepEqual(current.BinaryData, data.BinaryData) ||
+		!reflect.DeepEqual(current.Notes, data.Notes) ||
+		!reflect.DeepEqual(current.AdditionalFields, data.AdditionalFields) {
+		return sizeErr
+	}
+
+	payload, changed, err := p.editMetadata(prev.Payload, func(meta *models.Metadata) bool {
+		if reflect.DeepEqual(*meta, data.Metadata) {
+			return false
+		}
+		*meta = data.Metadata
+		return true
+	})
+	if err != nil || !changed {
+		return err
+	}
+
+	updated := prev
+	updated.Payload = payload
+	if updated.Hash, err = p.crypto.ComputeHash(updated.Payload); err != nil {
+		return fmt.Errorf("compute hash: %w", err)
+	}
+	now := p.clock.Now().UTC()
+	updated.UpdatedAt = &now
+	if err = p.localStore.PrivateDataRepository.UpdatePrivateData(ctx, updated); err != nil {
+		return fmt.Errorf("update local item: %w", err)
+	}
+	return nil
+}
+
+// pushUpdate replaces the payload of the local item prev with the already
+// encrypted encPayload, then pushes the change to the server with prev's
+// version as the optimistic-lock base. On server success the local version
+// counter is incremented.
+func (p *clientPrivateDataService) pushUpdate(ctx context.Context, prev models.PrivateData, encPayload models.PrivateDataPayload) error {
 	hash, err := p.crypto.ComputeHash(encPayload)
 	if err != nil {
 		return fmt.Errorf("compute hash with encrypted payload for create: %w", err)
 	}
 
-	now := time.Now().UTC()
+	now := p.clock.Now().UTC()
 	updated := prev
 	updated.Payload = encPayload
 	updated.Hash = hash
@@ -166,6 +385,7 @@ func (p *clientPrivateDataService) Update(ctx context.Context, data models.Decip
 				AdditionalFields: updated.Payload.AdditionalFields,
 			},
 		}},
+		Length: 1,
 	}
 
 	if err = p.adapter.Update(ctx, req); err != nil {
@@ -199,6 +419,7 @@ func (p *clientPrivateDataService) Delete(ctx context.Context, clientSideID stri
 			ClientSideID: clientSideID,
 			Version:      item.Version,
 		}},
+		Length: 1,
 	}
 
 	if err = p.adapter.Delete(ctx, req); err != nil {
@@ -212,3 +433,63 @@ func (p *clientPrivateDataService) Delete(ctx context.Context, clientSideID stri
 
 	return nil
 }
+
+// GetHistory implements ClientPrivateDataService. It fetches the previous
+// versions of the item from the server and decrypts each one. Versions that
+// cannot be decrypted are left out; a missing DEK fails the whole call.
+func (p *clientPrivateDataService) GetHistory(ctx context.Context, userID int64, clientSideID string) ([]models.DecipheredVersion, error) {
+	versions, err := p.adapter.GetVersionHistory(ctx, clientSideID)
+	if err != nil {
+		return nil, fmt.Errorf("get version history from server: %w", err)
+	}
+
+	history := make([]models.DecipheredVersion, 0, len(versions))
+	for _, v := range versions {
+		payload, decErr := p.crypto.DecryptPayload(v.Payload)
+		if errors.Is(decErr, ErrKeyNotAvailable) {
+			return nil, decErr
+		}
+		if decErr != nil {
+			continue
+		}
+		payload.ClientSideID = clientSideID
+		payload.UserID = userID
+		history = append(history, models.DecipheredVersion{
+			Version:    v.Version,
+			Payload:    payload,
+			UpdatedAt:  v.UpdatedAt,
+			ArchivedAt: v.ArchivedAt,
+		})
+	}
+
+	return history, nil
+}
+
+// RestoreVersion implements ClientPrivateDataService. The archived payload
+// is reused as-is, still encrypted: it is only decrypted to check that it
+// belongs to this vault, then written as a new version through the same
+// path as [clientPrivateDataService.Update], so the server applies its
+// optimistic lock and other devices pick the change up on sync.
+func (p *clientPrivateDataService) RestoreVersion(ctx context.Context, userID int64, clientSideID string, version int64) error {
+	versions, err := p.adapter.GetVersionHistory(ctx, clientSideID)
+	if err != nil {
+		return fmt.Errorf("get version history from server: %w", err)
+	}
+
+	idx := slices.IndexFunc(versions, func(v models.PrivateDataVersion) bool { return v.Version == version })
+	if idx < 0 {
+		return fmt.Errorf("%w (версия %d)", ErrVersionNotInHistory, version)
+	}
+	archived := versions[idx].Payload
+
+	if _, err = p.crypto.DecryptPayload(archived); err != nil {
+		return fmt.Errorf("decrypt archived version: %w", err)
+	}
+
+	prev, err := p.localStore.PrivateDataRepository.GetPrivateData(ctx, clientSideID, userID)
+	if err != nil {
+		return fmt.Errorf("load existing local item: %w", err)
+	}
+
+	return p.pushUpdate(ctx, prev, archived)
+}
@@ -6,8 +6,12 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/crypto"
 	"github.com/MKhiriev/go-pass-keeper/internal/mock"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
 	"github.com/MKhiriev/go-pass-keeper/models"
@@ -34,7 +38,7 @@ func newTestPrivateDataSvc(
 	storages := &store.ClientStorages{
 		PrivateDataRepository: mockRepo,
 	}
-	svc := NewClientPrivateDataService(storages, mockAdapter, mockCrypto)
+	svc := NewClientPrivateDataService(storages, mockAdapter, mockCrypto, testMaxBinarySize, 0, "", clock.Real{})
 	return svc, mockRepo, mockAdapter, mockCrypto
 }
 
@@ -57,19 +61,93 @@ func TestClientPrivateDataService_Create_Success(t *testing.T) {
 	mockCrypto.EXPECT().EncryptPayload(plain).Return(encPayload, nil)
 	mockCrypto.EXPECT().ComputeHash(encPayload).Return("hash123", nil)
 	mockRepo.EXPECT().SavePrivateData(ctx, userID, gomock.Any()).Return(nil)
-	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(nil)
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
 
 	err := svc.Create(ctx, userID, plain)
 	require.NoError(t, err)
 }
 
+func TestClientPrivateDataService_Create_StoresServerFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+	plain := models.DecipheredPayload{UserID: userID, Metadata: models.Metadata{Name: "test"}}
+	encPayload := models.PrivateDataPayload{}
+	createdAt := time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Minute)
+
+	var saved models.PrivateData
+	mockCrypto.EXPECT().EncryptPayload(plain).Return(encPayload, nil)
+	mockCrypto.EXPECT().ComputeHash(encPayload).Return("hash", nil)
+	mockRepo.EXPECT().SavePrivateData(ctx, userID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, data ...models.PrivateData) error {
+			require.Len(t, data, 1)
+			saved = data[0]
+			return nil
+		},
+	)
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, req models.UploadRequest) (models.UploadResponse, error) {
+			require.Len(t, req.PrivateDataList, 1)
+			return models.UploadResponse{
+				Items: []models.UploadedItem{{
+					ID:           99,
+					ClientSideID: req.PrivateDataList[0].ClientSideID,
+					CreatedAt:    &createdAt,
+					UpdatedAt:    &updatedAt,
+				}},
+				Length: 1,
+			}, nil
+		},
+	)
+	mockRepo.EXPECT().SetServerFields(ctx, userID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, items ...models.UploadedItem) error {
+			require.Len(t, items, 1)
+			assert.Equal(t, saved.ClientSideID, items[0].ClientSideID)
+			assert.Equal(t, int64(99), items[0].ID)
+			assert.Equal(t, &createdAt, items[0].CreatedAt)
+			assert.Equal(t, &updatedAt, items[0].UpdatedAt)
+			return nil
+		},
+	)
+
+	err := svc.Create(ctx, userID, plain)
+	require.NoError(t, err)
+}
+
+func TestClientPrivateDataService_Create_SetServerFieldsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+	plain := models.DecipheredPayload{UserID: userID, Metadata: models.Metadata{Name: "test"}}
+	encPayload := models.PrivateDataPayload{}
+
+	mockCrypto.EXPECT().EncryptPayload(plain).Return(encPayload, nil)
+	mockCrypto.EXPECT().ComputeHash(encPayload).Return("hash", nil)
+	mockRepo.EXPECT().SavePrivateData(ctx, userID, gomock.Any()).Return(nil)
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{
+		Items: []models.UploadedItem{{ID: 1, ClientSideID: "cid"}},
+	}, nil)
+	mockRepo.EXPECT().SetServerFields(ctx, userID, gomock.Any()).Return(errors.New("db error"))
+
+	err := svc.Create(ctx, userID, plain)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "store server fields of created item")
+}
+
 func TestClientPrivateDataService_Create_EncryptError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	svc, _, _, mockCrypto := newTestPrivateDataSvc(t, ctrl)
 	ctx := context.Background()
-	plain := models.DecipheredPayload{UserID: 1}
+	plain := models.DecipheredPayload{UserID: 1, Metadata: models.Metadata{Name: "test"}}
 
 	mockCrypto.EXPECT().EncryptPayload(plain).Return(models.PrivateDataPayload{}, errors.New("aes fail"))
 
@@ -84,7 +162,7 @@ func TestClientPrivateDataService_Create_HashError(t *testing.T) {
 
 	svc, _, _, mockCrypto := newTestPrivateDataSvc(t, ctrl)
 	ctx := context.Background()
-	plain := models.DecipheredPayload{UserID: 1}
+	plain := models.DecipheredPayload{UserID: 1, Metadata: models.Metadata{Name: "test"}}
 	encPayload := models.PrivateDataPayload{}
 
 	mockCrypto.EXPECT().EncryptPayload(plain).Return(encPayload, nil)
@@ -102,7 +180,7 @@ func TestClientPrivateDataService_Create_SaveLocalError(t *testing.T) {
 	svc, mockRepo, _, mockCrypto := newTestPrivateDataSvc(t, ctrl)
 	ctx := context.Background()
 	userID := int64(1)
-	plain := models.DecipheredPayload{UserID: userID}
+	plain := models.DecipheredPayload{UserID: userID, Metadata: models.Metadata{Name: "test"}}
 	encPayload := models.PrivateDataPayload{}
 
 	mockCrypto.EXPECT().EncryptPayload(plain).Return(encPayload, nil)
@@ -121,13 +199,13 @@ func TestClientPrivateDataService_Create_UploadError(t *testing.T) {
 	svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
 	ctx := context.Background()
 	userID := int64(1)
-	plain := models.DecipheredPayload{UserID: userID}
+	plain := models.DecipheredPayload{UserID: userID, Metadata: models.Metadata{Name: "test"}}
 	encPayload := models.PrivateDataPayload{}
 
 	mockCrypto.EXPECT().EncryptPayload(plain).Return(encPayload, nil)
 	mockCrypto.EXPECT().ComputeHash(encPayload).Return("hash", nil)
 	mockRepo.EXPECT().SavePrivateData(ctx, userID, gomock.Any()).Return(nil)
-	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(errors.New("network error"))
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, errors.New("network error"))
 
 	// IncrementVersion НЕ должен вызываться при ошибке Upload
 	err := svc.Create(ctx, userID, plain)
@@ -155,9 +233,10 @@ func TestClientPrivateDataService_GetAll_Success(t *testing.T) {
 	mockRepo.EXPECT().GetAllPrivateData(ctx, userID).Return(items, nil)
 	mockCrypto.EXPECT().DecryptPayload(encPayload).Return(decrypted, nil).Times(2)
 
-	got, err := svc.GetAll(ctx, userID)
+	got, failed, err := svc.GetAll(ctx, userID)
 	require.NoError(t, err)
 	assert.Len(t, got, 2)
+	assert.Empty(t, failed)
 }
 
 func TestClientPrivateDataService_GetAll_RepoError(t *testing.T) {
@@ -169,12 +248,12 @@ func TestClientPrivateDataService_GetAll_RepoError(t *testing.T) {
 
 	mockRepo.EXPECT().GetAllPrivateData(ctx, int64(1)).Return(nil, errors.New("db error"))
 
-	_, err := svc.GetAll(ctx, 1)
+	_, _, err := svc.GetAll(ctx, 1)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "get all local items")
 }
 
-func TestClientPrivateDataService_GetAll_DecryptError(t *testing.T) {
+func TestClientPrivateDataService_GetAll_KeyNotAvailable(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -186,15 +265,112 @@ func TestClientPrivateDataService_GetAll_DecryptError(t *testing.T) {
 	mockRepo.EXPECT().GetAllPrivateData(ctx, userID).Return([]models.PrivateData{
 		{ClientSideID: "id1", Payload: encPayload},
 	}, nil)
-	mockCrypto.EXPECT().DecryptPayload(encPayload).Return(models.DecipheredPayload{}, errors.New("decrypt fail"))
+	mockCrypto.EXPECT().DecryptPayload(encPayload).Return(models.DecipheredPayload{}, ErrKeyNotAvailable)
 
-	_, err := svc.GetAll(ctx, userID)
-	require.Error(t, err)
+	_, _, err := svc.GetAll(ctx, userID)
+	require.ErrorIs(t, err, ErrKeyNotAvailable)
 	assert.Contains(t, err.Error(), "decrypt item id1")
 }
 
+func TestClientPrivateDataService_GetAll_IsolatesCorruptItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := int64(1)
+
+	keyChain := crypto.NewKeyChainService()
+	dek, err := keyChain.GenerateDEK()
+	require.NoError(t, err)
+	otherDEK, err := keyChain.GenerateDEK()
+	require.NoError(t, err)
+
+	cryptoSvc := NewClientCryptoService(keyChain)
+	cryptoSvc.SetEncryptionKey(dek)
+	otherCryptoSvc := NewClientCryptoService(keyChain)
+	otherCryptoSvc.SetEncryptionKey(otherDEK)
+
+	encrypt := func(svc ClientCryptoService, name string) models.PrivateDataPayload {
+		enc, err := svc.EncryptPayload(models.DecipheredPayload{Type: models.Text, Metadata: models.Metadata{Name: name}})
+		require.NoError(t, err)
+		return enc
+	}
+	corrupt := encrypt(cryptoSvc, "corrupt")
+	corrupt.Data = "not-a-ciphertext"
+
+	mockRepo := mock.NewMockLocalPrivateDataRepository(ctrl)
+	mockRepo.EXPECT().GetAllPrivateData(ctx, userID).Return([]models.PrivateData{
+		{ClientSideID: "good-1", UserID: userID, Payload: encrypt(cryptoSvc, "first")},
+		{ClientSideID: "corrupt", UserID: userID, Payload: corrupt},
+		{ClientSideID: "old-key", UserID: userID, Payload: encrypt(otherCryptoSvc, "old")},
+		{ClientSideID: "good-2", UserID: userID, Payload: encrypt(cryptoSvc, "second")},
+	}, nil)
+
+	svc := NewClientPrivateDataService(
+		&store.ClientStorages{PrivateDataRepository: mockRepo},
+		mock.NewMockServerAdapter(ctrl),
+		cryptoSvc,
+		0,
+		0,
+		"",
+		clock.Real{},
+	)
+
+	got, failed, err := svc.GetAll(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "good-1", got[0].ClientSideID)
+	assert.Equal(t, "first", got[0].Metadata.Name)
+	assert.Equal(t, "good-2", got[1].ClientSideID)
+	assert.Equal(t, "second", got[1].Metadata.Name)
+	assert.Equal(t, []string{"corrupt", "old-key"}, failed)
+}
+
 // ── Get ──────────────────────────────────────────────────────────────────────
 
+func TestClientPrivateDataService_GetAllMeta_DecryptsMetadataOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, _, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+
+	good := models.PrivateDataPayload{Metadata: "meta-good", Type: models.LoginPassword}
+	broken := models.PrivateDataPayload{Metadata: "meta-broken"}
+	mockRepo.EXPECT().GetAllPrivateData(ctx, userID).Return([]models.PrivateData{
+		{ClientSideID: "id1", Payload: good},
+		{ClientSideID: "id2", UserID: userID, Payload: broken},
+	}, nil)
+	mockCrypto.EXPECT().DecryptMetadata(good).Return(models.DecipheredPayload{Metadata: models.Metadata{Name: "mail"}, Type: models.LoginPassword}, nil)
+	mockCrypto.EXPECT().DecryptMetadata(broken).Return(models.DecipheredPayload{}, errors.New("bad tag"))
+	mockCrypto.EXPECT().DecryptPayload(gomock.Any()).Times(0)
+
+	got, failed, err := svc.GetAllMeta(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []models.DecipheredPayload{{
+		ClientSideID: "id1",
+		UserID:       userID,
+		Type:         models.LoginPassword,
+		Metadata:     models.Metadata{Name: "mail"},
+	}}, got)
+	assert.Equal(t, []string{"id2"}, failed)
+}
+
+func TestClientPrivateDataService_GetAllMeta_KeyNotAvailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, _, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetAllPrivateData(ctx, int64(1)).Return([]models.PrivateData{{ClientSideID: "id1"}}, nil)
+	mockCrypto.EXPECT().DecryptMetadata(gomock.Any()).Return(models.DecipheredPayload{}, ErrKeyNotAvailable)
+
+	_, _, err := svc.GetAllMeta(ctx, 1)
+	require.ErrorIs(t, err, ErrKeyNotAvailable)
+}
+
 func TestClientPrivateDataService_Get_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -282,7 +458,7 @@ func TestClientPrivateDataService_Update_GetPrevError(t *testing.T) {
 
 	svc, mockRepo, _, _ := newTestPrivateDataSvc(t, ctrl)
 	ctx := context.Background()
-	data := models.DecipheredPayload{ClientSideID: "id1", UserID: 1}
+	data := models.DecipheredPayload{ClientSideID: "id1", UserID: 1, Metadata: models.Metadata{Name: "test"}}
 
 	mockRepo.EXPECT().GetPrivateData(ctx, "id1", int64(1)).Return(models.PrivateData{}, errors.New("not found"))
 
@@ -297,7 +473,7 @@ func TestClientPrivateDataService_Update_ServerError(t *testing.T) {
 
 	svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
 	ctx := context.Background()
-	data := models.DecipheredPayload{ClientSideID: "id1", UserID: 1}
+	data := models.DecipheredPayload{ClientSideID: "id1", UserID: 1, Metadata: models.Metadata{Name: "test"}}
 	prevItem := models.PrivateData{ClientSideID: "id1", UserID: 1, Version: 3}
 	encPayload := models.PrivateDataPayload{}
 
@@ -313,6 +489,126 @@ func TestClientPrivateDataService_Update_ServerError(t *testing.T) {
 	assert.Contains(t, err.Error(), "update item on server")
 }
 
+// ── History / Restore ────────────────────────────────────────────────────────
+
+func TestClientPrivateDataService_GetHistory_DecryptsVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+	ctx := context.Background()
+	archivedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	good := models.PrivateDataPayload{Metadata: "enc-v2"}
+	broken := models.PrivateDataPayload{Metadata: "enc-v1"}
+
+	mockAdapter.EXPECT().GetVersionHistory(ctx, "id1").Return([]models.PrivateDataVersion{
+		{ClientSideID: "id1", Version: 2, Payload: good, ArchivedAt: archivedAt},
+		{ClientSideID: "id1", Version: 1, Payload: broken, ArchivedAt: archivedAt},
+	}, nil)
+	mockCrypto.EXPECT().DecryptPayload(good).Return(models.DecipheredPayload{Metadata: models.Metadata{Name: "v2"}}, nil)
+	mockCrypto.EXPECT().DecryptPayload(broken).Return(models.DecipheredPayload{}, errors.New("auth failed"))
+
+	got, err := svc.GetHistory(ctx, 1, "id1")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, int64(2), got[0].Version)
+	assert.Equal(t, "v2", got[0].Payload.Metadata.Name)
+	assert.Equal(t, "id1", got[0].Payload.ClientSideID)
+	assert.Equal(t, int64(1), got[0].Payload.UserID)
+	assert.Equal(t, archivedAt, got[0].ArchivedAt)
+}
+
+func TestClientPrivateDataService_RestoreVersion(t *testing.T) {
+	archived := models.PrivateDataPayload{Type: models.Text, Metadata: "enc-meta-v2", Data: "enc-data-v2"}
+	history := []models.PrivateDataVersion{
+		{ClientSideID: "id1", Version: 3, Payload: models.PrivateDataPayload{Type: models.Text, Metadata: "enc-meta-v3"}},
+		{ClientSideID: "id1", Version: 2, Payload: archived},
+	}
+	current := models.PrivateData{
+		ClientSideID: "id1",
+		UserID:       1,
+		Version:      4,
+		Payload:      models.PrivateDataPayload{Type: models.Text, Metadata: "enc-meta-v4", Data: "enc-data-v4"},
+	}
+
+	t.Run("archived ciphertext becomes a new version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+		ctx := context.Background()
+
+		mockAdapter.EXPECT().GetVersionHistory(ctx, "id1").Return(history, nil)
+		mockCrypto.EXPECT().DecryptPayload(archived).Return(models.DecipheredPayload{Type: models.Text}, nil)
+		mockRepo.EXPECT().GetPrivateData(ctx, "id1", int64(1)).Return(current, nil)
+		// The archived payload is reused as-is: nothing is re-encrypted.
+		mockCrypto.EXPECT().ComputeHash(archived).Return("hash-v2", nil)
+
+		var stored models.PrivateData
+		mockRepo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, data models.PrivateData) error {
+			stored = data
+			return nil
+		})
+		var pushed models.UpdateRequest
+		mockAdapter.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req models.UpdateRequest) error {
+			pushed = req
+			return nil
+		})
+		mockRepo.EXPECT().IncrementVersion(ctx, "id1", int64(1)).Return(nil)
+
+		require.NoError(t, svc.RestoreVersion(ctx, 1, "id1", 2))
+
+		assert.Equal(t, archived, stored.Payload)
+		assert.Equal(t, "hash-v2", stored.Hash)
+		require.Len(t, pushed.PrivateDataUpdates, 1)
+		update := pushed.PrivateDataUpdates[0]
+		assert.Equal(t, int64(4), update.Version, "restore is based on the current version, not the archived one")
+		require.NotNil(t, update.FieldsUpdate.Metadata)
+		assert.Equal(t, archived.Metadata, *update.FieldsUpdate.Metadata)
+		require.NotNil(t, update.FieldsUpdate.Data)
+		assert.Equal(t, archived.Data, *update.FieldsUpdate.Data)
+	})
+
+	t.Run("server conflict leaves the local version unchanged", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+		ctx := context.Background()
+
+		mockAdapter.EXPECT().GetVersionHistory(ctx, "id1").Return(history, nil)
+		mockCrypto.EXPECT().DecryptPayload(archived).Return(models.DecipheredPayload{}, nil)
+		mockRepo.EXPECT().GetPrivateData(ctx, "id1", int64(1)).Return(current, nil)
+		mockCrypto.EXPECT().ComputeHash(archived).Return("hash-v2", nil)
+		mockRepo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).Return(nil)
+		mockAdapter.EXPECT().Update(ctx, gomock.Any()).Return(errors.New("conflict"))
+
+		err := svc.RestoreVersion(ctx, 1, "id1", 2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "update item on server")
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, _, mockAdapter, _ := newTestPrivateDataSvc(t, ctrl)
+		ctx := context.Background()
+
+		mockAdapter.EXPECT().GetVersionHistory(ctx, "id1").Return(history, nil)
+
+		require.ErrorIs(t, svc.RestoreVersion(ctx, 1, "id1", 1), ErrVersionNotInHistory)
+	})
+
+	t.Run("archived payload from another key is rejected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, _, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+		ctx := context.Background()
+
+		mockAdapter.EXPECT().GetVersionHistory(ctx, "id1").Return(history, nil)
+		mockCrypto.EXPECT().DecryptPayload(archived).Return(models.DecipheredPayload{}, errors.New("auth failed"))
+
+		err := svc.RestoreVersion(ctx, 1, "id1", 2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "decrypt archived version")
+	})
+}
+
 // ── Delete ───────────────────────────────────────────────────────────────────
 
 func TestClientPrivateDataService_Delete_Success(t *testing.T) {
@@ -384,3 +680,319 @@ func TestClientPrivateDataService_Delete_ServerError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "delete item on server")
 }
+
+// ── Binary size limit ────────────────────────────────────────────────────────
+
+const testMaxBinarySize = int64(1024)
+
+func TestValidateBinarySize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int64
+		maxSize int64
+		wantErr bool
+	}{
+		{name: "below limit", size: testMaxBinarySize - 1, maxSize: testMaxBinarySize},
+		{name: "exactly at limit", size: testMaxBinarySize, maxSize: testMaxBinarySize},
+		{name: "one byte over limit", size: testMaxBinarySize + 1, maxSize: testMaxBinarySize, wantErr: true},
+		{name: "limit disabled", size: 1 << 40, maxSize: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBinarySize(tt.size, tt.maxSize)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrBinaryTooLarge)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateBinarySize_MessageShowsLimitInMB(t *testing.T) {
+	err := ValidateBinarySize(10*1024*1024+1, 10*1024*1024)
+	require.Error(t, err)
+	assert.Equal(t, "файл слишком большой (max 10 MB)", err.Error())
+}
+
+func TestClientPrivateDataService_Create_BinarySizeBoundary(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int64
+		wantErr bool
+	}{
+		{name: "exactly at limit is uploaded", size: testMaxBinarySize},
+		{name: "one byte over limit is rejected", size: testMaxBinarySize + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+			ctx := context.Background()
+
+			plain := models.DecipheredPayload{
+				UserID:     1,
+				Type:       models.Binary,
+				Metadata:   models.Metadata{Name: "file"},
+				BinaryData: &models.BinaryData{FileName: "f.bin", Size: tt.size},
+			}
+
+			if !tt.wantErr {
+				encPayload := models.PrivateDataPayload{}
+				mockCrypto.EXPECT().EncryptPayload(plain).Return(encPayload, nil)
+				mockCrypto.EXPECT().ComputeHash(encPayload).Return("hash", nil)
+				mockRepo.EXPECT().SavePrivateData(ctx, int64(1), gomock.Any()).Return(nil)
+				mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
+			}
+
+			err := svc.Create(ctx, 1, plain)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrBinaryTooLarge)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClientPrivateDataService_Update_BinaryTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc, mockRepo, _, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+	ctx := context.Background()
+
+	prev := models.PrivateData{ClientSideID: "c1", UserID: 1, Payload: models.PrivateDataPayload{Type: models.Binary, Metadata: "meta"}}
+	mockRepo.EXPECT().GetPrivateData(ctx, "c1", int64(1)).Return(prev, nil)
+	mockCrypto.EXPECT().DecryptPayload(prev.Payload).Return(models.DecipheredPayload{
+		Type:       models.Binary,
+		Metadata:   models.Metadata{Name: "file"},
+		BinaryData: &models.BinaryData{Size: 10},
+	}, nil)
+
+	// Replacing the attachment with an oversized one is rejected.
+	err := svc.Update(ctx, models.DecipheredPayload{
+		ClientSideID: "c1",
+		UserID:       1,
+		Type:         models.Binary,
+		Metadata:     models.Metadata{Name: "file"},
+		BinaryData:   &models.BinaryData{Size: testMaxBinarySize + 1},
+	})
+	require.ErrorIs(t, err, ErrBinaryTooLarge)
+}
+
+func TestClientPrivateDataService_Update_OversizedLegacyItem(t *testing.T) {
+	attachment := &models.BinaryData{ID: "bin-1", FileName: "big.iso", Size: testMaxBinarySize + 1}
+	prev := models.PrivateData{ClientSideID: "c1", UserID: 1, Version: 3, Hash: "old", Payload: models.PrivateDataPayload{Type: models.Binary, Metadata: "meta", Data: "data"}}
+	stored := models.DecipheredPayload{Type: models.Binary, Metadata: models.Metadata{Name: "file"}, BinaryData: attachment}
+
+	t.Run("metadata-only edit is saved locally", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, mockRepo, _, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+		ctx := context.Background()
+
+		mockRepo.EXPECT().GetPrivateData(ctx, "c1", int64(1)).Return(prev, nil)
+		mockCrypto.EXPECT().DecryptPayload(prev.Payload).Return(stored, nil)
+		mockCrypto.EXPECT().DecryptPayloadOutdated(prev.Payload).Return(stored, false, nil)
+		mockCrypto.EXPECT().EncryptPayload(models.DecipheredPayload{Type: models.Binary, Metadata: models.Metadata{Name: "renamed"}}).
+			Return(models.PrivateDataPayload{Type: models.Binary, Metadata: "meta-renamed"}, nil)
+		mockCrypto.EXPECT().ComputeHash(gomock.Any()).Return("new", nil)
+		mockRepo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, item models.PrivateData) error {
+			assert.Equal(t, models.CipheredMetadata("meta-renamed"), item.Payload.Metadata)
+			assert.Equal(t, models.CipheredData("data"), item.Payload.Data)
+			assert.Equal(t, "new", item.Hash)
+			assert.Equal(t, int64(3), item.Version)
+			return nil
+		})
+		// The server adapter has no expectations: the item is not synced.
+
+		err := svc.Update(ctx, models.DecipheredPayload{
+			ClientSideID: "c1",
+			UserID:       1,
+			Type:         models.Binary,
+			Metadata:     models.Metadata{Name: " renamed "},
+			BinaryData:   attachment,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("changing notes is rejected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, mockRepo, _, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+		ctx := context.Background()
+
+		mockRepo.EXPECT().GetPrivateData(ctx, "c1", int64(1)).Return(prev, nil)
+		mockCrypto.EXPECT().DecryptPayload(prev.Payload).Return(stored, nil)
+
+		err := svc.Update(ctx, models.DecipheredPayload{
+			ClientSideID: "c1",
+			UserID:       1,
+			Type:         models.Binary,
+			Metadata:     models.Metadata{Name: "file"},
+			BinaryData:   attachment,
+			Notes:        &models.Notes{Notes: "new note"},
+		})
+		require.ErrorIs(t, err, ErrBinaryTooLarge)
+	})
+}
+
+// ── Item name ────────────────────────────────────────────────────────────────
+
+func TestNormalizeItemName(t *testing.T) {
+	atLimit := strings.Repeat("я", MaxItemNameLength)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "plain name", input: "GitHub", want: "GitHub"},
+		{name: "surrounding whitespace is trimmed", input: "  GitHub \t", want: "GitHub"},
+		{name: "single character", input: "x", want: "x"},
+		{name: "exactly at limit in characters", input: atLimit, want: atLimit},
+		{name: "at limit after trimming", input: "  " + atLimit + "  ", want: atLimit},
+		{name: "empty", input: "", wantErr: ErrItemNameEmpty},
+		{name: "whitespace only", input: " \t\n ", wantErr: ErrItemNameEmpty},
+		{name: "one character over limit", input: atLimit + "я", wantErr: ErrItemNameTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeItemName(tt.input)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClientPrivateDataService_Create_NormalizesName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+	ctx := context.Background()
+
+	want := models.DecipheredPayload{UserID: 1, Metadata: models.Metadata{Name: "bank"}}
+	encPayload := models.PrivateDataPayload{}
+
+	mockCrypto.EXPECT().EncryptPayload(want).Return(encPayload, nil)
+	mockCrypto.EXPECT().ComputeHash(encPayload).Return("hash", nil)
+	mockRepo.EXPECT().SavePrivateData(ctx, int64(1), gomock.Any()).Return(nil)
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
+
+	err := svc.Create(ctx, 1, models.DecipheredPayload{UserID: 1, Metadata: models.Metadata{Name: "  bank  "}})
+	require.NoError(t, err)
+}
+
+func TestClientPrivateDataService_Create_NormalizesFolder(t *testing.T) {
+	workEmail := "Work/Email"
+	tests := []struct {
+		name   string
+		folder string
+		want   *string
+	}{
+		{name: "path", folder: " Work // Email/ ", want: &workEmail},
+		{name: "separators only", folder: " / ", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+			ctx := context.Background()
+
+			want := models.DecipheredPayload{UserID: 1, Metadata: models.Metadata{Name: "bank", Folder: tt.want}}
+			encPayload := models.PrivateDataPayload{}
+
+			mockCrypto.EXPECT().EncryptPayload(want).Return(encPayload, nil)
+			mockCrypto.EXPECT().ComputeHash(encPayload).Return("hash", nil)
+			mockRepo.EXPECT().SavePrivateData(ctx, int64(1), gomock.Any()).Return(nil)
+			mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
+
+			folder := tt.folder
+			err := svc.Create(ctx, 1, models.DecipheredPayload{UserID: 1, Metadata: models.Metadata{Name: "bank", Folder: &folder}})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClientPrivateDataService_Create_StampsClockTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc, mockRepo, mockAdapter, mockCrypto := newTestPrivateDataSvc(t, ctrl)
+	ctx := context.Background()
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.(*clientPrivateDataService).clock = clock.NewFake(createdAt.In(time.FixedZone("MSK", 3*60*60)))
+
+	mockCrypto.EXPECT().EncryptPayload(gomock.Any()).Return(models.PrivateDataPayload{}, nil)
+	mockCrypto.EXPECT().ComputeHash(gomock.Any()).Return("hash", nil)
+	mockRepo.EXPECT().SavePrivateData(ctx, int64(1), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int64, item models.PrivateData) error {
+			require.NotNil(t, item.CreatedAt)
+			assert.Equal(t, createdAt, *item.CreatedAt, "creation time is taken from the clock in UTC")
+			return nil
+		})
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
+
+	require.NoError(t, svc.Create(ctx, 1, models.DecipheredPayload{UserID: 1, Metadata: models.Metadata{Name: "bank"}}))
+}
+
+func TestClientPrivateDataService_InvalidNameRejectedBeforeEncryption(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc, _, _, _ := newTestPrivateDataSvc(t, ctrl)
+	ctx := context.Background()
+
+	err := svc.Create(ctx, 1, models.DecipheredPayload{UserID: 1, Metadata: models.Metadata{Name: "   "}})
+	require.ErrorIs(t, err, ErrItemNameEmpty)
+
+	err = svc.Update(ctx, models.DecipheredPayload{
+		ClientSideID: "c1",
+		UserID:       1,
+		Metadata:     models.Metadata{Name: strings.Repeat("a", MaxItemNameLength+1)},
+	})
+	require.ErrorIs(t, err, ErrItemNameTooLong)
+}
+
+// BenchmarkClientPrivateDataService_ListLoad compares the full and the
+// metadata-only decryption of a 5000-item vault, the size the TUI list is
+// expected to open without a noticeable delay.
+func BenchmarkClientPrivateDataService_ListLoad(b *testing.B) {
+	const vaultSize = 5000
+
+	keyChain := crypto.NewKeyChainService()
+	dek, err := keyChain.GenerateDEK()
+	require.NoError(b, err)
+	cryptoSvc := NewClientCryptoService(keyChain)
+	cryptoSvc.SetEncryptionKey(dek)
+
+	notes := strings.Repeat("заметка ", 64)
+	stored := make([]models.PrivateData, vaultSize)
+	for i := range stored {
+		payload, encErr := cryptoSvc.EncryptPayload(models.DecipheredPayload{
+			Type:      models.LoginPassword,
+			Metadata:  models.Metadata{Name: "item"},
+			LoginData: &models.LoginData{Username: "user", Password: "secret"},
+			Notes:     &models.Notes{Notes: notes},
+		})
+		require.NoError(b, encErr)
+		stored[i] = models.PrivateData{ClientSideID: "id", UserID: 1, Payload: payload}
+	}
+
+	ctrl := gomock.NewController(b)
+	repo := mock.NewMockLocalPrivateDataRepository(ctrl)
+	repo.EXPECT().GetAllPrivateData(gomock.Any(), int64(1)).Return(stored, nil).AnyTimes()
+	svc := NewClientPrivateDataService(&store.ClientStorages{PrivateDataRepository: repo}, nil, cryptoSvc, testMaxBinarySize, 0, "", clock.Real{})
+
+	b.Run("GetAll", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = svc.GetAll(context.Background(), 1)
+		}
+	})
+	b.Run("GetAllMeta", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = svc.GetAllMeta(context.Background(), 1)
+		}
+	})
+}
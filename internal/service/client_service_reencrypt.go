@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+)
+
+// ReencryptOutdated implements ClientPrivateDataService. Deleted items are
+// skipped: their payload is never read again. Each outdated item goes
+// through pushUpdate, exactly like an edit: the item is re-encrypted
+// locally first, and one the server rejects with a version conflict is
+// counted out and left to the next sync, like a conflicting edit.
+func (p *clientPrivateDataService) ReencryptOutdated(ctx context.Context, userID int64) (int, error) {
+	stored, err := p.localStore.PrivateDataRepository.GetAllPrivateData(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("get all local items: %w", err)
+	}
+
+	migrated := 0
+	for _, item := range stored {
+		if item.Deleted {
+			continue
+		}
+		plain, outdated, err := p.crypto.DecryptPayloadOutdated(item.Payload)
+		if err != nil {
+			return migrated, fmt.Errorf("decrypt item %s: %w", item.ClientSideID, err)
+		}
+		if !outdated {
+			continue
+		}
+
+		enc, err := p.crypto.EncryptPayload(plain)
+		if err != nil {
+			return migrated, fmt.Errorf("encrypt item %s: %w", item.ClientSideID, err)
+		}
+		if err = p.pushUpdate(ctx, item, enc); err != nil {
+			if errors.Is(err, adapter.ErrConflict) {
+				continue
+			}
+			return migrated, fmt.Errorf("re-encrypt item %s: %w", item.ClientSideID, err)
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/crypto"
+	"github.com/MKhiriev/go-pass-keeper/internal/mock"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// sealFormatV1 encrypts v the way clients wrote blobs before
+// [crypto.FormatVersion2]: version ‖ nonce ‖ ciphertext under the DEK itself.
+func sealFormatV1(t *testing.T, dek []byte, v any) string {
+	t.Helper()
+	plaintext, err := json.Marshal(v)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(dek)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	header := []byte{crypto.FormatVersion1}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+	blob := append(append(header, nonce...), gcm.Seal(nil, nonce, plaintext, header)...)
+	return base64.StdEncoding.EncodeToString(blob)
+}
+
+// formatV1Payload returns a login payload with every field in the v1 format.
+func formatV1Payload(t *testing.T, dek []byte, name, password string) models.PrivateDataPayload {
+	t.Helper()
+	notes := models.CipheredNotes(sealFormatV1(t, dek, models.Notes{Notes: "заметка"}))
+	return models.PrivateDataPayload{
+		Type:     models.LoginPassword,
+		Metadata: models.CipheredMetadata(sealFormatV1(t, dek, models.Metadata{Name: name})),
+		Data:     models.CipheredData(sealFormatV1(t, dek, dataPayload{LoginData: &models.LoginData{Username: "user", Password: password}})),
+		Notes:    &notes,
+	}
+}
+
+// formatVersion returns the version byte of a ciphered field.
+func formatVersion(t *testing.T, enc string) byte {
+	t.Helper()
+	blob, err := base64.StdEncoding.DecodeString(enc)
+	require.NoError(t, err)
+	require.NotEmpty(t, blob)
+	return blob[0]
+}
+
+func newReencryptTestCrypto(t *testing.T) (ClientCryptoService, []byte) {
+	t.Helper()
+	keyChain := crypto.NewKeyChainService()
+	dek, err := keyChain.GenerateDEK()
+	require.NoError(t, err)
+	cryptoSvc := NewClientCryptoService(keyChain)
+	cryptoSvc.SetEncryptionKey(dek)
+	return cryptoSvc, dek
+}
+
+func TestClientCryptoService_DecryptPayloadOutdated(t *testing.T) {
+	cryptoSvc, dek := newReencryptTestCrypto(t)
+
+	plain, outdated, err := cryptoSvc.DecryptPayloadOutdated(formatV1Payload(t, dek, "Банк", "secret"))
+	require.NoError(t, err)
+	assert.True(t, outdated)
+	assert.Equal(t, "Банк", plain.Metadata.Name)
+	assert.Equal(t, "secret", plain.LoginData.Password)
+	require.NotNil(t, plain.Notes)
+	assert.Equal(t, "заметка", plain.Notes.Notes)
+
+	current, err := cryptoSvc.EncryptPayload(plain)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.FormatVersion2, formatVersion(t, string(current.Data)))
+	_, outdated, err = cryptoSvc.DecryptPayloadOutdated(current)
+	require.NoError(t, err)
+	assert.False(t, outdated)
+}
+
+func TestClientPrivateDataService_SetFavorite_MigratesOutdatedPayload(t *testing.T) {
+	ctx := context.Background()
+	cryptoSvc, dek := newReencryptTestCrypto(t)
+	stored := models.PrivateData{ClientSideID: "id1", UserID: 1, Version: 2, Payload: formatV1Payload(t, dek, "Банк", "secret")}
+
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockLocalPrivateDataRepository(ctrl)
+	serverAdapter := mock.NewMockServerAdapter(ctrl)
+	svc := NewClientPrivateDataService(&store.ClientStorages{PrivateDataRepository: repo}, serverAdapter, cryptoSvc, testMaxBinarySize, 0, "", clock.Real{})
+
+	repo.EXPECT().GetPrivateData(ctx, "id1", int64(1)).Return(stored, nil)
+	repo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, data models.PrivateData) error {
+		stored = data
+		return nil
+	})
+	var pushed models.UpdateRequest
+	serverAdapter.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req models.UpdateRequest) error {
+		pushed = req
+		return nil
+	})
+	repo.EXPECT().IncrementVersion(ctx, "id1", int64(1)).Return(nil)
+
+	require.NoError(t, svc.SetFavorite(ctx, 1, "id1", true))
+
+	assert.Equal(t, crypto.FormatVersion2, formatVersion(t, string(stored.Payload.Metadata)))
+	assert.Equal(t, crypto.FormatVersion2, formatVersion(t, string(stored.Payload.Data)))
+	require.NotNil(t, stored.Payload.Notes)
+	assert.Equal(t, crypto.FormatVersion2, formatVersion(t, string(*stored.Payload.Notes)))
+
+	plain, outdated, err := cryptoSvc.DecryptPayloadOutdated(stored.Payload)
+	require.NoError(t, err)
+	assert.False(t, outdated)
+	assert.True(t, plain.Metadata.Favorite)
+	assert.Equal(t, "secret", plain.LoginData.Password)
+
+	require.Len(t, pushed.PrivateDataUpdates, 1)
+	require.NotNil(t, pushed.PrivateDataUpdates[0].FieldsUpdate.Data)
+	assert.Equal(t, stored.Payload.Data, *pushed.PrivateDataUpdates[0].FieldsUpdate.Data)
+}
+
+func TestClientPrivateDataService_ReencryptOutdated(t *testing.T) {
+	ctx := context.Background()
+	cryptoSvc, dek := newReencryptTestCrypto(t)
+
+	current, err := cryptoSvc.EncryptPayload(models.DecipheredPayload{
+		Type:      models.LoginPassword,
+		Metadata:  models.Metadata{Name: "Почта"},
+		LoginData: &models.LoginData{Username: "user", Password: "mail"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		updateErr    error
+		wantMigrated int
+	}{
+		{name: "outdated item is migrated", wantMigrated: 1},
+		{name: "conflicting item is skipped", updateErr: adapter.ErrConflict, wantMigrated: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := []models.PrivateData{
+				{ClientSideID: "old", UserID: 1, Version: 1, Payload: formatV1Payload(t, dek, "Банк", "secret")},
+				{ClientSideID: "new", UserID: 1, Version: 1, Payload: current},
+				{ClientSideID: "gone", UserID: 1, Version: 1, Deleted: true, Payload: formatV1Payload(t, dek, "Старое", "x")},
+			}
+
+			ctrl := gomock.NewController(t)
+			repo := mock.NewMockLocalPrivateDataRepository(ctrl)
+			serverAdapter := mock.NewMockServerAdapter(ctrl)
+			svc := NewClientPrivateDataService(&store.ClientStorages{PrivateDataRepository: repo}, serverAdapter, cryptoSvc, testMaxBinarySize, 0, "", clock.Real{})
+
+			repo.EXPECT().GetAllPrivateData(ctx, int64(1)).Return(items, nil)
+			var saved models.PrivateData
+			repo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, data models.PrivateData) error {
+				saved = data
+				return nil
+			})
+			serverAdapter.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req models.UpdateRequest) error {
+				require.Len(t, req.PrivateDataUpdates, 1)
+				assert.Equal(t, "old", req.PrivateDataUpdates[0].ClientSideID)
+				return tt.updateErr
+			})
+			if tt.updateErr == nil {
+				repo.EXPECT().IncrementVersion(ctx, "old", int64(1)).Return(nil)
+			}
+
+			migrated, err := svc.ReencryptOutdated(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMigrated, migrated)
+
+			assert.Equal(t, "old", saved.ClientSideID)
+			assert.Equal(t, crypto.FormatVersion2, formatVersion(t, string(saved.Payload.Data)))
+			plain, err := cryptoSvc.DecryptPayload(saved.Payload)
+			require.NoError(t, err)
+			assert.Equal(t, "secret", plain.LoginData.Password)
+		})
+	}
+}
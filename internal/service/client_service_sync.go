@@ -7,37 +7,170 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"time"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/MKhiriev/go-pass-keeper/migrations"
 	"github.com/MKhiriev/go-pass-keeper/models"
 )
 
 type clientSyncService struct {
 	localStore *store.ClientStorages
 	adapter    adapter.ServerAdapter
+	crypto     ClientCryptoService
 	planner    SyncService
+
+	// schemaVersion is the newest server schema this client understands.
+	schemaVersion int64
+
+	// mode selects the plan buckets ExecutePlan carries out.
+	mode models.SyncMode
+
+	// conflicts selects how update conflicts are handled.
+	conflicts models.ConflictPolicy
+
+	// clientIDGenerator assigns the IDs of local copies made when the user
+	// keeps both versions of a conflicting item.
+	clientIDGenerator *utils.UUIDGenerator
+
+	// events receives the machine-readable sync event stream; nil disables it.
+	events *syncEventWriter
+
+	// inflight keeps FullSync calls for the same user from overlapping.
+	inflight syncFlights
+
+	// breaker stops FullSync from contacting a server that keeps failing on
+	// the network level; nil disables it.
+	breaker *syncBreaker
+
+	// clock stamps the sync time and the local copies of conflicting items.
+	clock clock.Clock
+
+	// downloadBatchSize caps the number of items requested in one download.
+	downloadBatchSize int
+	// maxBinarySize keeps items with larger attachments on the client.
+	maxBinarySize int64
+}
+
+// SyncPolicy configures how the client sync service carries out a plan.
+type SyncPolicy struct {
+	// Mode restricts sync to one direction; see [models.SyncMode].
+	Mode models.SyncMode
+	// Conflicts selects how update conflicts are handled; see
+	// [models.ConflictPolicy].
+	Conflicts models.ConflictPolicy
+	// ClientIDPrefix is prepended to the client-side IDs of the local copies
+	// made by [models.ConflictKeepBoth], like to those of new items.
+	ClientIDPrefix string
+	// BreakerThreshold is the number of consecutive syncs failing on the
+	// network level after which FullSync stops contacting the server for
+	// BreakerCooldown; zero or less disables the breaker.
+	BreakerThreshold int
+	// BreakerCooldown is how long FullSync fails fast with
+	// [ErrServerUnavailable] before probing the server again.
+	BreakerCooldown time.Duration
+	// Clock is the time source of the sync; nil means [clock.Real].
+	Clock clock.Clock
+	// DownloadBatch is the largest number of items requested in one
+	// download; it must not exceed the server's max_client_side_ids. Zero
+	// or less means [config.DefaultMaxClientSideIDs].
+	DownloadBatch int
+	// MaxBinarySize is the Binary attachment limit of the client; local
+	// items above it are neither uploaded nor pushed as updates. Zero or
+	// less disables the check.
+	MaxBinarySize int64
 }
 
 // NewClientSyncService constructs a clientSyncService wired to the provided local
-// store and server adapter. An in-memory SyncService is created internally to build
-// sync plans.
-func NewClientSyncService(localStore *store.ClientStorages, serverAdapter adapter.ServerAdapter) ClientSyncService {
+// store, server adapter, and crypto service. The crypto service is used by
+// the duplicate search, which compares decrypted payloads, and to show and
+// copy the versions of a conflicting item. An in-memory SyncService is
+// created internally to build sync plans. The newest server schema the
+// client accepts is the latest migration embedded in this build. policy
+// selects the sync direction and the conflict handling. When events is not
+// nil, every executed plan is reported to it as JSON lines of
+// [models.SyncEvent].
+func NewClientSyncService(localStore *store.ClientStorages, serverAdapter adapter.ServerAdapter, cryptoService ClientCryptoService, policy SyncPolicy, events io.Writer) ClientSyncService {
+	clk := policy.Clock
+	if clk == nil {
+		clk = clock.Real{}
+	}
+	downloadBatch := policy.DownloadBatch
+	if downloadBatch <= 0 {
+		downloadBatch = config.DefaultMaxClientSideIDs
+	}
 	return &clientSyncService{
-		localStore: localStore,
-		adapter:    serverAdapter,
-		planner:    NewSyncService(),
+		localStore:        localStore,
+		adapter:           serverAdapter,
+		crypto:            cryptoService,
+		planner:           NewSyncService(),
+		schemaVersion:     migrations.LatestVersion(),
+		mode:              policy.Mode,
+		conflicts:         policy.Conflicts,
+		clientIDGenerator: utils.NewPrefixedUUIDGenerator(policy.ClientIDPrefix),
+		events:            newSyncEventWriter(events, clk),
+		breaker:           newSyncBreaker(policy.BreakerThreshold, policy.BreakerCooldown, clk),
+		clock:             clk,
+		downloadBatchSize: downloadBatch,
+		maxBinarySize:     policy.MaxBinarySize,
 	}
 }
 
-// FullSync implements ClientSyncService. It fetches state descriptors from both the
-// server and the local store, builds a sync plan, and executes it. Returns an error
-// if userID is invalid, any I/O step fails, or plan execution fails.
+// FullSync implements ClientSyncService. It first checks that the server
+// schema is not newer than the client understands, then fetches state
+// descriptors from both the server and the local store, builds a sync plan, and
+// executes it. Before the plan touches the local store, a snapshot of it is
+// taken when backups are configured; a failed snapshot aborts the sync. The
+// sync time is recorded only after the plan has been executed
+// successfully. Returns [ErrIncompatibleServerSchema] (wrapped) if the server is
+// ahead, or an error if userID is invalid, any I/O step fails, or plan
+// execution fails.
+//
+// At most one FullSync per user runs at a time: a call made while another
+// one for the same user is in progress, e.g. a manual sync during the
+// background one, waits for it and returns its result.
+//
+// After [SyncPolicy.BreakerThreshold] consecutive syncs failed on the
+// network level, FullSync returns [ErrServerUnavailable] at once for
+// [SyncPolicy.BreakerCooldown]; the first sync after that probes the server
+// again.
 func (s *clientSyncService) FullSync(ctx context.Context, userID int64) error {
 	if userID <= 0 {
 		return fmt.Errorf("full sync: invalid user id")
 	}
 
+	return s.inflight.do(ctx, userID, func() error {
+		return s.breaker.guard(func() error {
+			return s.fullSync(ctx, userID)
+		})
+	})
+}
+
+// BreakerStatus implements ClientSyncService.
+func (s *clientSyncService) BreakerStatus() models.SyncBreakerStatus {
+	return s.breaker.status()
+}
+
+// Ping implements ClientSyncService.
+func (s *clientSyncService) Ping(ctx context.Context) error {
+	err := s.adapter.Ping(ctx)
+	s.breaker.observe(err)
+	return err
+}
+
+// fullSync carries out one FullSync run; see [clientSyncService.FullSync].
+func (s *clientSyncService) fullSync(ctx context.Context, userID int64) error {
+	hardDelete, err := s.checkServerSchema(ctx)
+	if err != nil {
+		return err
+	}
+
 	serverStates, err := s.adapter.GetServerStates(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("get server states: %w", err)
@@ -48,75 +181,205 @@ func (s *clientSyncService) FullSync(ctx context.Context, userID int64) error {
 		return fmt.Errorf("get local states: %w", err)
 	}
 
-	plan, err := s.planner.BuildSyncPlan(ctx, serverStates, clientStates)
+	plan, err := s.planner.BuildSyncPlan(ctx, serverStates, clientStates, hardDelete)
 	if err != nil {
 		return fmt.Errorf("build sync plan: %w", err)
 	}
 
+	pending, err := s.localStore.SyncStateRepository.GetPendingConflicts(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get pending conflicts: %w", err)
+	}
+	plan = withoutConflicts(plan, pending)
+
+	if plan, err = s.withoutOversized(ctx, plan, userID); err != nil {
+		return err
+	}
+
 	idx := make(map[string]models.PrivateDataState, len(serverStates))
 	for _, st := range serverStates {
 		idx[st.ClientSideID] = st
 	}
 
+	if changesLocalStore(s.mode.Filter(plan), s.conflicts) {
+		if _, err = s.localStore.Backup(ctx, s.clock.Now()); err != nil {
+			return fmt.Errorf("full sync: %w", err)
+		}
+	}
+
 	if err = s.ExecutePlan(ctx, plan, userID); err != nil {
 		return fmt.Errorf("execute sync plan: %w", err)
 	}
 
+	if err = s.localStore.SyncStateRepository.SetLastSyncedAt(ctx, userID, s.clock.Now()); err != nil {
+		return fmt.Errorf("save last synced time: %w", err)
+	}
+
 	return nil
 }
 
+// checkServerSchema refuses to sync with a server whose schema version is
+// ahead of s.schemaVersion and reports whether the server runs in hard-delete
+// mode. A server without the schema endpoint predates the check and is
+// accepted as a soft-delete server.
+func (s *clientSyncService) checkServerSchema(ctx context.Context) (hardDelete bool, err error) {
+	sv, err := s.adapter.GetSchemaVersion(ctx)
+	if errors.Is(err, adapter.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get server schema version: %w", err)
+	}
+
+	if sv.SchemaVersion > s.schemaVersion {
+		return false, fmt.Errorf("%w (сервер: %d, клиент: %d)", ErrIncompatibleServerSchema, sv.SchemaVersion, s.schemaVersion)
+	}
+	return sv.HardDelete, nil
+}
+
+// ServerVersion implements ClientSyncService.
+func (s *clientSyncService) ServerVersion(ctx context.Context) (string, error) {
+	version, err := s.adapter.GetServerVersion(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get server version: %w", err)
+	}
+	return version, nil
+}
+
+// LastSyncedAt implements ClientSyncService. It reads the last successful sync
+// time from the local store. Returns an error if userID is invalid or the read fails.
+func (s *clientSyncService) LastSyncedAt(ctx context.Context, userID int64) (time.Time, error) {
+	if userID <= 0 {
+		return time.Time{}, fmt.Errorf("last synced at: invalid user id")
+	}
+
+	syncedAt, err := s.localStore.SyncStateRepository.GetLastSyncedAt(ctx, userID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get last synced time: %w", err)
+	}
+
+	return syncedAt, nil
+}
+
 // ExecutePlan implements ClientSyncService. It carries out all actions in plan in
-// the following order: Download, Upload, Update, DeleteClient, DeleteServer. Returns
-// an error if userID is invalid or any individual action fails.
+// the following order: Download, Upload, Update, DeleteClient, DeleteServer.
+// Buckets excluded by the configured [models.SyncMode] are skipped. Each
+// operation and the final outcome are reported to the sync event stream, if
+// enabled. Returns an error if userID is invalid or any individual action fails.
+// Under [models.ConflictManual] update conflicts do not stop the plan; they
+// are recorded in the local store and returned, together with the ones
+// earlier syncs left unresolved, in a [*PendingConflictsError] once it is
+// done.
 func (s *clientSyncService) ExecutePlan(ctx context.Context, plan models.SyncPlan, userID int64) error {
 	if userID <= 0 {
 		return fmt.Errorf("execute sync plan: invalid user id")
 	}
 
+	run := s.events.begin()
+	err := s.executePlan(ctx, s.mode.Filter(plan), userID, run)
+	run.finish(err)
+
+	return err
+}
+
+func (s *clientSyncService) executePlan(ctx context.Context, plan models.SyncPlan, userID int64, run *syncEventRun) error {
 	if len(plan.Download) > 0 {
-		if err := s.downloadFromServer(ctx, plan, userID); err != nil {
+		if err := s.downloadFromServer(ctx, plan, userID, run); err != nil {
 			return err
 		}
 	}
 
+	// Items the server rejects do not stop the rest of the plan; they stay
+	// without a server ID, so the next sync uploads them again.
+	var uploadErr error
 	if len(plan.Upload) > 0 {
-		if err := s.uploadToServer(ctx, plan, userID); err != nil {
+		failed, err := s.uploadToServer(ctx, plan, userID)
+		if err != nil {
+			run.record(models.SyncEventUpload, err, collectIDs(plan.Upload)...)
 			return err
 		}
+		for _, id := range collectIDs(plan.Upload) {
+			run.record(models.SyncEventUpload, failed[id], id)
+		}
+		if len(failed) > 0 {
+			uploadErr = fmt.Errorf("%w: %d из %d", ErrUploadPartiallyFailed, len(failed), len(plan.Upload))
+		}
 	}
 
+	var conflicts []models.VersionConflict
 	for _, st := range plan.Update {
-		if err := s.updateServerData(ctx, st.ClientSideID, userID); err != nil {
+		err := s.updateServerData(ctx, st.ClientSideID, userID)
+		run.record(models.SyncEventUpdate, err, st.ClientSideID)
+		if pending, ok := PendingConflictsOf(err); ok {
+			conflicts = append(conflicts, pending...)
+			continue
+		}
+		if err != nil {
 			return err
 		}
 	}
 
 	for _, st := range plan.DeleteClient {
-		if err := s.deleteFromClient(ctx, st.ClientSideID, st.Version); err != nil {
+		err := s.deleteFromClient(ctx, st.ClientSideID, st.Version)
+		run.record(models.SyncEventDeleteClient, err, st.ClientSideID)
+		if err != nil {
 			return err
 		}
 	}
 
 	for _, st := range plan.DeleteServer {
-		if err := s.deleteFromServer(ctx, st.ClientSideID, userID); err != nil {
+		err := s.deleteFromServer(ctx, st.ClientSideID, userID)
+		run.record(models.SyncEventDeleteServer, err, st.ClientSideID)
+		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	if len(conflicts) > 0 {
+		if err := s.localStore.SyncStateRepository.SavePendingConflicts(ctx, userID, conflicts...); err != nil {
+			return fmt.Errorf("save pending conflicts: %w", err)
+		}
+	}
+	pending, err := s.localStore.SyncStateRepository.GetPendingConflicts(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get pending conflicts: %w", err)
+	}
+	if len(pending) > 0 {
+		return errors.Join(uploadErr, &PendingConflictsError{Conflicts: pending})
+	}
+	return uploadErr
 }
 
-func (s *clientSyncService) downloadFromServer(ctx context.Context, plan models.SyncPlan, userID int64) error {
+// downloadFromServer fetches the planned items in batches of at most
+// s.downloadBatchSize IDs, the server's per-request cap, and saves each batch
+// locally as soon as it arrives. The outcome of every batch is recorded in
+// run.
+func (s *clientSyncService) downloadFromServer(ctx context.Context, plan models.SyncPlan, userID int64, run *syncEventRun) error {
 	ids := collectIDs(plan.Download)
 
+	for batch := range slices.Chunk(ids, s.downloadBatchSize) {
+		err := s.downloadBatch(ctx, batch, userID)
+		run.record(models.SyncEventDownload, err, batch...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *clientSyncService) downloadBatch(ctx context.Context, batch []string, userID int64) error {
 	downloadedData, err := s.adapter.Download(ctx, models.DownloadRequest{
 		UserID:        userID,
-		ClientSideIDs: ids,
-		Length:        len(ids),
+		ClientSideIDs: batch,
+		Length:        len(batch),
 	})
 	if err != nil {
 		return fmt.Errorf("error sync downloading data from server: %w", err)
 	}
+	if err = s.verifyIntegrity(downloadedData); err != nil {
+		return err
+	}
 
 	if err = s.localStore.PrivateDataRepository.SavePrivateData(ctx, userID, downloadedData...); err != nil {
 		return fmt.Errorf("error saving downloaded items locally: %w", err)
@@ -125,28 +388,52 @@ func (s *clientSyncService) downloadFromServer(ctx context.Context, plan models.
 	return nil
 }
 
-func (s *clientSyncService) uploadToServer(ctx context.Context, plan models.SyncPlan, userID int64) error {
+// uploadToServer sends the planned items in one best-effort upload and stores
+// the server fields of the accepted ones. It returns the error of every item
+// the server rejected, keyed by ClientSideID; the error result is reserved for
+// failures of the request as a whole.
+func (s *clientSyncService) uploadToServer(ctx context.Context, plan models.SyncPlan, userID int64) (map[string]error, error) {
 	payload := make([]*models.PrivateData, 0, len(plan.Upload))
 
 	for _, st := range plan.Upload {
 		item, err := s.localStore.PrivateDataRepository.GetPrivateData(ctx, st.ClientSideID, userID)
 		if err != nil {
-			return fmt.Errorf("error getting client item for upload %s: %w", st.ClientSideID, err)
+			return nil, fmt.Errorf("error getting client item for upload %s: %w", st.ClientSideID, err)
 		}
 
 		it := item
 		payload = append(payload, &it)
 	}
 
-	if err := s.adapter.Upload(ctx, models.UploadRequest{
+	uploaded, err := s.adapter.Upload(ctx, models.UploadRequest{
 		UserID:          userID,
 		PrivateDataList: payload,
 		Length:          len(payload),
-	}); err != nil {
-		return fmt.Errorf("upload items in sync plan: %w", err)
+		BestEffort:      true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("upload items in sync plan: %w", err)
 	}
 
-	return nil
+	if len(uploaded.Items) > 0 {
+		if err = s.localStore.PrivateDataRepository.SetServerFields(ctx, userID, uploaded.Items...); err != nil {
+			return nil, fmt.Errorf("store server fields of uploaded items: %w", err)
+		}
+	}
+
+	var failed map[string]error
+	for _, f := range uploaded.Failed {
+		if failed == nil {
+			failed = make(map[string]error, len(uploaded.Failed))
+		}
+		if f.Field != "" {
+			failed[f.ClientSideID] = fmt.Errorf("%w: field %s: %s (HTTP %d)", ErrUploadRejected, f.Field, f.Error, f.Status)
+			continue
+		}
+		failed[f.ClientSideID] = fmt.Errorf("%w: %s (HTTP %d)", ErrUploadRejected, f.Error, f.Status)
+	}
+
+	return failed, nil
 }
 
 func (s *clientSyncService) updateServerData(ctx context.Context, clientSideID string, userID int64) error {
@@ -155,13 +442,32 @@ func (s *clientSyncService) updateServerData(ctx context.Context, clientSideID s
 		return fmt.Errorf("load local item for update %s: %w", clientSideID, err)
 	}
 
+	req := updateRequestOf(userID, item, item.Version)
+	err = retryLocked(ctx, func() error { return s.adapter.Update(ctx, req) })
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, adapter.ErrConflict) {
+		return fmt.Errorf("update server item %s: %w", clientSideID, err)
+	}
+
+	conflict := conflictOf(err, models.ConflictOnUpdate, clientSideID)
+	if s.conflicts == models.ConflictManual {
+		return &PendingConflictsError{Conflicts: []models.VersionConflict{conflict}}
+	}
+	return s.refreshConflict(ctx, userID, conflict)
+}
+
+// updateRequestOf builds the single-item update of userID that replaces the
+// server's copy of item, version baseVersion, with the payload of item.
+func updateRequestOf(userID int64, item models.PrivateData, baseVersion int64) models.UpdateRequest {
 	meta := item.Payload.Metadata
 	data := item.Payload.Data
-	req := models.UpdateRequest{
+	return models.UpdateRequest{
 		UserID: userID,
 		PrivateDataUpdates: []models.PrivateDataUpdate{{
 			ClientSideID:      item.ClientSideID,
-			Version:           item.Version,
+			Version:           baseVersion,
 			UpdatedRecordHash: item.Hash,
 			FieldsUpdate: models.FieldsUpdate{
 				Metadata:         &meta,
@@ -170,17 +476,32 @@ func (s *clientSyncService) updateServerData(ctx context.Context, clientSideID s
 				AdditionalFields: item.Payload.AdditionalFields,
 			},
 		}},
+		Length: 1,
 	}
+}
 
-	err = s.adapter.Update(ctx, req)
-	if err == nil {
-		return nil
-	}
-	if !errors.Is(err, adapter.ErrConflict) {
-		return fmt.Errorf("update server item %s: %w", clientSideID, err)
+// syncLockRetries is how many times an update or delete rejected with
+// [adapter.ErrLocked] is sent again before the error is returned.
+const syncLockRetries = 3
+
+// syncLockRetryDelay is the pause before the first retry of a locked call;
+// every further retry waits one more delay.
+var syncLockRetryDelay = 500 * time.Millisecond
+
+// retryLocked runs call and repeats it while the server rejects it with
+// [adapter.ErrLocked] because another device of the user is syncing. The
+// server changes nothing in that case, so repeating is safe.
+func retryLocked(ctx context.Context, call func() error) error {
+	err := call()
+	for attempt := 1; attempt <= syncLockRetries && errors.Is(err, adapter.ErrLocked); attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * syncLockRetryDelay):
+		}
+		err = call()
 	}
-
-	return s.refreshConflict(ctx, userID, clientSideID)
+	return err
 }
 
 func (s *clientSyncService) deleteFromClient(ctx context.Context, clientSideID string, version int64) error {
@@ -200,9 +521,9 @@ func (s *clientSyncService) deleteFromServer(ctx context.Context, clientSideID s
 	req := models.DeleteRequest{UserID: userID, DeleteEntries: []models.DeleteEntry{{
 		ClientSideID: clientSideID,
 		Version:      item.Version,
-	}}}
+	}}, Length: 1}
 
-	err = s.adapter.Delete(ctx, req)
+	err = retryLocked(ctx, func() error { return s.adapter.Delete(ctx, req) })
 	if err == nil {
 		return nil
 	}
@@ -210,25 +531,119 @@ func (s *clientSyncService) deleteFromServer(ctx context.Context, clientSideID s
 		return fmt.Errorf("delete server item %s: %w", clientSideID, err)
 	}
 
-	return s.refreshConflict(ctx, userID, clientSideID)
+	return s.refreshConflict(ctx, userID, conflictOf(err, models.ConflictOnDelete, clientSideID))
 }
 
-func (s *clientSyncService) refreshConflict(ctx context.Context, userID int64, clientSideID string) error {
+// conflictOf returns the conflict described by the [adapter.ErrConflict] err
+// of a single-item call on clientSideID. Details the server did not report
+// are filled in from the call.
+func conflictOf(err error, operation models.ConflictOperation, clientSideID string) models.VersionConflict {
+	conflict, _ := ConflictOf(err)
+	if conflict.Operation == "" {
+		conflict.Operation = operation
+	}
+	if conflict.ClientSideID == "" {
+		conflict.ClientSideID = clientSideID
+	}
+	return conflict
+}
+
+// refreshConflict replaces the local copy of the conflicting item with the
+// server's current version, discarding the rejected local change.
+func (s *clientSyncService) refreshConflict(ctx context.Context, userID int64, conflict models.VersionConflict) error {
+	clientSideID := conflict.ClientSideID
 	req := models.DownloadRequest{UserID: userID, ClientSideIDs: []string{clientSideID}, Length: 1}
 	items, err := s.adapter.Download(ctx, req)
 	if err != nil {
-		return fmt.Errorf("download conflict item %s: %w", clientSideID, err)
+		return fmt.Errorf("download conflict item %s after %s conflict (server version %d): %w",
+			clientSideID, conflict.Operation, conflict.ServerVersion, err)
 	}
 	if len(items) == 0 {
 		return nil
 	}
+	if err = s.verifyIntegrity(items); err != nil {
+		return err
+	}
 
 	if err = s.localStore.PrivateDataRepository.SavePrivateData(ctx, userID, items...); err != nil {
-		return fmt.Errorf("save conflict item %s: %w", clientSideID, err)
+		return fmt.Errorf("save conflict item %s after %s conflict (server version %d): %w",
+			clientSideID, conflict.Operation, conflict.ServerVersion, err)
+	}
+	return nil
+}
+
+// verifyIntegrity recomputes the hash of every downloaded item over its
+// encrypted payload, the way the client computed it before upload, and
+// compares it with the Hash stored on the server. A mismatch means the
+// payload was corrupted or altered after it was written and yields
+// [ErrIntegrityCheckFailed], so that nothing of the batch is saved. Items
+// without a hash, written before hashes were introduced, cannot be checked
+// and are accepted.
+func (s *clientSyncService) verifyIntegrity(items []models.PrivateData) error {
+	for _, item := range items {
+		if item.Hash == "" {
+			continue
+		}
+		hash, err := s.crypto.ComputeHash(item.Payload)
+		if err != nil {
+			return fmt.Errorf("compute hash of downloaded item %s: %w", item.ClientSideID, err)
+		}
+		if hash != item.Hash {
+			return fmt.Errorf("%w: item %s", ErrIntegrityCheckFailed, item.ClientSideID)
+		}
 	}
 	return nil
 }
 
+// changesLocalStore reports whether carrying out plan can overwrite or delete
+// local items, which is when FullSync backs up the local store first.
+// Downloads and client-side deletions always do; updates only under
+// [models.ConflictServerWins], where a conflict replaces the local version
+// with the server's. Uploads merely record server IDs.
+func changesLocalStore(plan models.SyncPlan, conflicts models.ConflictPolicy) bool {
+	if len(plan.Download) > 0 || len(plan.DeleteClient) > 0 {
+		return true
+	}
+	return len(plan.Update) > 0 && conflicts != models.ConflictManual
+}
+
+// withoutOversized drops from the uploads and updates of plan the local
+// Binary items whose attachment exceeds s.maxBinarySize. Such items predate
+// the limit; the detail view marks them as not synced and they stay on this
+// device until the attachment is replaced.
+func (s *clientSyncService) withoutOversized(ctx context.Context, plan models.SyncPlan, userID int64) (models.SyncPlan, error) {
+	if s.maxBinarySize <= 0 || len(plan.Upload)+len(plan.Update) == 0 {
+		return plan, nil
+	}
+
+	var checkErr error
+	oversized := func(st models.PrivateDataState) bool {
+		if checkErr != nil {
+			return false
+		}
+		item, err := s.localStore.PrivateDataRepository.GetPrivateData(ctx, st.ClientSideID, userID)
+		if err != nil {
+			checkErr = fmt.Errorf("load local item %s: %w", st.ClientSideID, err)
+			return false
+		}
+		if item.Payload.Type != models.Binary {
+			return false
+		}
+		plain, err := s.crypto.DecryptPayload(item.Payload)
+		if err != nil {
+			// Left in the plan: the sync reports such items as usual.
+			return false
+		}
+		return plain.BinaryData != nil && ValidateBinarySize(plain.BinaryData.Size, s.maxBinarySize) != nil
+	}
+	plan.Upload = slices.DeleteFunc(plan.Upload, oversized)
+	plan.Update = slices.DeleteFunc(plan.Update, oversized)
+	if checkErr != nil {
+		return plan, fmt.Errorf("check attachment sizes: %w", checkErr)
+	}
+	return plan, nil
+}
+
 func collectIDs(states []models.PrivateDataState) []string {
 	ids := make([]string, 0, len(states))
 	for _, st := range states {
@@ -25,10 +25,42 @@ func (s *spySyncService) FullSync(_ context.Context, _ int64) error {
 	return s.err
 }
 
+func (s *spySyncService) BreakerStatus() models.SyncBreakerStatus {
+	return models.SyncBreakerStatus{State: models.SyncBreakerClosed}
+}
+
+func (s *spySyncService) Ping(_ context.Context) error {
+	return nil
+}
+
 func (s *spySyncService) ExecutePlan(_ context.Context, _ models.SyncPlan, _ int64) error {
 	return nil
 }
 
+func (s *spySyncService) LastSyncedAt(_ context.Context, _ int64) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (s *spySyncService) ServerVersion(_ context.Context) (string, error) {
+	return "", nil
+}
+
+func (s *spySyncService) FindDuplicates(_ context.Context, _ int64) ([]models.DuplicateGroup, error) {
+	return nil, nil
+}
+
+func (s *spySyncService) MergeDuplicates(_ context.Context, _ int64, _ models.DuplicateGroup) error {
+	return nil
+}
+
+func (s *spySyncService) LoadConflict(_ context.Context, _ int64, _ models.VersionConflict) (models.ConflictVersions, error) {
+	return models.ConflictVersions{}, nil
+}
+
+func (s *spySyncService) ResolveConflict(_ context.Context, _ int64, _ string, _ models.ConflictChoice) error {
+	return nil
+}
+
 // ── NewClientSyncJob ─────────────────────────────────────────────────────────
 
 func TestNewClientSyncJob_ReturnsInterface(t *testing.T) {
@@ -213,6 +245,38 @@ func (c *captureSyncService) FullSync(ctx context.Context, userID int64) error {
 	return c.onFullSync(ctx, userID)
 }
 
+func (c *captureSyncService) BreakerStatus() models.SyncBreakerStatus {
+	return models.SyncBreakerStatus{State: models.SyncBreakerClosed}
+}
+
+func (c *captureSyncService) Ping(_ context.Context) error {
+	return nil
+}
+
 func (c *captureSyncService) ExecutePlan(_ context.Context, _ models.SyncPlan, _ int64) error {
 	return nil
 }
+
+func (c *captureSyncService) LastSyncedAt(_ context.Context, _ int64) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (c *captureSyncService) ServerVersion(_ context.Context) (string, error) {
+	return "", nil
+}
+
+func (c *captureSyncService) FindDuplicates(_ context.Context, _ int64) ([]models.DuplicateGroup, error) {
+	return nil, nil
+}
+
+func (c *captureSyncService) MergeDuplicates(_ context.Context, _ int64, _ models.DuplicateGroup) error {
+	return nil
+}
+
+func (c *captureSyncService) LoadConflict(_ context.Context, _ int64, _ models.VersionConflict) (models.ConflictVersions, error) {
+	return models.ConflictVersions{}, nil
+}
+
+func (c *captureSyncService) ResolveConflict(_ context.Context, _ int64, _ string, _ models.ConflictChoice) error {
+	return nil
+}
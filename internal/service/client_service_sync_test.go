@@ -4,11 +4,20 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/mock"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
 	"github.com/MKhiriev/go-pass-keeper/models"
@@ -19,11 +28,13 @@ import (
 
 // stubPlanner — простой мок SyncService, не требует mockgen (избегаем цикл импортов).
 type stubPlanner struct {
-	plan models.SyncPlan
-	err  error
+	plan       models.SyncPlan
+	err        error
+	hardDelete bool // последний переданный режим удаления на сервере
 }
 
-func (s *stubPlanner) BuildSyncPlan(_ context.Context, _, _ []models.PrivateDataState) (models.SyncPlan, error) {
+func (s *stubPlanner) BuildSyncPlan(_ context.Context, _, _ []models.PrivateDataState, hardDelete bool) (models.SyncPlan, error) {
+	s.hardDelete = hardDelete
 	return s.plan, s.err
 }
 
@@ -40,13 +51,20 @@ func newTestSyncSvc(
 	t.Helper()
 	mockRepo := mock.NewMockLocalPrivateDataRepository(ctrl)
 	mockAdapter := mock.NewMockServerAdapter(ctrl)
+	mockAdapter.EXPECT().GetSchemaVersion(gomock.Any()).Return(models.SchemaVersionResponse{}, nil).AnyTimes()
 	planner := &stubPlanner{}
 
+	// Большинство тестов не проверяют запись времени синхронизации.
+	mockSyncState := mock.NewMockLocalSyncStateRepository(ctrl)
+	mockSyncState.EXPECT().SetLastSyncedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	expectNoPendingConflicts(mockSyncState)
+
 	storages := &store.ClientStorages{
 		PrivateDataRepository: mockRepo,
+		SyncStateRepository:   mockSyncState,
 	}
 
-	svc := NewClientSyncService(storages, mockAdapter).(*clientSyncService)
+	svc := NewClientSyncService(storages, mockAdapter, NewClientCryptoService(nil), SyncPolicy{Mode: models.SyncModeBidirectional}, nil).(*clientSyncService)
 	svc.planner = planner
 
 	return svc, mockRepo, mockAdapter, planner
@@ -107,7 +125,7 @@ func TestClientSyncService_FullSync_DownloadAndUpload(t *testing.T) {
 	// Upload
 	localItem := models.PrivateData{ClientSideID: "new-on-client", UserID: userID, Version: 1}
 	mockRepo.EXPECT().GetPrivateData(ctx, "new-on-client", userID).Return(localItem, nil)
-	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(nil)
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
 
 	err := svc.FullSync(ctx, userID)
 	require.NoError(t, err)
@@ -184,6 +202,64 @@ func TestClientSyncService_FullSync_DeleteServer(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClientSyncService_FullSync_ServerSchemaCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverVersion int64
+		hardDelete    bool
+		serverErr     error
+		wantErr       error
+		wantSync      bool
+	}{
+		{name: "same schema", serverVersion: 8, wantSync: true},
+		{name: "older server", serverVersion: 5, wantSync: true},
+		{name: "hard-delete server", serverVersion: 8, hardDelete: true, wantSync: true},
+		{name: "server without schema endpoint", serverErr: fmt.Errorf("%w: 404 page not found", adapter.ErrNotFound), wantSync: true},
+		{name: "newer server", serverVersion: 9, wantErr: ErrIncompatibleServerSchema},
+		{name: "schema request fails", serverErr: errors.New("network error"), wantErr: errors.New("network error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockLocalPrivateDataRepository(ctrl)
+			mockAdapter := mock.NewMockServerAdapter(ctrl)
+			mockSyncState := mock.NewMockLocalSyncStateRepository(ctrl)
+			expectNoPendingConflicts(mockSyncState)
+			storages := &store.ClientStorages{PrivateDataRepository: mockRepo, SyncStateRepository: mockSyncState}
+
+			svc := NewClientSyncService(storages, mockAdapter, NewClientCryptoService(nil), SyncPolicy{Mode: models.SyncModeBidirectional}, nil).(*clientSyncService)
+			planner := &stubPlanner{}
+			svc.planner = planner
+			svc.schemaVersion = 8
+
+			ctx := context.Background()
+			mockAdapter.EXPECT().GetSchemaVersion(ctx).Return(models.SchemaVersionResponse{SchemaVersion: tt.serverVersion, HardDelete: tt.hardDelete}, tt.serverErr)
+			if tt.wantSync {
+				mockAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return(nil, nil)
+				mockRepo.EXPECT().GetAllStates(ctx, int64(1)).Return(nil, nil)
+				mockSyncState.EXPECT().SetLastSyncedAt(ctx, int64(1), gomock.Any()).Return(nil)
+			}
+
+			err := svc.FullSync(ctx, 1)
+
+			switch {
+			case tt.wantErr == nil:
+				require.NoError(t, err)
+				assert.Equal(t, tt.hardDelete, planner.hardDelete, "delete mode must reach the planner")
+			case errors.Is(tt.wantErr, ErrIncompatibleServerSchema):
+				require.ErrorIs(t, err, ErrIncompatibleServerSchema)
+				assert.Contains(t, err.Error(), "сервер: 9, клиент: 8")
+			default:
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr.Error())
+			}
+		})
+	}
+}
+
 func TestClientSyncService_FullSync_GetServerStatesError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -251,6 +327,309 @@ func TestClientSyncService_FullSync_ExecutePlanError(t *testing.T) {
 	assert.Contains(t, err.Error(), "execute sync plan")
 }
 
+// newTestSyncSvcWithState — как newTestSyncSvc, но со строгим моком хранилища
+// времени синхронизации: неожиданный вызов SetLastSyncedAt провалит тест.
+func newTestSyncSvcWithState(
+	t *testing.T,
+	ctrl *gomock.Controller,
+) (
+	*clientSyncService,
+	*mock.MockLocalPrivateDataRepository,
+	*mock.MockLocalSyncStateRepository,
+	*mock.MockServerAdapter,
+	*stubPlanner,
+) {
+	t.Helper()
+	svc, mockRepo, mockAdapter, planner := newTestSyncSvc(t, ctrl)
+	mockSyncState := mock.NewMockLocalSyncStateRepository(ctrl)
+	expectNoPendingConflicts(mockSyncState)
+	svc.localStore.SyncStateRepository = mockSyncState
+
+	return svc, mockRepo, mockSyncState, mockAdapter, planner
+}
+
+// expectNoPendingConflicts — для тестов, в которых нет неразрешённых конфликтов.
+func expectNoPendingConflicts(m *mock.MockLocalSyncStateRepository) {
+	m.EXPECT().GetPendingConflicts(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+}
+
+func TestClientSyncService_FullSync_RecordsLastSyncedAt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockSyncState, mockAdapter, planner := newTestSyncSvcWithState(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+
+	mockAdapter.EXPECT().GetServerStates(ctx, userID).Return(nil, nil)
+	mockRepo.EXPECT().GetAllStates(ctx, userID).Return(nil, nil)
+	planner.plan = models.SyncPlan{}
+
+	syncedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.clock = clock.NewFake(syncedAt)
+	mockSyncState.EXPECT().SetLastSyncedAt(ctx, userID, syncedAt).Return(nil)
+
+	require.NoError(t, svc.FullSync(ctx, userID))
+}
+
+func TestClientSyncService_FullSync_FailureDoesNotAdvanceLastSyncedAt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockSyncState, mockAdapter, planner := newTestSyncSvcWithState(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+
+	mockAdapter.EXPECT().GetServerStates(ctx, userID).Return(nil, nil)
+	mockRepo.EXPECT().GetAllStates(ctx, userID).Return(nil, nil)
+	planner.plan = models.SyncPlan{
+		Download: []models.PrivateDataState{{ClientSideID: "d1"}},
+	}
+	mockAdapter.EXPECT().Download(ctx, gomock.Any()).Return(nil, errors.New("network down"))
+	mockSyncState.EXPECT().SetLastSyncedAt(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	require.Error(t, svc.FullSync(ctx, userID))
+}
+
+func TestClientSyncService_FullSync_SaveLastSyncedAtError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockSyncState, mockAdapter, planner := newTestSyncSvcWithState(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+
+	mockAdapter.EXPECT().GetServerStates(ctx, userID).Return(nil, nil)
+	mockRepo.EXPECT().GetAllStates(ctx, userID).Return(nil, nil)
+	planner.plan = models.SyncPlan{}
+	mockSyncState.EXPECT().SetLastSyncedAt(ctx, userID, gomock.Any()).Return(errors.New("disk full"))
+
+	err := svc.FullSync(ctx, userID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "save last synced time")
+}
+
+// newTestSyncSvcWithBackups — хелпер для FullSync поверх настоящей локальной БД
+// аккаунта с включёнными резервными копиями.
+func newTestSyncSvcWithBackups(t *testing.T, ctrl *gomock.Controller, backupDir string, backupCount int) (*clientSyncService, *mock.MockServerAdapter) {
+	t.Helper()
+	storages, err := store.NewClientStorages(config.ClientStorage{
+		DataDir:     t.TempDir(),
+		BackupDir:   backupDir,
+		BackupCount: backupCount,
+	}, logger.NewClientLogger("test"))
+	require.NoError(t, err)
+	require.NoError(t, storages.OpenAccount("alice"))
+	t.Cleanup(func() { _ = storages.Close() })
+
+	mockAdapter := mock.NewMockServerAdapter(ctrl)
+	mockAdapter.EXPECT().GetSchemaVersion(gomock.Any()).Return(models.SchemaVersionResponse{}, nil).AnyTimes()
+
+	svc := NewClientSyncService(storages, mockAdapter, NewClientCryptoService(nil), SyncPolicy{Mode: models.SyncModeBidirectional}, nil).(*clientSyncService)
+	svc.planner = &stubPlanner{}
+	return svc, mockAdapter
+}
+
+func TestClientSyncService_FullSync_BacksUpLocalStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backupDir := filepath.Join(t.TempDir(), "backups")
+	svc, mockAdapter := newTestSyncSvcWithBackups(t, ctrl, backupDir, 2)
+	svc.planner = &stubPlanner{plan: models.SyncPlan{DeleteClient: []models.PrivateDataState{{ClientSideID: "gone"}}}}
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	svc.clock = clk
+	ctx := context.Background()
+	userID := int64(1)
+
+	mockAdapter.EXPECT().GetServerStates(ctx, userID).Return(nil, nil).Times(3)
+
+	require.NoError(t, svc.FullSync(ctx, userID))
+
+	entries, err := os.ReadDir(backupDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "a sync that changes the local store creates a backup")
+	assert.Contains(t, entries[0].Name(), "20260301T120000", "the backup is named after the sync clock")
+	info, err := entries[0].Info()
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	clk.Advance(time.Minute)
+	require.NoError(t, svc.FullSync(ctx, userID))
+	clk.Advance(time.Minute)
+	require.NoError(t, svc.FullSync(ctx, userID))
+
+	entries, err = os.ReadDir(backupDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "retention trims the backups to the configured count")
+}
+
+func TestClientSyncService_FullSync_NoOpSyncSkipsBackup(t *testing.T) {
+	tests := []struct {
+		name      string
+		plan      models.SyncPlan
+		conflicts models.ConflictPolicy
+	}{
+		{name: "empty plan"},
+		{name: "uploads only", plan: models.SyncPlan{Upload: []models.PrivateDataState{{ClientSideID: "missing"}}}},
+		{name: "updates under manual conflicts", plan: models.SyncPlan{Update: []models.PrivateDataState{{ClientSideID: "missing"}}}, conflicts: models.ConflictManual},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			backupDir := filepath.Join(t.TempDir(), "backups")
+			svc, mockAdapter := newTestSyncSvcWithBackups(t, ctrl, backupDir, 2)
+			svc.planner = &stubPlanner{plan: tt.plan}
+			svc.conflicts = tt.conflicts
+			ctx := context.Background()
+
+			mockAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return(nil, nil)
+
+			// The planned items do not exist locally, so the sync fails
+			// after the point where a backup would have been taken.
+			_ = svc.FullSync(ctx, 1)
+
+			assert.NoDirExists(t, backupDir, "nothing local is overwritten, so no backup is taken")
+		})
+	}
+}
+
+func TestClientSyncService_FullSync_BackupErrorAbortsSync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A regular file where the backup directory should be.
+	backupDir := filepath.Join(t.TempDir(), "backups")
+	require.NoError(t, os.WriteFile(backupDir, nil, 0o600))
+
+	svc, mockAdapter := newTestSyncSvcWithBackups(t, ctrl, backupDir, 2)
+	svc.planner = &stubPlanner{plan: models.SyncPlan{DeleteClient: []models.PrivateDataState{{ClientSideID: "gone"}}}}
+	ctx := context.Background()
+	userID := int64(1)
+
+	mockAdapter.EXPECT().GetServerStates(ctx, userID).Return(nil, nil)
+
+	err := svc.FullSync(ctx, userID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backup local store")
+
+	syncedAt, err := svc.LastSyncedAt(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, syncedAt.IsZero(), "a failed backup does not record a sync")
+}
+
+// ── LastSyncedAt ─────────────────────────────────────────────────────────────
+
+func TestClientSyncService_FullSync_ConcurrentCallsShareOneRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockAdapter, planner := newTestSyncSvc(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+	planner.plan = models.SyncPlan{}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mockAdapter.EXPECT().GetServerStates(gomock.Any(), userID).
+		DoAndReturn(func(context.Context, int64) ([]models.PrivateDataState, error) {
+			close(started)
+			<-release
+			return nil, nil
+		}).Times(1)
+	mockRepo.EXPECT().GetAllStates(gomock.Any(), userID).Return(nil, nil).Times(1)
+
+	errs := make(chan error, 2)
+	go func() { errs <- svc.FullSync(ctx, userID) }()
+	<-started
+	go func() { errs <- svc.FullSync(ctx, userID) }()
+	require.Eventually(t, func() bool { return svc.inflight.waiters(userID) == 1 }, time.Second, time.Millisecond)
+	close(release)
+
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+
+	// The next call after the shared run has finished starts a new one.
+	mockAdapter.EXPECT().GetServerStates(gomock.Any(), userID).Return(nil, errors.New("network down"))
+	require.Error(t, svc.FullSync(ctx, userID))
+}
+
+func TestClientSyncService_FullSync_WaiterSharesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mockAdapter.EXPECT().GetServerStates(gomock.Any(), userID).
+		DoAndReturn(func(context.Context, int64) ([]models.PrivateDataState, error) {
+			close(started)
+			<-release
+			return nil, errors.New("network down")
+		}).Times(1)
+
+	errs := make(chan error, 2)
+	go func() { errs <- svc.FullSync(ctx, userID) }()
+	<-started
+	go func() { errs <- svc.FullSync(ctx, userID) }()
+	require.Eventually(t, func() bool { return svc.inflight.waiters(userID) == 1 }, time.Second, time.Millisecond)
+	close(release)
+
+	assert.ErrorContains(t, <-errs, "network down")
+	assert.ErrorContains(t, <-errs, "network down")
+}
+
+func TestClientSyncService_ServerVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	ctx := context.Background()
+
+	mockAdapter.EXPECT().GetServerVersion(ctx).Return("1.4.0", nil)
+	version, err := svc.ServerVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", version)
+
+	mockAdapter.EXPECT().GetServerVersion(ctx).Return("", adapter.ErrNotFound)
+	_, err = svc.ServerVersion(ctx)
+	assert.ErrorIs(t, err, adapter.ErrNotFound)
+}
+
+func TestClientSyncService_LastSyncedAt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, mockSyncState, _, _ := newTestSyncSvcWithState(t, ctrl)
+	ctx := context.Background()
+	syncedAt := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	mockSyncState.EXPECT().GetLastSyncedAt(ctx, int64(1)).Return(syncedAt, nil)
+
+	got, err := svc.LastSyncedAt(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, syncedAt, got)
+}
+
+func TestClientSyncService_LastSyncedAt_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, mockSyncState, _, _ := newTestSyncSvcWithState(t, ctrl)
+	ctx := context.Background()
+
+	_, err := svc.LastSyncedAt(ctx, 0)
+	require.Error(t, err)
+
+	mockSyncState.EXPECT().GetLastSyncedAt(ctx, int64(1)).Return(time.Time{}, errors.New("db error"))
+	_, err = svc.LastSyncedAt(ctx, 1)
+	require.Error(t, err)
+}
+
 // ── ExecutePlan: Download ────────────────────────────────────────────────────
 
 func TestClientSyncService_ExecutePlan_DownloadSuccess(t *testing.T) {
@@ -323,6 +702,173 @@ func TestClientSyncService_ExecutePlan_DownloadSaveError(t *testing.T) {
 	assert.Contains(t, err.Error(), "error saving downloaded items locally")
 }
 
+func TestClientSyncService_ExecutePlan_DownloadVerifiesIntegrity(t *testing.T) {
+	payload := models.PrivateDataPayload{
+		Metadata: "enc-meta",
+		Type:     models.LoginPassword,
+		Data:     "enc-data",
+	}
+	hash, err := NewClientCryptoService(nil).ComputeHash(payload)
+	require.NoError(t, err)
+
+	tampered := payload
+	tampered.Data = "enc-data-altered"
+
+	tests := []struct {
+		name    string
+		item    models.PrivateData
+		wantErr bool
+	}{
+		{name: "matching hash", item: models.PrivateData{ClientSideID: "d1", Payload: payload, Hash: hash}},
+		{name: "tampered payload", item: models.PrivateData{ClientSideID: "d1", Payload: tampered, Hash: hash}, wantErr: true},
+		{name: "corrupted hash", item: models.PrivateData{ClientSideID: "d1", Payload: payload, Hash: "0000"}, wantErr: true},
+		{name: "item without hash", item: models.PrivateData{ClientSideID: "d1", Payload: payload}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+			ctx := context.Background()
+			plan := models.SyncPlan{Download: []models.PrivateDataState{{ClientSideID: "d1"}}}
+
+			mockAdapter.EXPECT().Download(ctx, gomock.Any()).Return([]models.PrivateData{tt.item}, nil)
+			if !tt.wantErr {
+				mockRepo.EXPECT().SavePrivateData(ctx, int64(1), tt.item).Return(nil)
+			}
+
+			err := svc.ExecutePlan(ctx, plan, 1)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrIntegrityCheckFailed)
+				assert.Contains(t, err.Error(), "d1")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClientSyncService_ExecutePlan_ConflictRefreshVerifiesIntegrity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+
+	plan := models.SyncPlan{Update: []models.PrivateDataState{{ClientSideID: "up1"}}}
+
+	mockRepo.EXPECT().GetPrivateData(ctx, "up1", userID).Return(models.PrivateData{
+		ClientSideID: "up1", UserID: userID, Version: 2,
+	}, nil)
+	mockAdapter.EXPECT().Update(ctx, gomock.Any()).Return(adapter.ErrConflict)
+	mockAdapter.EXPECT().Download(ctx, gomock.Any()).Return([]models.PrivateData{{
+		ClientSideID: "up1", UserID: userID, Version: 5, Hash: "not-the-hash",
+	}}, nil)
+
+	err := svc.ExecutePlan(ctx, plan, userID)
+	require.ErrorIs(t, err, ErrIntegrityCheckFailed)
+}
+
+func TestClientSyncService_ExecutePlan_DownloadSplitsLargePlans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	ctx := context.Background()
+
+	total := config.DefaultMaxClientSideIDs + 1
+	plan := models.SyncPlan{Download: make([]models.PrivateDataState, total)}
+	for i := range plan.Download {
+		plan.Download[i].ClientSideID = fmt.Sprintf("d%d", i)
+	}
+
+	var sizes []int
+	mockAdapter.EXPECT().Download(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, req models.DownloadRequest) ([]models.PrivateData, error) {
+			sizes = append(sizes, len(req.ClientSideIDs))
+			assert.Equal(t, len(req.ClientSideIDs), req.Length)
+			return nil, nil
+		},
+	).Times(2)
+	mockRepo.EXPECT().SavePrivateData(ctx, int64(1)).Return(nil).Times(2)
+
+	require.NoError(t, svc.ExecutePlan(ctx, plan, 1))
+	assert.Equal(t, []int{config.DefaultMaxClientSideIDs, 1}, sizes)
+}
+
+func TestClientSyncService_FullSync_SkipsOversizedAttachments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc, mockRepo, mockAdapter, planner := newTestSyncSvc(t, ctrl)
+	mockCrypto := mock.NewMockClientCryptoService(ctrl)
+	svc.crypto = mockCrypto
+	svc.maxBinarySize = 1024
+	ctx := context.Background()
+	userID := int64(1)
+
+	sizes := map[string]int64{"big-new": 1025, "small-new": 1024, "big-edited": 4096}
+	mockRepo.EXPECT().GetAllStates(ctx, userID).Return(nil, nil)
+	mockAdapter.EXPECT().GetServerStates(ctx, userID).Return(nil, nil)
+	mockRepo.EXPECT().GetPrivateData(ctx, gomock.Any(), userID).DoAndReturn(
+		func(_ context.Context, id string, _ int64) (models.PrivateData, error) {
+			typ := models.Binary
+			if id == "text" {
+				typ = models.Text
+			}
+			return models.PrivateData{ClientSideID: id, UserID: userID, Payload: models.PrivateDataPayload{Type: typ, Metadata: models.CipheredMetadata(id)}}, nil
+		},
+	).AnyTimes()
+	mockCrypto.EXPECT().DecryptPayload(gomock.Any()).DoAndReturn(
+		func(enc models.PrivateDataPayload) (models.DecipheredPayload, error) {
+			return models.DecipheredPayload{Type: models.Binary, BinaryData: &models.BinaryData{Size: sizes[string(enc.Metadata)]}}, nil
+		},
+	).Times(3)
+	planner.plan = models.SyncPlan{
+		Upload: []models.PrivateDataState{{ClientSideID: "big-new"}, {ClientSideID: "small-new"}, {ClientSideID: "text"}},
+		Update: []models.PrivateDataState{{ClientSideID: "big-edited"}},
+	}
+
+	var uploaded []string
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req models.UploadRequest) (models.UploadResponse, error) {
+		for _, item := range req.PrivateDataList {
+			uploaded = append(uploaded, item.ClientSideID)
+		}
+		return models.UploadResponse{}, nil
+	})
+	// No Update call: the only planned update is oversized.
+
+	require.NoError(t, svc.FullSync(ctx, userID))
+	assert.Equal(t, []string{"small-new", "text"}, uploaded)
+}
+
+func TestClientSyncService_ExecutePlan_DownloadUsesConfiguredBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	base, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	svc := NewClientSyncService(base.localStore, mockAdapter, NewClientCryptoService(nil), SyncPolicy{DownloadBatch: 2}, nil)
+	ctx := context.Background()
+
+	plan := models.SyncPlan{Download: make([]models.PrivateDataState, 5)}
+	for i := range plan.Download {
+		plan.Download[i].ClientSideID = fmt.Sprintf("d%d", i)
+	}
+
+	var sizes []int
+	mockAdapter.EXPECT().Download(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, req models.DownloadRequest) ([]models.PrivateData, error) {
+			sizes = append(sizes, len(req.ClientSideIDs))
+			return nil, nil
+		},
+	).Times(3)
+	mockRepo.EXPECT().SavePrivateData(ctx, int64(1)).Return(nil).Times(3)
+
+	require.NoError(t, svc.ExecutePlan(ctx, plan, 1))
+	assert.Equal(t, []int{2, 2, 1}, sizes, "a server with a lower cap is never sent larger batches")
+}
+
 // ── ExecutePlan: Upload ──────────────────────────────────────────────────────
 
 func TestClientSyncService_ExecutePlan_UploadSuccess(t *testing.T) {
@@ -343,20 +889,72 @@ func TestClientSyncService_ExecutePlan_UploadSuccess(t *testing.T) {
 	item1 := models.PrivateData{ClientSideID: "u1", UserID: userID, Version: 1}
 	item2 := models.PrivateData{ClientSideID: "u2", UserID: userID, Version: 1}
 
+	createdAt := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	uploaded := models.UploadResponse{
+		Items: []models.UploadedItem{
+			{ID: 11, ClientSideID: "u1", CreatedAt: &createdAt},
+			{ID: 12, ClientSideID: "u2", CreatedAt: &createdAt},
+		},
+		Length: 2,
+	}
+
 	mockRepo.EXPECT().GetPrivateData(ctx, "u1", userID).Return(item1, nil)
 	mockRepo.EXPECT().GetPrivateData(ctx, "u2", userID).Return(item2, nil)
 	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).DoAndReturn(
-		func(_ context.Context, req models.UploadRequest) error {
+		func(_ context.Context, req models.UploadRequest) (models.UploadResponse, error) {
 			assert.Len(t, req.PrivateDataList, 2)
 			assert.Equal(t, 2, req.Length)
-			return nil
+			return uploaded, nil
 		},
 	)
+	mockRepo.EXPECT().SetServerFields(ctx, userID, uploaded.Items[0], uploaded.Items[1]).Return(nil)
 
 	err := svc.ExecutePlan(ctx, plan, userID)
 	require.NoError(t, err)
 }
 
+func TestClientSyncService_ExecutePlan_UploadPartialFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	ctx := context.Background()
+	userID := int64(1)
+
+	plan := models.SyncPlan{
+		Upload: []models.PrivateDataState{
+			{ClientSideID: "u1"},
+			{ClientSideID: "u2"},
+		},
+		DeleteServer: []models.PrivateDataState{{ClientSideID: "d1"}},
+	}
+
+	createdAt := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	uploaded := models.UploadResponse{
+		Items:  []models.UploadedItem{{ID: 11, ClientSideID: "u1", CreatedAt: &createdAt}},
+		Length: 1,
+		Failed: []models.UploadFailure{{ClientSideID: "u2", Status: 409, Error: "data already exists"}},
+	}
+
+	mockRepo.EXPECT().GetPrivateData(ctx, "u1", userID).Return(models.PrivateData{ClientSideID: "u1", UserID: userID}, nil)
+	mockRepo.EXPECT().GetPrivateData(ctx, "u2", userID).Return(models.PrivateData{ClientSideID: "u2", UserID: userID}, nil)
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, req models.UploadRequest) (models.UploadResponse, error) {
+			assert.True(t, req.BestEffort)
+			return uploaded, nil
+		},
+	)
+	// Only the accepted item gets its server fields.
+	mockRepo.EXPECT().SetServerFields(ctx, userID, uploaded.Items[0]).Return(nil)
+	// The rejected upload does not stop the rest of the plan.
+	mockRepo.EXPECT().GetPrivateData(ctx, "d1", userID).Return(models.PrivateData{ClientSideID: "d1", UserID: userID}, nil)
+	mockAdapter.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
+
+	err := svc.ExecutePlan(ctx, plan, userID)
+	require.ErrorIs(t, err, ErrUploadPartiallyFailed)
+	assert.Contains(t, err.Error(), "1 из 2")
+}
+
 func TestClientSyncService_ExecutePlan_UploadGetLocalItemError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -388,7 +986,7 @@ func TestClientSyncService_ExecutePlan_UploadAdapterError(t *testing.T) {
 	}
 
 	mockRepo.EXPECT().GetPrivateData(ctx, "u1", userID).Return(models.PrivateData{ClientSideID: "u1"}, nil)
-	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(errors.New("server error"))
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, errors.New("server error"))
 
 	err := svc.ExecutePlan(ctx, plan, userID)
 	require.Error(t, err)
@@ -481,6 +1079,42 @@ func TestClientSyncService_ExecutePlan_UpdateServerError(t *testing.T) {
 	assert.Contains(t, err.Error(), "update server item up1")
 }
 
+func TestClientSyncService_ExecutePlan_RetriesLockedCalls(t *testing.T) {
+	delay := syncLockRetryDelay
+	syncLockRetryDelay = time.Millisecond
+	t.Cleanup(func() { syncLockRetryDelay = delay })
+
+	locked := fmt.Errorf("%w: another sync of this user is in progress", adapter.ErrLocked)
+	item := models.PrivateData{ClientSideID: "id1", UserID: 1, Version: 2}
+
+	t.Run("update succeeds once the lock is released", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+		ctx := context.Background()
+
+		mockRepo.EXPECT().GetPrivateData(ctx, "id1", int64(1)).Return(item, nil)
+		gomock.InOrder(
+			mockAdapter.EXPECT().Update(ctx, gomock.Any()).Return(locked),
+			mockAdapter.EXPECT().Update(ctx, gomock.Any()).Return(locked),
+			mockAdapter.EXPECT().Update(ctx, gomock.Any()).Return(nil),
+		)
+
+		require.NoError(t, svc.ExecutePlan(ctx, models.SyncPlan{Update: []models.PrivateDataState{{ClientSideID: "id1"}}}, 1))
+	})
+
+	t.Run("delete gives up after the retries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+		ctx := context.Background()
+
+		mockRepo.EXPECT().GetPrivateData(ctx, "id1", int64(1)).Return(item, nil)
+		mockAdapter.EXPECT().Delete(ctx, gomock.Any()).Return(locked).Times(syncLockRetries + 1)
+
+		err := svc.ExecutePlan(ctx, models.SyncPlan{DeleteServer: []models.PrivateDataState{{ClientSideID: "id1"}}}, 1)
+		assert.ErrorIs(t, err, adapter.ErrLocked)
+	})
+}
+
 // ── ExecutePlan: Update with conflict → refreshConflict ──────────────────────
 
 func TestClientSyncService_ExecutePlan_UpdateConflict_RefreshSuccess(t *testing.T) {
@@ -721,6 +1355,88 @@ func TestClientSyncService_ExecutePlan_DeleteServerConflict_RefreshError(t *test
 	assert.Contains(t, err.Error(), "download conflict item ds1")
 }
 
+func TestClientSyncService_ExecutePlan_ConflictCarriesOperation(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     models.SyncPlan
+		expect   func(a *mock.MockServerAdapter, err error)
+		conflict *adapter.ConflictError
+		wantErr  string
+	}{
+		{
+			name: "update conflict",
+			plan: models.SyncPlan{Update: []models.PrivateDataState{{ClientSideID: "c1"}}},
+			expect: func(a *mock.MockServerAdapter, err error) {
+				a.EXPECT().Update(gomock.Any(), gomock.Any()).Return(err)
+			},
+			conflict: &adapter.ConflictError{VersionConflict: models.VersionConflict{
+				Operation: models.ConflictOnUpdate, ClientSideID: "c1", ServerVersion: 5,
+			}},
+			wantErr: "download conflict item c1 after update conflict (server version 5)",
+		},
+		{
+			name: "delete conflict without server details",
+			plan: models.SyncPlan{DeleteServer: []models.PrivateDataState{{ClientSideID: "c1"}}},
+			expect: func(a *mock.MockServerAdapter, err error) {
+				a.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(err)
+			},
+			conflict: &adapter.ConflictError{},
+			wantErr:  "download conflict item c1 after delete conflict (server version 0)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+			ctx := context.Background()
+
+			mockRepo.EXPECT().GetPrivateData(ctx, "c1", int64(1)).Return(models.PrivateData{
+				ClientSideID: "c1", UserID: 1, Version: 2,
+			}, nil)
+			tt.expect(mockAdapter, fmt.Errorf("wrapped: %w", tt.conflict))
+			mockAdapter.EXPECT().Download(ctx, gomock.Any()).Return(nil, errors.New("network error"))
+
+			err := svc.ExecutePlan(ctx, tt.plan, 1)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestConflictOf(t *testing.T) {
+	conflict := &adapter.ConflictError{VersionConflict: models.VersionConflict{
+		Operation: models.ConflictOnDelete, ClientSideID: "c1", ServerVersion: 9,
+	}}
+
+	got, ok := ConflictOf(fmt.Errorf("delete item on server: %w", conflict))
+	require.True(t, ok)
+	assert.Equal(t, conflict.VersionConflict, got)
+
+	_, ok = ConflictOf(fmt.Errorf("%w: version conflict", adapter.ErrConflict))
+	assert.False(t, ok)
+	_, ok = ConflictOf(nil)
+	assert.False(t, ok)
+}
+
+func TestValidationFailureOf(t *testing.T) {
+	index := 1
+	invalid := &adapter.ValidationError{ValidationFailure: models.ValidationFailure{
+		Message: "invalid data provided", Index: &index, Field: "client_side_id", Reason: "invalid client side id",
+	}}
+
+	got, ok := ValidationFailureOf(fmt.Errorf("upload item: %w", invalid))
+	require.True(t, ok)
+	assert.Equal(t, invalid.ValidationFailure, got)
+
+	_, ok = ValidationFailureOf(fmt.Errorf("%w: invalid data provided", adapter.ErrBadRequest))
+	assert.False(t, ok)
+	_, ok = ValidationFailureOf(nil)
+	assert.False(t, ok)
+}
+
 // ── ExecutePlan: Mixed plan ──────────────────────────────────────────────────
 
 func TestClientSyncService_ExecutePlan_MixedPlan(t *testing.T) {
@@ -749,7 +1465,7 @@ func TestClientSyncService_ExecutePlan_MixedPlan(t *testing.T) {
 	mockRepo.EXPECT().GetPrivateData(ctx, "u1", userID).Return(
 		models.PrivateData{ClientSideID: "u1", UserID: userID}, nil,
 	)
-	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(nil)
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
 
 	// Update
 	mockRepo.EXPECT().GetPrivateData(ctx, "up1", userID).Return(
@@ -770,6 +1486,143 @@ func TestClientSyncService_ExecutePlan_MixedPlan(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// ── ExecutePlan: Sync mode ───────────────────────────────────────────────────
+
+func fullTestPlan() models.SyncPlan {
+	return models.SyncPlan{
+		Download:     []models.PrivateDataState{{ClientSideID: "d1"}},
+		Upload:       []models.PrivateDataState{{ClientSideID: "u1"}},
+		Update:       []models.PrivateDataState{{ClientSideID: "up1"}},
+		DeleteClient: []models.PrivateDataState{{ClientSideID: "dc1", Version: 2}},
+		DeleteServer: []models.PrivateDataState{{ClientSideID: "ds1"}},
+	}
+}
+
+func TestClientSyncService_ExecutePlan_PushOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	svc.mode = models.SyncModePushOnly
+	ctx := context.Background()
+	userID := int64(1)
+
+	// Only Upload and Update run; the strict mocks fail on any Download,
+	// local delete or server Delete.
+	mockRepo.EXPECT().GetPrivateData(ctx, "u1", userID).Return(
+		models.PrivateData{ClientSideID: "u1", UserID: userID}, nil,
+	)
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
+	mockRepo.EXPECT().GetPrivateData(ctx, "up1", userID).Return(
+		models.PrivateData{ClientSideID: "up1", UserID: userID, Version: 3}, nil,
+	)
+	mockAdapter.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+	require.NoError(t, svc.ExecutePlan(ctx, fullTestPlan(), userID))
+}
+
+func TestClientSyncService_ExecutePlan_PullOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	svc.mode = models.SyncModePullOnly
+	ctx := context.Background()
+	userID := int64(1)
+
+	// Only Download and DeleteClient run; the strict mocks fail on any
+	// Upload, Update or server Delete.
+	mockAdapter.EXPECT().Download(ctx, gomock.Any()).Return(
+		[]models.PrivateData{{ClientSideID: "d1", UserID: userID}}, nil,
+	)
+	mockRepo.EXPECT().SavePrivateData(ctx, userID, gomock.Any()).Return(nil)
+	mockRepo.EXPECT().DeletePrivateData(ctx, "dc1", int64(2)).Return(nil)
+
+	require.NoError(t, svc.ExecutePlan(ctx, fullTestPlan(), userID))
+}
+
+func TestSyncMode_Filter(t *testing.T) {
+	plan := fullTestPlan()
+
+	assert.Equal(t, plan, models.SyncModeBidirectional.Filter(plan))
+	assert.Equal(t, plan, models.SyncMode("").Filter(plan))
+	assert.Equal(t, models.SyncPlan{Upload: plan.Upload, Update: plan.Update}, models.SyncModePushOnly.Filter(plan))
+	assert.Equal(t, models.SyncPlan{Download: plan.Download, DeleteClient: plan.DeleteClient}, models.SyncModePullOnly.Filter(plan))
+	assert.False(t, models.SyncMode("sideways").IsValid())
+}
+
+// ── ExecutePlan: Sync events ─────────────────────────────────────────────────
+
+func decodeSyncEvents(t *testing.T, buf *bytes.Buffer) []models.SyncEvent {
+	t.Helper()
+	var events []models.SyncEvent
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var ev models.SyncEvent
+		require.NoError(t, dec.Decode(&ev))
+		assert.False(t, ev.Time.IsZero())
+		ev.Time = time.Time{}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestClientSyncService_ExecutePlan_EmitsSyncEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockRepo, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	var buf bytes.Buffer
+	svc.events = newSyncEventWriter(&buf, svc.clock)
+	ctx := context.Background()
+	userID := int64(1)
+
+	mockAdapter.EXPECT().Download(ctx, gomock.Any()).Return(
+		[]models.PrivateData{{ClientSideID: "d1", UserID: userID}}, nil,
+	)
+	mockRepo.EXPECT().SavePrivateData(ctx, userID, gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetPrivateData(ctx, "u1", userID).Return(
+		models.PrivateData{ClientSideID: "u1", UserID: userID}, nil,
+	)
+	mockAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
+	mockRepo.EXPECT().GetPrivateData(ctx, "up1", userID).Return(
+		models.PrivateData{ClientSideID: "up1", UserID: userID, Version: 3}, nil,
+	)
+	mockAdapter.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	mockRepo.EXPECT().DeletePrivateData(ctx, "dc1", int64(2)).Return(nil)
+	mockRepo.EXPECT().GetPrivateData(ctx, "ds1", userID).Return(
+		models.PrivateData{ClientSideID: "ds1", UserID: userID, Version: 5}, nil,
+	)
+	mockAdapter.EXPECT().Delete(ctx, gomock.Any()).Return(errors.New("boom"))
+
+	err := svc.ExecutePlan(ctx, fullTestPlan(), userID)
+	require.Error(t, err)
+
+	assert.Equal(t, []models.SyncEvent{
+		{Op: models.SyncEventDownload, ClientSideID: "d1", Result: models.SyncResultOK},
+		{Op: models.SyncEventUpload, ClientSideID: "u1", Result: models.SyncResultOK},
+		{Op: models.SyncEventUpdate, ClientSideID: "up1", Result: models.SyncResultOK},
+		{Op: models.SyncEventDeleteClient, ClientSideID: "dc1", Result: models.SyncResultOK},
+		{Op: models.SyncEventDeleteServer, ClientSideID: "ds1", Result: models.SyncResultError, Error: err.Error()},
+		{Op: models.SyncEventSummary, Result: models.SyncResultError, Error: err.Error(), Succeeded: 4, Failed: 1},
+	}, decodeSyncEvents(t, &buf))
+}
+
+func TestClientSyncService_ExecutePlan_EmptyPlanEmitsSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, _, _ := newTestSyncSvc(t, ctrl)
+	var buf bytes.Buffer
+	svc.events = newSyncEventWriter(&buf, svc.clock)
+
+	require.NoError(t, svc.ExecutePlan(context.Background(), models.SyncPlan{}, 1))
+
+	assert.Equal(t, []models.SyncEvent{
+		{Op: models.SyncEventSummary, Result: models.SyncResultOK},
+	}, decodeSyncEvents(t, &buf))
+}
+
 // ── ExecutePlan: Empty plan ──────────────────────────────────────────────────
 
 func TestClientSyncService_ExecutePlan_EmptyPlan(t *testing.T) {
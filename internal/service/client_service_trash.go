@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// GetTrash implements ClientPrivateDataService. It is GetAllMeta for the
+// soft-deleted items.
+func (p *clientPrivateDataService) GetTrash(ctx context.Context, userID int64) (items []models.DecipheredPayload, failed []string, err error) {
+	stored, err := p.localStore.PrivateDataRepository.GetDeletedPrivateData(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get deleted local items: %w", err)
+	}
+	return p.decryptAll(stored, userID, p.crypto.DecryptMetadata)
+}
+
+// RestoreDeleted implements ClientPrivateDataService. What it does depends on
+// the server's copy, so the server must be reachable:
+//   - the server still has the item live, because the deletion has not been
+//     pushed yet: the local deleted flag is cleared and nothing is sent;
+//   - the server has a tombstone: it is restored on the server with
+//     [adapter.ServerAdapter.Restore] at the tombstone's version, and the
+//     local copy is marked live at the version the server bumped it to, so
+//     the item keeps its ID on every device;
+//   - the server has no row, because it runs in hard-delete mode or does not
+//     support restores: the item is re-created from its decrypted payload
+//     under a new ID through [clientPrivateDataService.Create], and the local
+//     tombstone is purged.
+func (p *clientPrivateDataService) RestoreDeleted(ctx context.Context, userID int64, clientSideID string) error {
+	item, err := p.localStore.PrivateDataRepository.GetPrivateData(ctx, clientSideID, userID)
+	if err != nil {
+		return fmt.Errorf("load deleted local item %s: %w", clientSideID, err)
+	}
+	if !item.Deleted {
+		return fmt.Errorf("deleted local item %s: %w", clientSideID, store.ErrPrivateDataNotFound)
+	}
+
+	state, found, err := p.serverState(ctx, userID, clientSideID)
+	if err != nil {
+		return err
+	}
+	if found && !state.Deleted {
+		if err = p.localStore.PrivateDataRepository.RestorePrivateData(ctx, clientSideID, userID); err != nil {
+			return fmt.Errorf("restore local item %s: %w", clientSideID, err)
+		}
+		return nil
+	}
+
+	if found {
+		restored, restoreErr := p.restoreOnServer(ctx, item, state.Version)
+		if restoreErr != nil || restored {
+			return restoreErr
+		}
+	}
+
+	plain, err := p.crypto.DecryptPayload(item.Payload)
+	if err != nil {
+		return fmt.Errorf("decrypt deleted item %s: %w", clientSideID, err)
+	}
+	if err = p.Create(ctx, userID, plain); err != nil {
+		return fmt.Errorf("re-create deleted item %s: %w", clientSideID, err)
+	}
+	if err = p.localStore.PrivateDataRepository.PurgePrivateData(ctx, clientSideID, userID); err != nil {
+		return fmt.Errorf("purge restored tombstone %s: %w", clientSideID, err)
+	}
+	return nil
+}
+
+// RemoveFromTrash implements ClientPrivateDataService. The server keeps its
+// tombstone, which other devices need to learn about the deletion; only the
+// local copy is removed. Purging the tombstone on the server as well would
+// make a soft-delete server look like it lost the item, and the other
+// devices would upload it again.
+func (p *clientPrivateDataService) RemoveFromTrash(ctx context.Context, userID int64, clientSideID string) error {
+	live, err := p.liveOnServer(ctx, userID, clientSideID)
+	if err != nil {
+		return err
+	}
+	if live {
+		return ErrDeletePending
+	}
+	if err = p.localStore.PrivateDataRepository.PurgePrivateData(ctx, clientSideID, userID); err != nil {
+		return fmt.Errorf("purge local item %s: %w", clientSideID, err)
+	}
+	return nil
+}
+
+// restoreOnServer restores the tombstone of item, at serverVersion, on the
+// server and then marks the local copy live at the bumped version. It
+// reports false, without an error, when the server answers that it has no
+// such item, so that the caller falls back to re-creating it.
+func (p *clientPrivateDataService) restoreOnServer(ctx context.Context, item models.PrivateData, serverVersion int64) (bool, error) {
+	req := models.RestoreRequest{
+		UserID: item.UserID,
+		RestoreEntries: []models.RestoreEntry{{
+			ClientSideID: item.ClientSideID,
+			Version:      serverVersion,
+		}},
+		Length: 1,
+	}
+	if err := p.adapter.Restore(ctx, req); err != nil {
+		if errors.Is(err, adapter.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("restore item %s on server: %w", item.ClientSideID, err)
+	}
+
+	now := p.clock.Now().UTC()
+	item.Deleted = false
+	item.Version = serverVersion + 1
+	item.UpdatedAt = &now
+	if err := p.localStore.PrivateDataRepository.UpdatePrivateData(ctx, item); err != nil {
+		return false, fmt.Errorf("restore local item %s: %w", item.ClientSideID, err)
+	}
+	return true, nil
+}
+
+// liveOnServer reports whether the server has a live, not deleted, copy of
+// the item clientSideID.
+func (p *clientPrivateDataService) liveOnServer(ctx context.Context, userID int64, clientSideID string) (bool, error) {
+	state, found, err := p.serverState(ctx, userID, clientSideID)
+	if err != nil {
+		return false, err
+	}
+	return found && !state.Deleted, nil
+}
+
+// serverState returns the server's state of the item clientSideID and
+// whether the server has a row for it at all.
+func (p *clientPrivateDataService) serverState(ctx context.Context, userID int64, clientSideID string) (models.PrivateDataState, bool, error) {
+	states, err := p.adapter.GetServerStates(ctx, userID)
+	if err != nil {
+		return models.PrivateDataState{}, false, fmt.Errorf("get server states: %w", err)
+	}
+	for _, st := range states {
+		if st.ClientSideID == clientSideID {
+			return st, true, nil
+		}
+	}
+	return models.PrivateDataState{}, false, nil
+}
@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/crypto"
+	"github.com/MKhiriev/go-pass-keeper/internal/mock"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newTrashTestSvc returns a private-data service with a real crypto service
+// and the encrypted payload of a deleted login stored under "gone".
+func newTrashTestSvc(t *testing.T) (*clientPrivateDataService, *mock.MockLocalPrivateDataRepository, *mock.MockServerAdapter, ClientCryptoService, models.PrivateData) {
+	t.Helper()
+	keyChain := crypto.NewKeyChainService()
+	dek, err := keyChain.GenerateDEK()
+	require.NoError(t, err)
+	cryptoSvc := NewClientCryptoService(keyChain)
+	cryptoSvc.SetEncryptionKey(dek)
+
+	payload, err := cryptoSvc.EncryptPayload(models.DecipheredPayload{
+		Type:      models.LoginPassword,
+		Metadata:  models.Metadata{Name: "Почта"},
+		LoginData: &models.LoginData{Username: "user", Password: "secret"},
+	})
+	require.NoError(t, err)
+	deleted := models.PrivateData{ID: 7, ClientSideID: "gone", UserID: 1, Version: 4, Payload: payload, Deleted: true}
+
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockLocalPrivateDataRepository(ctrl)
+	serverAdapter := mock.NewMockServerAdapter(ctrl)
+	svc := NewClientPrivateDataService(&store.ClientStorages{PrivateDataRepository: repo}, serverAdapter, cryptoSvc, testMaxBinarySize, 0, "", clock.Real{}).(*clientPrivateDataService)
+	return svc, repo, serverAdapter, cryptoSvc, deleted
+}
+
+func TestClientPrivateDataService_GetTrash(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, _, deleted := newTrashTestSvc(t)
+
+	repo.EXPECT().GetDeletedPrivateData(ctx, int64(1)).Return([]models.PrivateData{
+		deleted,
+		{ClientSideID: "broken", UserID: 1, Deleted: true, Payload: models.PrivateDataPayload{Metadata: "garbage"}},
+	}, nil)
+
+	items, failed, err := svc.GetTrash(ctx, 1)
+
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "gone", items[0].ClientSideID)
+	assert.Equal(t, "Почта", items[0].Metadata.Name)
+	assert.Equal(t, []string{"broken"}, failed)
+}
+
+func TestClientPrivateDataService_RestoreDeleted(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deletion not pushed yet", func(t *testing.T) {
+		svc, repo, serverAdapter, _, deleted := newTrashTestSvc(t)
+		repo.EXPECT().GetPrivateData(ctx, "gone", int64(1)).Return(deleted, nil)
+		serverAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return([]models.PrivateDataState{{ClientSideID: "gone", Version: 4}}, nil)
+		repo.EXPECT().RestorePrivateData(ctx, "gone", int64(1)).Return(nil)
+
+		require.NoError(t, svc.RestoreDeleted(ctx, 1, "gone"))
+	})
+
+	t.Run("tombstone on the server", func(t *testing.T) {
+		svc, repo, serverAdapter, _, deleted := newTrashTestSvc(t)
+		repo.EXPECT().GetPrivateData(ctx, "gone", int64(1)).Return(deleted, nil)
+		serverAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return([]models.PrivateDataState{{ClientSideID: "gone", Version: 5, Deleted: true}}, nil)
+		serverAdapter.EXPECT().Restore(ctx, models.RestoreRequest{
+			UserID:         1,
+			RestoreEntries: []models.RestoreEntry{{ClientSideID: "gone", Version: 5}},
+			Length:         1,
+		}).Return(nil)
+
+		var restored models.PrivateData
+		repo.EXPECT().UpdatePrivateData(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, data models.PrivateData) error {
+			restored = data
+			return nil
+		})
+
+		require.NoError(t, svc.RestoreDeleted(ctx, 1, "gone"))
+		assert.Equal(t, "gone", restored.ClientSideID, "the item keeps its ID")
+		assert.False(t, restored.Deleted)
+		assert.Equal(t, int64(6), restored.Version)
+		assert.Equal(t, deleted.Payload, restored.Payload)
+	})
+
+	t.Run("tombstone restore conflicts", func(t *testing.T) {
+		svc, repo, serverAdapter, _, deleted := newTrashTestSvc(t)
+		repo.EXPECT().GetPrivateData(ctx, "gone", int64(1)).Return(deleted, nil)
+		serverAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return([]models.PrivateDataState{{ClientSideID: "gone", Version: 5, Deleted: true}}, nil)
+		serverAdapter.EXPECT().Restore(ctx, gomock.Any()).Return(adapter.ErrConflict)
+
+		assert.ErrorIs(t, svc.RestoreDeleted(ctx, 1, "gone"), adapter.ErrConflict)
+	})
+
+	t.Run("server lost the tombstone", func(t *testing.T) {
+		svc, repo, serverAdapter, _, deleted := newTrashTestSvc(t)
+		repo.EXPECT().GetPrivateData(ctx, "gone", int64(1)).Return(deleted, nil)
+		serverAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return([]models.PrivateDataState{{ClientSideID: "gone", Version: 5, Deleted: true}}, nil)
+		serverAdapter.EXPECT().Restore(ctx, gomock.Any()).Return(adapter.ErrNotFound)
+		repo.EXPECT().SavePrivateData(ctx, int64(1), gomock.Any()).Return(nil)
+		serverAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
+		repo.EXPECT().PurgePrivateData(ctx, "gone", int64(1)).Return(nil)
+
+		require.NoError(t, svc.RestoreDeleted(ctx, 1, "gone"))
+	})
+
+	t.Run("no row on the server", func(t *testing.T) {
+		svc, repo, serverAdapter, cryptoSvc, deleted := newTrashTestSvc(t)
+		repo.EXPECT().GetPrivateData(ctx, "gone", int64(1)).Return(deleted, nil)
+		serverAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return([]models.PrivateDataState{{ClientSideID: "other", Version: 1}}, nil)
+
+		var created models.PrivateData
+		repo.EXPECT().SavePrivateData(ctx, int64(1), gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, data ...models.PrivateData) error {
+			created = data[0]
+			return nil
+		})
+		serverAdapter.EXPECT().Upload(ctx, gomock.Any()).Return(models.UploadResponse{}, nil)
+		repo.EXPECT().PurgePrivateData(ctx, "gone", int64(1)).Return(nil)
+
+		require.NoError(t, svc.RestoreDeleted(ctx, 1, "gone"))
+		assert.NotEqual(t, "gone", created.ClientSideID, "the item is re-created under a new ID")
+		plain, err := cryptoSvc.DecryptPayload(created.Payload)
+		require.NoError(t, err)
+		assert.Equal(t, "Почта", plain.Metadata.Name)
+		assert.Equal(t, "secret", plain.LoginData.Password)
+	})
+
+	t.Run("live item", func(t *testing.T) {
+		svc, repo, _, _, deleted := newTrashTestSvc(t)
+		deleted.Deleted = false
+		repo.EXPECT().GetPrivateData(ctx, "gone", int64(1)).Return(deleted, nil)
+
+		assert.ErrorIs(t, svc.RestoreDeleted(ctx, 1, "gone"), store.ErrPrivateDataNotFound)
+	})
+
+	t.Run("server unreachable", func(t *testing.T) {
+		svc, repo, serverAdapter, _, deleted := newTrashTestSvc(t)
+		repo.EXPECT().GetPrivateData(ctx, "gone", int64(1)).Return(deleted, nil)
+		serverAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return(nil, errors.New("network down"))
+
+		assert.ErrorContains(t, svc.RestoreDeleted(ctx, 1, "gone"), "network down")
+	})
+}
+
+func TestClientPrivateDataService_RemoveFromTrash(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		states    []models.PrivateDataState
+		wantPurge bool
+		wantErr   error
+	}{
+		{name: "tombstone on the server", states: []models.PrivateDataState{{ClientSideID: "gone", Deleted: true}}, wantPurge: true},
+		{name: "not on the server", states: []models.PrivateDataState{{ClientSideID: "other"}}, wantPurge: true},
+		{name: "still live on the server", states: []models.PrivateDataState{{ClientSideID: "gone"}}, wantErr: ErrDeletePending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, serverAdapter, _, _ := newTrashTestSvc(t)
+			serverAdapter.EXPECT().GetServerStates(ctx, int64(1)).Return(tt.states, nil)
+			if tt.wantPurge {
+				repo.EXPECT().PurgePrivateData(ctx, "gone", int64(1)).Return(nil)
+			}
+
+			err := svc.RemoveFromTrash(ctx, 1, "gone")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
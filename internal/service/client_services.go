@@ -5,6 +5,8 @@ package service
 
 import (
 	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/crypto"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
@@ -33,6 +35,10 @@ type ClientServices struct {
 	// SyncJob is the background worker that periodically calls SyncService.FullSync
 	// at a configurable interval while the user is logged in.
 	SyncJob ClientSyncJob
+
+	// Clock is the time source shared by the services; the terminal client
+	// renders the age of the last sync with it.
+	Clock clock.Clock
 }
 
 // NewClientServices constructs and wires all client-side services.
@@ -41,21 +47,49 @@ type ClientServices struct {
 //  1. KeyChainService — provides low-level key-derivation and AES primitives.
 //  2. ClientCryptoService — wraps KeyChainService for payload encryption.
 //  3. ClientAuthService — handles registration/login using KeyChainService and
-//     ClientCryptoService.
+//     ClientCryptoService; with cfg.Offline it logs in from locally cached
+//     credentials instead of the server. The online login handshake is
+//     bounded by cfg.LoginTimeout and retried cfg.LoginRetries times.
 //  4. ClientPrivateDataService — CRUD service backed by the local store and
-//     server adapter.
-//  5. ClientSyncService — orchestrates full bidirectional sync.
+//     server adapter; Binary attachments are limited to cfg.MaxBinarySize.
+//  5. ClientSyncService — orchestrates full sync in the direction selected by
+//     cfg.SyncMode (bidirectional by default), downloading at most
+//     cfg.MaxClientSideIDs items per request; with cfg.SyncEvents set, it
+//     reports every executed operation as JSON lines to that target.
 //  6. ClientSyncJob — background ticker that calls FullSync periodically.
 //
-// Returns a fully initialised *ClientServices. The logger parameter is
+// Item timestamps and the sync state are taken from clk, which the
+// returned container keeps for the terminal client.
+//
+// When cfg.NonceAudit is set, a [crypto.NonceRecorder] is installed so that
+// any repeated AES-GCM nonce aborts the encryption instead of being used.
+//
+// Returns a fully initialised *ClientServices, or an error if the sync events
+// file cannot be opened. The logger parameter is
 // reserved for future structured logging and is currently unused.
-func NewClientServices(localStore *store.ClientStorages, serverAdapter adapter.ServerAdapter, logger *logger.Logger) (*ClientServices, error) {
+func NewClientServices(localStore *store.ClientStorages, serverAdapter adapter.ServerAdapter, cfg config.ClientApp, clk clock.Clock, logger *logger.Logger) (*ClientServices, error) {
+	if cfg.NonceAudit {
+		crypto.SetNonceRecorder(crypto.NewNonceRecorder())
+	}
 	keyChainService := crypto.NewKeyChainService()
 
 	cryptoSvc := NewClientCryptoService(keyChainService)
-	authSvc := NewClientAuthService(localStore, serverAdapter, keyChainService, cryptoSvc)
-	privateSvc := NewClientPrivateDataService(localStore, serverAdapter, cryptoSvc)
-	syncSvc := NewClientSyncService(localStore, serverAdapter)
+	authSvc := NewClientAuthService(localStore, serverAdapter, keyChainService, cryptoSvc, cfg.Offline, LoginPolicy{Timeout: cfg.LoginTimeout, Retries: cfg.LoginRetries}, clk)
+	privateSvc := NewClientPrivateDataService(localStore, serverAdapter, cryptoSvc, cfg.MaxBinarySize, cfg.MaxBatchEntries, cfg.ClientIDPrefix, clk)
+	syncEvents, err := OpenSyncEvents(cfg.SyncEvents)
+	if err != nil {
+		return nil, err
+	}
+	syncSvc := NewClientSyncService(localStore, serverAdapter, cryptoSvc, SyncPolicy{
+		Mode:             cfg.SyncMode,
+		Conflicts:        cfg.SyncConflicts,
+		ClientIDPrefix:   cfg.ClientIDPrefix,
+		BreakerThreshold: cfg.SyncBreakerThreshold,
+		BreakerCooldown:  cfg.SyncBreakerCooldown,
+		DownloadBatch:    cfg.MaxClientSideIDs,
+		MaxBinarySize:    cfg.MaxBinarySize,
+		Clock:            clk,
+	}, syncEvents)
 
 	return &ClientServices{
 		CryptoService:      cryptoSvc,
@@ -63,5 +97,6 @@ func NewClientServices(localStore *store.ClientStorages, serverAdapter adapter.S
 		PrivateDataService: privateSvc,
 		SyncService:        syncSvc,
 		SyncJob:            NewClientSyncJob(syncSvc),
+		Clock:              clk,
 	}, nil
 }
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// ToShareString serializes a single vault entry into text that can be
+// pasted into another device or app. A login with a TOTP secret becomes its
+// otpauth:// URI, with the entry name as issuer and the username as account,
+// so that an authenticator app can import it. Every other entry becomes
+// "label: value" lines with its own fields, notes and custom fields.
+//
+// Only the entry's own fields are written: nothing from the account (the
+// DEK, the master password) and no attachment content or attachment key,
+// just the file name and size.
+func ToShareString(payload models.DecipheredPayload) string {
+	if uri, ok := TOTPShareURI(payload); ok {
+		return uri
+	}
+
+	var b strings.Builder
+	writeShareLine(&b, "Название", payload.Metadata.Name)
+	if payload.Metadata.Folder != nil {
+		writeShareLine(&b, "Папка", *payload.Metadata.Folder)
+	}
+
+	switch payload.Type {
+	case models.LoginPassword:
+		if data := payload.LoginData; data != nil {
+			writeShareLine(&b, "Логин", data.Username)
+			writeShareLine(&b, "Пароль", data.Password)
+			for _, uri := range data.URIs {
+				writeShareLine(&b, "URI", uri.URI)
+			}
+		}
+	case models.Text:
+		if payload.TextData != nil {
+			writeShareLine(&b, "Текст", payload.TextData.Text)
+		}
+	case models.Binary:
+		if data := payload.BinaryData; data != nil {
+			writeShareLine(&b, "Файл", data.FileName)
+			if data.Size > 0 {
+				writeShareLine(&b, "Размер", fmt.Sprintf("%d байт", data.Size))
+			}
+		}
+	case models.BankCard:
+		if card := payload.BankCardData; card != nil {
+			writeShareLine(&b, "Держатель", card.CardholderName)
+			writeShareLine(&b, "Номер", card.Number)
+			writeShareLine(&b, "Сеть", card.Brand)
+			if card.ExpMonth != "" || card.ExpYear != "" {
+				writeShareLine(&b, "Срок", card.ExpMonth+"/"+card.ExpYear)
+			}
+			writeShareLine(&b, "CVV", card.Code)
+		}
+	}
+
+	if payload.AdditionalFields != nil {
+		for i, field := range *payload.AdditionalFields {
+			name := field.Name
+			if name == "" {
+				name = fmt.Sprintf("Поле %d", i+1)
+			}
+			writeShareLine(&b, name, string(field.Data))
+		}
+	}
+	if payload.Notes != nil {
+		writeShareLine(&b, "Заметки", payload.Notes.Notes)
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// TOTPShareURI returns the otpauth:// URI of a login with a TOTP secret, with
+// the entry name as issuer and the username as account. ok is false for
+// other entries.
+func TOTPShareURI(payload models.DecipheredPayload) (uri string, ok bool) {
+	if payload.Type != models.LoginPassword {
+		return "", false
+	}
+	totp, ok := TOTPFromLoginData(payload.LoginData)
+	if !ok {
+		return "", false
+	}
+	totp.Issuer = payload.Metadata.Name
+	totp.Account = payload.LoginData.Username
+	return totp.URI(), true
+}
+
+// writeShareLine appends "label: value" unless value is blank.
+func writeShareLine(b *strings.Builder, label, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	b.WriteString(label + ": " + value + "\n")
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToShareString(t *testing.T) {
+	folder := "Работа"
+	secret := "JBSWY3DPEHPK3PXP"
+
+	tests := []struct {
+		name    string
+		payload models.DecipheredPayload
+		want    string
+	}{
+		{
+			name: "login with TOTP becomes an otpauth URI",
+			payload: models.DecipheredPayload{
+				Type:     models.LoginPassword,
+				Metadata: models.Metadata{Name: "Example"},
+				LoginData: &models.LoginData{
+					Username: "alice@example.com",
+					Password: "p@ss",
+					TOTP:     &secret,
+					// Non-default parameters must survive the export.
+					TOTPDigits: 8,
+				},
+			},
+			want: "otpauth://totp/Example:alice@example.com?algorithm=SHA1&digits=8&issuer=Example&period=30&secret=JBSWY3DPEHPK3PXP",
+		},
+		{
+			name: "login without TOTP",
+			payload: models.DecipheredPayload{
+				Type:     models.LoginPassword,
+				Metadata: models.Metadata{Name: "Почта", Folder: &folder},
+				LoginData: &models.LoginData{
+					Username: "alice",
+					Password: "p@ss",
+					URIs:     []models.LoginURI{{URI: "https://mail.example.com"}},
+				},
+			},
+			want: "Название: Почта\nПапка: Работа\nЛогин: alice\nПароль: p@ss\nURI: https://mail.example.com",
+		},
+		{
+			name: "text with notes and custom fields",
+			payload: models.DecipheredPayload{
+				Type:             models.Text,
+				Metadata:         models.Metadata{Name: "Ключ"},
+				TextData:         &models.TextData{Text: "ssh-ed25519 AAAA"},
+				AdditionalFields: &[]models.CustomField{{Name: "host", Data: "srv"}, {Data: "x", IsSensitive: true}},
+				Notes:            &models.Notes{Notes: "старый сервер"},
+			},
+			want: "Название: Ключ\nТекст: ssh-ed25519 AAAA\nhost: srv\nПоле 2: x\nЗаметки: старый сервер",
+		},
+		{
+			name: "binary shares file name and size only",
+			payload: models.DecipheredPayload{
+				Type:     models.Binary,
+				Metadata: models.Metadata{Name: "Скан"},
+				BinaryData: &models.BinaryData{
+					ID:          "file-1",
+					FileName:    "passport.pdf",
+					Size:        2048,
+					Key:         "per-file-key",
+					NoncePrefix: "prefix",
+				},
+			},
+			want: "Название: Скан\nФайл: passport.pdf\nРазмер: 2048 байт",
+		},
+		{
+			name: "bank card",
+			payload: models.DecipheredPayload{
+				Type:     models.BankCard,
+				Metadata: models.Metadata{Name: "Карта"},
+				BankCardData: &models.BankCardData{
+					CardholderName: "ALICE",
+					Number:         "4111111111111111",
+					Brand:          "Visa",
+					ExpMonth:       "12",
+					ExpYear:        "29",
+					Code:           "123",
+				},
+			},
+			want: "Название: Карта\nДержатель: ALICE\nНомер: 4111111111111111\nСеть: Visa\nСрок: 12/29\nCVV: 123",
+		},
+		{
+			name:    "entry without data",
+			payload: models.DecipheredPayload{Type: models.Text, Metadata: models.Metadata{Name: "Пусто"}},
+			want:    "Название: Пусто",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ToShareString(tt.payload))
+		})
+	}
+}
+
+func TestTOTPConfig_URIRoundTrip(t *testing.T) {
+	cfg := TOTPConfig{
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Algorithm: "SHA256",
+		Digits:    8,
+		Period:    60,
+		Issuer:    "My Bank",
+		Account:   "alice smith",
+	}
+
+	parsed, err := ParseTOTP(cfg.URI())
+	require.NoError(t, err)
+	assert.Equal(t, cfg, parsed)
+}
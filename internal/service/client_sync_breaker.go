@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// syncBreaker is the circuit breaker in front of FullSync. After threshold
+// consecutive syncs failed on the network level it opens: for cooldown every
+// sync fails at once with [ErrServerUnavailable] instead of waiting for the
+// server to time out again. After the cooldown a single probing sync is let
+// through; it closes the breaker on success and opens it again on another
+// network failure. A nil *syncBreaker is disabled and lets every sync
+// through.
+type syncBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	clock clock.Clock
+
+	mu       sync.Mutex
+	state    models.SyncBreakerState
+	failures int
+	openedAt time.Time
+}
+
+// newSyncBreaker returns a breaker that opens after threshold consecutive
+// network failures, or nil (disabled) when threshold is not positive. The
+// cooldown is measured with clk.
+func newSyncBreaker(threshold int, cooldown time.Duration, clk clock.Clock) *syncBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &syncBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		clock:     clk,
+		state:     models.SyncBreakerClosed,
+	}
+}
+
+// guard runs fn unless the breaker is open and records its outcome. While
+// the breaker is open, and while a probe is running in the half-open state,
+// guard returns [ErrServerUnavailable] without calling fn.
+func (b *syncBreaker) guard(fn func() error) error {
+	if b == nil {
+		return fn()
+	}
+	if !b.allow() {
+		return ErrServerUnavailable
+	}
+	err := fn()
+	b.record(err)
+	return err
+}
+
+// allow reports whether a sync may run now, moving an open breaker whose
+// cooldown has passed to the half-open state for its probe.
+func (b *syncBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case models.SyncBreakerOpen:
+		if b.clock.Now().Before(b.openedAt.Add(b.cooldown)) {
+			return false
+		}
+		b.state = models.SyncBreakerHalfOpen
+		return true
+	case models.SyncBreakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// record updates the breaker with the outcome of a sync allow let through.
+// A cancelled sync says nothing about the server: it leaves the failure
+// count as it is and returns a probe's breaker to the open state, so the
+// next sync after the cooldown probes again.
+func (b *syncBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		if b.state == models.SyncBreakerHalfOpen {
+			b.state = models.SyncBreakerOpen
+		}
+	case isNetworkFailure(err):
+		b.failures++
+		if b.state == models.SyncBreakerHalfOpen || b.failures >= b.threshold {
+			b.state = models.SyncBreakerOpen
+			b.openedAt = b.clock.Now()
+		}
+	default:
+		b.state = models.SyncBreakerClosed
+		b.failures = 0
+	}
+}
+
+// observe records the outcome of a server call made outside guard, such as
+// a connectivity check, as if it were a sync. A cancelled call is ignored,
+// so that it cannot end a probe that guard let through.
+func (b *syncBreaker) observe(err error) {
+	if b == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	b.record(err)
+}
+
+// status returns a snapshot of the breaker. A nil breaker is always closed.
+func (b *syncBreaker) status() models.SyncBreakerStatus {
+	if b == nil {
+		return models.SyncBreakerStatus{State: models.SyncBreakerClosed}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st := models.SyncBreakerStatus{State: b.state, Failures: b.failures}
+	if b.state == models.SyncBreakerOpen {
+		st.RetryAt = b.openedAt.Add(b.cooldown)
+	}
+	return st
+}
+
+// isNetworkFailure reports whether err means the server could not be
+// reached: a network-level error or a 502 from a proxy in front of it.
+func isNetworkFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, adapter.ErrBadGateway) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
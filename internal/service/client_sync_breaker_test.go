@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/adapter"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// errDialRefused — сетевая ошибка, как при недоступном сервере.
+var errDialRefused error = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+// breakerStep — один вызов guard: через сколько времени после начала теста
+// он сделан и с какой ошибкой завершится fn, если будет вызвана.
+type breakerStep struct {
+	after time.Duration
+	err   error
+
+	wantCalled bool
+	wantErr    error
+	wantState  models.SyncBreakerState
+}
+
+func TestSyncBreaker_Transitions(t *testing.T) {
+	const cooldown = 30 * time.Second
+
+	tests := []struct {
+		name  string
+		steps []breakerStep
+	}{
+		{
+			name: "stays closed below threshold",
+			steps: []breakerStep{
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+			},
+		},
+		{
+			name: "opens at threshold and short-circuits",
+			steps: []breakerStep{
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerOpen},
+				{after: cooldown - time.Second, wantErr: ErrServerUnavailable, wantState: models.SyncBreakerOpen},
+			},
+		},
+		{
+			name: "successful probe closes",
+			steps: []breakerStep{
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerOpen},
+				{after: cooldown, wantCalled: true, wantState: models.SyncBreakerClosed},
+				{after: cooldown, err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+			},
+		},
+		{
+			name: "failed probe reopens at once",
+			steps: []breakerStep{
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerOpen},
+				{after: cooldown, err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerOpen},
+				{after: 2*cooldown - time.Second, wantErr: ErrServerUnavailable, wantState: models.SyncBreakerOpen},
+				{after: 2 * cooldown, wantCalled: true, wantState: models.SyncBreakerClosed},
+			},
+		},
+		{
+			name: "cancelled probe leaves breaker open",
+			steps: []breakerStep{
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerOpen},
+				{after: cooldown, err: context.Canceled, wantCalled: true, wantErr: context.Canceled, wantState: models.SyncBreakerOpen},
+				{after: cooldown, wantCalled: true, wantState: models.SyncBreakerClosed},
+			},
+		},
+		{
+			name: "bad gateway counts as network failure",
+			steps: []breakerStep{
+				{err: adapter.ErrBadGateway, wantCalled: true, wantErr: adapter.ErrBadGateway, wantState: models.SyncBreakerClosed},
+				{err: adapter.ErrBadGateway, wantCalled: true, wantErr: adapter.ErrBadGateway, wantState: models.SyncBreakerClosed},
+				{err: adapter.ErrBadGateway, wantCalled: true, wantErr: adapter.ErrBadGateway, wantState: models.SyncBreakerOpen},
+			},
+		},
+		{
+			name: "non-network error resets failures",
+			steps: []breakerStep{
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: adapter.ErrUnauthorized, wantCalled: true, wantErr: adapter.ErrUnauthorized, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+			},
+		},
+		{
+			name: "cancelled sync does not count",
+			steps: []breakerStep{
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: errDialRefused, wantCalled: true, wantErr: errDialRefused, wantState: models.SyncBreakerClosed},
+				{err: fmt.Errorf("get server states: %w", context.Canceled), wantCalled: true, wantErr: context.Canceled, wantState: models.SyncBreakerClosed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+			fake := clock.NewFake(start)
+			b := newSyncBreaker(3, cooldown, fake)
+
+			for i, step := range tt.steps {
+				fake.Set(start.Add(step.after))
+				called := false
+				err := b.guard(func() error {
+					called = true
+					return step.err
+				})
+
+				assert.Equal(t, step.wantCalled, called, "step %d: fn called", i)
+				if step.wantErr == nil {
+					assert.NoError(t, err, "step %d", i)
+				} else {
+					assert.ErrorIs(t, err, step.wantErr, "step %d", i)
+				}
+				assert.Equal(t, step.wantState, b.status().State, "step %d: state", i)
+			}
+		})
+	}
+}
+
+func TestSyncBreaker_HalfOpenLetsOneProbeThrough(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	b := newSyncBreaker(1, time.Minute, fake)
+
+	require.ErrorIs(t, b.guard(func() error { return errDialRefused }), errDialRefused)
+	fake.Advance(time.Minute)
+
+	probing := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- b.guard(func() error {
+			close(probing)
+			<-release
+			return nil
+		})
+	}()
+	<-probing
+
+	assert.Equal(t, models.SyncBreakerHalfOpen, b.status().State)
+	called := false
+	err := b.guard(func() error { called = true; return nil })
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+	assert.False(t, called, "a second sync must not run during the probe")
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, models.SyncBreakerClosed, b.status().State)
+}
+
+func TestSyncBreaker_Status(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newSyncBreaker(2, time.Minute, clock.NewFake(now))
+
+	assert.Equal(t, models.SyncBreakerStatus{State: models.SyncBreakerClosed}, b.status())
+
+	_ = b.guard(func() error { return errDialRefused })
+	assert.Equal(t, models.SyncBreakerStatus{State: models.SyncBreakerClosed, Failures: 1}, b.status())
+
+	_ = b.guard(func() error { return errDialRefused })
+	assert.Equal(t, models.SyncBreakerStatus{
+		State:    models.SyncBreakerOpen,
+		Failures: 2,
+		RetryAt:  now.Add(time.Minute),
+	}, b.status())
+}
+
+func TestSyncBreaker_Disabled(t *testing.T) {
+	for _, threshold := range []int{0, -1} {
+		b := newSyncBreaker(threshold, time.Minute, clock.Real{})
+		require.Nil(t, b)
+
+		for range 5 {
+			assert.ErrorIs(t, b.guard(func() error { return errDialRefused }), errDialRefused)
+		}
+		assert.Equal(t, models.SyncBreakerClosed, b.status().State)
+	}
+}
+
+func TestClientSyncService_FullSync_BreakerShortCircuits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+	svc.breaker = newSyncBreaker(2, time.Hour, clock.Real{})
+	ctx := context.Background()
+	userID := int64(1)
+
+	mockAdapter.EXPECT().GetServerStates(gomock.Any(), userID).Return(nil, errDialRefused).Times(2)
+
+	assert.ErrorIs(t, svc.FullSync(ctx, userID), errDialRefused)
+	assert.ErrorIs(t, svc.FullSync(ctx, userID), errDialRefused)
+
+	// The breaker is open: the server is not contacted again.
+	assert.ErrorIs(t, svc.FullSync(ctx, userID), ErrServerUnavailable)
+	st := svc.BreakerStatus()
+	assert.Equal(t, models.SyncBreakerOpen, st.State)
+	assert.Equal(t, 2, st.Failures)
+}
+
+func TestClientSyncService_Ping(t *testing.T) {
+	tests := []struct {
+		name      string
+		pingErrs  []error
+		wantErr   error
+		wantState models.SyncBreakerState
+	}{
+		{
+			name:      "reachable",
+			pingErrs:  []error{nil},
+			wantState: models.SyncBreakerClosed,
+		},
+		{
+			name:      "failing pings open the breaker",
+			pingErrs:  []error{errDialRefused, errDialRefused},
+			wantErr:   errDialRefused,
+			wantState: models.SyncBreakerOpen,
+		},
+		{
+			name:      "successful ping closes the breaker",
+			pingErrs:  []error{errDialRefused, errDialRefused, nil},
+			wantState: models.SyncBreakerClosed,
+		},
+		{
+			name:      "cancelled ping is ignored",
+			pingErrs:  []error{errDialRefused, errDialRefused, context.Canceled},
+			wantErr:   context.Canceled,
+			wantState: models.SyncBreakerOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc, _, mockAdapter, _ := newTestSyncSvc(t, ctrl)
+			svc.breaker = newSyncBreaker(2, time.Hour, clock.Real{})
+			for _, pingErr := range tt.pingErrs {
+				mockAdapter.EXPECT().Ping(gomock.Any()).Return(pingErr)
+			}
+
+			var err error
+			for range tt.pingErrs {
+				err = svc.Ping(context.Background())
+			}
+
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantState, svc.BreakerStatus().State)
+		})
+	}
+}
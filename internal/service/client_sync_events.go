@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// SyncEventsStderr is the sync events target that writes to standard error.
+const SyncEventsStderr = "stderr"
+
+// OpenSyncEvents returns the writer for the sync event stream selected by
+// target: nil for an empty target, os.Stderr for [SyncEventsStderr], and
+// otherwise the file at path target opened for appending. The file stays
+// open for the lifetime of the process.
+func OpenSyncEvents(target string) (io.Writer, error) {
+	switch target {
+	case "":
+		return nil, nil
+	case SyncEventsStderr:
+		return os.Stderr, nil
+	}
+
+	f, err := os.OpenFile(target, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open sync events file: %w", err)
+	}
+	return f, nil
+}
+
+// syncEventWriter encodes [models.SyncEvent]s as JSON lines. A nil writer
+// discards events, so callers need not check whether the stream is enabled.
+// Events are timed with clock.
+type syncEventWriter struct {
+	mu    sync.Mutex
+	enc   *json.Encoder
+	clock clock.Clock
+}
+
+func newSyncEventWriter(w io.Writer, clk clock.Clock) *syncEventWriter {
+	if w == nil {
+		return nil
+	}
+	return &syncEventWriter{enc: json.NewEncoder(w), clock: clk}
+}
+
+// write emits ev. Write errors are ignored: the stream is informational and
+// must never fail a sync.
+func (w *syncEventWriter) write(ev models.SyncEvent) {
+	if w == nil {
+		return
+	}
+	ev.Time = w.clock.Now().UTC()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_ = w.enc.Encode(ev)
+}
+
+// begin starts the events of one executed plan.
+func (w *syncEventWriter) begin() *syncEventRun {
+	if w == nil {
+		return nil
+	}
+	return &syncEventRun{w: w}
+}
+
+// syncEventRun counts the item events of one plan for its summary.
+type syncEventRun struct {
+	w         *syncEventWriter
+	succeeded int
+	failed    int
+}
+
+// record emits one event for each of ids with the outcome err.
+func (r *syncEventRun) record(op models.SyncEventOp, err error, ids ...string) {
+	if r == nil {
+		return
+	}
+	for _, id := range ids {
+		ev := models.SyncEvent{Op: op, ClientSideID: id, Result: models.SyncResultOK}
+		if err != nil {
+			ev.Result = models.SyncResultError
+			ev.Error = err.Error()
+			r.failed++
+		} else {
+			r.succeeded++
+		}
+		r.w.write(ev)
+	}
+}
+
+// finish emits the summary event; err is the outcome of the whole plan.
+func (r *syncEventRun) finish(err error) {
+	if r == nil {
+		return
+	}
+	ev := models.SyncEvent{
+		Op:        models.SyncEventSummary,
+		Result:    models.SyncResultOK,
+		Succeeded: r.succeeded,
+		Failed:    r.failed,
+	}
+	if err != nil {
+		ev.Result = models.SyncResultError
+		ev.Error = err.Error()
+	}
+	r.w.write(ev)
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// syncCall is a FullSync in progress. err is written before done is closed.
+type syncCall struct {
+	done chan struct{}
+	err  error
+
+	// waiters counts the callers that joined the call instead of starting
+	// their own.
+	waiters int
+}
+
+// syncFlights lets at most one FullSync per user run at a time: a caller that
+// arrives while a sync for the same user is in flight waits for it and gets
+// its result instead of starting a second, overlapping one. The zero value is
+// ready to use.
+type syncFlights struct {
+	mu    sync.Mutex
+	calls map[int64]*syncCall
+}
+
+// do runs fn for userID unless a run for userID is already in flight, in
+// which case it waits for that run and returns its error. A waiting caller
+// whose ctx is done stops waiting with ctx.Err(); the shared run itself is
+// bound to the context of the caller that started it.
+func (f *syncFlights) do(ctx context.Context, userID int64, fn func() error) error {
+	f.mu.Lock()
+	if c, ok := f.calls[userID]; ok {
+		c.waiters++
+		f.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if f.calls == nil {
+		f.calls = make(map[int64]*syncCall)
+	}
+	c := &syncCall{done: make(chan struct{})}
+	f.calls[userID] = c
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		delete(f.calls, userID)
+		f.mu.Unlock()
+		close(c.done)
+	}()
+	c.err = fn()
+	return c.err
+}
+
+// waiters returns how many callers joined the run in flight for userID.
+func (f *syncFlights) waiters(userID int64) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c, ok := f.calls[userID]; ok {
+		return c.waiters
+	}
+	return 0
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// Default TOTP parameters (RFC 6238), used for plain secrets and for
+// otpauth:// URIs that omit them.
+const (
+	DefaultTOTPAlgorithm = "SHA1"
+	DefaultTOTPDigits    = 6
+	DefaultTOTPPeriod    = 30
+)
+
+// totpBase32 decodes TOTP secrets, which are conventionally written without
+// padding.
+var totpBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPConfig holds everything needed to generate TOTP codes for a login.
+type TOTPConfig struct {
+	// Secret is the normalized (upper-case, unpadded) base32 shared secret.
+	Secret string
+
+	// Algorithm is "SHA1", "SHA256" or "SHA512".
+	Algorithm string
+
+	// Digits is the code length, 6 to 8.
+	Digits int
+
+	// Period is the code lifetime in seconds.
+	Period int
+
+	// Issuer and Account come from an otpauth:// URI label and are
+	// informational only.
+	Issuer  string
+	Account string
+}
+
+// ParseTOTP parses what the user typed into the TOTP field: either a full
+// otpauth://totp/... URI or a bare base32 secret. A bare secret gets the
+// defaults (SHA1, 6 digits, 30 s); a URI may override them with its
+// algorithm, digits and period parameters. It returns [ErrTOTPMalformedURI],
+// [ErrTOTPInvalidSecret] or [ErrTOTPInvalidParams] for bad input.
+func ParseTOTP(input string) (TOTPConfig, error) {
+	input = strings.TrimSpace(input)
+	if len(input) >= len("otpauth://") && strings.EqualFold(input[:len("otpauth://")], "otpauth://") {
+		return parseTOTPURI(input)
+	}
+
+	secret, err := normalizeTOTPSecret(input)
+	if err != nil {
+		return TOTPConfig{}, err
+	}
+	return TOTPConfig{
+		Secret:    secret,
+		Algorithm: DefaultTOTPAlgorithm,
+		Digits:    DefaultTOTPDigits,
+		Period:    DefaultTOTPPeriod,
+	}, nil
+}
+
+func parseTOTPURI(raw string) (TOTPConfig, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return TOTPConfig{}, fmt.Errorf("%w: %w", ErrTOTPMalformedURI, err)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return TOTPConfig{}, fmt.Errorf("%w: тип %q не поддерживается, нужен totp", ErrTOTPMalformedURI, u.Host)
+	}
+
+	query := u.Query()
+	secret, err := normalizeTOTPSecret(query.Get("secret"))
+	if err != nil {
+		return TOTPConfig{}, err
+	}
+
+	cfg := TOTPConfig{
+		Secret:    secret,
+		Algorithm: DefaultTOTPAlgorithm,
+		Digits:    DefaultTOTPDigits,
+		Period:    DefaultTOTPPeriod,
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		cfg.Issuer = strings.TrimSpace(issuer)
+		cfg.Account = strings.TrimSpace(account)
+	} else {
+		cfg.Account = strings.TrimSpace(label)
+	}
+	if issuer := strings.TrimSpace(query.Get("issuer")); issuer != "" {
+		cfg.Issuer = issuer
+	}
+
+	if v := query.Get("algorithm"); v != "" {
+		cfg.Algorithm = strings.ToUpper(v)
+	}
+	if v := query.Get("digits"); v != "" {
+		if cfg.Digits, err = strconv.Atoi(v); err != nil {
+			return TOTPConfig{}, fmt.Errorf("%w: digits=%q", ErrTOTPInvalidParams, v)
+		}
+	}
+	if v := query.Get("period"); v != "" {
+		if cfg.Period, err = strconv.Atoi(v); err != nil {
+			return TOTPConfig{}, fmt.Errorf("%w: period=%q", ErrTOTPInvalidParams, v)
+		}
+	}
+
+	if err = cfg.validate(); err != nil {
+		return TOTPConfig{}, err
+	}
+	return cfg, nil
+}
+
+// normalizeTOTPSecret strips spaces, dashes and padding, upper-cases the
+// secret and checks that it decodes as base32.
+func normalizeTOTPSecret(secret string) (string, error) {
+	secret = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '=' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(secret))
+	if secret == "" {
+		return "", ErrTOTPInvalidSecret
+	}
+	if _, err := totpBase32.DecodeString(secret); err != nil {
+		return "", ErrTOTPInvalidSecret
+	}
+	return secret, nil
+}
+
+func (c TOTPConfig) validate() error {
+	if totpHash(c.Algorithm) == nil {
+		return fmt.Errorf("%w: algorithm=%q", ErrTOTPInvalidParams, c.Algorithm)
+	}
+	if c.Digits < 6 || c.Digits > 8 {
+		return fmt.Errorf("%w: digits=%d", ErrTOTPInvalidParams, c.Digits)
+	}
+	if c.Period <= 0 {
+		return fmt.Errorf("%w: period=%d", ErrTOTPInvalidParams, c.Period)
+	}
+	return nil
+}
+
+func totpHash(algorithm string) func() hash.Hash {
+	switch algorithm {
+	case "SHA1":
+		return sha1.New
+	case "SHA256":
+		return sha256.New
+	case "SHA512":
+		return sha512.New
+	default:
+		return nil
+	}
+}
+
+// ApplyTo stores the secret and the non-default parameters in data. Default
+// parameters are left zero so that entries without custom settings keep
+// their previous JSON shape.
+func (c TOTPConfig) ApplyTo(data *models.LoginData) {
+	secret := c.Secret
+	data.TOTP = &secret
+	data.TOTPAlgorithm, data.TOTPDigits, data.TOTPPeriod = "", 0, 0
+	if c.Algorithm != DefaultTOTPAlgorithm {
+		data.TOTPAlgorithm = c.Algorithm
+	}
+	if c.Digits != DefaultTOTPDigits {
+		data.TOTPDigits = c.Digits
+	}
+	if c.Period != DefaultTOTPPeriod {
+		data.TOTPPeriod = c.Period
+	}
+}
+
+// TOTPFromLoginData returns the TOTP configuration stored in data, filling
+// in defaults for unset parameters. ok is false when data has no TOTP
+// secret.
+func TOTPFromLoginData(data *models.LoginData) (cfg TOTPConfig, ok bool) {
+	if data == nil || data.TOTP == nil || strings.TrimSpace(*data.TOTP) == "" {
+		return TOTPConfig{}, false
+	}
+	cfg = TOTPConfig{
+		Secret:    *data.TOTP,
+		Algorithm: data.TOTPAlgorithm,
+		Digits:    data.TOTPDigits,
+		Period:    data.TOTPPeriod,
+	}
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = DefaultTOTPAlgorithm
+	}
+	if cfg.Digits == 0 {
+		cfg.Digits = DefaultTOTPDigits
+	}
+	if cfg.Period == 0 {
+		cfg.Period = DefaultTOTPPeriod
+	}
+	return cfg, true
+}
+
+// URI returns c as an otpauth://totp/ URI that authenticator apps can
+// import. The label is "Issuer:Account" (or whichever of them is set) and
+// all code parameters are written explicitly. [ParseTOTP] reads it back.
+func (c TOTPConfig) URI() string {
+	label := c.Account
+	switch {
+	case c.Issuer != "" && c.Account != "":
+		label = c.Issuer + ":" + c.Account
+	case c.Issuer != "":
+		label = c.Issuer
+	}
+
+	query := url.Values{}
+	query.Set("secret", c.Secret)
+	if c.Issuer != "" {
+		query.Set("issuer", c.Issuer)
+	}
+	query.Set("algorithm", c.Algorithm)
+	query.Set("digits", strconv.Itoa(c.Digits))
+	query.Set("period", strconv.Itoa(c.Period))
+
+	u := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + label, RawQuery: query.Encode()}
+	return u.String()
+}
+
+// Code returns the TOTP code for the time step containing at (RFC 6238).
+func (c TOTPConfig) Code(at time.Time) (string, error) {
+	if err := c.validate(); err != nil {
+		return "", err
+	}
+	secret, err := normalizeTOTPSecret(c.Secret)
+	if err != nil {
+		return "", err
+	}
+	key, _ := totpBase32.DecodeString(secret)
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(at.Unix())/uint64(c.Period))
+
+	mac := hmac.New(totpHash(c.Algorithm), key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range c.Digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", c.Digits, value%mod), nil
+}
+
+// Remaining returns how long the code for at stays valid.
+func (c TOTPConfig) Remaining(at time.Time) time.Duration {
+	period := int64(c.Period)
+	if period <= 0 {
+		period = DefaultTOTPPeriod
+	}
+	return time.Duration(period-at.Unix()%period) * time.Second
+}
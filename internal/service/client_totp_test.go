@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTOTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    TOTPConfig
+		wantErr error
+	}{
+		{
+			name:  "plain secret gets defaults",
+			input: "jbsw y3dp ehpk 3pxp",
+			want:  TOTPConfig{Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA1", Digits: 6, Period: 30},
+		},
+		{
+			name:  "uri without optional parameters",
+			input: "otpauth://totp/alice@example.com?secret=JBSWY3DPEHPK3PXP",
+			want:  TOTPConfig{Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA1", Digits: 6, Period: 30, Account: "alice@example.com"},
+		},
+		{
+			name:  "uri with all parameters",
+			input: "otpauth://totp/Example:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Example&algorithm=sha256&digits=8&period=60",
+			want: TOTPConfig{
+				Secret:    "JBSWY3DPEHPK3PXP",
+				Algorithm: "SHA256",
+				Digits:    8,
+				Period:    60,
+				Issuer:    "Example",
+				Account:   "alice@example.com",
+			},
+		},
+		{
+			name:  "uri with escaped label and padded secret",
+			input: "OTPAUTH://totp/ACME%20Co:john?secret=JBSWY3DPEHPK3PXP%3D%3D%3D",
+			want:  TOTPConfig{Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA1", Digits: 6, Period: 30, Issuer: "ACME Co", Account: "john"},
+		},
+		{name: "empty secret", input: "   ", wantErr: ErrTOTPInvalidSecret},
+		{name: "plain secret not base32", input: "not-a-secret-1", wantErr: ErrTOTPInvalidSecret},
+		{name: "uri without secret", input: "otpauth://totp/alice?issuer=Example", wantErr: ErrTOTPInvalidSecret},
+		{name: "hotp uri", input: "otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP&counter=1", wantErr: ErrTOTPMalformedURI},
+		{name: "unparsable uri", input: "otpauth://totp/%zz?secret=JBSWY3DPEHPK3PXP", wantErr: ErrTOTPMalformedURI},
+		{name: "unknown algorithm", input: "otpauth://totp/a?secret=JBSWY3DPEHPK3PXP&algorithm=MD5", wantErr: ErrTOTPInvalidParams},
+		{name: "too many digits", input: "otpauth://totp/a?secret=JBSWY3DPEHPK3PXP&digits=10", wantErr: ErrTOTPInvalidParams},
+		{name: "non-numeric period", input: "otpauth://totp/a?secret=JBSWY3DPEHPK3PXP&period=soon", wantErr: ErrTOTPInvalidParams},
+		{name: "zero period", input: "otpauth://totp/a?secret=JBSWY3DPEHPK3PXP&period=0", wantErr: ErrTOTPInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTOTP(tt.input)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTOTPConfig_ApplyToRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantData models.LoginData
+	}{
+		{
+			name:     "defaults are not stored",
+			input:    "JBSWY3DPEHPK3PXP",
+			wantData: models.LoginData{},
+		},
+		{
+			name:     "custom parameters are stored",
+			input:    "otpauth://totp/a?secret=JBSWY3DPEHPK3PXP&algorithm=SHA512&digits=8&period=60",
+			wantData: models.LoginData{TOTPAlgorithm: "SHA512", TOTPDigits: 8, TOTPPeriod: 60},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseTOTP(tt.input)
+			require.NoError(t, err)
+
+			var data models.LoginData
+			cfg.ApplyTo(&data)
+			require.NotNil(t, data.TOTP)
+			assert.Equal(t, "JBSWY3DPEHPK3PXP", *data.TOTP)
+			assert.Equal(t, tt.wantData.TOTPAlgorithm, data.TOTPAlgorithm)
+			assert.Equal(t, tt.wantData.TOTPDigits, data.TOTPDigits)
+			assert.Equal(t, tt.wantData.TOTPPeriod, data.TOTPPeriod)
+
+			restored, ok := TOTPFromLoginData(&data)
+			require.True(t, ok)
+			assert.Equal(t, cfg.Secret, restored.Secret)
+			assert.Equal(t, cfg.Algorithm, restored.Algorithm)
+			assert.Equal(t, cfg.Digits, restored.Digits)
+			assert.Equal(t, cfg.Period, restored.Period)
+		})
+	}
+
+	_, ok := TOTPFromLoginData(&models.LoginData{})
+	assert.False(t, ok)
+}
+
+// TestTOTPConfig_Code checks the RFC 6238 appendix B test vectors.
+func TestTOTPConfig_Code(t *testing.T) {
+	secrets := map[string]string{
+		"SHA1":   totpBase32.EncodeToString([]byte("12345678901234567890")),
+		"SHA256": totpBase32.EncodeToString([]byte("12345678901234567890123456789012")),
+		"SHA512": totpBase32.EncodeToString([]byte("1234567890123456789012345678901234567890123456789012345678901234")),
+	}
+
+	tests := []struct {
+		unix      int64
+		algorithm string
+		want      string
+	}{
+		{unix: 59, algorithm: "SHA1", want: "94287082"},
+		{unix: 59, algorithm: "SHA256", want: "46119246"},
+		{unix: 59, algorithm: "SHA512", want: "90693936"},
+		{unix: 1111111109, algorithm: "SHA1", want: "07081804"},
+		{unix: 1234567890, algorithm: "SHA256", want: "91819424"},
+		{unix: 20000000000, algorithm: "SHA512", want: "47863826"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm+"/"+tt.want, func(t *testing.T) {
+			cfg := TOTPConfig{Secret: secrets[tt.algorithm], Algorithm: tt.algorithm, Digits: 8, Period: 30}
+			got, err := cfg.Code(time.Unix(tt.unix, 0))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	cfg := TOTPConfig{Secret: secrets["SHA1"], Algorithm: "SHA1", Digits: 6, Period: 30}
+	code, err := cfg.Code(time.Unix(59, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+	assert.Equal(t, time.Second, cfg.Remaining(time.Unix(59, 0)))
+}
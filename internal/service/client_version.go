@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VersionCompatibility is how a client build relates to the server it talks
+// to, as judged by [CheckVersionCompatibility].
+type VersionCompatibility int
+
+const (
+	// VersionCompatibilityUnknown means at least one version is not a
+	// release version, e.g. a development build without injected version.
+	VersionCompatibilityUnknown VersionCompatibility = iota
+	// VersionCompatible means both versions share the major version.
+	VersionCompatible
+	// VersionMajorMismatch means the major versions differ, so the client
+	// and the server may disagree on the API.
+	VersionMajorMismatch
+)
+
+// String returns a short description for logs.
+func (c VersionCompatibility) String() string {
+	switch c {
+	case VersionCompatible:
+		return "compatible"
+	case VersionMajorMismatch:
+		return "major versions differ, update the older side"
+	default:
+		return "compatibility unknown"
+	}
+}
+
+// CheckVersionCompatibility compares the semantic versions of the client and
+// the server by their major version. A leading "v" is accepted. The result
+// is informational: sync is gated by the schema version, not by it.
+func CheckVersionCompatibility(clientVersion, serverVersion string) VersionCompatibility {
+	clientMajor, ok := majorVersion(clientVersion)
+	if !ok {
+		return VersionCompatibilityUnknown
+	}
+	serverMajor, ok := majorVersion(serverVersion)
+	if !ok {
+		return VersionCompatibilityUnknown
+	}
+	if clientMajor != serverMajor {
+		return VersionMajorMismatch
+	}
+	return VersionCompatible
+}
+
+// majorVersion returns the major component of a "1.2.3"-style version.
+func majorVersion(version string) (int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckVersionCompatibility(t *testing.T) {
+	tests := []struct {
+		name   string
+		client string
+		server string
+		want   VersionCompatibility
+	}{
+		{name: "same version", client: "1.4.0", server: "1.4.0", want: VersionCompatible},
+		{name: "same major", client: "v1.2.0", server: "1.9.3", want: VersionCompatible},
+		{name: "major differs", client: "1.4.0", server: "2.0.0", want: VersionMajorMismatch},
+		{name: "client without version", client: "", server: "1.4.0", want: VersionCompatibilityUnknown},
+		{name: "dev server", client: "1.4.0", server: "dev", want: VersionCompatibilityUnknown},
+		{name: "not available", client: "N/A", server: "N/A", want: VersionCompatibilityUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CheckVersionCompatibility(tt.client, tt.server))
+		})
+	}
+}
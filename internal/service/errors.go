@@ -15,6 +15,11 @@ var (
 	// password does not match the stored credential hash for the given user.
 	ErrWrongPassword = errors.New("wrong password")
 
+	// ErrWeakTokenSignKey is returned at startup when the configured JWT
+	// signing key is empty, shorter than [MinTokenSignKeyLength] or a
+	// well-known default, so that tokens signed with it could be forged.
+	ErrWeakTokenSignKey = errors.New("token signing key is too weak")
+
 	// ErrTokenCreationFailed is returned when JWT library returns error
 	ErrTokenCreationFailed = errors.New("error creating JWT token")
 
@@ -54,6 +59,10 @@ var (
 	// request contains at least one blank (empty string) client-side item ID.
 	ErrValidationEmptyClientIDProvidedForSyncRequests = errors.New("empty client side ID provided for sync request")
 
+	// ErrValidationInvalidPage is returned when a paginated states request
+	// carries a negative cursor or limit.
+	ErrValidationInvalidPage = errors.New("invalid page cursor or limit")
+
 	// ErrUnauthorizedAccessToDifferentUserData is returned when the authenticated
 	// caller attempts to read or modify vault items that belong to another user.
 	ErrUnauthorizedAccessToDifferentUserData = errors.New("unauthorized access to different user's data")
@@ -72,4 +81,121 @@ var (
 	// rejects or fails to process the login request (e.g. wrong credentials,
 	// network error, or bad-gateway response from the server adapter).
 	ErrLoginOnServer = errors.New("login on server")
+
+	// ErrBinaryTooLarge is returned by the client private-data service when a
+	// Binary attachment exceeds the configured maximum size. The message is
+	// shown to the user as-is, so it is localised like the rest of the TUI.
+	ErrBinaryTooLarge = errors.New("файл слишком большой")
+
+	// ErrItemNameEmpty is returned by [NormalizeItemName] when the vault item
+	// name is empty or consists only of whitespace. Shown to the user as-is.
+	ErrItemNameEmpty = errors.New("нужно название")
+
+	// ErrItemNameTooLong is returned by [NormalizeItemName] when the vault item
+	// name exceeds [MaxItemNameLength] characters. Shown to the user as-is.
+	ErrItemNameTooLong = errors.New("название слишком длинное")
+
+	// ErrIncompatibleServerSchema is returned by the client sync service when
+	// the server's database schema is newer than the one this client was
+	// built for. Sync is refused so that the client cannot push records in an
+	// outdated format. Shown to the user as-is.
+	ErrIncompatibleServerSchema = errors.New("сервер использует более новую схему данных, обновите клиент")
+
+	// ErrInsecureConnection is returned by the client auth service when the
+	// server address uses plain http and insecure mode is not enabled (see
+	// [adapter.ErrInsecureTransport]). Shown to the user as-is.
+	ErrInsecureConnection = errors.New("небезопасное соединение: сервер доступен только по http")
+
+	// ErrServerNotResponding is returned by the client auth service when the
+	// login handshake did not finish within [LoginPolicy.Timeout] or kept
+	// timing out after every retry. Shown to the user as-is.
+	ErrServerNotResponding = errors.New("сервер не отвечает")
+
+	// ErrIntegrityCheckFailed is returned by the client sync service when the
+	// hash of a downloaded item's encrypted payload does not match the hash
+	// stored with it on the server, i.e. the item was corrupted or tampered
+	// with. The item is not saved locally. Shown to the user as-is.
+	ErrIntegrityCheckFailed = errors.New("проверка целостности записи не пройдена")
+
+	// ErrKeyNotAvailable is returned by the client crypto service when an
+	// encrypt or decrypt operation is attempted before the data-encryption
+	// key has been set, e.g. after the session lost it. The caller should
+	// send the user back through login instead of reporting a crypto failure.
+	// Shown to the user as-is.
+	ErrKeyNotAvailable = errors.New("ключ шифрования недоступен, войдите снова")
+
+	// ErrOfflineMode is returned by client services for operations that need
+	// the server or would modify the vault while the client runs in offline
+	// mode. Shown to the user as-is.
+	ErrOfflineMode = errors.New("недоступно в офлайн-режиме")
+
+	// ErrOfflineNoLocalUser is returned by the client auth service in offline
+	// mode when no credentials for the login are cached on this device.
+	// Shown to the user as-is.
+	ErrOfflineNoLocalUser = errors.New("нет сохранённых данных для входа: войдите хотя бы раз с подключением к серверу")
+
+	// ErrOfflineWrongPassword is returned by the client auth service in
+	// offline mode when the master password does not match the cached auth
+	// hash. Shown to the user as-is.
+	ErrOfflineWrongPassword = errors.New("неверный логин или мастер-пароль")
+
+	// ErrWrongMasterPassword is returned by
+	// [ClientAuthService.VerifyMasterPassword] when the password does not
+	// match the cached credentials. Shown to the user as-is.
+	ErrWrongMasterPassword = errors.New("неверный мастер-пароль")
+
+	// ErrVersionNotInHistory is returned by the client private-data service
+	// when the requested previous version is not (or no longer) kept in the
+	// server's version history. Shown to the user as-is.
+	ErrVersionNotInHistory = errors.New("версия не найдена в истории")
+
+	// ErrDeletePending is returned by the client private-data service when a
+	// deleted item is to be purged from the trash while the server still has
+	// it live, i.e. the deletion has not reached the server yet. Purging it
+	// then would let the next sync download it again. Shown to the user
+	// as-is.
+	ErrDeletePending = errors.New("удаление ещё не дошло до сервера, сначала синхронизируйте")
+
+	// ErrUploadRejected is returned for a single item the server refused to
+	// store during a best-effort upload.
+	ErrUploadRejected = errors.New("сервер отклонил запись")
+
+	// ErrUploadPartiallyFailed is returned by a sync when some of the
+	// uploaded items were rejected by the server. The other items and the
+	// rest of the sync plan are applied; the rejected items are retried on
+	// the next sync.
+	ErrUploadPartiallyFailed = errors.New("часть записей не загружена на сервер")
+
+	// ErrSyncConflicts is returned by a sync under [models.ConflictManual]
+	// when some updates were rejected with a version conflict. The
+	// conflicting items are left as they are; the error is a
+	// [*PendingConflictsError] that lists them.
+	ErrSyncConflicts = errors.New("конфликт версий, выберите, какую версию оставить")
+
+	// ErrConflictItemGone is returned by the client sync service when the
+	// server no longer has a live version of a conflicting item. The next
+	// sync applies the deletion. Shown to the user as-is.
+	ErrConflictItemGone = errors.New("запись удалена на сервере, выполните синхронизацию")
+
+	// ErrUnknownConflictChoice is returned by the client sync service for a
+	// [models.ConflictChoice] it does not support.
+	ErrUnknownConflictChoice = errors.New("неизвестный способ разрешения конфликта")
+
+	// ErrServerUnavailable is returned by the client sync service while the
+	// sync circuit breaker is open: the last syncs failed on the network
+	// level, so the server is not contacted until the cooldown has passed.
+	// Shown to the user as-is.
+	ErrServerUnavailable = errors.New("сервер недоступен, синхронизация приостановлена")
+
+	// ErrTOTPMalformedURI is returned by [ParseTOTP] for an otpauth:// URI
+	// that cannot be parsed or is not a TOTP URI. Shown to the user as-is.
+	ErrTOTPMalformedURI = errors.New("некорректная ссылка otpauth://")
+
+	// ErrTOTPInvalidSecret is returned by [ParseTOTP] when the TOTP secret is
+	// missing or is not valid base32. Shown to the user as-is.
+	ErrTOTPInvalidSecret = errors.New("секрет TOTP должен быть в base32")
+
+	// ErrTOTPInvalidParams is returned by [ParseTOTP] for an unsupported
+	// algorithm, digit count or period. Shown to the user as-is.
+	ErrTOTPInvalidParams = errors.New("неподдерживаемые параметры TOTP")
 )
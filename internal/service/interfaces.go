@@ -37,6 +37,13 @@ type PrivateDataService interface {
 	// Returns an error if validation fails or the storage layer rejects the write.
 	UploadPrivateData(ctx context.Context, data models.UploadRequest) error
 
+	// UploadPrivateDataEach is the best-effort variant of UploadPrivateData:
+	// every item is validated and stored on its own, so one bad item does
+	// not block the rest. The returned slice holds one error per item of
+	// data.PrivateDataList, nil for the stored ones. The second result
+	// reports failures of the request as a whole, such as a missing user.
+	UploadPrivateDataEach(ctx context.Context, data models.UploadRequest) ([]error, error)
+
 	// DownloadPrivateData retrieves a filtered set of vault items matching the
 	// criteria in downloadRequests (e.g. specific client-side IDs).
 	// Returns the matching items or an error if the query fails.
@@ -46,10 +53,11 @@ type PrivateDataService interface {
 	// Returns the full collection or an error if the query fails.
 	DownloadAllPrivateData(ctx context.Context, userID int64) ([]models.PrivateData, error)
 
-	// DownloadUserPrivateDataStates returns lightweight state descriptors
-	// (client-side ID + version) for all vault items owned by userID.
-	// Clients use these states to detect which items need to be synced.
-	DownloadUserPrivateDataStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error)
+	// DownloadUserPrivateDataStates returns one page of lightweight state
+	// descriptors (client-side ID + version) for the vault items owned by
+	// request.UserID. Clients use these states to detect which items need to
+	// be synced.
+	DownloadUserPrivateDataStates(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error)
 
 	// DownloadSpecificUserPrivateDataStates returns state descriptors only for
 	// the vault items whose client-side IDs are listed in syncRequest.
@@ -67,6 +75,23 @@ type PrivateDataService interface {
 	// DeletePrivateData soft-deletes the vault items listed in deleteRequests.
 	// Returns an error if validation fails or the storage layer rejects the operation.
 	DeletePrivateData(ctx context.Context, deleteRequests models.DeleteRequest) error
+
+	// RestorePrivateData undoes the soft-delete of the vault items listed in
+	// restoreRequests. Each entry carries the tombstone's current version for
+	// optimistic concurrency control.
+	// Returns an error if validation fails, a version conflict is detected, or
+	// the item does not exist.
+	RestorePrivateData(ctx context.Context, restoreRequests models.RestoreRequest) error
+
+	// GetVersionHistory returns the archived previous versions of the vault
+	// item of userID identified by clientSideID, newest first. Payloads stay
+	// encrypted. The result is empty when the server keeps no history.
+	GetVersionHistory(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error)
+
+	// GetAllClientSideIDs returns the client-side ID and deleted flag of
+	// every vault item of userID, tombstones included. Clients diff the list
+	// against their local IDs to find items the server never received.
+	GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error)
 }
 
 // SyncService defines the contract for computing a client-server synchronisation plan.
@@ -88,8 +113,11 @@ type SyncService interface {
 	//   DeleteServer — remove remotely (client holds a newer soft-deleted version)
 	//
 	// Items that are identical on both sides produce no entry in the plan.
+	// serverHardDelete reports whether the server removes deleted items
+	// instead of keeping tombstones; only then is a synced item missing from
+	// serverData treated as deleted on the server.
 	// ctx is forwarded to allow cancellation of any I/O performed internally.
-	BuildSyncPlan(ctx context.Context, serverData, clientData []models.PrivateDataState) (models.SyncPlan, error)
+	BuildSyncPlan(ctx context.Context, serverData, clientData []models.PrivateDataState, serverHardDelete bool) (models.SyncPlan, error)
 }
 
 // AuthService defines the contract for user authentication and JWT token management.
@@ -126,6 +154,39 @@ type AppInfoService interface {
 	// GetAppVersion returns the current semantic version string of the running
 	// application (e.g. "1.2.3" or "dev").
 	GetAppVersion(ctx context.Context) string
+
+	// GetSchemaVersion returns the version of the newest applied database
+	// migration. Clients use it to refuse syncing with a server whose cipher
+	// schema is newer than they understand.
+	GetSchemaVersion(ctx context.Context) (int64, error)
+
+	// HardDelete reports whether deleted items are removed instead of kept
+	// as tombstones. Clients only take an item missing on the server for a
+	// deleted one when it is set.
+	HardDelete(ctx context.Context) bool
+}
+
+// AuditService defines the contract for reading the append-only audit trail
+// of vault mutations. Entries carry metadata only, never payloads.
+type AuditService interface {
+	// GetAuditLog returns a page of audit entries for request.UserID, newest
+	// first. A non-positive Limit selects the default page size; Limit is
+	// capped at the maximum page size.
+	GetAuditLog(ctx context.Context, request models.AuditLogRequest) ([]models.AuditEntry, error)
+}
+
+// SyncActivityService tracks when each user last synced, so that operators
+// can spot clients that stopped syncing.
+type SyncActivityService interface {
+	// RecordActivity marks userID as seen now. It is called after every
+	// successful sync or vault request of the user.
+	RecordActivity(ctx context.Context, userID int64) error
+
+	// GetSyncLag returns a page of users with the time they were last seen
+	// and the lag since then, stalest first. request.UserID selects one
+	// user; zero lists every user seen so far. A non-positive Limit selects
+	// the default page size; Limit is capped at the maximum page size.
+	GetSyncLag(ctx context.Context, request models.SyncLagRequest) ([]models.SyncLag, error)
 }
 
 // PrivateDataServiceWrapper defines the middleware composition contract for
@@ -8,6 +8,7 @@ import (
 
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
 )
 
 // appInfoService is the concrete implementation of AppInfoService.
@@ -18,26 +19,36 @@ type appInfoService struct {
 	// (e.g. "1.2.3" or "dev"), sourced from config.App.Version.
 	appVersion string
 
+	// schemaRepository reports the applied database migration version.
+	schemaRepository store.SchemaRepository
+
+	// hardDelete reports that the storage removes deleted items instead of
+	// keeping tombstones; see [config.Storage.HardDelete].
+	hardDelete bool
+
 	// logger is the structured logger used for diagnostic output.
 	logger *logger.Logger
 }
 
 // NewAppInfoService constructs a new AppInfoService from the provided
-// application configuration and logger.
+// application configuration, schema repository, delete mode of the storage
+// and logger.
 //
 // It validates that cfg.Version is non-empty; if the version is missing,
 // ErrVersionIsNotSpecified is returned so that the application fails fast
 // at startup rather than serving an empty version string at runtime.
 //
 // Returns the initialised AppInfoService or an error if validation fails.
-func NewAppInfoService(cfg config.App, logger *logger.Logger) (AppInfoService, error) {
+func NewAppInfoService(cfg config.App, schemaRepository store.SchemaRepository, hardDelete bool, logger *logger.Logger) (AppInfoService, error) {
 	if cfg.Version == "" {
 		return nil, ErrVersionIsNotSpecified
 	}
 
 	return &appInfoService{
-		appVersion: cfg.Version,
-		logger:     logger,
+		appVersion:       cfg.Version,
+		schemaRepository: schemaRepository,
+		hardDelete:       hardDelete,
+		logger:           logger,
 	}, nil
 }
 
@@ -46,3 +57,15 @@ func NewAppInfoService(cfg config.App, logger *logger.Logger) (AppInfoService, e
 func (s *appInfoService) GetAppVersion(ctx context.Context) string {
 	return s.appVersion
 }
+
+// GetSchemaVersion returns the version of the newest applied database
+// migration as reported by the schema repository.
+func (s *appInfoService) GetSchemaVersion(ctx context.Context) (int64, error) {
+	return s.schemaRepository.SchemaVersion(ctx)
+}
+
+// HardDelete reports whether the storage removes deleted items instead of
+// keeping tombstones.
+func (s *appInfoService) HardDelete(ctx context.Context) bool {
+	return s.hardDelete
+}
@@ -21,7 +21,7 @@ import (
 func TestNewAppInfoService_Success(t *testing.T) {
 	cfg := config.App{Version: "1.0.0"}
 
-	svc, err := NewAppInfoService(cfg, logger.Nop())
+	svc, err := NewAppInfoService(cfg, nil, false, logger.Nop())
 
 	require.NoError(t, err)
 	require.NotNil(t, svc)
@@ -30,7 +30,7 @@ func TestNewAppInfoService_Success(t *testing.T) {
 func TestNewAppInfoService_EmptyVersion_ReturnsError(t *testing.T) {
 	cfg := config.App{Version: ""}
 
-	svc, err := NewAppInfoService(cfg, logger.Nop())
+	svc, err := NewAppInfoService(cfg, nil, false, logger.Nop())
 
 	assert.Nil(t, svc)
 	require.Error(t, err)
@@ -40,7 +40,7 @@ func TestNewAppInfoService_EmptyVersion_ReturnsError(t *testing.T) {
 func TestNewAppInfoService_ReturnsAppInfoServiceInterface(t *testing.T) {
 	cfg := config.App{Version: "2.5.1"}
 
-	svc, err := NewAppInfoService(cfg, logger.Nop())
+	svc, err := NewAppInfoService(cfg, nil, false, logger.Nop())
 
 	require.NoError(t, err)
 	// compile-time check: returned value must satisfy the interface
@@ -53,7 +53,7 @@ func TestNewAppInfoService_ReturnsAppInfoServiceInterface(t *testing.T) {
 
 func TestGetAppVersion_ReturnsConfiguredVersion(t *testing.T) {
 	cfg := config.App{Version: "3.1.4"}
-	svc, err := NewAppInfoService(cfg, logger.Nop())
+	svc, err := NewAppInfoService(cfg, nil, false, logger.Nop())
 	require.NoError(t, err)
 
 	got := svc.GetAppVersion(context.Background())
@@ -63,7 +63,7 @@ func TestGetAppVersion_ReturnsConfiguredVersion(t *testing.T) {
 
 func TestGetAppVersion_VersionIsStable(t *testing.T) {
 	cfg := config.App{Version: "0.0.1"}
-	svc, err := NewAppInfoService(cfg, logger.Nop())
+	svc, err := NewAppInfoService(cfg, nil, false, logger.Nop())
 	require.NoError(t, err)
 
 	ctx := context.Background()
@@ -74,10 +74,10 @@ func TestGetAppVersion_VersionIsStable(t *testing.T) {
 }
 
 func TestGetAppVersion_DifferentInstances_IndependentVersions(t *testing.T) {
-	svc1, err := NewAppInfoService(config.App{Version: "1.0.0"}, logger.Nop())
+	svc1, err := NewAppInfoService(config.App{Version: "1.0.0"}, nil, false, logger.Nop())
 	require.NoError(t, err)
 
-	svc2, err := NewAppInfoService(config.App{Version: "2.0.0"}, logger.Nop())
+	svc2, err := NewAppInfoService(config.App{Version: "2.0.0"}, nil, false, logger.Nop())
 	require.NoError(t, err)
 
 	assert.Equal(t, "1.0.0", svc1.GetAppVersion(context.Background()))
@@ -86,14 +86,14 @@ func TestGetAppVersion_DifferentInstances_IndependentVersions(t *testing.T) {
 
 func TestGetAppVersion_VersionWithSpecialChars(t *testing.T) {
 	version := "v1.2.3-beta+build.42"
-	svc, err := NewAppInfoService(config.App{Version: version}, logger.Nop())
+	svc, err := NewAppInfoService(config.App{Version: version}, nil, false, logger.Nop())
 	require.NoError(t, err)
 
 	assert.Equal(t, version, svc.GetAppVersion(context.Background()))
 }
 
 func TestGetAppVersion_CancelledContext_StillReturnsVersion(t *testing.T) {
-	svc, err := NewAppInfoService(config.App{Version: "1.0.0"}, logger.Nop())
+	svc, err := NewAppInfoService(config.App{Version: "1.0.0"}, nil, false, logger.Nop())
 	require.NoError(t, err)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -102,3 +102,48 @@ func TestGetAppVersion_CancelledContext_StillReturnsVersion(t *testing.T) {
 	// GetAppVersion does not use ctx, so it must still return the version
 	assert.Equal(t, "1.0.0", svc.GetAppVersion(ctx))
 }
+
+// ─────────────────────────────────────────────
+// GetSchemaVersion
+// ─────────────────────────────────────────────
+
+type stubSchemaRepository struct {
+	version int64
+	err     error
+}
+
+func (s stubSchemaRepository) CheckSchema(_ context.Context) error {
+	return nil
+}
+
+func (s stubSchemaRepository) SchemaVersion(_ context.Context) (int64, error) {
+	return s.version, s.err
+}
+
+func TestGetSchemaVersion_DelegatesToRepository(t *testing.T) {
+	svc, err := NewAppInfoService(config.App{Version: "1.0.0"}, stubSchemaRepository{version: 8}, false, logger.Nop())
+	require.NoError(t, err)
+
+	got, err := svc.GetSchemaVersion(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), got)
+}
+
+func TestGetSchemaVersion_RepositoryError(t *testing.T) {
+	repoErr := errors.New("query failed")
+	svc, err := NewAppInfoService(config.App{Version: "1.0.0"}, stubSchemaRepository{err: repoErr}, false, logger.Nop())
+	require.NoError(t, err)
+
+	_, err = svc.GetSchemaVersion(context.Background())
+
+	assert.ErrorIs(t, err, repoErr)
+}
+
+func TestAppInfoService_HardDelete(t *testing.T) {
+	for _, hardDelete := range []bool{false, true} {
+		svc, err := NewAppInfoService(config.App{Version: "1.0.0"}, nil, hardDelete, logger.Nop())
+		require.NoError(t, err)
+		assert.Equal(t, hardDelete, svc.HardDelete(context.Background()))
+	}
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+const (
+	// DefaultAuditPageSize is the number of audit entries returned when the
+	// caller does not specify a limit.
+	DefaultAuditPageSize = 50
+
+	// MaxAuditPageSize caps the number of audit entries returned per page.
+	MaxAuditPageSize = 500
+)
+
+// auditService is the concrete implementation of [AuditService].
+type auditService struct {
+	repo   store.AuditLogRepository
+	logger *logger.Logger
+}
+
+// NewAuditService constructs an [AuditService] backed by repo.
+func NewAuditService(repo store.AuditLogRepository, logger *logger.Logger) AuditService {
+	return &auditService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetAuditLog implements [AuditService]. It rejects a missing user ID or a
+// negative offset with [ErrInvalidDataProvided] and normalises the limit
+// before querying the repository.
+func (a *auditService) GetAuditLog(ctx context.Context, request models.AuditLogRequest) ([]models.AuditEntry, error) {
+	if request.UserID <= 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDataProvided, ErrValidationNoUserID)
+	}
+	if request.Offset < 0 {
+		return nil, fmt.Errorf("%w: negative offset", ErrInvalidDataProvided)
+	}
+
+	switch {
+	case request.Limit <= 0:
+		request.Limit = DefaultAuditPageSize
+	case request.Limit > MaxAuditPageSize:
+		request.Limit = MaxAuditPageSize
+	}
+
+	entries, err := a.repo.GetAuditLog(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("get audit log: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuditLogRepository struct {
+	got     models.AuditLogRequest
+	calls   int
+	entries []models.AuditEntry
+	err     error
+}
+
+func (m *mockAuditLogRepository) GetAuditLog(_ context.Context, request models.AuditLogRequest) ([]models.AuditEntry, error) {
+	m.calls++
+	m.got = request
+	return m.entries, m.err
+}
+
+func TestAuditService_GetAuditLog(t *testing.T) {
+	entries := []models.AuditEntry{{ID: 1, UserID: 7, Operation: models.AuditOperationCreate, ClientSideID: "cid", Version: 1}}
+	repoErr := errors.New("db down")
+
+	tests := []struct {
+		name      string
+		request   models.AuditLogRequest
+		repoErr   error
+		wantLimit int
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "default limit", request: models.AuditLogRequest{UserID: 7}, wantLimit: DefaultAuditPageSize, wantCalls: 1},
+		{name: "explicit limit kept", request: models.AuditLogRequest{UserID: 7, Limit: 10, Offset: 20}, wantLimit: 10, wantCalls: 1},
+		{name: "limit capped", request: models.AuditLogRequest{UserID: 7, Limit: MaxAuditPageSize + 1}, wantLimit: MaxAuditPageSize, wantCalls: 1},
+		{name: "missing user", request: models.AuditLogRequest{}, wantErr: ErrInvalidDataProvided},
+		{name: "negative offset", request: models.AuditLogRequest{UserID: 7, Offset: -1}, wantErr: ErrInvalidDataProvided},
+		{name: "repository error", request: models.AuditLogRequest{UserID: 7}, repoErr: repoErr, wantLimit: DefaultAuditPageSize, wantCalls: 1, wantErr: repoErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockAuditLogRepository{entries: entries, err: tt.repoErr}
+			svc := NewAuditService(repo, logger.Nop())
+
+			got, err := svc.GetAuditLog(context.Background(), tt.request)
+
+			assert.Equal(t, tt.wantCalls, repo.calls)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, entries, got)
+			assert.Equal(t, tt.wantLimit, repo.got.Limit)
+			assert.Equal(t, tt.request.Offset, repo.got.Offset)
+		})
+	}
+}
@@ -6,8 +6,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
@@ -37,24 +39,74 @@ type authService struct {
 	// tokenDuration controls how long a newly issued JWT remains valid.
 	tokenDuration time.Duration
 
+	// clock issues tokens and checks their expiry.
+	clock clock.Clock
+
 	// logger is the structured logger used for diagnostic and error output.
 	logger *logger.Logger
 }
 
+// MinTokenSignKeyLength is the minimum length, in bytes, of the JWT signing
+// key: HS256 needs a key at least as long as its 256-bit output.
+const MinTokenSignKeyLength = 32
+
+// knownTokenSignKeys are signing keys found in examples and templates,
+// compared case-insensitively. A server using one of them accepts tokens
+// anyone can sign.
+var knownTokenSignKeys = []string{
+	"secret",
+	"changeme",
+	"change-me",
+	"jwt-secret",
+	"jwt_secret",
+	"your-256-bit-secret",
+	"super-secret-token-sign-key",
+}
+
+// validateTokenSignKey returns [ErrWeakTokenSignKey] explaining why key must
+// not be used to sign tokens, or nil if it is acceptable.
+func validateTokenSignKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("%w: the key is not set", ErrWeakTokenSignKey)
+	}
+	for _, known := range knownTokenSignKeys {
+		if strings.EqualFold(strings.TrimSpace(key), known) {
+			return fmt.Errorf("%w: the key is a well-known default value", ErrWeakTokenSignKey)
+		}
+	}
+	if len(key) < MinTokenSignKeyLength {
+		return fmt.Errorf("%w: the key is %d bytes long, at least %d are required", ErrWeakTokenSignKey, len(key), MinTokenSignKeyLength)
+	}
+	if strings.Count(key, key[:1]) == len(key) {
+		return fmt.Errorf("%w: the key repeats a single character", ErrWeakTokenSignKey)
+	}
+	return nil
+}
+
 // NewAuthService constructs a new AuthService wired to the given UserRepository
-// and populated with security parameters from cfg.
+// and populated with security parameters from cfg. Tokens are issued and
+// checked for expiry with clk.
+//
+// Returns [ErrWeakTokenSignKey] if cfg.TokenSignKey is empty, shorter than
+// [MinTokenSignKeyLength] or a well-known default, so that the server fails
+// at startup instead of issuing forgeable tokens.
 //
 // The returned service is safe for concurrent use; all state is read-only after
 // construction.
-func NewAuthService(userRepository store.UserRepository, cfg config.App, logger *logger.Logger) AuthService {
+func NewAuthService(userRepository store.UserRepository, cfg config.App, clk clock.Clock, logger *logger.Logger) (AuthService, error) {
+	if err := validateTokenSignKey(cfg.TokenSignKey); err != nil {
+		return nil, err
+	}
+
 	return &authService{
 		userRepository: userRepository,
 		hashKey:        cfg.PasswordHashKey,
 		tokenSignKey:   cfg.TokenSignKey,
 		tokenIssuer:    cfg.TokenIssuer,
 		tokenDuration:  cfg.TokenDuration,
+		clock:          clk,
 		logger:         logger,
-	}
+	}, nil
 }
 
 // RegisterUser creates a new user account.
@@ -149,11 +201,12 @@ func (a *authService) Params(ctx context.Context, user models.User) (models.User
 // CreateToken issues a signed JWT for the given user.
 //
 // The token is signed with the configured tokenSignKey, carries the configured
-// tokenIssuer as the "iss" claim, and expires after tokenDuration.
+// tokenIssuer as the "iss" claim, and expires tokenDuration after the current
+// time of the service clock.
 //
 // Returns the token model on success or a wrapped error if JWT generation fails.
 func (a *authService) CreateToken(ctx context.Context, user models.User) (models.Token, error) {
-	token, err := utils.GenerateJWTToken(a.tokenIssuer, user.UserID, a.tokenDuration, a.tokenSignKey)
+	token, err := utils.GenerateJWTTokenAt(a.clock.Now(), a.tokenIssuer, user.UserID, a.tokenDuration, a.tokenSignKey)
 	if err != nil {
 		return models.Token{}, fmt.Errorf("%w: %w", ErrTokenCreationFailed, err)
 	}
@@ -163,15 +216,15 @@ func (a *authService) CreateToken(ctx context.Context, user models.User) (models
 
 // ParseToken validates and parses a raw JWT string.
 //
-// It delegates to utils.ValidateAndParseJWTToken, verifying the signature and
-// the issuer claim. Any validation failure (expired, wrong issuer, malformed)
+// It delegates to utils.ValidateAndParseJWTTokenAt, verifying the signature,
+// the issuer claim and the expiry against the service clock. Any validation failure (expired, wrong issuer, malformed)
 // is normalised to ErrTokenIsExpiredOrInvalid so that callers do not need to
 // inspect low-level JWT errors.
 //
 // Returns the decoded token model on success or ErrTokenIsExpiredOrInvalid on
 // any validation failure.
 func (a *authService) ParseToken(ctx context.Context, tokenString string) (models.Token, error) {
-	token, err := utils.ValidateAndParseJWTToken(tokenString, a.tokenSignKey, a.tokenIssuer)
+	token, err := utils.ValidateAndParseJWTTokenAt(a.clock.Now(), tokenString, a.tokenSignKey, a.tokenIssuer)
 	if err != nil {
 		return models.Token{}, ErrTokenIsExpiredOrInvalid
 	}
@@ -2,3 +2,86 @@
 // Copyright 2026 Rasul Khiriev
 
 package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuthService_TokenSignKeyStrength(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "empty", key: "", wantErr: true},
+		{name: "too short", key: "0123456789abcdef", wantErr: true},
+		{name: "one byte short", key: strings.Repeat("ab", 15) + "c", wantErr: true},
+		{name: "well-known default", key: "Super-Secret-Token-Sign-Key", wantErr: true},
+		{name: "repeated character", key: strings.Repeat("a", 64), wantErr: true},
+		{name: "strong", key: "q3Vt9ZkP0xLm2sR8wY5bN7cJ4hF6dG1e"},
+		{name: "long passphrase", key: "correct horse battery staple, but much longer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, err := NewAuthService(nil, config.App{TokenSignKey: tt.key}, clock.Real{}, logger.Nop())
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrWeakTokenSignKey)
+				assert.Nil(t, svc)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, svc)
+		})
+	}
+}
+
+func TestAuthService_TokenExpiry(t *testing.T) {
+	const tokenDuration = time.Hour
+	issuedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		after   time.Duration
+		wantErr bool
+	}{
+		{name: "just issued", after: 0},
+		{name: "one second before expiry", after: tokenDuration - time.Second},
+		{name: "at expiry", after: tokenDuration, wantErr: true},
+		{name: "after expiry", after: tokenDuration + time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clock.NewFake(issuedAt)
+			svc, err := NewAuthService(nil, config.App{
+				TokenSignKey:  "q3Vt9ZkP0xLm2sR8wY5bN7cJ4hF6dG1e",
+				TokenIssuer:   "go-pass-keeper",
+				TokenDuration: tokenDuration,
+			}, fake, logger.Nop())
+			require.NoError(t, err)
+
+			token, err := svc.CreateToken(context.Background(), models.User{UserID: 7})
+			require.NoError(t, err)
+
+			fake.Advance(tt.after)
+			parsed, err := svc.ParseToken(context.Background(), token.SignedString)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrTokenIsExpiredOrInvalid)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), parsed.UserID)
+		})
+	}
+}
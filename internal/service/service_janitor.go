@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+)
+
+// IdleEvictor is implemented by services that keep per-user or per-client
+// state in memory. EvictIdle drops every entry last accessed before cutoff
+// and returns the number of entries dropped.
+type IdleEvictor interface {
+	EvictIdle(cutoff time.Time) int
+}
+
+// Janitor periodically evicts stale entries from the in-memory state of the
+// server services, so that a long-running server does not grow without bound.
+// It is idle until Start is called.
+type Janitor struct {
+	evictors []IdleEvictor
+	ttl      time.Duration
+	interval time.Duration
+	logger   *logger.Logger
+	clock    clock.Clock
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewJanitor creates a [Janitor] that evicts the state of evictors older than
+// cfg.StateTTL every cfg.StateCleanupInterval; see [config.Server]. The age
+// of the entries is measured with clk.
+func NewJanitor(cfg config.Server, clk clock.Clock, logger *logger.Logger, evictors ...IdleEvictor) *Janitor {
+	return &Janitor{
+		evictors: evictors,
+		ttl:      cfg.StateTTLOrDefault(),
+		interval: cfg.StateCleanupIntervalOrDefault(),
+		logger:   logger,
+		clock:    clk,
+	}
+}
+
+// Start launches the background cleanup. It stops any previously running
+// cleanup first and does nothing when the cleanup is disabled or there is
+// nothing to clean. The goroutine exits when ctx is cancelled or Stop is
+// called.
+func (j *Janitor) Start(ctx context.Context) {
+	j.Stop()
+	if j.interval <= 0 || len(j.evictors) == 0 {
+		return
+	}
+
+	j.mu.Lock()
+	jobCtx, cancel := context.WithCancel(ctx)
+	j.cancel = cancel
+	j.wg.Add(1)
+	j.mu.Unlock()
+
+	go func() {
+		defer j.wg.Done()
+		t := time.NewTicker(j.interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-jobCtx.Done():
+				return
+			case <-t.C:
+				if evicted := j.Sweep(); evicted > 0 {
+					j.logger.Debug().Int("evicted", evicted).Msg("evicted stale in-memory state")
+				}
+			}
+		}
+	}()
+}
+
+// Stop cancels the background cleanup and blocks until it has exited. Safe
+// to call when the cleanup is not running.
+func (j *Janitor) Stop() {
+	j.mu.Lock()
+	cancel := j.cancel
+	j.cancel = nil
+	j.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	j.wg.Wait()
+}
+
+// Sweep runs one cleanup pass over every evictor and returns the total
+// number of evicted entries.
+func (j *Janitor) Sweep() int {
+	cutoff := j.clock.Now().Add(-j.ttl)
+
+	evicted := 0
+	for _, e := range j.evictors {
+		evicted += e.EvictIdle(cutoff)
+	}
+	return evicted
+}
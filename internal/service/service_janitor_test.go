@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingEvictor struct {
+	calls atomic.Int32
+}
+
+func (e *countingEvictor) EvictIdle(time.Time) int {
+	e.calls.Add(1)
+	return 0
+}
+
+func TestJanitor_SweepEvictsStaleSyncActivity(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	repo := &mockSyncActivityRepository{}
+	svc := newTestSyncActivityService(repo, fake)
+
+	janitor := NewJanitor(config.Server{StateTTL: time.Hour}, fake, logger.Nop(), svc)
+
+	require.NoError(t, svc.RecordActivity(ctx, 1))
+	fake.Set(start.Add(50 * time.Minute))
+	require.NoError(t, svc.RecordActivity(ctx, 2))
+
+	tests := []struct {
+		name        string
+		at          time.Time
+		wantEvicted int
+		wantUsers   []int64
+	}{
+		{name: "both within the TTL", at: start.Add(59 * time.Minute), wantEvicted: 0, wantUsers: []int64{1, 2}},
+		{name: "first past the TTL", at: start.Add(61 * time.Minute), wantEvicted: 1, wantUsers: []int64{2}},
+		{name: "second past the TTL", at: start.Add(111 * time.Minute), wantEvicted: 1, wantUsers: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.Set(tt.at)
+			assert.Equal(t, tt.wantEvicted, janitor.Sweep())
+
+			svc.mu.Lock()
+			var users []int64
+			for userID := range svc.lastSeen {
+				users = append(users, userID)
+			}
+			svc.mu.Unlock()
+			assert.ElementsMatch(t, tt.wantUsers, users)
+		})
+	}
+
+	// An evicted user is written to the repository again on the next sync.
+	touched := len(repo.touched)
+	require.NoError(t, svc.RecordActivity(ctx, 1))
+	assert.Len(t, repo.touched, touched+1)
+}
+
+func TestJanitor_StartAndStop(t *testing.T) {
+	evictor := &countingEvictor{}
+	janitor := NewJanitor(config.Server{StateCleanupInterval: time.Millisecond}, clock.Real{}, logger.Nop(), evictor)
+
+	janitor.Start(context.Background())
+	require.Eventually(t, func() bool { return evictor.calls.Load() >= 2 }, time.Second, time.Millisecond)
+
+	janitor.Stop()
+	calls := evictor.calls.Load()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, calls, evictor.calls.Load(), "no sweep after Stop")
+
+	janitor.Stop()
+}
+
+func TestJanitor_Disabled(t *testing.T) {
+	evictor := &countingEvictor{}
+	janitor := NewJanitor(config.Server{StateCleanupInterval: -1}, clock.Real{}, logger.Nop(), evictor)
+
+	janitor.Start(context.Background())
+	time.Sleep(10 * time.Millisecond)
+	janitor.Stop()
+
+	assert.Zero(t, evictor.calls.Load())
+}
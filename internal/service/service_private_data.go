@@ -31,14 +31,14 @@ type privateDataService struct {
 // NewPrivateDataValidationService().Wrap(), so every public method call is
 // validated before reaching the storage layer.
 //
-// The cfg parameter is accepted for future configuration needs (e.g. size
-// limits) and is currently unused by the core service itself.
+// cfg supplies the notes length limit and the per-request entry cap enforced
+// by the validation layer; it is unused by the core service itself.
 func NewPrivateDataService(privateDataRepository store.PrivateDataStorage, cfg config.App, logger *logger.Logger) PrivateDataService {
 	service := &privateDataService{
 		privateDataRepository: privateDataRepository,
 		logger:                logger,
 	}
-	validationService := NewPrivateDataValidationService()
+	validationService := NewPrivateDataValidationService(cfg.NotesLengthLimit(), cfg.BatchEntriesLimit())
 
 	return validationService.Wrap(service)
 }
@@ -50,6 +50,17 @@ func (p *privateDataService) UploadPrivateData(ctx context.Context, uploadReques
 	return p.privateDataRepository.Save(ctx, uploadRequest.PrivateDataList...)
 }
 
+// UploadPrivateDataEach stores every item of uploadRequest.PrivateDataList in
+// its own transaction and returns the error of each item, nil for the stored
+// ones.
+func (p *privateDataService) UploadPrivateDataEach(ctx context.Context, uploadRequest models.UploadRequest) ([]error, error) {
+	errs := make([]error, len(uploadRequest.PrivateDataList))
+	for i, data := range uploadRequest.PrivateDataList {
+		errs[i] = p.privateDataRepository.Save(ctx, data)
+	}
+	return errs, nil
+}
+
 // DownloadPrivateData retrieves the vault items identified by downloadRequests.
 // Returns the matching items or an error if the storage query fails.
 func (p *privateDataService) DownloadPrivateData(ctx context.Context, downloadRequests models.DownloadRequest) ([]models.PrivateData, error) {
@@ -62,13 +73,13 @@ func (p *privateDataService) DownloadAllPrivateData(ctx context.Context, userID
 	return p.privateDataRepository.GetAll(ctx, userID)
 }
 
-// DownloadUserPrivateDataStates returns lightweight state descriptors
-// (client-side ID + version) for all vault items owned by userID.
-// Clients use these states to detect which items are out of date and need
-// to be re-downloaded.
+// DownloadUserPrivateDataStates returns one page of lightweight state
+// descriptors (client-side ID + version) for the vault items owned by
+// request.UserID. Clients use these states to detect which items are out of
+// date and need to be re-downloaded.
 // Returns the state list or an error if the storage query fails.
-func (p *privateDataService) DownloadUserPrivateDataStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
-	return p.privateDataRepository.GetAllStates(ctx, userID)
+func (p *privateDataService) DownloadUserPrivateDataStates(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
+	return p.privateDataRepository.GetAllStates(ctx, request)
 }
 
 // DownloadSpecificUserPrivateDataStates returns state descriptors only for
@@ -92,3 +103,24 @@ func (p *privateDataService) UpdatePrivateData(ctx context.Context, updateReques
 func (p *privateDataService) DeletePrivateData(ctx context.Context, deleteRequests models.DeleteRequest) error {
 	return p.privateDataRepository.Delete(ctx, deleteRequests)
 }
+
+// RestorePrivateData restores the soft-deleted vault items listed in
+// restoreRequests in the storage layer.
+// Returns an error if the storage operation fails.
+func (p *privateDataService) RestorePrivateData(ctx context.Context, restoreRequests models.RestoreRequest) error {
+	return p.privateDataRepository.Restore(ctx, restoreRequests)
+}
+
+// GetVersionHistory returns the archived previous versions of a vault item
+// from the storage layer, newest first.
+// Returns the versions or an error if the storage query fails.
+func (p *privateDataService) GetVersionHistory(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error) {
+	return p.privateDataRepository.GetVersionHistory(ctx, userID, clientSideID)
+}
+
+// GetAllClientSideIDs returns the client-side IDs of every vault item of
+// userID, tombstones included, from the storage layer.
+// Returns the IDs or an error if the storage query fails.
+func (p *privateDataService) GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error) {
+	return p.privateDataRepository.GetAllClientSideIDs(ctx, userID)
+}
@@ -22,10 +22,12 @@ type mockPrivateDataStorage struct {
 	saveFn         func(ctx context.Context, data ...*models.PrivateData) error
 	getFn          func(ctx context.Context, req models.DownloadRequest) ([]models.PrivateData, error)
 	getAllFn       func(ctx context.Context, userID int64) ([]models.PrivateData, error)
-	getAllStatesFn func(ctx context.Context, userID int64) ([]models.PrivateDataState, error)
+	getAllStatesFn func(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error)
 	getStatesFn    func(ctx context.Context, req models.SyncRequest) ([]models.PrivateDataState, error)
 	updateFn       func(ctx context.Context, req models.UpdateRequest) error
 	deleteFn       func(ctx context.Context, req models.DeleteRequest) error
+	restoreFn      func(ctx context.Context, req models.RestoreRequest) error
+	historyFn      func(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error)
 }
 
 func (m *mockPrivateDataStorage) Save(ctx context.Context, data ...*models.PrivateData) error {
@@ -49,9 +51,9 @@ func (m *mockPrivateDataStorage) GetAll(ctx context.Context, userID int64) ([]mo
 	return nil, nil
 }
 
-func (m *mockPrivateDataStorage) GetAllStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
+func (m *mockPrivateDataStorage) GetAllStates(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
 	if m.getAllStatesFn != nil {
-		return m.getAllStatesFn(ctx, userID)
+		return m.getAllStatesFn(ctx, request)
 	}
 	return nil, nil
 }
@@ -77,6 +79,24 @@ func (m *mockPrivateDataStorage) Delete(ctx context.Context, req models.DeleteRe
 	return nil
 }
 
+func (m *mockPrivateDataStorage) Restore(ctx context.Context, req models.RestoreRequest) error {
+	if m.restoreFn != nil {
+		return m.restoreFn(ctx, req)
+	}
+	return nil
+}
+
+func (m *mockPrivateDataStorage) GetVersionHistory(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error) {
+	if m.historyFn != nil {
+		return m.historyFn(ctx, userID, clientSideID)
+	}
+	return nil, nil
+}
+
+func (m *mockPrivateDataStorage) GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error) {
+	return nil, nil
+}
+
 // ─────────────────────────────────────────────
 // Helper
 // ─────────────────────────────────────────────
@@ -128,6 +148,32 @@ func TestPrivateDataService_UploadPrivateData_StorageError(t *testing.T) {
 	require.ErrorIs(t, err, errStorage)
 }
 
+func TestPrivateDataService_UploadPrivateDataEach(t *testing.T) {
+	items := []*models.PrivateData{{ClientSideID: "a"}, {ClientSideID: "b"}, {ClientSideID: "c"}}
+	var saved []string
+	storage := &mockPrivateDataStorage{
+		saveFn: func(_ context.Context, data ...*models.PrivateData) error {
+			// Every item is saved on its own.
+			require.Len(t, data, 1)
+			saved = append(saved, data[0].ClientSideID)
+			if data[0].ClientSideID == "b" {
+				return errStorage
+			}
+			return nil
+		},
+	}
+	svc := newRawPrivateDataService(storage)
+
+	errs, err := svc.UploadPrivateDataEach(context.Background(), models.UploadRequest{PrivateDataList: items})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, saved)
+	require.Len(t, errs, 3)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], errStorage)
+	assert.NoError(t, errs[2])
+}
+
 func TestPrivateDataService_UploadPrivateData_EmptyList_DelegatesToStorage(t *testing.T) {
 	called := false
 	storage := &mockPrivateDataStorage{
@@ -222,14 +268,14 @@ func TestPrivateDataService_DownloadAllPrivateData_StorageError(t *testing.T) {
 func TestPrivateDataService_DownloadUserPrivateDataStates_Success(t *testing.T) {
 	expected := []models.PrivateDataState{{ClientSideID: "s-1"}}
 	storage := &mockPrivateDataStorage{
-		getAllStatesFn: func(_ context.Context, userID int64) ([]models.PrivateDataState, error) {
-			assert.Equal(t, int64(3), userID)
+		getAllStatesFn: func(_ context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
+			assert.Equal(t, models.StatesPageRequest{UserID: 3, After: 10, Limit: 50}, request)
 			return expected, nil
 		},
 	}
 	svc := newRawPrivateDataService(storage)
 
-	result, err := svc.DownloadUserPrivateDataStates(context.Background(), 3)
+	result, err := svc.DownloadUserPrivateDataStates(context.Background(), models.StatesPageRequest{UserID: 3, After: 10, Limit: 50})
 
 	require.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -237,13 +283,13 @@ func TestPrivateDataService_DownloadUserPrivateDataStates_Success(t *testing.T)
 
 func TestPrivateDataService_DownloadUserPrivateDataStates_StorageError(t *testing.T) {
 	storage := &mockPrivateDataStorage{
-		getAllStatesFn: func(_ context.Context, _ int64) ([]models.PrivateDataState, error) {
+		getAllStatesFn: func(_ context.Context, _ models.StatesPageRequest) ([]models.PrivateDataState, error) {
 			return nil, errStorage
 		},
 	}
 	svc := newRawPrivateDataService(storage)
 
-	result, err := svc.DownloadUserPrivateDataStates(context.Background(), 1)
+	result, err := svc.DownloadUserPrivateDataStates(context.Background(), models.StatesPageRequest{UserID: 1})
 
 	assert.Nil(t, result)
 	require.ErrorIs(t, err, errStorage)
@@ -360,3 +406,41 @@ func TestPrivateDataService_DeletePrivateData_StorageError(t *testing.T) {
 
 	require.ErrorIs(t, err, errStorage)
 }
+
+// ─────────────────────────────────────────────
+// RestorePrivateData
+// ─────────────────────────────────────────────
+
+func TestPrivateDataService_RestorePrivateData_Success(t *testing.T) {
+	req := models.RestoreRequest{
+		UserID: 9,
+		RestoreEntries: []models.RestoreEntry{
+			{ClientSideID: "del-1", Version: 2},
+		},
+		Length: 1,
+	}
+	storage := &mockPrivateDataStorage{
+		restoreFn: func(_ context.Context, r models.RestoreRequest) error {
+			assert.Equal(t, req, r)
+			return nil
+		},
+	}
+	svc := newRawPrivateDataService(storage)
+
+	err := svc.RestorePrivateData(context.Background(), req)
+
+	require.NoError(t, err)
+}
+
+func TestPrivateDataService_RestorePrivateData_StorageError(t *testing.T) {
+	storage := &mockPrivateDataStorage{
+		restoreFn: func(_ context.Context, _ models.RestoreRequest) error {
+			return errStorage
+		},
+	}
+	svc := newRawPrivateDataService(storage)
+
+	err := svc.RestorePrivateData(context.Background(), models.RestoreRequest{UserID: 1})
+
+	require.ErrorIs(t, err, errStorage)
+}
@@ -37,10 +37,13 @@ type privateDataValidationService struct {
 // decorates any PrivateDataService with validation and authorization checks.
 //
 // The returned wrapper uses validators.NewPrivateDataValidator() internally
-// and is typically applied in NewPrivateDataService.
-func NewPrivateDataValidationService() PrivateDataServiceWrapper {
+// and is typically applied in NewPrivateDataService. maxNotesLength is the
+// plaintext notes limit, in characters, passed to the validator; zero disables
+// the notes check. maxBatchEntries caps the entries of one update or delete
+// request; zero disables the cap.
+func NewPrivateDataValidationService(maxNotesLength, maxBatchEntries int) PrivateDataServiceWrapper {
 	return &privateDataValidationService{
-		validator: validators.NewPrivateDataValidator(),
+		validator: validators.NewPrivateDataValidator(maxNotesLength, maxBatchEntries),
 	}
 }
 
@@ -50,7 +53,9 @@ func NewPrivateDataValidationService() PrivateDataServiceWrapper {
 //   - ensures at least one private data item is provided;
 //   - ensures a user ID is present in the context;
 //   - ensures every item belongs to the authenticated user;
-//   - validates each item using the configured validator.
+//   - validates each item using the configured validator, including that a
+//     new item starts at version 0;
+//   - ensures Length equals the number of items.
 //
 // Returns an error if any validation step fails, otherwise forwards the call
 // to inner.UploadPrivateData.
@@ -64,19 +69,80 @@ func (v *privateDataValidationService) UploadPrivateData(ctx context.Context, up
 		return ErrValidationNoUserID
 	}
 
-	for _, data := range uploadRequest.PrivateDataList {
+	for i, data := range uploadRequest.PrivateDataList {
 		if data.UserID != userID {
 			return ErrUnauthorizedAccessToDifferentUserData
 		}
 
-		if err := v.validator.Validate(ctx, data); err != nil {
-			return fmt.Errorf("%w: %w", ErrInvalidDataProvided, err)
+		if err := v.validator.Validate(ctx, data, validators.UploadItemFields...); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidDataProvided, validators.AtIndex(err, i))
 		}
 	}
 
+	if err := v.validator.Validate(ctx, uploadRequest, validators.FieldLength); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDataProvided, err)
+	}
+
 	return v.inner.UploadPrivateData(ctx, uploadRequest)
 }
 
+// UploadPrivateDataEach applies the checks of UploadPrivateData, but only the
+// request-level ones reject the whole request: an item owned by another user
+// or failing validation gets its own error and is not passed to the inner
+// service, while the valid items are.
+func (v *privateDataValidationService) UploadPrivateDataEach(ctx context.Context, uploadRequest models.UploadRequest) ([]error, error) {
+	if len(uploadRequest.PrivateDataList) == 0 {
+		return nil, ErrValidationNoPrivateDataProvided
+	}
+
+	userID, found := utils.GetUserIDFromContext(ctx)
+	if !found {
+		return nil, ErrValidationNoUserID
+	}
+
+	if err := v.validator.Validate(ctx, uploadRequest, validators.FieldLength); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDataProvided, err)
+	}
+
+	errs := make([]error, len(uploadRequest.PrivateDataList))
+	valid := make([]*models.PrivateData, 0, len(uploadRequest.PrivateDataList))
+	validIdx := make([]int, 0, len(uploadRequest.PrivateDataList))
+	for i, data := range uploadRequest.PrivateDataList {
+		switch {
+		case data == nil:
+			errs[i] = ErrInvalidDataProvided
+		case data.UserID != userID:
+			errs[i] = ErrUnauthorizedAccessToDifferentUserData
+		default:
+			if err := v.validator.Validate(ctx, data, validators.UploadItemFields...); err != nil {
+				errs[i] = fmt.Errorf("%w: %w", ErrInvalidDataProvided, validators.AtIndex(err, i))
+				continue
+			}
+			valid = append(valid, data)
+			validIdx = append(validIdx, i)
+		}
+	}
+
+	if len(valid) == 0 {
+		return errs, nil
+	}
+
+	innerRequest := uploadRequest
+	innerRequest.PrivateDataList = valid
+	innerRequest.Length = len(valid)
+	innerErrs, err := v.inner.UploadPrivateDataEach(ctx, innerRequest)
+	if err != nil {
+		return nil, err
+	}
+	for i, idx := range validIdx {
+		if i < len(innerErrs) {
+			errs[idx] = innerErrs[i]
+		}
+	}
+
+	return errs, nil
+}
+
 // DownloadPrivateData validates the downloadRequests before delegating to the
 // inner service:
 //
@@ -124,11 +190,13 @@ func (v *privateDataValidationService) DownloadAllPrivateData(ctx context.Contex
 }
 
 // DownloadUserPrivateDataStates validates that the requested userID matches
-// the authenticated user before delegating to the inner service.
+// the authenticated user and that the page cursor and limit are not negative
+// before delegating to the inner service.
 //
-// Returns state descriptors for all private data items of the user or an error
-// if validation fails.
-func (v *privateDataValidationService) DownloadUserPrivateDataStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
+// Returns one page of state descriptors for the private data items of the user
+// or an error if validation fails.
+func (v *privateDataValidationService) DownloadUserPrivateDataStates(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
+	userID := request.UserID
 	if userID == 0 {
 		return nil, ErrValidationNoUserID
 	}
@@ -142,7 +210,11 @@ func (v *privateDataValidationService) DownloadUserPrivateDataStates(ctx context
 		return nil, ErrUnauthorizedAccessToDifferentUserData
 	}
 
-	return v.inner.DownloadUserPrivateDataStates(ctx, userID)
+	if request.After < 0 || request.Limit < 0 {
+		return nil, ErrValidationInvalidPage
+	}
+
+	return v.inner.DownloadUserPrivateDataStates(ctx, request)
 }
 
 // DownloadSpecificUserPrivateDataStates validates the syncRequest before
@@ -151,7 +223,8 @@ func (v *privateDataValidationService) DownloadUserPrivateDataStates(ctx context
 //   - ensures a user ID is present in the context;
 //   - ensures that at least one client-side ID is provided;
 //   - ensures the request's UserID matches the authenticated user;
-//   - ensures no empty client-side IDs are present.
+//   - ensures no empty client-side IDs are present;
+//   - ensures Length equals the number of client-side IDs.
 //
 // Returns a slice of PrivateDataState or an error if validation fails.
 func (v *privateDataValidationService) DownloadSpecificUserPrivateDataStates(ctx context.Context, syncRequest models.SyncRequest) ([]models.PrivateDataState, error) {
@@ -174,6 +247,10 @@ func (v *privateDataValidationService) DownloadSpecificUserPrivateDataStates(ctx
 		}
 	}
 
+	if err := v.validator.Validate(ctx, syncRequest, validators.FieldLength); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDataProvided, err)
+	}
+
 	return v.inner.DownloadSpecificUserPrivateDataStates(ctx, syncRequest)
 }
 
@@ -183,6 +260,8 @@ func (v *privateDataValidationService) DownloadSpecificUserPrivateDataStates(ctx
 //   - ensures a user ID is present in the context;
 //   - ensures the request's UserID matches the authenticated user;
 //   - ensures at least one update entry is provided;
+//   - ensures Length equals the number of update entries and that there
+//     are no more of them than the per-request cap;
 //   - validates each update entry using the validator.
 //
 // Returns an error if validation fails.
@@ -200,9 +279,13 @@ func (v *privateDataValidationService) UpdatePrivateData(ctx context.Context, up
 		return ErrValidationNoUpdateRequestsProvided
 	}
 
-	for _, dataUpdate := range updateRequests.PrivateDataUpdates {
+	if err := v.validator.Validate(ctx, updateRequests, validators.FieldLength); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDataProvided, err)
+	}
+
+	for i, dataUpdate := range updateRequests.PrivateDataUpdates {
 		if err := v.validator.Validate(ctx, dataUpdate); err != nil {
-			return fmt.Errorf("%w: %w", ErrInvalidDataProvided, err)
+			return fmt.Errorf("%w: %w", ErrInvalidDataProvided, validators.AtIndex(err, i))
 		}
 	}
 
@@ -214,7 +297,8 @@ func (v *privateDataValidationService) UpdatePrivateData(ctx context.Context, up
 //
 //   - ensures a user ID is present in the context;
 //   - ensures the request's UserID matches the authenticated user;
-//   - validates the request using the validator.
+//   - validates the request using the validator, including the
+//     per-request entry cap.
 //
 // Returns an error if validation fails.
 func (v *privateDataValidationService) DeletePrivateData(ctx context.Context, deleteRequests models.DeleteRequest) error {
@@ -234,13 +318,70 @@ func (v *privateDataValidationService) DeletePrivateData(ctx context.Context, de
 	return v.inner.DeletePrivateData(ctx, deleteRequests)
 }
 
+// RestorePrivateData validates the restoreRequests before delegating to the
+// inner service, with the same checks as [DeletePrivateData].
+//
+// Returns an error if validation fails.
+func (v *privateDataValidationService) RestorePrivateData(ctx context.Context, restoreRequests models.RestoreRequest) error {
+	userID, found := utils.GetUserIDFromContext(ctx)
+	if !found {
+		return ErrValidationNoUserID
+	}
+
+	if restoreRequests.UserID != userID {
+		return ErrUnauthorizedAccessToDifferentUserData
+	}
+
+	if err := v.validator.Validate(ctx, restoreRequests); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDataProvided, err)
+	}
+
+	return v.inner.RestorePrivateData(ctx, restoreRequests)
+}
+
+// GetVersionHistory validates that userID matches the authenticated user and
+// that clientSideID is not empty before delegating to the inner service.
+//
+// Returns the archived versions of the item or an error if validation fails.
+func (v *privateDataValidationService) GetVersionHistory(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error) {
+	userIDFromAuthToken, found := utils.GetUserIDFromContext(ctx)
+	if !found || userID == 0 {
+		return nil, ErrValidationNoUserID
+	}
+
+	if userIDFromAuthToken != userID {
+		return nil, ErrUnauthorizedAccessToDifferentUserData
+	}
+
+	if clientSideID == "" {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDataProvided, ErrValidationEmptyClientIDProvidedForSyncRequests)
+	}
+
+	return v.inner.GetVersionHistory(ctx, userID, clientSideID)
+}
+
+// GetAllClientSideIDs validates that userID matches the authenticated user
+// before delegating to the inner service.
+func (v *privateDataValidationService) GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error) {
+	userIDFromAuthToken, found := utils.GetUserIDFromContext(ctx)
+	if !found || userID == 0 {
+		return nil, ErrValidationNoUserID
+	}
+
+	if userIDFromAuthToken != userID {
+		return nil, ErrUnauthorizedAccessToDifferentUserData
+	}
+
+	return v.inner.GetAllClientSideIDs(ctx, userID)
+}
+
 // Wrap sets the inner PrivateDataService that this validation middleware will
 // delegate to and returns the decorated service.
 //
 // It is typically used in a composition chain:
 //
 //	var baseSvc PrivateDataService = newCoreService(...)
-//	var wrapper = NewPrivateDataValidationService()
+//	var wrapper = NewPrivateDataValidationService(cfg.NotesLengthLimit(), cfg.BatchEntriesLimit())
 //	svcWithValidation := wrapper.Wrap(baseSvc)
 func (v *privateDataValidationService) Wrap(wrapper PrivateDataService) PrivateDataService {
 	v.inner = wrapper
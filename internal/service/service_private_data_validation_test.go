@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/MKhiriev/go-pass-keeper/internal/validators"
 	"github.com/MKhiriev/go-pass-keeper/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,12 +21,16 @@ import (
 
 type mockInnerService struct {
 	uploadFn           func(ctx context.Context, req models.UploadRequest) error
+	uploadEachFn       func(ctx context.Context, req models.UploadRequest) ([]error, error)
 	downloadFn         func(ctx context.Context, req models.DownloadRequest) ([]models.PrivateData, error)
 	downloadAllFn      func(ctx context.Context, userID int64) ([]models.PrivateData, error)
-	downloadStatesFn   func(ctx context.Context, userID int64) ([]models.PrivateDataState, error)
+	downloadStatesFn   func(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error)
 	downloadSpecificFn func(ctx context.Context, req models.SyncRequest) ([]models.PrivateDataState, error)
 	updateFn           func(ctx context.Context, req models.UpdateRequest) error
 	deleteFn           func(ctx context.Context, req models.DeleteRequest) error
+	restoreFn          func(ctx context.Context, req models.RestoreRequest) error
+	historyFn          func(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error)
+	clientSideIDsFn    func(ctx context.Context, userID int64) ([]models.ClientSideIDState, error)
 }
 
 func (m *mockInnerService) UploadPrivateData(ctx context.Context, req models.UploadRequest) error {
@@ -34,6 +39,12 @@ func (m *mockInnerService) UploadPrivateData(ctx context.Context, req models.Upl
 	}
 	return nil
 }
+func (m *mockInnerService) UploadPrivateDataEach(ctx context.Context, req models.UploadRequest) ([]error, error) {
+	if m.uploadEachFn != nil {
+		return m.uploadEachFn(ctx, req)
+	}
+	return make([]error, len(req.PrivateDataList)), nil
+}
 func (m *mockInnerService) DownloadPrivateData(ctx context.Context, req models.DownloadRequest) ([]models.PrivateData, error) {
 	if m.downloadFn != nil {
 		return m.downloadFn(ctx, req)
@@ -46,9 +57,9 @@ func (m *mockInnerService) DownloadAllPrivateData(ctx context.Context, userID in
 	}
 	return nil, nil
 }
-func (m *mockInnerService) DownloadUserPrivateDataStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
+func (m *mockInnerService) DownloadUserPrivateDataStates(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
 	if m.downloadStatesFn != nil {
-		return m.downloadStatesFn(ctx, userID)
+		return m.downloadStatesFn(ctx, request)
 	}
 	return nil, nil
 }
@@ -70,6 +81,24 @@ func (m *mockInnerService) DeletePrivateData(ctx context.Context, req models.Del
 	}
 	return nil
 }
+func (m *mockInnerService) RestorePrivateData(ctx context.Context, req models.RestoreRequest) error {
+	if m.restoreFn != nil {
+		return m.restoreFn(ctx, req)
+	}
+	return nil
+}
+func (m *mockInnerService) GetVersionHistory(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error) {
+	if m.historyFn != nil {
+		return m.historyFn(ctx, userID, clientSideID)
+	}
+	return nil, nil
+}
+func (m *mockInnerService) GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error) {
+	if m.clientSideIDsFn != nil {
+		return m.clientSideIDsFn(ctx, userID)
+	}
+	return nil, nil
+}
 
 type mockValidator struct {
 	validateFn func(ctx context.Context, i any, fields ...string) error
@@ -135,7 +164,7 @@ func TestValidation_UploadPrivateData_ValidatorError(t *testing.T) {
 	}
 	err := svc.UploadPrivateData(ctxWithUserID(1), req)
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid data provided: validation failed")
+	assert.Contains(t, err.Error(), "invalid data provided: validation error at index 0: validation failed")
 	assert.True(t, errors.Is(err, errValidation))
 }
 
@@ -156,6 +185,74 @@ func TestValidation_UploadPrivateData_Success(t *testing.T) {
 	assert.True(t, called)
 }
 
+func TestValidation_UploadPrivateDataEach(t *testing.T) {
+	ok := &models.PrivateData{ClientSideID: "ok", UserID: 1}
+	taken := &models.PrivateData{ClientSideID: "taken", UserID: 1}
+	invalid := &models.PrivateData{ClientSideID: "invalid", UserID: 1}
+	foreign := &models.PrivateData{ClientSideID: "foreign", UserID: 2}
+
+	v := &mockValidator{
+		validateFn: func(_ context.Context, i any, _ ...string) error {
+			if d, isItem := i.(*models.PrivateData); isItem && d == invalid {
+				return errValidation
+			}
+			return nil
+		},
+	}
+	inner := &mockInnerService{
+		uploadEachFn: func(_ context.Context, req models.UploadRequest) ([]error, error) {
+			// Only the items that passed validation reach the inner service.
+			assert.Equal(t, []*models.PrivateData{ok, taken}, req.PrivateDataList)
+			assert.Equal(t, 2, req.Length)
+			return []error{nil, errStorageConflict}, nil
+		},
+	}
+	svc := newValidationService(inner, v)
+
+	errs, err := svc.UploadPrivateDataEach(ctxWithUserID(1), models.UploadRequest{
+		UserID:          1,
+		PrivateDataList: []*models.PrivateData{ok, invalid, taken, foreign},
+		Length:          4,
+		BestEffort:      true,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, errs, 4)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], ErrInvalidDataProvided)
+	assert.ErrorIs(t, errs[1], errValidation)
+	assert.ErrorIs(t, errs[2], errStorageConflict)
+	assert.ErrorIs(t, errs[3], ErrUnauthorizedAccessToDifferentUserData)
+}
+
+func TestValidation_UploadPrivateDataEach_RequestErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		req     models.UploadRequest
+		wantErr error
+	}{
+		{name: "no data", ctx: ctxWithUserID(1), wantErr: ErrValidationNoPrivateDataProvided},
+		{
+			name:    "no user in context",
+			ctx:     context.Background(),
+			req:     models.UploadRequest{PrivateDataList: []*models.PrivateData{{UserID: 1}}},
+			wantErr: ErrValidationNoUserID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newValidationService(&mockInnerService{}, &mockValidator{})
+			errs, err := svc.UploadPrivateDataEach(tt.ctx, tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, errs)
+		})
+	}
+}
+
+var errStorageConflict = errors.New("client_side_id is taken")
+
 // ─────────────────────────────────────────────
 // DownloadPrivateData
 // ─────────────────────────────────────────────
@@ -194,6 +291,34 @@ func TestValidation_DownloadAllPrivateData_Unauthorized(t *testing.T) {
 	assert.ErrorIs(t, err, ErrUnauthorizedAccessToDifferentUserData)
 }
 
+// ─────────────────────────────────────────────
+// DownloadUserPrivateDataStates
+// ─────────────────────────────────────────────
+
+func TestValidation_DownloadUserStates_InvalidPage(t *testing.T) {
+	svc := newValidationService(nil, nil)
+	for _, req := range []models.StatesPageRequest{
+		{UserID: 1, After: -1},
+		{UserID: 1, Limit: -5},
+	} {
+		_, err := svc.DownloadUserPrivateDataStates(ctxWithUserID(1), req)
+		assert.ErrorIs(t, err, ErrValidationInvalidPage)
+	}
+}
+
+func TestValidation_DownloadUserStates_Success(t *testing.T) {
+	want := models.StatesPageRequest{UserID: 1, After: 4, Limit: 10}
+	inner := &mockInnerService{
+		downloadStatesFn: func(_ context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
+			assert.Equal(t, want, request)
+			return nil, nil
+		},
+	}
+	svc := newValidationService(inner, &mockValidator{})
+	_, err := svc.DownloadUserPrivateDataStates(ctxWithUserID(1), want)
+	assert.NoError(t, err)
+}
+
 // ─────────────────────────────────────────────
 // DownloadSpecificUserPrivateDataStates
 // ─────────────────────────────────────────────
@@ -259,3 +384,191 @@ func TestValidation_DeletePrivateData_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, called)
 }
+
+// ─────────────────────────────────────────────
+// RestorePrivateData
+// ─────────────────────────────────────────────
+
+func TestValidation_RestorePrivateData(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		req        models.RestoreRequest
+		validErr   error
+		wantErr    error
+		wantCalled bool
+	}{
+		{name: "no user in context", ctx: context.Background(), req: models.RestoreRequest{UserID: 1}, wantErr: ErrValidationNoUserID},
+		{name: "different user", ctx: ctxWithUserID(2), req: models.RestoreRequest{UserID: 1}, wantErr: ErrUnauthorizedAccessToDifferentUserData},
+		{name: "validator error", ctx: ctxWithUserID(1), req: models.RestoreRequest{UserID: 1}, validErr: errValidation, wantErr: ErrInvalidDataProvided},
+		{name: "success", ctx: ctxWithUserID(1), req: models.RestoreRequest{UserID: 1}, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			inner := &mockInnerService{
+				restoreFn: func(_ context.Context, _ models.RestoreRequest) error {
+					called = true
+					return nil
+				},
+			}
+			v := &mockValidator{
+				validateFn: func(_ context.Context, _ any, _ ...string) error { return tt.validErr },
+			}
+			svc := newValidationService(inner, v)
+
+			err := svc.RestorePrivateData(tt.ctx, tt.req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalled, called)
+		})
+	}
+}
+
+// ─────────────────────────────────────────────
+// Length
+// ─────────────────────────────────────────────
+
+func TestValidation_LengthMismatch(t *testing.T) {
+	item := &models.PrivateData{
+		ClientSideID: "cid-1",
+		UserID:       1,
+		Payload: models.PrivateDataPayload{
+			Metadata: "meta",
+			Type:     models.LoginPassword,
+			Data:     "data",
+		},
+		Hash: "hash",
+	}
+	meta := models.CipheredMetadata("meta")
+	update := models.PrivateDataUpdate{
+		ClientSideID:      "cid-1",
+		Version:           1,
+		UpdatedRecordHash: "hash",
+		FieldsUpdate:      models.FieldsUpdate{Metadata: &meta},
+	}
+
+	tests := []struct {
+		name   string
+		length int
+		call   func(svc *privateDataValidationService, length int) error
+	}{
+		{name: "upload", length: 1, call: func(svc *privateDataValidationService, length int) error {
+			return svc.UploadPrivateData(ctxWithUserID(1), models.UploadRequest{UserID: 1, PrivateDataList: []*models.PrivateData{item}, Length: length})
+		}},
+		{name: "update", length: 1, call: func(svc *privateDataValidationService, length int) error {
+			return svc.UpdatePrivateData(ctxWithUserID(1), models.UpdateRequest{UserID: 1, PrivateDataUpdates: []models.PrivateDataUpdate{update}, Length: length})
+		}},
+		{name: "delete", length: 1, call: func(svc *privateDataValidationService, length int) error {
+			return svc.DeletePrivateData(ctxWithUserID(1), models.DeleteRequest{UserID: 1, DeleteEntries: []models.DeleteEntry{{ClientSideID: "cid-1", Version: 1}}, Length: length})
+		}},
+		{name: "download", length: 2, call: func(svc *privateDataValidationService, length int) error {
+			_, err := svc.DownloadPrivateData(ctxWithUserID(1), models.DownloadRequest{UserID: 1, ClientSideIDs: []string{"a", "b"}, Length: length})
+			return err
+		}},
+		{name: "download all", length: 0, call: func(svc *privateDataValidationService, length int) error {
+			_, err := svc.DownloadPrivateData(ctxWithUserID(1), models.DownloadRequest{UserID: 1, Length: length})
+			return err
+		}},
+		{name: "sync", length: 1, call: func(svc *privateDataValidationService, length int) error {
+			_, err := svc.DownloadSpecificUserPrivateDataStates(ctxWithUserID(1), models.SyncRequest{UserID: 1, ClientSideIDs: []string{"a"}, Length: length})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewPrivateDataValidationService(0, 0).Wrap(&mockInnerService{}).(*privateDataValidationService)
+
+			require.NoError(t, tt.call(svc, tt.length), "matching length")
+
+			err := tt.call(svc, tt.length+1)
+			require.ErrorIs(t, err, ErrInvalidDataProvided)
+			assert.ErrorIs(t, err, validators.ErrLengthMismatch)
+
+			err = tt.call(svc, -1)
+			assert.ErrorIs(t, err, validators.ErrLengthMismatch)
+		})
+	}
+}
+
+// ─────────────────────────────────────────────
+// GetVersionHistory
+// ─────────────────────────────────────────────
+
+func TestValidation_GetVersionHistory(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		userID       int64
+		clientSideID string
+		wantErr      error
+	}{
+		{name: "success", ctx: ctxWithUserID(1), userID: 1, clientSideID: "cid-1"},
+		{name: "no user in context", ctx: context.Background(), userID: 1, clientSideID: "cid-1", wantErr: ErrValidationNoUserID},
+		{name: "different user", ctx: ctxWithUserID(2), userID: 1, clientSideID: "cid-1", wantErr: ErrUnauthorizedAccessToDifferentUserData},
+		{name: "empty client side id", ctx: ctxWithUserID(1), userID: 1, wantErr: ErrInvalidDataProvided},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			inner := &mockInnerService{
+				historyFn: func(_ context.Context, _ int64, _ string) ([]models.PrivateDataVersion, error) {
+					called = true
+					return nil, nil
+				},
+			}
+			svc := newValidationService(inner, &mockValidator{})
+
+			_, err := svc.GetVersionHistory(tt.ctx, tt.userID, tt.clientSideID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, called)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, called)
+		})
+	}
+}
+
+func TestValidation_GetAllClientSideIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		userID  int64
+		wantErr error
+	}{
+		{name: "success", ctx: ctxWithUserID(1), userID: 1},
+		{name: "no user in context", ctx: context.Background(), userID: 1, wantErr: ErrValidationNoUserID},
+		{name: "zero user id", ctx: ctxWithUserID(1), wantErr: ErrValidationNoUserID},
+		{name: "different user", ctx: ctxWithUserID(2), userID: 1, wantErr: ErrUnauthorizedAccessToDifferentUserData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			inner := &mockInnerService{
+				clientSideIDsFn: func(_ context.Context, _ int64) ([]models.ClientSideIDState, error) {
+					called = true
+					return nil, nil
+				},
+			}
+			svc := newValidationService(inner, &mockValidator{})
+
+			_, err := svc.GetAllClientSideIDs(tt.ctx, tt.userID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, called)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, called)
+		})
+	}
+}
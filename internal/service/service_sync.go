@@ -30,13 +30,17 @@ func NewSyncService() SyncService {
 //   - Pass 1 (over serverData): handles items present on the server,
 //     whether or not they also exist on the client.
 //   - Pass 2 (over clientData): catches items that exist only on the
-//     client and were therefore invisible in pass 1.
+//     client and were therefore invisible in pass 1. A previously synced
+//     item missing from the server is only taken as deleted there when
+//     serverHardDelete is set; a soft-delete server always keeps a
+//     tombstone, so the item is uploaded again instead.
 //
 // ctx cancellation is checked at the start of each iteration so that
 // callers can abort early when operating on large datasets.
 func (s *syncService) BuildSyncPlan(
 	ctx context.Context,
 	serverData, clientData []models.PrivateDataState,
+	serverHardDelete bool,
 ) (models.SyncPlan, error) {
 	var plan models.SyncPlan
 
@@ -130,12 +134,20 @@ func (s *syncService) BuildSyncPlan(
 			continue
 		}
 
-		if !cd.Deleted {
-			// Live record that has never been pushed to the server → upload.
+		switch {
+		case cd.Deleted:
+			// Record was deleted locally and the server has no row for it:
+			// either it never reached the server or the server already
+			// removed it — no action.
+		case cd.ID != 0 && serverHardDelete:
+			// Live record the server used to have: it was removed there
+			// without a tombstone (hard-delete mode) → remove from client.
+			plan.DeleteClient = append(plan.DeleteClient, cd)
+		default:
+			// Live record that has never been pushed to the server, or one a
+			// soft-delete server lost without leaving a tombstone → upload.
 			plan.Upload = append(plan.Upload, cd)
 		}
-		// cd.Deleted && !existsOnServer: record was created and soft-deleted
-		// locally before the first sync — server never knew about it, no action.
 	}
 
 	return plan, nil
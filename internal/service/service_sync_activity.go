@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/store"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+const (
+	// DefaultSyncLagPageSize is the number of users returned by GetSyncLag
+	// when the caller does not specify a limit.
+	DefaultSyncLagPageSize = 50
+
+	// MaxSyncLagPageSize caps the number of users returned per page.
+	MaxSyncLagPageSize = 500
+
+	// SyncActivityResolution is how often the last-seen time of one user is
+	// written at most. A client syncs with several requests in a row; one
+	// write per user and interval keeps that off the database.
+	SyncActivityResolution = time.Minute
+)
+
+// syncActivityService is the concrete implementation of [SyncActivityService].
+type syncActivityService struct {
+	repo   store.SyncActivityRepository
+	logger *logger.Logger
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	lastSeen map[int64]time.Time
+}
+
+// NewSyncActivityService constructs a [SyncActivityService] backed by repo
+// that takes the activity times and the sync lag from clk.
+func NewSyncActivityService(repo store.SyncActivityRepository, clk clock.Clock, logger *logger.Logger) SyncActivityService {
+	return &syncActivityService{
+		repo:     repo,
+		logger:   logger,
+		clock:    clk,
+		lastSeen: make(map[int64]time.Time),
+	}
+}
+
+// RecordActivity implements [SyncActivityService]. The write is skipped when
+// this process already recorded userID less than [SyncActivityResolution]
+// ago, so the stored time may lag the real one by up to that interval.
+func (s *syncActivityService) RecordActivity(ctx context.Context, userID int64) error {
+	now := s.clock.Now().UTC()
+
+	s.mu.Lock()
+	if last, ok := s.lastSeen[userID]; ok && now.Sub(last) < SyncActivityResolution {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lastSeen[userID] = now
+	s.mu.Unlock()
+
+	if err := s.repo.TouchLastSeen(ctx, userID, now); err != nil {
+		s.mu.Lock()
+		delete(s.lastSeen, userID)
+		s.mu.Unlock()
+		return fmt.Errorf("record sync activity: %w", err)
+	}
+	return nil
+}
+
+// EvictIdle implements [IdleEvictor]. It forgets users last recorded before
+// cutoff; their next activity is written to the repository again.
+func (s *syncActivityService) EvictIdle(cutoff time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	evicted := 0
+	for userID, last := range s.lastSeen {
+		if last.Before(cutoff) {
+			delete(s.lastSeen, userID)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// GetSyncLag implements [SyncActivityService]. It rejects a negative user ID
+// or offset with [ErrInvalidDataProvided] and normalises the limit before
+// querying the repository.
+func (s *syncActivityService) GetSyncLag(ctx context.Context, request models.SyncLagRequest) ([]models.SyncLag, error) {
+	if request.UserID < 0 {
+		return nil, fmt.Errorf("%w: negative user_id", ErrInvalidDataProvided)
+	}
+	if request.Offset < 0 {
+		return nil, fmt.Errorf("%w: negative offset", ErrInvalidDataProvided)
+	}
+
+	switch {
+	case request.Limit <= 0:
+		request.Limit = DefaultSyncLagPageSize
+	case request.Limit > MaxSyncLagPageSize:
+		request.Limit = MaxSyncLagPageSize
+	}
+
+	lags, err := s.repo.GetLastSeen(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("get sync lag: %w", err)
+	}
+
+	now := s.clock.Now()
+	for i := range lags {
+		lags[i].LagSeconds = max(int64(now.Sub(lags[i].LastSeenAt)/time.Second), 0)
+	}
+	return lags, nil
+}
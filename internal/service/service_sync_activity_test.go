@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSyncActivityRepository struct {
+	touched  []time.Time
+	touchErr error
+	got      models.SyncLagRequest
+	calls    int
+	lags     []models.SyncLag
+	err      error
+}
+
+func (m *mockSyncActivityRepository) TouchLastSeen(_ context.Context, _ int64, at time.Time) error {
+	m.touched = append(m.touched, at)
+	return m.touchErr
+}
+
+func (m *mockSyncActivityRepository) GetLastSeen(_ context.Context, request models.SyncLagRequest) ([]models.SyncLag, error) {
+	m.calls++
+	m.got = request
+	return append([]models.SyncLag(nil), m.lags...), m.err
+}
+
+func newTestSyncActivityService(repo *mockSyncActivityRepository, clk clock.Clock) *syncActivityService {
+	return NewSyncActivityService(repo, clk, logger.Nop()).(*syncActivityService)
+}
+
+func TestSyncActivityService_RecordActivity(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	repo := &mockSyncActivityRepository{}
+	svc := newTestSyncActivityService(repo, fake)
+
+	require.NoError(t, svc.RecordActivity(ctx, 7))
+	fake.Advance(SyncActivityResolution / 2)
+	require.NoError(t, svc.RecordActivity(ctx, 7))
+	now := fake.Advance(SyncActivityResolution)
+	require.NoError(t, svc.RecordActivity(ctx, 7))
+
+	require.Len(t, repo.touched, 2, "a repeat within the resolution is not written")
+	assert.Equal(t, now, repo.touched[1])
+
+	// A failed write does not hold back the next one.
+	repo.touchErr = errors.New("db down")
+	require.ErrorIs(t, svc.RecordActivity(ctx, 8), repo.touchErr)
+	repo.touchErr = nil
+	require.NoError(t, svc.RecordActivity(ctx, 8))
+	assert.Len(t, repo.touched, 4)
+}
+
+func TestSyncActivityService_GetSyncLag(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lags := []models.SyncLag{
+		{UserID: 7, LastSeenAt: now.Add(-90 * time.Minute)},
+		{UserID: 3, LastSeenAt: now.Add(time.Second)},
+	}
+	repoErr := errors.New("db down")
+
+	tests := []struct {
+		name      string
+		request   models.SyncLagRequest
+		repoErr   error
+		wantLimit int
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "default limit", request: models.SyncLagRequest{}, wantLimit: DefaultSyncLagPageSize, wantCalls: 1},
+		{name: "explicit limit kept", request: models.SyncLagRequest{UserID: 7, Limit: 10, Offset: 20}, wantLimit: 10, wantCalls: 1},
+		{name: "limit capped", request: models.SyncLagRequest{Limit: MaxSyncLagPageSize + 1}, wantLimit: MaxSyncLagPageSize, wantCalls: 1},
+		{name: "negative user", request: models.SyncLagRequest{UserID: -1}, wantErr: ErrInvalidDataProvided},
+		{name: "negative offset", request: models.SyncLagRequest{Offset: -1}, wantErr: ErrInvalidDataProvided},
+		{name: "repository error", request: models.SyncLagRequest{}, repoErr: repoErr, wantLimit: DefaultSyncLagPageSize, wantCalls: 1, wantErr: repoErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockSyncActivityRepository{lags: lags, err: tt.repoErr}
+			svc := newTestSyncActivityService(repo, clock.NewFake(now))
+
+			got, err := svc.GetSyncLag(context.Background(), tt.request)
+
+			assert.Equal(t, tt.wantCalls, repo.calls)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLimit, repo.got.Limit)
+			require.Len(t, got, 2)
+			assert.Equal(t, int64(90*60), got[0].LagSeconds)
+			assert.Equal(t, int64(0), got[1].LagSeconds, "a clock ahead of the server is not a negative lag")
+		})
+	}
+}
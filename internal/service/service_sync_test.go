@@ -26,6 +26,12 @@ func st(id string, version int64, hash string, deleted bool) models.PrivateDataS
 	}
 }
 
+// synced returns s as a client state that was last synced under serverID.
+func synced(s models.PrivateDataState, serverID int64) models.PrivateDataState {
+	s.ID = serverID
+	return s
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // BuildSyncPlan — decision matrix (table-driven)
 // ─────────────────────────────────────────────────────────────────────────────
@@ -44,6 +50,7 @@ func TestSyncService_BuildSyncPlan_DecisionMatrix(t *testing.T) {
 		name       string
 		serverData []models.PrivateDataState
 		clientData []models.PrivateDataState
+		hardDelete bool
 		wantPlan   models.SyncPlan
 	}{
 		// ── Records present only on the server ───────────────────────────────
@@ -75,6 +82,29 @@ func TestSyncService_BuildSyncPlan_DecisionMatrix(t *testing.T) {
 			clientData: []models.PrivateDataState{st(id, 1, hash, true)},
 			wantPlan:   models.SyncPlan{},
 		},
+		{
+			// The item was synced before but the server has no row for it any
+			// more: it was hard-deleted there.
+			name:       "ClientOnly/Alive/PreviouslySynced/HardDeleteServer → DeleteClient",
+			serverData: nil,
+			clientData: []models.PrivateDataState{synced(st(id, 3, hash, false), 7)},
+			hardDelete: true,
+			wantPlan:   models.SyncPlan{DeleteClient: []models.PrivateDataState{synced(st(id, 3, hash, false), 7)}},
+		},
+		{
+			// A soft-delete server keeps a tombstone for every deletion, so a
+			// missing row was lost, not deleted → push the item back.
+			name:       "ClientOnly/Alive/PreviouslySynced/SoftDeleteServer → Upload",
+			serverData: nil,
+			clientData: []models.PrivateDataState{synced(st(id, 3, hash, false), 7)},
+			wantPlan:   models.SyncPlan{Upload: []models.PrivateDataState{synced(st(id, 3, hash, false), 7)}},
+		},
+		{
+			name:       "ClientOnly/Deleted/PreviouslySynced → NoAction",
+			serverData: nil,
+			clientData: []models.PrivateDataState{synced(st(id, 3, hash, true), 7)},
+			wantPlan:   models.SyncPlan{},
+		},
 
 		// ── Same version ─────────────────────────────────────────────────────
 
@@ -160,7 +190,7 @@ func TestSyncService_BuildSyncPlan_DecisionMatrix(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			plan, err := svc.BuildSyncPlan(context.Background(), tc.serverData, tc.clientData)
+			plan, err := svc.BuildSyncPlan(context.Background(), tc.serverData, tc.clientData, tc.hardDelete)
 
 			require.NoError(t, err)
 			assert.Equal(t, tc.wantPlan.Download, plan.Download, "Download mismatch")
@@ -179,7 +209,7 @@ func TestSyncService_BuildSyncPlan_DecisionMatrix(t *testing.T) {
 func TestSyncService_BuildSyncPlan_BothEmpty(t *testing.T) {
 	svc := NewSyncService()
 
-	plan, err := svc.BuildSyncPlan(context.Background(), nil, nil)
+	plan, err := svc.BuildSyncPlan(context.Background(), nil, nil, false)
 
 	require.NoError(t, err)
 	assert.Nil(t, plan.Download)
@@ -189,6 +219,24 @@ func TestSyncService_BuildSyncPlan_BothEmpty(t *testing.T) {
 	assert.Nil(t, plan.DeleteServer)
 }
 
+// TestSyncService_BuildSyncPlan_SoftDeleteServerNeverDeletesLocally checks
+// that synced items missing from a soft-delete server's states are never
+// planned for local deletion, whatever their version or hash.
+func TestSyncService_BuildSyncPlan_SoftDeleteServerNeverDeletesLocally(t *testing.T) {
+	clientData := []models.PrivateDataState{
+		synced(st("a", 1, "h1", false), 1),
+		synced(st("b", 4, "h2", false), 2),
+		synced(st("c", 9, "h3", false), 3),
+	}
+
+	svc := NewSyncService()
+	plan, err := svc.BuildSyncPlan(context.Background(), nil, clientData, false)
+
+	require.NoError(t, err)
+	assert.Empty(t, plan.DeleteClient)
+	assert.ElementsMatch(t, clientData, plan.Upload)
+}
+
 func TestSyncService_BuildSyncPlan_ContextCancelled(t *testing.T) {
 	// A large enough slice ensures the cancellation check fires before
 	// the loop finishes naturally.
@@ -202,7 +250,7 @@ func TestSyncService_BuildSyncPlan_ContextCancelled(t *testing.T) {
 	cancel() // cancel immediately
 
 	svc := NewSyncService()
-	_, err := svc.BuildSyncPlan(ctx, serverData, nil)
+	_, err := svc.BuildSyncPlan(ctx, serverData, nil, false)
 
 	require.Error(t, err)
 	assert.ErrorIs(t, err, context.Canceled)
@@ -250,7 +298,7 @@ func TestSyncService_BuildSyncPlan_MixedScenario(t *testing.T) {
 	}
 
 	svc := NewSyncService()
-	plan, err := svc.BuildSyncPlan(context.Background(), serverData, clientData)
+	plan, err := svc.BuildSyncPlan(context.Background(), serverData, clientData, false)
 
 	require.NoError(t, err)
 
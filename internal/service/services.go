@@ -8,6 +8,7 @@ package service
 import (
 	"fmt"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/store"
@@ -28,6 +29,17 @@ type Services struct {
 	// authenticated users, including upload, download, sync, update, and delete
 	// operations. The service is pre-wrapped with validation middleware.
 	PrivateDataService PrivateDataService
+
+	// AuditService exposes the audit trail of vault mutations to admins.
+	AuditService AuditService
+
+	// SyncActivityService records when each user last synced and reports
+	// the sync lag to admins.
+	SyncActivityService SyncActivityService
+
+	// Clock is the time source shared by the services, handed on to the
+	// layers built on top of them.
+	Clock clock.Clock
 }
 
 // NewServices constructs and wires all application services from the provided
@@ -36,26 +48,46 @@ type Services struct {
 // Initialization order:
 //  1. AppInfoService — validated first; returns an error immediately if
 //     cfg.Version is empty (fail-fast at startup).
-//  2. HMAC hasher pool — initialised with cfg.HashKey so that AuthService can
+//  2. AuthService — returns an error if the JWT signing key is too weak.
+//  3. HMAC hasher pool — initialised with cfg.HashKey so that AuthService can
 //     hash passwords without allocating a new hasher on every request.
-//  3. AuthService and PrivateDataService — constructed after the hasher pool
-//     is ready.
+//  4. PrivateDataService — constructed after the hasher pool is ready.
+//
+// Every service that reads the current time takes it from clk.
 //
 // Returns a fully initialised *Services or an error if any service fails to
 // initialise.
-func NewServices(storages *store.Storages, cfg config.App, logger *logger.Logger) (*Services, error) {
+func NewServices(storages *store.Storages, cfg config.App, clk clock.Clock, logger *logger.Logger) (*Services, error) {
 	logger.Info().Msg("creating new services...")
 
-	appService, err := NewAppInfoService(cfg, logger)
+	appService, err := NewAppInfoService(cfg, storages.SchemaRepository, storages.HardDelete, logger)
 	if err != nil {
 		return nil, fmt.Errorf("error creating app info service: %w", err)
 	}
 
+	authService, err := NewAuthService(storages.UserRepository, cfg, clk, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error creating auth service: %w", err)
+	}
+
 	utils.InitHasherPool(cfg.HashKey)
 
 	return &Services{
-		AppInfoService:     appService,
-		AuthService:        NewAuthService(storages.UserRepository, cfg, logger),
-		PrivateDataService: NewPrivateDataService(storages.PrivateDataStorage, cfg, logger),
+		AppInfoService:      appService,
+		AuthService:         authService,
+		PrivateDataService:  NewPrivateDataService(storages.PrivateDataStorage, cfg, logger),
+		AuditService:        NewAuditService(storages.AuditLogRepository, logger),
+		SyncActivityService: NewSyncActivityService(storages.SyncActivityRepository, clk, logger),
+		Clock:               clk,
 	}, nil
 }
+
+// IdleEvictors returns the services that keep per-user state in memory and
+// need a [Janitor] to evict it.
+func (s *Services) IdleEvictors() []IdleEvictor {
+	var evictors []IdleEvictor
+	if e, ok := s.SyncActivityService.(IdleEvictor); ok {
+		evictors = append(evictors, e)
+	}
+	return evictors
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeLayout names backup files so that their lexical order is their
+// chronological order.
+const backupTimeLayout = "20060102T150405.000000000Z"
+
+// Backup writes a consistent snapshot of the open local database into the
+// configured backup directory and deletes the oldest snapshots of the same
+// database beyond the configured count. The snapshot is named after at, the
+// caller's current time, so the order of the names is the order of the
+// calls. It returns the path of the new snapshot, or an empty path when
+// backups are disabled or no database is open yet.
+//
+// The snapshot is taken with SQLite's VACUUM INTO, so it is safe while the
+// database is in use. The backup directory is created with mode 0700 and
+// every snapshot with mode 0600, like the database itself. Snapshots stay
+// on this device; nothing reads them back except a user restoring by hand.
+func (s *ClientStorages) Backup(ctx context.Context, at time.Time) (string, error) {
+	if s.backupCount <= 0 || s.db == nil || s.dbPath == "" {
+		return "", nil
+	}
+
+	if err := ensurePrivateDir(s.backupDir); err != nil {
+		return "", fmt.Errorf("backup local store: %w", err)
+	}
+
+	prefix := backupPrefix(s.dbPath)
+	path := filepath.Join(s.backupDir, prefix+at.UTC().Format(backupTimeLayout)+".db")
+
+	// VACUUM INTO accepts an existing empty file; creating it first fixes
+	// its mode before any data is written.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("backup local store: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return "", fmt.Errorf("backup local store: %w", err)
+	}
+
+	if _, err = s.db.ExecContext(ctx, "VACUUM INTO ?;", path); err != nil {
+		s.logger.Err(err).Str("func", "ClientStorages.Backup").Msg("error writing local store snapshot")
+		_ = os.Remove(path)
+		return "", fmt.Errorf("backup local store: %w", err)
+	}
+	if err = os.Chmod(path, 0o600); err != nil {
+		return "", fmt.Errorf("backup local store: %w", err)
+	}
+
+	if err = rotateBackups(s.backupDir, prefix, s.backupCount); err != nil {
+		return path, fmt.Errorf("rotate local store backups: %w", err)
+	}
+	return path, nil
+}
+
+// backupPrefix returns the file name prefix shared by every snapshot of the
+// database at dbPath.
+func backupPrefix(dbPath string) string {
+	return strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath)) + "-"
+}
+
+// rotateBackups deletes the oldest snapshots with prefix in dir so that at
+// most keep remain.
+func rotateBackups(dir, prefix string, keep int) error {
+	backups, err := filepath.Glob(filepath.Join(dir, prefix+"*.db"))
+	if err != nil {
+		return err
+	}
+	if len(backups) <= keep {
+		return nil
+	}
+
+	sort.Strings(backups)
+	for _, old := range backups[:len(backups)-keep] {
+		if err = os.Remove(old); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/MKhiriev/go-pass-keeper/models"
 )
@@ -29,30 +30,100 @@ type LocalPrivateDataRepository interface {
 	// error occurs.
 	GetPrivateData(ctx context.Context, clientSideID string, userID int64) (models.PrivateData, error)
 
-	// GetAllPrivateData returns every vault item owned by userID, including
-	// soft-deleted records. Used by the service layer to decrypt and present
-	// the full vault to the user.
+	// GetAllPrivateData returns every live vault item owned by userID;
+	// soft-deleted records are left out. Used by the service layer to decrypt
+	// and present the vault to the user.
 	GetAllPrivateData(ctx context.Context, userID int64) ([]models.PrivateData, error)
 
+	// GetDeletedPrivateData returns only the soft-deleted vault items owned
+	// by userID, the contents of the trash.
+	GetDeletedPrivateData(ctx context.Context, userID int64) ([]models.PrivateData, error)
+
 	// GetAllStates returns lightweight state descriptors (ClientSideID, Hash,
 	// Version, Deleted, UpdatedAt) for all vault items owned by userID.
 	// Used by the sync planner to compare local and server states without
 	// transferring full encrypted payloads.
 	GetAllStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error)
 
+	// CountByType counts the vault items of userID per type with a single
+	// grouped query, without reading any payload. Soft-deleted items are
+	// counted separately in [models.VaultSummary.Deleted].
+	CountByType(ctx context.Context, userID int64) (models.VaultSummary, error)
+
 	// UpdatePrivateData overwrites an existing vault item in the local database
 	// with the field values contained in data. The caller is responsible for
 	// populating Version, Hash, and UpdatedAt before calling this method.
 	UpdatePrivateData(ctx context.Context, data models.PrivateData) error
 
+	// SetServerFields records the server-assigned ID and timestamps returned
+	// by an upload on the matching local items of userID. Nil timestamps
+	// leave the local values untouched.
+	SetServerFields(ctx context.Context, userID int64, items ...models.UploadedItem) error
+
 	// DeletePrivateData performs a soft-delete of the vault item identified by
 	// clientSideID and userID, setting its deleted flag so that the sync
 	// service can propagate the deletion to the server.
 	DeletePrivateData(ctx context.Context, clientSideID string, userID int64) error
 
+	// RestorePrivateData clears the deleted flag of the soft-deleted item
+	// identified by clientSideID and userID. Returns [ErrPrivateDataNotFound]
+	// (wrapped) if there is no such deleted item.
+	RestorePrivateData(ctx context.Context, clientSideID string, userID int64) error
+
+	// PurgePrivateData removes the soft-deleted item identified by
+	// clientSideID and userID from the local database for good. Returns
+	// [ErrPrivateDataNotFound] (wrapped) if there is no such deleted item.
+	PurgePrivateData(ctx context.Context, clientSideID string, userID int64) error
+
 	// IncrementVersion increments the local version counter of the vault item
 	// identified by clientSideID and userID by one. Called after a successful
 	// server-side write (update or delete) to keep the local version in sync
 	// with the server. Returns an error if the record is not found.
 	IncrementVersion(ctx context.Context, clientSideID string, userID int64) error
 }
+
+// LocalUserRepository caches the credential bundle of users who logged in on
+// this device, so that the vault can be unlocked without the server (offline
+// mode). Only values the server already stores are kept: the login, the
+// server-assigned user ID, the auth hash, the encryption salt and the
+// KEK-wrapped DEK. The master password and the plaintext DEK are never stored.
+type LocalUserRepository interface {
+	// SaveUser inserts or replaces the cached credential bundle of user,
+	// keyed by login.
+	SaveUser(ctx context.Context, user models.User) error
+
+	// GetUserByLogin returns the cached credential bundle for login.
+	// Returns [ErrNoUserWasFound] if the user never logged in on this device.
+	GetUserByLogin(ctx context.Context, login string) (models.User, error)
+
+	// GetUserByID returns the cached credential bundle for the
+	// server-assigned userID. Returns [ErrNoUserWasFound] if the user never
+	// logged in on this device.
+	GetUserByID(ctx context.Context, userID int64) (models.User, error)
+}
+
+// LocalSyncStateRepository persists per-user synchronisation bookkeeping in the
+// client's local database so that it survives application restarts.
+type LocalSyncStateRepository interface {
+	// SetLastSyncedAt records syncedAt as the time of the last successful full
+	// sync for userID, replacing any previously stored value.
+	SetLastSyncedAt(ctx context.Context, userID int64, syncedAt time.Time) error
+
+	// GetLastSyncedAt returns the time of the last successful full sync for
+	// userID. A zero time and a nil error are returned if the user has never
+	// synced on this device.
+	GetLastSyncedAt(ctx context.Context, userID int64) (time.Time, error)
+
+	// SavePendingConflicts records conflicts left to the user by a sync, so
+	// that later syncs leave the items alone until they are resolved. A
+	// conflict already recorded for the same item is replaced.
+	SavePendingConflicts(ctx context.Context, userID int64, conflicts ...models.VersionConflict) error
+
+	// GetPendingConflicts returns the unresolved conflicts of userID ordered
+	// by client-side ID, or an empty slice if there are none.
+	GetPendingConflicts(ctx context.Context, userID int64) ([]models.VersionConflict, error)
+
+	// DeletePendingConflict forgets the conflict on clientSideID once it is
+	// resolved. Deleting a conflict that is not recorded is not an error.
+	DeletePendingConflict(ctx context.Context, userID int64, clientSideID string) error
+}
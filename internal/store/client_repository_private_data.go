@@ -49,6 +49,7 @@ func (l *localPrivateDataRepository) SavePrivateData(ctx context.Context, userID
 			item.ClientSideID,
 			item.Hash,
 			item.Deleted,
+			item.ID,
 		)
 		if err != nil {
 			log.Err(err).
@@ -63,6 +64,35 @@ func (l *localPrivateDataRepository) SavePrivateData(ctx context.Context, userID
 	return nil
 }
 
+// SetServerFields implements [LocalPrivateDataRepository]. It stores the
+// server-assigned ID of each uploaded item and overwrites the local timestamps
+// with the server ones when the server returned them.
+//
+// Returns an error wrapping the driver error if any update fails.
+func (l *localPrivateDataRepository) SetServerFields(ctx context.Context, userID int64, items ...models.UploadedItem) error {
+	log := logger.FromContext(ctx)
+
+	for _, item := range items {
+		_, err := l.DB.ExecContext(ctx, setServerFields,
+			item.ID,
+			item.CreatedAt,
+			item.UpdatedAt,
+			userID,
+			item.ClientSideID,
+		)
+		if err != nil {
+			log.Err(err).
+				Str("func", "privateDataRepository.SetServerFields").
+				Int64("user_id", userID).
+				Str("client_side_id", item.ClientSideID).
+				Msg("failed to store server-assigned fields")
+			return fmt.Errorf("failed to store server fields (client_side_id=%s): %w", item.ClientSideID, err)
+		}
+	}
+
+	return nil
+}
+
 // GetPrivateData implements [LocalPrivateDataRepository]. It returns the single
 // vault item identified by clientSideID and userID. Returns an error if the
 // item does not exist or if scanning the result row fails.
@@ -82,6 +112,7 @@ func (l *localPrivateDataRepository) GetPrivateData(ctx context.Context, clientS
 	}
 
 	scanErr := row.Scan(
+		&item.ID,
 		&item.UserID,
 		&item.Payload.Type,
 		&item.Payload.Metadata,
@@ -106,16 +137,29 @@ func (l *localPrivateDataRepository) GetPrivateData(ctx context.Context, clientS
 	return item, nil
 }
 
-// GetAllPrivateData implements [LocalPrivateDataRepository]. It returns all
-// vault items owned by userID, including soft-deleted records. Returns an error
-// if the query or any row-scan fails.
+// GetAllPrivateData implements [LocalPrivateDataRepository]. It returns the
+// live vault items owned by userID; soft-deleted records are left out.
+// Returns an error if the query or any row-scan fails.
 func (l *localPrivateDataRepository) GetAllPrivateData(ctx context.Context, userID int64) ([]models.PrivateData, error) {
+	return l.queryPrivateData(ctx, "privateDataRepository.GetAllPrivateData", getAllPrivateData, userID)
+}
+
+// GetDeletedPrivateData implements [LocalPrivateDataRepository]. It returns
+// only the soft-deleted vault items owned by userID. Returns an error if the
+// query or any row-scan fails.
+func (l *localPrivateDataRepository) GetDeletedPrivateData(ctx context.Context, userID int64) ([]models.PrivateData, error) {
+	return l.queryPrivateData(ctx, "privateDataRepository.GetDeletedPrivateData", getDeletedPrivateData, userID)
+}
+
+// queryPrivateData runs query, which selects the full rows of the items of
+// userID, and scans the result. funcName names the caller in logs.
+func (l *localPrivateDataRepository) queryPrivateData(ctx context.Context, funcName, query string, userID int64) ([]models.PrivateData, error) {
 	log := logger.FromContext(ctx)
 
-	rows, err := l.DB.QueryContext(ctx, getAllPrivateData, userID)
+	rows, err := l.DB.QueryContext(ctx, query, userID)
 	if err != nil {
 		log.Err(err).
-			Str("func", "privateDataRepository.GetAllPrivateData").
+			Str("func", funcName).
 			Int64("user_id", userID).
 			Msg("failed to execute query for getting all private data")
 		return nil, fmt.Errorf("failed to query all private data: %w", err)
@@ -128,6 +172,7 @@ func (l *localPrivateDataRepository) GetAllPrivateData(ctx context.Context, user
 		var item models.PrivateData
 
 		scanErr := rows.Scan(
+			&item.ID,
 			&item.UserID,
 			&item.Payload.Type,
 			&item.Payload.Metadata,
@@ -143,7 +188,7 @@ func (l *localPrivateDataRepository) GetAllPrivateData(ctx context.Context, user
 		)
 		if scanErr != nil {
 			log.Err(scanErr).
-				Str("func", "privateDataRepository.GetAllPrivateData").
+				Str("func", funcName).
 				Int64("user_id", userID).
 				Msg("failed to scan private data row")
 			return nil, fmt.Errorf("failed to scan private data row: %w", scanErr)
@@ -154,7 +199,7 @@ func (l *localPrivateDataRepository) GetAllPrivateData(ctx context.Context, user
 
 	if rowsErr := rows.Err(); rowsErr != nil {
 		log.Err(rowsErr).
-			Str("func", "privateDataRepository.GetAllPrivateData").
+			Str("func", funcName).
 			Int64("user_id", userID).
 			Msg("error occurred during rows iteration")
 		return nil, fmt.Errorf("error iterating private data rows: %w", rowsErr)
@@ -186,6 +231,7 @@ func (l *localPrivateDataRepository) GetAllStates(ctx context.Context, userID in
 		var item models.PrivateDataState
 
 		scanErr := rows.Scan(
+			&item.ID,
 			&item.Version,
 			&item.ClientSideID,
 			&item.Hash,
@@ -214,6 +260,57 @@ func (l *localPrivateDataRepository) GetAllStates(ctx context.Context, userID in
 	return items, nil
 }
 
+// CountByType implements [LocalPrivateDataRepository]. It groups the items of
+// userID by type and deleted flag in SQL and folds the rows into a
+// [models.VaultSummary]. Returns an error if the query or a row scan fails.
+func (l *localPrivateDataRepository) CountByType(ctx context.Context, userID int64) (models.VaultSummary, error) {
+	log := logger.FromContext(ctx)
+
+	summary := models.VaultSummary{ByType: make(map[models.DataType]int)}
+
+	rows, err := l.DB.QueryContext(ctx, countByType, userID)
+	if err != nil {
+		log.Err(err).
+			Str("func", "privateDataRepository.CountByType").
+			Int64("user_id", userID).
+			Msg("failed to execute query for counting items by type")
+		return summary, fmt.Errorf("failed to count items by type: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			dataType models.DataType
+			deleted  bool
+			count    int
+		)
+		if scanErr := rows.Scan(&dataType, &deleted, &count); scanErr != nil {
+			log.Err(scanErr).
+				Str("func", "privateDataRepository.CountByType").
+				Int64("user_id", userID).
+				Msg("failed to scan item count row")
+			return summary, fmt.Errorf("failed to scan item count row: %w", scanErr)
+		}
+
+		if deleted {
+			summary.Deleted += count
+			continue
+		}
+		summary.ByType[dataType] += count
+		summary.Total += count
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		log.Err(rowsErr).
+			Str("func", "privateDataRepository.CountByType").
+			Int64("user_id", userID).
+			Msg("error occurred during rows iteration")
+		return summary, fmt.Errorf("error iterating item count rows: %w", rowsErr)
+	}
+
+	return summary, nil
+}
+
 // UpdatePrivateData implements [LocalPrivateDataRepository]. It overwrites the
 // stored vault item with the values in data, identified by data.ClientSideID
 // and data.UserID. The caller must populate Version, Hash, and UpdatedAt
@@ -266,6 +363,48 @@ func (l *localPrivateDataRepository) DeletePrivateData(ctx context.Context, clie
 	return nil
 }
 
+// RestorePrivateData implements [LocalPrivateDataRepository]. It clears the
+// deleted flag of the soft-deleted item identified by clientSideID and
+// userID. Returns [ErrPrivateDataNotFound] (wrapped) if there is no such
+// deleted item.
+func (l *localPrivateDataRepository) RestorePrivateData(ctx context.Context, clientSideID string, userID int64) error {
+	return l.execOnDeleted(ctx, "privateDataRepository.RestorePrivateData", restorePrivateData, clientSideID, userID)
+}
+
+// PurgePrivateData implements [LocalPrivateDataRepository]. It removes the
+// soft-deleted item identified by clientSideID and userID from the local
+// database. Returns [ErrPrivateDataNotFound] (wrapped) if there is no such
+// deleted item.
+func (l *localPrivateDataRepository) PurgePrivateData(ctx context.Context, clientSideID string, userID int64) error {
+	return l.execOnDeleted(ctx, "privateDataRepository.PurgePrivateData", purgePrivateData, clientSideID, userID)
+}
+
+// execOnDeleted runs query, which changes one soft-deleted item, and reports
+// [ErrPrivateDataNotFound] when no row was affected. funcName names the caller
+// in logs.
+func (l *localPrivateDataRepository) execOnDeleted(ctx context.Context, funcName, query, clientSideID string, userID int64) error {
+	log := logger.FromContext(ctx)
+
+	result, err := l.DB.ExecContext(ctx, query, userID, clientSideID)
+	if err != nil {
+		log.Err(err).
+			Str("func", funcName).
+			Int64("user_id", userID).
+			Str("client_side_id", clientSideID).
+			Msg("failed to execute query for deleted private data")
+		return fmt.Errorf("failed to change deleted private data (client_side_id=%s): %w", clientSideID, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected (client_side_id=%s): %w", clientSideID, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("deleted item %s: %w", clientSideID, ErrPrivateDataNotFound)
+	}
+	return nil
+}
+
 // IncrementVersion implements [LocalPrivateDataRepository]. It increments the
 // version counter of the vault item identified by clientSideID and userID by
 // one. Called after a successful server-side write to keep the local record in
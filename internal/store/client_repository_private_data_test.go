@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalPrivateDataRepository_CountByType(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewClientLogger("test")
+	db, err := openClientDB(config.ClientDB{DSN: filepath.Join(t.TempDir(), "client.db")}, false, log)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	repo := NewLocalPrivateDataRepository(db, log)
+
+	item := func(id string, dataType models.DataType) models.PrivateData {
+		return models.PrivateData{ClientSideID: id, UserID: 1, Version: 1, Hash: "h-" + id, Payload: models.PrivateDataPayload{Type: dataType}}
+	}
+	require.NoError(t, repo.SavePrivateData(ctx, 1,
+		item("l1", models.LoginPassword),
+		item("l2", models.LoginPassword),
+		item("t1", models.Text),
+		item("c1", models.BankCard),
+		item("c2", models.BankCard),
+	))
+	require.NoError(t, repo.DeletePrivateData(ctx, "c2", 1))
+	require.NoError(t, repo.SavePrivateData(ctx, 2, models.PrivateData{
+		ClientSideID: "other", UserID: 2, Version: 1, Hash: "h", Payload: models.PrivateDataPayload{Type: models.Binary},
+	}))
+
+	summary, err := repo.CountByType(ctx, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.VaultSummary{
+		ByType:  map[models.DataType]int{models.LoginPassword: 2, models.Text: 1, models.BankCard: 1},
+		Total:   4,
+		Deleted: 1,
+	}, summary)
+
+	empty, err := repo.CountByType(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, models.VaultSummary{ByType: map[models.DataType]int{}}, empty)
+}
+
+func TestLocalPrivateDataRepository_Trash(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewClientLogger("test")
+	db, err := openClientDB(config.ClientDB{DSN: filepath.Join(t.TempDir(), "client.db")}, false, log)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	repo := NewLocalPrivateDataRepository(db, log)
+
+	item := func(id string) models.PrivateData {
+		return models.PrivateData{ClientSideID: id, UserID: 1, Version: 1, Hash: "h-" + id, Payload: models.PrivateDataPayload{Type: models.Text}}
+	}
+	ids := func(items []models.PrivateData) []string {
+		var out []string
+		for _, it := range items {
+			out = append(out, it.ClientSideID)
+		}
+		return out
+	}
+	require.NoError(t, repo.SavePrivateData(ctx, 1, item("live"), item("gone-1"), item("gone-2")))
+	require.NoError(t, repo.DeletePrivateData(ctx, "gone-1", 1))
+	require.NoError(t, repo.DeletePrivateData(ctx, "gone-2", 1))
+
+	live, err := repo.GetAllPrivateData(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"live"}, ids(live), "the default list excludes deleted items")
+
+	trash, err := repo.GetDeletedPrivateData(ctx, 1)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"gone-1", "gone-2"}, ids(trash), "the trash holds only deleted items")
+	for _, it := range trash {
+		assert.True(t, it.Deleted)
+	}
+
+	// Restoring moves the item back to the list.
+	require.NoError(t, repo.RestorePrivateData(ctx, "gone-1", 1))
+	live, err = repo.GetAllPrivateData(ctx, 1)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"live", "gone-1"}, ids(live))
+
+	// Purging removes the row for good.
+	require.NoError(t, repo.PurgePrivateData(ctx, "gone-2", 1))
+	_, err = repo.GetPrivateData(ctx, "gone-2", 1)
+	assert.Error(t, err)
+	trash, err = repo.GetDeletedPrivateData(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+
+	// Only deleted items can be restored or purged.
+	assert.ErrorIs(t, repo.RestorePrivateData(ctx, "live", 1), ErrPrivateDataNotFound)
+	assert.ErrorIs(t, repo.PurgePrivateData(ctx, "live", 1), ErrPrivateDataNotFound)
+	assert.ErrorIs(t, repo.PurgePrivateData(ctx, "missing", 1), ErrPrivateDataNotFound)
+}
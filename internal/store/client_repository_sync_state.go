@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+type localSyncStateRepository struct {
+	*DB
+	logger *logger.Logger
+}
+
+// NewLocalSyncStateRepository constructs a [LocalSyncStateRepository] backed by
+// the provided SQLite [DB] connection.
+func NewLocalSyncStateRepository(db *DB, logger *logger.Logger) LocalSyncStateRepository {
+	return &localSyncStateRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// SetLastSyncedAt implements [LocalSyncStateRepository]. It upserts the
+// sync_state row for userID. The time is stored in UTC.
+func (l *localSyncStateRepository) SetLastSyncedAt(ctx context.Context, userID int64, syncedAt time.Time) error {
+	log := logger.FromContext(ctx)
+
+	if _, err := l.DB.ExecContext(ctx, setLastSyncedAt, userID, syncedAt.UTC()); err != nil {
+		log.Err(err).
+			Str("func", "syncStateRepository.SetLastSyncedAt").
+			Int64("user_id", userID).
+			Msg("failed to save last synced time")
+		return fmt.Errorf("failed to save last synced time: %w", err)
+	}
+
+	return nil
+}
+
+// GetLastSyncedAt implements [LocalSyncStateRepository]. It returns the zero
+// time if no sync_state row exists for userID.
+func (l *localSyncStateRepository) GetLastSyncedAt(ctx context.Context, userID int64) (time.Time, error) {
+	log := logger.FromContext(ctx)
+
+	var syncedAt time.Time
+	err := l.DB.QueryRowContext(ctx, getLastSyncedAt, userID).Scan(&syncedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		log.Err(err).
+			Str("func", "syncStateRepository.GetLastSyncedAt").
+			Int64("user_id", userID).
+			Msg("failed to query last synced time")
+		return time.Time{}, fmt.Errorf("failed to query last synced time: %w", err)
+	}
+
+	return syncedAt, nil
+}
+
+// SavePendingConflicts implements [LocalSyncStateRepository]. Each conflict is
+// upserted by (user_id, client_side_id).
+func (l *localSyncStateRepository) SavePendingConflicts(ctx context.Context, userID int64, conflicts ...models.VersionConflict) error {
+	log := logger.FromContext(ctx)
+
+	for _, c := range conflicts {
+		_, err := l.DB.ExecContext(ctx, savePendingConflict, userID, c.ClientSideID, string(c.Operation), c.ServerVersion)
+		if err != nil {
+			log.Err(err).
+				Str("func", "syncStateRepository.SavePendingConflicts").
+				Int64("user_id", userID).
+				Str("client_side_id", c.ClientSideID).
+				Msg("failed to save pending conflict")
+			return fmt.Errorf("failed to save pending conflict (client_side_id=%s): %w", c.ClientSideID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetPendingConflicts implements [LocalSyncStateRepository].
+func (l *localSyncStateRepository) GetPendingConflicts(ctx context.Context, userID int64) ([]models.VersionConflict, error) {
+	log := logger.FromContext(ctx)
+
+	rows, err := l.DB.QueryContext(ctx, getPendingConflicts, userID)
+	if err != nil {
+		log.Err(err).
+			Str("func", "syncStateRepository.GetPendingConflicts").
+			Int64("user_id", userID).
+			Msg("failed to query pending conflicts")
+		return nil, fmt.Errorf("failed to query pending conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	conflicts := make([]models.VersionConflict, 0)
+	for rows.Next() {
+		var (
+			c         models.VersionConflict
+			operation string
+		)
+		if err = rows.Scan(&c.ClientSideID, &operation, &c.ServerVersion); err != nil {
+			return nil, fmt.Errorf("failed to scan pending conflict: %w", err)
+		}
+		c.Operation = models.ConflictOperation(operation)
+		conflicts = append(conflicts, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pending conflicts: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+// DeletePendingConflict implements [LocalSyncStateRepository].
+func (l *localSyncStateRepository) DeletePendingConflict(ctx context.Context, userID int64, clientSideID string) error {
+	log := logger.FromContext(ctx)
+
+	if _, err := l.DB.ExecContext(ctx, deletePendingConflict, userID, clientSideID); err != nil {
+		log.Err(err).
+			Str("func", "syncStateRepository.DeletePendingConflict").
+			Int64("user_id", userID).
+			Str("client_side_id", clientSideID).
+			Msg("failed to delete pending conflict")
+		return fmt.Errorf("failed to delete pending conflict (client_side_id=%s): %w", clientSideID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalSyncStateRepository_PendingConflicts(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewClientLogger("test")
+	db, err := openClientDB(config.ClientDB{DSN: filepath.Join(t.TempDir(), "client.db")}, false, log)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	repo := NewLocalSyncStateRepository(db, log)
+
+	empty, err := repo.GetPendingConflicts(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	require.NoError(t, repo.SavePendingConflicts(ctx, 1,
+		models.VersionConflict{Operation: models.ConflictOnUpdate, ClientSideID: "b", ServerVersion: 4},
+		models.VersionConflict{Operation: models.ConflictOnUpdate, ClientSideID: "a", ServerVersion: 2},
+	))
+	require.NoError(t, repo.SavePendingConflicts(ctx, 2, models.VersionConflict{ClientSideID: "other"}))
+	// A newer conflict on the same item replaces the recorded one.
+	require.NoError(t, repo.SavePendingConflicts(ctx, 1, models.VersionConflict{Operation: models.ConflictOnUpdate, ClientSideID: "b", ServerVersion: 6}))
+
+	got, err := repo.GetPendingConflicts(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []models.VersionConflict{
+		{Operation: models.ConflictOnUpdate, ClientSideID: "a", ServerVersion: 2},
+		{Operation: models.ConflictOnUpdate, ClientSideID: "b", ServerVersion: 6},
+	}, got)
+
+	require.NoError(t, repo.DeletePendingConflict(ctx, 1, "a"))
+	require.NoError(t, repo.DeletePendingConflict(ctx, 1, "missing"))
+
+	got, err = repo.GetPendingConflicts(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []models.VersionConflict{{Operation: models.ConflictOnUpdate, ClientSideID: "b", ServerVersion: 6}}, got)
+}
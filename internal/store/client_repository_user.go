@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+type localUserRepository struct {
+	*DB
+	logger *logger.Logger
+}
+
+// NewLocalUserRepository constructs a [LocalUserRepository] backed by the
+// provided SQLite [DB] connection.
+func NewLocalUserRepository(db *DB, logger *logger.Logger) LocalUserRepository {
+	return &localUserRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// SaveUser implements [LocalUserRepository]. An existing row with the same
+// login or user ID is replaced.
+func (l *localUserRepository) SaveUser(ctx context.Context, user models.User) error {
+	log := logger.FromContext(ctx)
+
+	if _, err := l.DB.ExecContext(ctx, saveLocalUser,
+		user.UserID,
+		user.Login,
+		user.AuthHash,
+		user.EncryptionSalt,
+		user.EncryptedMasterKey,
+	); err != nil {
+		log.Err(err).
+			Str("func", "localUserRepository.SaveUser").
+			Int64("user_id", user.UserID).
+			Msg("failed to save local user")
+		return fmt.Errorf("failed to save local user: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserByLogin implements [LocalUserRepository].
+func (l *localUserRepository) GetUserByLogin(ctx context.Context, login string) (models.User, error) {
+	return l.getUser(ctx, "localUserRepository.GetUserByLogin", getLocalUserByLogin, login)
+}
+
+// GetUserByID implements [LocalUserRepository].
+func (l *localUserRepository) GetUserByID(ctx context.Context, userID int64) (models.User, error) {
+	return l.getUser(ctx, "localUserRepository.GetUserByID", getLocalUserByID, userID)
+}
+
+// getUser runs query, which selects a single cached user by arg.
+func (l *localUserRepository) getUser(ctx context.Context, funcName, query string, arg any) (models.User, error) {
+	log := logger.FromContext(ctx)
+
+	var user models.User
+	err := l.DB.QueryRowContext(ctx, query, arg).Scan(
+		&user.UserID,
+		&user.Login,
+		&user.AuthHash,
+		&user.EncryptionSalt,
+		&user.EncryptedMasterKey,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, ErrNoUserWasFound
+	}
+	if err != nil {
+		log.Err(err).
+			Str("func", funcName).
+			Msg("failed to query local user")
+		return models.User{}, fmt.Errorf("failed to query local user: %w", err)
+	}
+
+	return user, nil
+}
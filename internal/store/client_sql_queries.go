@@ -17,11 +17,13 @@ const (
 			version,
 			client_side_id,
 			hash,
-			deleted
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);`
+			deleted,
+			server_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, 0));`
 
 	getSinglePrivateData = `
 		SELECT
+			COALESCE(server_id, 0),
 			user_id,
 			type,
 			metadata,
@@ -39,6 +41,7 @@ const (
 
 	getAllPrivateData = `
 		SELECT
+			COALESCE(server_id, 0),
 			user_id,
 			type,
 			metadata,
@@ -54,8 +57,33 @@ const (
 		FROM ciphers
 		WHERE user_id = $1 AND deleted=false;`
 
+	getDeletedPrivateData = `
+		SELECT
+			COALESCE(server_id, 0),
+			user_id,
+			type,
+			metadata,
+			data,
+			notes,
+			additional_fields,
+			created_at,
+			updated_at,
+			version,
+			client_side_id,
+			hash,
+			deleted
+		FROM ciphers
+		WHERE user_id = $1 AND deleted=true;`
+
+	countByType = `
+		SELECT type, deleted, COUNT(*)
+		FROM ciphers
+		WHERE user_id = $1
+		GROUP BY type, deleted;`
+
 	getAllStates = `
 		SELECT
+			COALESCE(server_id, 0),
 			version,
 			client_side_id,
 			hash,
@@ -83,9 +111,83 @@ const (
 			updated_at = CURRENT_TIMESTAMP
 		WHERE user_id = $1 AND client_side_id = $2;`
 
+	restorePrivateData = `
+		UPDATE ciphers SET
+			deleted    = false,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND client_side_id = $2 AND deleted = true;`
+
+	purgePrivateData = `
+		DELETE FROM ciphers
+		WHERE user_id = $1 AND client_side_id = $2 AND deleted = true;`
+
 	incrementVersion = `
 		UPDATE ciphers
 		SET version = version + 1
 		WHERE client_side_id = $1
 		  AND user_id = $2;`
+
+	setServerFields = `
+		UPDATE ciphers SET
+			server_id  = $1,
+			created_at = COALESCE($2, created_at),
+			updated_at = COALESCE($3, updated_at)
+		WHERE user_id = $4 AND client_side_id = $5;`
+
+	setLastSyncedAt = `
+		INSERT INTO sync_state (user_id, last_synced_at)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET last_synced_at = excluded.last_synced_at;`
+
+	getLastSyncedAt = `
+		SELECT last_synced_at
+		FROM sync_state
+		WHERE user_id = $1;`
+
+	savePendingConflict = `
+		INSERT INTO pending_conflicts (user_id, client_side_id, operation, server_version)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, client_side_id) DO UPDATE SET
+			operation = excluded.operation,
+			server_version = excluded.server_version;`
+
+	getPendingConflicts = `
+		SELECT client_side_id, operation, server_version
+		FROM pending_conflicts
+		WHERE user_id = $1
+		ORDER BY client_side_id;`
+
+	deletePendingConflict = `
+		DELETE FROM pending_conflicts
+		WHERE user_id = $1 AND client_side_id = $2;`
+
+	saveLocalUser = `
+		INSERT OR REPLACE INTO users (
+			user_id,
+			login,
+			auth_hash,
+			encryption_salt,
+			encrypted_master_key,
+			updated_at
+		) VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP);`
+
+	getLocalUserByLogin = `
+		SELECT
+			user_id,
+			login,
+			auth_hash,
+			encryption_salt,
+			encrypted_master_key
+		FROM users
+		WHERE login = $1;`
+
+	getLocalUserByID = `
+		SELECT
+			user_id,
+			login,
+			auth_hash,
+			encryption_salt,
+			encrypted_master_key
+		FROM users
+		WHERE user_id = $1;`
 )
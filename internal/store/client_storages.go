@@ -5,36 +5,158 @@ package store
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 )
 
 // ClientStorages groups all client-side storage repositories into a single
-// value that can be passed around the service layer. Currently it holds only
-// [LocalPrivateDataRepository]; additional repositories can be added here as
-// the feature set grows.
+// value that can be passed around the service layer. It holds the
+// [LocalPrivateDataRepository], [LocalSyncStateRepository] and
+// [LocalUserRepository]; additional repositories can be added here as the
+// feature set grows.
+//
+// Without a configured DSN every account login has its own database file,
+// opened by [ClientStorages.OpenAccount]; the repositories are nil until then.
 type ClientStorages struct {
 	// PrivateDataRepository is the SQLite-backed repository for encrypted
 	// vault items stored locally on the client device.
 	PrivateDataRepository LocalPrivateDataRepository
+
+	// SyncStateRepository stores per-user sync bookkeeping such as the time
+	// of the last successful full sync.
+	SyncStateRepository LocalSyncStateRepository
+
+	// UserRepository caches the credentials needed to unlock the vault
+	// without contacting the server.
+	UserRepository LocalUserRepository
+
+	// cfg and logger open the per-account databases. cfg.DataDir is empty
+	// when one database is shared by all accounts.
+	cfg    config.ClientStorage
+	logger *logger.Logger
+
+	// db and dbPath are the open database and its file.
+	db     *DB
+	dbPath string
+
+	// backupDir and backupCount configure [ClientStorages.Backup].
+	backupDir   string
+	backupCount int
 }
 
 // NewClientStorages initialises the client storage layer using the supplied
-// configuration and logger. It performs the following steps:
+// configuration and logger. When cfg.DB.DSN is set it performs the following
+// steps:
 //  1. Opens an SQLite connection to the file path specified in cfg.DB.DSN,
 //     creating the database file if it does not yet exist.
 //  2. Runs pending schema migrations via [DB.Migrate].
-//  3. Constructs and returns a [ClientStorages] value wired to a fresh
-//     [LocalPrivateDataRepository].
+//  3. In read-only mode (cfg.ReadOnly), switches the connection to
+//     query-only so that any write fails.
+//  4. Constructs and returns a [ClientStorages] value wired to fresh
+//     local repositories.
+//
+// Without a DSN no database is opened yet: the same steps run for the
+// account's own file in cfg.DataDir when [ClientStorages.OpenAccount] is
+// called at login.
 //
 // Returns an error if the database connection cannot be established or if
 // migration fails.
 func NewClientStorages(cfg config.ClientStorage, logger *logger.Logger) (*ClientStorages, error) {
 	logger.Info().Msg("creating new storages...")
 
-	db, err := NewConnectSQLite(context.Background(), cfg.DB, logger)
+	if cfg.DB.DSN == "" {
+		return &ClientStorages{cfg: cfg, logger: logger, backupDir: cfg.BackupDir, backupCount: cfg.BackupCount}, nil
+	}
+
+	db, err := openClientDB(cfg.DB, cfg.ReadOnly, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	storages := &ClientStorages{db: db, dbPath: cfg.DB.DSN, logger: logger, backupDir: cfg.BackupDir, backupCount: cfg.BackupCount}
+	storages.use(db, logger)
+	return storages, nil
+}
+
+// Close closes the open local database, if any. It is safe to call more than
+// once; the repositories must not be used afterwards.
+func (s *ClientStorages) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	db := s.db
+	s.db, s.dbPath = nil, ""
+	return db.Close()
+}
+
+// OpenAccount switches the repositories to the local database of the account
+// login, creating the data directory with mode 0700 and the database file
+// with mode 0600 if needed. The database of the previous account is closed.
+// It does nothing when all accounts share the configured DSN or when the
+// account's database is already open.
+//
+// OpenAccount must not be called while the repositories are in use.
+func (s *ClientStorages) OpenAccount(login string) error {
+	if s.cfg.DataDir == "" {
+		return nil
+	}
+
+	path := AccountDBPath(s.cfg.DataDir, login)
+	if path == s.dbPath {
+		return nil
+	}
+
+	if err := ensurePrivateDir(s.cfg.DataDir); err != nil {
+		return err
+	}
+
+	db, err := openClientDB(config.ClientDB{DSN: path}, s.cfg.ReadOnly, s.logger)
+	if err != nil {
+		return err
+	}
+
+	if s.db != nil {
+		if err = s.db.Close(); err != nil {
+			s.logger.Err(err).Str("func", "ClientStorages.OpenAccount").Msg("error closing previous account database")
+		}
+	}
+	s.use(db, s.logger)
+	s.db, s.dbPath = db, path
+	return nil
+}
+
+// AccountDBPath returns the path of the local database of the account login
+// inside dataDir. The file name is derived from a hash of the trimmed,
+// lower-cased login, so that any login maps to a safe file name and logins
+// differing only in case share a database.
+func AccountDBPath(dataDir, login string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(login))))
+	return filepath.Join(dataDir, "vault-"+hex.EncodeToString(sum[:8])+".db")
+}
+
+// ensurePrivateDir creates dir with mode 0700, or restricts an existing dir
+// to it, so that other OS users cannot list or read the vault databases.
+func ensurePrivateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create data directory: %w", err)
+	}
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return fmt.Errorf("restrict data directory permissions: %w", err)
+	}
+	return nil
+}
+
+// openClientDB opens and migrates the client database cfg, switching it to
+// query-only in readOnly mode.
+func openClientDB(cfg config.ClientDB, readOnly bool, logger *logger.Logger) (*DB, error) {
+	db, err := NewConnectSQLite(context.Background(), cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("postgres connection error: %w", err)
 	}
@@ -43,7 +165,17 @@ func NewClientStorages(cfg config.ClientStorage, logger *logger.Logger) (*Client
 		return nil, fmt.Errorf("migration failed: %w", err)
 	}
 
-	return &ClientStorages{
-		PrivateDataRepository: NewLocalPrivateDataRepository(db, logger),
-	}, nil
+	if readOnly {
+		if err := db.setQueryOnly(context.Background()); err != nil {
+			return nil, fmt.Errorf("switch to read-only: %w", err)
+		}
+	}
+	return db, nil
+}
+
+// use wires fresh local repositories to db.
+func (s *ClientStorages) use(db *DB, logger *logger.Logger) {
+	s.PrivateDataRepository = NewLocalPrivateDataRepository(db, logger)
+	s.SyncStateRepository = NewLocalSyncStateRepository(db, logger)
+	s.UserRepository = NewLocalUserRepository(db, logger)
 }
@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountDBPath(t *testing.T) {
+	dir := filepath.Join("data", "vault")
+
+	alice := AccountDBPath(dir, "alice")
+	bob := AccountDBPath(dir, "bob")
+
+	assert.NotEqual(t, alice, bob)
+	assert.Equal(t, alice, AccountDBPath(dir, " Alice "))
+	assert.Equal(t, dir, filepath.Dir(alice))
+	assert.Equal(t, dir, filepath.Dir(AccountDBPath(dir, "../../etc/passwd")))
+}
+
+func TestClientStorages_OpenAccount(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), "go-pass-keeper")
+	storages, err := NewClientStorages(config.ClientStorage{DataDir: dataDir}, logger.NewClientLogger("test"))
+	require.NoError(t, err)
+	assert.Nil(t, storages.UserRepository, "no database is opened before login")
+
+	require.NoError(t, storages.OpenAccount("alice"))
+	require.NotNil(t, storages.UserRepository)
+	aliceRepo := storages.UserRepository
+
+	dirInfo, err := os.Stat(dataDir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), dirInfo.Mode().Perm())
+
+	fileInfo, err := os.Stat(AccountDBPath(dataDir, "alice"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fileInfo.Mode().Perm())
+
+	require.NoError(t, storages.OpenAccount("alice"))
+	assert.Same(t, aliceRepo, storages.UserRepository, "reopening the same account keeps its database")
+
+	require.NoError(t, storages.OpenAccount("bob"))
+	assert.NotSame(t, aliceRepo, storages.UserRepository)
+	_, err = os.Stat(AccountDBPath(dataDir, "bob"))
+	require.NoError(t, err)
+}
+
+func TestClientStorages_OpenAccount_SharedDSN(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "client.db")
+	storages, err := NewClientStorages(config.ClientStorage{DB: config.ClientDB{DSN: dsn}, DataDir: t.TempDir()}, logger.NewClientLogger("test"))
+	require.NoError(t, err)
+	repo := storages.UserRepository
+
+	require.NoError(t, storages.OpenAccount("alice"))
+	assert.Same(t, repo, storages.UserRepository)
+}
+
+func TestClientStorages_Close(t *testing.T) {
+	storages, err := NewClientStorages(config.ClientStorage{DataDir: t.TempDir()}, logger.NewClientLogger("test"))
+	require.NoError(t, err)
+	require.NoError(t, storages.Close(), "nothing opened yet")
+
+	require.NoError(t, storages.OpenAccount("alice"))
+	require.NoError(t, storages.Close())
+	require.NoError(t, storages.Close())
+
+	// The account can be reopened after a close.
+	require.NoError(t, storages.OpenAccount("alice"))
+	require.NoError(t, storages.Close())
+}
+
+func TestClientStorages_Backup(t *testing.T) {
+	dataDir := t.TempDir()
+	backupDir := filepath.Join(dataDir, "backups")
+	storages, err := NewClientStorages(config.ClientStorage{DataDir: dataDir, BackupDir: backupDir, BackupCount: 2}, logger.NewClientLogger("test"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storages.Close() })
+
+	at := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	path, err := storages.Backup(testContext(), at)
+	require.NoError(t, err)
+	assert.Empty(t, path, "no database is open before login")
+
+	require.NoError(t, storages.OpenAccount("alice"))
+	require.NoError(t, storages.SyncStateRepository.SetLastSyncedAt(testContext(), 1, time.Unix(1700000000, 0)))
+
+	path, err = storages.Backup(testContext(), at)
+	require.NoError(t, err)
+	require.NotEmpty(t, path)
+	assert.Equal(t, backupDir, filepath.Dir(path))
+	assert.Equal(t, backupPrefix(AccountDBPath(dataDir, "alice"))+"20260301T123000.000000000Z.db", filepath.Base(path), "named after the given time")
+
+	dirInfo, err := os.Stat(backupDir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), dirInfo.Mode().Perm())
+
+	fileInfo, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fileInfo.Mode().Perm())
+
+	// The snapshot is a complete database of its own.
+	snapshot, err := NewClientStorages(config.ClientStorage{DB: config.ClientDB{DSN: path}}, logger.NewClientLogger("test"))
+	require.NoError(t, err)
+	syncedAt, err := snapshot.SyncStateRepository.GetLastSyncedAt(testContext(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000), syncedAt.Unix())
+	require.NoError(t, snapshot.Close())
+}
+
+func TestClientStorages_Backup_Rotation(t *testing.T) {
+	dataDir := t.TempDir()
+	backupDir := filepath.Join(dataDir, "backups")
+	storages, err := NewClientStorages(config.ClientStorage{DataDir: dataDir, BackupDir: backupDir, BackupCount: 3}, logger.NewClientLogger("test"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storages.Close() })
+	require.NoError(t, storages.OpenAccount("alice"))
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var paths []string
+	for i := range 5 {
+		path, err := storages.Backup(testContext(), at.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+		paths = append(paths, path)
+	}
+
+	entries, err := os.ReadDir(backupDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+	for _, path := range paths[:2] {
+		assert.NoFileExists(t, path, "the oldest snapshots are deleted")
+	}
+	for _, path := range paths[2:] {
+		assert.FileExists(t, path)
+	}
+
+	// Snapshots of another account's database are rotated separately.
+	require.NoError(t, storages.OpenAccount("bob"))
+	_, err = storages.Backup(testContext(), at)
+	require.NoError(t, err)
+	entries, err = os.ReadDir(backupDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+}
+
+func TestClientStorages_Backup_Disabled(t *testing.T) {
+	dataDir := t.TempDir()
+	backupDir := filepath.Join(dataDir, "backups")
+	storages, err := NewClientStorages(config.ClientStorage{DataDir: dataDir, BackupDir: backupDir}, logger.NewClientLogger("test"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storages.Close() })
+	require.NoError(t, storages.OpenAccount("alice"))
+
+	path, err := storages.Backup(testContext(), time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, path)
+	assert.NoDirExists(t, backupDir)
+}
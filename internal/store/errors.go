@@ -3,7 +3,12 @@
 
 package store
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
 
 // Sentinel errors returned by repository methods to signal well-known failure
 // conditions. Callers should use [errors.Is] to match against these values.
@@ -31,8 +36,56 @@ var (
 	// stored in the database, meaning another device has modified the record
 	// since the client last synchronized.
 	ErrVersionConflict = errors.New("private data version conflict occurred")
+
+	// ErrPrivateDataTombstoned is returned when an upload or update targets a
+	// client_side_id whose record has been soft-deleted. The tombstone blocks
+	// reuse of the id until it is purged; the client should generate a fresh
+	// client_side_id for new content.
+	ErrPrivateDataTombstoned = errors.New("client_side_id belongs to a deleted record")
+
+	// ErrPrivateDataNotDeleted is returned when a restore targets a record
+	// that is live, i.e. not soft-deleted. Nothing is changed.
+	ErrPrivateDataNotDeleted = errors.New("private data is not deleted")
+
+	// ErrPrivateDataAlreadyExists is returned when an upload uses a
+	// client_side_id that already identifies a live record of the same user.
+	ErrPrivateDataAlreadyExists = errors.New("private data already exists")
+
+	// ErrSyncLocked is returned when a batch update or delete cannot acquire
+	// the per-user sync lock within the configured timeout because a
+	// concurrent update or delete of the same user holds it. Nothing has
+	// been changed; the client should retry later.
+	ErrSyncLocked = errors.New("another sync of this user is in progress")
+
+	// ErrSchemaDrift is returned by the startup schema check when a table
+	// does not have exactly the expected columns, e.g. after a partially
+	// applied migration.
+	ErrSchemaDrift = errors.New("database schema does not match the expected schema")
 )
 
+// VersionConflictError is the [ErrVersionConflict] returned by updates and
+// deletes. It carries the rejected operation and the version currently
+// stored, so that the handler can report it to the client.
+type VersionConflictError struct {
+	models.VersionConflict
+}
+
+// Error implements error.
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s of %s: %s (current version %d)", e.Operation, e.ClientSideID, ErrVersionConflict, e.ServerVersion)
+}
+
+// Unwrap makes [errors.Is] match [ErrVersionConflict].
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// newVersionConflict returns the [VersionConflictError] of operation on
+// clientSideID whose stored version is serverVersion.
+func newVersionConflict(operation models.ConflictOperation, clientSideID string, serverVersion int64) error {
+	return &VersionConflictError{models.VersionConflict{Operation: operation, ClientSideID: clientSideID, ServerVersion: serverVersion}}
+}
+
 // Low-level database operation errors. These are returned (or wrapped) by
 // repository methods when a SQL-level operation fails before any domain logic
 // can be applied.
@@ -68,4 +121,18 @@ var (
 	// ErrScanningRows is returned when scanning column values during
 	// multi-row iteration fails, typically mid-result-set.
 	ErrScanningRows = errors.New("failed to scan private data rows")
+
+	// ErrWritingAuditEntry is returned when the audit row accompanying a vault
+	// mutation cannot be inserted. The mutation is rolled back with it.
+	ErrWritingAuditEntry = errors.New("failed to write audit entry")
+
+	// ErrWritingVersionHistory is returned when the previous version of an
+	// updated vault item cannot be archived or the history cannot be
+	// trimmed. The update is rolled back with it.
+	ErrWritingVersionHistory = errors.New("failed to write version history")
+
+	// ErrTooManyClientSideIDs is returned when a download or states request
+	// filters by more client-side IDs than [config.Storage.MaxClientSideIDs]
+	// allows. The client should split the request.
+	ErrTooManyClientSideIDs = errors.New("too many client side IDs in one request")
 )
@@ -11,6 +11,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/MKhiriev/go-pass-keeper/models"
 )
@@ -36,11 +37,11 @@ type PrivateDataStorage interface {
 	// including soft-deleted records.
 	GetAll(ctx context.Context, userID int64) ([]models.PrivateData, error)
 
-	// GetAllStates returns lightweight state descriptors for every vault item
-	// owned by the given user. The result contains only identity and
-	// change-detection fields (ClientSideID, Hash, Version, Deleted, UpdatedAt),
-	// without encrypted payloads.
-	GetAllStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error)
+	// GetAllStates returns one page of lightweight state descriptors for the
+	// vault items owned by request.UserID, ordered by record id. The result
+	// contains only identity and change-detection fields (ID, ClientSideID,
+	// Hash, Version, Deleted, UpdatedAt), without encrypted payloads.
+	GetAllStates(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error)
 
 	// GetStates returns lightweight state descriptors for vault items
 	// whose ClientSideIDs are listed in syncRequest.
@@ -48,15 +49,28 @@ type PrivateDataStorage interface {
 	// fetched, pushed, or removed on the client.
 	GetStates(ctx context.Context, syncRequest models.SyncRequest) ([]models.PrivateDataState, error)
 
+	// GetAllClientSideIDs returns the client-side ID of every vault item of
+	// userID. See [PrivateDataRepository.GetAllClientSideIDs].
+	GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error)
+
 	// Update applies a batch of partial updates described in updateRequests.
 	// Each update uses optimistic locking: the provided Version must match
 	// the current database version, otherwise [ErrVersionConflict] is returned.
 	Update(ctx context.Context, updateRequests models.UpdateRequest) error
 
-	// Delete performs a soft-delete of one or more vault items described
-	// in deleteRequests. The records remain in the database with the Deleted
-	// flag set to true so that clients can detect the deletion during sync.
+	// Delete deletes one or more vault items described in deleteRequests.
+	// By default the records remain in the database with the Deleted flag
+	// set to true so that clients can detect the deletion during sync; in
+	// hard-delete mode they are removed.
 	Delete(ctx context.Context, deleteRequests models.DeleteRequest) error
+
+	// Restore undoes the soft-delete of one or more vault items described in
+	// restoreRequests. Each entry uses optimistic locking like [Update].
+	Restore(ctx context.Context, restoreRequests models.RestoreRequest) error
+
+	// GetVersionHistory returns the archived previous versions of a vault
+	// item, newest first. See [PrivateDataRepository.GetVersionHistory].
+	GetVersionHistory(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error)
 }
 
 // PrivateDataRepository defines the relational database access contract
@@ -73,24 +87,83 @@ type PrivateDataRepository interface {
 	// Filtering is applied by UserID and, optionally, by ClientSideIDs.
 	GetPrivateData(ctx context.Context, downloadRequests models.DownloadRequest) ([]models.PrivateData, error)
 
+	// GetSinglePrivateData returns the vault item of userID identified by
+	// clientSideID, including soft-deleted ones. Returns
+	// [ErrPrivateDataNotFound] if it does not exist.
+	GetSinglePrivateData(ctx context.Context, userID int64, clientSideID string) (models.PrivateData, error)
+
 	// GetAllPrivateData returns all vault items belonging to the specified user.
 	GetAllPrivateData(ctx context.Context, userID int64) ([]models.PrivateData, error)
 
-	// GetAllStates returns lightweight state descriptors for all vault items
-	// owned by the specified user, without encrypted payload fields.
-	GetAllStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error)
+	// GetAllStates returns state descriptors for the vault items of
+	// request.UserID with id greater than request.After, ordered by id and
+	// limited to request.Limit rows (all when zero), without encrypted
+	// payload fields.
+	GetAllStates(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error)
 
 	// GetStates returns lightweight state descriptors for vault items
 	// whose ClientSideIDs are listed in syncRequest.
 	GetStates(ctx context.Context, syncRequest models.SyncRequest) ([]models.PrivateDataState, error)
 
+	// GetAllClientSideIDs returns the client-side ID and deleted flag of
+	// every vault item of userID, tombstones included, ordered by record
+	// id. Items removed in hard-delete mode are not listed.
+	GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error)
+
 	// UpdatePrivateData applies a batch of partial updates with optimistic
 	// locking. Returns [ErrVersionConflict] on version mismatch or
 	// [ErrPrivateDataNotFound] if a targeted record does not exist.
 	UpdatePrivateData(ctx context.Context, updateRequests models.UpdateRequest) error
 
-	// DeletePrivateData soft-deletes vault items identified in deleteRequests.
+	// DeletePrivateData soft-deletes (or, in hard-delete mode, removes)
+	// vault items identified in deleteRequests.
 	DeletePrivateData(ctx context.Context, deleteRequests models.DeleteRequest) error
+
+	// RestorePrivateData clears the deleted flag of the soft-deleted vault
+	// items identified in restoreRequests, with optimistic locking. Returns
+	// [ErrVersionConflict] on version mismatch or [ErrPrivateDataNotFound]
+	// if a targeted record does not exist.
+	RestorePrivateData(ctx context.Context, restoreRequests models.RestoreRequest) error
+
+	// GetVersionHistory returns the archived previous versions of the vault
+	// item of userID identified by clientSideID, newest first. The result
+	// is empty when version history is disabled or the item was never
+	// updated.
+	GetVersionHistory(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error)
+}
+
+// AuditLogRepository provides read access to the append-only audit trail of
+// vault mutations. Entries are written by [PrivateDataRepository] inside the
+// same transaction as the mutation they describe, so the two cannot diverge.
+type AuditLogRepository interface {
+	// GetAuditLog returns a page of audit entries for request.UserID ordered
+	// from newest to oldest.
+	GetAuditLog(ctx context.Context, request models.AuditLogRequest) ([]models.AuditEntry, error)
+}
+
+// SyncActivityRepository stores the time each user was last seen syncing,
+// used to report how far behind a user's clients are.
+type SyncActivityRepository interface {
+	// TouchLastSeen records at as the last-seen time of userID. An earlier
+	// time than the stored one is ignored.
+	TouchLastSeen(ctx context.Context, userID int64, at time.Time) error
+
+	// GetLastSeen returns a page of last-seen times, stalest first, for
+	// request.UserID or, when it is zero, for every user seen so far.
+	// LagSeconds is left zero.
+	GetLastSeen(ctx context.Context, request models.SyncLagRequest) ([]models.SyncLag, error)
+}
+
+// SchemaRepository reports the state of the database schema.
+type SchemaRepository interface {
+	// SchemaVersion returns the version of the newest applied migration,
+	// or zero when no migration has been applied.
+	SchemaVersion(ctx context.Context) (int64, error)
+
+	// CheckSchema verifies that the "ciphers" table has exactly the columns
+	// the repositories query. Returns [ErrSchemaDrift] listing the missing
+	// and unexpected columns on a mismatch.
+	CheckSchema(ctx context.Context) error
 }
 
 // PrivateDataFileStorage defines the contract for persisting and retrieving
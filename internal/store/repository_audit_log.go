@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// auditLogRepository is the PostgreSQL-backed implementation of
+// [AuditLogRepository]. It reads from the append-only "audit_log" table.
+type auditLogRepository struct {
+	*DB
+	logger *logger.Logger
+}
+
+// NewAuditLogRepository constructs an [AuditLogRepository] backed by the
+// provided database connection and logger.
+func NewAuditLogRepository(db *DB, logger *logger.Logger) AuditLogRepository {
+	return &auditLogRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// GetAuditLog implements [AuditLogRepository]. Entries are ordered by ID
+// descending so that the newest mutation comes first and pagination via
+// LIMIT/OFFSET is stable while new entries are appended.
+func (a *auditLogRepository) GetAuditLog(ctx context.Context, request models.AuditLogRequest) ([]models.AuditEntry, error) {
+	log := logger.FromContext(ctx)
+
+	rows, err := a.DB.QueryContext(ctx, getAuditEntries, request.UserID, request.Limit, request.Offset)
+	if err != nil {
+		log.Err(err).
+			Str("func", "auditLogRepository.GetAuditLog").
+			Int64("user_id", request.UserID).
+			Msg("failed to execute query for getting audit entries")
+		return nil, fmt.Errorf("%w: %w", ErrExecutingQuery, err)
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var entry models.AuditEntry
+		if scanErr := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Operation,
+			&entry.ClientSideID,
+			&entry.Version,
+			&entry.CreatedAt,
+		); scanErr != nil {
+			log.Err(scanErr).
+				Str("func", "auditLogRepository.GetAuditLog").
+				Int64("user_id", request.UserID).
+				Msg("failed to scan audit entry row")
+			return nil, fmt.Errorf("%w: %w", ErrScanningRows, scanErr)
+		}
+		entries = append(entries, entry)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		log.Err(rowsErr).
+			Str("func", "auditLogRepository.GetAuditLog").
+			Int64("user_id", request.UserID).
+			Msg("error occurred during rows iteration")
+		return nil, fmt.Errorf("%w: %w", ErrScanningRows, rowsErr)
+	}
+
+	return entries, nil
+}
+
+// writeAuditEntry appends a metadata-only audit row for a vault mutation
+// inside tx. It must be called in the same transaction as the mutation so
+// that a rollback discards both.
+func writeAuditEntry(ctx context.Context, tx *sql.Tx, entry models.AuditEntry) error {
+	if _, err := tx.ExecContext(ctx, insertAuditEntry,
+		entry.UserID,
+		entry.Operation,
+		entry.ClientSideID,
+		entry.Version,
+	); err != nil {
+		logger.FromContext(ctx).Err(err).
+			Str("func", "writeAuditEntry").
+			Int64("user_id", entry.UserID).
+			Str("client_side_id", entry.ClientSideID).
+			Str("operation", string(entry.Operation)).
+			Msg("failed to write audit entry")
+		return fmt.Errorf("%w: %w", ErrWritingAuditEntry, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var auditColumns = []string{"id", "user_id", "operation", "client_side_id", "version", "created_at"}
+
+func newTestAuditRepo(t *testing.T) (AuditLogRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock := newTestDB(t)
+	return NewAuditLogRepository(newDBFromSQL(db), logger.Nop()), mock
+}
+
+func TestGetAuditLog(t *testing.T) {
+	now := time.Now().UTC()
+	request := models.AuditLogRequest{UserID: 42, Limit: 2, Offset: 4}
+
+	tests := []struct {
+		name    string
+		setup   func(mock sqlmock.Sqlmock)
+		want    []models.AuditEntry
+		wantErr error
+	}{
+		{
+			name: "success: entries returned newest first",
+			setup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(auditColumns).
+					AddRow(int64(9), int64(42), "delete", "cid-1", int64(3), now).
+					AddRow(int64(7), int64(42), "create", "cid-2", int64(1), now)
+				mock.ExpectQuery(regexp.QuoteMeta(getAuditEntries)).
+					WithArgs(int64(42), 2, 4).
+					WillReturnRows(rows)
+			},
+			want: []models.AuditEntry{
+				{ID: 9, UserID: 42, Operation: models.AuditOperationDelete, ClientSideID: "cid-1", Version: 3, CreatedAt: now},
+				{ID: 7, UserID: 42, Operation: models.AuditOperationCreate, ClientSideID: "cid-2", Version: 1, CreatedAt: now},
+			},
+		},
+		{
+			name: "success: no entries",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getAuditEntries)).
+					WithArgs(int64(42), 2, 4).
+					WillReturnRows(sqlmock.NewRows(auditColumns))
+			},
+			want: []models.AuditEntry{},
+		},
+		{
+			name: "error: query fails",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getAuditEntries)).
+					WithArgs(int64(42), 2, 4).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: ErrExecutingQuery,
+		},
+		{
+			name: "error: scan fails",
+			setup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(auditColumns).
+					AddRow("not-a-number", int64(42), "create", "cid-1", int64(1), now)
+				mock.ExpectQuery(regexp.QuoteMeta(getAuditEntries)).
+					WithArgs(int64(42), 2, 4).
+					WillReturnRows(rows)
+			},
+			wantErr: ErrScanningRows,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := newTestAuditRepo(t)
+			tc.setup(mock)
+
+			got, err := repo.GetAuditLog(testContext(), request)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPrivateDataMutations_WriteAuditLog(t *testing.T) {
+	const userID = int64(42)
+
+	ctx := func() context.Context {
+		l := zerolog.Nop()
+		return context.WithValue(l.WithContext(context.Background()), utils.UserIDCtxKey, userID)
+	}
+
+	updateQuery := regexp.QuoteMeta(`UPDATE ciphers`)
+	cteColumns := []string{"updated_id", "current_db_version"}
+	id1 := int64(1)
+	ver5 := int64(5)
+
+	t.Run("successful update writes exactly one audit row", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db).(*privateDataRepository)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectQuery(updateQuery).
+			WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5)))
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationUpdate, "cid-1", int64(6)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.UpdatePrivateData(ctx(), models.UpdateRequest{
+			UserID: userID,
+			PrivateDataUpdates: []models.PrivateDataUpdate{
+				{ClientSideID: "cid-1", Version: 5, UpdatedRecordHash: "h"},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolled-back update writes no audit row", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db).(*privateDataRepository)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectQuery(updateQuery).
+			WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver5)))
+		mock.ExpectRollback()
+
+		err := repo.UpdatePrivateData(ctx(), models.UpdateRequest{
+			UserID: userID,
+			PrivateDataUpdates: []models.PrivateDataUpdate{
+				{ClientSideID: "cid-1", Version: 3, UpdatedRecordHash: "h"},
+			},
+		})
+		require.ErrorIs(t, err, ErrVersionConflict)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("audit write failure rolls back the mutation", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db).(*privateDataRepository)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectQuery(updateQuery).
+			WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5)))
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WillReturnError(errors.New("disk full"))
+		mock.ExpectRollback()
+
+		err := repo.UpdatePrivateData(ctx(), models.UpdateRequest{
+			UserID: userID,
+			PrivateDataUpdates: []models.PrivateDataUpdate{
+				{ClientSideID: "cid-1", Version: 5, UpdatedRecordHash: "h"},
+			},
+		})
+		require.ErrorIs(t, err, ErrWritingAuditEntry)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create writes audit row with the stored version", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db).(*privateDataRepository)
+		createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(savePrivateData)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(int64(1), createdAt, nil))
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationCreate, "cid-1", int64(1)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		data := &models.PrivateData{
+			UserID:       userID,
+			ClientSideID: "cid-1",
+			Payload:      models.PrivateDataPayload{Type: models.Text},
+			Version:      1,
+		}
+		err := repo.SavePrivateData(ctx(), data)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+
+		assert.Equal(t, int64(1), data.ID)
+		require.NotNil(t, data.CreatedAt)
+		assert.Equal(t, createdAt, *data.CreatedAt)
+		assert.Nil(t, data.UpdatedAt)
+	})
+
+	t.Run("delete writes audit row with the bumped version", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db).(*privateDataRepository)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta(deletePrivateDataQuery)).
+			WithArgs("cid-1", userID, int64(5)).
+			WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5)))
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationDelete, "cid-1", int64(6)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeletePrivateData(ctx(), models.DeleteRequest{
+			UserID: userID,
+			DeleteEntries: []models.DeleteEntry{
+				{ClientSideID: "cid-1", Version: 5},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
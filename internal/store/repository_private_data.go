@@ -5,9 +5,14 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
 	"github.com/MKhiriev/go-pass-keeper/models"
 )
 
@@ -21,17 +26,47 @@ import (
 type privateDataRepository struct {
 	*DB
 	logger *logger.Logger
+
+	// hardDelete switches DeletePrivateData from soft-delete to removing
+	// the row (see [config.Storage.HardDelete]).
+	hardDelete bool
+
+	// historyLimit is the number of previous versions kept per item in
+	// cipher_history; zero disables the history (see
+	// [config.Storage.VersionHistory]).
+	historyLimit int
+
+	// maxClientSideIDs caps the client-side IDs one query may filter by
+	// (see [config.Storage.ClientSideIDsLimit]).
+	maxClientSideIDs int
+
+	// syncLockWait is how long update and delete transactions wait for the
+	// per-user sync lock; negative disables the lock (see
+	// [config.Storage.SyncLockWait]).
+	syncLockWait time.Duration
 }
 
+// syncLockRetryInterval is the pause between two attempts to take the
+// per-user sync lock.
+const syncLockRetryInterval = 50 * time.Millisecond
+
 // NewPrivateDataRepository constructs a [PrivateDataRepository] backed by
-// the provided database connection and logger.
+// the provided database connection and logger. cfg.HardDelete selects the
+// delete mode, cfg.VersionHistory the number of archived versions kept per
+// item, cfg.MaxClientSideIDs the per-request client-side ID cap and
+// cfg.SyncLockTimeout the wait for the per-user sync lock.
 //
 // The logger parameter is stored for fallback logging; most methods prefer
 // the context-scoped logger obtained via [logger.FromContext].
-func NewPrivateDataRepository(db *DB, logger *logger.Logger) PrivateDataRepository {
+func NewPrivateDataRepository(db *DB, cfg config.Storage, logger *logger.Logger) PrivateDataRepository {
 	return &privateDataRepository{
-		DB:     db,
-		logger: logger,
+		DB:           db,
+		logger:       logger,
+		hardDelete:   cfg.HardDelete,
+		historyLimit: cfg.VersionHistory,
+
+		maxClientSideIDs: cfg.ClientSideIDsLimit(),
+		syncLockWait:     cfg.SyncLockWait(),
 	}
 }
 
@@ -45,7 +80,7 @@ func NewPrivateDataRepository(db *DB, logger *logger.Logger) PrivateDataReposito
 func (p *privateDataRepository) GetPrivateData(ctx context.Context, downloadRequest models.DownloadRequest) ([]models.PrivateData, error) {
 	log := logger.FromContext(ctx)
 
-	query, args, err := buildGetPrivateDataQuery(ctx, downloadRequest)
+	query, args, err := buildGetPrivateDataQuery(ctx, downloadRequest, p.maxClientSideIDs)
 	if err != nil {
 		log.Err(err).
 			Str("func", "privateDataRepository.GetPrivateData").
@@ -107,6 +142,58 @@ func (p *privateDataRepository) GetPrivateData(ctx context.Context, downloadRequ
 	return results, nil
 }
 
+// GetSinglePrivateData retrieves the vault item identified by clientSideID
+// for userID. It reuses [buildGetPrivateDataQuery] with a one-element ID
+// filter. Soft-deleted records are returned like any other with Deleted set,
+// matching [privateDataRepository.GetPrivateData].
+//
+// Returns [ErrPrivateDataNotFound] when no such record exists.
+func (p *privateDataRepository) GetSinglePrivateData(ctx context.Context, userID int64, clientSideID string) (models.PrivateData, error) {
+	log := logger.FromContext(ctx)
+
+	query, args, err := buildGetPrivateDataQuery(ctx, models.DownloadRequest{
+		UserID:        userID,
+		ClientSideIDs: []string{clientSideID},
+	}, p.maxClientSideIDs)
+	if err != nil {
+		log.Err(err).
+			Str("func", "privateDataRepository.GetSinglePrivateData").
+			Int64("user_id", userID).
+			Msg("failed to create query")
+		return models.PrivateData{}, err
+	}
+
+	var item models.PrivateData
+	scanErr := p.DB.QueryRowContext(ctx, query, args...).Scan(
+		&item.ID,
+		&item.UserID,
+		&item.Payload.Type,
+		&item.Payload.Metadata,
+		&item.Payload.Data,
+		&item.Payload.Notes,
+		&item.Payload.AdditionalFields,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+		&item.Version,
+		&item.ClientSideID,
+		&item.Hash,
+		&item.Deleted,
+	)
+	if errors.Is(scanErr, sql.ErrNoRows) {
+		return models.PrivateData{}, ErrPrivateDataNotFound
+	}
+	if scanErr != nil {
+		log.Err(scanErr).
+			Str("func", "privateDataRepository.GetSinglePrivateData").
+			Int64("user_id", userID).
+			Str("client_side_id", clientSideID).
+			Msg("failed to get private data row")
+		return models.PrivateData{}, fmt.Errorf("%w: %w", ErrScanningRow, scanErr)
+	}
+
+	return item, nil
+}
+
 // GetAllPrivateData retrieves every vault item owned by the given user,
 // including soft-deleted records.
 //
@@ -167,16 +254,26 @@ func (p *privateDataRepository) GetAllPrivateData(ctx context.Context, userID in
 }
 
 // GetAllStates returns lightweight [models.PrivateDataState] descriptors for
-// every vault item owned by the given user.
+// the vault items owned by request.UserID.
 //
 // The result contains only identity and change-detection fields
-// (ClientSideID, Hash, Version, Deleted, UpdatedAt) — no encrypted payloads.
-// This is the primary method used at the start of a sync cycle when the
-// client needs a full picture of the server-side state.
-func (p *privateDataRepository) GetAllStates(ctx context.Context, userID int64) ([]models.PrivateDataState, error) {
+// (ID, ClientSideID, Hash, Version, Deleted, UpdatedAt) — no encrypted
+// payloads. This is the primary method used at the start of a sync cycle when
+// the client needs a full picture of the server-side state.
+//
+// Pagination uses the keyset on id: rows with id greater than request.After
+// are returned in id order, at most request.Limit of them (all when zero).
+// Soft-deleted rows are left out when request.ExcludeDeleted is set.
+func (p *privateDataRepository) GetAllStates(ctx context.Context, request models.StatesPageRequest) ([]models.PrivateDataState, error) {
 	log := logger.FromContext(ctx)
+	userID := request.UserID
 
-	rows, queryErr := p.DB.QueryContext(ctx, getAllUserDataState, userID)
+	query := getAllUserDataState
+	if request.ExcludeDeleted {
+		query = getAllUserLiveDataState
+	}
+
+	rows, queryErr := p.DB.QueryContext(ctx, query, userID, request.After, request.Limit)
 	if queryErr != nil {
 		log.Err(queryErr).
 			Str("func", "privateDataRepository.GetAllStates").
@@ -192,6 +289,7 @@ func (p *privateDataRepository) GetAllStates(ctx context.Context, userID int64)
 		var data models.PrivateDataState
 
 		scanErr := rows.Scan(
+			&data.ID,
 			&data.ClientSideID,
 			&data.Hash,
 			&data.Version,
@@ -220,6 +318,45 @@ func (p *privateDataRepository) GetAllStates(ctx context.Context, userID int64)
 	return dataStates, nil
 }
 
+// GetAllClientSideIDs implements [PrivateDataRepository].
+func (p *privateDataRepository) GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error) {
+	log := logger.FromContext(ctx)
+
+	rows, queryErr := p.DB.QueryContext(ctx, getAllClientSideIDs, userID)
+	if queryErr != nil {
+		log.Err(queryErr).
+			Str("func", "privateDataRepository.GetAllClientSideIDs").
+			Int64("user_id", userID).
+			Msg("failed to execute query for getting all client-side IDs")
+		return nil, fmt.Errorf("%w: %w", ErrExecutingQuery, queryErr)
+	}
+	defer rows.Close()
+
+	ids := make([]models.ClientSideIDState, 0, 50)
+
+	for rows.Next() {
+		var id models.ClientSideIDState
+		if scanErr := rows.Scan(&id.ClientSideID, &id.Deleted); scanErr != nil {
+			log.Err(scanErr).
+				Str("func", "privateDataRepository.GetAllClientSideIDs").
+				Int64("user_id", userID).
+				Msg("failed to scan cipher row")
+			return nil, fmt.Errorf("%w: %w", ErrScanningRow, scanErr)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Err(err).
+			Str("func", "privateDataRepository.GetAllClientSideIDs").
+			Int64("user_id", userID).
+			Msg("error occurred during rows iteration")
+		return nil, fmt.Errorf("%w: %w", ErrScanningRows, err)
+	}
+
+	return ids, nil
+}
+
 // GetStates returns lightweight [models.PrivateDataState] descriptors for
 // vault items whose ClientSideIDs are listed in syncRequest.
 //
@@ -231,7 +368,7 @@ func (p *privateDataRepository) GetStates(ctx context.Context, syncRequest model
 
 	userID := syncRequest.UserID
 
-	query, args, err := buildGetStatesSyncQuery(ctx, syncRequest)
+	query, args, err := buildGetStatesSyncQuery(ctx, syncRequest, p.maxClientSideIDs)
 	if err != nil {
 		log.Err(err).
 			Str("func", "privateDataRepository.GetStates").
@@ -288,8 +425,12 @@ func (p *privateDataRepository) GetStates(ctx context.Context, syncRequest model
 //
 // Routing strategy:
 //   - Zero updates → no-op (returns nil with a warning log).
-//   - Exactly one update → delegates to [updateSingleRecord] (no transaction overhead).
-//   - Two or more updates → delegates to [updateMultipleRecords] (wrapped in a transaction).
+//   - Exactly one update → delegates to [updateSingleRecord].
+//   - Two or more updates → delegates to [updateMultipleRecords].
+//
+// Both paths run in a transaction that also appends an [models.AuditOperationUpdate]
+// row to the audit log for every updated record and, when version history is
+// enabled, archives the replaced version (see [privateDataRepository.archiveVersion]).
 //
 // Each individual update uses optimistic locking: the provided Version must
 // match the current database version, otherwise [ErrVersionConflict] is returned.
@@ -311,17 +452,21 @@ func (p *privateDataRepository) UpdatePrivateData(ctx context.Context, updateReq
 	return p.updateMultipleRecords(ctx, updateRequest.PrivateDataUpdates)
 }
 
-// DeletePrivateData performs a soft-delete of one or more vault items
-// described in deleteRequest.
+// DeletePrivateData deletes one or more vault items described in
+// deleteRequest.
 //
 // Routing strategy mirrors [UpdatePrivateData]:
 //   - Zero entries → no-op.
-//   - One entry → [deleteSingleRecord] (no transaction).
-//   - Two or more → [deleteMultipleRecords] (transaction).
+//   - One entry → [deleteSingleRecord].
+//   - Two or more → [deleteMultipleRecords].
 //
-// Soft-delete sets the "deleted" flag to true and bumps the version,
-// preserving the record so that clients can detect the deletion during sync.
-// Optimistic locking is enforced: [ErrVersionConflict] on mismatch,
+// Both paths run in a transaction that also appends an [models.AuditOperationDelete]
+// row to the audit log for every deleted record.
+//
+// By default the delete is soft: it sets the "deleted" flag to true and bumps
+// the version, preserving the record so that clients can detect the deletion
+// during sync. In hard-delete mode the row is removed; clients then detect the
+// deletion by the item's absence from the sync states. Optimistic locking is enforced: [ErrVersionConflict] on mismatch,
 // [ErrPrivateDataNotFound] if the record does not exist.
 func (p *privateDataRepository) DeletePrivateData(ctx context.Context, deleteRequest models.DeleteRequest) error {
 	log := logger.FromContext(ctx)
@@ -340,10 +485,10 @@ func (p *privateDataRepository) DeletePrivateData(ctx context.Context, deleteReq
 	return p.deleteMultipleRecords(ctx, deleteRequest)
 }
 
-// deleteSingleRecord soft-deletes a single vault item without opening
-// a database transaction.
+// deleteSingleRecord deletes a single vault item and records the
+// deletion in the audit log within one transaction.
 //
-// It executes the CTE-based delete query ([deletePrivateDataQuery]) that
+// It executes the CTE-based delete query returned by [deleteQuery] that
 // returns both the updated row ID and the current database version,
 // enabling the caller to distinguish between "not found" (both NULL)
 // and "version conflict" (updatedID NULL, currentDBVersion non-NULL).
@@ -356,17 +501,32 @@ func (p *privateDataRepository) deleteSingleRecord(ctx context.Context, deleteRe
 		Str("func", "privateDataRepository.deleteSingleRecord").
 		Str("client_side_id", entry.ClientSideID).
 		Int64("user_id", deleteRequest.UserID).
-		Msg("soft-deleting single private data record")
+		Bool("hard_delete", p.hardDelete).
+		Msg("deleting single private data record")
+
+	tx, err := p.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Err(err).
+			Str("func", "privateDataRepository.deleteSingleRecord").
+			Str("client_side_id", entry.ClientSideID).
+			Msg("failed to begin transaction")
+		return fmt.Errorf("%w: %w", ErrBeginningTransaction, err)
+	}
+	defer tx.Rollback()
+
+	if err = p.lockUserSync(ctx, tx, deleteRequest.UserID); err != nil {
+		return err
+	}
 
 	var updatedID *int64
 	var currentDBVersion *int64
 
-	queryRowErr := p.DB.QueryRowContext(ctx, deletePrivateDataQuery, entry.ClientSideID, deleteRequest.UserID, entry.Version).Scan(&updatedID, &currentDBVersion)
+	queryRowErr := tx.QueryRowContext(ctx, p.deleteQuery(), entry.ClientSideID, deleteRequest.UserID, entry.Version).Scan(&updatedID, &currentDBVersion)
 	if queryRowErr != nil {
 		log.Err(queryRowErr).
 			Str("func", "privateDataRepository.deleteSingleRecord").
 			Str("client_side_id", entry.ClientSideID).
-			Msg("failed to execute soft delete query")
+			Msg("failed to execute delete query")
 		return fmt.Errorf("%w: %w", ErrExecutingQuery, queryRowErr)
 	}
 
@@ -387,19 +547,36 @@ func (p *privateDataRepository) deleteSingleRecord(ctx context.Context, deleteRe
 			Int64("db_version", *currentDBVersion).
 			Int64("provided_version", entry.Version).
 			Msg("optimistic lock failed: version mismatch on delete")
-		return ErrVersionConflict
+		return newVersionConflict(models.ConflictOnDelete, entry.ClientSideID, *currentDBVersion)
+	}
+
+	if err = writeAuditEntry(ctx, tx, models.AuditEntry{
+		UserID:       deleteRequest.UserID,
+		Operation:    models.AuditOperationDelete,
+		ClientSideID: entry.ClientSideID,
+		Version:      entry.Version + 1,
+	}); err != nil {
+		return err
+	}
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		log.Err(commitErr).
+			Str("func", "privateDataRepository.deleteSingleRecord").
+			Str("client_side_id", entry.ClientSideID).
+			Msg("failed to commit transaction")
+		return fmt.Errorf("%w: %w", ErrCommitingTransaction, commitErr)
 	}
 
 	log.Info().
 		Str("func", "privateDataRepository.deleteSingleRecord").
 		Str("client_side_id", entry.ClientSideID).
 		Int64("deleted_id", *updatedID).
-		Msg("successfully soft-deleted single private data record")
+		Msg("successfully deleted single private data record")
 
 	return nil
 }
 
-// deleteMultipleRecords soft-deletes two or more vault items inside a single
+// deleteMultipleRecords deletes two or more vault items inside a single
 // database transaction.
 //
 // The transaction is rolled back automatically (via defer) if any individual
@@ -419,24 +596,28 @@ func (p *privateDataRepository) deleteMultipleRecords(ctx context.Context, delet
 	}
 	defer tx.Rollback()
 
+	if err = p.lockUserSync(ctx, tx, deleteRequest.UserID); err != nil {
+		return err
+	}
+
 	for idx, entry := range deleteRequest.DeleteEntries {
 		log.Debug().
 			Str("func", "privateDataRepository.DeletePrivateData").
 			Int("iteration", idx+1).
 			Int("total", len(deleteRequest.DeleteEntries)).
 			Str("client_side_id", entry.ClientSideID).
-			Msg("soft-deleting private data in transaction")
+			Msg("deleting private data in transaction")
 
 		var updatedID *int64
 		var currentDBVersion *int64
 
-		queryRowErr := tx.QueryRowContext(ctx, deletePrivateDataQuery, entry.ClientSideID, deleteRequest.UserID, entry.Version).Scan(&updatedID, &currentDBVersion)
+		queryRowErr := tx.QueryRowContext(ctx, p.deleteQuery(), entry.ClientSideID, deleteRequest.UserID, entry.Version).Scan(&updatedID, &currentDBVersion)
 		if queryRowErr != nil {
 			log.Err(queryRowErr).
 				Str("func", "privateDataRepository.DeletePrivateData").
 				Int("iteration", idx+1).
 				Str("client_side_id", entry.ClientSideID).
-				Msg("failed to execute soft delete query")
+				Msg("failed to execute delete query")
 			return fmt.Errorf("%w: %w", ErrExecutingQuery, queryRowErr)
 		}
 
@@ -459,7 +640,16 @@ func (p *privateDataRepository) deleteMultipleRecords(ctx context.Context, delet
 				Int64("db_version", *currentDBVersion).
 				Int64("provided_version", entry.Version).
 				Msg("optimistic lock failed: version mismatch on delete")
-			return fmt.Errorf("failed to delete private data at index %d: %w", idx, ErrVersionConflict)
+			return fmt.Errorf("failed to delete private data at index %d: %w", idx, newVersionConflict(models.ConflictOnDelete, entry.ClientSideID, *currentDBVersion))
+		}
+
+		if err = writeAuditEntry(ctx, tx, models.AuditEntry{
+			UserID:       deleteRequest.UserID,
+			Operation:    models.AuditOperationDelete,
+			ClientSideID: entry.ClientSideID,
+			Version:      entry.Version + 1,
+		}); err != nil {
+			return err
 		}
 
 		log.Debug().
@@ -467,7 +657,7 @@ func (p *privateDataRepository) deleteMultipleRecords(ctx context.Context, delet
 			Int("iteration", idx+1).
 			Str("client_side_id", entry.ClientSideID).
 			Int64("deleted_id", *updatedID).
-			Msg("soft-deleted record in current iteration")
+			Msg("deleted record in current iteration")
 	}
 
 	if commitErr := tx.Commit(); commitErr != nil {
@@ -482,16 +672,144 @@ func (p *privateDataRepository) deleteMultipleRecords(ctx context.Context, delet
 		Str("func", "privateDataRepository.DeletePrivateData").
 		Int64("user_id", deleteRequest.UserID).
 		Int("entries_count", len(deleteRequest.DeleteEntries)).
-		Msg("successfully soft-deleted private data")
+		Bool("hard_delete", p.hardDelete).
+		Msg("successfully deleted private data")
+
+	return nil
+}
+
+// RestorePrivateData restores the soft-deleted vault items described in
+// restoreRequest: it clears the "deleted" flag and bumps the version of each
+// of them with [restorePrivateDataQuery].
+//
+// All entries are restored in one transaction that also appends an
+// [models.AuditOperationRestore] row to the audit log for every restored
+// record; the transaction is rolled back if any entry fails. Optimistic
+// locking is enforced as in [DeletePrivateData]: [ErrVersionConflict] on
+// mismatch, [ErrPrivateDataNotFound] if the record does not exist, e.g.
+// because it was removed in hard-delete mode. Only tombstones are restored:
+// a live record yields [ErrPrivateDataNotDeleted].
+func (p *privateDataRepository) RestorePrivateData(ctx context.Context, restoreRequest models.RestoreRequest) error {
+	log := logger.FromContext(ctx)
+
+	if len(restoreRequest.RestoreEntries) == 0 {
+		log.Warn().
+			Str("func", "privateDataRepository.RestorePrivateData").
+			Msg("no restore requests provided")
+		return nil
+	}
+
+	tx, err := p.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Err(err).
+			Str("func", "privateDataRepository.RestorePrivateData").
+			Int("entries_count", len(restoreRequest.RestoreEntries)).
+			Msg("failed to begin transaction")
+		return fmt.Errorf("%w: %w", ErrBeginningTransaction, err)
+	}
+	defer tx.Rollback()
+
+	if err = p.lockUserSync(ctx, tx, restoreRequest.UserID); err != nil {
+		return err
+	}
+
+	for idx, entry := range restoreRequest.RestoreEntries {
+		log.Debug().
+			Str("func", "privateDataRepository.RestorePrivateData").
+			Int("iteration", idx+1).
+			Int("total", len(restoreRequest.RestoreEntries)).
+			Str("client_side_id", entry.ClientSideID).
+			Msg("restoring private data in transaction")
+
+		var updatedID *int64
+		var currentDBVersion *int64
+		var currentDeleted *bool
+
+		queryRowErr := tx.QueryRowContext(ctx, restorePrivateDataQuery, entry.ClientSideID, restoreRequest.UserID, entry.Version).Scan(&updatedID, &currentDBVersion, &currentDeleted)
+		if queryRowErr != nil {
+			log.Err(queryRowErr).
+				Str("func", "privateDataRepository.RestorePrivateData").
+				Int("iteration", idx+1).
+				Str("client_side_id", entry.ClientSideID).
+				Msg("failed to execute restore query")
+			return fmt.Errorf("%w: %w", ErrExecutingQuery, queryRowErr)
+		}
+
+		// not found: target_record empty -> both NULL
+		if currentDBVersion == nil {
+			log.Warn().
+				Str("func", "privateDataRepository.RestorePrivateData").
+				Int("iteration", idx+1).
+				Str("client_side_id", entry.ClientSideID).
+				Msg("record not found")
+			return ErrPrivateDataNotFound
+		}
+
+		// found but live -> nothing to restore
+		if currentDeleted != nil && !*currentDeleted {
+			log.Warn().
+				Str("func", "privateDataRepository.RestorePrivateData").
+				Int("iteration", idx+1).
+				Str("client_side_id", entry.ClientSideID).
+				Msg("record is not deleted")
+			return fmt.Errorf("failed to restore private data at index %d: %w", idx, ErrPrivateDataNotDeleted)
+		}
+
+		// found but not updated -> version mismatch
+		if updatedID == nil {
+			log.Error().
+				Str("func", "privateDataRepository.RestorePrivateData").
+				Int("iteration", idx+1).
+				Str("client_side_id", entry.ClientSideID).
+				Int64("db_version", *currentDBVersion).
+				Int64("provided_version", entry.Version).
+				Msg("optimistic lock failed: version mismatch on restore")
+			return fmt.Errorf("failed to restore private data at index %d: %w", idx, newVersionConflict(models.ConflictOnRestore, entry.ClientSideID, *currentDBVersion))
+		}
+
+		if err = writeAuditEntry(ctx, tx, models.AuditEntry{
+			UserID:       restoreRequest.UserID,
+			Operation:    models.AuditOperationRestore,
+			ClientSideID: entry.ClientSideID,
+			Version:      entry.Version + 1,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		log.Err(commitErr).
+			Str("func", "privateDataRepository.RestorePrivateData").
+			Int("entries_count", len(restoreRequest.RestoreEntries)).
+			Msg("failed to commit transaction")
+		return fmt.Errorf("%w: %w", ErrCommitingTransaction, commitErr)
+	}
+
+	log.Info().
+		Str("func", "privateDataRepository.RestorePrivateData").
+		Int64("user_id", restoreRequest.UserID).
+		Int("entries_count", len(restoreRequest.RestoreEntries)).
+		Msg("successfully restored private data")
 
 	return nil
 }
 
+// deleteQuery returns the delete statement for the configured delete mode.
+func (p *privateDataRepository) deleteQuery() string {
+	if p.hardDelete {
+		return hardDeletePrivateDataQuery
+	}
+	return deletePrivateDataQuery
+}
+
 // SavePrivateData persists one or more new vault items.
 //
 // Routing strategy:
-//   - Exactly one item → [saveSinglePrivateData] (plain INSERT, no transaction).
-//   - Two or more items → [saveMultiplePrivateData] (transaction with a prepared statement).
+//   - Exactly one item → [saveSinglePrivateData] (plain INSERT).
+//   - Two or more items → [saveMultiplePrivateData] (prepared statement).
+//
+// Both paths run in a transaction that also appends an [models.AuditOperationCreate]
+// row to the audit log for every inserted item.
 //
 // On success each [models.PrivateData.ID] is populated with the server-assigned
 // primary key returned by the INSERT … RETURNING id clause.
@@ -503,11 +821,15 @@ func (p *privateDataRepository) SavePrivateData(ctx context.Context, data ...*mo
 	return p.saveMultiplePrivateData(ctx, data)
 }
 
-// saveSinglePrivateData inserts a single vault item without opening a
-// transaction.
+// saveSinglePrivateData inserts a single vault item and records the creation
+// in the audit log within one transaction.
 //
-// The generated database ID is written back into data.ID via the
-// INSERT … RETURNING id clause.
+// A client_side_id that is already taken is rejected with
+// [ErrPrivateDataTombstoned] when its record is soft-deleted and with
+// [ErrPrivateDataAlreadyExists] otherwise; see [insertConflictError].
+//
+// The generated database ID and the stored timestamps are written back into
+// data via the INSERT … RETURNING clause.
 func (p *privateDataRepository) saveSinglePrivateData(ctx context.Context, data *models.PrivateData) error {
 	log := logger.FromContext(ctx)
 
@@ -516,7 +838,17 @@ func (p *privateDataRepository) saveSinglePrivateData(ctx context.Context, data
 		Int64("user_id", data.UserID).
 		Msg("saving single private data record")
 
-	err := p.DB.QueryRowContext(ctx, savePrivateData,
+	tx, err := p.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Err(err).
+			Str("func", "privateDataRepository.saveSinglePrivateData").
+			Str("client_side_id", data.ClientSideID).
+			Msg("failed to begin transaction")
+		return fmt.Errorf("%w: %w", ErrBeginningTransaction, err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, savePrivateData,
 		data.ClientSideID,
 		data.UserID,
 		data.Payload.Metadata,
@@ -527,7 +859,17 @@ func (p *privateDataRepository) saveSinglePrivateData(ctx context.Context, data
 		data.Version,
 		data.Hash,
 		data.CreatedAt,
-	).Scan(&data.ID)
+	).Scan(&data.ID, &data.CreatedAt, &data.UpdatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		conflictErr := insertConflictError(ctx, tx, data)
+		log.Warn().Err(conflictErr).
+			Str("func", "privateDataRepository.saveSinglePrivateData").
+			Str("client_side_id", data.ClientSideID).
+			Int64("user_id", data.UserID).
+			Msg("client_side_id is already taken")
+		return conflictErr
+	}
 
 	if err != nil {
 		log.Err(err).
@@ -539,6 +881,23 @@ func (p *privateDataRepository) saveSinglePrivateData(ctx context.Context, data
 		return fmt.Errorf("%w: %w", ErrExecutingQuery, err)
 	}
 
+	if err = writeAuditEntry(ctx, tx, models.AuditEntry{
+		UserID:       data.UserID,
+		Operation:    models.AuditOperationCreate,
+		ClientSideID: data.ClientSideID,
+		Version:      data.Version,
+	}); err != nil {
+		return err
+	}
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		log.Err(commitErr).
+			Str("func", "privateDataRepository.saveSinglePrivateData").
+			Str("client_side_id", data.ClientSideID).
+			Msg("failed to commit transaction")
+		return fmt.Errorf("%w: %w", ErrCommitingTransaction, commitErr)
+	}
+
 	return nil
 }
 
@@ -546,8 +905,8 @@ func (p *privateDataRepository) saveSinglePrivateData(ctx context.Context, data
 // database transaction using a prepared statement for efficiency.
 //
 // The prepared statement is created once from [savePrivateData] and reused
-// for every item. Each generated database ID is written back into the
-// corresponding [models.PrivateData.ID] field.
+// for every item. Each generated database ID and the stored timestamps are
+// written back into the corresponding [models.PrivateData].
 //
 // The transaction is rolled back automatically (via defer) if any individual
 // insert fails; the commit is attempted only after all items succeed.
@@ -594,15 +953,34 @@ func (p *privateDataRepository) saveMultiplePrivateData(ctx context.Context, dat
 			singleData.Version,
 			singleData.Hash,
 			singleData.CreatedAt,
-		).Scan(&singleData.ID)
+		).Scan(&singleData.ID, &singleData.CreatedAt, &singleData.UpdatedAt)
+
+		if errors.Is(queryErr, sql.ErrNoRows) {
+			conflictErr := insertConflictError(ctx, tx, singleData)
+			log.Warn().Err(conflictErr).
+				Str("func", "privateDataRepository.saveMultiplePrivateData").
+				Int("iteration", idx+1).
+				Str("client_side_id", singleData.ClientSideID).
+				Msg("client_side_id is already taken")
+			return fmt.Errorf("failed to save private data at index %d: %w", idx, conflictErr)
+		}
 
 		if queryErr != nil {
-			log.Err(err).
+			log.Err(queryErr).
 				Str("func", "privateDataRepository.saveMultiplePrivateData").
 				Int("iteration", idx+1).
 				Str("client_side_id", singleData.ClientSideID).
 				Msg("failed to execute prepared statement")
-			return fmt.Errorf("%w: %w", ErrExecutingStatement, err)
+			return fmt.Errorf("%w: %w", ErrExecutingStatement, queryErr)
+		}
+
+		if err = writeAuditEntry(ctx, tx, models.AuditEntry{
+			UserID:       singleData.UserID,
+			Operation:    models.AuditOperationCreate,
+			ClientSideID: singleData.ClientSideID,
+			Version:      singleData.Version,
+		}); err != nil {
+			return err
 		}
 	}
 
@@ -617,13 +995,15 @@ func (p *privateDataRepository) saveMultiplePrivateData(ctx context.Context, dat
 	return nil
 }
 
-// updateSingleRecord applies a partial update to a single vault item
-// without opening a database transaction.
+// updateSingleRecord applies a partial update to a single vault item and
+// records it in the audit log within one transaction.
 //
 // The method builds a dynamic UPDATE query via [buildUpdateQuery], executes
 // it, and inspects the CTE result to determine the outcome:
 //   - Both updatedID and currentDBVersion are non-NULL → success.
 //   - currentDBVersion is NULL → record not found ([ErrPrivateDataNotFound]).
+//   - updatedID is NULL and currentDBVersion equals update.Version → the record
+//     is soft-deleted ([ErrPrivateDataTombstoned]).
 //   - updatedID is NULL but currentDBVersion is non-NULL → version mismatch ([ErrVersionConflict]).
 //
 // If the built query contains only the two mandatory positional args
@@ -649,10 +1029,29 @@ func (p *privateDataRepository) updateSingleRecord(ctx context.Context, update m
 		return nil
 	}
 
+	tx, err := p.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Err(err).
+			Str("func", "privateDataRepository.updateSingleRecord").
+			Str("id", update.ClientSideID).
+			Msg("failed to begin transaction")
+		return fmt.Errorf("%w: %w", ErrBeginningTransaction, err)
+	}
+	defer tx.Rollback()
+
+	userID, _ := utils.GetUserIDFromContext(ctx)
+	if err = p.lockUserSync(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	if err = p.archiveVersion(ctx, tx, update); err != nil {
+		return err
+	}
+
 	var updatedID *int64
 	var currentDBVersion *int64
 
-	queryRowErr := p.DB.QueryRowContext(ctx, query, args...).Scan(&updatedID, &currentDBVersion)
+	queryRowErr := tx.QueryRowContext(ctx, query, args...).Scan(&updatedID, &currentDBVersion)
 	if queryRowErr != nil {
 		log.Err(queryRowErr).
 			Str("func", "privateDataRepository.updateSingleRecord").
@@ -670,6 +1069,15 @@ func (p *privateDataRepository) updateSingleRecord(ctx context.Context, update m
 		return ErrPrivateDataNotFound
 	}
 
+	// version matches, but UPDATE didn't work - the record is a tombstone
+	if updatedID == nil && *currentDBVersion == update.Version {
+		log.Warn().
+			Str("func", "privateDataRepository.updateSingleRecord").
+			Str("id", update.ClientSideID).
+			Msg("update of a soft-deleted record rejected")
+		return fmt.Errorf("failed to update private data: %w", ErrPrivateDataTombstoned)
+	}
+
 	// cipher record found, but UPDATE didn't work - version mismatch
 	if updatedID == nil {
 		log.Warn().
@@ -678,7 +1086,19 @@ func (p *privateDataRepository) updateSingleRecord(ctx context.Context, update m
 			Int64("db_version", *currentDBVersion).
 			Int64("provided_version", update.Version).
 			Msg("optimistic lock failed: version mismatch")
-		return fmt.Errorf("failed to update private data: %w", ErrVersionConflict)
+		return fmt.Errorf("failed to update private data: %w", newVersionConflict(models.ConflictOnUpdate, update.ClientSideID, *currentDBVersion))
+	}
+
+	if err = writeAuditEntry(ctx, tx, updateAuditEntry(ctx, update)); err != nil {
+		return err
+	}
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		log.Err(commitErr).
+			Str("func", "privateDataRepository.updateSingleRecord").
+			Str("id", update.ClientSideID).
+			Msg("failed to commit transaction")
+		return fmt.Errorf("%w: %w", ErrCommitingTransaction, commitErr)
 	}
 
 	log.Info().
@@ -711,6 +1131,11 @@ func (p *privateDataRepository) updateMultipleRecords(ctx context.Context, updat
 	}
 	defer tx.Rollback()
 
+	userID, _ := utils.GetUserIDFromContext(ctx)
+	if err = p.lockUserSync(ctx, tx, userID); err != nil {
+		return err
+	}
+
 	for idx, update := range updates {
 		query, args, buildErr := buildUpdateQuery(ctx, update)
 		if buildErr != nil {
@@ -729,6 +1154,10 @@ func (p *privateDataRepository) updateMultipleRecords(ctx context.Context, updat
 			Str("id", update.ClientSideID).
 			Msg("updating private data in transaction")
 
+		if err = p.archiveVersion(ctx, tx, update); err != nil {
+			return err
+		}
+
 		var updatedID *int64
 		var currentDBVersion *int64
 
@@ -752,6 +1181,16 @@ func (p *privateDataRepository) updateMultipleRecords(ctx context.Context, updat
 			return ErrPrivateDataNotFound
 		}
 
+		// version matches, but UPDATE didn't work - the record is a tombstone
+		if updatedID == nil && *currentDBVersion == update.Version {
+			log.Warn().
+				Str("func", "privateDataRepository.updateMultipleRecords").
+				Int("iteration", idx+1).
+				Str("id", update.ClientSideID).
+				Msg("update of a soft-deleted record rejected")
+			return fmt.Errorf("failed to update private data at index %d: %w", idx, ErrPrivateDataTombstoned)
+		}
+
 		// cipher record found, but UPDATE didn't work - version mismatch
 		if updatedID == nil {
 			log.Error().
@@ -761,7 +1200,11 @@ func (p *privateDataRepository) updateMultipleRecords(ctx context.Context, updat
 				Int64("db_version", *currentDBVersion).
 				Int64("provided_version", update.Version).
 				Msg("optimistic lock failed: version mismatch")
-			return fmt.Errorf("failed to update private data at index %d: %w", idx, ErrVersionConflict)
+			return fmt.Errorf("failed to update private data at index %d: %w", idx, newVersionConflict(models.ConflictOnUpdate, update.ClientSideID, *currentDBVersion))
+		}
+
+		if err = writeAuditEntry(ctx, tx, updateAuditEntry(ctx, update)); err != nil {
+			return err
 		}
 
 		log.Debug().
@@ -786,3 +1229,79 @@ func (p *privateDataRepository) updateMultipleRecords(ctx context.Context, updat
 
 	return nil
 }
+
+// updateAuditEntry builds the audit row for a successful update. The owner is
+// taken from ctx, matching [buildUpdateQuery]; the optimistic-lock update bumps
+// the version by exactly one.
+func updateAuditEntry(ctx context.Context, update models.PrivateDataUpdate) models.AuditEntry {
+	userID, _ := utils.GetUserIDFromContext(ctx)
+	return models.AuditEntry{
+		UserID:       userID,
+		Operation:    models.AuditOperationUpdate,
+		ClientSideID: update.ClientSideID,
+		Version:      update.Version + 1,
+	}
+}
+
+// insertConflictError explains why an INSERT … ON CONFLICT DO NOTHING stored
+// no row for data. A soft-deleted record keeps its client_side_id reserved
+// until it is purged, so reuse yields [ErrPrivateDataTombstoned]; a live record
+// yields [ErrPrivateDataAlreadyExists].
+func insertConflictError(ctx context.Context, tx *sql.Tx, data *models.PrivateData) error {
+	var deleted bool
+	if err := tx.QueryRowContext(ctx, getPrivateDataDeletedFlag, data.UserID, data.ClientSideID).Scan(&deleted); err != nil {
+		return fmt.Errorf("%w: %w", ErrExecutingQuery, err)
+	}
+	if deleted {
+		return ErrPrivateDataTombstoned
+	}
+	return ErrPrivateDataAlreadyExists
+}
+
+// lockUserSync takes the per-user sync lock inside tx so that concurrent
+// batch updates and deletes of the same user, e.g. from two devices syncing
+// at once, are applied one after another instead of interleaving. The lock
+// is a transaction-scoped PostgreSQL advisory lock released on commit or
+// rollback.
+//
+// While another transaction holds the lock, the attempt is repeated every
+// [syncLockRetryInterval] until [privateDataRepository.syncLockWait] has
+// passed; then [ErrSyncLocked] is returned. A negative wait disables the
+// lock.
+func (p *privateDataRepository) lockUserSync(ctx context.Context, tx *sql.Tx, userID int64) error {
+	if p.syncLockWait < 0 {
+		return nil
+	}
+
+	log := logger.FromContext(ctx)
+	deadline := time.Now().Add(p.syncLockWait)
+
+	for attempt := 1; ; attempt++ {
+		var acquired bool
+		if err := tx.QueryRowContext(ctx, tryLockUserSync, userID).Scan(&acquired); err != nil {
+			log.Err(err).
+				Str("func", "privateDataRepository.lockUserSync").
+				Int64("user_id", userID).
+				Msg("failed to request sync lock")
+			return fmt.Errorf("%w: %w", ErrExecutingQuery, err)
+		}
+		if acquired {
+			return nil
+		}
+
+		if time.Until(deadline) < syncLockRetryInterval {
+			log.Warn().
+				Str("func", "privateDataRepository.lockUserSync").
+				Int64("user_id", userID).
+				Int("attempts", attempt).
+				Msg("sync lock is held by a concurrent sync")
+			return ErrSyncLocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(syncLockRetryInterval):
+		}
+	}
+}
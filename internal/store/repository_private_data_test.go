@@ -14,6 +14,7 @@ import (
 	"time"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/utils"
 	"github.com/MKhiriev/go-pass-keeper/models"
@@ -45,7 +46,15 @@ func newTestRepo(t *testing.T, db *sql.DB) PrivateDataRepository {
 	t.Helper()
 	storeDB := newDBFromSQL(db)
 	log := logger.Nop()
-	return NewPrivateDataRepository(storeDB, log)
+	return NewPrivateDataRepository(storeDB, config.Storage{}, log)
+}
+
+// expectSyncLock expects the per-user sync lock to be requested and granted
+// right after a mutating transaction begins.
+func expectSyncLock(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta(tryLockUserSync)).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(true))
 }
 
 func testContext() context.Context {
@@ -349,6 +358,85 @@ func TestGetPrivateData(t *testing.T) {
 	}
 }
 
+func TestGetSinglePrivateData(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	query := regexp.QuoteMeta(selectPrivateDataSQL + ` WHERE user_id = $1 AND client_side_id IN ($2)`)
+
+	tests := []struct {
+		name    string
+		row     *privateDataRow
+		dbErr   error
+		want    models.PrivateData
+		wantErr error
+	}{
+		{
+			name: "found",
+			row: &privateDataRow{
+				id: 1, userID: 42,
+				dataType: models.LoginPassword, metadata: "enc_meta", data: "enc_data",
+				createdAt: &now, updatedAt: &now,
+				version: 3, clientSideID: "cid-1", hash: "hash1",
+			},
+			want: models.PrivateData{
+				ID: 1, UserID: 42,
+				Payload:   models.PrivateDataPayload{Type: models.LoginPassword, Metadata: "enc_meta", Data: "enc_data"},
+				CreatedAt: &now, UpdatedAt: &now,
+				Version: 3, ClientSideID: "cid-1", Hash: "hash1",
+			},
+		},
+		{
+			name: "soft-deleted record is returned as a tombstone",
+			row: &privateDataRow{
+				id: 1, userID: 42,
+				dataType: models.Text, metadata: "enc_meta", data: "enc_data",
+				createdAt: &now, updatedAt: &now,
+				version: 4, clientSideID: "cid-1", hash: "hash1", deleted: true,
+			},
+			want: models.PrivateData{
+				ID: 1, UserID: 42,
+				Payload:   models.PrivateDataPayload{Type: models.Text, Metadata: "enc_meta", Data: "enc_data"},
+				CreatedAt: &now, UpdatedAt: &now,
+				Version: 4, ClientSideID: "cid-1", Hash: "hash1", Deleted: true,
+			},
+		},
+		{
+			name:    "not found",
+			wantErr: ErrPrivateDataNotFound,
+		},
+		{
+			name:    "query error",
+			dbErr:   errors.New("connection refused"),
+			wantErr: ErrScanningRow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			repo := newTestRepo(t, db)
+
+			expect := mock.ExpectQuery(query).WithArgs(int64(42), "cid-1")
+			switch {
+			case tt.dbErr != nil:
+				expect.WillReturnError(tt.dbErr)
+			case tt.row != nil:
+				expect.WillReturnRows(sqlmock.NewRows(privateDataColumns).AddRow(tt.row.toArgs()...))
+			default:
+				expect.WillReturnRows(sqlmock.NewRows(privateDataColumns))
+			}
+
+			got, err := repo.GetSinglePrivateData(testContext(), 42, "cid-1")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestGetAllPrivateData(t *testing.T) {
 	now := time.Now().Truncate(time.Millisecond)
 	notes := models.CipheredNotes("enc_notes")
@@ -615,11 +703,13 @@ func TestGetAllPrivateData(t *testing.T) {
 func TestGetAllStates(t *testing.T) {
 	now := time.Now().Truncate(time.Millisecond)
 
-	const query = `SELECT client_side_id, hash, version, deleted, updated_at FROM ciphers WHERE user_id = $1;`
+	const query = `SELECT id, client_side_id, hash, version, deleted, updated_at FROM ciphers WHERE user_id = $1 AND id > $2 ORDER BY id LIMIT NULLIF($3, 0);`
+	const liveQuery = `SELECT id, client_side_id, hash, version, deleted, updated_at FROM ciphers WHERE user_id = $1 AND id > $2 AND deleted = FALSE ORDER BY id LIMIT NULLIF($3, 0);`
 
-	var stateColumns = []string{"client_side_id", "hash", "version", "deleted", "updated_at"}
+	var stateColumns = []string{"id", "client_side_id", "hash", "version", "deleted", "updated_at"}
 
 	type stateRow struct {
+		id           int64
 		clientSideID string
 		hash         string
 		version      int64
@@ -628,7 +718,7 @@ func TestGetAllStates(t *testing.T) {
 	}
 
 	toArgs := func(r stateRow) []driver.Value {
-		return []driver.Value{r.clientSideID, r.hash, r.version, r.deleted, r.updatedAt}
+		return []driver.Value{r.id, r.clientSideID, r.hash, r.version, r.deleted, r.updatedAt}
 	}
 
 	type mockSetup struct {
@@ -645,33 +735,50 @@ func TestGetAllStates(t *testing.T) {
 	}
 
 	tests := []struct {
-		name   string
-		userID int64
-		mock   mockSetup
-		want   want
+		name    string
+		request models.StatesPageRequest
+		mock    mockSetup
+		want    want
 	}{
 		{
-			name:   "success: multiple records",
-			userID: 42,
+			name:    "success: multiple records",
+			request: models.StatesPageRequest{UserID: 42},
 			mock: mockSetup{
 				rows: []stateRow{
-					{clientSideID: "cid-1", hash: "hash1", version: 1, deleted: false, updatedAt: &now},
-					{clientSideID: "cid-2", hash: "hash2", version: 3, deleted: false, updatedAt: &now},
-					{clientSideID: "cid-3", hash: "hash3", version: 7, deleted: true, updatedAt: &now},
+					{id: 1, clientSideID: "cid-1", hash: "hash1", version: 1, deleted: false, updatedAt: &now},
+					{id: 2, clientSideID: "cid-2", hash: "hash2", version: 3, deleted: false, updatedAt: &now},
+					{id: 5, clientSideID: "cid-3", hash: "hash3", version: 7, deleted: true, updatedAt: &now},
 				},
 			},
 			want: want{
 				resultLen: 3,
 				items: []models.PrivateDataState{
-					{ClientSideID: "cid-1", Hash: "hash1", Version: 1, Deleted: false, UpdatedAt: &now},
-					{ClientSideID: "cid-2", Hash: "hash2", Version: 3, Deleted: false, UpdatedAt: &now},
-					{ClientSideID: "cid-3", Hash: "hash3", Version: 7, Deleted: true, UpdatedAt: &now},
+					{ID: 1, ClientSideID: "cid-1", Hash: "hash1", Version: 1, Deleted: false, UpdatedAt: &now},
+					{ID: 2, ClientSideID: "cid-2", Hash: "hash2", Version: 3, Deleted: false, UpdatedAt: &now},
+					{ID: 5, ClientSideID: "cid-3", Hash: "hash3", Version: 7, Deleted: true, UpdatedAt: &now},
 				},
 			},
 		},
 		{
-			name:   "success: deleted record included",
-			userID: 42,
+			name:    "success: keyset page after cursor",
+			request: models.StatesPageRequest{UserID: 42, After: 5, Limit: 2},
+			mock: mockSetup{
+				rows: []stateRow{
+					{id: 8, clientSideID: "cid-8", hash: "hash8", version: 1, updatedAt: &now},
+					{id: 13, clientSideID: "cid-13", hash: "hash13", version: 2, updatedAt: &now},
+				},
+			},
+			want: want{
+				resultLen: 2,
+				items: []models.PrivateDataState{
+					{ID: 8, ClientSideID: "cid-8", Hash: "hash8", Version: 1, UpdatedAt: &now},
+					{ID: 13, ClientSideID: "cid-13", Hash: "hash13", Version: 2, UpdatedAt: &now},
+				},
+			},
+		},
+		{
+			name:    "success: deleted record included",
+			request: models.StatesPageRequest{UserID: 42},
 			mock: mockSetup{
 				rows: []stateRow{
 					{clientSideID: "cid-del", hash: "hash-del", version: 5, deleted: true, updatedAt: &now},
@@ -685,8 +792,48 @@ func TestGetAllStates(t *testing.T) {
 			},
 		},
 		{
-			name:   "success: updatedAt = NULL",
-			userID: 42,
+			name:    "success: deleted records excluded",
+			request: models.StatesPageRequest{UserID: 42},
+			mock: mockSetup{
+				rows: []stateRow{
+					{id: 1, clientSideID: "cid-1", hash: "hash1", version: 1, updatedAt: &now},
+					{id: 7, clientSideID: "cid-7", hash: "hash7", version: 2, updatedAt: &now},
+				},
+			},
+			want: want{
+				resultLen: 2,
+				items: []models.PrivateDataState{
+					{ID: 1, ClientSideID: "cid-1", Hash: "hash1", Version: 1, UpdatedAt: &now},
+					{ID: 7, ClientSideID: "cid-7", Hash: "hash7", Version: 2, UpdatedAt: &now},
+				},
+			},
+		},
+		{
+			name:    "success: keyset page of live records",
+			request: models.StatesPageRequest{UserID: 42, After: 7, Limit: 1, ExcludeDeleted: true},
+			mock: mockSetup{
+				rows: []stateRow{
+					{id: 9, clientSideID: "cid-9", hash: "hash9", version: 4, updatedAt: &now},
+				},
+			},
+			want: want{
+				resultLen: 1,
+				items: []models.PrivateDataState{
+					{ID: 9, ClientSideID: "cid-9", Hash: "hash9", Version: 4, UpdatedAt: &now},
+				},
+			},
+		},
+		{
+			name:    "error: query without deleted records fails",
+			request: models.StatesPageRequest{UserID: 42, ExcludeDeleted: true},
+			mock: mockSetup{
+				queryErr: errors.New("connection refused"),
+			},
+			want: want{err: "error executing sql query"},
+		},
+		{
+			name:    "success: updatedAt = NULL",
+			request: models.StatesPageRequest{UserID: 42},
 			mock: mockSetup{
 				rows: []stateRow{
 					{clientSideID: "cid-null", hash: "hash-null", version: 2, deleted: false, updatedAt: nil},
@@ -700,22 +847,22 @@ func TestGetAllStates(t *testing.T) {
 			},
 		},
 		{
-			name:   "success: empty result",
-			userID: 99,
-			mock:   mockSetup{rows: []stateRow{}},
-			want:   want{resultLen: 0},
+			name:    "success: empty result",
+			request: models.StatesPageRequest{UserID: 99},
+			mock:    mockSetup{rows: []stateRow{}},
+			want:    want{resultLen: 0},
 		},
 		{
-			name:   "error: query execution fails",
-			userID: 42,
+			name:    "error: query execution fails",
+			request: models.StatesPageRequest{UserID: 42},
 			mock: mockSetup{
 				queryErr: errors.New("connection refused"),
 			},
 			want: want{err: "error executing sql query"},
 		},
 		{
-			name:   "error: scan fails (wrong column count)",
-			userID: 42,
+			name:    "error: scan fails (wrong column count)",
+			request: models.StatesPageRequest{UserID: 42},
 			mock: mockSetup{
 				badCols: []string{"client_side_id"},
 				rows:    []stateRow{{clientSideID: "cid-1"}},
@@ -723,8 +870,8 @@ func TestGetAllStates(t *testing.T) {
 			want: want{err: "failed to scan private data row"},
 		},
 		{
-			name:   "error: rows iteration error",
-			userID: 42,
+			name:    "error: rows iteration error",
+			request: models.StatesPageRequest{UserID: 42},
 			mock: mockSetup{
 				rows: []stateRow{
 					{clientSideID: "cid-1", hash: "hash1", version: 1, deleted: false, updatedAt: &now},
@@ -741,8 +888,12 @@ func TestGetAllStates(t *testing.T) {
 			repo := newTestRepo(t, db)
 			ctx := testContext()
 
-			expectation := mock.ExpectQuery(regexp.QuoteMeta(query)).
-				WithArgs(driver.Value(tc.userID))
+			wantQuery := query
+			if tc.request.ExcludeDeleted {
+				wantQuery = liveQuery
+			}
+			expectation := mock.ExpectQuery(regexp.QuoteMeta(wantQuery)).
+				WithArgs(tc.request.UserID, tc.request.After, tc.request.Limit)
 
 			if tc.mock.queryErr != nil {
 				expectation.WillReturnError(tc.mock.queryErr)
@@ -766,7 +917,7 @@ func TestGetAllStates(t *testing.T) {
 				expectation.WillReturnRows(mockRows)
 			}
 
-			result, err := repo.GetAllStates(ctx, tc.userID)
+			result, err := repo.GetAllStates(ctx, tc.request)
 
 			if tc.want.err != "" {
 				require.Error(t, err)
@@ -782,6 +933,7 @@ func TestGetAllStates(t *testing.T) {
 			for i, expected := range tc.want.items {
 				got := result[i]
 
+				assert.Equal(t, expected.ID, got.ID, "ID[%d]", i)
 				assert.Equal(t, expected.ClientSideID, got.ClientSideID, "ClientSideID[%d]", i)
 				assert.Equal(t, expected.Hash, got.Hash, "Hash[%d]", i)
 				assert.Equal(t, expected.Version, got.Version, "Version[%d]", i)
@@ -800,6 +952,64 @@ func TestGetAllStates(t *testing.T) {
 	}
 }
 
+func TestGetAllClientSideIDs(t *testing.T) {
+	const query = `SELECT client_side_id, deleted FROM ciphers WHERE user_id = $1 ORDER BY id;`
+	columns := []string{"client_side_id", "deleted"}
+
+	tests := []struct {
+		name     string
+		rows     [][]driver.Value
+		queryErr error
+		rowErr   error
+		want     []models.ClientSideIDState
+		wantErr  string
+	}{
+		{
+			name: "active and tombstoned ids",
+			rows: [][]driver.Value{{"cid-1", false}, {"cid-gone", true}, {"cid-2", false}},
+			want: []models.ClientSideIDState{
+				{ClientSideID: "cid-1"},
+				{ClientSideID: "cid-gone", Deleted: true},
+				{ClientSideID: "cid-2"},
+			},
+		},
+		{name: "no items", want: []models.ClientSideIDState{}},
+		{name: "query fails", queryErr: errors.New("connection refused"), wantErr: "error executing sql query"},
+		{name: "rows iteration fails", rows: [][]driver.Value{{"cid-1", false}}, rowErr: errors.New("network interruption"), wantErr: "failed to scan private data rows"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			repo := newTestRepo(t, db)
+
+			expectation := mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(42))
+			if tc.queryErr != nil {
+				expectation.WillReturnError(tc.queryErr)
+			} else {
+				rows := sqlmock.NewRows(columns)
+				for i, r := range tc.rows {
+					rows.AddRow(r...)
+					if tc.rowErr != nil {
+						rows.RowError(i, tc.rowErr)
+					}
+				}
+				expectation.WillReturnRows(rows)
+			}
+
+			got, err := repo.GetAllClientSideIDs(testContext(), 42)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestGetStates(t *testing.T) {
 	now := time.Now().Truncate(time.Millisecond)
 
@@ -1077,6 +1287,7 @@ func TestUpdateSingleRecord(t *testing.T) {
           WHERE client_side_id = $1
             AND user_id = $2
             AND version = %s
+            AND deleted = FALSE
           RETURNING id
        )
        SELECT
@@ -1116,9 +1327,10 @@ func TestUpdateSingleRecord(t *testing.T) {
 	}
 
 	type want struct {
-		err     error  // for errors.Is checks
-		errWrap string // for string Contains checks
-		noErr   bool
+		err      error  // for errors.Is checks
+		errWrap  string // for string Contains checks
+		conflict *models.VersionConflict
+		noErr    bool
 	}
 
 	tests := []struct {
@@ -1226,7 +1438,11 @@ func TestUpdateSingleRecord(t *testing.T) {
 				updatedID:        nil,
 				currentDBVersion: &ver5,
 			},
-			want: want{errWrap: ErrVersionConflict.Error()},
+			want: want{errWrap: ErrVersionConflict.Error(), conflict: &models.VersionConflict{
+				Operation:     models.ConflictOnUpdate,
+				ClientSideID:  "cid-1",
+				ServerVersion: 5,
+			}},
 		},
 		{
 			name: "error: query execution fails",
@@ -1250,6 +1466,8 @@ func TestUpdateSingleRecord(t *testing.T) {
 			repo := newTestRepo(t, db).(*privateDataRepository)
 			ctx := testContextWithUser(userID)
 
+			mock.ExpectBegin()
+			expectSyncLock(mock)
 			if tc.mock.queryErr != nil {
 				mock.ExpectQuery(regexp.QuoteMeta(tc.mock.query)).
 					WithArgs(tc.mock.args...).
@@ -1264,6 +1482,14 @@ func TestUpdateSingleRecord(t *testing.T) {
 					WithArgs(tc.mock.args...).
 					WillReturnRows(rows)
 			}
+			if tc.want.noErr {
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+					WithArgs(userID, models.AuditOperationUpdate, tc.update.ClientSideID, tc.update.Version+1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
 
 			err := repo.updateSingleRecord(ctx, tc.update)
 
@@ -1276,6 +1502,11 @@ func TestUpdateSingleRecord(t *testing.T) {
 			default:
 				require.NoError(t, err)
 			}
+			if tc.want.conflict != nil {
+				var conflict *VersionConflictError
+				require.ErrorAs(t, err, &conflict)
+				assert.Equal(t, *tc.want.conflict, conflict.VersionConflict)
+			}
 
 			require.NoError(t, mock.ExpectationsWereMet())
 		})
@@ -1300,6 +1531,7 @@ func TestUpdateSingleRecord_WithExistingRecord(t *testing.T) {
 		return fmt.Sprintf(`
        WITH target_record AS (          SELECT id, version          FROM ciphers          WHERE client_side_id = $1 AND user_id = $2       ),       updated_record AS (          UPDATE ciphers          SET %s
           WHERE client_side_id = $1            AND user_id = $2            AND version = %s
+            AND deleted = FALSE
           RETURNING id       )       SELECT          (SELECT id FROM updated_record)      AS updated_id,          (SELECT version FROM target_record)   AS current_db_version`,
 			setClauses, versionPlaceholder)
 	}
@@ -1346,9 +1578,15 @@ func TestUpdateSingleRecord_WithExistingRecord(t *testing.T) {
 				driver.Value(&updatedID), // updated_id != nil -> UPDATE succeeded
 				driver.Value(&dbVersion), // current_db_version = 5
 			)
+		mock.ExpectBegin()
+		expectSyncLock(mock)
 		mock.ExpectQuery(regexp.QuoteMeta(query)).
 			WithArgs("cid-existing", userID, "new_meta", "new-hash", int64(5)).
 			WillReturnRows(updateRows)
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationUpdate, "cid-existing", int64(6)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		err = repo.updateSingleRecord(ctx, models.PrivateDataUpdate{
 			ClientSideID:      "cid-existing",
@@ -1420,9 +1658,12 @@ func TestUpdateSingleRecord_WithExistingRecord(t *testing.T) {
 				driver.Value((*int64)(nil)), // updated_id = NULL -> UPDATE did not affect row
 				driver.Value(&dbVersion),    // current_db_version = 5 -> record exists
 			)
+		mock.ExpectBegin()
+		expectSyncLock(mock)
 		mock.ExpectQuery(regexp.QuoteMeta(query)).
 			WithArgs("cid-existing", userID, "new_meta", "new-hash", int64(3)).
 			WillReturnRows(updateRows)
+		mock.ExpectRollback()
 
 		err = repo.updateSingleRecord(ctx, models.PrivateDataUpdate{
 			ClientSideID:      "cid-existing",
@@ -1475,6 +1716,7 @@ func TestUpdateMultipleRecords(t *testing.T) {
 		return fmt.Sprintf(`
        WITH target_record AS (          SELECT id, version          FROM ciphers          WHERE client_side_id = $1 AND user_id = $2       ),       updated_record AS (          UPDATE ciphers          SET %s
           WHERE client_side_id = $1            AND user_id = $2            AND version = %s
+            AND deleted = FALSE
           RETURNING id       )       SELECT          (SELECT id FROM updated_record)      AS updated_id,          (SELECT version FROM target_record)   AS current_db_version`,
 			setClauses, versionPlaceholder)
 	}
@@ -1527,18 +1769,25 @@ func TestUpdateMultipleRecords(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
+		expectSyncLock(mock)
 
 		rows1 := sqlmock.NewRows(cteColumns).
 			AddRow(driver.Value(&id1), driver.Value(&ver5))
 		mock.ExpectQuery(regexp.QuoteMeta(metaHashQuery)).
 			WithArgs("cid-1", userID, "meta-1-new", "hash-1-new", int64(5)).
 			WillReturnRows(rows1)
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationUpdate, "cid-1", int64(6)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		rows2 := sqlmock.NewRows(cteColumns).
 			AddRow(driver.Value(&id2), driver.Value(&ver3))
 		mock.ExpectQuery(regexp.QuoteMeta(metaDataHashQuery)).
 			WithArgs("cid-2", userID, "meta-2-new", "data-2-new", "hash-2-new", int64(3)).
 			WillReturnRows(rows2)
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationUpdate, "cid-2", int64(4)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		mock.ExpectCommit()
 
@@ -1571,21 +1820,31 @@ func TestUpdateMultipleRecords(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
+		expectSyncLock(mock)
 
 		r1 := sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5))
 		mock.ExpectQuery(regexp.QuoteMeta(hashOnlyQuery)).
 			WithArgs("cid-1", userID, "h1", int64(5)).
 			WillReturnRows(r1)
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationUpdate, "cid-1", int64(6)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		r2 := sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id2), driver.Value(&ver3))
 		mock.ExpectQuery(regexp.QuoteMeta(hashOnlyQuery)).
 			WithArgs("cid-2", userID, "h2", int64(3)).
 			WillReturnRows(r2)
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationUpdate, "cid-2", int64(4)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		r3 := sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id3), driver.Value(&ver7))
 		mock.ExpectQuery(regexp.QuoteMeta(hashOnlyQuery)).
 			WithArgs("cid-3", userID, "h3", int64(7)).
 			WillReturnRows(r3)
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationUpdate, "cid-3", int64(8)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		mock.ExpectCommit()
 
@@ -1628,12 +1887,16 @@ func TestUpdateMultipleRecords(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
+		expectSyncLock(mock)
 
 		// First UPDATE: success.
 		r1 := sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5))
 		mock.ExpectQuery(regexp.QuoteMeta(hashOnlyQuery)).
 			WithArgs("cid-1", userID, "h1", int64(5)).
 			WillReturnRows(r1)
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationUpdate, "cid-1", int64(6)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		// Second UPDATE: version mismatch (updatedID=NULL, currentDBVersion=3).
 		r2 := sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver3))
@@ -1669,6 +1932,7 @@ func TestUpdateMultipleRecords(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
+		expectSyncLock(mock)
 
 		// First UPDATE: record not found (both values are NULL).
 		r1 := sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value((*int64)(nil)))
@@ -1697,6 +1961,7 @@ func TestUpdateMultipleRecords(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
+		expectSyncLock(mock)
 
 		mock.ExpectQuery(regexp.QuoteMeta(hashOnlyQuery)).
 			WithArgs("cid-1", userID, "h", int64(5)).
@@ -1724,11 +1989,15 @@ func TestUpdateMultipleRecords(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
+		expectSyncLock(mock)
 
 		r1 := sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5))
 		mock.ExpectQuery(regexp.QuoteMeta(hashOnlyQuery)).
 			WithArgs("cid-1", userID, "h1", int64(5)).
 			WillReturnRows(r1)
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WithArgs(userID, models.AuditOperationUpdate, "cid-1", int64(6)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
 
@@ -1738,3 +2007,525 @@ func TestUpdateMultipleRecords(t *testing.T) {
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestTombstonedClientSideID(t *testing.T) {
+	const userID = int64(42)
+
+	ctx := func() context.Context {
+		l := zerolog.Nop()
+		return context.WithValue(l.WithContext(context.Background()), utils.UserIDCtxKey, userID)
+	}
+	newData := func(cid string) *models.PrivateData {
+		return &models.PrivateData{
+			UserID:       userID,
+			ClientSideID: cid,
+			Payload:      models.PrivateDataPayload{Type: models.Text},
+			Version:      1,
+		}
+	}
+	saveColumns := []string{"id", "created_at", "updated_at"}
+	cteColumns := []string{"updated_id", "current_db_version"}
+	ver5 := int64(5)
+
+	t.Run("insert over tombstone is rejected", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(savePrivateData)).
+			WillReturnRows(sqlmock.NewRows(saveColumns))
+		mock.ExpectQuery(regexp.QuoteMeta(getPrivateDataDeletedFlag)).
+			WithArgs(userID, "cid-dead").
+			WillReturnRows(sqlmock.NewRows([]string{"deleted"}).AddRow(true))
+		mock.ExpectRollback()
+
+		err := repo.SavePrivateData(ctx(), newData("cid-dead"))
+		require.ErrorIs(t, err, ErrPrivateDataTombstoned)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("insert over live record is rejected", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(savePrivateData)).
+			WillReturnRows(sqlmock.NewRows(saveColumns))
+		mock.ExpectQuery(regexp.QuoteMeta(getPrivateDataDeletedFlag)).
+			WithArgs(userID, "cid-live").
+			WillReturnRows(sqlmock.NewRows([]string{"deleted"}).AddRow(false))
+		mock.ExpectRollback()
+
+		err := repo.SavePrivateData(ctx(), newData("cid-live"))
+		require.ErrorIs(t, err, ErrPrivateDataAlreadyExists)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("batch insert over tombstone rolls back the batch", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db)
+
+		mock.ExpectBegin()
+		prep := mock.ExpectPrepare(regexp.QuoteMeta(savePrivateData))
+		prep.ExpectQuery().
+			WillReturnRows(sqlmock.NewRows(saveColumns).AddRow(int64(1), time.Now(), nil))
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		prep.ExpectQuery().
+			WillReturnRows(sqlmock.NewRows(saveColumns))
+		mock.ExpectQuery(regexp.QuoteMeta(getPrivateDataDeletedFlag)).
+			WithArgs(userID, "cid-dead").
+			WillReturnRows(sqlmock.NewRows([]string{"deleted"}).AddRow(true))
+		mock.ExpectRollback()
+
+		err := repo.SavePrivateData(ctx(), newData("cid-new"), newData("cid-dead"))
+		require.ErrorIs(t, err, ErrPrivateDataTombstoned)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update of tombstone is rejected", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db)
+
+		// The version matches, yet nothing was updated: only the
+		// deleted = FALSE guard can have excluded the row.
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE ciphers`)).
+			WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver5)))
+		mock.ExpectRollback()
+
+		err := repo.UpdatePrivateData(ctx(), models.UpdateRequest{
+			UserID: userID,
+			PrivateDataUpdates: []models.PrivateDataUpdate{
+				{ClientSideID: "cid-dead", Version: 5, UpdatedRecordHash: "h"},
+			},
+		})
+		require.ErrorIs(t, err, ErrPrivateDataTombstoned)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stale update of tombstone is still a version conflict", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE ciphers`)).
+			WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver5)))
+		mock.ExpectRollback()
+
+		err := repo.UpdatePrivateData(ctx(), models.UpdateRequest{
+			UserID: userID,
+			PrivateDataUpdates: []models.PrivateDataUpdate{
+				{ClientSideID: "cid-dead", Version: 4, UpdatedRecordHash: "h"},
+			},
+		})
+		require.ErrorIs(t, err, ErrVersionConflict)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("batch update of tombstone is rejected", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := newTestRepo(t, db)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE ciphers`)).
+			WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver5)))
+		mock.ExpectRollback()
+
+		err := repo.UpdatePrivateData(ctx(), models.UpdateRequest{
+			UserID: userID,
+			PrivateDataUpdates: []models.PrivateDataUpdate{
+				{ClientSideID: "cid-dead", Version: 5, UpdatedRecordHash: "h"},
+				{ClientSideID: "cid-live", Version: 1, UpdatedRecordHash: "h"},
+			},
+		})
+		require.ErrorIs(t, err, ErrPrivateDataTombstoned)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeletePrivateData_Modes(t *testing.T) {
+	const userID = int64(42)
+
+	ctx := func() context.Context {
+		l := zerolog.Nop()
+		return context.WithValue(l.WithContext(context.Background()), utils.UserIDCtxKey, userID)
+	}
+	cteColumns := []string{"updated_id", "current_db_version"}
+	id1, id2 := int64(1), int64(2)
+	ver5 := int64(5)
+
+	modes := []struct {
+		name       string
+		hardDelete bool
+		query      string
+	}{
+		{name: "soft delete", hardDelete: false, query: deletePrivateDataQuery},
+		{name: "hard delete", hardDelete: true, query: hardDeletePrivateDataQuery},
+	}
+
+	tests := []struct {
+		name    string
+		entries []models.DeleteEntry
+		setup   func(mock sqlmock.Sqlmock, query string)
+		wantErr error
+	}{
+		{
+			name:    "single record deleted",
+			entries: []models.DeleteEntry{{ClientSideID: "cid-1", Version: 5}},
+			setup: func(mock sqlmock.Sqlmock, query string) {
+				mock.ExpectBegin()
+				expectSyncLock(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("cid-1", userID, int64(5)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5)))
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+					WithArgs(userID, models.AuditOperationDelete, "cid-1", int64(6)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "multiple records deleted in one transaction",
+			entries: []models.DeleteEntry{
+				{ClientSideID: "cid-1", Version: 5},
+				{ClientSideID: "cid-2", Version: 5},
+			},
+			setup: func(mock sqlmock.Sqlmock, query string) {
+				mock.ExpectBegin()
+				expectSyncLock(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("cid-1", userID, int64(5)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5)))
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("cid-2", userID, int64(5)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id2), driver.Value(&ver5)))
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "record not found",
+			entries: []models.DeleteEntry{{ClientSideID: "cid-missing", Version: 1}},
+			setup: func(mock sqlmock.Sqlmock, query string) {
+				mock.ExpectBegin()
+				expectSyncLock(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value((*int64)(nil))))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrPrivateDataNotFound,
+		},
+		{
+			name:    "version conflict",
+			entries: []models.DeleteEntry{{ClientSideID: "cid-1", Version: 3}},
+			setup: func(mock sqlmock.Sqlmock, query string) {
+				mock.ExpectBegin()
+				expectSyncLock(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver5)))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrVersionConflict,
+		},
+	}
+
+	for _, mode := range modes {
+		for _, tc := range tests {
+			t.Run(mode.name+"/"+tc.name, func(t *testing.T) {
+				db, mock := newTestDB(t)
+				repo := NewPrivateDataRepository(newDBFromSQL(db), config.Storage{HardDelete: mode.hardDelete}, logger.Nop())
+				tc.setup(mock, mode.query)
+
+				err := repo.DeletePrivateData(ctx(), models.DeleteRequest{UserID: userID, DeleteEntries: tc.entries})
+				if tc.wantErr != nil {
+					require.ErrorIs(t, err, tc.wantErr)
+				} else {
+					require.NoError(t, err)
+				}
+				require.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestRestorePrivateData(t *testing.T) {
+	const userID = int64(42)
+
+	ctx := func() context.Context {
+		l := zerolog.Nop()
+		return context.WithValue(l.WithContext(context.Background()), utils.UserIDCtxKey, userID)
+	}
+	cteColumns := []string{"updated_id", "current_db_version", "current_deleted"}
+	id1, id2 := int64(1), int64(2)
+	ver5 := int64(5)
+	deleted, live := true, false
+
+	tests := []struct {
+		name    string
+		entries []models.RestoreEntry
+		setup   func(mock sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name:    "no entries is a no-op",
+			entries: nil,
+			setup:   func(mock sqlmock.Sqlmock) {},
+		},
+		{
+			name:    "single record restored",
+			entries: []models.RestoreEntry{{ClientSideID: "cid-1", Version: 5}},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectSyncLock(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(restorePrivateDataQuery)).
+					WithArgs("cid-1", userID, int64(5)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5), driver.Value(&deleted)))
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+					WithArgs(userID, models.AuditOperationRestore, "cid-1", int64(6)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "multiple records restored in one transaction",
+			entries: []models.RestoreEntry{
+				{ClientSideID: "cid-1", Version: 5},
+				{ClientSideID: "cid-2", Version: 5},
+			},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectSyncLock(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(restorePrivateDataQuery)).
+					WithArgs("cid-1", userID, int64(5)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5), driver.Value(&deleted)))
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(restorePrivateDataQuery)).
+					WithArgs("cid-2", userID, int64(5)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id2), driver.Value(&ver5), driver.Value(&deleted)))
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "record not found",
+			entries: []models.RestoreEntry{{ClientSideID: "cid-missing", Version: 1}},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectSyncLock(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(restorePrivateDataQuery)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value((*int64)(nil)), driver.Value((*bool)(nil))))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrPrivateDataNotFound,
+		},
+		{
+			name:    "version conflict",
+			entries: []models.RestoreEntry{{ClientSideID: "cid-1", Version: 3}},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectSyncLock(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(restorePrivateDataQuery)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver5), driver.Value(&deleted)))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrVersionConflict,
+		},
+		{
+			name:    "live record is not restored",
+			entries: []models.RestoreEntry{{ClientSideID: "cid-1", Version: 5}},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectSyncLock(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(restorePrivateDataQuery)).
+					WithArgs("cid-1", userID, int64(5)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver5), driver.Value(&live)))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrPrivateDataNotDeleted,
+		},
+		{
+			name: "conflict on a later entry rolls back the earlier ones",
+			entries: []models.RestoreEntry{
+				{ClientSideID: "cid-1", Version: 5},
+				{ClientSideID: "cid-2", Version: 3},
+			},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectSyncLock(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(restorePrivateDataQuery)).
+					WithArgs("cid-1", userID, int64(5)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5), driver.Value(&deleted)))
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(restorePrivateDataQuery)).
+					WithArgs("cid-2", userID, int64(3)).
+					WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver5), driver.Value(&deleted)))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrVersionConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			repo := NewPrivateDataRepository(newDBFromSQL(db), config.Storage{}, logger.Nop())
+			tc.setup(mock)
+
+			err := repo.RestorePrivateData(ctx(), models.RestoreRequest{UserID: userID, RestoreEntries: tc.entries})
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRestorePrivateData_ConflictReportsServerVersion(t *testing.T) {
+	const userID = int64(42)
+
+	db, mock := newTestDB(t)
+	repo := NewPrivateDataRepository(newDBFromSQL(db), config.Storage{}, logger.Nop())
+	ver7 := int64(7)
+	deleted := true
+
+	mock.ExpectBegin()
+	expectSyncLock(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(restorePrivateDataQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_id", "current_db_version", "current_deleted"}).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver7), driver.Value(&deleted)))
+	mock.ExpectRollback()
+
+	err := repo.RestorePrivateData(testContext(), models.RestoreRequest{
+		UserID:         userID,
+		RestoreEntries: []models.RestoreEntry{{ClientSideID: "cid-1", Version: 3}},
+	})
+
+	var conflict *VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, models.ConflictOnRestore, conflict.Operation)
+	assert.Equal(t, "cid-1", conflict.ClientSideID)
+	assert.Equal(t, int64(7), conflict.ServerVersion)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPrivateDataRepository_SyncLock(t *testing.T) {
+	const userID = int64(42)
+
+	ctx := func() context.Context {
+		l := zerolog.Nop()
+		return context.WithValue(l.WithContext(context.Background()), utils.UserIDCtxKey, userID)
+	}
+	lockRows := func(acquired bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(acquired)
+	}
+	id1, ver5 := int64(1), int64(5)
+	entry := models.DeleteEntry{ClientSideID: "cid-1", Version: 5}
+	deleteSQL := (&privateDataRepository{}).deleteQuery()
+
+	expectDelete := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(regexp.QuoteMeta(deleteSQL)).
+			WithArgs("cid-1", userID, int64(5)).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_id", "current_db_version"}).AddRow(driver.Value(&id1), driver.Value(&ver5)))
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		setup   func(mock sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "lock is requested for the user and released on commit",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(tryLockUserSync)).WithArgs(userID).WillReturnRows(lockRows(true))
+				expectDelete(mock)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "waits until a concurrent holder releases the lock",
+			timeout: time.Second,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(tryLockUserSync)).WithArgs(userID).WillReturnRows(lockRows(false))
+				mock.ExpectQuery(regexp.QuoteMeta(tryLockUserSync)).WithArgs(userID).WillReturnRows(lockRows(true))
+				expectDelete(mock)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "conflicting holder past the timeout is reported and nothing is changed",
+			timeout: time.Millisecond,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(tryLockUserSync)).WithArgs(userID).WillReturnRows(lockRows(false))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrSyncLocked,
+		},
+		{
+			name: "lock query failure rolls back",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(tryLockUserSync)).WithArgs(userID).WillReturnError(errors.New("connection reset"))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrExecutingQuery,
+		},
+		{
+			name:    "negative timeout disables the lock",
+			timeout: -1,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectDelete(mock)
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			repo := NewPrivateDataRepository(newDBFromSQL(db), config.Storage{SyncLockTimeout: tc.timeout}, logger.Nop())
+			tc.setup(mock)
+
+			err := repo.DeletePrivateData(ctx(), models.DeleteRequest{UserID: userID, DeleteEntries: []models.DeleteEntry{entry}})
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("batch update takes the lock of the request user", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		repo := NewPrivateDataRepository(newDBFromSQL(db), config.Storage{SyncLockTimeout: time.Millisecond}, logger.Nop())
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(tryLockUserSync)).WithArgs(userID).WillReturnRows(lockRows(false))
+		mock.ExpectRollback()
+
+		err := repo.UpdatePrivateData(ctx(), models.UpdateRequest{UserID: userID, PrivateDataUpdates: []models.PrivateDataUpdate{
+			{ClientSideID: "cid-1", Version: 1, UpdatedRecordHash: "h1"},
+			{ClientSideID: "cid-2", Version: 1, UpdatedRecordHash: "h2"},
+		}})
+		require.ErrorIs(t, err, ErrSyncLocked)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+)
+
+// schemaRepository is the PostgreSQL-backed implementation of
+// [SchemaRepository]. It reads the "goose_db_version" table maintained by
+// the migration runner.
+type schemaRepository struct {
+	*DB
+	logger *logger.Logger
+}
+
+// NewSchemaRepository constructs a [SchemaRepository] backed by the provided
+// database connection and logger.
+func NewSchemaRepository(db *DB, logger *logger.Logger) SchemaRepository {
+	return &schemaRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// SchemaVersion implements [SchemaRepository].
+func (s *schemaRepository) SchemaVersion(ctx context.Context) (int64, error) {
+	var version int64
+	if err := s.DB.QueryRowContext(ctx, getSchemaVersion).Scan(&version); err != nil {
+		logger.FromContext(ctx).Err(err).
+			Str("func", "schemaRepository.SchemaVersion").
+			Msg("failed to query schema version")
+		return 0, fmt.Errorf("%w: %w", ErrExecutingQuery, err)
+	}
+
+	return version, nil
+}
+
+// ciphersTable is the table verified by [schemaRepository.CheckSchema].
+const ciphersTable = "ciphers"
+
+// cipherColumns are the columns of the "ciphers" table after all migrations
+// have been applied.
+var cipherColumns = []string{
+	"id",
+	"user_id",
+	"type",
+	"metadata",
+	"data",
+	"notes",
+	"additional_fields",
+	"created_at",
+	"updated_at",
+	"version",
+	"client_side_id",
+	"hash",
+	"deleted",
+}
+
+// CheckSchema implements [SchemaRepository].
+func (s *schemaRepository) CheckSchema(ctx context.Context) error {
+	rows, err := s.DB.QueryContext(ctx, getTableColumns, ciphersTable)
+	if err != nil {
+		logger.FromContext(ctx).Err(err).
+			Str("func", "schemaRepository.CheckSchema").
+			Msg("failed to query table columns")
+		return fmt.Errorf("%w: %w", ErrExecutingQuery, err)
+	}
+	defer rows.Close()
+
+	var actual []string
+	for rows.Next() {
+		var column string
+		if err = rows.Scan(&column); err != nil {
+			return fmt.Errorf("%w: %w", ErrScanningRow, err)
+		}
+		actual = append(actual, column)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrScanningRows, err)
+	}
+
+	missing, extra := diffColumns(cipherColumns, actual)
+	if len(missing) == 0 && len(extra) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: table %q: missing columns [%s], unexpected columns [%s]",
+		ErrSchemaDrift, ciphersTable, strings.Join(missing, ", "), strings.Join(extra, ", "))
+}
+
+// diffColumns returns the columns of expected absent from actual and the
+// columns of actual absent from expected, each in its input order.
+func diffColumns(expected, actual []string) (missing, extra []string) {
+	have := make(map[string]bool, len(actual))
+	for _, c := range actual {
+		have[c] = true
+	}
+	want := make(map[string]bool, len(expected))
+	for _, c := range expected {
+		want[c] = true
+		if !have[c] {
+			missing = append(missing, c)
+		}
+	}
+	for _, c := range actual {
+		if !want[c] {
+			extra = append(extra, c)
+		}
+	}
+	return missing, extra
+}
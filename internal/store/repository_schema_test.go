@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(mock sqlmock.Sqlmock)
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "success: newest applied migration",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getSchemaVersion)).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(int64(8)))
+			},
+			want: 8,
+		},
+		{
+			name: "error: query fails",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getSchemaVersion)).
+					WillReturnError(errors.New("relation does not exist"))
+			},
+			wantErr: ErrExecutingQuery,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			repo := NewSchemaRepository(newDBFromSQL(db), logger.Nop())
+			tc.setup(mock)
+
+			got, err := repo.SchemaVersion(testContext())
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	columnRows := func(columns ...string) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"column_name"})
+		for _, c := range columns {
+			rows.AddRow(c)
+		}
+		return rows
+	}
+	without := func(drop string) []string {
+		var out []string
+		for _, c := range cipherColumns {
+			if c != drop {
+				out = append(out, c)
+			}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		setup     func(mock sqlmock.Sqlmock)
+		wantErr   error
+		wantInMsg []string
+	}{
+		{
+			name: "success: columns match",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getTableColumns)).
+					WithArgs(ciphersTable).
+					WillReturnRows(columnRows(cipherColumns...))
+			},
+		},
+		{
+			name: "error: missing and extra columns",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getTableColumns)).
+					WithArgs(ciphersTable).
+					WillReturnRows(columnRows(append(without("hash"), "legacy_blob")...))
+			},
+			wantErr:   ErrSchemaDrift,
+			wantInMsg: []string{"missing columns [hash]", "unexpected columns [legacy_blob]"},
+		},
+		{
+			name: "error: table does not exist",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getTableColumns)).
+					WithArgs(ciphersTable).
+					WillReturnRows(columnRows())
+			},
+			wantErr:   ErrSchemaDrift,
+			wantInMsg: []string{"missing columns [id, user_id,", "unexpected columns []"},
+		},
+		{
+			name: "error: query fails",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getTableColumns)).
+					WithArgs(ciphersTable).
+					WillReturnError(errors.New("permission denied"))
+			},
+			wantErr: ErrExecutingQuery,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			repo := NewSchemaRepository(newDBFromSQL(db), logger.Nop())
+			tc.setup(mock)
+
+			err := repo.CheckSchema(testContext())
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				for _, s := range tc.wantInMsg {
+					assert.Contains(t, err.Error(), s)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
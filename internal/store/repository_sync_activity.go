@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// syncActivityRepository is the PostgreSQL-backed implementation of
+// [SyncActivityRepository]. It keeps one row per user in the
+// "sync_activity" table.
+type syncActivityRepository struct {
+	*DB
+	logger *logger.Logger
+}
+
+// NewSyncActivityRepository constructs a [SyncActivityRepository] backed by
+// the provided database connection and logger.
+func NewSyncActivityRepository(db *DB, logger *logger.Logger) SyncActivityRepository {
+	return &syncActivityRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// TouchLastSeen implements [SyncActivityRepository] with a single upsert.
+func (s *syncActivityRepository) TouchLastSeen(ctx context.Context, userID int64, at time.Time) error {
+	if _, err := s.DB.ExecContext(ctx, touchSyncActivity, userID, at.UTC()); err != nil {
+		logger.FromContext(ctx).Err(err).
+			Str("func", "syncActivityRepository.TouchLastSeen").
+			Int64("user_id", userID).
+			Msg("failed to record sync activity")
+		return fmt.Errorf("%w: %w", ErrExecutingQuery, err)
+	}
+	return nil
+}
+
+// GetLastSeen implements [SyncActivityRepository]. Ties on the last-seen
+// time are ordered by user ID so that pagination is stable.
+func (s *syncActivityRepository) GetLastSeen(ctx context.Context, request models.SyncLagRequest) ([]models.SyncLag, error) {
+	log := logger.FromContext(ctx)
+
+	rows, err := s.DB.QueryContext(ctx, getSyncActivity, request.UserID, request.Limit, request.Offset)
+	if err != nil {
+		log.Err(err).
+			Str("func", "syncActivityRepository.GetLastSeen").
+			Int64("user_id", request.UserID).
+			Msg("failed to execute query for getting sync activity")
+		return nil, fmt.Errorf("%w: %w", ErrExecutingQuery, err)
+	}
+	defer rows.Close()
+
+	lags := make([]models.SyncLag, 0)
+	for rows.Next() {
+		var lag models.SyncLag
+		if scanErr := rows.Scan(&lag.UserID, &lag.LastSeenAt); scanErr != nil {
+			log.Err(scanErr).
+				Str("func", "syncActivityRepository.GetLastSeen").
+				Int64("user_id", request.UserID).
+				Msg("failed to scan sync activity row")
+			return nil, fmt.Errorf("%w: %w", ErrScanningRows, scanErr)
+		}
+		lags = append(lags, lag)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		log.Err(rowsErr).
+			Str("func", "syncActivityRepository.GetLastSeen").
+			Int64("user_id", request.UserID).
+			Msg("error occurred during rows iteration")
+		return nil, fmt.Errorf("%w: %w", ErrScanningRows, rowsErr)
+	}
+
+	return lags, nil
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var syncActivityColumns = []string{"user_id", "last_seen_at"}
+
+func newTestSyncActivityRepo(t *testing.T) (SyncActivityRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock := newTestDB(t)
+	return NewSyncActivityRepository(newDBFromSQL(db), logger.Nop()), mock
+}
+
+func TestTouchLastSeen(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	tests := []struct {
+		name    string
+		execErr error
+		wantErr error
+	}{
+		{name: "success: upserted in UTC"},
+		{name: "error: exec fails", execErr: errors.New("connection refused"), wantErr: ErrExecutingQuery},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := newTestSyncActivityRepo(t)
+			exp := mock.ExpectExec(regexp.QuoteMeta(touchSyncActivity)).WithArgs(int64(42), at.UTC())
+			if tc.execErr != nil {
+				exp.WillReturnError(tc.execErr)
+			} else {
+				exp.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			err := repo.TouchLastSeen(testContext(), 42, at)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetLastSeen(t *testing.T) {
+	older := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		request models.SyncLagRequest
+		setup   func(mock sqlmock.Sqlmock)
+		want    []models.SyncLag
+		wantErr error
+	}{
+		{
+			name:    "success: every user, stalest first",
+			request: models.SyncLagRequest{Limit: 10},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getSyncActivity)).
+					WithArgs(int64(0), 10, 0).
+					WillReturnRows(sqlmock.NewRows(syncActivityColumns).
+						AddRow(int64(7), older).
+						AddRow(int64(3), newer))
+			},
+			want: []models.SyncLag{{UserID: 7, LastSeenAt: older}, {UserID: 3, LastSeenAt: newer}},
+		},
+		{
+			name:    "success: one user never seen",
+			request: models.SyncLagRequest{UserID: 9, Limit: 10, Offset: 5},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getSyncActivity)).
+					WithArgs(int64(9), 10, 5).
+					WillReturnRows(sqlmock.NewRows(syncActivityColumns))
+			},
+			want: []models.SyncLag{},
+		},
+		{
+			name:    "error: query fails",
+			request: models.SyncLagRequest{Limit: 10},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getSyncActivity)).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: ErrExecutingQuery,
+		},
+		{
+			name:    "error: scan fails",
+			request: models.SyncLagRequest{Limit: 10},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getSyncActivity)).
+					WillReturnRows(sqlmock.NewRows(syncActivityColumns).AddRow("not-a-number", older))
+			},
+			wantErr: ErrScanningRows,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := newTestSyncActivityRepo(t)
+			tc.setup(mock)
+
+			got, err := repo.GetLastSeen(testContext(), tc.request)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
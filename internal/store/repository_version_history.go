@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// archiveVersion copies the row that update is about to replace into
+// cipher_history and trims the item's history to p.historyLimit entries. It
+// runs inside tx before the update, so a failed update discards the archived
+// copy as well. It is a no-op when version history is disabled.
+func (p *privateDataRepository) archiveVersion(ctx context.Context, tx *sql.Tx, update models.PrivateDataUpdate) error {
+	if p.historyLimit <= 0 {
+		return nil
+	}
+
+	userID, _ := utils.GetUserIDFromContext(ctx)
+	if _, err := tx.ExecContext(ctx, archiveCipherVersion, userID, update.ClientSideID, update.Version); err != nil {
+		logger.FromContext(ctx).Err(err).
+			Str("func", "privateDataRepository.archiveVersion").
+			Str("id", update.ClientSideID).
+			Msg("failed to archive previous version")
+		return fmt.Errorf("%w: %w", ErrWritingVersionHistory, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, trimCipherHistory, userID, update.ClientSideID, p.historyLimit); err != nil {
+		logger.FromContext(ctx).Err(err).
+			Str("func", "privateDataRepository.archiveVersion").
+			Str("id", update.ClientSideID).
+			Msg("failed to trim version history")
+		return fmt.Errorf("%w: %w", ErrWritingVersionHistory, err)
+	}
+
+	return nil
+}
+
+// GetVersionHistory implements [PrivateDataRepository]. Versions are ordered
+// by version number descending, so the most recently replaced one comes
+// first.
+func (p *privateDataRepository) GetVersionHistory(ctx context.Context, userID int64, clientSideID string) ([]models.PrivateDataVersion, error) {
+	log := logger.FromContext(ctx)
+
+	rows, err := p.DB.QueryContext(ctx, getCipherHistory, userID, clientSideID)
+	if err != nil {
+		log.Err(err).
+			Str("func", "privateDataRepository.GetVersionHistory").
+			Int64("user_id", userID).
+			Str("client_side_id", clientSideID).
+			Msg("failed to execute query for getting version history")
+		return nil, fmt.Errorf("%w: %w", ErrExecutingQuery, err)
+	}
+	defer rows.Close()
+
+	versions := make([]models.PrivateDataVersion, 0)
+	for rows.Next() {
+		var v models.PrivateDataVersion
+		if scanErr := rows.Scan(
+			&v.ClientSideID,
+			&v.Version,
+			&v.Payload.Type,
+			&v.Payload.Metadata,
+			&v.Payload.Data,
+			&v.Payload.Notes,
+			&v.Payload.AdditionalFields,
+			&v.Hash,
+			&v.UpdatedAt,
+			&v.ArchivedAt,
+		); scanErr != nil {
+			log.Err(scanErr).
+				Str("func", "privateDataRepository.GetVersionHistory").
+				Int64("user_id", userID).
+				Str("client_side_id", clientSideID).
+				Msg("failed to scan version history row")
+			return nil, fmt.Errorf("%w: %w", ErrScanningRows, scanErr)
+		}
+		versions = append(versions, v)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		log.Err(rowsErr).
+			Str("func", "privateDataRepository.GetVersionHistory").
+			Int64("user_id", userID).
+			Msg("error occurred during rows iteration")
+		return nil, fmt.Errorf("%w: %w", ErrScanningRows, rowsErr)
+	}
+
+	return versions, nil
+}
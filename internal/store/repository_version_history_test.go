@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/internal/utils"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var historyColumns = []string{
+	"client_side_id", "version", "type", "metadata", "data", "notes",
+	"additional_fields", "hash", "updated_at", "archived_at",
+}
+
+func TestUpdatePrivateData_WritesVersionHistory(t *testing.T) {
+	const userID = int64(42)
+
+	ctx := func() context.Context {
+		l := zerolog.Nop()
+		return context.WithValue(l.WithContext(context.Background()), utils.UserIDCtxKey, userID)
+	}
+
+	updateQuery := regexp.QuoteMeta(`UPDATE ciphers`)
+	cteColumns := []string{"updated_id", "current_db_version"}
+	id1 := int64(1)
+	ver5 := int64(5)
+
+	newRepo := func(t *testing.T, limit int) (PrivateDataRepository, sqlmock.Sqlmock) {
+		t.Helper()
+		db, mock := newTestDB(t)
+		return NewPrivateDataRepository(newDBFromSQL(db), config.Storage{VersionHistory: limit}, logger.Nop()), mock
+	}
+
+	request := models.UpdateRequest{
+		UserID: userID,
+		PrivateDataUpdates: []models.PrivateDataUpdate{
+			{ClientSideID: "cid-1", Version: 5, UpdatedRecordHash: "h"},
+		},
+	}
+
+	t.Run("update archives the replaced version and trims to the cap", func(t *testing.T) {
+		repo, mock := newRepo(t, 3)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectExec(regexp.QuoteMeta(archiveCipherVersion)).
+			WithArgs(userID, "cid-1", int64(5)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(trimCipherHistory)).
+			WithArgs(userID, "cid-1", 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(updateQuery).
+			WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5)))
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.UpdatePrivateData(ctx(), request))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("every update of a batch is archived", func(t *testing.T) {
+		repo, mock := newRepo(t, 1)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		for _, cid := range []string{"cid-1", "cid-2"} {
+			mock.ExpectExec(regexp.QuoteMeta(archiveCipherVersion)).
+				WithArgs(userID, cid, int64(5)).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec(regexp.QuoteMeta(trimCipherHistory)).
+				WithArgs(userID, cid, 1).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(updateQuery).
+				WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5)))
+			mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+		mock.ExpectCommit()
+
+		err := repo.UpdatePrivateData(ctx(), models.UpdateRequest{
+			UserID: userID,
+			PrivateDataUpdates: []models.PrivateDataUpdate{
+				{ClientSideID: "cid-1", Version: 5, UpdatedRecordHash: "h"},
+				{ClientSideID: "cid-2", Version: 5, UpdatedRecordHash: "h"},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("version conflict rolls back the archived copy", func(t *testing.T) {
+		repo, mock := newRepo(t, 3)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectExec(regexp.QuoteMeta(archiveCipherVersion)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(trimCipherHistory)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(updateQuery).
+			WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value((*int64)(nil)), driver.Value(&ver5)))
+		mock.ExpectRollback()
+
+		err := repo.UpdatePrivateData(ctx(), models.UpdateRequest{
+			UserID: userID,
+			PrivateDataUpdates: []models.PrivateDataUpdate{
+				{ClientSideID: "cid-1", Version: 3, UpdatedRecordHash: "h"},
+			},
+		})
+		require.ErrorIs(t, err, ErrVersionConflict)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("history write failure rolls back the update", func(t *testing.T) {
+		repo, mock := newRepo(t, 3)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectExec(regexp.QuoteMeta(archiveCipherVersion)).
+			WillReturnError(errors.New("disk full"))
+		mock.ExpectRollback()
+
+		require.ErrorIs(t, repo.UpdatePrivateData(ctx(), request), ErrWritingVersionHistory)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("disabled history writes nothing", func(t *testing.T) {
+		repo, mock := newRepo(t, 0)
+
+		mock.ExpectBegin()
+		expectSyncLock(mock)
+		mock.ExpectQuery(updateQuery).
+			WillReturnRows(sqlmock.NewRows(cteColumns).AddRow(driver.Value(&id1), driver.Value(&ver5)))
+		mock.ExpectExec(regexp.QuoteMeta(insertAuditEntry)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.UpdatePrivateData(ctx(), request))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetVersionHistory(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	archivedAt := updatedAt.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		setup   func(mock sqlmock.Sqlmock)
+		want    []models.PrivateDataVersion
+		wantErr error
+	}{
+		{
+			name: "success: versions newest first",
+			setup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(historyColumns).
+					AddRow("cid-1", int64(4), models.Text, "meta-4", "data-4", nil, nil, "h4", updatedAt, archivedAt).
+					AddRow("cid-1", int64(3), models.Text, "meta-3", "data-3", nil, nil, "h3", updatedAt, archivedAt)
+				mock.ExpectQuery(regexp.QuoteMeta(getCipherHistory)).
+					WithArgs(int64(42), "cid-1").
+					WillReturnRows(rows)
+			},
+			want: []models.PrivateDataVersion{
+				{
+					ClientSideID: "cid-1",
+					Version:      4,
+					Payload:      models.PrivateDataPayload{Type: models.Text, Metadata: "meta-4", Data: "data-4"},
+					Hash:         "h4",
+					UpdatedAt:    &updatedAt,
+					ArchivedAt:   archivedAt,
+				},
+				{
+					ClientSideID: "cid-1",
+					Version:      3,
+					Payload:      models.PrivateDataPayload{Type: models.Text, Metadata: "meta-3", Data: "data-3"},
+					Hash:         "h3",
+					UpdatedAt:    &updatedAt,
+					ArchivedAt:   archivedAt,
+				},
+			},
+		},
+		{
+			name: "success: no history",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getCipherHistory)).
+					WithArgs(int64(42), "cid-1").
+					WillReturnRows(sqlmock.NewRows(historyColumns))
+			},
+			want: []models.PrivateDataVersion{},
+		},
+		{
+			name: "error: query fails",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(getCipherHistory)).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: ErrExecutingQuery,
+		},
+		{
+			name: "error: scan fails",
+			setup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(historyColumns).
+					AddRow("cid-1", "not-a-number", models.Text, "m", "d", nil, nil, "h", updatedAt, archivedAt)
+				mock.ExpectQuery(regexp.QuoteMeta(getCipherHistory)).
+					WillReturnRows(rows)
+			},
+			wantErr: ErrScanningRows,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			repo := newTestRepo(t, db)
+			tc.setup(mock)
+
+			got, err := repo.GetVersionHistory(testContext(), 42, "cid-1")
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
@@ -50,3 +51,9 @@ type DB struct {
 func (db *DB) Migrate() error {
 	return migrations.Migrate(db.DB)
 }
+
+// PendingMigrations returns the migrations [DB.Migrate] would apply, in
+// order, without applying them or otherwise changing the database.
+func (db *DB) PendingMigrations(ctx context.Context) ([]migrations.Migration, error) {
+	return migrations.Pending(ctx, db.DB)
+}
@@ -15,6 +15,12 @@ import (
 )
 
 const (
+	// tryLockUserSync takes the per-user sync advisory lock for the current
+	// transaction without waiting. The key is derived from a fixed namespace
+	// and the user ID so that it does not clash with other advisory locks;
+	// the lock is released on commit or rollback.
+	tryLockUserSync = `SELECT pg_try_advisory_xact_lock(hashtextextended('go-pass-keeper:sync', $1));`
+
 	createUser = `
 		INSERT INTO users (login, auth_hash, master_password_hint, name, encryption_salt, encrypted_master_key) 
     	VALUES ($1, $2, $3, $4, $5, $6) 
@@ -37,8 +43,14 @@ const (
 			version,
 			hash,
 			created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id;`
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10::timestamptz, NOW()))
+		ON CONFLICT (user_id, client_side_id) DO NOTHING
+		RETURNING id, created_at, updated_at;`
+
+	getPrivateDataDeletedFlag = `
+		SELECT deleted
+		FROM ciphers
+		WHERE user_id = $1 AND client_side_id = $2;`
 
 	getAllUserPrivateData = `
 		SELECT
@@ -59,9 +71,24 @@ const (
 		WHERE user_id = $1;`
 
 	getAllUserDataState = `
-		SELECT client_side_id, hash, version, deleted, updated_at
+		SELECT id, client_side_id, hash, version, deleted, updated_at
 		FROM ciphers
-		WHERE user_id = $1;`
+		WHERE user_id = $1 AND id > $2
+		ORDER BY id
+		LIMIT NULLIF($3, 0);`
+
+	getAllUserLiveDataState = `
+		SELECT id, client_side_id, hash, version, deleted, updated_at
+		FROM ciphers
+		WHERE user_id = $1 AND id > $2 AND deleted = FALSE
+		ORDER BY id
+		LIMIT NULLIF($3, 0);`
+
+	getAllClientSideIDs = `
+		SELECT client_side_id, deleted
+		FROM ciphers
+		WHERE user_id = $1
+		ORDER BY id;`
 
 	deletePrivateDataQuery = `
 		WITH target_record AS (
@@ -83,6 +110,131 @@ const (
 		SELECT
 			(SELECT id FROM updated_record)       AS updated_id,
 			(SELECT version FROM target_record)   AS current_db_version;`
+
+	// hardDeletePrivateDataQuery is the hard-delete counterpart of
+	// [deletePrivateDataQuery]: the row is removed instead of flagged. The
+	// result columns have the same meaning, so both queries share the
+	// not-found / version-conflict handling.
+	hardDeletePrivateDataQuery = `
+		WITH target_record AS (
+			SELECT id, version
+			FROM ciphers
+			WHERE client_side_id = $1 AND user_id = $2
+		),
+		deleted_record AS (
+			DELETE FROM ciphers
+			WHERE client_side_id = $1
+			  AND user_id = $2
+			  AND version = $3
+			RETURNING id
+		)
+		SELECT
+			(SELECT id FROM deleted_record)       AS updated_id,
+			(SELECT version FROM target_record)   AS current_db_version;`
+
+	// restorePrivateDataQuery is the inverse of [deletePrivateDataQuery]: it
+	// clears the "deleted" flag of a tombstone and bumps the version. The
+	// first two result columns have the same meaning as in the delete
+	// queries, so it shares their not-found / version-conflict handling;
+	// current_deleted tells a live target, which is left untouched, apart
+	// from a version mismatch.
+	restorePrivateDataQuery = `
+		WITH target_record AS (
+			SELECT id, version, deleted
+			FROM ciphers
+			WHERE client_side_id = $1 AND user_id = $2
+		),
+		updated_record AS (
+			UPDATE ciphers
+			SET
+				deleted = FALSE,
+				updated_at = NOW(),
+				version = version + 1
+			WHERE client_side_id = $1
+			  AND user_id = $2
+			  AND version = $3
+			  AND deleted = TRUE
+			RETURNING id
+		)
+		SELECT
+			(SELECT id FROM updated_record)       AS updated_id,
+			(SELECT version FROM target_record)   AS current_db_version,
+			(SELECT deleted FROM target_record)   AS current_deleted;`
+
+	insertAuditEntry = `
+		INSERT INTO audit_log (user_id, operation, client_side_id, version)
+		VALUES ($1, $2, $3, $4);`
+
+	getAuditEntries = `
+		SELECT id, user_id, operation, client_side_id, version, created_at
+		FROM audit_log
+		WHERE user_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3;`
+
+	// touchSyncActivity records the last-seen time of a user. A time older
+	// than the stored one, e.g. from a request that finished late, never
+	// moves it back.
+	touchSyncActivity = `
+		INSERT INTO sync_activity (user_id, last_seen_at)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+			SET last_seen_at = GREATEST(sync_activity.last_seen_at, EXCLUDED.last_seen_at);`
+
+	// getSyncActivity lists last-seen times, stalest first. A zero $1 selects
+	// every user.
+	getSyncActivity = `
+		SELECT user_id, last_seen_at
+		FROM sync_activity
+		WHERE $1::BIGINT = 0 OR user_id = $1::BIGINT
+		ORDER BY last_seen_at, user_id
+		LIMIT $2 OFFSET $3;`
+
+	// archiveCipherVersion copies the current row of a vault item into
+	// cipher_history before an update replaces it. Nothing is copied when
+	// the expected version does not match or the item is soft-deleted; the
+	// update itself then fails and the transaction is rolled back.
+	archiveCipherVersion = `
+		INSERT INTO cipher_history (
+			user_id, client_side_id, version, type, metadata, data,
+			notes, additional_fields, hash, updated_at
+		)
+		SELECT
+			user_id, client_side_id, version, type, metadata, data,
+			notes, additional_fields, hash, COALESCE(updated_at, created_at)
+		FROM ciphers
+		WHERE user_id = $1 AND client_side_id = $2 AND version = $3 AND deleted = FALSE
+		ON CONFLICT (user_id, client_side_id, version) DO NOTHING;`
+
+	// trimCipherHistory keeps only the $3 newest archived versions of a
+	// vault item.
+	trimCipherHistory = `
+		DELETE FROM cipher_history
+		WHERE user_id = $1 AND client_side_id = $2
+		  AND id NOT IN (
+			SELECT id
+			FROM cipher_history
+			WHERE user_id = $1 AND client_side_id = $2
+			ORDER BY version DESC
+			LIMIT $3
+		  );`
+
+	getCipherHistory = `
+		SELECT client_side_id, version, type, metadata, data, notes, additional_fields, hash, updated_at, archived_at
+		FROM cipher_history
+		WHERE user_id = $1 AND client_side_id = $2
+		ORDER BY version DESC;`
+
+	getSchemaVersion = `
+		SELECT COALESCE(MAX(version_id), 0)
+		FROM goose_db_version
+		WHERE is_applied;`
+
+	getTableColumns = `
+		SELECT column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1
+		ORDER BY ordinal_position;`
 )
 
 var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
@@ -115,9 +267,15 @@ func buildSelectAllUserDataQuery(ctx context.Context, userID int64) (string, []a
 	return query, args, nil
 }
 
-// buildGetPrivateDataQuery builds SELECT query with optional ID filter
+// buildGetPrivateDataQuery builds SELECT query with optional ID and type filters.
+// It returns [ErrTooManyClientSideIDs] when req filters by more than maxIDs
+// client-side IDs, since each ID becomes one bind parameter.
 // checked!
-func buildGetPrivateDataQuery(ctx context.Context, req models.DownloadRequest) (string, []any, error) {
+func buildGetPrivateDataQuery(ctx context.Context, req models.DownloadRequest, maxIDs int) (string, []any, error) {
+	if err := checkClientSideIDsCount(len(req.ClientSideIDs), maxIDs); err != nil {
+		return "", nil, err
+	}
+
 	qb := psql.
 		Select(
 			"id",
@@ -141,6 +299,10 @@ func buildGetPrivateDataQuery(ctx context.Context, req models.DownloadRequest) (
 		qb = qb.Where(sq.Eq{"client_side_id": req.ClientSideIDs})
 	}
 
+	if len(req.Types) > 0 {
+		qb = qb.Where(sq.Eq{"type": req.Types})
+	}
+
 	query, args, err := qb.ToSql()
 	if err != nil {
 		return "", nil, fmt.Errorf("%w: %w", ErrBuildingSQLQuery, err)
@@ -150,8 +312,15 @@ func buildGetPrivateDataQuery(ctx context.Context, req models.DownloadRequest) (
 	return query, args, nil
 }
 
+// buildGetStatesSyncQuery builds the states SELECT query for the client-side
+// IDs in syncRequest, rejecting more than maxIDs of them with
+// [ErrTooManyClientSideIDs].
 // checked!
-func buildGetStatesSyncQuery(ctx context.Context, syncRequest models.SyncRequest) (string, []any, error) {
+func buildGetStatesSyncQuery(ctx context.Context, syncRequest models.SyncRequest, maxIDs int) (string, []any, error) {
+	if err := checkClientSideIDsCount(len(syncRequest.ClientSideIDs), maxIDs); err != nil {
+		return "", nil, err
+	}
+
 	qb := psql.
 		Select(
 			"client_side_id",
@@ -181,9 +350,20 @@ func buildGetStatesSyncQuery(ctx context.Context, syncRequest models.SyncRequest
 	return query, args, nil
 }
 
+// checkClientSideIDsCount returns [ErrTooManyClientSideIDs] when count
+// exceeds maxIDs. A non-positive maxIDs disables the check.
+func checkClientSideIDsCount(count, maxIDs int) error {
+	if maxIDs > 0 && count > maxIDs {
+		return fmt.Errorf("%w: %d > %d", ErrTooManyClientSideIDs, count, maxIDs)
+	}
+	return nil
+}
+
 // buildUpdateQuery dynamically builds UPDATE query with CTE for optimistic locking.
 // Returns a query that always returns a row if the record exists,
 // allowing to distinguish between NotFound and VersionConflict.
+// Soft-deleted records are never updated: a matching version with no updated
+// row means the record is a tombstone.
 // checked!
 func buildUpdateQuery(ctx context.Context, update models.PrivateDataUpdate) (string, []any, error) {
 	userID, _ := utils.GetUserIDFromContext(ctx)
@@ -240,6 +420,7 @@ func buildUpdateQuery(ctx context.Context, update models.PrivateDataUpdate) (str
 			WHERE client_side_id = $1
 			  AND user_id = $2
 			  AND version = %s
+			  AND deleted = FALSE
 			RETURNING id
 		)
 		SELECT
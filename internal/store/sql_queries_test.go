@@ -5,9 +5,11 @@ package store
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/utils"
 	"github.com/MKhiriev/go-pass-keeper/models"
 	"github.com/stretchr/testify/assert"
@@ -291,13 +293,83 @@ func Test_buildGetPrivateDataQuery_SQLContainsParts(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "success: no Types adds no type filter",
+			req: models.DownloadRequest{
+				UserID: 42,
+				Types:  nil,
+			},
+			checkQuery: func(t *testing.T, query string, args []any) {
+				q := strings.ToLower(query)
+
+				// type is present in SELECT, so check only the WHERE section.
+				wherePart := q[strings.Index(q, "where"):]
+				require.NotContains(t, wherePart, "type")
+
+				require.Len(t, args, 1)
+				require.Equal(t, int64(42), args[0])
+			},
+		},
+		{
+			name: "success: userID + single Type",
+			req: models.DownloadRequest{
+				UserID: 42,
+				Types:  []models.DataType{models.BankCard},
+			},
+			checkQuery: func(t *testing.T, query string, args []any) {
+				q := strings.ToLower(query)
+
+				wherePart := q[strings.Index(q, "where"):]
+				require.Contains(t, wherePart, "type in ($2)")
+
+				require.Len(t, args, 2)
+				require.Equal(t, int64(42), args[0])
+				require.Equal(t, models.BankCard, args[1])
+			},
+		},
+		{
+			name: "success: userID + multiple Types",
+			req: models.DownloadRequest{
+				UserID: 42,
+				Types:  []models.DataType{models.LoginPassword, models.Binary},
+			},
+			checkQuery: func(t *testing.T, query string, args []any) {
+				q := strings.ToLower(query)
+
+				wherePart := q[strings.Index(q, "where"):]
+				require.Contains(t, wherePart, "type in ($2,$3)")
+
+				require.Len(t, args, 3)
+				require.Equal(t, models.LoginPassword, args[1])
+				require.Equal(t, models.Binary, args[2])
+			},
+		},
+		{
+			name: "success: ClientSideIDs and Types combined",
+			req: models.DownloadRequest{
+				UserID:        42,
+				ClientSideIDs: []string{"abc-123"},
+				Types:         []models.DataType{models.Text},
+			},
+			checkQuery: func(t *testing.T, query string, args []any) {
+				q := strings.ToLower(query)
+
+				wherePart := q[strings.Index(q, "where"):]
+				require.Contains(t, wherePart, "client_side_id in ($2)")
+				require.Contains(t, wherePart, "type in ($3)")
+
+				require.Len(t, args, 3)
+				require.Equal(t, "abc-123", args[1])
+				require.Equal(t, models.Text, args[2])
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
 
-			query, args, err := buildGetPrivateDataQuery(ctx, tt.req)
+			query, args, err := buildGetPrivateDataQuery(ctx, tt.req, config.DefaultMaxClientSideIDs)
 
 			require.NoError(t, err)
 			require.NotEmpty(t, query)
@@ -467,7 +539,7 @@ func Test_buildGetStatesSyncQuery_SQLContainsParts(t *testing.T) {
 				query2, args2, err2 := buildGetStatesSyncQuery(context.Background(), models.SyncRequest{
 					UserID:        99,
 					ClientSideIDs: []string{"x-1", "x-2"},
-				})
+				}, config.DefaultMaxClientSideIDs)
 				require.NoError(t, err2)
 				require.Equal(t, query, query2)
 				require.Equal(t, args, args2)
@@ -479,7 +551,7 @@ func Test_buildGetStatesSyncQuery_SQLContainsParts(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
 
-			query, args, err := buildGetStatesSyncQuery(ctx, tt.req)
+			query, args, err := buildGetStatesSyncQuery(ctx, tt.req, config.DefaultMaxClientSideIDs)
 
 			require.NoError(t, err)
 			require.NotEmpty(t, query)
@@ -490,6 +562,51 @@ func Test_buildGetStatesSyncQuery_SQLContainsParts(t *testing.T) {
 	}
 }
 
+func Test_buildQueries_ClientSideIDsCap(t *testing.T) {
+	const maxIDs = 3
+	ids := func(n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = "cid-" + strconv.Itoa(i)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		count   int
+		maxIDs  int
+		wantErr bool
+	}{
+		{name: "below the cap", count: maxIDs - 1, maxIDs: maxIDs},
+		{name: "exactly at the cap", count: maxIDs, maxIDs: maxIDs},
+		{name: "one over the cap", count: maxIDs + 1, maxIDs: maxIDs, wantErr: true},
+		{name: "cap disabled", count: maxIDs + 1, maxIDs: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			_, args, err := buildGetPrivateDataQuery(ctx, models.DownloadRequest{UserID: 1, ClientSideIDs: ids(tt.count)}, tt.maxIDs)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrTooManyClientSideIDs)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, args, tt.count+1)
+			}
+
+			_, args, err = buildGetStatesSyncQuery(ctx, models.SyncRequest{UserID: 1, ClientSideIDs: ids(tt.count)}, tt.maxIDs)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrTooManyClientSideIDs)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, args, tt.count+1)
+			}
+		})
+	}
+}
+
 func Test_buildUpdateQuery_SQLContainsParts(t *testing.T) {
 	userID := int64(42)
 
@@ -684,3 +801,10 @@ func Test_buildUpdateQuery_SQLContainsParts(t *testing.T) {
 		})
 	}
 }
+
+func Test_restorePrivateDataQuery_OnlyRestoresTombstones(t *testing.T) {
+	update := restorePrivateDataQuery[strings.Index(restorePrivateDataQuery, "UPDATE ciphers"):strings.Index(restorePrivateDataQuery, "RETURNING id")]
+
+	assert.Contains(t, update, "AND deleted = TRUE", "a live record must never match the restore")
+	assert.Contains(t, restorePrivateDataQuery, "AS current_deleted")
+}
@@ -53,10 +53,24 @@ func NewConnectSQLite(ctx context.Context, cfg config.ClientDB, log *logger.Logg
 	return db, nil
 }
 
+// setQueryOnly makes every later statement on db fail if it would modify the
+// database. SQLite applies the pragma per connection, so the pool is limited
+// to the single connection the pragma was set on.
+func (db *DB) setQueryOnly(ctx context.Context) error {
+	db.DB.SetMaxOpenConns(1)
+	if _, err := db.DB.ExecContext(ctx, "PRAGMA query_only = ON;"); err != nil {
+		db.logger.Err(err).Str("func", "DB.setQueryOnly").Msg("error enabling query_only")
+		return err
+	}
+	return nil
+}
+
+// createLocalDBFileIfNotExists creates dbFile with mode 0600, readable only
+// by its owner, if it does not exist yet.
 func createLocalDBFileIfNotExists(dbFile string) error {
 	if _, err := os.Stat(dbFile); os.IsNotExist(err) {
 		// if not found - create
-		f, err := os.Create(dbFile)
+		f, err := os.OpenFile(dbFile, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
 		if err != nil {
 			return fmt.Errorf("error creating DB file: %w", err)
 		}
@@ -58,7 +58,7 @@ func NewPrivateDataStorage(db *DB, cfg config.Storage, logger *logger.Logger) Pr
 
 	storage := new(privateDataStorage)
 
-	repository := NewPrivateDataRepository(db, logger)
+	repository := NewPrivateDataRepository(db, cfg, logger)
 	storage.repository = repository
 	storage.logger = logger
 
@@ -116,8 +116,8 @@ func (p *privateDataStorage) GetAll(
 	return p.repository.GetAllPrivateData(ctx, userID)
 }
 
-// GetAllStates returns lightweight state descriptors for all vault items
-// owned by the specified user.
+// GetAllStates returns one page of lightweight state descriptors for the
+// vault items owned by request.UserID.
 //
 // The returned slice contains only identity and change-detection fields
 // (ClientSideID, Hash, Version, Deleted, UpdatedAt).
@@ -129,9 +129,9 @@ func (p *privateDataStorage) GetAll(
 // Delegates to [PrivateDataRepository.GetAllStates].
 func (p *privateDataStorage) GetAllStates(
 	ctx context.Context,
-	userID int64,
+	request models.StatesPageRequest,
 ) ([]models.PrivateDataState, error) {
-	return p.repository.GetAllStates(ctx, userID)
+	return p.repository.GetAllStates(ctx, request)
 }
 
 // GetStates returns lightweight state descriptors for a subset of vault items
@@ -174,8 +174,8 @@ func (p *privateDataStorage) Update(
 //   - Increments Version,
 //   - Preserves the row for synchronization consistency.
 //
-// Physical deletion is intentionally avoided to ensure clients can
-// detect removals during synchronization cycles.
+// When [config.Storage.HardDelete] is set the rows are removed instead, and
+// clients detect the removal by the item's absence from the sync states.
 //
 // Delegates to [PrivateDataRepository.DeletePrivateData].
 func (p *privateDataStorage) Delete(
@@ -184,3 +184,35 @@ func (p *privateDataStorage) Delete(
 ) error {
 	return p.repository.DeletePrivateData(ctx, deleteRequests)
 }
+
+// Restore clears the deleted flag of one or more soft-deleted vault items
+// and increments their versions, so that clients pick them up again during
+// sync.
+//
+// Delegates to [PrivateDataRepository.RestorePrivateData].
+func (p *privateDataStorage) Restore(
+	ctx context.Context,
+	restoreRequests models.RestoreRequest,
+) error {
+	return p.repository.RestorePrivateData(ctx, restoreRequests)
+}
+
+// GetAllClientSideIDs returns the client-side ID of every vault item of
+// userID, tombstones included.
+//
+// Delegates to [PrivateDataRepository.GetAllClientSideIDs].
+func (p *privateDataStorage) GetAllClientSideIDs(ctx context.Context, userID int64) ([]models.ClientSideIDState, error) {
+	return p.repository.GetAllClientSideIDs(ctx, userID)
+}
+
+// GetVersionHistory returns the archived previous versions of a vault item,
+// newest first.
+//
+// Delegates to [PrivateDataRepository.GetVersionHistory].
+func (p *privateDataStorage) GetVersionHistory(
+	ctx context.Context,
+	userID int64,
+	clientSideID string,
+) ([]models.PrivateDataVersion, error) {
+	return p.repository.GetVersionHistory(ctx, userID, clientSideID)
+}
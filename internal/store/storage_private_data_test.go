@@ -24,6 +24,8 @@ type mockPrivateDataRepository struct {
 	saveErr         error
 	getResult       []models.PrivateData
 	getErr          error
+	getSingleResult models.PrivateData
+	getSingleErr    error
 	getAllResult    []models.PrivateData
 	getAllErr       error
 	allStatesResult []models.PrivateDataState
@@ -32,6 +34,9 @@ type mockPrivateDataRepository struct {
 	statesErr       error
 	updateErr       error
 	deleteErr       error
+	restoreErr      error
+	historyResult   []models.PrivateDataVersion
+	historyErr      error
 }
 
 func (m *mockPrivateDataRepository) SavePrivateData(_ context.Context, _ ...*models.PrivateData) error {
@@ -40,10 +45,13 @@ func (m *mockPrivateDataRepository) SavePrivateData(_ context.Context, _ ...*mod
 func (m *mockPrivateDataRepository) GetPrivateData(_ context.Context, _ models.DownloadRequest) ([]models.PrivateData, error) {
 	return m.getResult, m.getErr
 }
+func (m *mockPrivateDataRepository) GetSinglePrivateData(_ context.Context, _ int64, _ string) (models.PrivateData, error) {
+	return m.getSingleResult, m.getSingleErr
+}
 func (m *mockPrivateDataRepository) GetAllPrivateData(_ context.Context, _ int64) ([]models.PrivateData, error) {
 	return m.getAllResult, m.getAllErr
 }
-func (m *mockPrivateDataRepository) GetAllStates(_ context.Context, _ int64) ([]models.PrivateDataState, error) {
+func (m *mockPrivateDataRepository) GetAllStates(_ context.Context, _ models.StatesPageRequest) ([]models.PrivateDataState, error) {
 	return m.allStatesResult, m.allStatesErr
 }
 func (m *mockPrivateDataRepository) GetStates(_ context.Context, _ models.SyncRequest) ([]models.PrivateDataState, error) {
@@ -55,6 +63,15 @@ func (m *mockPrivateDataRepository) UpdatePrivateData(_ context.Context, _ model
 func (m *mockPrivateDataRepository) DeletePrivateData(_ context.Context, _ models.DeleteRequest) error {
 	return m.deleteErr
 }
+func (m *mockPrivateDataRepository) RestorePrivateData(_ context.Context, _ models.RestoreRequest) error {
+	return m.restoreErr
+}
+func (m *mockPrivateDataRepository) GetVersionHistory(_ context.Context, _ int64, _ string) ([]models.PrivateDataVersion, error) {
+	return m.historyResult, m.historyErr
+}
+func (m *mockPrivateDataRepository) GetAllClientSideIDs(_ context.Context, _ int64) ([]models.ClientSideIDState, error) {
+	return nil, nil
+}
 
 // ─────────────────────────────────────────────
 // Helper
@@ -218,7 +235,7 @@ func TestGetAllStates_Success(t *testing.T) {
 	}
 	s := newStorageWithMock(&mockPrivateDataRepository{allStatesResult: expected})
 
-	result, err := s.GetAllStates(context.Background(), 1)
+	result, err := s.GetAllStates(context.Background(), models.StatesPageRequest{UserID: 1})
 
 	require.NoError(t, err)
 	require.Len(t, result, 1)
@@ -230,7 +247,7 @@ func TestGetAllStates_Error(t *testing.T) {
 	expected := errors.New("states failed")
 	s := newStorageWithMock(&mockPrivateDataRepository{allStatesErr: expected})
 
-	_, err := s.GetAllStates(context.Background(), 1)
+	_, err := s.GetAllStates(context.Background(), models.StatesPageRequest{UserID: 1})
 
 	assert.ErrorIs(t, err, expected)
 }
@@ -238,7 +255,7 @@ func TestGetAllStates_Error(t *testing.T) {
 func TestGetAllStates_Empty(t *testing.T) {
 	s := newStorageWithMock(&mockPrivateDataRepository{allStatesResult: []models.PrivateDataState{}})
 
-	result, err := s.GetAllStates(context.Background(), 1)
+	result, err := s.GetAllStates(context.Background(), models.StatesPageRequest{UserID: 1})
 
 	require.NoError(t, err)
 	assert.Empty(t, result)
@@ -332,3 +349,24 @@ func TestDelete_EmptyEntries(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+// ─────────────────────────────────────────────
+// Restore
+// ─────────────────────────────────────────────
+
+func TestRestore_Success(t *testing.T) {
+	s := newStorageWithMock(&mockPrivateDataRepository{})
+
+	err := s.Restore(context.Background(), models.RestoreRequest{UserID: 1})
+
+	assert.NoError(t, err)
+}
+
+func TestRestore_Error(t *testing.T) {
+	expected := errors.New("restore failed")
+	s := newStorageWithMock(&mockPrivateDataRepository{restoreErr: expected})
+
+	err := s.Restore(context.Background(), models.RestoreRequest{})
+
+	assert.ErrorIs(t, err, expected)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
+	"github.com/MKhiriev/go-pass-keeper/migrations"
 )
 
 // Storages is a top-level container that aggregates all storage abstractions
@@ -27,6 +28,22 @@ type Storages struct {
 	// coordinating between relational storage and (in the future) file storage.
 	// See [PrivateDataStorage] for the full method contract.
 	PrivateDataStorage PrivateDataStorage
+
+	// AuditLogRepository provides read access to the audit trail of vault
+	// mutations. See [AuditLogRepository] for the full method contract.
+	AuditLogRepository AuditLogRepository
+
+	// SyncActivityRepository records when each user last synced.
+	// See [SyncActivityRepository] for the full method contract.
+	SyncActivityRepository SyncActivityRepository
+
+	// SchemaRepository reports the applied migration version.
+	// See [SchemaRepository] for the full method contract.
+	SchemaRepository SchemaRepository
+
+	// HardDelete is set when PrivateDataStorage removes deleted items
+	// instead of keeping tombstones; see [config.Storage.HardDelete].
+	HardDelete bool
 }
 
 // NewStorages initialises all storage dependencies and returns a ready-to-use
@@ -34,9 +51,12 @@ type Storages struct {
 //
 // The function performs the following steps in order:
 //  1. Opens and verifies a PostgreSQL connection using [NewConnectPostgres].
-//  2. Runs pending database migrations via [DB.Migrate].
-//  3. Constructs [UserRepository] and [PrivateDataStorage] backed by the
-//     established connection.
+//  2. Runs pending database migrations via [DB.Migrate] and, unless
+//     cfg.SkipSchemaCheck is set, verifies the resulting schema with
+//     [SchemaRepository.CheckSchema].
+//  3. Constructs [UserRepository], [PrivateDataStorage],
+//     [AuditLogRepository], [SyncActivityRepository] and [SchemaRepository]
+//     backed by the established connection.
 //
 // If any step fails, a descriptive wrapped error is returned and the caller
 // should treat the application as unable to start.
@@ -57,8 +77,36 @@ func NewStorages(cfg config.Storage, logger *logger.Logger) (*Storages, error) {
 		return nil, fmt.Errorf("migration failed: %w", err)
 	}
 
+	schema := NewSchemaRepository(db, logger)
+	if !cfg.SkipSchemaCheck {
+		if err := schema.CheckSchema(context.Background()); err != nil {
+			return nil, fmt.Errorf("schema check failed: %w", err)
+		}
+	}
+
 	return &Storages{
-		UserRepository:     NewUserRepository(db, logger),
-		PrivateDataStorage: NewPrivateDataStorage(db, cfg, logger),
+		UserRepository:         NewUserRepository(db, logger),
+		PrivateDataStorage:     NewPrivateDataStorage(db, cfg, logger),
+		AuditLogRepository:     NewAuditLogRepository(db, logger),
+		SyncActivityRepository: NewSyncActivityRepository(db, logger),
+		SchemaRepository:       schema,
+		HardDelete:             cfg.HardDelete,
 	}, nil
 }
+
+// PendingMigrations connects to the database in cfg and returns the migrations
+// [NewStorages] would apply on startup, without applying them. The connection
+// is closed before returning.
+func PendingMigrations(ctx context.Context, cfg config.Storage, logger *logger.Logger) ([]migrations.Migration, error) {
+	db, err := NewConnectPostgres(ctx, cfg.DB, logger)
+	if err != nil {
+		return nil, fmt.Errorf("postgres connection error: %w", err)
+	}
+	defer db.Close()
+
+	pending, err := db.PendingMigrations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list pending migrations: %w", err)
+	}
+	return pending, nil
+}
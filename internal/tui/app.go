@@ -23,7 +23,9 @@ type RootModel struct {
 	quitByUser bool
 	resultID   int64
 	resultKey  []byte
-	buildInfo  models.AppBuildInfo
+	// resultWeakPassword carries [LoginResult.WeakPassword] to [TUI.LoginFlow].
+	resultWeakPassword bool
+	buildInfo          models.AppBuildInfo
 
 	showBuildInfo bool
 }
@@ -101,6 +103,7 @@ func (r RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			setSessionUserID(result.UserID)
 			r.resultID = result.UserID
 			r.resultKey = result.EncryptionKey
+			r.resultWeakPassword = result.WeakPassword
 			return r, tea.Quit
 		}
 	}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// binaryPreviewUnavailable is shown instead of the preview pane when the file
+// is not text or its content was not embedded; see [service.BinaryPreview].
+const binaryPreviewUnavailable = "бинарный файл, предпросмотр недоступен"
+
+const (
+	binaryPreviewWidth  = 76
+	binaryPreviewHeight = 16
+)
+
+// startBinaryPreview opens the scrollable preview pane of a Binary entry, or
+// reports in the status line why there is nothing to preview.
+func (m *mainLoopModel) startBinaryPreview(item models.DecipheredPayload) {
+	text, ok := service.BinaryPreview(item.BinaryData)
+	if !ok {
+		m.status = binaryPreviewUnavailable
+		return
+	}
+	pane := viewport.New(binaryPreviewWidth, binaryPreviewHeight)
+	pane.SetContent(text)
+	m.previewPane = pane
+	m.preview = true
+	m.status = ""
+}
+
+// updateBinaryPreview scrolls the preview pane; esc returns to the detail
+// view.
+func (m mainLoopModel) updateBinaryPreview(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if keyMsg.String() == "esc" {
+		m.preview = false
+		m.previewPane = viewport.Model{}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.previewPane, cmd = m.previewPane.Update(keyMsg)
+	return m, cmd
+}
+
+func (m mainLoopModel) viewBinaryPreview(item models.DecipheredPayload) string {
+	name := item.Metadata.Name
+	if item.BinaryData != nil && item.BinaryData.FileName != "" {
+		name = item.BinaryData.FileName
+	}
+	return renderPage("ПРЕДПРОСМОТР: "+name, m.previewPane.View(), "↑/↓ pgup/pgdn: прокрутка │ esc: назад")
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// conflictColumnWidth is the width of each side of the conflict screen.
+const conflictColumnWidth = 40
+
+type conflictLoadedMsg struct {
+	versions models.ConflictVersions
+	err      error
+}
+
+type conflictResolvedMsg struct {
+	err error
+}
+
+// startConflicts opens the conflict screen for the conflicts a sync left to
+// the user, if err reports any. The rest of the sync went through, so the
+// list is reloaded as well.
+func (m mainLoopModel) startConflicts(err error) (tea.Model, tea.Cmd, bool) {
+	conflicts, ok := service.PendingConflictsOf(err)
+	if !ok || len(conflicts) == 0 {
+		return m, nil, false
+	}
+	m.conflicts = conflicts
+	m.conflict = nil
+	m.status = fmt.Sprintf("Конфликтов версий: %d", len(conflicts))
+	m.errMsg = ""
+	m.loading = true
+	return m, tea.Batch(m.cmdLoadItems(), m.cmdLoadConflict(conflicts[0])), true
+}
+
+func (m mainLoopModel) conflictLoaded(msg conflictLoadedMsg) (tea.Model, tea.Cmd) {
+	if isCanceled(msg.err) {
+		m.conflicts = nil
+		return m, nil
+	}
+	if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+		m.conflicts = nil
+		return m.reauthenticate()
+	}
+	if msg.err != nil {
+		// The item cannot be shown; skip it, the next sync settles it.
+		m.errMsg = fmt.Sprintf("Ошибка загрузки конфликта: %v", msg.err)
+		return m.nextConflict()
+	}
+	m.conflict = &msg.versions
+	return m, nil
+}
+
+func (m mainLoopModel) conflictResolved(msg conflictResolvedMsg) (tea.Model, tea.Cmd) {
+	m.conflictSaving = false
+	if isCanceled(msg.err) {
+		m.status = "Разрешение конфликта: " + statusCanceled
+		return m, nil
+	}
+	if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+		m.conflict = nil
+		m.conflicts = nil
+		return m.reauthenticate()
+	}
+	if msg.err != nil {
+		m.errMsg = fmt.Sprintf("Ошибка разрешения конфликта: %v", msg.err)
+		return m, nil
+	}
+	m.errMsg = ""
+	return m.nextConflict()
+}
+
+// nextConflict drops the current conflict and loads the next one. Once all
+// are settled a sync uploads what the choices left to push, such as the
+// copies made by "keep both".
+func (m mainLoopModel) nextConflict() (tea.Model, tea.Cmd) {
+	m.conflict = nil
+	if len(m.conflicts) > 0 {
+		m.conflicts = m.conflicts[1:]
+	}
+	if len(m.conflicts) > 0 {
+		return m, m.cmdLoadConflict(m.conflicts[0])
+	}
+	m.conflicts = nil
+	m.status = "Конфликты разрешены"
+	m.syncing = true
+	return m, m.cmdSync()
+}
+
+func (m mainLoopModel) updateConflict(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.conflictSaving {
+		return m, nil
+	}
+	var choice models.ConflictChoice
+	switch keyMsg.String() {
+	case "1":
+		choice = models.ConflictKeepLocal
+	case "2":
+		choice = models.ConflictKeepServer
+	case "3":
+		choice = models.ConflictKeepBoth
+	case "esc":
+		// Nothing is written: the sync leaves the items alone and reports
+		// the conflicts again until they are resolved.
+		m.conflict = nil
+		m.conflicts = nil
+		m.errMsg = ""
+		m.status = "Конфликты отложены: они будут показаны снова при следующей синхронизации"
+		return m, nil
+	default:
+		return m, nil
+	}
+	m.conflictSaving = true
+	m.errMsg = ""
+	return m, m.cmdResolveConflict(m.conflict.Conflict.ClientSideID, choice)
+}
+
+func (m mainLoopModel) viewConflict() string {
+	versions := m.conflict
+
+	out := fmt.Sprintf("Запись «%s» изменена и на этом устройстве, и на другом.\n", versions.Local.Metadata.Name)
+	if left := len(m.conflicts) - 1; left > 0 {
+		out += fmt.Sprintf("Ещё конфликтов: %d\n", left)
+	}
+	out += "\n"
+
+	column := lipgloss.NewStyle().Width(conflictColumnWidth).MarginRight(2)
+	out += lipgloss.JoinHorizontal(lipgloss.Top,
+		column.Render(viewConflictSide("[ НА ЭТОМ УСТРОЙСТВЕ ]", versions.Local)),
+		column.Render(viewConflictSide(fmt.Sprintf("[ НА СЕРВЕРЕ, версия %d ]", versions.Conflict.ServerVersion), versions.Server)),
+	) + "\n"
+
+	if m.conflictSaving {
+		out += "\n[Сохранение...]\n"
+	}
+	if m.errMsg != "" {
+		out += "\n" + errorLine(m.errMsg) + "\n"
+	}
+	return renderPage("КОНФЛИКТ СИНХРОНИЗАЦИИ", strings.TrimRight(out, "\n"),
+		"1: оставить локальную │ 2: оставить серверную │ 3: оставить обе │ esc: отложить")
+}
+
+// viewConflictSide renders one version of a conflicting item. Secrets stay
+// masked: the sides are told apart by their visible fields.
+func viewConflictSide(title string, item models.DecipheredPayload) string {
+	var b strings.Builder
+	b.WriteString(title + "\n")
+	b.WriteString("Название  : " + item.Metadata.Name + "\n")
+	b.WriteString("Папка     : " + valueOrDash(item.Metadata.Folder) + "\n")
+
+	switch {
+	case item.LoginData != nil:
+		b.WriteString("Логин     : " + valueOrDash(&item.LoginData.Username) + "\n")
+		b.WriteString("Пароль    : " + maskSecret(item.LoginData.Password, false) + "\n")
+		for _, uri := range item.LoginData.URIs {
+			b.WriteString("URI       : " + uri.URI + "\n")
+		}
+	case item.TextData != nil:
+		b.WriteString("Текст     : " + firstLine(item.TextData.Text) + "\n")
+	case item.BinaryData != nil:
+		b.WriteString("Файл      : " + valueOrDash(&item.BinaryData.FileName) + "\n")
+		b.WriteString("Размер    : " + formatSize(item.BinaryData.Size) + "\n")
+	case item.BankCardData != nil:
+		b.WriteString("Держатель : " + valueOrDash(&item.BankCardData.CardholderName) + "\n")
+		b.WriteString("Номер     : " + maskCardNumber(item.BankCardData.Number, false) + "\n")
+		b.WriteString("Срок      : " + item.BankCardData.ExpMonth + "/" + item.BankCardData.ExpYear + "\n")
+	}
+	if item.Notes != nil && strings.TrimSpace(item.Notes.Notes) != "" {
+		b.WriteString("Заметки   : " + firstLine(item.Notes.Notes) + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (m mainLoopModel) cmdLoadConflict(conflict models.VersionConflict) tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.SyncService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return conflictLoadedMsg{err: errUserIDNotSet}
+		}
+		versions, err := svc.LoadConflict(ctx, userID, conflict)
+		return conflictLoadedMsg{versions: versions, err: err}
+	}
+}
+
+func (m mainLoopModel) cmdResolveConflict(clientSideID string, choice models.ConflictChoice) tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.SyncService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return conflictResolvedMsg{err: errUserIDNotSet}
+		}
+		return conflictResolvedMsg{err: svc.ResolveConflict(ctx, userID, clientSideID, choice)}
+	}
+}
+
+// firstLine returns the first line of text, cut to fit a conflict column.
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return fitText(line, conflictColumnWidth-12)
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"context"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pingTimeout bounds a single connectivity check, so that a server that
+// accepts the connection but never answers is shown as unreachable.
+const pingTimeout = 5 * time.Second
+
+// connectivity is the server reachability shown in the main page header.
+type connectivity int
+
+const (
+	// connUnknown is shown until the first check finished; the header has
+	// no indicator then.
+	connUnknown connectivity = iota
+	connOnline
+	connOffline
+)
+
+// pingDueMsg starts the next periodic connectivity check.
+type pingDueMsg struct{}
+
+// pingDoneMsg reports a connectivity check started by [mainLoopModel.cmdPing].
+type pingDoneMsg struct {
+	err     error
+	breaker models.SyncBreakerStatus
+}
+
+// cmdPing checks that the server is reachable. The check is bound to the
+// model's context, so quitting aborts it.
+func (m mainLoopModel) cmdPing() tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.SyncService
+
+	return func() tea.Msg {
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		defer cancel()
+		err := svc.Ping(pingCtx)
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return pingDoneMsg{err: err, breaker: svc.BreakerStatus()}
+	}
+}
+
+// schedulePing returns the command that starts the next connectivity check
+// after the configured interval, or nil when the checks are disabled.
+func (m mainLoopModel) schedulePing() tea.Cmd {
+	if m.pingInterval <= 0 || m.offline {
+		return nil
+	}
+	return tea.Tick(m.pingInterval, func(time.Time) tea.Msg { return pingDueMsg{} })
+}
+
+// handlePing applies the outcome of a connectivity check and schedules the
+// next one. A check aborted by quitting schedules nothing.
+func (m mainLoopModel) handlePing(msg pingDoneMsg) (tea.Model, tea.Cmd) {
+	if isCanceled(msg.err) {
+		return m, nil
+	}
+	m.syncBreaker = msg.breaker
+	if msg.err != nil {
+		m.connectivity = connOffline
+	} else {
+		m.connectivity = connOnline
+	}
+	return m, m.schedulePing()
+}
+
+// label is the header suffix of the connection indicator.
+func (c connectivity) label() string {
+	switch c {
+	case connOnline:
+		return "в сети"
+	case connOffline:
+		return "нет связи с сервером"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// customFields manages the custom field inputs of a form. Each field takes
+// two consecutive inputs, its name and its value, the last ones of the
+// form's input slice starting at start, so tab navigation covers them like
+// any other field. types and sensitive hold the type and the
+// [models.CustomField.IsSensitive] flag of each field; initial is sensitive
+// as the form was opened with.
+type customFields struct {
+	start     int
+	types     []models.DataType
+	sensitive []bool
+	initial   []bool
+}
+
+// newCustomFields appends a name and a value input per field of fields to
+// inputs, or a single empty pair when there are none.
+func newCustomFields(inputs []textinput.Model, fields []models.CustomField) ([]textinput.Model, customFields) {
+	f := customFields{start: len(inputs)}
+	for _, field := range fields {
+		name, value := newCustomFieldInputs(field.IsSensitive)
+		name.SetValue(field.Name)
+		value.SetValue(string(field.Data))
+		inputs = append(inputs, name, value)
+		f.types = append(f.types, field.Type)
+		f.sensitive = append(f.sensitive, field.IsSensitive)
+	}
+	if len(f.types) == 0 {
+		name, value := newCustomFieldInputs(false)
+		inputs = append(inputs, name, value)
+		f.types = append(f.types, models.Text)
+		f.sensitive = append(f.sensitive, false)
+	}
+	f.initial = slices.Clone(f.sensitive)
+	return inputs, f
+}
+
+// sensitiveChanged reports whether a field was marked as sensitive or not
+// since the form was opened.
+func (f customFields) sensitiveChanged() bool {
+	return !slices.Equal(f.sensitive, f.initial)
+}
+
+func newCustomFieldInputs(sensitive bool) (name, value textinput.Model) {
+	name = textinput.New()
+	name.Placeholder = "Название поля"
+	name.Width = 40
+
+	value = textinput.New()
+	value.Placeholder = "Значение"
+	value.Width = 40
+	setSensitiveEcho(&value, sensitive)
+	return name, value
+}
+
+// setSensitiveEcho hides the typed value of a sensitive field.
+func setSensitiveEcho(input *textinput.Model, sensitive bool) {
+	input.EchoMode = textinput.EchoNormal
+	if sensitive {
+		input.EchoMode = textinput.EchoPassword
+		input.EchoCharacter = '*'
+	}
+}
+
+// handleKey applies the custom field keys while an input of a field has the
+// focus: ctrl+n adds a field below the focused one, ctrl+x removes the
+// focused one and ctrl+t marks it as sensitive or not. It returns the
+// updated inputs and focus, and false when key is not a field key or the
+// focus is elsewhere.
+func (f *customFields) handleKey(key string, inputs []textinput.Model, focus int) ([]textinput.Model, int, bool) {
+	if focus < f.start {
+		return inputs, focus, false
+	}
+	i := (focus - f.start) / 2
+	if i >= len(f.types) {
+		return inputs, focus, false
+	}
+	first := f.start + 2*i
+
+	switch key {
+	case "ctrl+n":
+		name, value := newCustomFieldInputs(false)
+		inputs[focus].Blur()
+		inputs = slices.Insert(inputs, first+2, name, value)
+		f.types = slices.Insert(f.types, i+1, models.Text)
+		f.sensitive = slices.Insert(f.sensitive, i+1, false)
+		focus = first + 2
+	case "ctrl+x":
+		// The last remaining field is cleared instead, so there is always
+		// a field to type into.
+		if len(f.types) == 1 {
+			inputs[first].SetValue("")
+			inputs[first+1].SetValue("")
+			f.types[0] = models.Text
+			f.sensitive[0] = false
+			setSensitiveEcho(&inputs[first+1], false)
+			return inputs, focus, true
+		}
+		inputs = slices.Delete(inputs, first, first+2)
+		f.types = slices.Delete(f.types, i, i+1)
+		f.sensitive = slices.Delete(f.sensitive, i, i+1)
+		if i >= len(f.types) {
+			i--
+		}
+		focus = f.start + 2*i
+	case "ctrl+t":
+		f.sensitive[i] = !f.sensitive[i]
+		setSensitiveEcho(&inputs[first+1], f.sensitive[i])
+		return inputs, focus, true
+	default:
+		return inputs, focus, false
+	}
+
+	inputs[focus].Focus()
+	return inputs, focus, true
+}
+
+// collect returns the fields with a name or a value, or nil when there are
+// none.
+func (f customFields) collect(inputs []textinput.Model) *[]models.CustomField {
+	var fields []models.CustomField
+	for i := range f.types {
+		name := strings.TrimSpace(inputs[f.start+2*i].Value())
+		value := inputs[f.start+2*i+1].Value()
+		if name == "" && strings.TrimSpace(value) == "" {
+			continue
+		}
+		fields = append(fields, models.CustomField{
+			Name:        name,
+			Type:        f.types[i],
+			Data:        models.CipheredData(value),
+			IsSensitive: f.sensitive[i],
+		})
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &fields
+}
+
+// view renders the name and the value input of each field, numbered when
+// there is more than one. sep separates the label from the input, matching
+// the layout of the surrounding form.
+func (f customFields) view(inputs []textinput.Model, sep string) string {
+	var b strings.Builder
+	for i := range f.types {
+		label := "Поле"
+		if len(f.types) > 1 {
+			label = fmt.Sprintf("Поле %d", i+1)
+		}
+		line := fmt.Sprintf("%-10s%s [ %s ] = [ %s ]", label, sep, inputs[f.start+2*i].View(), inputs[f.start+2*i+1].View())
+		if f.sensitive[i] {
+			line += " секретное"
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// fieldHotKeys documents the custom field keys of a form.
+const fieldHotKeys = "ctrl+n: доб. поле │ ctrl+x: уд. поле │ ctrl+t: секретное"
+
+// uriFieldHotKeys documents the keys of a login form, where ctrl+n, ctrl+x
+// and ctrl+t act on the URI or the custom field that has the focus.
+const uriFieldHotKeys = "ctrl+n: доб. URI/поле │ ctrl+x: уд. URI/поле │ ctrl+t: правило URI/секретное"
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// readOnlyKeys are the list and detail keys that modify the current entry;
+// they are refused for entries of a type that is not enabled.
+var readOnlyKeys = map[string]bool{
+	"e":      true,
+	"ctrl+d": true,
+	"f":      true,
+	"x":      true,
+}
+
+// isReadOnly reports whether item is of a type left out of the enabled types
+// ([config.ClientApp.EnabledDataTypes]). Such entries were added before the
+// type was disabled, or on another device; they can be viewed and copied
+// but not changed.
+func (m mainLoopModel) isReadOnly(item models.DecipheredPayload) bool {
+	if m.isUndecryptable(item) || !slices.Contains(config.AllDataTypes(), item.Type) {
+		return false
+	}
+	return !slices.Contains(m.addTypeOptions, item.Type)
+}
+
+func readOnlyStatus(dataType models.DataType) string {
+	return fmt.Sprintf("Тип «%s» отключён, запись доступна только для просмотра", dataTypeLabel(dataType))
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// discardPrompt is shown when esc is pressed on a form with unsaved input.
+const discardPrompt = "Отменить изменения? y/n"
+
+// hasUnsavedChanges reports whether the open add or edit form holds input
+// that esc would throw away. An add form is dirty once anything was typed or
+// a stage past the name was reached; an edit form is dirty once any input
+// differs from the values it was opened with.
+func (m mainLoopModel) hasUnsavedChanges() bool {
+	if m.editing {
+		if m.editReprompt != m.editPayload.Metadata.Reprompt || m.editFields.sensitiveChanged() {
+			return true
+		}
+		values := inputValues(m.editInputs)
+		if len(values) != len(m.editOriginal) {
+			return true
+		}
+		for i := range values {
+			if values[i] != m.editOriginal[i] {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch m.addStage {
+	case addStageNone, addStageType:
+		return false
+	case addStageMeta:
+		if len(m.addMetaInputs) < 2 {
+			return false
+		}
+		return strings.TrimSpace(m.addMetaInputs[0].Value()) != "" ||
+			strings.TrimSpace(m.addMetaInputs[1].Value()) != strings.TrimSpace(m.defaultFolder) ||
+			m.addFields.collect(m.addMetaInputs) != nil
+	default:
+		// Leaving the meta stage requires a name, so later stages always
+		// hold input.
+		return true
+	}
+}
+
+// handleFormEsc intercepts keys of the add and edit forms while a discard is
+// pending or esc is pressed on a dirty form. It reports false when the key
+// must go to the form itself.
+func (m mainLoopModel) handleFormEsc(keyMsg tea.KeyMsg) (mainLoopModel, bool) {
+	if m.confirmDiscard {
+		switch keyMsg.String() {
+		case "y":
+			m.confirmDiscard = false
+			m.discardForm()
+		case "n", "esc":
+			m.confirmDiscard = false
+		}
+		return m, true
+	}
+
+	if keyMsg.String() == "esc" && m.hasUnsavedChanges() {
+		m.confirmDiscard = true
+		return m, true
+	}
+	return m, false
+}
+
+// discardForm closes the open add or edit form the way esc did before the
+// confirmation existed.
+func (m *mainLoopModel) discardForm() {
+	if m.editing {
+		m.editing = false
+		m.editSubmitting = false
+		m.errMsg = ""
+		return
+	}
+	m.resetAddFlow()
+}
+
+func (m mainLoopModel) viewDiscardConfirm() string {
+	return renderPage("ОТМЕНА ИЗМЕНЕНИЙ", discardPrompt, "y: отменить изменения │ n/esc: вернуться к форме")
+}
+
+func inputValues(inputs []textinput.Model) []string {
+	values := make([]string, len(inputs))
+	for i := range inputs {
+		values[i] = inputs[i].Value()
+	}
+	return values
+}
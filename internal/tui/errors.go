@@ -3,13 +3,51 @@
 
 package tui
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// conflictMessage describes a version conflict reported by the server for an
+// update or delete of the current item.
+func conflictMessage(conflict models.VersionConflict) string {
+	operation := "Конфликт при обновлении"
+	if conflict.Operation == models.ConflictOnDelete {
+		operation = "Конфликт при удалении"
+	}
+	msg := operation + ": запись изменена на другом устройстве"
+	if conflict.ServerVersion > 0 {
+		msg += fmt.Sprintf(" (версия на сервере: %d)", conflict.ServerVersion)
+	}
+	return msg + ", выполните синхронизацию"
+}
+
+// validationMessage describes a field of the current item the server rejected.
+func validationMessage(failure models.ValidationFailure) string {
+	if failure.Field == "" {
+		return "Сервер отклонил запись: " + failure.Reason
+	}
+	return fmt.Sprintf("Сервер отклонил поле «%s»: %s", failure.Field, failure.Reason)
+}
 
 func humanizeServerUnavailableError(err error) string {
 	if err == nil {
 		return ""
 	}
 
+	if errors.Is(err, service.ErrInsecureConnection) {
+		return service.ErrInsecureConnection.Error() +
+			". Укажите адрес сервера с https:// или запустите клиент с флагом -insecure (только для локальной разработки)"
+	}
+
+	if errors.Is(err, service.ErrServerNotResponding) {
+		return "Сервер не отвечает, попробуйте позже"
+	}
+
 	s := strings.ToLower(err.Error())
 	if strings.Contains(s, "connection refused") ||
 		strings.Contains(s, "dial tcp") ||
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"github.com/MKhiriev/go-pass-keeper/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// favoriteMark precedes the name of a favorite entry in the list.
+const favoriteMark = "★ "
+
+// favoritesFirst returns items with the favorites moved to the top. The
+// order within the favorites and within the rest is kept.
+func favoritesFirst(items []models.DecipheredPayload) []models.DecipheredPayload {
+	sorted := make([]models.DecipheredPayload, 0, len(items))
+	for _, item := range items {
+		if item.Metadata.Favorite {
+			sorted = append(sorted, item)
+		}
+	}
+	for _, item := range items {
+		if !item.Metadata.Favorite {
+			sorted = append(sorted, item)
+		}
+	}
+	return sorted
+}
+
+// followItem moves the cursor to the entry clientSideID, so that the
+// selection stays on the same entry when a reload reorders the list. The
+// cursor is left alone when the entry is no longer listed, or is hidden in
+// a collapsed folder of the grouped list.
+func (m *mainLoopModel) followItem(clientSideID string) {
+	if clientSideID == "" {
+		return
+	}
+	if m.folderTree {
+		for i, row := range m.treeRows() {
+			if row.folder == nil && row.item.ClientSideID == clientSideID {
+				m.idx = i
+				return
+			}
+		}
+		return
+	}
+	for i, item := range m.visibleItems() {
+		if item.ClientSideID == clientSideID {
+			m.idx = i
+			return
+		}
+	}
+}
+
+// toggleFavorite flips the favorite mark of item.
+func (m mainLoopModel) toggleFavorite(item models.DecipheredPayload) (tea.Model, tea.Cmd) {
+	if m.isUndecryptable(item) {
+		m.status = "Запись не расшифрована, изменение недоступно"
+		return m, nil
+	}
+	if m.keyMissing() {
+		return m.reauthenticate()
+	}
+	m.errMsg = ""
+	return m, m.cmdSetFavorite(item.ClientSideID, !item.Metadata.Favorite)
+}
+
+func (m mainLoopModel) cmdSetFavorite(clientSideID string, favorite bool) tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.PrivateDataService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return favoriteDoneMsg{favorite: favorite, err: errUserIDNotSet}
+		}
+		return favoriteDoneMsg{favorite: favorite, err: svc.SetFavorite(ctx, userID, clientSideID, favorite)}
+	}
+}
+
+// favoriteHotKey is the detail hotkey hint for item.
+func favoriteHotKey(item models.DecipheredPayload) string {
+	if item.Metadata.Favorite {
+		return "f: убрать из избранного"
+	}
+	return "f: в избранное"
+}
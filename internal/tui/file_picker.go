@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+)
+
+// filePickerVisibleRows is the number of directory entries rendered at once;
+// the window scrolls to keep the cursor visible.
+const filePickerVisibleRows = 15
+
+// defaultMaxAttachmentSize is the largest file the add flow accepts when no
+// explicit limit is configured.
+const defaultMaxAttachmentSize = config.DefaultMaxBinarySize
+
+type filePickerEntry struct {
+	name  string
+	isDir bool
+	size  int64
+}
+
+// filePicker is a minimal directory browser used by the add flow to select a
+// file for a Binary entry without typing its path.
+type filePicker struct {
+	dir     string
+	entries []filePickerEntry
+	idx     int
+	offset  int
+	err     string
+	maxSize int64
+}
+
+func newFilePicker(dir string, maxSize int64) filePicker {
+	if dir == "" {
+		if wd, err := os.Getwd(); err == nil {
+			dir = wd
+		} else {
+			dir = string(filepath.Separator)
+		}
+	}
+	if maxSize <= 0 {
+		maxSize = defaultMaxAttachmentSize
+	}
+
+	p := filePicker{maxSize: maxSize}
+	p.open(dir)
+	return p
+}
+
+// open switches the picker to dir and reloads its listing. Directories are
+// listed first, then files, both sorted by name. A ".." entry is prepended
+// unless dir is the filesystem root.
+func (p *filePicker) open(dir string) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		p.err = fmt.Sprintf("не удалось открыть папку: %v", err)
+		return
+	}
+
+	var dirs, files []filePickerEntry
+	for _, e := range dirEntries {
+		if e.IsDir() {
+			dirs = append(dirs, filePickerEntry{name: e.Name(), isDir: true})
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, filePickerEntry{name: e.Name(), size: info.Size()})
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].name < dirs[j].name })
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+
+	entries := make([]filePickerEntry, 0, len(dirs)+len(files)+1)
+	if parent := filepath.Dir(dir); parent != dir {
+		entries = append(entries, filePickerEntry{name: "..", isDir: true})
+	}
+	entries = append(entries, dirs...)
+	entries = append(entries, files...)
+
+	p.dir = dir
+	p.entries = entries
+	p.idx = 0
+	p.offset = 0
+	p.err = ""
+}
+
+func (p *filePicker) up() {
+	if p.idx > 0 {
+		p.idx--
+	}
+	if p.idx < p.offset {
+		p.offset = p.idx
+	}
+}
+
+func (p *filePicker) down() {
+	if p.idx < len(p.entries)-1 {
+		p.idx++
+	}
+	if p.idx >= p.offset+filePickerVisibleRows {
+		p.offset = p.idx - filePickerVisibleRows + 1
+	}
+}
+
+// parent navigates to the parent of the current directory.
+func (p *filePicker) parent() {
+	if parent := filepath.Dir(p.dir); parent != p.dir {
+		p.open(parent)
+	}
+}
+
+// enter activates the entry under the cursor. Directories are opened in place;
+// for files the path is validated and returned with selected=true. On
+// validation failure the picker stays on the current entry and p.err is set.
+func (p *filePicker) enter() (path string, selected bool) {
+	if p.idx < 0 || p.idx >= len(p.entries) {
+		return "", false
+	}
+
+	entry := p.entries[p.idx]
+	if entry.isDir {
+		if entry.name == ".." {
+			p.parent()
+		} else {
+			p.open(filepath.Join(p.dir, entry.name))
+		}
+		return "", false
+	}
+
+	path = filepath.Join(p.dir, entry.name)
+	if err := validateAttachment(path, p.maxSize); err != nil {
+		p.err = err.Error()
+		return "", false
+	}
+
+	p.err = ""
+	return path, true
+}
+
+// validateAttachment checks that path names an existing, readable regular
+// file no larger than maxSize bytes.
+func validateAttachment(path string, maxSize int64) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("файл не найден")
+	}
+	if info.IsDir() {
+		return fmt.Errorf("укажите путь к файлу, а не к папке")
+	}
+	if err := service.ValidateBinarySize(info.Size(), maxSize); err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("файл недоступен для чтения")
+	}
+	_ = f.Close()
+
+	return nil
+}
+
+func (p filePicker) view() string {
+	var b strings.Builder
+
+	b.WriteString("Папка     : " + p.dir + "\n\n")
+	if len(p.entries) == 0 {
+		b.WriteString("(пусто)\n")
+	}
+
+	end := min(p.offset+filePickerVisibleRows, len(p.entries))
+	for i := p.offset; i < end; i++ {
+		entry := p.entries[i]
+		cursor := cursorMark(i == p.idx)
+		if entry.isDir {
+			b.WriteString(fmt.Sprintf("%s %-40s │ %s\n", cursor, fitText(entry.name+"/", 40), "папка"))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%s %-40s │ %s\n", cursor, fitText(entry.name, 40), formatSize(entry.size)))
+	}
+	if len(p.entries) > filePickerVisibleRows {
+		b.WriteString(fmt.Sprintf("\n%d-%d из %d\n", p.offset+1, end, len(p.entries)))
+	}
+
+	b.WriteString("\nМакс. размер: " + formatSize(p.maxSize) + "\n")
+	if p.idx >= 0 && p.idx < len(p.entries) && !p.entries[p.idx].isDir {
+		b.WriteString("Файл      : " + binaryPreview(filepath.Join(p.dir, p.entries[p.idx].name)) + "\n")
+	}
+	if p.err != "" {
+		b.WriteString("\n" + errorLine(p.err) + "\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// folderNode is a folder of the grouped vault list. Folder paths such as
+// "Work/Email" are split on [service.FolderSeparator], so "Email" becomes a
+// child of "Work" even when no entry is stored directly in "Work".
+type folderNode struct {
+	// name is the last level of the path, the one shown in the tree.
+	name string
+	// path is the full normalized path; it identifies the folder when it is
+	// collapsed.
+	path     string
+	children []*folderNode
+	items    []models.DecipheredPayload
+}
+
+// buildFolderTree groups items by their folder paths. The returned root
+// holds the entries without a folder. Sub-folders are sorted by name
+// case-insensitively; the entries of a folder keep the order of items.
+func buildFolderTree(items []models.DecipheredPayload) *folderNode {
+	root := &folderNode{}
+	for _, item := range items {
+		node := root
+		if item.Metadata.Folder != nil {
+			levels := service.FolderPath(*item.Metadata.Folder)
+			for i, level := range levels {
+				node = node.child(level, strings.Join(levels[:i+1], service.FolderSeparator))
+			}
+		}
+		node.items = append(node.items, item)
+	}
+	root.sort()
+	return root
+}
+
+// child returns the sub-folder name of n, adding it when missing.
+func (n *folderNode) child(name, path string) *folderNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	c := &folderNode{name: name, path: path}
+	n.children = append(n.children, c)
+	return c
+}
+
+func (n *folderNode) sort() {
+	sort.SliceStable(n.children, func(i, j int) bool {
+		return strings.ToLower(n.children[i].name) < strings.ToLower(n.children[j].name)
+	})
+	for _, c := range n.children {
+		c.sort()
+	}
+}
+
+// count returns the number of entries in n and all its sub-folders.
+func (n *folderNode) count() int {
+	total := len(n.items)
+	for _, c := range n.children {
+		total += c.count()
+	}
+	return total
+}
+
+// treeRow is a line of the grouped vault list: a folder when folder is set,
+// an entry otherwise.
+type treeRow struct {
+	depth  int
+	folder *folderNode
+	// collapsed is set on the row of a folder whose contents are hidden.
+	collapsed bool
+	item      models.DecipheredPayload
+}
+
+// flattenFolderTree lists the rows of the tree under root in display order:
+// the sub-folders of each folder first, then its entries. The contents of
+// the folders whose paths are in collapsed are left out.
+func flattenFolderTree(root *folderNode, collapsed map[string]bool) []treeRow {
+	var rows []treeRow
+	var walk func(n *folderNode, depth int)
+	walk = func(n *folderNode, depth int) {
+		for _, c := range n.children {
+			rows = append(rows, treeRow{depth: depth, folder: c, collapsed: collapsed[c.path]})
+			if !collapsed[c.path] {
+				walk(c, depth+1)
+			}
+		}
+		for _, item := range n.items {
+			rows = append(rows, treeRow{depth: depth, item: item})
+		}
+	}
+	walk(root, 0)
+	return rows
+}
+
+// treeRows returns the rows of the grouped list. While a search filter is
+// active every folder is expanded, so that no match is hidden.
+func (m mainLoopModel) treeRows() []treeRow {
+	collapsed := m.collapsed
+	if strings.TrimSpace(m.searchQuery) != "" {
+		collapsed = nil
+	}
+	return flattenFolderTree(buildFolderTree(m.visibleItems()), collapsed)
+}
+
+// listLen returns the number of rows the cursor moves over.
+func (m mainLoopModel) listLen() int {
+	if m.folderTree {
+		return len(m.treeRows())
+	}
+	return len(m.visibleItems())
+}
+
+// currentRow returns the row of the grouped list under the cursor.
+func (m mainLoopModel) currentRow() (treeRow, bool) {
+	rows := m.treeRows()
+	if m.idx < 0 || m.idx >= len(rows) {
+		return treeRow{}, false
+	}
+	return rows[m.idx], true
+}
+
+// folderRowKeys are the list keys that act on an entry and do nothing on a
+// folder row.
+var folderRowKeys = map[string]bool{
+	"e":      true,
+	"c":      true,
+	"ctrl+d": true,
+	"x":      true,
+	"f":      true,
+}
+
+// updateFolderTree handles the keys of the grouped list that depend on the
+// row under the cursor: enter toggles a folder, → expands it and ← collapses
+// it or, on a collapsed folder or an entry, moves to the parent folder. It
+// reports whether key was handled.
+func (m mainLoopModel) updateFolderTree(key string) (mainLoopModel, bool) {
+	row, ok := m.currentRow()
+	if !ok {
+		return m, false
+	}
+
+	if row.folder == nil {
+		if key == "left" {
+			m.focusParent(row.depth)
+			return m, true
+		}
+		return m, false
+	}
+
+	path := row.folder.path
+	switch key {
+	case "enter":
+		m.setCollapsed(path, !row.collapsed)
+	case "right":
+		m.setCollapsed(path, false)
+	case "left":
+		if row.collapsed {
+			m.focusParent(row.depth)
+		} else {
+			m.setCollapsed(path, true)
+		}
+	default:
+		if !folderRowKeys[key] {
+			return m, false
+		}
+		m.status = "Выбрана папка: enter — свернуть или развернуть"
+	}
+	return m, true
+}
+
+// setCollapsed collapses or expands the folder path.
+func (m *mainLoopModel) setCollapsed(path string, collapsed bool) {
+	if !collapsed {
+		delete(m.collapsed, path)
+		return
+	}
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[path] = true
+}
+
+// focusParent moves the cursor from a row at depth to the folder row it is
+// listed under. Rows at depth 0 have no parent folder.
+func (m *mainLoopModel) focusParent(depth int) {
+	if depth == 0 {
+		return
+	}
+	rows := m.treeRows()
+	for i := m.idx - 1; i >= 0; i-- {
+		if rows[i].folder != nil && rows[i].depth == depth-1 {
+			m.idx = i
+			return
+		}
+	}
+}
+
+// folderTreeIndent is the indentation of one folder level.
+const folderTreeIndent = "  "
+
+// viewFolderTree renders the grouped list like [viewListTable], with the
+// entries indented under their folders. Folder rows show ▸ when collapsed,
+// ▾ when expanded and the number of entries inside; they are not numbered.
+func viewFolderTree(columns []listColumn, rows []treeRow, idx int, selected map[string]bool, maskNames bool) string {
+	var b strings.Builder
+	writeListHeader(&b, columns)
+
+	num := 0
+	for i, row := range rows {
+		indent := strings.Repeat(folderTreeIndent, row.depth)
+		if row.folder != nil {
+			marker := "▾"
+			if row.collapsed {
+				marker = "▸"
+			}
+			fmt.Fprintf(&b, "%s    │ %s%s %s (%d)\n", cursorMark(i == idx), indent, marker, row.folder.name, row.folder.count())
+			continue
+		}
+
+		num++
+		item := row.item
+		if maskNames && i != idx {
+			item.Metadata.Name = maskName(item.Metadata.Name)
+		}
+		writeListRow(&b, columns, item, num, i == idx, selected[item.ClientSideID], indent)
+	}
+	return b.String()
+}
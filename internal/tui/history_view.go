@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateHistory handles keys on the version history page. Restoring a
+// version goes through the regular update path, so the server's optimistic
+// lock applies and the next sync propagates the change.
+func (m mainLoopModel) updateHistory(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch keyMsg.String() {
+	case "esc":
+		m.closeHistory()
+		m.status = ""
+	case "up":
+		if m.historyIdx > 0 {
+			m.historyIdx--
+		}
+	case "down":
+		if m.historyIdx < len(m.historyVersions)-1 {
+			m.historyIdx++
+		}
+	case "enter":
+		if m.historyLoading || len(m.historyVersions) == 0 {
+			return m, nil
+		}
+		item, ok := m.current()
+		if !ok {
+			m.closeHistory()
+			return m, nil
+		}
+		if m.isReadOnly(item) {
+			m.status = readOnlyStatus(item.Type)
+			return m, nil
+		}
+		version := m.historyVersions[m.historyIdx].Version
+		m.historyLoading = true
+		m.status = fmt.Sprintf("Восстановление версии %d...", version)
+		m.errMsg = ""
+		return m, m.cmdRestore(item.ClientSideID, version)
+	}
+	return m, nil
+}
+
+func (m *mainLoopModel) closeHistory() {
+	m.history = false
+	m.historyVersions = nil
+	m.historyIdx = 0
+	m.historyLoading = false
+}
+
+func (m mainLoopModel) viewHistory() string {
+	item, _ := m.current()
+	title := "ИСТОРИЯ: " + item.Metadata.Name
+
+	out := ""
+	if m.errMsg != "" {
+		out += errorLine(m.errMsg) + "\n"
+	}
+	if m.status != "" {
+		out += "Статус: " + m.status + "\n"
+	}
+	if out != "" {
+		out += "\n"
+	}
+
+	switch {
+	case m.historyVersions == nil && m.historyLoading:
+	case len(m.historyVersions) == 0:
+		out += "История пуста (сервер не хранит версии)\n"
+	default:
+		out += "   Версия │ Обновлено        │ Заменено         │ Наименование\n"
+		out += "──────────┼──────────────────┼──────────────────┼────────────────\n"
+		for i, v := range m.historyVersions {
+			cursor := cursorMark(i == m.historyIdx)
+			updated := "-"
+			if v.UpdatedAt != nil {
+				updated = formatHistoryTime(*v.UpdatedAt)
+			}
+			out += fmt.Sprintf("%s %7d │ %-16s │ %-16s │ %s\n",
+				cursor, v.Version, updated, formatHistoryTime(v.ArchivedAt), v.Payload.Metadata.Name)
+		}
+	}
+
+	return renderPage(title, strings.TrimRight(out, "\n"), "enter: восстановить │ ↑/↓: нав. │ esc: назад")
+}
+
+func formatHistoryTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("02.01.2006 15:04")
+}
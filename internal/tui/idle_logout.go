@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleCheckMsg checks whether the session has been idle for
+// [mainLoopModel.idleLogout].
+type idleCheckMsg struct{}
+
+// idleCheckAfter wakes the model up after d to check the idle time.
+func idleCheckAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return idleCheckMsg{} })
+}
+
+// handleIdleCheck logs out, the same way "l" does, once no key was pressed
+// for m.idleLogout as measured by m.clock. Otherwise it waits for the rest of
+// the timeout, counted from the last key press. Zero disables the logout.
+func (m mainLoopModel) handleIdleCheck() (tea.Model, tea.Cmd) {
+	if m.idleLogout <= 0 {
+		return m, nil
+	}
+	idle := m.clock.Now().Sub(m.lastActivity)
+	if idle < m.idleLogout {
+		return m, idleCheckAfter(m.idleLogout - idle)
+	}
+	m.logout = true
+	m.cancel()
+	return m, tea.Quit
+}
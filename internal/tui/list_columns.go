@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/MKhiriev/go-pass-keeper/models"
+)
+
+// listColumn is a column of the vault list after the row number.
+type listColumn struct {
+	name  string
+	title string
+	width int
+	value func(item models.DecipheredPayload) string
+}
+
+// listColumnSpecs maps the configurable column names to their layout. The
+// name column additionally receives the width of every hidden column.
+var listColumnSpecs = map[string]listColumn{
+	config.ListColumnName: {
+		name:  config.ListColumnName,
+		title: "Наименование",
+		width: 24,
+		value: func(item models.DecipheredPayload) string {
+			if item.Metadata.Favorite {
+				return favoriteMark + item.Metadata.Name
+			}
+			return item.Metadata.Name
+		},
+	},
+	config.ListColumnType: {
+		name:  config.ListColumnType,
+		title: "Тип",
+		width: 15,
+		value: func(item models.DecipheredPayload) string { return dataTypeLabel(item.Type) },
+	},
+	config.ListColumnFolder: {
+		name:  config.ListColumnFolder,
+		title: "Папка",
+		width: 15,
+		value: func(item models.DecipheredPayload) string { return valueOrDash(item.Metadata.Folder) },
+	},
+}
+
+// resolveListColumns returns the layout of the configured columns in order.
+// Unknown names are skipped and an empty configuration shows all columns.
+func resolveListColumns(names []string) []listColumn {
+	if len(names) == 0 {
+		names = config.DefaultListColumns()
+	}
+
+	var columns []listColumn
+	shown := make(map[string]bool)
+	for _, name := range names {
+		spec, ok := listColumnSpecs[name]
+		if !ok || shown[name] {
+			continue
+		}
+		shown[name] = true
+		columns = append(columns, spec)
+	}
+
+	// Hand the space of hidden columns to the name column.
+	freed := 0
+	for name, spec := range listColumnSpecs {
+		if !shown[name] {
+			freed += spec.width + 3
+		}
+	}
+	for i := range columns {
+		if columns[i].name == config.ListColumnName {
+			columns[i].width += freed
+		}
+	}
+	return columns
+}
+
+// maskedNameFill replaces the middle of an entry name in privacy mode. Its
+// length is fixed so that the mask does not reveal the length of the name.
+const maskedNameFill = "•••"
+
+// maskName keeps the first and the last character of name and hides the
+// rest; see [maskedNameFill].
+func maskName(name string) string {
+	runes := []rune(name)
+	switch len(runes) {
+	case 0:
+		return ""
+	case 1, 2:
+		return string(runes[0]) + maskedNameFill
+	default:
+		return string(runes[0]) + maskedNameFill + string(runes[len(runes)-1])
+	}
+}
+
+// viewListTable renders the header, the divider and one row per item. The
+// last column is not padded. With maskNames set, the names of all rows but
+// the focused one are masked; see [maskName].
+func viewListTable(columns []listColumn, items []models.DecipheredPayload, idx int, selected map[string]bool, maskNames bool) string {
+	var b strings.Builder
+	writeListHeader(&b, columns)
+
+	for i, item := range items {
+		shown := item
+		if maskNames && i != idx {
+			shown.Metadata.Name = maskName(item.Metadata.Name)
+		}
+		writeListRow(&b, columns, shown, i+1, i == idx, selected[item.ClientSideID], "")
+	}
+	return b.String()
+}
+
+// writeListHeader writes the column titles and the divider under them.
+func writeListHeader(b *strings.Builder, columns []listColumn) {
+	b.WriteString("ID   ")
+	for i, col := range columns {
+		b.WriteString(listCell(col.title, col.width, i == len(columns)-1))
+	}
+	b.WriteString("\n─────")
+	for i, col := range columns {
+		dashes := col.width + 2
+		if i == len(columns)-1 {
+			dashes = col.width + 1
+		}
+		b.WriteString("┼" + strings.Repeat("─", dashes))
+	}
+	b.WriteString("\n")
+}
+
+// writeListRow writes the row of item numbered num. indent is put in front
+// of the name column.
+func writeListRow(b *strings.Builder, columns []listColumn, item models.DecipheredPayload, num int, focused, selected bool, indent string) {
+	mark := " "
+	if selected {
+		mark = "*"
+	}
+	fmt.Fprintf(b, "%s%s%-3d", cursorMark(focused), mark, num)
+	for j, col := range columns {
+		last := j == len(columns)-1
+		value := col.value(item)
+		if col.name == config.ListColumnName {
+			value = indent + value
+		}
+		if !last {
+			value = fitText(value, col.width)
+		}
+		b.WriteString(listCell(value, col.width, last))
+	}
+	b.WriteString("\n")
+}
+
+func listCell(value string, width int, last bool) string {
+	if last {
+		return "│ " + value
+	}
+	return fmt.Sprintf("│ %-*s ", width, value)
+}
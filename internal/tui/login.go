@@ -5,6 +5,7 @@ package tui
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
@@ -24,7 +25,17 @@ type LoginModel struct {
 	inputs     []textinput.Model
 	focus      int
 	submitting bool
-	errMsg     string
+	// attempt is the number of the login request attempt in progress when
+	// the service is retrying, zero before the first retry.
+	attempt int
+	errMsg  string
+}
+
+// loginAttemptMsg reports that the login service started attempt number
+// attempt; next delivers the following report.
+type loginAttemptMsg struct {
+	attempt int
+	next    <-chan int
 }
 
 // NewLoginModel creates a [LoginModel] with pre-configured username and password inputs.
@@ -57,6 +68,7 @@ func (m *LoginModel) Init() tea.Cmd {
 
 // Update implements [tea.Model]. Handled messages:
 //   - [LoginResult]  — clears submitting state; on error, populates errMsg.
+//   - loginAttemptMsg — shows the number of the retried login attempt.
 //   - esc            — cancels and navigates back to the menu.
 //   - tab            — moves focus to the next input.
 //   - shift+tab      — moves focus to the previous input.
@@ -66,11 +78,18 @@ func (m *LoginModel) Init() tea.Cmd {
 func (m *LoginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if result, ok := msg.(LoginResult); ok {
 		m.submitting = false
+		m.attempt = 0
 		if result.Err != nil {
 			m.errMsg = humanizeServerUnavailableError(result.Err)
 		}
 		return m, nil
 	}
+	if attempt, ok := msg.(loginAttemptMsg); ok {
+		if m.submitting {
+			m.attempt = attempt.attempt
+		}
+		return m, waitLoginAttempt(attempt.next)
+	}
 
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if ok {
@@ -99,7 +118,9 @@ func (m *LoginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			m.errMsg = ""
 			m.submitting = true
-			return m, m.cmdLogin(login, pass)
+			m.attempt = 0
+			attempts := make(chan int, 1)
+			return m, tea.Batch(m.cmdLogin(login, pass, attempts), waitLoginAttempt(attempts))
 		}
 	}
 
@@ -121,26 +142,39 @@ func (m *LoginModel) View() string {
 	b.WriteString(m.inputs[1].View())
 	b.WriteString("]\n")
 
-	if m.submitting {
+	if m.submitting && m.attempt > 1 {
+		b.WriteString(fmt.Sprintf("\n[Войти... попытка %d]\n", m.attempt))
+	} else if m.submitting {
 		b.WriteString("\n[Войти...]\n")
 	} else {
 		b.WriteString("\n[Войти]\n")
 	}
 
 	if m.errMsg != "" {
-		b.WriteString("\nОшибка: ")
-		b.WriteString(m.errMsg)
+		b.WriteString("\n")
+		b.WriteString(errorLine(m.errMsg))
 		b.WriteString("\n")
 	}
 
 	return renderPage("ВХОД", strings.TrimRight(b.String(), "\n"), "esc: назад │ tab: след. поле │ enter: подтвердить")
 }
 
-func (m *LoginModel) cmdLogin(login, pass string) tea.Cmd {
-	ctx := m.ctx
+// cmdLogin runs the login and reports every retried attempt to attempts,
+// which it closes when the login has finished. A report is dropped if the
+// previous one has not been received yet.
+func (m *LoginModel) cmdLogin(login, pass string, attempts chan<- int) tea.Cmd {
+	ctx := service.WithLoginAttemptObserver(m.ctx, func(attempt int) {
+		select {
+		case attempts <- attempt:
+		default:
+		}
+	})
 	auth := m.auth
 
 	return func() tea.Msg {
+		defer close(attempts)
+		// Scored here, while the plaintext is still at hand; Login clears it.
+		weak := service.IsWeakPassword(pass)
 		userID, key, err := auth.Login(ctx, models.User{
 			Login:          login,
 			MasterPassword: pass,
@@ -151,7 +185,20 @@ func (m *LoginModel) cmdLogin(login, pass string) tea.Cmd {
 			Username:      login,
 			UserID:        userID,
 			EncryptionKey: key,
+			WeakPassword:  err == nil && weak,
+		}
+	}
+}
+
+// waitLoginAttempt waits for the next attempt report of a running login. It
+// produces no message once the login has finished.
+func waitLoginAttempt(attempts <-chan int) tea.Cmd {
+	return func() tea.Msg {
+		attempt, ok := <-attempts
+		if !ok {
+			return nil
 		}
+		return loginAttemptMsg{attempt: attempt, next: attempts}
 	}
 }
 
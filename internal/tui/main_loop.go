@@ -13,11 +13,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/browser"
+	"github.com/MKhiriev/go-pass-keeper/internal/clipboard"
+	"github.com/MKhiriev/go-pass-keeper/internal/clock"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
 	"github.com/MKhiriev/go-pass-keeper/models"
-	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -29,16 +33,24 @@ const (
 	addStageMeta
 	addStageData
 	addStageNotes
+	addStageFile
 )
 
 type mainLoopModel struct {
+	// ctx is canceled on quit and logout so that in-flight commands abort.
 	ctx       context.Context
+	cancel    context.CancelFunc
 	services  *service.ClientServices
 	userID    int64
 	debug     bool
 	buildInfo models.AppBuildInfo
 
+	// items holds the listed entries. Unless itemsFull is set only their
+	// metadata is decrypted; the full payload of an entry is decrypted on
+	// demand when it is opened and kept in opened until the next reload.
 	items                 []models.DecipheredPayload
+	itemsFull             bool
+	opened                map[string]models.DecipheredPayload
 	idx                   int
 	loading               bool
 	syncing               bool
@@ -48,35 +60,242 @@ type mainLoopModel struct {
 	detailRevealSensitive bool
 	editing               bool
 
+	// preview is set while the content of a Binary entry is shown in
+	// previewPane; see [mainLoopModel.startBinaryPreview].
+	preview     bool
+	previewPane viewport.Model
+
+	// totpQR is set while the otpauth:// URI of a login is shown as a QR
+	// code; see [mainLoopModel.startTOTPQR].
+	totpQR    bool
+	totpQRURI string
+
+	// reprompting is set while repromptInput asks for the master password
+	// before repromptKey reveals or copies a secret of an entry marked with
+	// [models.Metadata.Reprompt]. repromptUnlocked is the client-side ID of
+	// the entry whose detail view was unlocked; see [mainLoopModel.gateReprompt].
+	reprompting      bool
+	repromptBusy     bool
+	repromptInput    textinput.Model
+	repromptKey      tea.KeyMsg
+	repromptUnlocked string
+
+	// width and height are the terminal size from the last
+	// tea.WindowSizeMsg, zero until one arrives.
+	width  int
+	height int
+
+	// undecryptable holds the client-side IDs of items that failed to
+	// decrypt. They are listed as placeholder rows that can only be
+	// inspected or deleted.
+	undecryptable map[string]bool
+
+	// clipboard copies detail values; see [clipboard.New].
+	clipboard clipboard.Clipboard
+	// detailCopyFallback holds the value that could not be copied because no
+	// clipboard backend works; "p" prints it on screen instead.
+	detailCopyFallback  string
+	detailShowCopyValue bool
+	// clipboardSeq numbers the clipboard writes, so that a delayed clear of
+	// a generated password does not wipe a value copied after it.
+	clipboardSeq int
+	// clipboardClearAt is when the last generated password is cleared from
+	// the clipboard; see [mainLoopModel.handleClipboardClear].
+	clipboardClearAt time.Time
+	// copyGenerated also copies a password generated with ctrl+g to the
+	// clipboard; see [mainLoopModel.generatePassword].
+	copyGenerated bool
+	// browser opens the first URI of a login; see [mainLoopModel.openLoginURI].
+	browser browser.Launcher
+	// clock is the time the screens are rendered for, the age of the last
+	// sync and the current TOTP code, and the time the clipboard clear and
+	// the idle logout wait for.
+	clock clock.Clock
+	// idleLogout logs out after that long without a key press; zero
+	// disables it. lastActivity is the time of the last key press.
+	idleLogout   time.Duration
+	lastActivity time.Time
+
 	editInputs     []textinput.Model
 	editFocus      int
 	editSubmitting bool
 	editPayload    models.DecipheredPayload
+	// editReprompt is the Reprompt flag of the edited entry, toggled with
+	// ctrl+r.
+	editReprompt bool
+	// editURIs and addURIs track the URI inputs of the login forms.
+	editURIs uriFields
+	// editFields and addFields track the custom field inputs of the forms.
+	editFields customFields
+	// editInfo is the notice of the edit form, e.g. that a password was
+	// generated.
+	editInfo string
+	// editOriginal holds the input values the edit form was opened with;
+	// see [mainLoopModel.hasUnsavedChanges].
+	editOriginal []string
+	// confirmDiscard is set while esc on a dirty add or edit form waits for
+	// the user's y/n answer.
+	confirmDiscard bool
 
 	addStage       addStage
 	addTypeOptions []models.DataType
 	addTypeIdx     int
 	addErr         string
+	addInfo        string
 	addPayload     models.DecipheredPayload
 	addMetaInputs  []textinput.Model
 	addMetaFocus   int
 	addDataInputs  []textinput.Model
 	addDataFocus   int
+	addURIs        uriFields
+	addFields      customFields
 	addTextArea    textarea.Model
 	addNotesArea   textarea.Model
 	addSaving      bool
+	addFilePicker  filePicker
 	showBuildInfo  bool
 
+	maxAttachmentSize int64
+	maxNotesLength    int
+	lastSyncedAt      time.Time
+	syncStaleAfter    time.Duration
+
+	// syncBreaker is the sync circuit breaker state reported by the last
+	// sync; an open breaker is shown under the last sync time.
+	syncBreaker models.SyncBreakerStatus
+
+	// pingInterval is how often the server's reachability is checked for
+	// the header indicator; zero disables the checks.
+	pingInterval time.Duration
+	connectivity connectivity
+
+	// detectDuplicates enables the duplicate search after a manual sync.
+	// dupGroups holds the groups still waiting for the user's y/n answer;
+	// nothing is merged without it.
+	detectDuplicates bool
+	dupGroups        []models.DuplicateGroup
+
+	// syncOnChange runs a sync after every successful create, update and
+	// delete; see [mainLoopModel.afterChange].
+	syncOnChange bool
+
+	// listColumns are the configured list columns; see [resolveListColumns].
+	listColumns []string
+
+	// maskNames is the list privacy mode: entry names are partially masked
+	// on every row but the focused one. Toggled with "p".
+	maskNames bool
+
+	// folderTree groups the list into a folder tree; see [buildFolderTree].
+	// Toggled with "g". collapsed holds the paths of the collapsed folders.
+	folderTree bool
+	collapsed  map[string]bool
+
+	// recoveryKitDir is where "k" writes the recovery kit; empty means the
+	// working directory.
+	recoveryKitDir string
+
+	// defaultAddType and defaultFolder preset the add flow; zero values keep
+	// the first type selected and the folder empty.
+	defaultAddType models.DataType
+	defaultFolder  string
+
+	// offline disables sync and every action that modifies the vault; the
+	// vault can only be browsed.
+	offline bool
+
+	// history shows the server-side versions of the open detail item;
+	// historyVersions is nil while they are being loaded.
+	history         bool
+	historyVersions []models.DecipheredVersion
+	historyIdx      int
+	historyLoading  bool
+
+	// summary shows the vault composition screen; summaryData is nil while
+	// the counts are being loaded.
+	summary     bool
+	summaryData *models.VaultSummary
+
+	// trash lists the soft-deleted entries; trashItems is nil while they
+	// are being loaded. trashConfirmRemove asks before an entry is removed
+	// from the trash.
+	trash              bool
+	trashItems         []models.DecipheredPayload
+	trashIdx           int
+	trashBusy          bool
+	trashConfirmRemove bool
+
+	// selected marks list items by client-side ID for a bulk move. While
+	// moving is set, moveInput asks for the target folder.
+	selected   map[string]bool
+	moving     bool
+	moveSaving bool
+	moveInput  textinput.Model
+
+	// conflicts holds the conflicts a sync under the manual policy left to
+	// the user, the first one being on screen once conflict is loaded.
+	conflicts      []models.VersionConflict
+	conflict       *models.ConflictVersions
+	conflictSaving bool
+
+	// searchQuery filters the list; see [matchesSearch]. searchDeep extends
+	// the match to decrypted notes and login usernames, which is off by
+	// default to avoid scanning large notes. searching is set while the
+	// query is being typed into searchInput.
+	searchQuery string
+	searchDeep  bool
+	searching   bool
+	searchInput textinput.Model
+
+	// weakPasswordHint shows a suggestion to change a weak master password
+	// until the first key press; see [LoginResult.WeakPassword].
+	weakPasswordHint bool
+
 	logout bool
 }
 
 type listLoadedMsg struct {
 	items []models.DecipheredPayload
-	err   error
+	// full is set when items carry their full payloads rather than only
+	// their metadata.
+	full bool
+	// failed lists the client-side IDs of items that could not be decrypted.
+	failed []string
+	err    error
+	// lastSyncedAt is nil when the last sync time could not be read; the
+	// previously shown value is kept in that case.
+	lastSyncedAt *time.Time
 }
 
 type syncDoneMsg struct {
-	err error
+	err     error
+	breaker models.SyncBreakerStatus
+}
+
+// openAction is what happens once an entry opened with
+// [mainLoopModel.cmdOpenItem] has been decrypted.
+type openAction int
+
+const (
+	openDetail openAction = iota
+	openEdit
+	// openCopy copies the primary secret of the entry from the list.
+	openCopy
+	// openRefresh only replaces the cached payload of an entry that is
+	// already shown.
+	openRefresh
+)
+
+type itemOpenedMsg struct {
+	item   models.DecipheredPayload
+	action openAction
+	err    error
+}
+
+// autoSyncDoneMsg reports the sync started by [mainLoopModel.afterChange].
+type autoSyncDoneMsg struct {
+	err     error
+	breaker models.SyncBreakerStatus
 }
 
 type deleteDoneMsg struct {
@@ -91,6 +310,44 @@ type createDoneMsg struct {
 	err error
 }
 
+type duplicatesFoundMsg struct {
+	groups []models.DuplicateGroup
+	err    error
+}
+
+type mergeDoneMsg struct {
+	err error
+}
+
+type summaryLoadedMsg struct {
+	summary models.VaultSummary
+	err     error
+}
+
+type recoveryKitSavedMsg struct {
+	path string
+	err  error
+}
+
+type historyLoadedMsg struct {
+	versions []models.DecipheredVersion
+	err      error
+}
+
+type restoreDoneMsg struct {
+	err error
+}
+
+type moveDoneMsg struct {
+	result models.MoveResult
+	err    error
+}
+
+type favoriteDoneMsg struct {
+	favorite bool
+	err      error
+}
+
 var errUserIDNotSet = errors.New("user id не установлен")
 var errClientSideIDNotSet = errors.New("clientSideID не установлен")
 
@@ -103,54 +360,207 @@ func newMainLoopModel(ctx context.Context, services *service.ClientServices, use
 		setSessionUserID(effectiveUserID)
 	}
 
+	// Services assembled without a clock, as in tests, run on the system one.
+	var clk clock.Clock = clock.Real{}
+	if services != nil && services.Clock != nil {
+		clk = services.Clock
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
 	return mainLoopModel{
 		ctx:       ctx,
+		cancel:    cancel,
 		services:  services,
 		userID:    effectiveUserID,
 		debug:     isTUIDebugEnabled(),
 		buildInfo: buildInfo,
 		loading:   true,
-		addTypeOptions: []models.DataType{
-			models.LoginPassword,
-			models.Text,
-			models.Binary,
-			models.BankCard,
-		},
+
+		maxAttachmentSize: defaultMaxAttachmentSize,
+		maxNotesLength:    defaultMaxNotesLength,
+		syncStaleAfter:    config.DefaultSyncStaleAfter,
+		clipboard:         clipboard.New(clipboard.ModeAuto, os.Stdout, os.Getenv),
+		browser:           browser.New(),
+		clock:             clk,
+		lastActivity:      clk.Now(),
+		addTypeOptions:    config.AllDataTypes(),
 	}
 }
 
 func (m mainLoopModel) Init() tea.Cmd {
-	return m.cmdLoadItems()
+	cmds := []tea.Cmd{m.cmdLoadItems()}
+	if m.pingInterval > 0 && !m.offline {
+		cmds = append(cmds, m.cmdPing())
+	}
+	if m.idleLogout > 0 {
+		cmds = append(cmds, idleCheckAfter(m.idleLogout))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.lastActivity = m.clock.Now()
+	}
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
 	case listLoadedMsg:
 		m.loading = false
+		if msg.lastSyncedAt != nil {
+			m.lastSyncedAt = *msg.lastSyncedAt
+		}
+		if isCanceled(msg.err) {
+			m.status = "Загрузка: " + statusCanceled
+			return m, nil
+		}
+		if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+			return m.reauthenticate()
+		}
 		if msg.err != nil {
 			m.errMsg = msg.err.Error()
 			return m, nil
 		}
 		m.errMsg = ""
-		m.items = msg.items
-		if m.idx >= len(m.items) {
-			m.idx = len(m.items) - 1
+		var currentID string
+		if item, ok := m.current(); ok {
+			currentID = item.ClientSideID
+		}
+		m.items = favoritesFirst(msg.items)
+		m.itemsFull = msg.full
+		m.followItem(currentID)
+		refresh := m.keepOpenedDetail()
+		m.undecryptable = make(map[string]bool, len(msg.failed))
+		for _, id := range msg.failed {
+			m.undecryptable[id] = true
+			m.items = append(m.items, models.DecipheredPayload{
+				ClientSideID: id,
+				Metadata:     models.Metadata{Name: undecryptableLabel},
+			})
+		}
+		if len(msg.failed) > 0 {
+			m.status = fmt.Sprintf("Не удалось расшифровать записей: %d", len(msg.failed))
+		}
+		if n := m.listLen(); m.idx >= n {
+			m.idx = n - 1
 		}
 		if m.idx < 0 {
 			m.idx = 0
 		}
+		return m, refresh
+	case itemOpenedMsg:
+		if isCanceled(msg.err) {
+			return m, nil
+		}
+		if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+			return m.reauthenticate()
+		}
+		if msg.err != nil {
+			m.errMsg = fmt.Sprintf("Ошибка расшифровки записи: %v", msg.err)
+			return m, nil
+		}
+		if m.opened == nil {
+			m.opened = make(map[string]models.DecipheredPayload)
+		}
+		m.opened[msg.item.ClientSideID] = msg.item
+		switch msg.action {
+		case openDetail:
+			m.errMsg = ""
+			m.detailRevealSensitive = false
+			m.repromptUnlocked = ""
+			m.preview = false
+			m.totpQR = false
+			m.detail = true
+		case openEdit:
+			m.errMsg = ""
+			m.startEdit(msg.item)
+		case openCopy:
+			m.errMsg = ""
+			m.quickCopy(msg.item)
+		}
 		return m, nil
 	case syncDoneMsg:
 		m.syncing = false
+		m.syncBreaker = msg.breaker
+		if isCanceled(msg.err) {
+			m.status = "Синхронизация: " + statusCanceled
+			m.errMsg = ""
+			return m, nil
+		}
+		if next, cmd, ok := m.startConflicts(msg.err); ok {
+			return next, cmd
+		}
 		if msg.err != nil {
 			m.errMsg = syncErrorMessage(msg.err)
 			return m, nil
 		}
 		m.status = "Синхронизация завершена"
 		m.errMsg = ""
+		m.connectivity = connOnline
+		m.loading = true
+		if m.detectDuplicates {
+			return m, tea.Batch(m.cmdLoadItems(), m.cmdFindDuplicates())
+		}
+		return m, m.cmdLoadItems()
+	case autoSyncDoneMsg:
+		m.syncing = false
+		m.syncBreaker = msg.breaker
+		if isCanceled(msg.err) {
+			return m, nil
+		}
+		if next, cmd, ok := m.startConflicts(msg.err); ok {
+			return next, cmd
+		}
+		if msg.err != nil {
+			// The change is already saved locally; the next sync pushes it.
+			m.status += " (синхронизация отложена)"
+			return m, nil
+		}
+		m.status += " и синхронизирована"
+		m.loading = true
+		return m, m.cmdLoadItems()
+	case pingDueMsg:
+		return m, m.cmdPing()
+	case pingDoneMsg:
+		return m.handlePing(msg)
+	case clipboardClearMsg:
+		return m, m.handleClipboardClear(msg)
+	case idleCheckMsg:
+		return m.handleIdleCheck()
+	case duplicatesFoundMsg:
+		if isCanceled(msg.err) {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.errMsg = fmt.Sprintf("Ошибка поиска дубликатов: %v", msg.err)
+			return m, nil
+		}
+		m.dupGroups = msg.groups
+		return m, nil
+	case mergeDoneMsg:
+		if isCanceled(msg.err) {
+			m.status = "Объединение: " + statusCanceled
+			return m, nil
+		}
+		if msg.err != nil {
+			m.errMsg = fmt.Sprintf("Ошибка объединения: %v", msg.err)
+			return m, nil
+		}
+		m.status = "Дубликаты объединены"
+		m.errMsg = ""
 		m.loading = true
 		return m, m.cmdLoadItems()
 	case deleteDoneMsg:
+		if isCanceled(msg.err) {
+			m.status = "Удаление: " + statusCanceled
+			return m, nil
+		}
+		if conflict, ok := service.ConflictOf(msg.err); ok {
+			m.errMsg = conflictMessage(conflict)
+			return m, nil
+		}
 		if msg.err != nil {
 			m.errMsg = fmt.Sprintf("Ошибка удаления: %v", msg.err)
 			return m, nil
@@ -158,9 +568,24 @@ func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.status = "Запись удалена"
 		m.errMsg = ""
 		m.loading = true
-		return m, m.cmdLoadItems()
+		return m.afterChange()
 	case updateDoneMsg:
 		m.editSubmitting = false
+		if isCanceled(msg.err) {
+			m.status = "Изменение: " + statusCanceled
+			return m, nil
+		}
+		if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+			return m.reauthenticate()
+		}
+		if conflict, ok := service.ConflictOf(msg.err); ok {
+			m.errMsg = conflictMessage(conflict)
+			return m, nil
+		}
+		if failure, ok := service.ValidationFailureOf(msg.err); ok {
+			m.errMsg = validationMessage(failure)
+			return m, nil
+		}
 		if msg.err != nil {
 			m.errMsg = fmt.Sprintf("Ошибка изменения: %v", msg.err)
 			return m, nil
@@ -169,35 +594,189 @@ func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.status = "Запись обновлена"
 		m.errMsg = ""
 		m.loading = true
+		return m.afterChange()
+	case summaryLoadedMsg:
+		return m.summaryLoaded(msg)
+	case trashLoadedMsg:
+		return m.trashLoaded(msg)
+	case trashActionDoneMsg:
+		return m.trashActionDone(msg)
+	case conflictLoadedMsg:
+		return m.conflictLoaded(msg)
+	case conflictResolvedMsg:
+		return m.conflictResolved(msg)
+	case recoveryKitSavedMsg:
+		if msg.err != nil {
+			m.errMsg = fmt.Sprintf("Ошибка создания набора восстановления: %v", msg.err)
+			return m, nil
+		}
+		m.errMsg = ""
+		m.status = "Набор восстановления сохранён: " + msg.path
+		return m, nil
+	case historyLoadedMsg:
+		m.historyLoading = false
+		if isCanceled(msg.err) {
+			m.history = false
+			m.status = "История: " + statusCanceled
+			return m, nil
+		}
+		if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+			return m.reauthenticate()
+		}
+		if msg.err != nil {
+			m.history = false
+			m.errMsg = fmt.Sprintf("Ошибка загрузки истории: %v", msg.err)
+			return m, nil
+		}
+		m.status = ""
+		m.errMsg = ""
+		m.historyVersions = msg.versions
+		m.historyIdx = 0
+		return m, nil
+	case restoreDoneMsg:
+		m.historyLoading = false
+		if isCanceled(msg.err) {
+			m.status = "Восстановление: " + statusCanceled
+			return m, nil
+		}
+		if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+			return m.reauthenticate()
+		}
+		if msg.err != nil {
+			m.errMsg = fmt.Sprintf("Ошибка восстановления: %v", msg.err)
+			return m, nil
+		}
+		m.closeHistory()
+		m.detail = false
+		m.detailRevealSensitive = false
+		m.status = "Версия восстановлена"
+		m.errMsg = ""
+		m.loading = true
 		return m, m.cmdLoadItems()
+	case moveDoneMsg:
+		m.moveSaving = false
+		if isCanceled(msg.err) {
+			m.status = "Перемещение: " + statusCanceled
+			return m, nil
+		}
+		if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+			return m.reauthenticate()
+		}
+		if msg.err != nil {
+			m.errMsg = fmt.Sprintf("Ошибка перемещения: %v", msg.err)
+			return m, nil
+		}
+		m.moving = false
+		m.selected = nil
+		m.status = moveStatus(msg.result)
+		m.errMsg = ""
+		m.loading = true
+		return m, m.cmdLoadItems()
+	case repromptDoneMsg:
+		return m.finishReprompt(msg.err)
+	case favoriteDoneMsg:
+		if isCanceled(msg.err) {
+			m.status = "Избранное: " + statusCanceled
+			return m, nil
+		}
+		if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+			return m.reauthenticate()
+		}
+		if conflict, ok := service.ConflictOf(msg.err); ok {
+			m.errMsg = conflictMessage(conflict)
+			return m, nil
+		}
+		if msg.err != nil {
+			m.errMsg = fmt.Sprintf("Ошибка изменения избранного: %v", msg.err)
+			return m, nil
+		}
+		m.status = "Убрано из избранного"
+		if msg.favorite {
+			m.status = "Добавлено в избранное"
+		}
+		m.errMsg = ""
+		m.loading = true
+		return m.afterChange()
 	case createDoneMsg:
 		m.addSaving = false
+		if isCanceled(msg.err) {
+			m.status = "Добавление: " + statusCanceled
+			m.resetAddFlow()
+			return m, nil
+		}
+		if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+			m.resetAddFlow()
+			return m.reauthenticate()
+		}
+		if failure, ok := service.ValidationFailureOf(msg.err); ok {
+			m.status = "Возникла ошибка"
+			m.errMsg = validationMessage(failure)
+			m.resetAddFlow()
+			return m, nil
+		}
 		if msg.err != nil {
 			m.status = "Возникла ошибка"
 			m.errMsg = msg.err.Error()
 			m.resetAddFlow()
 			return m, nil
 		}
-		m.status = "Запись добавлена!"
+		m.status = "Запись добавлена"
 		m.errMsg = ""
 		m.resetAddFlow()
 		m.loading = true
-		return m, m.cmdLoadItems()
+		return m.afterChange()
 	}
 
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if !ok {
+		if m.reprompting {
+			return m.updateReprompt(msg)
+		}
 		if m.addStage != addStageNone {
 			return m.updateAddFlow(msg)
 		}
 		if m.editing {
 			return m.updateEditing(msg)
 		}
+		if m.moving {
+			return m.updateMove(msg)
+		}
+		if m.searching {
+			return m.updateSearch(msg)
+		}
 		return m, nil
 	}
 
+	m.weakPasswordHint = false
+
+	if m.searching {
+		return m.updateSearch(msg)
+	}
+
+	if m.reprompting {
+		return m.updateReprompt(msg)
+	}
+
+	// On the detail page of a login with TOTP, q shows its QR code instead
+	// of quitting, and on the QR page it closes it again.
+	if keyMsg.String() == "q" && m.totpQR {
+		return m.updateTOTPQR(keyMsg)
+	}
+	if keyMsg.String() == "q" && m.detail && !m.preview {
+		if item, ok := m.current(); ok && !m.isUndecryptable(item) {
+			if uri, ok := service.TOTPShareURI(item); ok {
+				if next, handled := m.gateReprompt(keyMsg); handled {
+					return next, textinput.Blink
+				}
+				m.startTOTPQR(uri)
+				return m, nil
+			}
+		}
+	}
+
 	switch keyMsg.String() {
 	case "ctrl+c", "q":
+		m.cancel()
 		return m, tea.Quit
 	case "v":
 		if m.addStage == addStageNone && !m.editing && !m.detail {
@@ -213,6 +792,12 @@ func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.addStage != addStageNone || m.editing {
+		if next, handled := m.handleFormEsc(keyMsg); handled {
+			return next, nil
+		}
+	}
+
 	if m.addStage != addStageNone {
 		return m.updateAddFlow(msg)
 	}
@@ -221,6 +806,44 @@ func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateEditing(msg)
 	}
 
+	if m.moving {
+		return m.updateMove(msg)
+	}
+
+	if m.conflict != nil {
+		return m.updateConflict(keyMsg)
+	}
+
+	if m.trash {
+		return m.updateTrash(keyMsg)
+	}
+
+	if m.offline && offlineDisabledKeys[keyMsg.String()] {
+		m.status = service.ErrOfflineMode.Error()
+		return m, nil
+	}
+
+	if readOnlyKeys[keyMsg.String()] && !m.history && !m.summary {
+		if item, ok := m.current(); ok && m.isReadOnly(item) {
+			m.status = readOnlyStatus(item.Type)
+			return m, nil
+		}
+	}
+
+	if m.history {
+		return m.updateHistory(keyMsg)
+	}
+
+	if m.summary {
+		return m.updateSummary(keyMsg)
+	}
+
+	if !m.preview && !m.totpQR {
+		if next, handled := m.gateReprompt(keyMsg); handled {
+			return next, textinput.Blink
+		}
+	}
+
 	if m.detail {
 		item, ok := m.current()
 		if !ok {
@@ -228,10 +851,35 @@ func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.preview {
+			return m.updateBinaryPreview(keyMsg)
+		}
+
+		if m.totpQR {
+			return m.updateTOTPQR(keyMsg)
+		}
+
+		if keyMsg.String() != "p" {
+			m.detailCopyFallback = ""
+			m.detailShowCopyValue = false
+		}
+
+		if m.isUndecryptable(item) {
+			switch keyMsg.String() {
+			case "esc":
+				m.detail = false
+			case "ctrl+d":
+				m.detail = false
+				return m, m.cmdDelete(item.ClientSideID)
+			}
+			return m, nil
+		}
+
 		switch keyMsg.String() {
 		case "esc":
 			m.detail = false
 			m.detailRevealSensitive = false
+			m.repromptUnlocked = ""
 		case " ":
 			m.detailRevealSensitive = !m.detailRevealSensitive
 		case "e":
@@ -239,6 +887,18 @@ func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.detailRevealSensitive = false
 			m.startEdit(item)
 			return m, nil
+		case "h":
+			if strings.TrimSpace(item.ClientSideID) == "" {
+				m.errMsg = fmt.Sprintf("Ошибка загрузки истории: %v", errClientSideIDNotSet)
+				return m, nil
+			}
+			m.history = true
+			m.historyVersions = nil
+			m.historyIdx = 0
+			m.historyLoading = true
+			m.status = "Загрузка истории..."
+			m.errMsg = ""
+			return m, m.cmdLoadHistory(item.ClientSideID)
 		case "ctrl+d":
 			if strings.TrimSpace(item.ClientSideID) == "" {
 				m.errMsg = fmt.Sprintf("Ошибка удаления: %v", errClientSideIDNotSet)
@@ -253,25 +913,104 @@ func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.status = "Нечего копировать"
 				return m, nil
 			}
-			if err := clipboard.WriteAll(text); err != nil {
-				m.errMsg = fmt.Sprintf("Ошибка копирования: %v", err)
+			m.copyToClipboard(text)
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			field, ok := customFieldAt(item, int(keyMsg.String()[0]-'1'))
+			if !ok {
+				m.status = "Нечего копировать"
 				return m, nil
 			}
-			m.status = "Скопировано"
+			// Masking is display-only: the plaintext is copied even when the
+			// field is sensitive and hidden.
+			m.copyToClipboard(string(field.Data))
+		case "alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9":
+			uri, ok := loginURIAt(item, int(keyMsg.String()[len("alt+")]-'1'))
+			if !ok {
+				m.status = "Нечего копировать"
+				return m, nil
+			}
+			m.copyToClipboard(uri.URI)
+		case "f":
+			return m.toggleFavorite(item)
+		case "enter":
+			if item.Type == models.Binary {
+				m.startBinaryPreview(item)
+			}
+		case "O":
+			m.openLoginURI(item)
+		case "x":
+			// Logins with TOTP are exported as an otpauth:// URI, everything
+			// else as "label: value" lines; see [service.ToShareString].
+			m.copyToClipboard(service.ToShareString(item))
+		case "p":
+			if m.detailCopyFallback != "" {
+				m.detailShowCopyValue = true
+				m.errMsg = ""
+			}
 		}
 		return m, nil
 	}
 
+	if len(m.dupGroups) > 0 {
+		switch keyMsg.String() {
+		case "y":
+			group := m.dupGroups[0]
+			m.dupGroups = m.dupGroups[1:]
+			m.status = "Объединение..."
+			return m, m.cmdMergeDuplicates(group)
+		case "n":
+			m.dupGroups = m.dupGroups[1:]
+			m.status = "Дубликат пропущен"
+			return m, nil
+		}
+	}
+
+	if m.folderTree {
+		if next, handled := m.updateFolderTree(keyMsg.String()); handled {
+			return next, nil
+		}
+	}
+
 	switch keyMsg.String() {
 	case "up":
 		if m.idx > 0 {
 			m.idx--
 		}
 	case "down":
-		if m.idx < len(m.items)-1 {
+		if m.idx < m.listLen()-1 {
 			m.idx++
 		}
+	case "g":
+		item, hasItem := m.current()
+		m.folderTree = !m.folderTree
+		m.idx = 0
+		if hasItem {
+			m.followItem(item.ClientSideID)
+		}
+		m.status = "Список без папок"
+		if m.folderTree {
+			m.status = "Список по папкам"
+		}
+	case "/":
+		m.startSearch()
+		return m, textinput.Blink
+	case "i":
+		return m.startSummary()
+	case "T":
+		return m.startTrash()
+	case "p":
+		m.maskNames = !m.maskNames
+		m.status = "Режим приватности выключен"
+		if m.maskNames {
+			m.status = "Режим приватности: названия скрыты"
+		}
+	case "k":
+		m.status = "Создание набора восстановления..."
+		return m, m.cmdRecoveryKit()
 	case "a":
+		if m.keyMissing() {
+			return m.reauthenticate()
+		}
 		m.startAddFlow()
 		return m, nil
 	case "s":
@@ -283,11 +1022,21 @@ func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.errMsg = ""
 		return m, m.cmdSync()
 	case "enter":
-		if _, ok := m.current(); !ok {
+		item, ok := m.current()
+		if !ok {
 			m.status = "Нет записей"
 			return m, nil
 		}
+		if m.keyMissing() {
+			return m.reauthenticate()
+		}
+		if !m.isDecrypted(item) {
+			return m, m.cmdOpenItem(item.ClientSideID, openDetail)
+		}
 		m.detailRevealSensitive = false
+		m.repromptUnlocked = ""
+		m.preview = false
+		m.totpQR = false
 		m.detail = true
 	case "e":
 		item, ok := m.current()
@@ -295,8 +1044,35 @@ func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.status = "Нет записей"
 			return m, nil
 		}
+		if m.keyMissing() {
+			return m.reauthenticate()
+		}
+		if m.isUndecryptable(item) {
+			m.status = "Запись не расшифрована, изменение недоступно"
+			return m, nil
+		}
+		if !m.isDecrypted(item) {
+			return m, m.cmdOpenItem(item.ClientSideID, openEdit)
+		}
 		m.startEdit(item)
 		return m, nil
+	case "c":
+		item, ok := m.current()
+		if !ok {
+			m.status = "Нет записей"
+			return m, nil
+		}
+		if m.isUndecryptable(item) {
+			m.status = "Нечего копировать"
+			return m, nil
+		}
+		if m.keyMissing() {
+			return m.reauthenticate()
+		}
+		if !m.isDecrypted(item) {
+			return m, m.cmdOpenItem(item.ClientSideID, openCopy)
+		}
+		m.quickCopy(item)
 	case "ctrl+d":
 		item, ok := m.current()
 		if !ok {
@@ -308,8 +1084,37 @@ func (m mainLoopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		return m, m.cmdDelete(item.ClientSideID)
+	case "x":
+		item, ok := m.current()
+		if !ok {
+			m.status = "Нет записей"
+			return m, nil
+		}
+		if m.isUndecryptable(item) {
+			m.status = "Запись не расшифрована, перемещение недоступно"
+			return m, nil
+		}
+		m.toggleSelected(item.ClientSideID)
+	case "f":
+		item, ok := m.current()
+		if !ok {
+			m.status = "Нет записей"
+			return m, nil
+		}
+		return m.toggleFavorite(item)
+	case "m":
+		if len(m.selectedIDs()) == 0 {
+			m.status = "Не отмечено ни одной записи (x: отметить)"
+			return m, nil
+		}
+		if m.keyMissing() {
+			return m.reauthenticate()
+		}
+		m.startMove()
+		return m, textinput.Blink
 	case "l":
 		m.logout = true
+		m.cancel()
 		return m, tea.Quit
 	}
 
@@ -326,6 +1131,8 @@ func (m mainLoopModel) updateAddFlow(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateAddData(msg)
 	case addStageNotes:
 		return m.updateAddNotes(msg)
+	case addStageFile:
+		return m.updateAddFile(msg)
 	default:
 		return m, nil
 	}
@@ -350,7 +1157,11 @@ func (m mainLoopModel) updateAddType(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.addTypeIdx++
 		}
 	case "1", "2", "3", "4":
-		m.addTypeIdx = int(keyMsg.String()[0] - '1')
+		idx := int(keyMsg.String()[0] - '1')
+		if idx >= len(m.addTypeOptions) {
+			return m, nil
+		}
+		m.addTypeIdx = idx
 		m.selectAddType()
 		return m, nil
 	case "enter":
@@ -375,10 +1186,11 @@ func (m *mainLoopModel) initAddMetaInputs() {
 	name.Focus()
 
 	folder := textinput.New()
-	folder.Placeholder = "Папка (можно пусто)"
+	folder.Placeholder = "Папка, напр. Работа/Почта (можно пусто)"
 	folder.Width = 40
+	folder.SetValue(m.defaultFolder)
 
-	m.addMetaInputs = []textinput.Model{name, folder}
+	m.addMetaInputs, m.addFields = newCustomFields([]textinput.Model{name, folder}, nil)
 	m.addMetaFocus = 0
 }
 
@@ -399,13 +1211,16 @@ func (m mainLoopModel) updateAddMeta(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.addMetaFocus = (m.addMetaFocus - 1 + len(m.addMetaInputs)) % len(m.addMetaInputs)
 			m.addMetaInputs[m.addMetaFocus].Focus()
 			return m, nil
+		case "ctrl+n", "ctrl+x", "ctrl+t":
+			m.addMetaInputs, m.addMetaFocus, _ = m.addFields.handleKey(keyMsg.String(), m.addMetaInputs, m.addMetaFocus)
+			return m, nil
 		case "enter":
-			name := strings.TrimSpace(m.addMetaInputs[0].Value())
-			folder := strings.TrimSpace(m.addMetaInputs[1].Value())
-			if name == "" {
-				m.addErr = "нужно название."
+			name, err := service.NormalizeItemName(m.addMetaInputs[0].Value())
+			if err != nil {
+				m.addErr = err.Error() + "."
 				return m, nil
 			}
+			folder := strings.TrimSpace(m.addMetaInputs[1].Value())
 
 			m.addPayload.Metadata.Name = name
 			if folder == "" {
@@ -414,10 +1229,14 @@ func (m mainLoopModel) updateAddMeta(msg tea.Msg) (tea.Model, tea.Cmd) {
 				f := folder
 				m.addPayload.Metadata.Folder = &f
 			}
+			m.addPayload.AdditionalFields = m.addFields.collect(m.addMetaInputs)
 
 			m.addErr = ""
 			m.addStage = addStageData
 			m.initAddDataInputs()
+			if m.addPayload.Type == models.Binary {
+				m.startAddFilePicker()
+			}
 			return m, nil
 		}
 	}
@@ -427,6 +1246,61 @@ func (m mainLoopModel) updateAddMeta(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// startAddFilePicker opens the built-in file browser for a Binary entry. The
+// browser starts in the directory of the path typed so far, or in the current
+// working directory when the path is empty.
+func (m *mainLoopModel) startAddFilePicker() {
+	dir := ""
+	if len(m.addDataInputs) > 0 {
+		if typed := strings.TrimSpace(m.addDataInputs[0].Value()); typed != "" {
+			if info, err := os.Stat(typed); err == nil && info.IsDir() {
+				dir = typed
+			} else {
+				dir = filepath.Dir(typed)
+			}
+		}
+	}
+
+	m.addFilePicker = newFilePicker(dir, m.maxAttachmentSize)
+	m.addErr = ""
+	m.addStage = addStageFile
+}
+
+func (m mainLoopModel) updateAddFile(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		m.resetAddFlow()
+	case "up":
+		m.addFilePicker.up()
+	case "down":
+		m.addFilePicker.down()
+	case "backspace", "left":
+		m.addFilePicker.parent()
+	case "tab":
+		m.addStage = addStageData
+		m.addDataInputs[0].Focus()
+	case "enter", "right":
+		path, selected := m.addFilePicker.enter()
+		if !selected {
+			return m, nil
+		}
+		m.addDataInputs[0].SetValue(path)
+		if err := m.collectAddTypedData(); err != nil {
+			m.addFilePicker.err = err.Error()
+			return m, nil
+		}
+		m.addErr = ""
+		m.startAddNotes()
+	}
+
+	return m, nil
+}
+
 func (m *mainLoopModel) initAddDataInputs() {
 	m.addDataInputs = nil
 	m.addDataFocus = 0
@@ -444,15 +1318,11 @@ func (m *mainLoopModel) initAddDataInputs() {
 		pass.EchoMode = textinput.EchoPassword
 		pass.EchoCharacter = '*'
 
-		uri := textinput.New()
-		uri.Placeholder = "URI"
-		uri.Width = 40
-
 		totp := textinput.New()
-		totp.Placeholder = "TOTP (необязательно)"
+		totp.Placeholder = "секрет или otpauth:// (необязательно)"
 		totp.Width = 40
 
-		m.addDataInputs = []textinput.Model{login, pass, uri, totp}
+		m.addDataInputs, m.addURIs = newURIFields([]textinput.Model{login, pass, totp}, nil)
 
 	case models.Text:
 		ta := textarea.New()
@@ -544,6 +1414,20 @@ func (m mainLoopModel) updateAddDataInputs(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc":
 			m.resetAddFlow()
 			return m, nil
+		case "ctrl+o":
+			if m.addPayload.Type == models.Binary {
+				m.startAddFilePicker()
+				return m, nil
+			}
+		case "ctrl+n", "ctrl+x", "ctrl+t":
+			if m.addPayload.Type == models.LoginPassword {
+				m.addDataInputs, m.addDataFocus, _ = m.addURIs.handleKey(keyMsg.String(), m.addDataInputs, m.addDataFocus)
+				return m, nil
+			}
+		case "ctrl+g":
+			if m.addPayload.Type == models.LoginPassword {
+				return m, m.generatePassword(&m.addDataInputs[1], &m.addInfo, &m.addErr)
+			}
 		case "tab":
 			m.addDataInputs[m.addDataFocus].Blur()
 			m.addDataFocus = (m.addDataFocus + 1) % len(m.addDataInputs)
@@ -578,20 +1462,19 @@ func (m *mainLoopModel) collectAddTypedData() error {
 	case models.LoginPassword:
 		login := strings.TrimSpace(m.addDataInputs[0].Value())
 		pass := strings.TrimSpace(m.addDataInputs[1].Value())
-		uri := strings.TrimSpace(m.addDataInputs[2].Value())
-		totpRaw := strings.TrimSpace(m.addDataInputs[3].Value())
+		totpRaw := strings.TrimSpace(m.addDataInputs[2].Value())
 
 		if login == "" || pass == "" {
 			return fmt.Errorf("логин и пароль обязательны")
 		}
 
-		data := &models.LoginData{Username: login, Password: pass}
-		if uri != "" {
-			data.URIs = []models.LoginURI{{URI: uri, Match: 0}}
-		}
+		data := &models.LoginData{Username: login, Password: pass, URIs: m.addURIs.collect(m.addDataInputs)}
 		if totpRaw != "" {
-			totp := totpRaw
-			data.TOTP = &totp
+			totp, err := service.ParseTOTP(totpRaw)
+			if err != nil {
+				return err
+			}
+			totp.ApplyTo(data)
 		}
 		m.addPayload.LoginData = data
 		return nil
@@ -602,13 +1485,13 @@ func (m *mainLoopModel) collectAddTypedData() error {
 			return fmt.Errorf("нужно указать путь к файлу")
 		}
 
+		if err := validateAttachment(path, m.maxAttachmentSize); err != nil {
+			return err
+		}
 		info, err := os.Stat(path)
 		if err != nil {
 			return fmt.Errorf("файл не найден")
 		}
-		if info.IsDir() {
-			return fmt.Errorf("укажите путь к файлу, а не к папке")
-		}
 
 		m.addPayload.BinaryData = &models.BinaryData{
 			ID:       fmt.Sprintf("bin-%d", time.Now().UnixNano()),
@@ -616,6 +1499,14 @@ func (m *mainLoopModel) collectAddTypedData() error {
 			Size:     info.Size(),
 			Key:      "",
 		}
+		// The file travels inside the encrypted payload, sealed in chunks,
+		// so that it syncs with the entry; validateAttachment already
+		// bounded its size.
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("не удалось прочитать файл: %w", err)
+		}
+		m.addPayload.BinaryData.Content = content
 		return nil
 
 	case models.BankCard:
@@ -674,6 +1565,10 @@ func (m mainLoopModel) updateAddNotes(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			notesText := strings.TrimSpace(m.addNotesArea.Value())
+			if err := checkNotesLength(notesLength(notesText), m.maxNotesLength); err != nil {
+				m.addErr = err.Error()
+				return m, nil
+			}
 			payload := m.addPayload
 			if notesText != "" {
 				payload.Notes = &models.Notes{Notes: notesText}
@@ -693,24 +1588,37 @@ func (m mainLoopModel) updateAddNotes(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *mainLoopModel) startAddFlow() {
 	m.addStage = addStageType
 	m.addTypeIdx = 0
+	for i, t := range m.addTypeOptions {
+		if t == m.defaultAddType {
+			m.addTypeIdx = i
+			break
+		}
+	}
 	m.addErr = ""
+	m.addInfo = ""
 	m.addSaving = false
 	m.addPayload = models.DecipheredPayload{}
 	m.addMetaInputs = nil
 	m.addDataInputs = nil
 	m.addMetaFocus = 0
 	m.addDataFocus = 0
+	m.addURIs = uriFields{}
+	m.addFields = customFields{}
 }
 
 func (m *mainLoopModel) resetAddFlow() {
 	m.addStage = addStageNone
+	m.confirmDiscard = false
 	m.addErr = ""
+	m.addInfo = ""
 	m.addSaving = false
 	m.addPayload = models.DecipheredPayload{}
 	m.addMetaInputs = nil
 	m.addDataInputs = nil
 	m.addMetaFocus = 0
 	m.addDataFocus = 0
+	m.addURIs = uriFields{}
+	m.addFields = customFields{}
 }
 
 func (m mainLoopModel) View() string {
@@ -718,6 +1626,14 @@ func (m mainLoopModel) View() string {
 		return renderBuildInfoWindow(m.buildInfo)
 	}
 
+	if m.confirmDiscard {
+		return m.viewDiscardConfirm()
+	}
+
+	if m.reprompting {
+		return m.viewReprompt()
+	}
+
 	switch m.addStage {
 	case addStageType:
 		return m.viewAddType()
@@ -727,6 +1643,8 @@ func (m mainLoopModel) View() string {
 		return m.viewAddData()
 	case addStageNotes:
 		return m.viewAddNotes()
+	case addStageFile:
+		return m.viewAddFile()
 	}
 
 	if m.editing {
@@ -742,11 +1660,23 @@ func (m mainLoopModel) View() string {
 			out += "Срок (мм) : [" + m.editInputs[5].View() + "]\n"
 			out += "Срок (гг) : [" + m.editInputs[6].View() + "]\n"
 			out += "CVV       : [" + m.editInputs[7].View() + "]\n"
+			out += "Защита    : " + repromptLabel(m.editReprompt) + "\n\n"
+			out += "[ ПОЛЯ ]\n"
+			out += m.editFields.view(m.editInputs, ":")
 		} else {
 			out += "Поле      │ Значение\n"
 			out += "──────────┼──────────────────────────────────────────\n"
 			out += "Название  │ [" + m.editInputs[0].View() + "]\n"
 			out += "Папка     │ [" + m.editInputs[1].View() + "]\n"
+			out += "Защита    │ " + repromptLabel(m.editReprompt) + "\n"
+			if m.editPayload.Type == models.LoginPassword && m.editURIs.start > editPasswordInput {
+				out += "Пароль    │ [" + m.editInputs[editPasswordInput].View() + "]\n"
+				out += m.editURIs.view(m.editInputs, "│")
+			}
+			out += m.editFields.view(m.editInputs, "│")
+		}
+		if m.editInfo != "" {
+			out += "\n" + m.editInfo + "\n"
 		}
 		if m.editSubmitting {
 			out += "\n[Сохранение...]\n"
@@ -754,9 +1684,33 @@ func (m mainLoopModel) View() string {
 			out += "\n[Сохранить]\n"
 		}
 		if m.errMsg != "" {
-			out += "Ошибка: " + m.errMsg + "\n"
+			out += errorLine(m.errMsg) + "\n"
 		}
-		return renderPage("ИЗМЕНЕНИЕ ЗАПИСИ", strings.TrimRight(out, "\n"), "esc: назад │ tab: след. поле │ shift+tab: пред. поле │ enter: сохранить")
+		hotKeys := "esc: назад │ tab: след. поле │ shift+tab: пред. поле │ " + fieldHotKeys + " │ ctrl+r: запрос пароля │ enter: сохранить"
+		if m.editPayload.Type == models.LoginPassword {
+			hotKeys = "esc: назад │ tab: след. поле │ shift+tab: пред. поле │ ctrl+g: сгенерировать пароль │ " + uriFieldHotKeys + " │ ctrl+r: запрос пароля │ enter: сохранить"
+		}
+		return renderPage("ИЗМЕНЕНИЕ ЗАПИСИ", strings.TrimRight(out, "\n"), hotKeys)
+	}
+
+	if m.conflict != nil {
+		return m.viewConflict()
+	}
+
+	if m.history {
+		return m.viewHistory()
+	}
+
+	if m.summary {
+		return m.viewSummary()
+	}
+
+	if m.trash {
+		return m.viewTrash()
+	}
+
+	if m.moving {
+		return m.viewMove()
 	}
 
 	if m.detail {
@@ -765,7 +1719,22 @@ func (m mainLoopModel) View() string {
 			return renderPage("ПРОСМОТР ЗАПИСИ", "Запись не найдена", "esc: назад")
 		}
 
+		if m.isUndecryptable(item) {
+			return renderPage("НЕ РАСШИФРОВАНО", viewUndecryptable(item), "ctrl+d: удалить │ esc: назад")
+		}
+
+		if m.preview {
+			return m.viewBinaryPreview(item)
+		}
+
+		if m.totpQR {
+			return m.viewTOTPQR(item.Metadata.Name)
+		}
+
 		title, out, hotKeys := m.viewDetail(item)
+		if m.isReadOnly(item) {
+			out = theme.Attention.Render(readOnlyStatus(item.Type)) + "\n\n" + out
+		}
 		return renderPage(title, strings.TrimRight(out, "\n"), hotKeys)
 	}
 
@@ -773,80 +1742,82 @@ func (m mainLoopModel) View() string {
 
 	if m.loading {
 		out += "Загрузка списка...\n"
-		return renderPage("ГЛАВНАЯ СТРАНИЦА", strings.TrimRight(out, "\n"), "a: добавить │ s: синхр. │ enter: открыть │ e: изм. │ ctrl+d: уд. │ ↑/↓: нав. │ l: выйти │ v: версия")
+		return renderPage(m.mainTitle(), strings.TrimRight(out, "\n"), m.mainHotKeys())
 	}
 
 	if m.errMsg != "" {
-		out += "Ошибка: " + m.errMsg + "\n"
+		out += errorLine(m.errMsg) + "\n"
 	}
 
+	if m.weakPasswordHint {
+		out += theme.Attention.Render(weakPasswordHintText) + "\n"
+	}
 	if m.status != "" {
 		out += "Статус: " + m.status + "\n"
 	}
+	if len(m.dupGroups) > 0 {
+		group := m.dupGroups[0]
+		out += fmt.Sprintf("Найдены дубликаты «%s» (%d шт.). y: объединить │ n: пропустить\n", group.Name, len(group.Duplicates)+1)
+	}
+	out += formatLastSynced(m.lastSyncedAt, m.syncStaleAfter, m.clock.Now()) + "\n"
+	if line := formatSyncBreaker(m.syncBreaker); line != "" {
+		out += line + "\n"
+	}
 	if m.debug {
 		out += fmt.Sprintf("DEBUG: user_id=%d session_user_id=%d\n", m.userID, getSessionUserID())
 	}
 
-	if len(m.items) == 0 {
+	visible := m.visibleItems()
+	out += m.viewSearch(len(visible))
+
+	if len(visible) == 0 {
 		if out != "" {
 			out += "\n"
 		}
-		out += "Записей нет\n"
+		if len(m.items) == 0 {
+			out += "Записей нет\n"
+		} else {
+			out += "Ничего не найдено\n"
+		}
 	} else {
 		if out != "" {
 			out += "\n"
 		}
-		out += "ID   │ Наименование             │ Тип             │ Папка\n"
-		out += "─────┼──────────────────────────┼─────────────────┼────────────────\n"
-		for i, item := range m.items {
-			cursor := " "
-			if i == m.idx {
-				cursor = ">"
-			}
-
-			out += fmt.Sprintf(
-				"%s %-3d│ %-24s │ %-15s │ %s\n",
-				cursor,
-				i+1,
-				fitText(item.Metadata.Name, 24),
-				fitText(dataTypeLabel(item.Type), 15),
-				valueOrDash(item.Metadata.Folder),
-			)
+		if m.folderTree {
+			out += viewFolderTree(resolveListColumns(m.listColumns), m.treeRows(), m.idx, m.selected, m.maskNames)
+		} else {
+			out += viewListTable(resolveListColumns(m.listColumns), visible, m.idx, m.selected, m.maskNames)
 		}
 	}
 
-	return renderPage(
-		"ГЛАВНАЯ СТРАНИЦА",
-		strings.TrimRight(out, "\n"),
-		"a: добавить │ s: синхр. │ enter: открыть │ e: изм. │ ctrl+d: уд. │ ↑/↓: нав. │ l: выйти │ v: версия",
-	)
+	return renderPage(m.mainTitle(), strings.TrimRight(out, "\n"), m.mainHotKeys())
 }
 
 func (m mainLoopModel) viewAddType() string {
 	out := ""
 	for i, t := range m.addTypeOptions {
-		cursor := " "
-		if i == m.addTypeIdx {
-			cursor = ">"
-		}
+		cursor := cursorMark(i == m.addTypeIdx)
 		out += fmt.Sprintf("%s %d. %s\n", cursor, i+1, dataTypeLabel(t))
 	}
 	if m.addErr != "" {
-		out += "\nОшибка: " + m.addErr + "\n"
+		out += "\n" + errorLine(m.addErr) + "\n"
 	}
 
-	return renderPage("ДОБАВИТЬ: ВЫБОР ТИПА", strings.TrimRight(out, "\n"), "1-4/enter: выбрать │ ↑/↓: навигация │ esc: отмена")
+	hotKeys := fmt.Sprintf("1-%d/enter: выбрать │ ↑/↓: навигация │ esc: отмена", len(m.addTypeOptions))
+	return renderPage("ДОБАВИТЬ: ВЫБОР ТИПА", strings.TrimRight(out, "\n"), hotKeys)
 }
 
 func (m mainLoopModel) viewAddMeta() string {
 	out := "[ ОСНОВНОЕ ]\n"
 	out += "Название  : [ " + m.addMetaInputs[0].View() + " ]\n"
-	out += "Папка     : [ " + m.addMetaInputs[1].View() + " ]\n"
+	out += "Папка     : [ " + m.addMetaInputs[1].View() + " ]\n\n"
+	out += "[ ПОЛЯ ]\n"
+	out += m.addFields.view(m.addMetaInputs, ":")
 	if m.addErr != "" {
-		out += "\nОшибка: " + m.addErr + "\n"
+		out += "\n" + errorLine(m.addErr) + "\n"
 	}
 
-	return renderPage("ДОБАВИТЬ: МЕТАДАННЫЕ", strings.TrimRight(out, "\n"), "tab: след. поле │ shift+tab: пред. поле │ enter: далее │ esc: отмена")
+	return renderPage("ДОБАВИТЬ: МЕТАДАННЫЕ", strings.TrimRight(out, "\n"), "tab: след. поле │ shift+tab: пред. поле │ "+fieldHotKeys+" │ enter: далее │ esc: отмена")
 }
 
 func (m mainLoopModel) viewAddData() string {
@@ -859,19 +1830,22 @@ func (m mainLoopModel) viewAddData() string {
 		out := meta
 		out += "Логин     : [ " + m.addDataInputs[0].View() + " ]\n"
 		out += "Пароль    : [ " + m.addDataInputs[1].View() + " ]\n"
-		out += "URI       : [ " + m.addDataInputs[2].View() + " ]\n"
-		out += "TOTP      : [ " + m.addDataInputs[3].View() + " ]\n"
+		out += "TOTP      : [ " + m.addDataInputs[2].View() + " ]\n"
+		out += m.addURIs.view(m.addDataInputs, ":")
+		if m.addInfo != "" {
+			out += "\n" + m.addInfo + "\n"
+		}
 		if m.addErr != "" {
-			out += "\nОшибка: " + m.addErr + "\n"
+			out += "\n" + errorLine(m.addErr) + "\n"
 		}
-		return renderPage("НОВАЯ ЗАПИСЬ: Логин/Пароль", strings.TrimRight(out, "\n"), "tab: след. поле │ shift+tab: пред. поле │ enter: сохранить │ esc: отмена")
+		return renderPage("НОВАЯ ЗАПИСЬ: Логин/Пароль", strings.TrimRight(out, "\n"), "tab: след. поле │ shift+tab: пред. поле │ ctrl+g: сгенерировать пароль │ "+uriHotKeys+" │ enter: сохранить │ esc: отмена")
 
 	case models.Text:
 		out := meta
 		out += "Текст:\n"
 		out += m.addTextArea.View()
 		if m.addErr != "" {
-			out += "\nОшибка: " + m.addErr + "\n"
+			out += "\n" + errorLine(m.addErr) + "\n"
 		}
 		return renderPage("НОВАЯ ЗАПИСЬ: Текстовые данные", strings.TrimRight(out, "\n"), "enter: новая строка │ ctrl+s: сохранить │ esc: отмена")
 
@@ -881,9 +1855,9 @@ func (m mainLoopModel) viewAddData() string {
 		out += "Путь      : [ " + m.addDataInputs[0].View() + " ]\n\n"
 		out += "Файл      : " + binaryPreview(path) + "\n"
 		if m.addErr != "" {
-			out += "\nОшибка: " + m.addErr + "\n"
+			out += "\n" + errorLine(m.addErr) + "\n"
 		}
-		return renderPage("НОВАЯ ЗАПИСЬ: Файл", strings.TrimRight(out, "\n"), "tab: след. поле │ enter: сохранить │ esc: отмена")
+		return renderPage("НОВАЯ ЗАПИСЬ: Файл", strings.TrimRight(out, "\n"), "ctrl+o: обзор │ enter: сохранить │ esc: отмена")
 
 	case models.BankCard:
 		out := meta
@@ -894,7 +1868,7 @@ func (m mainLoopModel) viewAddData() string {
 		out += "Срок (гг) : [ " + m.addDataInputs[4].View() + " ]\n"
 		out += "CVV       : [ " + m.addDataInputs[5].View() + " ]\n"
 		if m.addErr != "" {
-			out += "\nОшибка: " + m.addErr + "\n"
+			out += "\n" + errorLine(m.addErr) + "\n"
 		}
 		return renderPage("НОВАЯ ЗАПИСЬ: Банковская карта", strings.TrimRight(out, "\n"), "tab: след. поле │ shift+tab: пред. поле │ enter: сохранить │ esc: отмена")
 	}
@@ -902,11 +1876,21 @@ func (m mainLoopModel) viewAddData() string {
 	return renderPage("НОВАЯ ЗАПИСЬ", "Неизвестный тип", "esc: отмена")
 }
 
+func (m mainLoopModel) viewAddFile() string {
+	out := "[ ОСНОВНОЕ ]\n"
+	out += "Название  : " + m.addPayload.Metadata.Name + "\n"
+	out += "Папка     : " + valueOrDash(m.addPayload.Metadata.Folder) + "\n\n"
+	out += m.addFilePicker.view()
+
+	return renderPage("НОВАЯ ЗАПИСЬ: Выбор файла", strings.TrimRight(out, "\n"), "↑/↓: нав. │ enter: открыть/выбрать │ backspace: вверх │ tab: ввести путь │ esc: отмена")
+}
+
 func (m mainLoopModel) viewAddNotes() string {
 	out := "[ ЗАМЕТКИ ]\n"
-	out += m.addNotesArea.View()
+	out += m.addNotesArea.View() + "\n"
+	out += m.notesCounter() + "\n"
 	if m.addErr != "" {
-		out += "\nОшибка: " + m.addErr + "\n"
+		out += "\n" + errorLine(m.addErr) + "\n"
 	}
 	if m.addSaving {
 		out += "\nСохранение...\n"
@@ -915,24 +1899,155 @@ func (m mainLoopModel) viewAddNotes() string {
 	return renderPage("ЗАМЕТКИ", strings.TrimRight(out, "\n"), "enter: новая строка │ ctrl+s: сохранить │ esc: отмена")
 }
 
+// offlineDisabledKeys are the list and detail keys that sync or modify the
+// vault; they are refused in offline mode.
+var offlineDisabledKeys = map[string]bool{
+	"a":      true,
+	"s":      true,
+	"e":      true,
+	"ctrl+d": true,
+	"y":      true,
+	"h":      true,
+	"m":      true,
+	"f":      true,
+}
+
+func (m mainLoopModel) mainTitle() string {
+	if m.offline {
+		return "ГЛАВНАЯ СТРАНИЦА │ офлайн-режим"
+	}
+	if label := m.connectivity.label(); label != "" {
+		return "ГЛАВНАЯ СТРАНИЦА │ " + label
+	}
+	return "ГЛАВНАЯ СТРАНИЦА"
+}
+
+func (m mainLoopModel) mainHotKeys() string {
+	if m.searching {
+		return searchHotKeys
+	}
+	if m.offline {
+		return "enter: открыть │ c: копировать │ g: папки │ /: поиск │ i: состав │ T: корзина │ p: приватность │ k: набор восст. │ ↑/↓: нав. │ l: выйти │ v: версия"
+	}
+	return "a: добавить │ s: синхр. │ enter: открыть │ c: копировать │ e: изм. │ f: избранное │ ctrl+d: уд. │ x: отметить │ m: переместить │ g: папки │ /: поиск │ i: состав │ T: корзина │ p: приватность │ k: набор восст. │ ↑/↓: нав. │ l: выйти │ v: версия"
+}
+
+// undecryptableLabel names the placeholder row shown for an item that could
+// not be decrypted.
+const undecryptableLabel = "⚠ не удалось расшифровать"
+
+// weakPasswordHintText is shown once after a login with a weak master password.
+const weakPasswordHintText = "⚠ Мастер-пароль слабый и легко подбирается. Рекомендуем сменить его на более длинный (любая клавиша — скрыть)"
+
+// isUndecryptable reports whether item is a placeholder for a record that
+// failed to decrypt.
+func (m mainLoopModel) isUndecryptable(item models.DecipheredPayload) bool {
+	return m.undecryptable[item.ClientSideID]
+}
+
+func viewUndecryptable(item models.DecipheredPayload) string {
+	out := "Запись не удалось расшифровать.\n"
+	out += "ID        : " + item.ClientSideID + "\n\n"
+	out += "Данные повреждены или зашифрованы другим ключом.\n"
+	out += "Запись можно удалить."
+	return out
+}
+
+// current returns the selected entry, with its full payload if it has been
+// decrypted.
 func (m mainLoopModel) current() (models.DecipheredPayload, bool) {
-	if len(m.items) == 0 || m.idx < 0 || m.idx >= len(m.items) {
-		return models.DecipheredPayload{}, false
+	var item models.DecipheredPayload
+	if m.folderTree {
+		row, ok := m.currentRow()
+		if !ok || row.folder != nil {
+			return models.DecipheredPayload{}, false
+		}
+		item = row.item
+	} else {
+		visible := m.visibleItems()
+		if len(visible) == 0 || m.idx < 0 || m.idx >= len(visible) {
+			return models.DecipheredPayload{}, false
+		}
+		item = visible[m.idx]
+	}
+	if full, ok := m.opened[item.ClientSideID]; ok {
+		return full, true
+	}
+	return item, true
+}
+
+// isDecrypted reports whether the full payload of item is available. The
+// placeholders of undecryptable entries count as decrypted: there is nothing
+// more to load.
+func (m mainLoopModel) isDecrypted(item models.DecipheredPayload) bool {
+	if m.itemsFull || m.isUndecryptable(item) {
+		return true
+	}
+	_, ok := m.opened[item.ClientSideID]
+	return ok
+}
+
+// keepOpenedDetail drops the payloads decrypted on demand after the list has
+// been reloaded, except the one shown in the detail or history view, and
+// returns the command that decrypts that one again from the reloaded data.
+func (m *mainLoopModel) keepOpenedDetail() tea.Cmd {
+	opened := m.opened
+	m.opened = nil
+	if m.itemsFull || (!m.detail && !m.history) {
+		return nil
+	}
+
+	item, ok := m.current()
+	if !ok {
+		return nil
+	}
+	full, ok := opened[item.ClientSideID]
+	if !ok {
+		return nil
 	}
-	return m.items[m.idx], true
+	m.opened = map[string]models.DecipheredPayload{full.ClientSideID: full}
+	return m.cmdOpenItem(full.ClientSideID, openRefresh)
 }
 
+// cmdLoadItems reloads the list. Only metadata is decrypted unless the
+// search also matches notes and usernames, which needs the full payloads.
 func (m mainLoopModel) cmdLoadItems() tea.Cmd {
 	ctx := m.ctx
 	svc := m.services.PrivateDataService
+	syncSvc := m.services.SyncService
+	full := m.searchDeep
 
 	return func() tea.Msg {
 		userID := m.activeUserID()
 		if userID <= 0 {
 			return listLoadedMsg{err: errUserIDNotSet}
 		}
-		items, err := svc.GetAll(ctx, userID)
-		return listLoadedMsg{items: items, err: err}
+		getAll := svc.GetAllMeta
+		if full {
+			getAll = svc.GetAll
+		}
+		items, failed, err := getAll(ctx, userID)
+		msg := listLoadedMsg{items: items, full: full, failed: failed, err: err}
+		if syncedAt, syncErr := syncSvc.LastSyncedAt(ctx, userID); syncErr == nil {
+			msg.lastSyncedAt = &syncedAt
+		}
+		return msg
+	}
+}
+
+// cmdOpenItem decrypts the full payload of the entry clientSideID and then
+// performs action.
+func (m mainLoopModel) cmdOpenItem(clientSideID string, action openAction) tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.PrivateDataService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return itemOpenedMsg{action: action, err: errUserIDNotSet}
+		}
+		item, err := svc.Get(ctx, clientSideID, userID)
+		return itemOpenedMsg{item: item, action: action, err: err}
 	}
 }
 
@@ -946,7 +2061,59 @@ func (m mainLoopModel) cmdSync() tea.Cmd {
 			return syncDoneMsg{err: errUserIDNotSet}
 		}
 		err := svc.FullSync(ctx, userID)
-		return syncDoneMsg{err: err}
+		return syncDoneMsg{err: err, breaker: svc.BreakerStatus()}
+	}
+}
+
+// afterChange reloads the list after a successful create, update or delete
+// and, with syncOnChange set, pushes the change with a sync unless one is
+// already running.
+func (m mainLoopModel) afterChange() (tea.Model, tea.Cmd) {
+	if !m.syncOnChange || m.offline || m.syncing {
+		return m, m.cmdLoadItems()
+	}
+	m.syncing = true
+	return m, tea.Batch(m.cmdLoadItems(), m.cmdAutoSync())
+}
+
+func (m mainLoopModel) cmdAutoSync() tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.SyncService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return autoSyncDoneMsg{err: errUserIDNotSet}
+		}
+		err := svc.FullSync(ctx, userID)
+		return autoSyncDoneMsg{err: err, breaker: svc.BreakerStatus()}
+	}
+}
+
+func (m mainLoopModel) cmdFindDuplicates() tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.SyncService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return duplicatesFoundMsg{err: errUserIDNotSet}
+		}
+		groups, err := svc.FindDuplicates(ctx, userID)
+		return duplicatesFoundMsg{groups: groups, err: err}
+	}
+}
+
+func (m mainLoopModel) cmdMergeDuplicates(group models.DuplicateGroup) tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.SyncService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return mergeDoneMsg{err: errUserIDNotSet}
+		}
+		return mergeDoneMsg{err: svc.MergeDuplicates(ctx, userID, group)}
 	}
 }
 
@@ -984,6 +2151,54 @@ func (m mainLoopModel) cmdUpdate(payload models.DecipheredPayload) tea.Cmd {
 	}
 }
 
+func (m mainLoopModel) cmdLoadHistory(clientSideID string) tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.PrivateDataService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return historyLoadedMsg{err: errUserIDNotSet}
+		}
+		versions, err := svc.GetHistory(ctx, userID, clientSideID)
+		return historyLoadedMsg{versions: versions, err: err}
+	}
+}
+
+func (m mainLoopModel) cmdRestore(clientSideID string, version int64) tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.PrivateDataService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return restoreDoneMsg{err: errUserIDNotSet}
+		}
+		return restoreDoneMsg{err: svc.RestoreVersion(ctx, userID, clientSideID, version)}
+	}
+}
+
+// cmdRecoveryKit writes the recovery kit of the active user into
+// recoveryKitDir; see [service.WriteRecoveryKit].
+func (m mainLoopModel) cmdRecoveryKit() tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.AuthService
+	dir := m.recoveryKitDir
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return recoveryKitSavedMsg{err: errUserIDNotSet}
+		}
+		kit, err := svc.RecoveryKit(ctx, userID)
+		if err != nil {
+			return recoveryKitSavedMsg{err: err}
+		}
+		path, err := service.WriteRecoveryKit(dir, kit)
+		return recoveryKitSavedMsg{path: path, err: err}
+	}
+}
+
 func (m mainLoopModel) cmdCreate(payload models.DecipheredPayload) tea.Cmd {
 	ctx := m.ctx
 	svc := m.services.PrivateDataService
@@ -1057,11 +2272,32 @@ func (m *mainLoopModel) startEdit(item models.DecipheredPayload) {
 		inputs = append(inputs, holder, number, brand, month, year, cvv)
 	}
 
+	m.editURIs = uriFields{}
+	if item.Type == models.LoginPassword && item.LoginData != nil {
+		pass := textinput.New()
+		pass.Placeholder = "password"
+		pass.Width = 40
+		pass.EchoMode = textinput.EchoPassword
+		pass.EchoCharacter = '*'
+		pass.SetValue(item.LoginData.Password)
+
+		inputs = append(inputs, pass)
+		inputs, m.editURIs = newURIFields(inputs, item.LoginData.URIs)
+	}
+	var fields []models.CustomField
+	if item.AdditionalFields != nil {
+		fields = *item.AdditionalFields
+	}
+	inputs, m.editFields = newCustomFields(inputs, fields)
+
 	m.editInputs = inputs
+	m.editOriginal = inputValues(inputs)
 	m.editFocus = 0
 	m.editSubmitting = false
 	m.editPayload = item
+	m.editReprompt = item.Metadata.Reprompt
 	m.editing = true
+	m.editInfo = ""
 	m.errMsg = ""
 }
 
@@ -1075,11 +2311,41 @@ func (m mainLoopModel) activeUserID() int64 {
 	return 0
 }
 
+// statusCanceled is shown instead of an error when an operation was aborted
+// because the session context was canceled.
+const statusCanceled = "отменено"
+
+func isCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// keyMissing reports whether the private-data service has lost its DEK, in
+// which case create, edit and detail views cannot encrypt or decrypt.
+func (m mainLoopModel) keyMissing() bool {
+	return m.services.PrivateDataService != nil && !m.services.PrivateDataService.HasEncryptionKey()
+}
+
+// reauthenticate ends the main loop the same way an explicit logout does, so
+// that the caller sends the user back through login to obtain the DEK again.
+func (m mainLoopModel) reauthenticate() (tea.Model, tea.Cmd) {
+	m.status = service.ErrKeyNotAvailable.Error()
+	m.logout = true
+	m.cancel()
+	return m, tea.Quit
+}
+
 func syncErrorMessage(err error) string {
 	if err == nil {
 		return ""
 	}
 
+	if errors.Is(err, service.ErrIncompatibleServerSchema) {
+		return "Синхронизация заблокирована: " + err.Error()
+	}
+	if errors.Is(err, service.ErrServerUnavailable) {
+		return "Синхронизация не выполнена: " + err.Error()
+	}
+
 	s := strings.ToLower(err.Error())
 	if strings.Contains(s, "connection refused") ||
 		strings.Contains(s, "dial tcp") ||
@@ -1093,6 +2359,10 @@ func syncErrorMessage(err error) string {
 	return fmt.Sprintf("Ошибка синхронизации: %v", err)
 }
 
+// editPasswordInput is the index of the password input in the login edit
+// form, after the name and the folder.
+const editPasswordInput = 2
+
 func (m mainLoopModel) updateEditing(msg tea.Msg) (tea.Model, tea.Cmd) {
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if ok {
@@ -1102,6 +2372,25 @@ func (m mainLoopModel) updateEditing(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.editSubmitting = false
 			m.errMsg = ""
 			return m, nil
+		case "ctrl+n", "ctrl+x", "ctrl+t":
+			before := len(m.editInputs)
+			var handled bool
+			m.editInputs, m.editFocus, handled = m.editURIs.handleKey(keyMsg.String(), m.editInputs, m.editFocus)
+			if handled {
+				// The custom fields follow the URIs.
+				m.editFields.start += len(m.editInputs) - before
+				return m, nil
+			}
+			m.editInputs, m.editFocus, _ = m.editFields.handleKey(keyMsg.String(), m.editInputs, m.editFocus)
+			return m, nil
+		case "ctrl+r":
+			m.editReprompt = !m.editReprompt
+			return m, nil
+		case "ctrl+g":
+			if m.editPayload.Type == models.LoginPassword && m.editURIs.start > editPasswordInput {
+				return m, m.generatePassword(&m.editInputs[editPasswordInput], &m.editInfo, &m.errMsg)
+			}
+			return m, nil
 		case "tab":
 			m.editInputs[m.editFocus].Blur()
 			m.editFocus = (m.editFocus + 1) % len(m.editInputs)
@@ -1117,15 +2406,16 @@ func (m mainLoopModel) updateEditing(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-			name := strings.TrimSpace(m.editInputs[0].Value())
-			folder := strings.TrimSpace(m.editInputs[1].Value())
-			if name == "" {
-				m.errMsg = "Название обязательно"
+			name, err := service.NormalizeItemName(m.editInputs[0].Value())
+			if err != nil {
+				m.errMsg = err.Error()
 				return m, nil
 			}
+			folder := strings.TrimSpace(m.editInputs[1].Value())
 
 			payload := m.editPayload
 			payload.Metadata.Name = name
+			payload.Metadata.Reprompt = m.editReprompt
 			if folder == "" {
 				payload.Metadata.Folder = nil
 			} else {
@@ -1163,6 +2453,15 @@ func (m mainLoopModel) updateEditing(msg tea.Msg) (tea.Model, tea.Cmd) {
 				payload.BankCardData.ExpYear = year
 				payload.BankCardData.Code = cvv
 			}
+			if payload.Type == models.LoginPassword && payload.LoginData != nil && len(m.editURIs.matches) > 0 {
+				// Copy so that the listed item keeps its password and URIs
+				// until the update is saved.
+				data := *payload.LoginData
+				data.Password = m.editInputs[editPasswordInput].Value()
+				data.URIs = m.editURIs.collect(m.editInputs)
+				payload.LoginData = &data
+			}
+			payload.AdditionalFields = m.editFields.collect(m.editInputs)
 
 			m.errMsg = ""
 			m.editSubmitting = true
@@ -1183,7 +2482,15 @@ func (m mainLoopModel) viewDetail(item models.DecipheredPayload) (title, body, h
 
 	b.WriteString("[ ОСНОВНОЕ ]\n")
 	b.WriteString("Название  : " + item.Metadata.Name + "\n")
-	b.WriteString("Папка     : " + valueOrDash(item.Metadata.Folder) + "\n\n")
+	b.WriteString("Папка     : " + valueOrDash(item.Metadata.Folder) + "\n")
+	if item.Metadata.Favorite {
+		b.WriteString("Избранное : да\n")
+	}
+	if item.Metadata.Reprompt {
+		b.WriteString("Защита    : запрос мастер-пароля\n")
+	}
+	b.WriteString("\n")
+	locked := m.needsReprompt(item)
 
 	switch item.Type {
 	case models.LoginPassword:
@@ -1197,24 +2504,34 @@ func (m mainLoopModel) viewDetail(item models.DecipheredPayload) (title, body, h
 				password := maskSecret(item.LoginData.Password, m.detailRevealSensitive)
 				b.WriteString("Пароль    : " + password + "  [пробел: показать]\n")
 			}
-			if len(item.LoginData.URIs) > 0 && item.LoginData.URIs[0].URI != "" {
-				b.WriteString("URI       : " + item.LoginData.URIs[0].URI + "\n")
-			}
-			if item.LoginData.TOTP != nil && *item.LoginData.TOTP != "" {
-				b.WriteString("TOTP      : " + *item.LoginData.TOTP + "\n")
+			b.WriteString(viewLoginURIs(item.LoginData.URIs))
+			if totp, ok := service.TOTPFromLoginData(item.LoginData); ok {
+				b.WriteString("TOTP      : " + totp.Secret + "\n")
+				now := m.clock.Now()
+				if code, err := totp.Code(now); err == nil {
+					b.WriteString(fmt.Sprintf("Код TOTP  : %s (ещё %d с)\n", code, int(totp.Remaining(now).Seconds())))
+				}
 			}
 		}
-		hotKeys = "e: изменить │ c: копировать пароль │ ctrl+d: удалить │ пробел: показать │ esc: назад"
+		hotKeys = "e: изменить │ c: копировать пароль │ ctrl+d: удалить │ h: история │ x: экспорт │ пробел: показать │ esc: назад"
+		if _, ok := service.TOTPShareURI(item); ok {
+			hotKeys = "q: QR-код TOTP │ " + hotKeys
+		}
+		if item.LoginData != nil && len(item.LoginData.URIs) > 0 {
+			hotKeys = "O: открыть URI │ alt+1-9: копировать URI │ " + hotKeys
+		}
 
 	case models.Text:
 		title = "ЗАМЕТКА: " + item.Metadata.Name
 		b.WriteString("[ ТЕКСТ ]\n")
-		if item.TextData != nil && item.TextData.Text != "" {
+		if item.TextData != nil && item.TextData.Text != "" && locked {
+			b.WriteString(repromptHidden() + "\n")
+		} else if item.TextData != nil && item.TextData.Text != "" {
 			b.WriteString(item.TextData.Text + "\n")
 		} else {
 			b.WriteString("(пусто)\n")
 		}
-		hotKeys = "e: изменить │ c: копировать текст │ ctrl+d: удалить │ esc: назад"
+		hotKeys = "e: изменить │ c: копировать текст │ ctrl+d: удалить │ h: история │ x: экспорт │ esc: назад"
 
 	case models.Binary:
 		title = "ФАЙЛ: " + item.Metadata.Name
@@ -1226,11 +2543,14 @@ func (m mainLoopModel) viewDetail(item models.DecipheredPayload) (title, body, h
 			if item.BinaryData.Size > 0 {
 				b.WriteString("Размер    : " + formatSize(item.BinaryData.Size) + "\n")
 			}
+			if err := service.ValidateBinarySize(item.BinaryData.Size, m.maxAttachmentSize); err != nil {
+				b.WriteString("⚠ " + err.Error() + " — не синхронизируется\n")
+			}
 			if item.BinaryData.ID != "" {
 				b.WriteString("ID        : " + item.BinaryData.ID + "\n")
 			}
 		}
-		hotKeys = "e: изменить │ ctrl+d: удалить │ esc: назад"
+		hotKeys = "enter: предпросмотр │ e: изменить │ ctrl+d: удалить │ h: история │ x: экспорт │ esc: назад"
 
 	case models.BankCard:
 		title = "КАРТА: " + item.Metadata.Name
@@ -1254,22 +2574,42 @@ func (m mainLoopModel) viewDetail(item models.DecipheredPayload) (title, body, h
 				b.WriteString("CVV       : " + cvv + "  [пробел: показать]\n")
 			}
 		}
-		hotKeys = "e: изменить │ c: копировать номер │ ctrl+d: удалить │ пробел: показать │ esc: назад"
+		hotKeys = "e: изменить │ c: копировать номер │ ctrl+d: удалить │ h: история │ x: экспорт │ пробел: показать │ esc: назад"
 
 	default:
 		title = "ЗАПИСЬ: " + item.Metadata.Name
 		b.WriteString("[ ДАННЫЕ ]\n")
 		b.WriteString("Тип       : " + dataTypeLabel(item.Type) + "\n")
-		hotKeys = "e: изменить │ ctrl+d: удалить │ esc: назад"
+		hotKeys = "e: изменить │ ctrl+d: удалить │ h: история │ x: экспорт │ esc: назад"
 	}
 
-	b.WriteString("\n")
-	notesTitle := "[ ЗАМЕТКИ ]"
-	if item.Notes != nil && item.Notes.IsEncrypted {
-		notesTitle = "[ ЗАМЕТКИ 🔒 ]"
+	hotKeys = favoriteHotKey(item) + " │ " + hotKeys
+
+	if item.AdditionalFields != nil && len(*item.AdditionalFields) > 0 {
+		b.WriteString("\n[ ПОЛЯ ]\n")
+		for i, field := range *item.AdditionalFields {
+			name := field.Name
+			if name == "" {
+				name = fmt.Sprintf("Поле %d", i+1)
+			}
+			value := string(field.Data)
+			if field.IsSensitive {
+				value = maskSecret(value, m.detailRevealSensitive)
+			}
+			b.WriteString(fmt.Sprintf("%d. %s: %s\n", i+1, name, value))
+		}
+		hotKeys = "1-9: копировать поле │ " + hotKeys
 	}
-	b.WriteString(notesTitle + "\n")
-	if item.Notes != nil && strings.TrimSpace(item.Notes.Notes) != "" {
+
+	if m.detailShowCopyValue {
+		b.WriteString("\n[ ЗНАЧЕНИЕ ]\n" + m.detailCopyFallback + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString("[ ЗАМЕТКИ ]\n")
+	if item.Notes != nil && strings.TrimSpace(item.Notes.Notes) != "" && locked {
+		b.WriteString(repromptHidden() + "\n")
+	} else if item.Notes != nil && strings.TrimSpace(item.Notes.Notes) != "" {
 		b.WriteString(item.Notes.Notes + "\n")
 	} else {
 		b.WriteString("(пусто)\n")
@@ -1296,6 +2636,93 @@ func (m mainLoopModel) detailCopyValue(item models.DecipheredPayload) (string, b
 	return "", false
 }
 
+// quickCopy copies the primary secret of item from the list screen, the same
+// value "c" copies in the detail view. Without a clipboard the list cannot
+// show the value, so the user is pointed to the detail view instead.
+func (m *mainLoopModel) quickCopy(item models.DecipheredPayload) {
+	text, ok := m.detailCopyValue(item)
+	if !ok {
+		m.status = "Нечего копировать"
+		return
+	}
+	m.copyToClipboard(text)
+	if m.detailCopyFallback != "" {
+		m.detailCopyFallback = ""
+		m.errMsg = "Буфер обмена недоступен. enter: открыть запись и показать значение"
+	}
+}
+
+// copyToClipboard writes text to the clipboard. When no clipboard is
+// available the value is kept so that "p" can show it on screen instead.
+func (m *mainLoopModel) copyToClipboard(text string) {
+	if err := m.writeClipboard(text); err != nil {
+		if errors.Is(err, clipboard.ErrUnavailable) {
+			m.detailCopyFallback = text
+			m.errMsg = "Буфер обмена недоступен. p: показать значение на экране"
+			return
+		}
+		m.errMsg = fmt.Sprintf("Ошибка копирования: %v", err)
+		return
+	}
+	m.status = "Скопировано"
+}
+
+// writeClipboard writes text to the clipboard and numbers the write for
+// [mainLoopModel.handleClipboardClear]. Without a clipboard it returns an
+// error wrapping [clipboard.ErrUnavailable].
+func (m *mainLoopModel) writeClipboard(text string) error {
+	if err := m.clipboard.WriteAll(text); err != nil {
+		return err
+	}
+	m.clipboardSeq++
+	return nil
+}
+
+// openLoginURI opens the first URI of a login item in the browser and copies
+// its password, so that it can be pasted into the opened page.
+func (m *mainLoopModel) openLoginURI(item models.DecipheredPayload) {
+	first, ok := loginURIAt(item, 0)
+	if !ok {
+		m.status = "Нет URI"
+		return
+	}
+	uri, err := browser.Normalize(first.URI)
+	if err != nil {
+		m.errMsg = fmt.Sprintf("Нельзя открыть URI %q: поддерживаются только http и https", first.URI)
+		return
+	}
+	if err = m.browser.Open(uri); err != nil {
+		m.errMsg = fmt.Sprintf("Не удалось открыть браузер: %v", err)
+		return
+	}
+
+	m.errMsg = ""
+	if item.LoginData.Password == "" {
+		m.status = "Открыто: " + uri
+		return
+	}
+	m.copyToClipboard(item.LoginData.Password)
+	if m.errMsg == "" {
+		m.status = "Открыто: " + uri + ", пароль скопирован"
+	}
+}
+
+// loginURIAt returns the URI of a login item at index i.
+func loginURIAt(item models.DecipheredPayload, i int) (models.LoginURI, bool) {
+	if item.LoginData == nil || i < 0 || i >= len(item.LoginData.URIs) {
+		return models.LoginURI{}, false
+	}
+	return item.LoginData.URIs[i], true
+}
+
+// customFieldAt returns the custom field of item at index i.
+func customFieldAt(item models.DecipheredPayload, i int) (models.CustomField, bool) {
+	if item.AdditionalFields == nil || i < 0 || i >= len(*item.AdditionalFields) {
+		return models.CustomField{}, false
+	}
+	return (*item.AdditionalFields)[i], true
+}
+
 func maskSecret(value string, reveal bool) string {
 	if reveal {
 		return value
@@ -1303,7 +2730,7 @@ func maskSecret(value string, reveal bool) string {
 	if value == "" {
 		return ""
 	}
-	return strings.Repeat("•", 10)
+	return theme.Masked.Render(strings.Repeat("•", 10))
 }
 
 func maskCardNumber(number string, reveal bool) string {
@@ -1315,7 +2742,7 @@ func maskCardNumber(number string, reveal bool) string {
 		return clean
 	}
 	masked := strings.Repeat("*", len(clean)-4) + clean[len(clean)-4:]
-	return groupBy4(masked)
+	return theme.Masked.Render(groupBy4(masked))
 }
 
 func sanitizeAddBankCardInputs(inputs *[]textinput.Model) {
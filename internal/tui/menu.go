@@ -106,12 +106,11 @@ func (m *MenuModel) View() string {
 	b.WriteString("\n")
 
 	for i, item := range m.items {
-		cursor := " "
-		if i == m.idx {
-			cursor = ">"
-		}
-		idCell := fmt.Sprintf("%s %d", cursor, i+1)
-		b.WriteString(fmt.Sprintf("%-*s │ %-*s\n", idColWidth, idCell, actionColWidth, item))
+		// The cursor may carry style escapes, so the cell is padded by its
+		// visible width rather than with a fmt width.
+		idCell := fmt.Sprintf("%s %d", cursorMark(i == m.idx), i+1)
+		idCell += strings.Repeat(" ", max(0, idColWidth-lipgloss.Width(idCell)))
+		b.WriteString(fmt.Sprintf("%s │ %-*s\n", idCell, actionColWidth, item))
 	}
 
 	return renderPage("ГЛАВНОЕ МЕНЮ", strings.TrimRight(b.String(), "\n"), "enter: выбрать │ ↑/↓: навигация │ v: версия")
@@ -26,6 +26,10 @@ type LoginResult struct {
 	// EncryptionKey is the symmetric key derived from the master password,
 	// used for client-side encryption of private data.
 	EncryptionKey []byte
+	// WeakPassword is set on a successful login with a master password that
+	// [service.IsWeakPassword] scores as weak. It only drives a one-time
+	// hint and is never stored.
+	WeakPassword bool
 }
 
 // RegisterResult is a Bubble Tea message produced by the async registration command.
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m *mainLoopModel) toggleSelected(clientSideID string) {
+	if m.selected[clientSideID] {
+		delete(m.selected, clientSideID)
+		return
+	}
+	if m.selected == nil {
+		m.selected = make(map[string]bool)
+	}
+	m.selected[clientSideID] = true
+}
+
+// selectedIDs returns the marked client-side IDs in list order. Marks of
+// items that are no longer listed are dropped.
+func (m mainLoopModel) selectedIDs() []string {
+	ids := make([]string, 0, len(m.selected))
+	for _, item := range m.items {
+		if m.selected[item.ClientSideID] {
+			ids = append(ids, item.ClientSideID)
+		}
+	}
+	return ids
+}
+
+func (m *mainLoopModel) startMove() {
+	input := textinput.New()
+	input.Placeholder = "Папка (пусто — без папки)"
+	input.Width = 40
+	input.Focus()
+
+	m.moveInput = input
+	m.moving = true
+	m.moveSaving = false
+	m.errMsg = ""
+}
+
+func (m mainLoopModel) updateMove(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			if !m.moveSaving {
+				m.moving = false
+				m.errMsg = ""
+			}
+			return m, nil
+		case "enter":
+			if m.moveSaving {
+				return m, nil
+			}
+			m.moveSaving = true
+			m.errMsg = ""
+			return m, m.cmdMove(m.selectedIDs(), m.moveInput.Value())
+		}
+	}
+
+	var cmd tea.Cmd
+	m.moveInput, cmd = m.moveInput.Update(msg)
+	return m, cmd
+}
+
+func (m mainLoopModel) viewMove() string {
+	out := fmt.Sprintf("Отмечено записей: %d\n\n", len(m.selectedIDs()))
+	out += "Папка     : [" + m.moveInput.View() + "]\n"
+	if m.moveSaving {
+		out += "\n[Перемещение...]\n"
+	} else {
+		out += "\n[Переместить]\n"
+	}
+	if m.errMsg != "" {
+		out += errorLine(m.errMsg) + "\n"
+	}
+	return renderPage("ПЕРЕМЕЩЕНИЕ В ПАПКУ", strings.TrimRight(out, "\n"), "enter: переместить │ esc: отмена")
+}
+
+func (m mainLoopModel) cmdMove(clientSideIDs []string, folder string) tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.PrivateDataService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return moveDoneMsg{err: errUserIDNotSet}
+		}
+		result, err := svc.MoveToFolder(ctx, userID, clientSideIDs, folder)
+		return moveDoneMsg{result: result, err: err}
+	}
+}
+
+// moveStatus summarizes a bulk move. Conflicted items were changed elsewhere
+// and are left as they are until the next sync.
+func moveStatus(result models.MoveResult) string {
+	status := fmt.Sprintf("Перемещено: %d", len(result.Moved))
+	if len(result.Conflicted) > 0 {
+		status += fmt.Sprintf(", конфликт версий: %d (выполните синхронизацию)", len(result.Conflicted))
+	}
+	return status
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+)
+
+// defaultMaxNotesLength is the notes limit of the add flow when no explicit
+// limit is configured.
+const defaultMaxNotesLength = config.DefaultMaxNotesLength
+
+// notesLength returns the length, in characters, of the notes that would be
+// saved from the raw textarea value.
+func notesLength(value string) int {
+	return utf8.RuneCountInString(strings.TrimSpace(value))
+}
+
+// notesNearLimit reports whether n characters are within the last tenth of
+// limit, where the counter starts warning.
+func notesNearLimit(n, limit int) bool {
+	return n*10 >= limit*9
+}
+
+// checkNotesLength returns an error when n characters exceed limit.
+func checkNotesLength(n, limit int) error {
+	if n > limit {
+		return fmt.Errorf("заметки: максимум %d символов, сейчас %d", limit, n)
+	}
+	return nil
+}
+
+// notesCounter renders the character counter of the notes stage, highlighted
+// once the notes get close to or over the limit.
+func (m mainLoopModel) notesCounter() string {
+	n := notesLength(m.addNotesArea.Value())
+	counter := fmt.Sprintf("Символов: %d/%d", n, m.maxNotesLength)
+
+	switch {
+	case n > m.maxNotesLength:
+		return theme.Error.Render(counter + " — превышен лимит, сохранение недоступно")
+	case notesNearLimit(n, m.maxNotesLength):
+		return theme.Attention.Render(counter + " — близко к лимиту")
+	default:
+		return counter
+	}
+}
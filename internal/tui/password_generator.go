@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/clipboard"
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// generatedClipboardClearAfter is how long a generated password copied to the
+// clipboard stays there.
+const generatedClipboardClearAfter = 30 * time.Second
+
+// clipboardClearMsg clears the clipboard at [mainLoopModel.clipboardClearAt]
+// unless something else was copied after the write numbered seq.
+type clipboardClearMsg struct {
+	seq int
+}
+
+// generatePassword fills input, the password field of a login add or edit
+// form, with a generated password and reports the outcome in info or
+// errText, the messages of that form. With copyGenerated set the password is
+// also copied to the clipboard, and the returned command clears it again
+// once [generatedClipboardClearAfter] has passed on m.clock. Without a
+// clipboard the password is shown in the field instead, like "p" shows a
+// value on the detail page.
+func (m *mainLoopModel) generatePassword(input *textinput.Model, info, errText *string) tea.Cmd {
+	password, err := service.GeneratePassword(service.GeneratedPasswordLength)
+	if err != nil {
+		*errText = fmt.Sprintf("не удалось сгенерировать пароль: %v", err)
+		return nil
+	}
+	input.SetValue(password)
+	*errText = ""
+	*info = "Пароль сгенерирован"
+	if !m.copyGenerated {
+		return nil
+	}
+
+	if err = m.writeClipboard(password); err != nil {
+		if errors.Is(err, clipboard.ErrUnavailable) {
+			input.EchoMode = textinput.EchoNormal
+			*info = "Пароль сгенерирован. Буфер обмена недоступен: пароль показан в поле"
+			return nil
+		}
+		*errText = fmt.Sprintf("пароль сгенерирован, но не скопирован: %v", err)
+		return nil
+	}
+	*info = "Пароль сгенерирован, пароль скопирован"
+	m.clipboardClearAt = m.clock.Now().Add(generatedClipboardClearAfter)
+	return clipboardClearAfter(generatedClipboardClearAfter, m.clipboardSeq)
+}
+
+// clipboardClearAfter wakes the model up after d to clear the write numbered
+// seq.
+func clipboardClearAfter(d time.Duration, seq int) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return clipboardClearMsg{seq: seq} })
+}
+
+// handleClipboardClear clears the clipboard when msg belongs to the last
+// write, so that a value copied later is kept. The timer only wakes the
+// model up: when m.clock has not reached [mainLoopModel.clipboardClearAt]
+// yet, the returned command waits for the rest.
+func (m *mainLoopModel) handleClipboardClear(msg clipboardClearMsg) tea.Cmd {
+	if msg.seq != m.clipboardSeq {
+		return nil
+	}
+	if remaining := m.clipboardClearAt.Sub(m.clock.Now()); remaining > 0 {
+		return clipboardClearAfter(remaining, msg.seq)
+	}
+	_ = m.clipboard.WriteAll("")
+	return nil
+}
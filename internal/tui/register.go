@@ -172,8 +172,8 @@ func (m *RegisterModel) View() string {
 	}
 
 	if m.errMsg != "" {
-		b.WriteString("\nОшибка: ")
-		b.WriteString(m.errMsg)
+		b.WriteString("\n")
+		b.WriteString(errorLine(m.errMsg))
 		b.WriteString("\n")
 	}
 
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type repromptDoneMsg struct {
+	err error
+}
+
+// detailRepromptKeys are the detail view keys that reveal or copy a secret
+// of the entry, or open it in a view that does; see [models.Metadata.Reprompt].
+var detailRepromptKeys = map[string]bool{
+	" ": true, "c": true, "e": true, "x": true, "O": true, "q": true, "enter": true,
+	"1": true, "2": true, "3": true, "4": true, "5": true, "6": true, "7": true, "8": true, "9": true,
+}
+
+// listRepromptKeys are the list keys that copy or edit the focused entry.
+var listRepromptKeys = map[string]bool{
+	"c": true, "e": true,
+}
+
+// repromptHidden replaces the text and notes of an entry until the master
+// password is entered.
+func repromptHidden() string {
+	return theme.Masked.Render(strings.Repeat("•", 10)) + "  [пробел: ввести мастер-пароль]"
+}
+
+// repromptLabel describes the Reprompt flag on the edit form.
+func repromptLabel(on bool) string {
+	if on {
+		return "запрос мастер-пароля  [ctrl+r]"
+	}
+	return "нет  [ctrl+r]"
+}
+
+// needsReprompt reports whether item asks for the master password before
+// its secrets are shown and has not been unlocked yet.
+func (m mainLoopModel) needsReprompt(item models.DecipheredPayload) bool {
+	return item.Metadata.Reprompt && m.repromptUnlocked != item.ClientSideID
+}
+
+// gateReprompt starts the master password prompt when keyMsg would reveal
+// or copy a secret of the focused entry and the entry asks for it. The key
+// is replayed once the password is verified. handled is false when the key
+// may proceed.
+func (m mainLoopModel) gateReprompt(keyMsg tea.KeyMsg) (next tea.Model, handled bool) {
+	keys := listRepromptKeys
+	if m.detail {
+		keys = detailRepromptKeys
+	}
+	if !keys[keyMsg.String()] {
+		return m, false
+	}
+
+	item, ok := m.current()
+	if !ok || m.isUndecryptable(item) || !m.needsReprompt(item) {
+		return m, false
+	}
+
+	input := textinput.New()
+	input.Placeholder = "мастер-пароль"
+	input.EchoMode = textinput.EchoPassword
+	input.EchoCharacter = '*'
+	input.Width = 40
+	input.Focus()
+
+	m.reprompting = true
+	m.repromptBusy = false
+	m.repromptInput = input
+	m.repromptKey = keyMsg
+	m.errMsg = ""
+	return m, true
+}
+
+func (m mainLoopModel) updateReprompt(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			if !m.repromptBusy {
+				m.reprompting = false
+				m.repromptInput = textinput.Model{}
+				m.errMsg = ""
+			}
+			return m, nil
+		case "enter":
+			if m.repromptBusy {
+				return m, nil
+			}
+			m.repromptBusy = true
+			m.errMsg = ""
+			return m, m.cmdVerifyMasterPassword(m.repromptInput.Value())
+		}
+	}
+
+	if m.repromptBusy {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.repromptInput, cmd = m.repromptInput.Update(msg)
+	return m, cmd
+}
+
+// finishReprompt handles the result of the password check. On success the
+// focused entry is unlocked and the key that asked for the password is
+// replayed; the unlock lasts while its detail view stays open.
+func (m mainLoopModel) finishReprompt(err error) (tea.Model, tea.Cmd) {
+	m.repromptBusy = false
+	if isCanceled(err) {
+		m.reprompting = false
+		m.repromptInput = textinput.Model{}
+		return m, nil
+	}
+	if err != nil {
+		m.repromptInput.SetValue("")
+		if errors.Is(err, service.ErrWrongMasterPassword) {
+			m.errMsg = err.Error()
+			return m, nil
+		}
+		m.errMsg = "Не удалось проверить мастер-пароль: " + err.Error()
+		return m, nil
+	}
+
+	m.reprompting = false
+	m.repromptInput = textinput.Model{}
+	m.errMsg = ""
+	item, ok := m.current()
+	if !ok {
+		return m, nil
+	}
+	m.repromptUnlocked = item.ClientSideID
+
+	next, cmd := m.Update(m.repromptKey)
+	result := next.(mainLoopModel)
+	if !result.detail {
+		// Outside the detail view the unlock covers a single action.
+		result.repromptUnlocked = ""
+	}
+	return result, cmd
+}
+
+func (m mainLoopModel) cmdVerifyMasterPassword(password string) tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.AuthService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return repromptDoneMsg{err: errUserIDNotSet}
+		}
+		return repromptDoneMsg{err: svc.VerifyMasterPassword(ctx, userID, password)}
+	}
+}
+
+func (m mainLoopModel) viewReprompt() string {
+	out := "Запись защищена: для просмотра и копирования введите мастер-пароль.\n\n"
+	out += "Мастер-пароль : [" + m.repromptInput.View() + "]\n"
+	if m.repromptBusy {
+		out += "\n[Проверка...]\n"
+	} else {
+		out += "\n[Подтвердить]\n"
+	}
+	if m.errMsg != "" {
+		out += errorLine(m.errMsg) + "\n"
+	}
+	return renderPage("ПОДТВЕРЖДЕНИЕ", strings.TrimRight(out, "\n"), "enter: подтвердить │ esc: отмена")
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// searchHotKeys is the hint shown while the search query is being typed.
+const searchHotKeys = "enter: применить │ esc: сбросить │ tab: искать в заметках и логинах"
+
+// matchesSearch reports whether item matches query. The query is matched
+// case-insensitively as a substring of the name and the folder; with deep set
+// the decrypted notes and the login username are searched as well. An empty
+// query matches every item.
+func matchesSearch(item models.DecipheredPayload, query string, deep bool) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), query)
+	}
+
+	if contains(item.Metadata.Name) {
+		return true
+	}
+	if item.Metadata.Folder != nil && contains(*item.Metadata.Folder) {
+		return true
+	}
+	if !deep {
+		return false
+	}
+	if item.LoginData != nil && contains(item.LoginData.Username) {
+		return true
+	}
+	return item.Notes != nil && contains(item.Notes.Notes)
+}
+
+// visibleItems returns the items matching the current search query in list
+// order. m.idx indexes this slice, or in the grouped list the rows of
+// [mainLoopModel.treeRows] built from it.
+func (m mainLoopModel) visibleItems() []models.DecipheredPayload {
+	if strings.TrimSpace(m.searchQuery) == "" {
+		return m.items
+	}
+
+	visible := make([]models.DecipheredPayload, 0, len(m.items))
+	for _, item := range m.items {
+		if matchesSearch(item, m.searchQuery, m.searchDeep) {
+			visible = append(visible, item)
+		}
+	}
+	return visible
+}
+
+func (m *mainLoopModel) startSearch() {
+	input := textinput.New()
+	input.Placeholder = "Название или папка"
+	input.Width = 40
+	input.SetValue(m.searchQuery)
+	input.CursorEnd()
+	input.Focus()
+
+	m.searchInput = input
+	m.searching = true
+}
+
+// updateSearch filters the list while the query is typed. Enter keeps the
+// filter and returns to the list, esc clears it, tab toggles matching of
+// notes and usernames.
+func (m mainLoopModel) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			m.cancel()
+			return m, tea.Quit
+		case "esc":
+			m.searching = false
+			m.searchQuery = ""
+			m.idx = 0
+			return m, nil
+		case "enter":
+			m.searching = false
+			return m, nil
+		case "tab":
+			m.searchDeep = !m.searchDeep
+			m.idx = 0
+			if m.searchDeep && !m.itemsFull {
+				// Notes and usernames are only decrypted on demand; load
+				// the full payloads of every entry to search them.
+				return m, m.cmdLoadItems()
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	if query := m.searchInput.Value(); query != m.searchQuery {
+		m.searchQuery = query
+		m.idx = 0
+	}
+	return m, cmd
+}
+
+// viewSearch renders the search line shown above the list while a query is
+// typed or applied; it is empty when no search is active.
+func (m mainLoopModel) viewSearch(found int) string {
+	if !m.searching && strings.TrimSpace(m.searchQuery) == "" {
+		return ""
+	}
+
+	scope := "название, папка"
+	if m.searchDeep {
+		scope += ", логин, заметки"
+	}
+
+	query := m.searchQuery
+	if m.searching {
+		query = m.searchInput.View()
+	}
+	return fmt.Sprintf("Поиск     : [%s] (%s) — найдено %d из %d\n", query, scope, found, len(m.items))
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// summaryTypes lists the rows of the summary screen in display order.
+var summaryTypes = []models.DataType{models.LoginPassword, models.Text, models.BankCard, models.Binary}
+
+// startSummary opens the vault composition screen and loads the counts.
+func (m mainLoopModel) startSummary() (tea.Model, tea.Cmd) {
+	m.summary = true
+	m.summaryData = nil
+	m.errMsg = ""
+	return m, m.cmdLoadSummary()
+}
+
+func (m mainLoopModel) cmdLoadSummary() tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.PrivateDataService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return summaryLoadedMsg{err: errUserIDNotSet}
+		}
+		summary, err := svc.Summary(ctx, userID)
+		return summaryLoadedMsg{summary: summary, err: err}
+	}
+}
+
+func (m mainLoopModel) summaryLoaded(msg summaryLoadedMsg) (tea.Model, tea.Cmd) {
+	if !m.summary {
+		return m, nil
+	}
+	if isCanceled(msg.err) {
+		m.summary = false
+		m.status = "Состав хранилища: " + statusCanceled
+		return m, nil
+	}
+	if msg.err != nil {
+		m.summary = false
+		m.errMsg = fmt.Sprintf("Ошибка подсчёта записей: %v", msg.err)
+		return m, nil
+	}
+	m.summaryData = &msg.summary
+	return m, nil
+}
+
+// updateSummary closes the summary screen on esc.
+func (m mainLoopModel) updateSummary(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if keyMsg.String() == "esc" {
+		m.summary = false
+		m.summaryData = nil
+	}
+	return m, nil
+}
+
+func (m mainLoopModel) viewSummary() string {
+	if m.summaryData == nil {
+		return renderPage("СОСТАВ ХРАНИЛИЩА", "Подсчёт записей...", "esc: назад")
+	}
+
+	var b strings.Builder
+	for _, dataType := range summaryTypes {
+		fmt.Fprintf(&b, "%-17s│ %d\n", dataTypeLabel(dataType), m.summaryData.ByType[dataType])
+	}
+	b.WriteString("─────────────────┼──────\n")
+	fmt.Fprintf(&b, "%-17s│ %d\n", "Всего", m.summaryData.Total)
+	fmt.Fprintf(&b, "%-17s│ %d", "Удалено", m.summaryData.Deleted)
+
+	return renderPage("СОСТАВ ХРАНИЛИЩА", b.String(), "esc: назад")
+}
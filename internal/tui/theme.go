@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the styles the screens render with. The built-in themes are
+// selected by name with the client's app.theme setting.
+type Theme struct {
+	// Header styles page titles.
+	Header lipgloss.Style
+	// Divider styles the lines above and below the page content.
+	Divider lipgloss.Style
+	// Cursor styles the selection cursor of lists.
+	Cursor lipgloss.Style
+	// Error styles error lines.
+	Error lipgloss.Style
+	// Masked styles hidden secrets such as passwords and card numbers.
+	Masked lipgloss.Style
+	// Attention highlights values that need the user's attention, such as an
+	// outdated last sync time.
+	Attention lipgloss.Style
+}
+
+var themes = map[string]Theme{
+	config.ThemeDefault: {
+		Header:    lipgloss.NewStyle().Bold(true),
+		Divider:   lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+		Cursor:    lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true),
+		Error:     lipgloss.NewStyle().Foreground(lipgloss.Color("9")),
+		Masked:    lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+		Attention: lipgloss.NewStyle().Foreground(lipgloss.Color("9")),
+	},
+	config.ThemeHighContrast: {
+		Header:    lipgloss.NewStyle().Bold(true).Reverse(true),
+		Divider:   lipgloss.NewStyle().Foreground(lipgloss.Color("15")),
+		Cursor:    lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Bold(true),
+		Error:     lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("1")).Bold(true),
+		Masked:    lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true),
+		Attention: lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Bold(true),
+	},
+	// monochrome relies on text attributes only, for terminals without
+	// color support.
+	config.ThemeMonochrome: {
+		Header:    lipgloss.NewStyle().Bold(true),
+		Divider:   lipgloss.NewStyle(),
+		Cursor:    lipgloss.NewStyle().Bold(true),
+		Error:     lipgloss.NewStyle().Bold(true),
+		Masked:    lipgloss.NewStyle().Faint(true),
+		Attention: lipgloss.NewStyle().Underline(true),
+	},
+}
+
+// theme is the active theme. It is set once by [New], before any program
+// runs, and only read afterwards.
+var theme = themes[config.ThemeDefault]
+
+// setTheme activates the built-in theme called name. Unknown names, which
+// config validation rejects, fall back to the default theme.
+func setTheme(name string) {
+	t, ok := themes[name]
+	if !ok {
+		t = themes[config.ThemeDefault]
+	}
+	theme = t
+}
+
+// errorLine renders msg as an error line of the active theme.
+func errorLine(msg string) string {
+	return theme.Error.Render("Ошибка: " + msg)
+}
+
+// cursorMark returns the list cursor for the current row and a blank of the
+// same width for the others.
+func cursorMark(current bool) string {
+	if !current {
+		return " "
+	}
+	return theme.Cursor.Render(">")
+}
+
+func divider(width int) string {
+	return theme.Divider.Render(strings.Repeat("─", width))
+}
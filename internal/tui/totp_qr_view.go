@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/skip2/go-qrcode"
+)
+
+// totpQRChromeLines is the number of lines the QR page adds around the code:
+// the title, two dividers, three blank lines, the hint and two hotkey lines.
+const totpQRChromeLines = 9
+
+// totpQRIndent is the left margin renderPage puts before every line.
+const totpQRIndent = 2
+
+// startTOTPQR opens the QR page of the otpauth:// URI of a login; see
+// [service.TOTPShareURI].
+func (m *mainLoopModel) startTOTPQR(uri string) {
+	m.totpQR = true
+	m.totpQRURI = uri
+	m.status = ""
+}
+
+// updateTOTPQR handles keys on the QR page; esc and q return to the detail
+// view. q is consumed here so that it does not quit the app.
+func (m mainLoopModel) updateTOTPQR(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.totpQR = false
+		m.totpQRURI = ""
+	}
+	return m, nil
+}
+
+func (m mainLoopModel) viewTOTPQR(name string) string {
+	code, ok := renderQR(m.totpQRURI, m.width, m.height)
+	if !ok {
+		body := "Окно слишком мало для QR-кода. Ссылка для приложения-аутентификатора:\n\n" + m.totpQRURI
+		return renderPage("TOTP: "+name, body, "q, esc: назад")
+	}
+	body := code + "\n\nОтсканируйте код приложением-аутентификатором"
+	return renderPage("TOTP: "+name, body, "q, esc: назад")
+}
+
+// renderQR renders text as a QR code of Unicode half blocks, two modules per
+// character row, drawn for a dark terminal background. ok is false when the
+// code does not fit into a width×height terminal; a zero size means unknown
+// and is never too small.
+func renderQR(text string, width, height int) (code string, ok bool) {
+	qr, err := qrcode.New(text, qrcode.Low)
+	if err != nil {
+		return "", false
+	}
+	code = strings.TrimRight(qr.ToSmallString(false), "\n")
+
+	lines := strings.Split(code, "\n")
+	if width > 0 && totpQRIndent+utf8.RuneCountInString(lines[0]) > width {
+		return "", false
+	}
+	if height > 0 && len(lines)+totpQRChromeLines > height {
+		return "", false
+	}
+	return code, true
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/internal/service"
+	"github.com/MKhiriev/go-pass-keeper/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type trashLoadedMsg struct {
+	items []models.DecipheredPayload
+	err   error
+}
+
+type trashActionDoneMsg struct {
+	restored bool
+	err      error
+}
+
+// startTrash opens the trash screen, which lists the soft-deleted entries
+// the main list leaves out, and loads them.
+func (m mainLoopModel) startTrash() (tea.Model, tea.Cmd) {
+	m.trash = true
+	m.trashItems = nil
+	m.trashIdx = 0
+	m.trashConfirmRemove = false
+	m.errMsg = ""
+	m.status = ""
+	return m, m.cmdLoadTrash()
+}
+
+func (m *mainLoopModel) closeTrash() {
+	m.trash = false
+	m.trashItems = nil
+	m.trashIdx = 0
+	m.trashBusy = false
+	m.trashConfirmRemove = false
+}
+
+func (m mainLoopModel) cmdLoadTrash() tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.PrivateDataService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return trashLoadedMsg{err: errUserIDNotSet}
+		}
+		// Entries that fail to decrypt cannot be told apart, so they are
+		// not listed; they can still be purged by a later version.
+		items, _, err := svc.GetTrash(ctx, userID)
+		return trashLoadedMsg{items: items, err: err}
+	}
+}
+
+func (m mainLoopModel) trashLoaded(msg trashLoadedMsg) (tea.Model, tea.Cmd) {
+	if !m.trash {
+		return m, nil
+	}
+	if isCanceled(msg.err) {
+		m.closeTrash()
+		m.status = "Корзина: " + statusCanceled
+		return m, nil
+	}
+	if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+		m.closeTrash()
+		return m.reauthenticate()
+	}
+	if msg.err != nil {
+		m.closeTrash()
+		m.errMsg = fmt.Sprintf("Ошибка загрузки корзины: %v", msg.err)
+		return m, nil
+	}
+	m.trashItems = msg.items
+	if msg.items == nil {
+		m.trashItems = []models.DecipheredPayload{}
+	}
+	m.trashIdx = min(m.trashIdx, max(len(m.trashItems)-1, 0))
+	return m, nil
+}
+
+// updateTrash handles keys on the trash screen: r restores the selected
+// entry, ctrl+d removes it from the trash after a y confirmation.
+func (m mainLoopModel) updateTrash(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.trashConfirmRemove {
+		m.trashConfirmRemove = false
+		if keyMsg.String() != "y" {
+			m.status = "Очистка отменена"
+			return m, nil
+		}
+		item, ok := m.currentTrashItem()
+		if !ok {
+			return m, nil
+		}
+		m.trashBusy = true
+		m.status = "Очистка..."
+		return m, m.cmdTrashAction(item.ClientSideID, false)
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		m.closeTrash()
+		m.status = ""
+	case "up":
+		if m.trashIdx > 0 {
+			m.trashIdx--
+		}
+	case "down":
+		if m.trashIdx < len(m.trashItems)-1 {
+			m.trashIdx++
+		}
+	case "r", "ctrl+d":
+		item, ok := m.currentTrashItem()
+		if !ok || m.trashBusy {
+			return m, nil
+		}
+		if m.offline {
+			m.status = service.ErrOfflineMode.Error()
+			return m, nil
+		}
+		if keyMsg.String() == "ctrl+d" {
+			m.trashConfirmRemove = true
+			return m, nil
+		}
+		m.trashBusy = true
+		m.status = "Восстановление..."
+		return m, m.cmdTrashAction(item.ClientSideID, true)
+	}
+	return m, nil
+}
+
+func (m mainLoopModel) currentTrashItem() (models.DecipheredPayload, bool) {
+	if m.trashIdx < 0 || m.trashIdx >= len(m.trashItems) {
+		return models.DecipheredPayload{}, false
+	}
+	return m.trashItems[m.trashIdx], true
+}
+
+// cmdTrashAction restores the deleted entry clientSideID, or removes it from
+// the trash when restore is false.
+func (m mainLoopModel) cmdTrashAction(clientSideID string, restore bool) tea.Cmd {
+	ctx := m.ctx
+	svc := m.services.PrivateDataService
+
+	return func() tea.Msg {
+		userID := m.activeUserID()
+		if userID <= 0 {
+			return trashActionDoneMsg{restored: restore, err: errUserIDNotSet}
+		}
+		if restore {
+			return trashActionDoneMsg{restored: true, err: svc.RestoreDeleted(ctx, userID, clientSideID)}
+		}
+		return trashActionDoneMsg{err: svc.RemoveFromTrash(ctx, userID, clientSideID)}
+	}
+}
+
+func (m mainLoopModel) trashActionDone(msg trashActionDoneMsg) (tea.Model, tea.Cmd) {
+	m.trashBusy = false
+	action := "Очистка"
+	if msg.restored {
+		action = "Восстановление"
+	}
+	if isCanceled(msg.err) {
+		m.status = action + ": " + statusCanceled
+		return m, nil
+	}
+	if errors.Is(msg.err, service.ErrKeyNotAvailable) {
+		m.closeTrash()
+		return m.reauthenticate()
+	}
+	if msg.err != nil {
+		m.status = ""
+		m.errMsg = fmt.Sprintf("%s: %v", action, msg.err)
+		return m, nil
+	}
+
+	m.errMsg = ""
+	if !msg.restored {
+		m.status = "Запись убрана из корзины"
+		return m, m.cmdLoadTrash()
+	}
+	m.status = "Запись восстановлена"
+	m.loading = true
+	next, cmd := m.afterChange()
+	return next, tea.Batch(cmd, m.cmdLoadTrash())
+}
+
+func (m mainLoopModel) viewTrash() string {
+	const hotKeys = "r: восстановить │ ctrl+d: убрать из корзины │ ↑/↓: нав. │ esc: назад"
+	if m.trashItems == nil {
+		return renderPage("КОРЗИНА", "Загрузка корзины...", "esc: назад")
+	}
+
+	var b strings.Builder
+	if m.errMsg != "" {
+		b.WriteString(errorLine(m.errMsg) + "\n")
+	}
+	if m.status != "" {
+		b.WriteString("Статус: " + m.status + "\n")
+	}
+	if m.trashConfirmRemove {
+		if item, ok := m.currentTrashItem(); ok {
+			b.WriteString(theme.Attention.Render(fmt.Sprintf("Убрать «%s» из корзины на этом устройстве? Сервер сохранит отметку об удалении. y: да │ любая клавиша: отмена", item.Metadata.Name)) + "\n")
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+
+	if len(m.trashItems) == 0 {
+		b.WriteString("Корзина пуста")
+		return renderPage("КОРЗИНА", b.String(), "esc: назад")
+	}
+	b.WriteString(viewListTable(resolveListColumns(m.listColumns), m.trashItems, m.trashIdx, nil, false))
+	return renderPage("КОРЗИНА", strings.TrimRight(b.String(), "\n"), hotKeys)
+}
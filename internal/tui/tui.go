@@ -19,7 +19,10 @@ package tui
 import (
 	"context"
 	"errors"
+	"os"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/clipboard"
+	"github.com/MKhiriev/go-pass-keeper/internal/config"
 	"github.com/MKhiriev/go-pass-keeper/internal/logger"
 	"github.com/MKhiriev/go-pass-keeper/internal/service"
 	"github.com/MKhiriev/go-pass-keeper/models"
@@ -38,12 +41,20 @@ var ErrUserIDMissing = errors.New("не удалось получить user id
 // and exposes methods for running each lifecycle stage of the application.
 type TUI struct {
 	services *service.ClientServices
+	cfg      config.ClientApp
+
+	// weakPassword is set by a [TUI.LoginFlow] whose master password scored
+	// as weak; the next [TUI.MainLoop] shows the hint once and clears it.
+	weakPassword bool
 }
 
-// New creates and returns a new [TUI] instance.
+// New creates and returns a new [TUI] instance. cfg supplies client settings
+// the screens depend on, such as the maximum Binary attachment size, and
+// selects the color [Theme] of all screens.
 // The logger parameter is reserved for future use and is currently ignored.
-func New(services *service.ClientServices, _ *logger.Logger) (*TUI, error) {
-	return &TUI{services: services}, nil
+func New(services *service.ClientServices, cfg config.ClientApp, _ *logger.Logger) (*TUI, error) {
+	setTheme(cfg.Theme)
+	return &TUI{services: services, cfg: cfg}, nil
 }
 
 // LoginFlow launches the interactive login/registration TUI in alternate-screen mode
@@ -60,6 +71,9 @@ func New(services *service.ClientServices, _ *logger.Logger) (*TUI, error) {
 func (t *TUI) LoginFlow(ctx context.Context, buildInfo models.AppBuildInfo) (userID int64, encryptionKey []byte, err error) {
 	clearSessionUserID()
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	pages := map[string]tea.Model{
 		"menu":     NewMenuModel(),
 		"login":    NewLoginModel(ctx, t.services.AuthService),
@@ -85,6 +99,7 @@ func (t *TUI) LoginFlow(ctx context.Context, buildInfo models.AppBuildInfo) (use
 		return 0, nil, ErrUserIDMissing
 	}
 	setSessionUserID(result.resultID)
+	t.weakPassword = result.resultWeakPassword
 
 	return result.resultID, result.resultKey, nil
 }
@@ -93,16 +108,48 @@ func (t *TUI) LoginFlow(ctx context.Context, buildInfo models.AppBuildInfo) (use
 //
 // If userID is greater than zero the session user ID is initialised from it; otherwise
 // the value stored by a previous [TUI.LoginFlow] call is used.
-// The method blocks until the user quits (q / Ctrl+C) or requests a logout (l).
+// The method blocks until the user quits (q / Ctrl+C) or requests a logout (l),
+// or until the session was idle for the configured idle logout.
+// Both cancel a child of ctx so that in-flight sync and data commands abort
+// instead of delaying shutdown.
 //
-// Returns logout=true when the user explicitly chose to log out so that the caller
-// can re-run [TUI.LoginFlow] for a new session.
+// Returns logout=true when the user chose to log out or the idle logout fired,
+// so that the caller can re-run [TUI.LoginFlow] for a new session.
 func (t *TUI) MainLoop(ctx context.Context, userID int64, buildInfo models.AppBuildInfo) (logout bool, err error) {
 	if userID > 0 {
 		setSessionUserID(userID)
 	}
 
 	model := newMainLoopModel(ctx, t.services, userID, buildInfo)
+	defer model.cancel()
+	if t.cfg.MaxBinarySize > 0 {
+		model.maxAttachmentSize = t.cfg.MaxBinarySize
+	}
+	if t.cfg.MaxNotesLength > 0 {
+		model.maxNotesLength = t.cfg.MaxNotesLength
+	}
+	if t.cfg.SyncStaleAfter > 0 {
+		model.syncStaleAfter = t.cfg.SyncStaleAfter
+	}
+	model.detectDuplicates = t.cfg.DetectDuplicates
+	model.syncOnChange = t.cfg.SyncOnChange
+	model.pingInterval = t.cfg.PingInterval
+	model.idleLogout = t.cfg.IdleLogout
+	model.copyGenerated = t.cfg.CopyGenerated
+	model.defaultAddType = t.cfg.DefaultDataType
+	if len(t.cfg.EnabledDataTypes) > 0 {
+		model.addTypeOptions = t.cfg.EnabledDataTypes
+	}
+	model.defaultFolder = t.cfg.DefaultFolder
+	model.listColumns = t.cfg.ListColumns
+	model.maskNames = t.cfg.MaskNames
+	model.folderTree = t.cfg.FolderTree
+	model.offline = t.cfg.Offline
+	model.weakPasswordHint = t.weakPassword
+	t.weakPassword = false
+	if t.cfg.Clipboard != "" {
+		model.clipboard = clipboard.New(t.cfg.Clipboard, os.Stdout, os.Getenv)
+	}
 	finalModel, runErr := tea.NewProgram(model, tea.WithAltScreen()).Run()
 	if runErr != nil {
 		return false, runErr
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MKhiriev/go-pass-keeper/models"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// uriMatchLabels names the [models.LoginURI] match rules, indexed by rule.
+var uriMatchLabels = []string{
+	models.URIMatchDomain:     "домен",
+	models.URIMatchHost:       "хост",
+	models.URIMatchStartsWith: "начало",
+	models.URIMatchExact:      "точно",
+	models.URIMatchRegex:      "regex",
+	models.URIMatchNever:      "никогда",
+}
+
+func uriMatchLabel(match int) string {
+	if match < 0 || match >= len(uriMatchLabels) {
+		return fmt.Sprintf("правило %d", match)
+	}
+	return uriMatchLabels[match]
+}
+
+// uriFields manages the variable number of URI inputs of a login form. The
+// inputs are the last ones of the form's input slice, starting at start, so
+// tab navigation covers them like any other field; matches holds the match
+// rule of each of them.
+type uriFields struct {
+	start   int
+	matches []int
+}
+
+// newURIFields appends one input per URI of uris to inputs, or a single empty
+// one when there are none.
+func newURIFields(inputs []textinput.Model, uris []models.LoginURI) ([]textinput.Model, uriFields) {
+	f := uriFields{start: len(inputs)}
+	for _, uri := range uris {
+		input := newURIInput()
+		input.SetValue(uri.URI)
+		inputs = append(inputs, input)
+		f.matches = append(f.matches, uri.Match)
+	}
+	if len(f.matches) == 0 {
+		inputs = append(inputs, newURIInput())
+		f.matches = append(f.matches, models.URIMatchDomain)
+	}
+	return inputs, f
+}
+
+func newURIInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "URI"
+	input.Width = 40
+	return input
+}
+
+// handleKey applies the URI keys while a URI input has the focus: ctrl+n
+// adds an input below the focused one, ctrl+x removes the focused one and
+// ctrl+t switches its match rule. It returns the updated inputs and focus,
+// and false when key is not a URI key or the focus is elsewhere.
+func (f *uriFields) handleKey(key string, inputs []textinput.Model, focus int) ([]textinput.Model, int, bool) {
+	i := focus - f.start
+	if i < 0 || i >= len(f.matches) {
+		return inputs, focus, false
+	}
+
+	switch key {
+	case "ctrl+n":
+		inputs[focus].Blur()
+		inputs = slices.Insert(inputs, focus+1, newURIInput())
+		f.matches = slices.Insert(f.matches, i+1, models.URIMatchDomain)
+		focus++
+	case "ctrl+x":
+		// The last remaining input is cleared instead, so there is always
+		// a field to type a URI into.
+		if len(f.matches) == 1 {
+			inputs[focus].SetValue("")
+			f.matches[0] = models.URIMatchDomain
+			return inputs, focus, true
+		}
+		inputs = slices.Delete(inputs, focus, focus+1)
+		f.matches = slices.Delete(f.matches, i, i+1)
+		if focus-f.start >= len(f.matches) {
+			focus--
+		}
+	case "ctrl+t":
+		f.matches[i] = (f.matches[i] + 1) % len(uriMatchLabels)
+		return inputs, focus, true
+	default:
+		return inputs, focus, false
+	}
+
+	inputs[focus].Focus()
+	return inputs, focus, true
+}
+
+// collect returns the non-empty URIs with their match rules.
+func (f uriFields) collect(inputs []textinput.Model) []models.LoginURI {
+	var uris []models.LoginURI
+	for i, match := range f.matches {
+		uri := strings.TrimSpace(inputs[f.start+i].Value())
+		if uri == "" {
+			continue
+		}
+		uris = append(uris, models.LoginURI{URI: uri, Match: match})
+	}
+	return uris
+}
+
+// view renders one line per URI input. sep separates the label from the
+// input, matching the layout of the surrounding form.
+func (f uriFields) view(inputs []textinput.Model, sep string) string {
+	var b strings.Builder
+	for i, match := range f.matches {
+		label := "URI"
+		if len(f.matches) > 1 {
+			label = fmt.Sprintf("URI %d", i+1)
+		}
+		b.WriteString(fmt.Sprintf("%-10s%s [ %s ] %s\n", label, sep, inputs[f.start+i].View(), uriMatchLabel(match)))
+	}
+	return b.String()
+}
+
+// uriHotKeys documents the URI keys of a login form.
+const uriHotKeys = "ctrl+n: доб. URI │ ctrl+x: уд. URI │ ctrl+t: правило URI"
+
+// viewLoginURIs renders the URIs of a login detail page, numbered when there
+// is more than one. The match rule is shown only when it is not the default.
+func viewLoginURIs(uris []models.LoginURI) string {
+	var b strings.Builder
+	for i, uri := range uris {
+		label := "URI"
+		if len(uris) > 1 {
+			label = fmt.Sprintf("URI %d", i+1)
+		}
+		line := fmt.Sprintf("%-10s: %s", label, uri.URI)
+		if uri.Match != models.URIMatchDomain {
+			line += " (" + uriMatchLabel(uri.Match) + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -5,7 +5,9 @@ package tui
 
 import (
 	"strings"
+	"time"
 
+	"github.com/MKhiriev/go-pass-keeper/models"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -13,12 +15,12 @@ const minDividerWidth = 54
 
 func renderPage(title, data, hotKeys string) string {
 	var b strings.Builder
-	divider := strings.Repeat("─", pageContentWidth(title, data, hotKeys))
+	line := divider(pageContentWidth(title, data, hotKeys))
 
-	b.WriteString(title)
+	b.WriteString(theme.Header.Render(title))
 	b.WriteString("\n")
 	b.WriteString("  ")
-	b.WriteString(divider)
+	b.WriteString(line)
 	b.WriteString("\n\n")
 
 	if strings.TrimSpace(data) != "" {
@@ -34,7 +36,7 @@ func renderPage(title, data, hotKeys string) string {
 
 	b.WriteString("\n")
 	b.WriteString("  ")
-	b.WriteString(divider)
+	b.WriteString(line)
 	b.WriteString("\n")
 
 	if strings.TrimSpace(hotKeys) != "" {
@@ -86,3 +88,37 @@ func fitText(v string, max int) string {
 	}
 	return v[:max-3] + "..."
 }
+
+// formatLastSynced renders the "last synced" header line. Times from today are
+// shown as HH:MM, older ones include the date. The value is rendered with the
+// theme's attention style when it is older than staleAfter or the user has never synced.
+func formatLastSynced(syncedAt time.Time, staleAfter time.Duration, now time.Time) string {
+	const label = "Последняя синхронизация: "
+	if syncedAt.IsZero() {
+		return label + theme.Attention.Render("никогда")
+	}
+
+	local := syncedAt.In(now.Location())
+	value := local.Format("15:04")
+	if y, m, d := local.Date(); y != now.Year() || m != now.Month() || d != now.Day() {
+		value = local.Format("02.01.2006 15:04")
+	}
+
+	if staleAfter > 0 && now.Sub(syncedAt) > staleAfter {
+		value = theme.Attention.Render(value)
+	}
+	return label + value
+}
+
+// formatSyncBreaker renders the status line of an open or half-open sync
+// circuit breaker, or "" while it is closed.
+func formatSyncBreaker(st models.SyncBreakerStatus) string {
+	switch st.State {
+	case models.SyncBreakerOpen:
+		return theme.Attention.Render("Сервер недоступен: синхронизация приостановлена до " + st.RetryAt.Local().Format("15:04:05"))
+	case models.SyncBreakerHalfOpen:
+		return theme.Attention.Render("Сервер недоступен: проверяем соединение")
+	default:
+		return ""
+	}
+}
@@ -32,6 +32,50 @@ func (c contextKey) String() string {
 //	ctx := context.WithValue(ctx, utils.UserIDCtxKey, int64(42))
 var UserIDCtxKey = contextKey("userID")
 
+// RequestIDCtxKey is the key used to store the request correlation identifier
+// in the context. On the server it is populated by the request-ID middleware;
+// on the client it may be set by callers that want to pin a specific ID onto
+// an outbound request.
+//
+// Example of writing a value to the context:
+//
+//	ctx := utils.WithRequestID(ctx, "3f0c...")
+var RequestIDCtxKey = contextKey("requestID")
+
+// RequestIDHeader is the HTTP header that carries the request correlation
+// identifier between the client and the server. The client sets it on every
+// outbound call and the server echoes it back in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// Headers of a 409 Conflict response to an update or delete. They describe
+// the conflict ([models.VersionConflict]) without changing the plain-text
+// body older clients match on.
+const (
+	// ConflictOperationHeader carries the rejected operation, "update" or
+	// "delete".
+	ConflictOperationHeader = "X-Conflict-Operation"
+	// ConflictClientSideIDHeader carries the client_side_id of the item.
+	ConflictClientSideIDHeader = "X-Conflict-Client-Side-ID"
+	// ServerVersionHeader carries the item's current version on the server.
+	ServerVersionHeader = "X-Server-Version"
+)
+
+// WithRequestID returns a copy of ctx carrying requestID under
+// [RequestIDCtxKey].
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, RequestIDCtxKey, requestID)
+}
+
+// GetRequestIDFromContext retrieves the request correlation identifier from
+// the context.
+//
+// Returns the request ID and an ok flag that is false when the value is
+// missing, empty, or has an unexpected type.
+func GetRequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(RequestIDCtxKey).(string)
+	return requestID, ok && requestID != ""
+}
+
 // GetUserIDFromContext retrieves the user identifier from the context.
 //
 // Returns the user ID of type int64 and an ok flag:
@@ -40,11 +40,16 @@ import (
 //
 //	token, err := utils.GenerateJWTToken("my-service", 42, time.Hour, "secret")
 func GenerateJWTToken(issuer string, userID int64, tokenDuration time.Duration, signKey string) (models.Token, error) {
+	return GenerateJWTTokenAt(time.Now(), issuer, userID, tokenDuration, signKey)
+}
+
+// GenerateJWTTokenAt is [GenerateJWTToken] with the token issued at now
+// instead of the current time.
+func GenerateJWTTokenAt(now time.Time, issuer string, userID int64, tokenDuration time.Duration, signKey string) (models.Token, error) {
 	if issuer == "" || tokenDuration == 0 || signKey == "" {
 		return models.Token{}, errors.New("invalid params for generating JWT Token")
 	}
 
-	now := time.Now()
 	claims := &jwt.RegisteredClaims{
 		Issuer:    issuer,
 		Subject:   strconv.FormatInt(userID, 10),
@@ -87,9 +92,16 @@ func GenerateJWTToken(issuer string, userID int64, tokenDuration time.Duration,
 //	    // handle invalid or expired token
 //	}
 func ValidateAndParseJWTToken(tokenString, tokenSignKey, tokenIssuer string) (models.Token, error) {
+	return ValidateAndParseJWTTokenAt(time.Now(), tokenString, tokenSignKey, tokenIssuer)
+}
+
+// ValidateAndParseJWTTokenAt is [ValidateAndParseJWTToken] with the
+// expiration checked against now instead of the current time. A token is
+// expired from the second of its exp claim on.
+func ValidateAndParseJWTTokenAt(now time.Time, tokenString, tokenSignKey, tokenIssuer string) (models.Token, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &models.Token{}, func(token *jwt.Token) (any, error) {
 		return []byte(tokenSignKey), nil
-	}, jwt.WithIssuer(tokenIssuer))
+	}, jwt.WithIssuer(tokenIssuer), jwt.WithTimeFunc(func() time.Time { return now }))
 	if err != nil {
 		return models.Token{}, fmt.Errorf("error occurred validating and parsing token: %w", err)
 	}
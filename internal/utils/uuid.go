@@ -10,7 +10,9 @@ import "github.com/google/uuid"
 // The generator is stateless and safe to reuse across goroutines.
 // Its [Generate] method prefers UUID version 7 (time-ordered) and falls
 // back to a random UUID if v7 generation fails.
-type UUIDGenerator struct{}
+type UUIDGenerator struct {
+	prefix string
+}
 
 // NewUUIDGenerator returns a new [UUIDGenerator] instance.
 //
@@ -20,16 +22,30 @@ func NewUUIDGenerator() *UUIDGenerator {
 	return &UUIDGenerator{}
 }
 
+// NewPrefixedUUIDGenerator returns a [UUIDGenerator] whose values are the
+// UUID preceded by prefix and a dash, e.g. "laptop-<uuid>". The prefix is
+// informational only; uniqueness still comes from the UUID. An empty prefix
+// behaves like [NewUUIDGenerator].
+func NewPrefixedUUIDGenerator(prefix string) *UUIDGenerator {
+	return &UUIDGenerator{prefix: prefix}
+}
+
 // Generate returns a UUID string suitable for use as a client-side identifier.
 //
 // It first attempts to create UUID v7 via [uuid.NewV7]. If that operation
 // fails, it falls back to [uuid.NewString] (random UUID) to preserve
-// availability and still return a valid UUID-formatted value.
+// availability and still return a valid UUID-formatted value. A configured
+// prefix is prepended as "prefix-".
 func (g *UUIDGenerator) Generate() string {
-	v7, err := uuid.NewV7()
-	if err != nil {
-		return uuid.NewString()
+	var id string
+	if v7, err := uuid.NewV7(); err == nil {
+		id = v7.String()
+	} else {
+		id = uuid.NewString()
 	}
 
-	return v7.String()
+	if g.prefix == "" {
+		return id
+	}
+	return g.prefix + "-" + id
 }
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestUUIDGenerator_Generate(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator *UUIDGenerator
+		prefix    string
+	}{
+		{name: "no prefix", generator: NewUUIDGenerator()},
+		{name: "empty prefix", generator: NewPrefixedUUIDGenerator("")},
+		{name: "device prefix", generator: NewPrefixedUUIDGenerator("laptop"), prefix: "laptop-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const n = 1000
+			seen := make(map[string]struct{}, n)
+			for range n {
+				id := tt.generator.Generate()
+
+				if !strings.HasPrefix(id, tt.prefix) {
+					t.Fatalf("id %q lacks prefix %q", id, tt.prefix)
+				}
+				if _, err := uuid.Parse(strings.TrimPrefix(id, tt.prefix)); err != nil {
+					t.Fatalf("id %q: UUID part does not parse: %v", id, err)
+				}
+				if _, dup := seen[id]; dup {
+					t.Fatalf("duplicate id %q", id)
+				}
+				seen[id] = struct{}{}
+			}
+		})
+	}
+}
@@ -3,7 +3,10 @@
 
 package validators
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrUnsupportedType is returned when a value of an unsupported type
@@ -50,6 +53,10 @@ var (
 	// but the list of entries to delete is empty.
 	ErrNoDeleteEntries = errors.New("delete entries list cannot be empty")
 
+	// ErrNoRestoreEntries is returned when a restore operation is requested
+	// but the list of entries to restore is empty.
+	ErrNoRestoreEntries = errors.New("restore entries list cannot be empty")
+
 	// ErrNoFieldsToUpdate is returned when an update request is submitted
 	// without specifying any fields to be changed.
 	ErrNoFieldsToUpdate = errors.New("at least one field must be provided for update")
@@ -69,4 +76,66 @@ var (
 	// ErrInvalidUpdateVersion is returned when the version field provided
 	// in an update request is not zero.
 	ErrInvalidUpdateVersion = errors.New("invalid Update Version")
+
+	// ErrNotesTooLong is returned when the encrypted notes of a vault item
+	// are longer than the configured notes limit allows.
+	ErrNotesTooLong = errors.New("notes are too long")
+
+	// ErrLengthMismatch is returned when the Length field of a batch request
+	// does not equal the number of entries the request carries.
+	ErrLengthMismatch = errors.New("length does not match number of entries")
+
+	// ErrTooManyEntries is returned when an update or delete request carries
+	// more entries than the server applies in one transaction.
+	ErrTooManyEntries = errors.New("too many entries in one request")
 )
+
+// FieldError is the error of a validation that failed on a known field. It
+// wraps one of the sentinel errors above, so [errors.Is] still matches them,
+// and tells which field failed and, for an item of a batch request, at which
+// index.
+type FieldError struct {
+	// Index is the position of the invalid item in the request's list, or -1
+	// when the error is not about an item of a batch.
+	Index int
+
+	// Field is the JSON name of the invalid field, e.g. [FieldClientSideID].
+	// It is empty when the error is about the item as a whole.
+	Field string
+
+	// Err is the sentinel error describing the problem.
+	Err error
+}
+
+// Error implements error. Errors of batch items read
+// "validation error at index <i>: <reason>".
+func (e *FieldError) Error() string {
+	if e.Index < 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("validation error at index %d: %v", e.Index, e.Err)
+}
+
+// Unwrap makes [errors.Is] match the wrapped sentinel.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// fieldError returns a [FieldError] for field that is not about a batch item.
+func fieldError(field string, err error) error {
+	return &FieldError{Index: -1, Field: field, Err: err}
+}
+
+// AtIndex attributes the validation error err of a single item to index i of
+// a batch request. The field of a [FieldError] in err is kept.
+func AtIndex(err error, i int) error {
+	if err == nil {
+		return nil
+	}
+	fieldErr := &FieldError{Index: i, Err: err}
+	var inner *FieldError
+	if errors.As(err, &inner) {
+		fieldErr.Field, fieldErr.Err = inner.Field, inner.Err
+	}
+	return fieldErr
+}
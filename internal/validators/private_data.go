@@ -38,9 +38,16 @@ const (
 	// FieldClientSideIDs targets the array of client-side identifiers in bulk requests.
 	FieldClientSideIDs = "client_side_ids"
 
+	// FieldTypes targets the optional data type filter of a download request.
+	FieldTypes = "types"
+
 	// FieldDeleteEntries targets the list of entries to be soft-deleted.
 	FieldDeleteEntries = "delete_entries"
 
+	// FieldRestoreEntries targets the list of soft-deleted entries to be
+	// restored.
+	FieldRestoreEntries = "restore_entries"
+
 	// FieldPrivateData targets the list of vault items in an upload request.
 	FieldPrivateData = "private_data"
 
@@ -54,8 +61,31 @@ const (
 	// FieldUpdatedRecordHash targets the post-update integrity hash
 	// that the client computes from the merged record state.
 	FieldUpdatedRecordHash = "updated_record_hash"
+
+	// FieldNotes targets the optional encrypted notes of a vault item, which
+	// must not exceed the configured limit.
+	FieldNotes = "notes"
+
+	// FieldLength targets the declared entry count of a batch request, which
+	// must equal the length of the request's list.
+	FieldLength = "length"
 )
 
+// UploadItemFields are the fields checked on every item of an upload request.
+// Besides the required payload fields they include
+// [FieldPrivateDataVersionForDataUpload]: a new item must start at version 0,
+// otherwise its first update would skip the optimistic-locking check.
+var UploadItemFields = []string{
+	FieldClientSideID,
+	FieldUserID,
+	FieldMetadata,
+	FieldType,
+	FieldData,
+	FieldNotes,
+	FieldHash,
+	FieldPrivateDataVersionForDataUpload,
+}
+
 // allowedDataTypes is the exhaustive set of DataType values accepted by the validator.
 // Any DataType not present in this slice is considered invalid.
 var allowedDataTypes = []models.DataType{
@@ -67,17 +97,57 @@ var allowedDataTypes = []models.DataType{
 
 // PrivateDataValidator implements the Validator interface for all
 // private-data-related domain models: PrivateData, UploadRequest,
-// UpdateRequest, PrivateDataUpdate, DeleteRequest, and DownloadRequest.
+// UpdateRequest, PrivateDataUpdate, DeleteRequest, DownloadRequest and
+// SyncRequest.
 //
 // It supports both value and pointer receivers for every model type
 // and allows optional field-level scoping via variadic field name arguments.
 type PrivateDataValidator struct {
+	// maxCipheredNotes is the longest accepted CipheredNotes value; zero
+	// disables the check.
+	maxCipheredNotes int
+
+	// maxBatchEntries is the largest number of entries an update or delete
+	// request may carry; zero disables the check.
+	maxBatchEntries int
 }
 
 // NewPrivateDataValidator constructs a new PrivateDataValidator
 // and returns it as the Validator interface.
-func NewPrivateDataValidator() Validator {
-	return &PrivateDataValidator{}
+//
+// maxNotesLength is the longest plaintext note, in characters, a client may
+// send; encrypted notes longer than such a note can produce are rejected with
+// [ErrNotesTooLong]. Zero or a negative value disables the check.
+//
+// maxBatchEntries caps the entries of one update or delete request, which the
+// server applies in a single transaction; larger requests are rejected with
+// [ErrTooManyEntries]. Zero or a negative value disables the check.
+func NewPrivateDataValidator(maxNotesLength, maxBatchEntries int) Validator {
+	return &PrivateDataValidator{
+		maxCipheredNotes: CipheredNotesLimit(maxNotesLength),
+		maxBatchEntries:  max(maxBatchEntries, 0),
+	}
+}
+
+// CipheredNotesLimit returns the length of the longest CipheredNotes value a
+// note of maxNotesLength characters can be encrypted to, or zero when
+// maxNotesLength is not positive.
+//
+// The bound assumes the worst case of the client's encoding: every character
+// JSON-escaped to six bytes, wrapped in the Notes object, sealed with a
+// one-byte format version, a 16-byte key salt, a 12-byte nonce and a 16-byte
+// GCM tag, then Base64-encoded.
+func CipheredNotesLimit(maxNotesLength int) int {
+	if maxNotesLength <= 0 {
+		return 0
+	}
+	const (
+		jsonOverhead   = len(`{"Notes":""}`)
+		sealedOverhead = 1 + 16 + 12 + 16
+		maxEscapedRune = 6
+	)
+	sealed := sealedOverhead + jsonOverhead + maxEscapedRune*maxNotesLength
+	return (sealed + 2) / 3 * 4
 }
 
 // Validate dispatches validation to the appropriate type-specific method
@@ -90,7 +160,9 @@ func NewPrivateDataValidator() Validator {
 //   - models.UpdateRequest / *models.UpdateRequest
 //   - models.PrivateDataUpdate / *models.PrivateDataUpdate
 //   - models.DeleteRequest / *models.DeleteRequest
+//   - models.RestoreRequest / *models.RestoreRequest
 //   - models.DownloadRequest / *models.DownloadRequest
+//   - models.SyncRequest / *models.SyncRequest
 //
 // Returns ErrUnsupportedType if obj does not match any known model.
 // Optional fields restrict validation to the named subset; when omitted,
@@ -122,11 +194,21 @@ func (v *PrivateDataValidator) Validate(ctx context.Context, obj any, fields ...
 	case *models.DeleteRequest:
 		return v.validateDeleteDataRequest(ctx, *value, fields...)
 
+	case models.RestoreRequest:
+		return v.validateRestoreDataRequest(ctx, value, fields...)
+	case *models.RestoreRequest:
+		return v.validateRestoreDataRequest(ctx, *value, fields...)
+
 	case models.DownloadRequest:
 		return v.validateDownloadDataRequest(ctx, value, fields...)
 	case *models.DownloadRequest:
 		return v.validateDownloadDataRequest(ctx, *value, fields...)
 
+	case models.SyncRequest:
+		return v.validateSyncRequest(ctx, value, fields...)
+	case *models.SyncRequest:
+		return v.validateSyncRequest(ctx, *value, fields...)
+
 	default:
 		return ErrUnsupportedType
 	}
@@ -143,53 +225,88 @@ func isValidDataType(dt models.DataType) bool {
 	return false
 }
 
+// checkLength reports [ErrLengthMismatch] unless the declared length equals
+// the actual number of entries n. A negative length never matches.
+func checkLength(length, n int) error {
+	if length != n {
+		return fmt.Errorf("%w (length %d, entries %d)", ErrLengthMismatch, length, n)
+	}
+	return nil
+}
+
+// checkBatchSize reports [ErrTooManyEntries] when an update or delete request
+// carries more than the configured number of entries.
+func (v *PrivateDataValidator) checkBatchSize(n int) error {
+	if v.maxBatchEntries > 0 && n > v.maxBatchEntries {
+		return fmt.Errorf("%w (entries %d, limit %d)", ErrTooManyEntries, n, v.maxBatchEntries)
+	}
+	return nil
+}
+
+// checkNotes reports [ErrNotesTooLong] when notes are longer than the
+// configured limit. Absent notes always pass.
+func (v *PrivateDataValidator) checkNotes(notes *models.CipheredNotes) error {
+	if notes == nil || v.maxCipheredNotes <= 0 {
+		return nil
+	}
+	if n := len(*notes); n > v.maxCipheredNotes {
+		return fmt.Errorf("%w (length %d, limit %d)", ErrNotesTooLong, n, v.maxCipheredNotes)
+	}
+	return nil
+}
+
 // validatePrivateData validates a single PrivateData model.
 //
 // Default validated fields (when none specified):
-// ClientSideID, UserID, Metadata, Type, Data, Hash, Version.
+// ClientSideID, UserID, Metadata, Type, Data, Notes, Hash, Version.
 //
 // Special field FieldPrivateDataVersionForDataUpload enforces Version == 0
 // for newly created records.
 //
-// Returns the first encountered validation error or nil.
+// Returns the first encountered validation error, as a [FieldError] naming
+// the field, or nil.
 func (v *PrivateDataValidator) validatePrivateData(ctx context.Context, data models.PrivateData, fields ...string) error {
 	if len(fields) == 0 {
-		fields = []string{FieldClientSideID, FieldUserID, FieldMetadata, FieldType, FieldData, FieldHash, FieldVersion}
+		fields = []string{FieldClientSideID, FieldUserID, FieldMetadata, FieldType, FieldData, FieldNotes, FieldHash, FieldVersion}
 	}
 
 	for _, f := range fields {
 		switch f {
 		case FieldClientSideID:
 			if data.ClientSideID == "" {
-				return ErrInvalidClientSideID
+				return fieldError(FieldClientSideID, ErrInvalidClientSideID)
 			}
 		case FieldUserID:
 			if data.UserID <= 0 {
-				return ErrInvalidUserID
+				return fieldError(FieldUserID, ErrInvalidUserID)
 			}
 		case FieldMetadata:
 			if len(data.Payload.Metadata) == 0 {
-				return ErrEmptyMetadata
+				return fieldError(FieldMetadata, ErrEmptyMetadata)
 			}
 		case FieldType:
 			if !isValidDataType(data.Payload.Type) {
-				return ErrInvalidType
+				return fieldError(FieldType, ErrInvalidType)
 			}
 		case FieldData:
 			if len(data.Payload.Data) == 0 {
-				return ErrEmptyData
+				return fieldError(FieldData, ErrEmptyData)
+			}
+		case FieldNotes:
+			if err := v.checkNotes(data.Payload.Notes); err != nil {
+				return fieldError(FieldNotes, err)
 			}
 		case FieldHash:
 			if data.Hash == "" {
-				return ErrInvalidHash
+				return fieldError(FieldHash, ErrInvalidHash)
 			}
 		case FieldVersion:
 			if data.Version < 0 {
-				return ErrInvalidVersion
+				return fieldError(FieldVersion, ErrInvalidVersion)
 			}
 		case FieldPrivateDataVersionForDataUpload:
 			if data.Version != 0 {
-				return ErrInvalidVersion
+				return fieldError(FieldVersion, ErrInvalidVersion)
 			}
 		default:
 			return ErrUnknownField
@@ -202,17 +319,17 @@ func (v *PrivateDataValidator) validatePrivateData(ctx context.Context, data mod
 // validateUploadRequest validates an UploadRequest, which contains a batch
 // of new vault items to be persisted.
 //
-// Default validated fields: UserID, PrivateData.
+// Default validated fields: UserID, PrivateData, Length.
 //
 // When FieldPrivateData is validated, each item in PrivateDataList is
 // individually checked with validatePrivateData using the upload-specific
 // field set (including FieldPrivateDataVersionForDataUpload to ensure
 // version is zero for new records).
 //
-// Returns a wrapped error indicating the index of the first invalid item.
+// Returns a [FieldError] with the index and field of the first invalid item.
 func (v *PrivateDataValidator) validateUploadRequest(ctx context.Context, request models.UploadRequest, fields ...string) error {
 	if len(fields) == 0 {
-		fields = []string{FieldUserID, FieldPrivateData}
+		fields = []string{FieldUserID, FieldPrivateData, FieldLength}
 	}
 
 	for _, f := range fields {
@@ -226,10 +343,14 @@ func (v *PrivateDataValidator) validateUploadRequest(ctx context.Context, reques
 				return ErrEmptyPrivateData
 			}
 			for i, data := range request.PrivateDataList {
-				if err := v.validatePrivateData(ctx, *data, FieldClientSideID, FieldUserID, FieldMetadata, FieldType, FieldData, FieldHash, FieldPrivateDataVersionForDataUpload); err != nil {
-					return fmt.Errorf("validation error at index %d: %w", i, err)
+				if err := v.validatePrivateData(ctx, *data, UploadItemFields...); err != nil {
+					return AtIndex(err, i)
 				}
 			}
+		case FieldLength:
+			if err := checkLength(request.Length, len(request.PrivateDataList)); err != nil {
+				return err
+			}
 		default:
 			return ErrUnknownField
 		}
@@ -241,15 +362,16 @@ func (v *PrivateDataValidator) validateUploadRequest(ctx context.Context, reques
 // validateUpdateDataRequest validates an UpdateRequest, which contains
 // a batch of partial updates to existing vault items.
 //
-// Default validated fields: UserID, PrivateDataUpdates.
+// Default validated fields: UserID, PrivateDataUpdates, Length.
 //
 // When FieldPrivateDataUpdates is validated, each PrivateDataUpdate
-// is individually checked with validatePrivateDataUpdate.
+// is individually checked with validatePrivateDataUpdate. FieldLength also
+// enforces the per-request entry cap.
 //
-// Returns a wrapped error indicating the index of the first invalid update.
+// Returns a [FieldError] with the index and field of the first invalid update.
 func (v *PrivateDataValidator) validateUpdateDataRequest(ctx context.Context, request models.UpdateRequest, fields ...string) error {
 	if len(fields) == 0 {
-		fields = []string{FieldUserID, FieldPrivateDataUpdates}
+		fields = []string{FieldUserID, FieldPrivateDataUpdates, FieldLength}
 	}
 
 	for _, f := range fields {
@@ -264,9 +386,16 @@ func (v *PrivateDataValidator) validateUpdateDataRequest(ctx context.Context, re
 			}
 			for i, update := range request.PrivateDataUpdates {
 				if err := v.validatePrivateDataUpdate(ctx, update); err != nil {
-					return fmt.Errorf("validation error at index %d: %w", i, err)
+					return AtIndex(err, i)
 				}
 			}
+		case FieldLength:
+			if err := checkLength(request.Length, len(request.PrivateDataUpdates)); err != nil {
+				return err
+			}
+			if err := v.checkBatchSize(len(request.PrivateDataUpdates)); err != nil {
+				return err
+			}
 		default:
 			return ErrUnknownField
 		}
@@ -277,40 +406,46 @@ func (v *PrivateDataValidator) validateUpdateDataRequest(ctx context.Context, re
 
 // validatePrivateDataUpdate validates a single PrivateDataUpdate descriptor.
 //
-// Default validated fields: ClientSideID, Metadata, Data, Version, UpdatedRecordHash.
+// Default validated fields: ClientSideID, Metadata, Data, Notes, Version,
+// UpdatedRecordHash.
 //
-// Field-level checks for Metadata and Data only trigger when the corresponding
-// pointer is non-nil (partial update semantics: nil means "do not touch").
+// Field-level checks for Metadata, Data and Notes only trigger when the
+// corresponding pointer is non-nil (partial update semantics: nil means "do not touch").
 //
 // After field-level checks, an additional structural rule is enforced:
 // at least one payload field (Metadata, Data, Notes, or AdditionalFields)
-// must be non-nil. Returns ErrNoFieldsToUpdate otherwise.
+// must be non-nil. Returns ErrNoFieldsToUpdate otherwise. Field-level
+// failures are returned as a [FieldError] naming the field.
 func (v *PrivateDataValidator) validatePrivateDataUpdate(ctx context.Context, update models.PrivateDataUpdate, fields ...string) error {
 	if len(fields) == 0 {
-		fields = []string{FieldClientSideID, FieldMetadata, FieldData, FieldVersion, FieldVersion, FieldUpdatedRecordHash}
+		fields = []string{FieldClientSideID, FieldMetadata, FieldData, FieldNotes, FieldVersion, FieldVersion, FieldUpdatedRecordHash}
 	}
 
 	for _, f := range fields {
 		switch f {
 		case FieldClientSideID:
 			if update.ClientSideID == "" {
-				return ErrInvalidClientSideID
+				return fieldError(FieldClientSideID, ErrInvalidClientSideID)
 			}
 		case FieldMetadata:
 			if update.FieldsUpdate.Metadata != nil && len(*update.FieldsUpdate.Metadata) == 0 {
-				return ErrEmptyMetadata
+				return fieldError(FieldMetadata, ErrEmptyMetadata)
 			}
 		case FieldData:
 			if update.FieldsUpdate.Data != nil && len(*update.FieldsUpdate.Data) == 0 {
-				return ErrEmptyData
+				return fieldError(FieldData, ErrEmptyData)
+			}
+		case FieldNotes:
+			if err := v.checkNotes(update.FieldsUpdate.Notes); err != nil {
+				return fieldError(FieldNotes, err)
 			}
 		case FieldUpdatedRecordHash:
 			if update.UpdatedRecordHash == "" {
-				return ErrInvalidUpdatedRecordHash
+				return fieldError(FieldUpdatedRecordHash, ErrInvalidUpdatedRecordHash)
 			}
 		case FieldVersion:
 			if update.Version < 0 {
-				return ErrInvalidUpdateVersion
+				return fieldError(FieldVersion, ErrInvalidUpdateVersion)
 			}
 		default:
 			return ErrUnknownField
@@ -327,13 +462,14 @@ func (v *PrivateDataValidator) validatePrivateDataUpdate(ctx context.Context, up
 // validateDeleteDataRequest validates a DeleteRequest, which contains
 // a list of vault items to be soft-deleted.
 //
-// Default validated fields: UserID, ClientSideIDs.
+// Default validated fields: UserID, DeleteEntries, Length.
 //
 // When FieldDeleteEntries is validated, each entry is checked for
-// a non-empty ClientSideID and a non-negative Version.
+// a non-empty ClientSideID and a non-negative Version. FieldLength also
+// enforces the per-request entry cap.
 func (v *PrivateDataValidator) validateDeleteDataRequest(ctx context.Context, request models.DeleteRequest, fields ...string) error {
 	if len(fields) == 0 {
-		fields = []string{FieldUserID, FieldDeleteEntries}
+		fields = []string{FieldUserID, FieldDeleteEntries, FieldLength}
 	}
 
 	for _, f := range fields {
@@ -354,6 +490,57 @@ func (v *PrivateDataValidator) validateDeleteDataRequest(ctx context.Context, re
 					return ErrInvalidVersion
 				}
 			}
+		case FieldLength:
+			if err := checkLength(request.Length, len(request.DeleteEntries)); err != nil {
+				return err
+			}
+			if err := v.checkBatchSize(len(request.DeleteEntries)); err != nil {
+				return err
+			}
+		default:
+			return ErrUnknownField
+		}
+	}
+
+	return nil
+}
+
+// validateRestoreDataRequest validates a RestoreRequest, which contains
+// a list of soft-deleted vault items to be restored.
+//
+// Default validated fields: UserID, RestoreEntries, Length.
+//
+// The checks mirror [PrivateDataValidator.validateDeleteDataRequest].
+func (v *PrivateDataValidator) validateRestoreDataRequest(ctx context.Context, request models.RestoreRequest, fields ...string) error {
+	if len(fields) == 0 {
+		fields = []string{FieldUserID, FieldRestoreEntries, FieldLength}
+	}
+
+	for _, f := range fields {
+		switch f {
+		case FieldUserID:
+			if request.UserID <= 0 {
+				return ErrInvalidUserID
+			}
+		case FieldRestoreEntries:
+			if len(request.RestoreEntries) == 0 {
+				return ErrNoRestoreEntries
+			}
+			for _, restoreEntry := range request.RestoreEntries {
+				if restoreEntry.ClientSideID == "" {
+					return ErrInvalidClientSideID
+				}
+				if restoreEntry.Version < 0 {
+					return ErrInvalidVersion
+				}
+			}
+		case FieldLength:
+			if err := checkLength(request.Length, len(request.RestoreEntries)); err != nil {
+				return err
+			}
+			if err := v.checkBatchSize(len(request.RestoreEntries)); err != nil {
+				return err
+			}
 		default:
 			return ErrUnknownField
 		}
@@ -363,15 +550,17 @@ func (v *PrivateDataValidator) validateDeleteDataRequest(ctx context.Context, re
 }
 
 // validateDownloadDataRequest validates a DownloadRequest, which specifies
-// search criteria for querying vault items by owner and optional client-side IDs.
+// search criteria for querying vault items by owner, optional client-side IDs
+// and optional data types.
 //
-// Default validated fields: UserID, ClientSideIDs.
+// Default validated fields: UserID, ClientSideIDs, Types, Length.
 //
 // When FieldClientSideIDs is validated, each entry in the list is checked
-// for a non-empty value.
+// for a non-empty value. When FieldTypes is validated, each entry must be a
+// recognized DataType.
 func (v *PrivateDataValidator) validateDownloadDataRequest(ctx context.Context, request models.DownloadRequest, fields ...string) error {
 	if len(fields) == 0 {
-		fields = []string{FieldUserID, FieldClientSideIDs}
+		fields = []string{FieldUserID, FieldClientSideIDs, FieldTypes, FieldLength}
 	}
 
 	for _, f := range fields {
@@ -386,6 +575,55 @@ func (v *PrivateDataValidator) validateDownloadDataRequest(ctx context.Context,
 					return ErrInvalidClientSideID
 				}
 			}
+		case FieldTypes:
+			for _, dataType := range request.Types {
+				if !isValidDataType(dataType) {
+					return ErrInvalidType
+				}
+			}
+		case FieldLength:
+			if err := checkLength(request.Length, len(request.ClientSideIDs)); err != nil {
+				return err
+			}
+		default:
+			return ErrUnknownField
+		}
+	}
+
+	return nil
+}
+
+// validateSyncRequest validates a SyncRequest, which lists the client-side
+// IDs whose server states are requested.
+//
+// Default validated fields: UserID, ClientSideIDs, Length.
+//
+// When FieldClientSideIDs is validated, the list must not be empty and each
+// entry must be a non-empty value.
+func (v *PrivateDataValidator) validateSyncRequest(ctx context.Context, request models.SyncRequest, fields ...string) error {
+	if len(fields) == 0 {
+		fields = []string{FieldUserID, FieldClientSideIDs, FieldLength}
+	}
+
+	for _, f := range fields {
+		switch f {
+		case FieldUserID:
+			if request.UserID <= 0 {
+				return ErrInvalidUserID
+			}
+		case FieldClientSideIDs:
+			if len(request.ClientSideIDs) == 0 {
+				return ErrEmptyIDs
+			}
+			for _, clientSideID := range request.ClientSideIDs {
+				if clientSideID == "" {
+					return ErrInvalidClientSideID
+				}
+			}
+		case FieldLength:
+			if err := checkLength(request.Length, len(request.ClientSideIDs)); err != nil {
+				return err
+			}
 		default:
 			return ErrUnknownField
 		}
@@ -5,8 +5,10 @@ package validators
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/MKhiriev/go-pass-keeper/internal/crypto"
 	"github.com/MKhiriev/go-pass-keeper/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -51,7 +53,7 @@ func validPrivateDataUpdate() models.PrivateDataUpdate {
 // ---------------------------------------------------------------------------
 
 func TestNewPrivateDataValidator(t *testing.T) {
-	v := NewPrivateDataValidator()
+	v := NewPrivateDataValidator(0, 0)
 	require.NotNil(t, v)
 }
 
@@ -60,7 +62,7 @@ func TestNewPrivateDataValidator(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestValidate_Dispatch(t *testing.T) {
-	v := NewPrivateDataValidator()
+	v := NewPrivateDataValidator(0, 0)
 	ctx := context.Background()
 
 	t.Run("unsupported type", func(t *testing.T) {
@@ -98,7 +100,7 @@ func TestValidate_Dispatch(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestValidatePrivateData(t *testing.T) {
-	v := NewPrivateDataValidator()
+	v := NewPrivateDataValidator(0, 0)
 	ctx := context.Background()
 
 	t.Run("valid with defaults", func(t *testing.T) {
@@ -191,7 +193,7 @@ func TestValidatePrivateData(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestValidateUploadRequest(t *testing.T) {
-	v := NewPrivateDataValidator()
+	v := NewPrivateDataValidator(0, 0)
 	ctx := context.Background()
 
 	validItem := func() *models.PrivateData {
@@ -203,6 +205,7 @@ func TestValidateUploadRequest(t *testing.T) {
 		r := models.UploadRequest{
 			UserID:          1,
 			PrivateDataList: []*models.PrivateData{validItem()},
+			Length:          1,
 		}
 		require.NoError(t, v.Validate(ctx, r))
 	})
@@ -234,6 +237,11 @@ func TestValidateUploadRequest(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "index 1")
 		assert.ErrorIs(t, err, ErrInvalidClientSideID)
+
+		var fieldErr *FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, 1, fieldErr.Index)
+		assert.Equal(t, FieldClientSideID, fieldErr.Field)
 	})
 
 	t.Run("item with non-zero version fails upload", func(t *testing.T) {
@@ -259,13 +267,14 @@ func TestValidateUploadRequest(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestValidateUpdateDataRequest(t *testing.T) {
-	v := NewPrivateDataValidator()
+	v := NewPrivateDataValidator(0, 0)
 	ctx := context.Background()
 
 	t.Run("valid with defaults", func(t *testing.T) {
 		r := models.UpdateRequest{
 			UserID:             1,
 			PrivateDataUpdates: []models.PrivateDataUpdate{validPrivateDataUpdate()},
+			Length:             1,
 		}
 		require.NoError(t, v.Validate(ctx, r))
 	})
@@ -310,7 +319,7 @@ func TestValidateUpdateDataRequest(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestValidatePrivateDataUpdate(t *testing.T) {
-	v := NewPrivateDataValidator()
+	v := NewPrivateDataValidator(0, 0)
 	ctx := context.Background()
 
 	t.Run("valid with defaults", func(t *testing.T) {
@@ -401,7 +410,7 @@ func TestValidatePrivateDataUpdate(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestValidateDeleteDataRequest(t *testing.T) {
-	v := NewPrivateDataValidator()
+	v := NewPrivateDataValidator(0, 0)
 	ctx := context.Background()
 
 	t.Run("valid with defaults (no delete_entries field checked)", func(t *testing.T) {
@@ -430,12 +439,80 @@ func TestValidateDeleteDataRequest(t *testing.T) {
 	})
 }
 
+// ---------------------------------------------------------------------------
+// TestValidateRestoreDataRequest
+// ---------------------------------------------------------------------------
+
+func TestValidateRestoreDataRequest(t *testing.T) {
+	v := NewPrivateDataValidator(0, 0)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		request any
+		fields  []string
+		wantErr error
+	}{
+		{
+			name:    "valid",
+			request: models.RestoreRequest{UserID: 1, RestoreEntries: []models.RestoreEntry{{ClientSideID: "c1", Version: 2}}, Length: 1},
+		},
+		{
+			name:    "pointer receiver",
+			request: &models.RestoreRequest{UserID: 1, RestoreEntries: []models.RestoreEntry{{ClientSideID: "c1", Version: 2}}, Length: 1},
+		},
+		{
+			name:    "empty restore entries",
+			request: models.RestoreRequest{UserID: 1},
+			wantErr: ErrNoRestoreEntries,
+		},
+		{
+			name:    "invalid user_id",
+			request: models.RestoreRequest{UserID: 0},
+			fields:  []string{FieldUserID},
+			wantErr: ErrInvalidUserID,
+		},
+		{
+			name:    "empty client_side_id",
+			request: models.RestoreRequest{UserID: 1, RestoreEntries: []models.RestoreEntry{{Version: 2}}, Length: 1},
+			wantErr: ErrInvalidClientSideID,
+		},
+		{
+			name:    "negative version",
+			request: models.RestoreRequest{UserID: 1, RestoreEntries: []models.RestoreEntry{{ClientSideID: "c1", Version: -1}}, Length: 1},
+			wantErr: ErrInvalidVersion,
+		},
+		{
+			name:    "length mismatch",
+			request: models.RestoreRequest{UserID: 1, RestoreEntries: []models.RestoreEntry{{ClientSideID: "c1", Version: 2}}, Length: 2},
+			wantErr: ErrLengthMismatch,
+		},
+		{
+			name:    "unknown field",
+			request: models.RestoreRequest{UserID: 1},
+			fields:  []string{"bad_field"},
+			wantErr: ErrUnknownField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(ctx, tt.request, tt.fields...)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestValidateDownloadDataRequest
 // ---------------------------------------------------------------------------
 
 func TestValidateDownloadDataRequest(t *testing.T) {
-	v := NewPrivateDataValidator()
+	v := NewPrivateDataValidator(0, 0)
 	ctx := context.Background()
 
 	t.Run("valid with defaults", func(t *testing.T) {
@@ -457,6 +534,139 @@ func TestValidateDownloadDataRequest(t *testing.T) {
 		r := models.DownloadRequest{UserID: 1}
 		require.NoError(t, v.Validate(ctx, &r))
 	})
+
+	t.Run("valid types filter", func(t *testing.T) {
+		r := models.DownloadRequest{UserID: 1, Types: []models.DataType{models.BankCard, models.Text}}
+		require.NoError(t, v.Validate(ctx, r))
+	})
+
+	t.Run("invalid type in filter", func(t *testing.T) {
+		r := models.DownloadRequest{UserID: 1, Types: []models.DataType{models.BankCard, models.DataType(99)}}
+		require.ErrorIs(t, v.Validate(ctx, r), ErrInvalidType)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestValidateSyncRequest
+// ---------------------------------------------------------------------------
+
+func TestValidateSyncRequest(t *testing.T) {
+	v := NewPrivateDataValidator(0, 0)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		req     models.SyncRequest
+		fields  []string
+		wantErr error
+	}{
+		{name: "valid with defaults", req: models.SyncRequest{UserID: 1, ClientSideIDs: []string{"a"}, Length: 1}},
+		{name: "invalid user_id", req: models.SyncRequest{ClientSideIDs: []string{"a"}, Length: 1}, wantErr: ErrInvalidUserID},
+		{name: "empty ids", req: models.SyncRequest{UserID: 1}, wantErr: ErrEmptyIDs},
+		{name: "empty id", req: models.SyncRequest{UserID: 1, ClientSideIDs: []string{""}, Length: 1}, wantErr: ErrInvalidClientSideID},
+		{name: "unknown field", req: models.SyncRequest{UserID: 1}, fields: []string{"bad_field"}, wantErr: ErrUnknownField},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(ctx, tt.req, tt.fields...)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("pointer receiver", func(t *testing.T) {
+		r := models.SyncRequest{UserID: 1, ClientSideIDs: []string{"a"}, Length: 1}
+		require.NoError(t, v.Validate(ctx, &r))
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestValidateLength
+// ---------------------------------------------------------------------------
+
+func TestValidateLength(t *testing.T) {
+	v := NewPrivateDataValidator(0, 0)
+	ctx := context.Background()
+
+	item := validPrivateData()
+	upload := func(n, length int) models.UploadRequest {
+		r := models.UploadRequest{UserID: 1, Length: length}
+		for range n {
+			r.PrivateDataList = append(r.PrivateDataList, &item)
+		}
+		return r
+	}
+	update := func(n, length int) models.UpdateRequest {
+		r := models.UpdateRequest{UserID: 1, Length: length}
+		for range n {
+			r.PrivateDataUpdates = append(r.PrivateDataUpdates, validPrivateDataUpdate())
+		}
+		return r
+	}
+	remove := func(n, length int) models.DeleteRequest {
+		r := models.DeleteRequest{UserID: 1, Length: length}
+		for range n {
+			r.DeleteEntries = append(r.DeleteEntries, models.DeleteEntry{ClientSideID: "cid-1", Version: 1})
+		}
+		return r
+	}
+	download := func(n, length int) models.DownloadRequest {
+		r := models.DownloadRequest{UserID: 1, Length: length}
+		for range n {
+			r.ClientSideIDs = append(r.ClientSideIDs, "cid-1")
+		}
+		return r
+	}
+	sync := func(n, length int) models.SyncRequest {
+		r := models.SyncRequest{UserID: 1, Length: length}
+		for range n {
+			r.ClientSideIDs = append(r.ClientSideIDs, "cid-1")
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		req     any
+		wantErr bool
+	}{
+		{name: "upload matching", req: upload(2, 2)},
+		{name: "upload mismatching", req: upload(2, 1), wantErr: true},
+		{name: "upload zero length with items", req: upload(1, 0), wantErr: true},
+		{name: "upload negative length", req: upload(1, -1), wantErr: true},
+		{name: "update matching", req: update(2, 2)},
+		{name: "update mismatching", req: update(1, 3), wantErr: true},
+		{name: "update zero length with items", req: update(1, 0), wantErr: true},
+		{name: "delete matching", req: remove(2, 2)},
+		{name: "delete mismatching", req: remove(2, 3), wantErr: true},
+		{name: "delete zero length with entries", req: remove(1, 0), wantErr: true},
+		{name: "download matching", req: download(3, 3)},
+		{name: "download zero length without ids", req: download(0, 0)},
+		{name: "download mismatching", req: download(3, 2), wantErr: true},
+		{name: "download length without ids", req: download(0, 1), wantErr: true},
+		{name: "download negative length", req: download(0, -1), wantErr: true},
+		{name: "sync matching", req: sync(1, 1)},
+		{name: "sync mismatching", req: sync(2, 1), wantErr: true},
+		{name: "sync zero length with ids", req: sync(1, 0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(ctx, tt.req, FieldLength)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				// Defaults include the length check as well.
+				require.NoError(t, v.Validate(ctx, tt.req))
+				return
+			}
+			require.ErrorIs(t, err, ErrLengthMismatch)
+			require.ErrorIs(t, v.Validate(ctx, tt.req), ErrLengthMismatch)
+		})
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -471,3 +681,114 @@ func TestIsValidDataType(t *testing.T) {
 	assert.False(t, isValidDataType(models.DataType(999)))
 	assert.False(t, isValidDataType(models.DataType(-1)))
 }
+
+// ---------------------------------------------------------------------------
+// TestValidate_NotesLimit
+// ---------------------------------------------------------------------------
+
+func TestValidate_NotesLimit(t *testing.T) {
+	const maxNotesLength = 10
+	limit := CipheredNotesLimit(maxNotesLength)
+	v := NewPrivateDataValidator(maxNotesLength, 0)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		notes   *models.CipheredNotes
+		wantErr error
+	}{
+		{name: "no notes", notes: nil},
+		{name: "at limit", notes: ptrNotes(strings.Repeat("A", limit))},
+		{name: "over limit", notes: ptrNotes(strings.Repeat("A", limit+1)), wantErr: ErrNotesTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run("private data "+tt.name, func(t *testing.T) {
+			d := validPrivateData()
+			d.Payload.Notes = tt.notes
+			assert.ErrorIs(t, v.Validate(ctx, d), tt.wantErr)
+		})
+		t.Run("update "+tt.name, func(t *testing.T) {
+			u := validPrivateDataUpdate()
+			u.FieldsUpdate.Notes = tt.notes
+			assert.ErrorIs(t, v.Validate(ctx, u), tt.wantErr)
+		})
+	}
+
+	t.Run("disabled without a limit", func(t *testing.T) {
+		d := validPrivateData()
+		d.Payload.Notes = ptrNotes(strings.Repeat("A", limit+1))
+		assert.NoError(t, NewPrivateDataValidator(0, 0).Validate(ctx, d))
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestValidate_BatchLimit
+// ---------------------------------------------------------------------------
+
+func TestValidate_BatchLimit(t *testing.T) {
+	const maxBatchEntries = 3
+	v := NewPrivateDataValidator(0, maxBatchEntries)
+	ctx := context.Background()
+
+	updateRequest := func(n int) models.UpdateRequest {
+		req := models.UpdateRequest{UserID: 1, Length: n}
+		for range n {
+			req.PrivateDataUpdates = append(req.PrivateDataUpdates, validPrivateDataUpdate())
+		}
+		return req
+	}
+	deleteRequest := func(n int) models.DeleteRequest {
+		req := models.DeleteRequest{UserID: 1, Length: n}
+		for range n {
+			req.DeleteEntries = append(req.DeleteEntries, models.DeleteEntry{ClientSideID: "cid", Version: 1})
+		}
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		entries int
+		wantErr error
+	}{
+		{name: "below limit", entries: maxBatchEntries - 1},
+		{name: "at limit", entries: maxBatchEntries},
+		{name: "over limit", entries: maxBatchEntries + 1, wantErr: ErrTooManyEntries},
+	}
+
+	for _, tt := range tests {
+		t.Run("update "+tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, v.Validate(ctx, updateRequest(tt.entries)), tt.wantErr)
+			assert.ErrorIs(t, v.Validate(ctx, updateRequest(tt.entries), FieldLength), tt.wantErr)
+		})
+		t.Run("delete "+tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, v.Validate(ctx, deleteRequest(tt.entries)), tt.wantErr)
+		})
+	}
+
+	t.Run("disabled without a limit", func(t *testing.T) {
+		unlimited := NewPrivateDataValidator(0, 0)
+		assert.NoError(t, unlimited.Validate(ctx, updateRequest(maxBatchEntries+1)))
+		assert.NoError(t, unlimited.Validate(ctx, deleteRequest(maxBatchEntries+1)))
+	})
+
+	t.Run("uploads are not capped", func(t *testing.T) {
+		d := validPrivateData()
+		req := models.UploadRequest{UserID: 1, PrivateDataList: []*models.PrivateData{&d, &d, &d, &d}, Length: 4}
+		assert.NoError(t, v.Validate(ctx, req, FieldLength))
+	})
+}
+
+func TestCipheredNotesLimit_FitsWorstCaseNote(t *testing.T) {
+	const maxNotesLength = 100
+	dek := make([]byte, 32)
+
+	// Control characters are JSON-escaped to six bytes each, the longest
+	// encoding a single character can get.
+	note := models.Notes{Notes: strings.Repeat("\x01", maxNotesLength)}
+	enc, err := crypto.NewKeyChainService().EncryptData(note, dek)
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, len(enc), CipheredNotesLimit(maxNotesLength))
+	assert.Zero(t, CipheredNotesLimit(0))
+}
@@ -0,0 +1,24 @@
+-- SPDX-License-Identifier: Apache-2.0
+-- Copyright 2026 Rasul Khiriev
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS audit_log (
+    id             BIGSERIAL   PRIMARY KEY,
+    user_id        BIGINT      NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    operation      TEXT        NOT NULL CHECK (operation IN ('create', 'update', 'delete')),
+    client_side_id TEXT        NOT NULL,
+    version        BIGINT      NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_user_id_id_idx ON audit_log (user_id, id DESC);
+
+COMMENT ON TABLE audit_log IS
+    'Append-only audit trail of vault mutations. Stores only metadata, never payloads.';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_log;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- SPDX-License-Identifier: Apache-2.0
+-- Copyright 2026 Rasul Khiriev
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS cipher_history (
+    id                BIGSERIAL   PRIMARY KEY,
+    user_id           BIGINT      NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    client_side_id    TEXT        NOT NULL,
+    version           BIGINT      NOT NULL,
+    type              INTEGER     NOT NULL REFERENCES data_types(id),
+    metadata          TEXT        NOT NULL,
+    data              TEXT        NOT NULL,
+    notes             TEXT,
+    additional_fields TEXT,
+    hash              TEXT        NOT NULL DEFAULT '',
+    updated_at        TIMESTAMPTZ,
+    archived_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (user_id, client_side_id, version)
+);
+
+COMMENT ON TABLE cipher_history IS
+    'Previous versions of ciphers rows, written on update and trimmed to the configured count. Payloads stay encrypted.';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS cipher_history;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- SPDX-License-Identifier: Apache-2.0
+-- Copyright 2026 Rasul Khiriev
+
+-- +goose Up
+-- +goose StatementBegin
+
+-- Room for an optional device prefix in front of the 36-character UUID.
+ALTER TABLE ciphers
+    ALTER COLUMN client_side_id TYPE VARCHAR(64);
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE ciphers
+    ALTER COLUMN client_side_id TYPE VARCHAR(40);
+
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- SPDX-License-Identifier: Apache-2.0
+-- Copyright 2026 Rasul Khiriev
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS sync_activity (
+    user_id      BIGINT      PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    last_seen_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS sync_activity_last_seen_at_idx ON sync_activity (last_seen_at);
+
+COMMENT ON TABLE sync_activity IS
+    'Time of the last successful sync or vault request of each user, for monitoring sync lag.';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sync_activity;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- SPDX-License-Identifier: Apache-2.0
+-- Copyright 2026 Rasul Khiriev
+
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_operation_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_operation_check
+    CHECK (operation IN ('create', 'update', 'delete', 'restore'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM audit_log WHERE operation = 'restore';
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_operation_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_operation_check
+    CHECK (operation IN ('create', 'update', 'delete'));
+-- +goose StatementEnd
@@ -11,6 +11,8 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
+	"io/fs"
+	"strconv"
 	"strings"
 
 	"github.com/pressly/goose/v3"
@@ -64,6 +66,26 @@ func Migrate(db *sql.DB) error {
 	return nil
 }
 
+// LatestVersion returns the version of the newest embedded PostgreSQL
+// migration, i.e. the server schema version this build was compiled against.
+// Clients compare it with the schema version reported by the server: a server
+// ahead of it may store ciphers in a format this build does not understand.
+func LatestVersion() int64 {
+	files, err := fs.Glob(embedMigrations, "*.sql")
+	if err != nil {
+		return 0
+	}
+
+	var latest int64
+	for _, name := range files {
+		prefix, _, _ := strings.Cut(name, "_")
+		if version, err := strconv.ParseInt(prefix, 10, 64); err == nil && version > latest {
+			latest = version
+		}
+	}
+	return latest
+}
+
 func resolveDialectAndDir(db *sql.DB) (dialect, dir string) {
 	driverType := fmt.Sprintf("%T", db.Driver())
 	if strings.Contains(strings.ToLower(driverType), "sqlite") {
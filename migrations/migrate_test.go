@@ -5,6 +5,8 @@ package migrations
 
 import (
 	"database/sql"
+	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -42,3 +44,25 @@ func TestMigrate_NilDB(t *testing.T) {
 		t.Errorf("expected 'db is nil' error, got: %v", err)
 	}
 }
+
+func TestLatestVersion(t *testing.T) {
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatalf("read migrations dir: %v", err)
+	}
+
+	var want int64
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		prefix, _, _ := strings.Cut(e.Name(), "_")
+		if v, err := strconv.ParseInt(prefix, 10, 64); err == nil && v > want {
+			want = v
+		}
+	}
+
+	if got := LatestVersion(); got != want || got == 0 {
+		t.Errorf("LatestVersion() = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Migration describes one embedded migration file.
+type Migration struct {
+	// Version is the numeric prefix of the file name.
+	Version int64
+	// Name is the file name, e.g. "00011_add_sync_activity.sql".
+	Name string
+}
+
+// versionTableExists reports, per goose dialect, whether the goose version
+// table exists. $1 is the table name.
+var versionTableExists = map[string]string{
+	"pgx":     `SELECT to_regclass($1) IS NOT NULL;`,
+	"sqlite3": `SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = $1;`,
+}
+
+// Pending returns the embedded migrations that [Migrate] would apply to db,
+// in the order it would apply them, without changing the database.
+//
+// The current schema version is read from the goose version table the same
+// way goose reads it; a database without the table has every migration
+// pending. Unlike [Migrate], Pending never creates the table, so it only
+// issues SELECT statements.
+func Pending(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	if db == nil {
+		return nil, fmt.Errorf("migration error: db is nil")
+	}
+
+	goose.SetBaseFS(embedMigrations)
+
+	dialect, dir := resolveDialectAndDir(db)
+	current, err := currentVersion(ctx, db, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("migration error reading schema version: %w", err)
+	}
+
+	found, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+	if err != nil {
+		return nil, fmt.Errorf("migration error collecting migrations: %w", err)
+	}
+
+	pending := make([]Migration, 0, len(found))
+	for _, m := range found {
+		if m.Version <= current {
+			continue
+		}
+		pending = append(pending, Migration{Version: m.Version, Name: filepath.Base(m.Source)})
+	}
+	return pending, nil
+}
+
+// currentVersion returns the schema version goose considers applied: the
+// newest version whose latest record is marked as applied, or 0 when the
+// version table does not exist yet.
+func currentVersion(ctx context.Context, db *sql.DB, dialect string) (int64, error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx, versionTableExists[dialect], goose.TableName()).Scan(&exists); err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	// The table name comes from goose, not from user input.
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`SELECT version_id, is_applied FROM %s ORDER BY id DESC;`, goose.TableName()))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	// The most recent record of each version says whether it is applied or
+	// rolled back, as in goose.EnsureDBVersion.
+	skip := make(map[int64]struct{})
+	for rows.Next() {
+		var (
+			version int64
+			applied bool
+		)
+		if err = rows.Scan(&version, &applied); err != nil {
+			return 0, err
+		}
+		if _, ok := skip[version]; ok {
+			continue
+		}
+		if applied {
+			return version, rows.Err()
+		}
+		skip[version] = struct{}{}
+	}
+	return 0, rows.Err()
+}
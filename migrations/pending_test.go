@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package migrations
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPending(t *testing.T) {
+	latest := LatestVersion()
+	if latest < 3 {
+		t.Fatalf("need at least 3 embedded migrations, have %d", latest)
+	}
+
+	tests := []struct {
+		name        string
+		tableExists bool
+		// records are the goose version table rows, newest first.
+		records [][2]any
+		want    int64 // first pending version
+	}{
+		{
+			name:        "fresh database",
+			tableExists: false,
+			want:        1,
+		},
+		{
+			name:        "partially migrated",
+			tableExists: true,
+			records:     [][2]any{{latest - 2, true}, {latest - 3, true}, {int64(0), true}},
+			want:        latest - 1,
+		},
+		{
+			name:        "last migration rolled back",
+			tableExists: true,
+			records:     [][2]any{{latest - 1, false}, {latest - 1, true}, {latest - 2, true}, {int64(0), true}},
+			want:        latest - 1,
+		},
+		{
+			name:        "up to date",
+			tableExists: true,
+			records:     [][2]any{{latest, true}, {int64(0), true}},
+			want:        latest + 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			// Only these reads are expected: any CREATE, INSERT or migration
+			// statement makes Pending fail on an unexpected call.
+			mock.ExpectQuery(regexp.QuoteMeta(versionTableExists["pgx"])).
+				WithArgs("goose_db_version").
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tt.tableExists))
+			if tt.tableExists {
+				rows := sqlmock.NewRows([]string{"version_id", "is_applied"})
+				for _, r := range tt.records {
+					rows.AddRow(r[0], r[1])
+				}
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT version_id, is_applied FROM goose_db_version ORDER BY id DESC;")).
+					WillReturnRows(rows)
+			}
+
+			pending, err := Pending(context.Background(), db)
+			if err != nil {
+				t.Fatalf("Pending() error: %v", err)
+			}
+
+			if got, want := int64(len(pending)), latest-tt.want+1; got != want {
+				t.Fatalf("Pending() returned %d migrations, want %d: %v", got, want, pending)
+			}
+			for i, m := range pending {
+				if m.Version != tt.want+int64(i) {
+					t.Errorf("pending[%d].Version = %d, want %d", i, m.Version, tt.want+int64(i))
+				}
+				if m.Name == "" {
+					t.Errorf("pending[%d].Name is empty", i)
+				}
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPending_NilDB(t *testing.T) {
+	if _, err := Pending(context.Background(), nil); err == nil {
+		t.Fatal("expected error when db is nil, got nil")
+	}
+}
@@ -0,0 +1,16 @@
+-- SPDX-License-Identifier: Apache-2.0
+-- Copyright 2026 Rasul Khiriev
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS sync_state
+(
+    user_id        INTEGER  PRIMARY KEY,
+    last_synced_at DATETIME NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sync_state;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- SPDX-License-Identifier: Apache-2.0
+-- Copyright 2026 Rasul Khiriev
+
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE ciphers ADD COLUMN server_id INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ciphers DROP COLUMN server_id;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- SPDX-License-Identifier: Apache-2.0
+-- Copyright 2026 Rasul Khiriev
+
+-- The auth hash is the credential the server accepts, so a cached copy lets
+-- anyone holding the client database or a backup of it log in without the
+-- master password. Offline logins check the password by unwrapping the DEK
+-- instead; the column is kept empty.
+
+-- +goose Up
+-- +goose StatementBegin
+UPDATE users SET auth_hash = '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 1;
+-- +goose StatementEnd
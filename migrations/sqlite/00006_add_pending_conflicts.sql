@@ -0,0 +1,19 @@
+-- SPDX-License-Identifier: Apache-2.0
+-- Copyright 2026 Rasul Khiriev
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS pending_conflicts
+(
+    user_id        INTEGER NOT NULL,
+    client_side_id TEXT    NOT NULL,
+    operation      TEXT    NOT NULL DEFAULT '',
+    server_version INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, client_side_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pending_conflicts;
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+import "time"
+
+// AuditOperation identifies the kind of vault mutation recorded in the audit log.
+type AuditOperation string
+
+const (
+	// AuditOperationCreate records the upload of a new vault item.
+	AuditOperationCreate AuditOperation = "create"
+
+	// AuditOperationUpdate records a successful update of a vault item.
+	AuditOperationUpdate AuditOperation = "update"
+
+	// AuditOperationDelete records a soft-delete of a vault item.
+	AuditOperationDelete AuditOperation = "delete"
+
+	// AuditOperationRestore records the restore of a soft-deleted vault item.
+	AuditOperationRestore AuditOperation = "restore"
+)
+
+// AuditEntry is a single row of the server-side audit trail. It describes who
+// changed which vault item and when. Encrypted payloads are never recorded.
+type AuditEntry struct {
+	// ID is the server-assigned, monotonically increasing entry identifier.
+	ID int64 `json:"id"`
+
+	// UserID is the owner of the mutated vault item.
+	UserID int64 `json:"user_id"`
+
+	// Operation is the kind of mutation that was performed.
+	Operation AuditOperation `json:"operation"`
+
+	// ClientSideID identifies the mutated vault item.
+	ClientSideID string `json:"client_side_id"`
+
+	// Version is the version of the vault item after the mutation.
+	Version int64 `json:"version"`
+
+	// CreatedAt is the time the mutation was committed.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditLogRequest selects a page of a user's audit entries, newest first.
+type AuditLogRequest struct {
+	// UserID is the user whose audit entries are requested.
+	UserID int64 `json:"user_id"`
+
+	// Limit is the maximum number of entries to return.
+	Limit int `json:"limit"`
+
+	// Offset is the number of newest entries to skip.
+	Offset int `json:"offset"`
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+// ConflictOperation names the write that was rejected by an optimistic-locking
+// conflict.
+type ConflictOperation string
+
+const (
+	// ConflictOnUpdate marks a conflict of an update of an item.
+	ConflictOnUpdate ConflictOperation = "update"
+	// ConflictOnDelete marks a conflict of a delete of an item.
+	ConflictOnDelete ConflictOperation = "delete"
+	// ConflictOnRestore marks a conflict of a restore of a deleted item.
+	ConflictOnRestore ConflictOperation = "restore"
+)
+
+// VersionConflict describes an optimistic-locking conflict: the version sent
+// by the client no longer matches the one stored on the server.
+type VersionConflict struct {
+	// Operation is the rejected write. Empty when the server did not report
+	// it.
+	Operation ConflictOperation
+
+	// ClientSideID is the item the conflict occurred on.
+	ClientSideID string
+
+	// ServerVersion is the current version of the item on the server. Zero
+	// when the server did not report it.
+	ServerVersion int64
+}
+
+// ConflictPolicy selects how the client sync handles an update the server
+// rejects with a version conflict.
+type ConflictPolicy string
+
+// Supported conflict policies.
+const (
+	// ConflictServerWins discards the rejected local change and stores the
+	// server's current version locally. It is the default.
+	ConflictServerWins ConflictPolicy = "server-wins"
+
+	// ConflictManual leaves the conflicting item untouched and reports it,
+	// so that the user can compare both versions and pick a
+	// [ConflictChoice]. The rest of the sync plan is still carried out.
+	ConflictManual ConflictPolicy = "manual"
+)
+
+// IsValid reports whether p is a supported policy. The empty string is
+// accepted as [ConflictServerWins].
+func (p ConflictPolicy) IsValid() bool {
+	switch p {
+	case "", ConflictServerWins, ConflictManual:
+		return true
+	}
+	return false
+}
+
+// ConflictChoice is the user's resolution of a conflict reported under
+// [ConflictManual].
+type ConflictChoice string
+
+const (
+	// ConflictKeepLocal overwrites the server's version with the local one.
+	ConflictKeepLocal ConflictChoice = "keep-local"
+	// ConflictKeepServer replaces the local version with the server's one,
+	// as [ConflictServerWins] would have done.
+	ConflictKeepServer ConflictChoice = "keep-server"
+	// ConflictKeepBoth keeps the server's version under the item's ID and
+	// saves the local one as a new item, uploaded by the next sync.
+	ConflictKeepBoth ConflictChoice = "keep-both"
+)
+
+// ConflictVersions holds both sides of a conflict, decrypted, for the user
+// to compare.
+type ConflictVersions struct {
+	// Conflict is the conflict as reported by the sync, with ServerVersion
+	// set to the version of Server.
+	Conflict VersionConflict
+
+	// Local is the local version of the item, including the rejected change.
+	Local DecipheredPayload
+
+	// Server is the version currently stored on the server.
+	Server DecipheredPayload
+}
@@ -6,9 +6,16 @@ package models
 // CustomField represents a user-defined field attached to PrivateData.
 // Each custom field has its own semantic type and encrypted value.
 type CustomField struct {
+	// Name is the user-visible label of the custom field.
+	Name string `json:"name,omitempty"`
+
 	// Type defines the data type of the custom field.
 	Type DataType `json:"type"`
 
 	// Data contains the encrypted value of the custom field.
 	Data CipheredData `json:"data"`
+
+	// IsSensitive marks the value as a secret (e.g. recovery codes).
+	// Sensitive values are masked in the client until the user reveals them.
+	IsSensitive bool `json:"is_sensitive,omitempty"`
 }
@@ -43,6 +43,16 @@ type LoginData struct {
 	// TOTP contains an optional time-based one-time password seed.
 	// When present, it is used to generate 2FA codes.
 	TOTP *string `json:"totp,omitempty"`
+
+	// TOTPAlgorithm is the HMAC hash used for codes: "SHA1", "SHA256" or
+	// "SHA512". Empty means SHA1.
+	TOTPAlgorithm string `json:"totpAlgorithm,omitempty"`
+
+	// TOTPDigits is the code length. Zero means 6.
+	TOTPDigits int `json:"totpDigits,omitempty"`
+
+	// TOTPPeriod is the code lifetime in seconds. Zero means 30.
+	TOTPPeriod int `json:"totpPeriod,omitempty"`
 }
 
 // LoginURI represents a single resource matching rule
@@ -52,10 +62,27 @@ type LoginURI struct {
 	URI string `json:"uri"`
 
 	// Match defines the matching strategy used to associate
-	// the login with the given URI.
+	// the login with the given URI; one of the URIMatch constants.
 	Match int `json:"match"`
 }
 
+// Match rules of a [LoginURI].
+const (
+	// URIMatchDomain matches any URI on the same base domain. It is the
+	// zero value, so URIs stored without a rule use it.
+	URIMatchDomain = iota
+	// URIMatchHost matches the host name and port exactly.
+	URIMatchHost
+	// URIMatchStartsWith matches URIs that begin with the stored value.
+	URIMatchStartsWith
+	// URIMatchExact matches only the identical URI.
+	URIMatchExact
+	// URIMatchRegex treats the stored value as a regular expression.
+	URIMatchRegex
+	// URIMatchNever never matches; the URI is kept for reference only.
+	URIMatchNever
+)
+
 // TextData represents decrypted free-form textual content.
 // Used for secure notes or arbitrary secret text.
 type TextData struct {
@@ -78,6 +105,23 @@ type BinaryData struct {
 	// Key is the encryption key or key reference
 	// used to encrypt and decrypt the binary content.
 	Key string `json:"key"`
+
+	// ChunkSize is the plaintext size of each encrypted chunk of the binary
+	// content. Zero means the content has not been stream-encrypted.
+	ChunkSize int `json:"chunkSize,omitempty"`
+
+	// ChunkCount is the number of encrypted chunks, including the final one.
+	ChunkCount int `json:"chunkCount,omitempty"`
+
+	// NoncePrefix is the base64-encoded random prefix shared by all chunk
+	// nonces of the stream-encrypted content.
+	NoncePrefix string `json:"noncePrefix,omitempty"`
+
+	// Content is the file itself, embedded so that it is encrypted and synced
+	// with the entry. Once encrypted it holds the chunk ciphertext described
+	// by ChunkSize, ChunkCount and NoncePrefix. It is empty for entries that
+	// were added with metadata only.
+	Content []byte `json:"content,omitempty"`
 }
 
 // BankCardData represents decrypted payment card information.
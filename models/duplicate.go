@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+// DuplicateGroup describes vault items that hold identical decrypted content
+// but different ClientSideIDs, typically because the same entry was created
+// independently on two offline devices.
+type DuplicateGroup struct {
+	// Name is the item name shared by every entry in the group.
+	Name string
+
+	// Keep is the ClientSideID of the entry with the highest version. It
+	// survives a merge.
+	Keep string
+
+	// Duplicates are the ClientSideIDs that a merge soft-deletes.
+	Duplicates []string
+}
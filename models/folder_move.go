@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+// MoveResult reports the outcome of moving several vault items into one
+// folder.
+type MoveResult struct {
+	// Moved are the ClientSideIDs whose folder was changed locally and on
+	// the server.
+	Moved []string
+
+	// Conflicted are the ClientSideIDs left untouched because the server
+	// copy is missing, deleted or at a different version than the local one.
+	// A sync brings them up to date.
+	Conflicted []string
+}
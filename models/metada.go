@@ -11,4 +11,14 @@ type Metadata struct {
 
 	// Folder is an optional logical container used to group items.
 	Folder *string
+
+	// Favorite pins the item to the top of the client's list. It is omitted
+	// from the encrypted metadata when false, so the metadata of items that
+	// were never pinned looks the same as before the field existed.
+	Favorite bool `json:",omitempty"`
+
+	// Reprompt makes the client ask for the master password again before
+	// the secrets of the item are revealed or copied. Omitted when false,
+	// like Favorite.
+	Reprompt bool `json:",omitempty"`
 }
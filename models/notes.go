@@ -4,11 +4,12 @@
 package models
 
 // Notes represents an optional textual annotation attached to PrivateData.
+//
+// Notes are always encrypted on the client together with the rest of the
+// item and reach the server only as [CipheredNotes]; there are no plaintext
+// notes. Blobs written by older clients may still carry an "IsEncrypted"
+// key, which is ignored on decryption.
 type Notes struct {
-	// IsEncrypted indicates whether the notes content is encrypted.
-	IsEncrypted bool
-
 	// Notes contains the note text.
-	// When IsEncrypted is true, this value is stored in encrypted form.
 	Notes string
 }
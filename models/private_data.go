@@ -68,8 +68,8 @@ type PrivateDataPayload struct {
 	Data CipheredData `json:"data"`
 
 	// Notes contains optional user notes.
-	// Notes may be stored encrypted or in plain form depending on configuration.
-	// Notes are stored in DB as an encrypted string.
+	// Notes are always encrypted by the client and stored in DB as an
+	// encrypted string.
 	Notes *CipheredNotes `json:"notes,omitempty"`
 
 	// AdditionalFields contains optional custom user-defined fields.
@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+import "time"
+
+// RecoveryKit documents the identifiers of an account for offline storage.
+//
+// It deliberately holds nothing secret: the encryption salt is public (the
+// server hands it out before login) and the master password is still needed
+// to derive the KEK and unwrap the DEK kept on the server. The kit lets the
+// user log in again from a new device after losing the local store.
+type RecoveryKit struct {
+	// Login is the account login.
+	Login string `json:"login"`
+
+	// UserID is the server-assigned account ID.
+	UserID int64 `json:"user_id"`
+
+	// EncryptionSalt is the base64 KDF salt of the account.
+	EncryptionSalt string `json:"encryption_salt"`
+
+	// CreatedAt is when the kit was generated.
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -15,4 +15,8 @@ type DownloadRequest struct {
 
 	// Length is the total number of entries in ClientSideIDs.
 	Length int `json:"length"`
+
+	// Types optionally restricts the result to the listed data types.
+	// An empty list means no type filtering.
+	Types []DataType `json:"types,omitempty"`
 }
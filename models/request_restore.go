@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+// RestoreRequest represents criteria for restoring soft-deleted vault items.
+// Restoring clears the deleted flag and increments the version, so that other
+// clients pick the item up again during sync.
+type RestoreRequest struct {
+	// UserID is the owner of the data to restore.
+	UserID int64 `json:"user_id"`
+
+	// RestoreEntries is a list of items to restore.
+	// Each entry carries its own version for independent optimistic locking.
+	RestoreEntries []RestoreEntry `json:"restore_entries"`
+
+	// Length is the total number of entries in RestoreEntries.
+	Length int `json:"length"`
+}
+
+// RestoreEntry identifies a single soft-deleted vault item to be restored.
+type RestoreEntry struct {
+	// ClientSideID is the unique identifier generated by the client.
+	ClientSideID string `json:"client_side_id"`
+
+	// Version is used for optimistic concurrency control. The server rejects
+	// the restore if this value does not match the version of the tombstone
+	// in the database.
+	Version int64 `json:"version"`
+}
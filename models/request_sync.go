@@ -21,3 +21,24 @@ type SyncRequest struct {
 	// Length is the total number of entries in ClientSideIDs.
 	Length int `json:"length"`
 }
+
+// StatesPageRequest selects one page of a user's state descriptors.
+// Pages are ordered by the server-assigned record id, so that a cursor stays
+// valid while items are added or changed between requests.
+type StatesPageRequest struct {
+	// UserID is the owner of the vault being synchronized.
+	UserID int64 `json:"user_id"`
+
+	// After is the cursor returned with the previous page as
+	// [SyncResponse.NextAfter]. Zero requests the first page.
+	After int64 `json:"after"`
+
+	// Limit is the maximum number of states to return. Zero means no limit.
+	Limit int `json:"limit"`
+
+	// ExcludeDeleted leaves the tombstones of deleted items out of the page.
+	// Sync needs them to propagate deletions, so they are included by default;
+	// a fresh bootstrap can leave them out. The states endpoint sets it when
+	// include_deleted=false is passed.
+	ExcludeDeleted bool `json:"exclude_deleted"`
+}
@@ -17,4 +17,9 @@ type UploadRequest struct {
 
 	// Length is the total number of entries in PrivateDataList.
 	Length int `json:"length"`
+
+	// BestEffort stores every item on its own instead of in one transaction.
+	// Items that fail are reported in [UploadResponse.Failed] and do not
+	// prevent the others from being stored.
+	BestEffort bool `json:"best_effort,omitempty"`
 }
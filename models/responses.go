@@ -3,6 +3,8 @@
 
 package models
 
+import "time"
+
 // SyncResponse contains the server-side state of every vault item
 // that belongs to the user. The client uses this information to
 // reconcile its local database: download missing items, push local
@@ -18,4 +20,89 @@ type SyncResponse struct {
 	// Provided for convenience so the client can pre-allocate
 	// or validate the response without iterating the slice.
 	Length int `json:"length"`
+
+	// NextAfter is the cursor for the next page of a paginated states
+	// response. Zero means that this is the last page.
+	NextAfter int64 `json:"next_after,omitempty"`
+}
+
+// SchemaVersionResponse is returned by the server schema version endpoint.
+type SchemaVersionResponse struct {
+	// SchemaVersion is the version of the newest applied database migration.
+	SchemaVersion int64 `json:"schema_version"`
+
+	// HardDelete is set when the server removes deleted items instead of
+	// keeping tombstones. Only then may a client take an item missing on
+	// the server for a deleted one.
+	HardDelete bool `json:"hard_delete"`
+}
+
+// UploadResponse is returned by the server after a successful upload. It
+// carries the server-assigned identifiers and timestamps of every stored
+// item so that the client can reflect them in its local copy.
+type UploadResponse struct {
+	// Items lists one entry per uploaded vault item, keyed by ClientSideID.
+	Items []UploadedItem `json:"items"`
+
+	// Length is the total number of entries in Items.
+	Length int `json:"length"`
+
+	// Failed lists the items a best-effort upload could not store. Always
+	// empty for a regular upload, which fails as a whole instead.
+	Failed []UploadFailure `json:"failed,omitempty"`
+}
+
+// UploadFailure describes one item of a best-effort upload that was not
+// stored.
+type UploadFailure struct {
+	// ClientSideID identifies the rejected item.
+	ClientSideID string `json:"client_side_id"`
+
+	// Status is the HTTP status the item would have been rejected with in a
+	// regular upload, e.g. 409 for a client_side_id that is already taken.
+	Status int `json:"status"`
+
+	// Error is the reason the item was rejected.
+	Error string `json:"error"`
+
+	// Field is the JSON name of the item's field that failed validation, if
+	// the item was rejected for one.
+	Field string `json:"field,omitempty"`
+}
+
+// ValidationFailure is the JSON body of a 400 response to an upload or update
+// that failed validation on a known item or field. Other 400 responses carry
+// a plain-text message.
+type ValidationFailure struct {
+	// Message is the generic message of the response, the same text a
+	// plain-text 400 response would carry.
+	Message string `json:"message"`
+
+	// Index is the position of the invalid item in the request's list, e.g.
+	// PrivateDataList of an upload. It is nil when the failure is not about
+	// one item.
+	Index *int `json:"index,omitempty"`
+
+	// Field is the JSON name of the invalid field, e.g. "client_side_id".
+	// It is empty when the failure is about the item as a whole.
+	Field string `json:"field,omitempty"`
+
+	// Reason describes what is wrong, e.g. "invalid client side id".
+	Reason string `json:"reason"`
+}
+
+// UploadedItem holds the server-assigned fields of a single uploaded item.
+type UploadedItem struct {
+	// ID is the server database identifier of the stored record.
+	ID int64 `json:"id"`
+
+	// ClientSideID identifies the item the server fields belong to.
+	ClientSideID string `json:"client_side_id"`
+
+	// CreatedAt is the creation timestamp stored by the server.
+	CreatedAt *time.Time `json:"created_at"`
+
+	// UpdatedAt is the last modification timestamp stored by the server,
+	// nil for a freshly created record.
+	UpdatedAt *time.Time `json:"updated_at"`
 }
@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+// VaultSummary describes the composition of a user's vault. It is computed
+// from the unencrypted type and deleted columns, so no item has to be
+// decrypted.
+type VaultSummary struct {
+	// ByType counts the live (not deleted) items of each type. Types
+	// without items are absent.
+	ByType map[DataType]int `json:"by_type"`
+
+	// Total is the number of live items, the sum of ByType.
+	Total int `json:"total"`
+
+	// Deleted is the number of soft-deleted items not yet purged.
+	Deleted int `json:"deleted"`
+}
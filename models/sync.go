@@ -10,6 +10,12 @@ import "time"
 // the local copy is up-to-date, needs to be fetched, pushed, or removed.
 // No encrypted payload is included — only identity and change-detection fields.
 type PrivateDataState struct {
+	// ID is the server-assigned record id used as the pagination cursor.
+	// It is not serialized; clients receive the cursor as
+	// [SyncResponse.NextAfter]. In client-side states it is the server id the
+	// item was last synced under, or zero if it has never reached the server.
+	ID int64 `json:"-"`
+
 	// ClientSideID is the unique identifier generated by the client.
 	// Used to correlate server records with local client state.
 	ClientSideID string `json:"client_side_id"`
@@ -65,3 +71,54 @@ type SyncPlan struct {
 	// The client holds a newer or equal version that is soft-deleted (Deleted == true).
 	DeleteServer []PrivateDataState
 }
+
+// SyncMode selects which buckets of a [SyncPlan] the client executes. The
+// plan itself is always computed in full.
+type SyncMode string
+
+// Supported sync modes.
+const (
+	// SyncModeBidirectional executes every bucket. It is the default.
+	SyncModeBidirectional SyncMode = "bidirectional"
+
+	// SyncModePushOnly only writes local changes to the server: Upload and
+	// Update. Nothing is downloaded and nothing is deleted on either side.
+	SyncModePushOnly SyncMode = "push-only"
+
+	// SyncModePullOnly never modifies the server: only Download and
+	// DeleteClient are executed.
+	SyncModePullOnly SyncMode = "pull-only"
+)
+
+// IsValid reports whether m is a supported mode. The empty string is
+// accepted as [SyncModeBidirectional].
+func (m SyncMode) IsValid() bool {
+	switch m {
+	case "", SyncModeBidirectional, SyncModePushOnly, SyncModePullOnly:
+		return true
+	}
+	return false
+}
+
+// Filter returns plan with the buckets that m does not execute set to nil.
+func (m SyncMode) Filter(plan SyncPlan) SyncPlan {
+	switch m {
+	case SyncModePushOnly:
+		return SyncPlan{Upload: plan.Upload, Update: plan.Update}
+	case SyncModePullOnly:
+		return SyncPlan{Download: plan.Download, DeleteClient: plan.DeleteClient}
+	default:
+		return plan
+	}
+}
+
+// ClientSideIDState is one entry of the list of every client-side ID a user
+// has on the server, soft-deleted items included. Clients diff the list
+// against their local IDs to find items the server never received.
+type ClientSideIDState struct {
+	// ClientSideID is the identifier generated by the client.
+	ClientSideID string `json:"client_side_id"`
+
+	// Deleted reports that the item is a tombstone.
+	Deleted bool `json:"deleted"`
+}
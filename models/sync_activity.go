@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+import "time"
+
+// SyncLag describes how long ago a user's client last synchronised with the
+// server.
+type SyncLag struct {
+	// UserID is the user the activity belongs to.
+	UserID int64 `json:"user_id"`
+
+	// LastSeenAt is the time of the last successful sync or vault request.
+	LastSeenAt time.Time `json:"last_seen_at"`
+
+	// LagSeconds is the number of whole seconds between LastSeenAt and the
+	// time the lag was queried.
+	LagSeconds int64 `json:"lag_seconds"`
+}
+
+// SyncLagRequest selects a page of users' sync activity, stalest first.
+type SyncLagRequest struct {
+	// UserID limits the result to one user; zero lists every user.
+	UserID int64 `json:"user_id"`
+
+	// Limit is the maximum number of users to return.
+	Limit int `json:"limit"`
+
+	// Offset is the number of stalest users to skip.
+	Offset int `json:"offset"`
+}
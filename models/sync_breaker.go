@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+import "time"
+
+// SyncBreakerState is the state of the client's sync circuit breaker, which
+// stops FullSync from contacting a server that keeps failing on the network
+// level.
+type SyncBreakerState string
+
+const (
+	// SyncBreakerClosed lets every sync through. It is the normal state.
+	SyncBreakerClosed SyncBreakerState = "closed"
+
+	// SyncBreakerOpen fails every sync at once without contacting the
+	// server until the cooldown has passed.
+	SyncBreakerOpen SyncBreakerState = "open"
+
+	// SyncBreakerHalfOpen lets a single probing sync through after the
+	// cooldown. Its outcome closes the breaker or opens it again.
+	SyncBreakerHalfOpen SyncBreakerState = "half-open"
+)
+
+// SyncBreakerStatus is a snapshot of the sync circuit breaker, shown in the
+// status line.
+type SyncBreakerStatus struct {
+	// State is the breaker state.
+	State SyncBreakerState
+
+	// Failures is the number of consecutive syncs that failed on the
+	// network level.
+	Failures int
+
+	// RetryAt is when an open breaker lets the next probing sync through.
+	// It is zero unless State is [SyncBreakerOpen].
+	RetryAt time.Time
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+import "time"
+
+// SyncEventOp names the sync operation a [SyncEvent] describes.
+type SyncEventOp string
+
+const (
+	SyncEventDownload     SyncEventOp = "download"
+	SyncEventUpload       SyncEventOp = "upload"
+	SyncEventUpdate       SyncEventOp = "update"
+	SyncEventDeleteClient SyncEventOp = "delete_client"
+	SyncEventDeleteServer SyncEventOp = "delete_server"
+
+	// SyncEventSummary closes the events of one executed sync plan.
+	SyncEventSummary SyncEventOp = "summary"
+)
+
+// Results of a [SyncEvent].
+const (
+	SyncResultOK    = "ok"
+	SyncResultError = "error"
+)
+
+// SyncEvent is one line of the client's machine-readable sync event stream.
+// Every executed operation produces one event per item; a [SyncEventSummary]
+// event follows once the whole plan has been carried out or has failed.
+type SyncEvent struct {
+	Time time.Time   `json:"time"`
+	Op   SyncEventOp `json:"op"`
+
+	// ClientSideID is the affected item. Empty for summary events.
+	ClientSideID string `json:"client_side_id,omitempty"`
+
+	// Result is [SyncResultOK] or [SyncResultError].
+	Result string `json:"result"`
+
+	// Error is the failure message when Result is [SyncResultError].
+	Error string `json:"error,omitempty"`
+
+	// Succeeded and Failed count the item events of the plan. Set only on
+	// summary events.
+	Succeeded int `json:"succeeded,omitempty"`
+	Failed    int `json:"failed,omitempty"`
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Rasul Khiriev
+
+package models
+
+import "time"
+
+// PrivateDataVersion is a previous version of a vault item kept in the
+// server-side version history. The payload is stored exactly as it was
+// uploaded, i.e. still encrypted with the owner's key.
+type PrivateDataVersion struct {
+	// ClientSideID identifies the vault item.
+	ClientSideID string `json:"client_side_id"`
+
+	// Version is the version number the item had while this payload was
+	// current.
+	Version int64 `json:"version"`
+
+	// Payload is the encrypted content of this version.
+	Payload PrivateDataPayload `json:"payload"`
+
+	// Hash is the integrity checksum of Payload.
+	Hash string `json:"hash"`
+
+	// UpdatedAt is when this version was written (created or last updated).
+	UpdatedAt *time.Time `json:"updated_at"`
+
+	// ArchivedAt is when this version was replaced by a newer one.
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// DecipheredVersion is the client-side plaintext view of a
+// [PrivateDataVersion], used to show the history of an item.
+type DecipheredVersion struct {
+	// Version is the version number the item had while this payload was
+	// current.
+	Version int64 `json:"version"`
+
+	// Payload is the decrypted content of this version.
+	Payload DecipheredPayload `json:"payload"`
+
+	// UpdatedAt is when this version was written.
+	UpdatedAt *time.Time `json:"updated_at"`
+
+	// ArchivedAt is when this version was replaced by a newer one.
+	ArchivedAt time.Time `json:"archived_at"`
+}